@@ -16,9 +16,12 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 
 	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/spf13/cobra"
@@ -90,10 +93,19 @@ const (
 	connKey                     = "conn"
 	stopNodeFlag                = "stop-hosts"
 	reIPFileFlag                = "re-ip-file"
+	reIPNodesFlag               = "re-ip-nodes"
 	removeNodeFlag              = "remove"
 	// VER-90436: restart -> start
 	startNodeFlag = "restart"
 	startHostFlag = "start-hosts"
+	tlsConfigFlag = "tls-config"
+	// hosts of the nodes to put under or take out of maintenance
+	maintenanceHostsFlag = "maintenance-hosts"
+	// archive of the restore points, and a restore point in it
+	archiveNameFlag    = "archive-name"
+	restorePointIDFlag = "restore-point-id"
+	// new name of a subcluster to rename
+	newSubclusterNameFlag = "new-name"
 )
 
 // Flag and key for database replication
@@ -110,6 +122,12 @@ const (
 	targetConnKey          = "targetConn"
 	sourceTLSConfigFlag    = "source-tlsconfig"
 	sourceTLSConfigKey     = "sourceTLSConfig"
+	tableOrSchemaNameFlag  = "table-or-schema-name"
+	includePatternFlag     = "include-pattern"
+	excludePatternFlag     = "exclude-pattern"
+	asyncFlag              = "async"
+	transactionIDFlag      = "transaction-id"
+	trashLocationFlag      = "trash-location"
 )
 
 // flags to viper key map
@@ -150,32 +168,49 @@ var targetFlagKeyMap = map[string]string{
 }
 
 const (
-	createDBSubCmd          = "create_db"
-	stopDBSubCmd            = "stop_db"
-	reviveDBSubCmd          = "revive_db"
-	manageConfigSubCmd      = "manage_config"
-	createConnectionSubCmd  = "create_connection"
-	configRecoverSubCmd     = "recover"
-	configShowSubCmd        = "show"
-	replicationSubCmd       = "replication"
-	startReplicationSubCmd  = "start"
-	listAllNodesSubCmd      = "list_all_nodes"
-	startDBSubCmd           = "start_db"
-	dropDBSubCmd            = "drop_db"
-	addSCSubCmd             = "add_subcluster"
-	removeSCSubCmd          = "remove_subcluster"
-	stopSCSubCmd            = "stop_subcluster"
-	addNodeSubCmd           = "add_node"
-	startSCSubCmd           = "start_subcluster"
-	stopNodeCmd             = "stop_node"
-	removeNodeSubCmd        = "remove_node"
-	restartNodeSubCmd       = "restart_node"
-	reIPSubCmd              = "re_ip"
-	sandboxSubCmd           = "sandbox_subcluster"
-	unsandboxSubCmd         = "unsandbox_subcluster"
-	scrutinizeSubCmd        = "scrutinize"
-	showRestorePointsSubCmd = "show_restore_points"
-	installPkgSubCmd        = "install_packages"
+	createDBSubCmd             = "create_db"
+	stopDBSubCmd               = "stop_db"
+	reviveDBSubCmd             = "revive_db"
+	manageConfigSubCmd         = "manage_config"
+	createConnectionSubCmd     = "create_connection"
+	configRecoverSubCmd        = "recover"
+	configShowSubCmd           = "show"
+	replicationSubCmd          = "replication"
+	startReplicationSubCmd     = "start"
+	replicationStatusSubCmd    = "status"
+	listAllNodesSubCmd         = "list_all_nodes"
+	startDBSubCmd              = "start_db"
+	dropDBSubCmd               = "drop_db"
+	addSCSubCmd                = "add_subcluster"
+	removeSCSubCmd             = "remove_subcluster"
+	renameSCSubCmd             = "rename_subcluster"
+	stopSCSubCmd               = "stop_subcluster"
+	addNodeSubCmd              = "add_node"
+	startSCSubCmd              = "start_subcluster"
+	stopNodeCmd                = "stop_node"
+	removeNodeSubCmd           = "remove_node"
+	restartNodeSubCmd          = "restart_node"
+	reIPSubCmd                 = "re_ip"
+	sandboxSubCmd              = "sandbox_subcluster"
+	unsandboxSubCmd            = "unsandbox_subcluster"
+	scrutinizeSubCmd           = "scrutinize"
+	showRestorePointsSubCmd    = "show_restore_points"
+	installPkgSubCmd           = "install_packages"
+	setTLSConfigSubCmd         = "set_tls_config"
+	deployServerCertSubCmd     = "deploy_server_certificate"
+	setNodeMaintenanceSubCmd   = "set_node_maintenance"
+	clearNodeMaintenanceSubCmd = "clear_node_maintenance"
+	createArchiveSubCmd        = "create_archive"
+	saveRestorePointSubCmd     = "save_restore_point"
+	removeRestorePointSubCmd   = "remove_restore_point"
+	getClusterLeaseSubCmd      = "get_cluster_lease"
+	healthCheckSubCmd          = "health_check"
+	manageDepotSubCmd          = "manage_depot"
+	rebalanceShardsSubCmd      = "rebalance_shards"
+	setCommunalCredsSubCmd     = "set_communal_storage_credentials"
+	purgeTrashSubCmd           = "purge_trash"
+	setReadOnlySubCmd          = "set_read_only"
+	rotateTLSCertsSubCmd       = "rotate_tls_certs"
 )
 
 // cmdGlobals holds global variables shared by multiple
@@ -236,7 +271,10 @@ type cmdInterface interface {
 }
 
 func Execute() {
-	err := rootCmd.Execute()
+	// cancel the running command on interrupt, so that its in-flight requests are aborted
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
 	if err != nil {
 		fmt.Printf("Error during execution: %s\n", err)
 		os.Exit(1)
@@ -253,7 +291,7 @@ func initVcc(cmd *cobra.Command) vclusterops.VClusterCommands {
 		VClusterCommandsLogger: vclusterops.VClusterCommandsLogger{
 			Log: logger.WithName(cmd.CalledAs()),
 		},
-	}
+	}.WithContext(cmd.Context())
 	vcc.LogInfo("New VCluster command initialization")
 
 	return vcc
@@ -319,8 +357,8 @@ func configViper(cmd *cobra.Command, flagsInConfig []string) error {
 	// initialize config file
 	initConfig()
 
-	// target-flags are only available for replication start command
-	if cmd.CalledAs() == startReplicationSubCmd {
+	// target-flags are only available for replication commands
+	if isReplicationSubCmd(cmd.CalledAs()) {
 		for targetFlag := range targetFlagKeyMap {
 			flagsInConfig = append(flagsInConfig, targetFlag)
 		}
@@ -393,8 +431,8 @@ func loadConfig(cmd *cobra.Command) (err error) {
 	}
 
 	// load target db options from connection file to viper
-	// conn file is only available for replication subcommand
-	if cmd.CalledAs() == startReplicationSubCmd {
+	// conn file is only available for replication subcommands
+	if isReplicationSubCmd(cmd.CalledAs()) {
 		err := loadConnToViper()
 		if err != nil {
 			return err
@@ -433,6 +471,12 @@ func handleViperUserInput(flagsInConfig []string) error {
 	return nil
 }
 
+// isReplicationSubCmd returns true for the replication subcommands, which
+// take the target database options
+func isReplicationSubCmd(calledAs string) bool {
+	return calledAs == startReplicationSubCmd || calledAs == replicationStatusSubCmd
+}
+
 // filterFlagsInConfig can filter the flags that have a relevant field in vcluster config file
 func filterFlagsInConfig(flags []string) []string {
 	flagsAccepted := mapset.NewSet(flags...)
@@ -465,6 +509,12 @@ func makeBasicCobraCmd(i cmdInterface, use, short, long string, commonFlags []st
 			}
 			defer closeFile(globals.file)
 			globals.file = f
+			switch {
+			case isTargetSelectorCmd(cmd.CalledAs()):
+				excludeMaintenanceNodes(vcc, &dbOptions)
+			case isNodeTargetingCmd(cmd.CalledAs()):
+				warnMaintenanceNodes(vcc)
+			}
 			i.SetDatabaseOptions(&dbOptions)
 			// parseError and runError will be printed by the command invoker.
 			// we silence them in cobra for not printing duplicate error messages.
@@ -494,6 +544,25 @@ func makeBasicCobraCmd(i cmdInterface, use, short, long string, commonFlags []st
 	return cmd
 }
 
+// isTargetSelectorCmd returns true for the commands that honor
+// vclusterops.TargetSelector, which are the only ones that skip the nodes
+// under maintenance
+func isTargetSelectorCmd(calledAs string) bool {
+	return calledAs == restartNodeSubCmd || calledAs == stopNodeCmd
+}
+
+// isNodeTargetingCmd returns true for the other commands that change the
+// state of nodes. They do not skip the nodes under maintenance, so they only
+// warn about them.
+func isNodeTargetingCmd(calledAs string) bool {
+	switch calledAs {
+	case startDBSubCmd, stopDBSubCmd, startSCSubCmd, stopSCSubCmd, removeNodeSubCmd,
+		removeSCSubCmd, rebalanceShardsSubCmd, reIPSubCmd, sandboxSubCmd, unsandboxSubCmd:
+		return true
+	}
+	return false
+}
+
 // makeSimpleCobraCmd can make a simple cobra command for some vcluster commands
 // such as replication and manage_config
 func makeSimpleCobraCmd(use, short, long string) *cobra.Command {
@@ -519,6 +588,13 @@ func constructCmds() []*cobra.Command {
 		makeCmdReIP(),
 		makeCmdShowRestorePoints(),
 		makeCmdInstallPackages(),
+		makeCmdSetTLSConfig(),
+		makeCmdDeployServerCertificate(),
+		makeCmdCreateArchive(),
+		makeCmdSaveRestorePoint(),
+		makeCmdRemoveRestorePoint(),
+		makeCmdGetClusterLease(),
+		makeCmdHealthCheck(),
 		// sc-scope cmds
 		makeCmdAddSubcluster(),
 		makeCmdRemoveSubcluster(),
@@ -526,11 +602,20 @@ func constructCmds() []*cobra.Command {
 		makeCmdStartSubcluster(),
 		makeCmdSandboxSubcluster(),
 		makeCmdUnsandboxSubcluster(),
+		makeCmdRenameSubcluster(),
 		// node-scope cmds
 		makeCmdRestartNodes(),
 		makeCmdAddNode(),
 		makeCmdStopNode(),
 		makeCmdRemoveNode(),
+		makeCmdSetNodeMaintenance(),
+		makeCmdClearNodeMaintenance(),
+		makeCmdManageDepot(),
+		makeCmdRebalanceShards(),
+		makeCmdSetCommunalStorageCredentials(),
+		makeCmdPurgeTrash(),
+		makeCmdSetReadOnly(),
+		makeCmdRotateTLSCerts(),
 		// others
 		makeCmdScrutinize(),
 		makeCmdManageConfig(),
@@ -98,12 +98,19 @@ func (c *CmdAddNode) setLocalFlags(cmd *cobra.Command) {
 		false,
 		"Whether to force clean-up of existing directories before adding host(s)",
 	)
+	c.setTrashFlags(cmd, &c.addNodeOptions.Trash)
 	cmd.Flags().BoolVar(
 		c.addNodeOptions.SkipRebalanceShards,
 		"skip-rebalance-shards",
 		false,
 		util.GetEonFlagMsg("Skip the subcluster shards rebalancing"),
 	)
+	cmd.Flags().BoolVar(
+		&c.addNodeOptions.RebalanceCluster,
+		"rebalance-cluster",
+		false,
+		"Rebalance the data across the nodes after adding the host(s). This is for Enterprise databases only",
+	)
 	cmd.Flags().StringVar(
 		&c.addNodeOptions.SCName,
 		subclusterFlag,
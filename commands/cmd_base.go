@@ -358,6 +358,32 @@ func (c *CmdBase) setPasswordFlags(cmd *cobra.Command) {
 		readPasswordFromPromptFlag}...)
 }
 
+// setTrashFlags sets the flags that make a force removal move the existing
+// directories to a trash location
+func (c *CmdBase) setTrashFlags(cmd *cobra.Command, trash *vclusterops.TrashOptions) {
+	cmd.Flags().StringVar(
+		&trash.TrashLocation,
+		trashLocationFlag,
+		"",
+		"With force removal, move the existing directories to a timestamped directory in this path, "+
+			"instead of deleting them",
+	)
+	cmd.Flags().IntVar(
+		&trash.TrashRetentionHours,
+		"trash-retention-hours",
+		0,
+		"The hours to keep the directories in the trash location, older ones are purged at the next force removal. "+
+			"Zero keeps them until purge_trash is run",
+	)
+	cmd.Flags().BoolVar(
+		&trash.AllowDeleteFallback,
+		"trash-allow-delete",
+		false,
+		"Delete the existing directories on the hosts whose NMA cannot move them to the trash location, "+
+			"instead of failing",
+	)
+}
+
 // ResetUserInputOptions reset password option to nil in each command
 // if it is not provided in cli
 func (c *CmdBase) ResetUserInputOptions(opt *vclusterops.DatabaseOptions) {
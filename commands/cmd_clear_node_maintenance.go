@@ -0,0 +1,109 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdClearNodeMaintenance
+ *
+ * A subcommand clearing the maintenance flag of nodes
+ * in the config file.
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdClearNodeMaintenance struct {
+	CmdBase
+	sOptions         vclusterops.DatabaseOptions
+	maintenanceHosts []string
+}
+
+func makeCmdClearNodeMaintenance() *cobra.Command {
+	newCmd := &CmdClearNodeMaintenance{}
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		clearNodeMaintenanceSubCmd,
+		"Take nodes out of maintenance",
+		`This command clears the maintenance flag that set_node_maintenance recorded
+in the config file, so that other vcluster commands target the nodes again.
+
+Examples:
+  # Take a node out of maintenance
+  vcluster clear_node_maintenance --maintenance-hosts 10.20.30.41 \
+    --config /opt/vertica/config/vertica_cluster.yaml
+`,
+		[]string{configFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsRequired(cmd, maintenanceHostsFlag)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdClearNodeMaintenance) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(
+		&c.maintenanceHosts,
+		maintenanceHostsFlag,
+		[]string{},
+		"Comma-separated list of the hosts of the nodes to take out of maintenance",
+	)
+}
+
+func (c *CmdClearNodeMaintenance) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogArgParse(&c.argv)
+
+	return util.ParseHostList(&c.maintenanceHosts)
+}
+
+func (c *CmdClearNodeMaintenance) Run(vcc vclusterops.ClusterCommands) error {
+	dbConfig, err := readConfig()
+	if err != nil {
+		return err
+	}
+	hosts, err := util.ResolveRawHostsToAddresses(c.maintenanceHosts, dbConfig.Ipv6)
+	if err != nil {
+		return err
+	}
+
+	err = dbConfig.clearMaintenance(hosts)
+	if err != nil {
+		return err
+	}
+	err = dbConfig.write(dbOptions.ConfigPath, true /*forceOverwrite*/)
+	if err != nil {
+		return fmt.Errorf("fail to write the config file, details: %w", err)
+	}
+
+	vcc.PrintInfo("Successfully took the nodes on hosts %v out of maintenance", hosts)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance
+func (c *CmdClearNodeMaintenance) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.sOptions = *opt
+}
@@ -0,0 +1,142 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdCreateArchive
+ *
+ * Parses arguments for VCreateArchiveOptions to pass down to
+ * VCreateArchive.
+ *
+ * Implements ClusterCommand interface
+ */
+
+type CmdCreateArchive struct {
+	CmdBase
+	createArchiveOptions *vclusterops.VCreateArchiveOptions
+}
+
+func makeCmdCreateArchive() *cobra.Command {
+	// CmdCreateArchive
+	newCmd := &CmdCreateArchive{}
+	opt := vclusterops.VCreateArchiveOptionsFactory()
+	newCmd.createArchiveOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		createArchiveSubCmd,
+		"Create an archive for restore points",
+		`This command creates an archive in the communal storage of an Eon Mode
+database. Restore points can then be saved to the archive with
+save_restore_point, and the database can be restored to one of them with
+revive_db.
+
+The --num-restore-points option limits the number of restore points that the
+archive keeps. When a new restore point exceeds the limit, the oldest one is
+removed. By default, the number of restore points is not limited.
+
+Examples:
+  # Create an archive that keeps the last 7 restore points
+  vcluster create_archive --db-name test_db --archive-name daily \
+    --num-restore-points 7 --hosts 10.20.30.40,10.20.30.41,10.20.30.42
+
+  # Create an archive with config file
+  vcluster create_archive --archive-name db \
+    --config /opt/vertica/config/vertica_cluster.yaml
+`,
+		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, passwordFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsRequired(cmd, archiveNameFlag)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdCreateArchive) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.createArchiveOptions.ArchiveName,
+		archiveNameFlag,
+		"",
+		"The name of the archive to create",
+	)
+	cmd.Flags().IntVar(
+		&c.createArchiveOptions.NumRestorePoints,
+		"num-restore-points",
+		0,
+		"The maximum number of restore points that the archive keeps, 0 means no limit",
+	)
+}
+
+func (c *CmdCreateArchive) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.createArchiveOptions.DatabaseOptions)
+
+	return c.validateParse(logger)
+}
+
+// all validations of the arguments should go in here
+func (c *CmdCreateArchive) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	err := c.getCertFilesFromCertPaths(&c.createArchiveOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.createArchiveOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.createArchiveOptions.DatabaseOptions)
+}
+
+func (c *CmdCreateArchive) Analyze(_ vlog.Printer) error {
+	return nil
+}
+
+func (c *CmdCreateArchive) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.createArchiveOptions
+
+	err := vcc.VCreateArchive(options)
+	if err != nil {
+		vcc.LogError(err, "failed to create the archive", "archive", options.ArchiveName)
+		return err
+	}
+
+	vcc.PrintInfo("Successfully created archive %s", options.ArchiveName)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdCreateArchive
+func (c *CmdCreateArchive) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.createArchiveOptions.DatabaseOptions = *opt
+}
@@ -141,6 +141,13 @@ func (c *CmdCreateDB) setLocalFlags(cmd *cobra.Command) {
 		0,
 		util.GetEonFlagMsg("Number of shards in the database"),
 	)
+	cmd.Flags().IntVar(
+		&c.createDBOptions.ExpectedNodeCount,
+		"expected-node-count",
+		0,
+		util.GetEonFlagMsg("Number of nodes that the database is expected to grow to. "+
+			"A warning is shown if the shard count cannot scale to it elastically"),
+	)
 	cmd.Flags().StringVar(
 		&c.createDBOptions.DepotSize,
 		"depot-size",
@@ -195,6 +202,7 @@ func (c *CmdCreateDB) setLocalFlags(cmd *cobra.Command) {
 		false,
 		"Force removal of existing directories before creating the database",
 	)
+	c.setTrashFlags(cmd, &c.createDBOptions.Trash)
 	cmd.Flags().BoolVar(
 		&c.createDBOptions.ForceOverwriteFile,
 		"force-overwrite-file",
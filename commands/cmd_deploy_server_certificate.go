@@ -0,0 +1,187 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdDeployServerCertificate
+ *
+ * Parses arguments for VDeployServerCertificateOptions to pass down to
+ * VDeployServerCertificate.
+ *
+ * Implements ClusterCommand interface
+ */
+
+type CmdDeployServerCertificate struct {
+	CmdBase
+	deployCertOptions *vclusterops.VDeployServerCertificateOptions
+	certificateFile   string
+	privateKeyFile    string
+}
+
+func makeCmdDeployServerCertificate() *cobra.Command {
+	// CmdDeployServerCertificate
+	newCmd := &CmdDeployServerCertificate{}
+	opt := vclusterops.VDeployServerCertificateOptionsFactory()
+	newCmd.deployCertOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		deployServerCertSubCmd,
+		"Deploy a certificate for the client-server connections",
+		`This command deploys a certificate for the client-server connections.
+
+It imports the certificate and its private key into the database, sets the
+server TLS configuration to use the certificate, and then verifies that each up
+node presents the certificate to a new client connection.
+
+The CA certificates passed to the --ca-certificates option must already be
+imported into the database.
+
+Examples:
+  # Deploy a certificate with user input
+  vcluster deploy_server_certificate --db-name test_db \
+    --certificate-name server_cert_2024 \
+    --certificate-file /path/to/server.crt --private-key-file /path/to/server.key \
+    --ca-certificates ca_cert --tls-mode VERIFY_CA \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42
+
+  # Deploy a certificate with config file
+  vcluster deploy_server_certificate --certificate-name server_cert_2024 \
+    --certificate-file /path/to/server.crt --private-key-file /path/to/server.key \
+    --config /opt/vertica/config/vertica_cluster.yaml
+`,
+		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, passwordFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsRequired(cmd, "certificate-name", "certificate-file", "private-key-file")
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdDeployServerCertificate) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.deployCertOptions.CertificateName,
+		"certificate-name",
+		"",
+		"The name of the certificate in the database",
+	)
+	cmd.Flags().StringVar(
+		&c.certificateFile,
+		"certificate-file",
+		"",
+		"Path of the PEM-encoded certificate",
+	)
+	cmd.Flags().StringVar(
+		&c.privateKeyFile,
+		"private-key-file",
+		"",
+		"Path of the PEM-encoded private key of the certificate",
+	)
+	cmd.Flags().StringSliceVar(
+		&c.deployCertOptions.CACertificates,
+		"ca-certificates",
+		[]string{},
+		"Comma-separated list of the names of the CA certificates that the server TLS configuration trusts",
+	)
+	cmd.Flags().StringVar(
+		&c.deployCertOptions.TLSMode,
+		"tls-mode",
+		"",
+		"The TLS mode of the server TLS configuration, one of DISABLE, ENABLE, TRY_VERIFY, VERIFY_CA and VERIFY_FULL",
+	)
+	cmd.Flags().IntVar(
+		&c.deployCertOptions.ClientPort,
+		"client-port",
+		util.DefaultClientPort,
+		"The port on which the nodes accept client connections",
+	)
+}
+
+func (c *CmdDeployServerCertificate) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.deployCertOptions.DatabaseOptions)
+
+	return c.validateParse(logger)
+}
+
+// all validations of the arguments should go in here
+func (c *CmdDeployServerCertificate) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	certPEM, err := os.ReadFile(c.certificateFile)
+	if err != nil {
+		return fmt.Errorf("fail to read the certificate file %s, details: %w", c.certificateFile, err)
+	}
+	c.deployCertOptions.CertificatePEM = string(certPEM)
+	keyPEM, err := os.ReadFile(c.privateKeyFile)
+	if err != nil {
+		return fmt.Errorf("fail to read the private key file %s, details: %w", c.privateKeyFile, err)
+	}
+	c.deployCertOptions.PrivateKeyPEM = string(keyPEM)
+
+	err = c.getCertFilesFromCertPaths(&c.deployCertOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.deployCertOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.deployCertOptions.DatabaseOptions)
+}
+
+func (c *CmdDeployServerCertificate) Analyze(_ vlog.Printer) error {
+	return nil
+}
+
+func (c *CmdDeployServerCertificate) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.deployCertOptions
+
+	err := vcc.VDeployServerCertificate(options)
+	if err != nil {
+		vcc.LogError(err, "failed to deploy the certificate", "certificate", options.CertificateName)
+		return err
+	}
+
+	vcc.PrintInfo("Successfully deployed certificate %s", options.CertificateName)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdDeployServerCertificate
+func (c *CmdDeployServerCertificate) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.deployCertOptions.DatabaseOptions = *opt
+}
@@ -41,10 +41,11 @@ func makeCmdDropDB() *cobra.Command {
 		newCmd,
 		dropDBSubCmd,
 		"Drop a database",
-		`This command drops a stopped database.
+		`This command drops a stopped database. To stop the database first if
+it is running, use the --stop-if-running option.
 
-For an Eon database, communal storage is not deleted. You can recover 
-the dropped database with revive_db.
+For an Eon database, communal storage is not deleted by default. You can
+recover the dropped database with revive_db.
 
 The config file must be specified to retrieve host information. If --config
 is not provided, a configuration file is created in one of the following 
@@ -58,19 +59,33 @@ When the command completes, the config file is removed.
 To remove the local directories like catalog, depot, and data, use the 
 --force-delete option. The data deleted with this option is unrecoverable.
 
+To also delete the communal storage of an Eon database, use the
+--delete-communal-storage option, and confirm it by passing the communal
+storage location to --confirm-communal-storage-deletion. The communal storage
+is deleted after the local directories. The database cannot be revived after
+its communal storage is deleted.
+
 Examples:
   # Drop a database with config file
   vcluster drop_db --db-name test_db \
     --config /opt/vertica/config/vertica_cluster.yaml
+
+  # Stop and drop a database, and delete its directories and communal storage
+  vcluster drop_db --db-name test_db --stop-if-running --force-delete \
+    --delete-communal-storage --confirm-communal-storage-deletion s3://bucket/test_db \
+    --config /opt/vertica/config/vertica_cluster.yaml
 `,
-		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, catalogPathFlag, dataPathFlag, depotPathFlag},
+		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, catalogPathFlag, dataPathFlag, depotPathFlag,
+			eonModeFlag, communalStorageLocationFlag, configParamFlag, passwordFlag},
 	)
 
 	// local flags
 	newCmd.setLocalFlags(cmd)
 
 	// hide flags since we expect it to come from config file, not from user input
-	hideLocalFlags(cmd, []string{hostsFlag, catalogPathFlag, dataPathFlag, depotPathFlag})
+	hideLocalFlags(cmd, []string{hostsFlag, catalogPathFlag, dataPathFlag, depotPathFlag, eonModeFlag, communalStorageLocationFlag})
+
+	cmd.MarkFlagsRequiredTogether("delete-communal-storage", "confirm-communal-storage-deletion")
 
 	return cmd
 }
@@ -83,11 +98,34 @@ func (c *CmdDropDB) setLocalFlags(cmd *cobra.Command) {
 		false,
 		"Delete local directories like catalog, depot, and data.",
 	)
+	cmd.Flags().BoolVar(
+		&c.dropDBOptions.StopIfRunning,
+		"stop-if-running",
+		false,
+		"Stop the database first if it is running.",
+	)
+	cmd.Flags().BoolVar(
+		&c.dropDBOptions.DeleteCommunalStorage,
+		"delete-communal-storage",
+		false,
+		"Delete the communal storage of an Eon database. The data deleted with this option is unrecoverable.",
+	)
+	cmd.Flags().StringVar(
+		&c.dropDBOptions.CommunalStorageDeletionConfirmation,
+		"confirm-communal-storage-deletion",
+		"",
+		"The communal storage location, to confirm the deletion of the communal storage.",
+	)
 }
 
 func (c *CmdDropDB) Parse(inputArgv []string, logger vlog.Printer) error {
 	c.argv = inputArgv
-	logger.LogArgParse(&c.argv)
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.dropDBOptions.DatabaseOptions)
 
 	return c.validateParse(logger)
 }
@@ -98,7 +136,16 @@ func (c *CmdDropDB) validateParse(logger vlog.Printer) error {
 	if err != nil {
 		return err
 	}
-	return c.ValidateParseBaseOptions(&c.dropDBOptions.DatabaseOptions)
+	err = c.ValidateParseBaseOptions(&c.dropDBOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.setDBPassword(&c.dropDBOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setConfigParam(&c.dropDBOptions.DatabaseOptions)
 }
 
 func (c *CmdDropDB) Run(vcc vclusterops.ClusterCommands) error {
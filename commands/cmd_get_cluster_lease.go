@@ -0,0 +1,124 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdGetClusterLease
+ *
+ * Parses arguments for VGetClusterLeaseOptions to pass down to
+ * VGetClusterLease.
+ *
+ * Implements ClusterCommand interface
+ */
+
+type CmdGetClusterLease struct {
+	CmdBase
+	getClusterLeaseOptions *vclusterops.VGetClusterLeaseOptions
+}
+
+func makeCmdGetClusterLease() *cobra.Command {
+	// CmdGetClusterLease
+	newCmd := &CmdGetClusterLease{}
+	opt := vclusterops.VGetClusterLeaseOptionsFactory()
+	newCmd.getClusterLeaseOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		getClusterLeaseSubCmd,
+		"Show the cluster lease on the communal storage",
+		`This command shows the lease that a running cluster holds on the communal
+storage of an Eon Mode database: the hosts of the cluster, when the lease
+expires, and how many seconds remain. It does not revive the database.
+
+revive_db fails while the lease has not expired. Use this command to decide
+whether to wait for the lease to expire, or to run revive_db with
+--ignore-cluster-lease.
+
+Examples:
+  # Show the cluster lease with user input
+  vcluster get_cluster_lease --db-name test_db \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42 \
+    --communal-storage-location /communal
+
+  # Show the cluster lease with config file
+  vcluster get_cluster_lease --db-name test_db \
+    --config /opt/vertica/config/vertica_cluster.yaml
+`,
+		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, communalStorageLocationFlag,
+			configParamFlag, outputFileFlag},
+	)
+
+	return cmd
+}
+
+func (c *CmdGetClusterLease) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogArgParse(&c.argv)
+
+	return c.validateParse(logger)
+}
+
+// all validations of the arguments should go in here
+func (c *CmdGetClusterLease) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	err := c.getCertFilesFromCertPaths(&c.getClusterLeaseOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.getClusterLeaseOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setConfigParam(&c.getClusterLeaseOptions.DatabaseOptions)
+}
+
+func (c *CmdGetClusterLease) Analyze(_ vlog.Printer) error {
+	return nil
+}
+
+func (c *CmdGetClusterLease) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.getClusterLeaseOptions
+
+	lease, err := vcc.VGetClusterLease(options)
+	if err != nil {
+		vcc.LogError(err, "failed to get the cluster lease", "DBName", options.DBName)
+		return err
+	}
+	bytes, err := json.MarshalIndent(lease, "", "  ")
+	if err != nil {
+		return err
+	}
+	c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+
+	vcc.PrintInfo("Successfully got the cluster lease of database %s", options.DBName)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdGetClusterLease
+func (c *CmdGetClusterLease) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.getClusterLeaseOptions.DatabaseOptions = *opt
+}
@@ -0,0 +1,161 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdHealthCheck
+ *
+ * Parses arguments for VHealthCheckOptions to pass down to
+ * VHealthCheck.
+ *
+ * Implements ClusterCommand interface
+ */
+
+type CmdHealthCheck struct {
+	CmdBase
+	healthCheckOptions *vclusterops.VHealthCheckOptions
+}
+
+func makeCmdHealthCheck() *cobra.Command {
+	// CmdHealthCheck
+	newCmd := &CmdHealthCheck{}
+	opt := vclusterops.VHealthCheckOptionsFactory()
+	newCmd.healthCheckOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		healthCheckSubCmd,
+		"Run a health check of the database",
+		`This command runs a one-shot diagnostic of the database and prints a report
+with a severity for each check:
+  - the NMA health of each host
+  - the state of each node, and whether each cluster has a quorum
+  - the disk usage of the catalog, depot, and data paths
+  - the clock skew between hosts
+  - whether the hosts agree on the node states
+
+The command fails if any check is CRITICAL, so it can be used as a readiness
+probe.
+
+Examples:
+  # Run a health check with config file
+  vcluster health_check --password testpassword \
+    --config /opt/vertica/config/vertica_cluster.yaml
+
+  # Run a health check with custom thresholds
+  vcluster health_check --db-name test_db --hosts 10.20.30.40,10.20.30.41 \
+    --disk-warning-percent 70 --disk-critical-percent 90 --clock-skew-critical-seconds 2
+`,
+		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, passwordFlag, outputFileFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdHealthCheck) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64Var(
+		&c.healthCheckOptions.DiskWarningPercent,
+		"disk-warning-percent",
+		c.healthCheckOptions.DiskWarningPercent,
+		"The disk usage percentage of a database path that raises a warning",
+	)
+	cmd.Flags().Float64Var(
+		&c.healthCheckOptions.DiskCriticalPercent,
+		"disk-critical-percent",
+		c.healthCheckOptions.DiskCriticalPercent,
+		"The disk usage percentage of a database path that is critical",
+	)
+	cmd.Flags().Float64Var(
+		&c.healthCheckOptions.ClockSkewWarningSeconds,
+		"clock-skew-warning-seconds",
+		c.healthCheckOptions.ClockSkewWarningSeconds,
+		"The clock skew in seconds between hosts that raises a warning",
+	)
+	cmd.Flags().Float64Var(
+		&c.healthCheckOptions.ClockSkewCriticalSeconds,
+		"clock-skew-critical-seconds",
+		c.healthCheckOptions.ClockSkewCriticalSeconds,
+		"The clock skew in seconds between hosts that is critical",
+	)
+}
+
+func (c *CmdHealthCheck) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogArgParse(&c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.healthCheckOptions.DatabaseOptions)
+
+	return c.validateParse(logger)
+}
+
+// all validations of the arguments should go in here
+func (c *CmdHealthCheck) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	err := c.getCertFilesFromCertPaths(&c.healthCheckOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.healthCheckOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.healthCheckOptions.DatabaseOptions)
+}
+
+func (c *CmdHealthCheck) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.healthCheckOptions
+
+	report, err := vcc.VHealthCheck(options)
+	if err != nil {
+		vcc.LogError(err, "failed to run the health check", "DBName", options.DBName)
+		return err
+	}
+	bytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fail to marshal the health check report, details %w", err)
+	}
+	c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+
+	if !report.IsReady() {
+		return fmt.Errorf("the health check of database %s is %s", options.DBName, report.Severity)
+	}
+	vcc.PrintInfo("The health check of database %s is %s", options.DBName, report.Severity)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdHealthCheck
+func (c *CmdHealthCheck) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.healthCheckOptions.DatabaseOptions = *opt
+}
@@ -52,7 +52,11 @@ You must provide the --hosts option followed by all hosts in the database as a
 comma-separated list.
 
 Default packages are located in /opt/vertica/packages. During installation, the
-status for each package is returned.
+status for each package is returned. The command fails if any package fails to
+install, after writing out the status of all packages.
+
+Run this command after creating, reviving, or upgrading a database to make sure
+the default packages are installed.
 
 Examples:
   # Install default packages with user input
@@ -115,21 +119,23 @@ func (c *CmdInstallPackages) Analyze(_ vlog.Printer) error {
 func (c *CmdInstallPackages) Run(vcc vclusterops.ClusterCommands) error {
 	options := c.installPkgOpts
 
-	status, err := vcc.VInstallPackages(options)
-	if err != nil {
-		vcc.LogError(err, "failed to install the packages")
-		return err
+	status, runErr := vcc.VInstallPackages(options)
+	// the status of each package is written out even if some packages failed to install
+	if status != nil {
+		bytes, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return err
+		}
+		c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+		vcc.LogInfo("Installed the packages: ", "packages", string(bytes))
 	}
-
-	var bytes []byte
-	bytes, err = json.MarshalIndent(status, "", "  ")
-	if err != nil {
-		return err
+	if runErr != nil {
+		vcc.LogError(runErr, "failed to install the packages")
+		return runErr
 	}
 
-	c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
-	vcc.LogInfo("Installed the packages: ", "packages", string(bytes))
-
+	vcc.PrintInfo("Installed %d packages, skipped %d already installed packages",
+		len(status.InstalledPackages()), len(status.SkippedPackages()))
 	return nil
 }
 
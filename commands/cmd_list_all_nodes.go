@@ -30,6 +30,8 @@ import (
  */
 type CmdListAllNodes struct {
 	fetchNodeStateOptions *vclusterops.VFetchNodeStateOptions
+	// collect the view of every host and show the reconciled node details
+	detail bool
 
 	CmdBase
 }
@@ -51,6 +53,9 @@ To provide its status, each host must run the spread daemon.
 
 You must provide the --hosts option one or more hosts as a comma-separated
 list. list_all_nodes returns the first response it receives from any host.
+With --detail, it collects the view of the cluster from every reachable host
+and shows the reconciled state, catalog version, uptime, and depot usage of
+each node, as well as any conflicting views or split brain.
 
 The --db-name and --catalog-path options are required only when vcluster cannot
 obtain node information from a running database and the config file is not
@@ -61,13 +66,30 @@ Examples:
   # used to access the database
   vcluster list_all_nodes --password testpassword \
     --config /opt/vertica/config/vertica_cluster.yaml
+
+  # List the detailed node states as seen from all hosts
+  vcluster list_all_nodes --detail --password testpassword \
+    --config /opt/vertica/config/vertica_cluster.yaml
 `,
 		[]string{dbNameFlag, hostsFlag, passwordFlag, ipv6Flag, catalogPathFlag, configFlag, outputFileFlag},
 	)
 
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
 	return cmd
 }
 
+// setLocalFlags will set the local flags the command has
+func (c *CmdListAllNodes) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&c.detail,
+		"detail",
+		false,
+		"Collect the node states from all reachable hosts and show the details of each node",
+	)
+}
+
 func (c *CmdListAllNodes) Parse(inputArgv []string, logger vlog.Printer) error {
 	c.argv = inputArgv
 	logger.LogArgParse(&c.argv)
@@ -100,6 +122,10 @@ func (c *CmdListAllNodes) validateParse(logger vlog.Printer) error {
 func (c *CmdListAllNodes) Run(vcc vclusterops.ClusterCommands) error {
 	vcc.V(1).Info("Called method Run()")
 
+	if c.detail {
+		return c.runDetail(vcc)
+	}
+
 	nodeStates, err := vcc.VFetchNodeState(c.fetchNodeStateOptions)
 	if err != nil {
 		// if all nodes are down, the nodeStates list is not empty
@@ -120,6 +146,27 @@ func (c *CmdListAllNodes) Run(vcc vclusterops.ClusterCommands) error {
 	return nil
 }
 
+func (c *CmdListAllNodes) runDetail(vcc vclusterops.ClusterCommands) error {
+	report, err := vcc.VFetchNodeStateDetails(c.fetchNodeStateOptions)
+	if err != nil {
+		vcc.PrintError("fail to list node details: %s", err)
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fail to marshal the node state details, details %w", err)
+	}
+
+	c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+	vcc.LogInfo("Node state details: ", "report", string(bytes))
+	if report.SplitBrain {
+		vcc.PrintWarning("the nodes of %q do not see each other, the cluster may have a split brain",
+			report.SplitBrainClusters)
+	}
+	return nil
+}
+
 // SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdListAllNodes
 func (c *CmdListAllNodes) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
 	c.fetchNodeStateOptions.DatabaseOptions = *opt
@@ -0,0 +1,171 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdManageDepot
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdManageDepot struct {
+	manageDepotOptions *vclusterops.VManageDepotOptions
+	action             string
+
+	CmdBase
+}
+
+func makeCmdManageDepot() *cobra.Command {
+	newCmd := &CmdManageDepot{}
+
+	opt := vclusterops.VManageDepotOptionsFactory()
+	newCmd.manageDepotOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		manageDepotSubCmd,
+		"Resize, clear, or warm the depot of nodes",
+		`This command manages the depot of nodes in an Eon Mode database:
+  - resize: changes the depot size to --depot-size
+  - clear: removes the contents of the depot
+  - warm: loads the tables or projections in --objects into the depot
+
+The action runs on the nodes in --node-names, or on all UP nodes of the main
+cluster if --node-names is not set. The result of each node is printed, and
+the command fails if the action fails on any node.
+
+Examples:
+  # Resize the depot of all nodes to 60% of the disk with config file
+  vcluster manage_depot --action resize --depot-size 60% \
+    --config /opt/vertica/config/vertica_cluster.yaml
+
+  # Clear the depot of a node with user input
+  vcluster manage_depot --action clear --node-names v_test_db_node0001 \
+    --db-name test_db --hosts 10.20.30.40,10.20.30.41,10.20.30.42
+
+  # Warm the depot of all nodes with two tables
+  vcluster manage_depot --action warm --objects public.t1,public.t2 \
+    --config /opt/vertica/config/vertica_cluster.yaml
+`,
+		[]string{dbNameFlag, hostsFlag, ipv6Flag, eonModeFlag, configFlag, passwordFlag, outputFileFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsRequired(cmd, "action")
+
+	// hide eon mode flag since we expect it to come from config file, not from user input
+	hideLocalFlags(cmd, []string{eonModeFlag})
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdManageDepot) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.action,
+		"action",
+		"",
+		fmt.Sprintf("The depot action to run, one of %q, %q, or %q",
+			vclusterops.DepotActionResize, vclusterops.DepotActionClear, vclusterops.DepotActionWarm),
+	)
+	cmd.Flags().StringSliceVar(
+		&c.manageDepotOptions.NodeNames,
+		"node-names",
+		[]string{},
+		"Comma-separated list of the nodes to run the action on. Default is all UP nodes of the main cluster",
+	)
+	cmd.Flags().StringVar(
+		&c.manageDepotOptions.DepotSize,
+		"depot-size",
+		"",
+		"The new size of the depot, for the resize action. Two formats are supported: % and KMGT, e.g., 50% or 10G",
+	)
+	cmd.Flags().StringSliceVar(
+		&c.manageDepotOptions.Objects,
+		"objects",
+		[]string{},
+		"Comma-separated list of the tables or projections to load into the depot, for the warm action",
+	)
+}
+
+func (c *CmdManageDepot) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogArgParse(&c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.manageDepotOptions.DatabaseOptions)
+
+	// manage_depot only works for an Eon db so we assume the user always runs this subcommand
+	// on an Eon db. When Eon mode cannot be found in config file, we set its value to true.
+	if !viper.IsSet(eonModeKey) {
+		c.manageDepotOptions.IsEon = true
+	}
+	c.manageDepotOptions.Action = vclusterops.DepotAction(c.action)
+
+	return c.validateParse(logger)
+}
+
+func (c *CmdManageDepot) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+	err := c.getCertFilesFromCertPaths(&c.manageDepotOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.manageDepotOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.manageDepotOptions.DatabaseOptions)
+}
+
+func (c *CmdManageDepot) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.manageDepotOptions
+
+	results, runErr := vcc.VManageDepot(options)
+	if len(results) > 0 {
+		bytes, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("fail to marshal the depot action results, details %w", err)
+		}
+		c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+	}
+	if runErr != nil {
+		vcc.LogError(runErr, "failed to manage the depot", "action", options.Action)
+		return runErr
+	}
+	vcc.PrintInfo("Successfully ran depot action %s on %d nodes", options.Action, len(results))
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdManageDepot
+func (c *CmdManageDepot) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.manageDepotOptions.DatabaseOptions = *opt
+}
@@ -0,0 +1,135 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdPurgeTrash
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdPurgeTrash struct {
+	purgeTrashOptions *vclusterops.VPurgeTrashOptions
+
+	CmdBase
+}
+
+func makeCmdPurgeTrash() *cobra.Command {
+	newCmd := &CmdPurgeTrash{}
+
+	opt := vclusterops.VPurgeTrashOptionsFactory()
+	newCmd.purgeTrashOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		purgeTrashSubCmd,
+		"Purge the directories moved to a trash location",
+		`This command deletes the directories that a force removal moved to a trash
+location, instead of deleting them. The create_db, add_node, and revive_db
+commands move the directories to the trash location when they are run with
+--force-removal and --trash-location.
+
+With --older-than-hours, only the directories that were moved to the trash
+more than the given hours ago are deleted.
+
+The database does not need to exist, but the NMA must be running on the hosts.
+The purged directories of each host are written as JSON.
+
+Examples:
+  # Purge all directories in the trash
+  vcluster purge_trash --hosts 10.20.30.40,10.20.30.41,10.20.30.42 \
+    --trash-location /data/trash
+
+  # Purge the directories that were moved to the trash more than a day ago
+  vcluster purge_trash --trash-location /data/trash --older-than-hours 24 \
+    --config /opt/vertica/config/vertica_cluster.yaml
+`,
+		[]string{hostsFlag, ipv6Flag, configFlag, outputFileFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsRequired(cmd, trashLocationFlag)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdPurgeTrash) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.purgeTrashOptions.TrashLocation,
+		trashLocationFlag,
+		"",
+		"The trash location that the directories were moved to",
+	)
+	cmd.Flags().IntVar(
+		&c.purgeTrashOptions.OlderThanHours,
+		"older-than-hours",
+		0,
+		"Only purge the directories that were moved to the trash more than these hours ago",
+	)
+}
+
+func (c *CmdPurgeTrash) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogArgParse(&c.argv)
+
+	return c.validateParse(logger)
+}
+
+func (c *CmdPurgeTrash) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+	if !c.usePassword() {
+		err := c.getCertFilesFromCertPaths(&c.purgeTrashOptions.DatabaseOptions)
+		if err != nil {
+			return err
+		}
+	}
+	return c.ValidateParseBaseOptions(&c.purgeTrashOptions.DatabaseOptions)
+}
+
+func (c *CmdPurgeTrash) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.purgeTrashOptions
+
+	purgedPaths, err := vcc.VPurgeTrash(options)
+	if err != nil {
+		vcc.LogError(err, "failed to purge the trash", "trash location", options.TrashLocation)
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(purgedPaths, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fail to marshal the purged paths, details %w", err)
+	}
+	c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+	vcc.PrintInfo("Successfully purged the trash location %s", options.TrashLocation)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdPurgeTrash
+func (c *CmdPurgeTrash) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.purgeTrashOptions.DatabaseOptions = *opt
+}
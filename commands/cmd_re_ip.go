@@ -57,6 +57,12 @@ following format:
 ] 
 
 Include in the file only the nodes whose IP addresses you want to change.
+Instead of the file, you can give the new addresses of the nodes with the
+re-ip-nodes option.
+
+With the online option, re_ip changes the IP addresses of down nodes of a
+running database through its UP nodes, and reloads spread. You can then
+start the nodes with restart_node.
 		
 Examples:
   # Alter the IP address of database nodes with user input
@@ -66,6 +72,11 @@ Examples:
   # Alter the IP address of database nodes with config file
   vcluster re_ip --db-name test_db --re-ip-file /data/re_ip_map.json \
     --config /opt/vertica/config/vertica_cluster.yaml
+
+  # Alter the IP address of down nodes of a running database
+  vcluster re_ip --db-name test_db --online \
+    --re-ip-nodes v_test_db_node0002=10.20.30.41,v_test_db_node0003=10.20.30.43 \
+    --config /opt/vertica/config/vertica_cluster.yaml
 `,
 		[]string{dbNameFlag, hostsFlag, ipv6Flag, catalogPathFlag, configParamFlag, configFlag},
 	)
@@ -73,8 +84,9 @@ Examples:
 	// local flags
 	newCmd.setLocalFlags(cmd)
 
-	// require re-ip-file
-	markFlagsRequired(cmd, reIPFileFlag)
+	// require re-ip-file or re-ip-nodes
+	cmd.MarkFlagsOneRequired(reIPFileFlag, reIPNodesFlag)
+	cmd.MarkFlagsMutuallyExclusive(reIPFileFlag, reIPNodesFlag)
 	markFlagsFileName(cmd, map[string][]string{reIPFileFlag: {"json"}})
 
 	return cmd
@@ -88,14 +100,27 @@ func (c *CmdReIP) setLocalFlags(cmd *cobra.Command) {
 		"",
 		"Path of the re-ip file",
 	)
+	cmd.Flags().StringToStringVar(
+		&c.reIPOptions.NodeAddresses,
+		reIPNodesFlag,
+		map[string]string{},
+		"Comma-separated list of <node_name=new_address> pairs of the nodes to re-ip",
+	)
+	cmd.Flags().BoolVar(
+		&c.reIPOptions.OnlineReIP,
+		"online",
+		false,
+		"Re-ip down nodes of a running database, and reload spread",
+	)
 }
 
 func (c *CmdReIP) Parse(inputArgv []string, logger vlog.Printer) error {
 	c.argv = inputArgv
 	logger.LogArgParse(&c.argv)
 	// Set CheckDBRunning to true so that CLI can check running db for Re_IP
-	// Re-IP should only be used for down DB, checking if db is running
-	c.reIPOptions.CheckDBRunning = true
+	// Re-IP should only be used for down DB, checking if db is running,
+	// unless it is done online
+	c.reIPOptions.CheckDBRunning = !c.reIPOptions.OnlineReIP
 	return c.validateParse(logger)
 }
 
@@ -116,6 +141,9 @@ func (c *CmdReIP) validateParse(logger vlog.Printer) error {
 	if err != nil {
 		return err
 	}
+	if c.reIPFilePath == "" {
+		return nil
+	}
 	return c.reIPOptions.ReadReIPFile(c.reIPFilePath)
 }
 
@@ -158,7 +186,9 @@ func (c *CmdReIP) Run(vcc vclusterops.ClusterCommands) error {
 	return nil
 }
 
-// UpdateConfig will update node addresses in the config object after re_ip
+// UpdateConfig will update node addresses in the config object after re_ip.
+// The saved startup commands of the re-IPed nodes are cleared, as they have
+// the old addresses.
 func (c *CmdReIP) UpdateConfig(dbConfig *DatabaseConfig) {
 	nodeNameToAddress := make(map[string]string)
 	for _, reIPInfo := range c.reIPOptions.ReIPList {
@@ -169,8 +199,9 @@ func (c *CmdReIP) UpdateConfig(dbConfig *DatabaseConfig) {
 
 	for _, n := range dbConfig.Nodes {
 		newAddress, ok := nodeNameToAddress[n.Name]
-		if ok {
+		if ok && newAddress != n.Address {
 			n.Address = newAddress
+			n.StartCommand = nil
 		}
 	}
 }
@@ -0,0 +1,184 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdRebalanceShards
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdRebalanceShards struct {
+	rebalanceShardsOptions *vclusterops.VRebalanceShardsOptions
+	jobStatusOptions       *vclusterops.VRebalanceJobStatusOptions
+
+	CmdBase
+}
+
+func makeCmdRebalanceShards() *cobra.Command {
+	newCmd := &CmdRebalanceShards{}
+
+	opt := vclusterops.VRebalanceShardsOptionsFactory()
+	newCmd.rebalanceShardsOptions = &opt
+	statusOpt := vclusterops.VRebalanceJobStatusOptionsFactory()
+	newCmd.jobStatusOptions = &statusOpt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		rebalanceShardsSubCmd,
+		"Rebalance the shards of a subcluster",
+		`This command rebalances the shards of a subcluster in an Eon Mode database,
+and waits for the rebalance to complete. The progress and the estimated time
+to completion are logged while waiting.
+
+With --async, the command starts the rebalance and prints the rebalance job
+without waiting for it to complete. The progress of the job can then be
+checked with --job-id.
+
+Examples:
+  # Rebalance the shards of a subcluster with config file
+  vcluster rebalance_shards --subcluster sc1 \
+    --config /opt/vertica/config/vertica_cluster.yaml
+
+  # Start the rebalance of a subcluster in the background with user input
+  vcluster rebalance_shards --subcluster sc1 --async \
+    --db-name test_db --hosts 10.20.30.40,10.20.30.41,10.20.30.42
+
+  # Check the progress of a rebalance job
+  vcluster rebalance_shards --job-id 45035996273705058 \
+    --config /opt/vertica/config/vertica_cluster.yaml
+`,
+		[]string{dbNameFlag, hostsFlag, ipv6Flag, eonModeFlag, configFlag, passwordFlag, outputFileFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	cmd.MarkFlagsMutuallyExclusive(subclusterFlag, "job-id")
+	cmd.MarkFlagsOneRequired(subclusterFlag, "job-id")
+	cmd.MarkFlagsMutuallyExclusive("async", "job-id")
+
+	// hide eon mode flag since we expect it to come from config file, not from user input
+	hideLocalFlags(cmd, []string{eonModeFlag})
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdRebalanceShards) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.rebalanceShardsOptions.SCName,
+		subclusterFlag,
+		"",
+		"The name of the subcluster to rebalance the shards of",
+	)
+	cmd.Flags().BoolVar(
+		&c.rebalanceShardsOptions.Async,
+		"async",
+		false,
+		"Start the rebalance and return the rebalance job without waiting for it to complete",
+	)
+	cmd.Flags().IntVar(
+		&c.rebalanceShardsOptions.StatePollingTimeout,
+		"timeout",
+		c.rebalanceShardsOptions.StatePollingTimeout,
+		"The timeout in seconds to wait for the rebalance to complete. A negative value means no timeout",
+	)
+	cmd.Flags().StringVar(
+		&c.jobStatusOptions.JobID,
+		"job-id",
+		"",
+		"The ID of a rebalance job to print the progress of, rather than starting a rebalance",
+	)
+}
+
+func (c *CmdRebalanceShards) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogArgParse(&c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.rebalanceShardsOptions.DatabaseOptions)
+
+	// rebalance_shards only works for an Eon db so we assume the user always runs this subcommand
+	// on an Eon db. When Eon mode cannot be found in config file, we set its value to true.
+	if !viper.IsSet(eonModeKey) {
+		c.rebalanceShardsOptions.IsEon = true
+	}
+
+	return c.validateParse(logger)
+}
+
+func (c *CmdRebalanceShards) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+	err := c.getCertFilesFromCertPaths(&c.rebalanceShardsOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.rebalanceShardsOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.rebalanceShardsOptions.DatabaseOptions)
+}
+
+func (c *CmdRebalanceShards) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	var job vclusterops.RebalanceJob
+	var err error
+	if c.jobStatusOptions.JobID != "" {
+		c.jobStatusOptions.DatabaseOptions = c.rebalanceShardsOptions.DatabaseOptions
+		job, err = vcc.VGetRebalanceJobStatus(c.jobStatusOptions)
+		if err != nil {
+			vcc.LogError(err, "failed to get the rebalance job status", "jobID", c.jobStatusOptions.JobID)
+			return err
+		}
+	} else {
+		job, err = vcc.VRebalanceShards(c.rebalanceShardsOptions)
+		if err != nil {
+			vcc.LogError(err, "failed to rebalance shards", "subcluster", c.rebalanceShardsOptions.SCName)
+			return err
+		}
+	}
+
+	bytes, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fail to marshal the rebalance job, details %w", err)
+	}
+	c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+	if job.IsDone() {
+		vcc.PrintInfo("Rebalance job %s of subcluster %s is %s", job.JobID, job.SCName, job.State)
+	} else {
+		vcc.PrintInfo("Rebalance job %s of subcluster %s is %.1f%% done", job.JobID, job.SCName, job.ProgressPercent)
+	}
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdRebalanceShards
+func (c *CmdRebalanceShards) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.rebalanceShardsOptions.DatabaseOptions = *opt
+}
@@ -87,6 +87,12 @@ func (c *CmdRemoveNode) setLocalFlags(cmd *cobra.Command) {
 		true,
 		"Whether to force clean-up of existing directories if they are not empty",
 	)
+	cmd.Flags().BoolVar(
+		&c.removeNodeOptions.Force,
+		"force",
+		false,
+		"Remove the host(s) even if the database would lose its quorum, shard coverage, or K-safety",
+	)
 }
 
 func (c *CmdRemoveNode) Parse(inputArgv []string, logger vlog.Printer) error {
@@ -0,0 +1,136 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdRemoveRestorePoint
+ *
+ * Parses arguments for VRemoveRestorePointOptions to pass down to
+ * VRemoveRestorePoint.
+ *
+ * Implements ClusterCommand interface
+ */
+
+type CmdRemoveRestorePoint struct {
+	CmdBase
+	removeRestorePointOptions *vclusterops.VRemoveRestorePointOptions
+}
+
+func makeCmdRemoveRestorePoint() *cobra.Command {
+	// CmdRemoveRestorePoint
+	newCmd := &CmdRemoveRestorePoint{}
+	opt := vclusterops.VRemoveRestorePointOptionsFactory()
+	newCmd.removeRestorePointOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		removeRestorePointSubCmd,
+		"Remove a restore point from an archive",
+		`This command removes a restore point from an archive of an Eon Mode database,
+and frees the communal storage that only the restore point uses.
+
+The --restore-point-id option is the identifier of the restore point, as
+listed by show_restore_points.
+
+Examples:
+  # Remove a restore point from the archive
+  vcluster remove_restore_point --db-name test_db --archive-name daily \
+    --restore-point-id 8f3d0ad1-4c4e-4b0e-9b3b-3bf3f6c0f3a1 \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42
+`,
+		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, passwordFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsRequired(cmd, archiveNameFlag, restorePointIDFlag)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdRemoveRestorePoint) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.removeRestorePointOptions.ArchiveName,
+		archiveNameFlag,
+		"",
+		"The name of the archive that the restore point is in",
+	)
+	cmd.Flags().StringVar(
+		&c.removeRestorePointOptions.RestorePointID,
+		restorePointIDFlag,
+		"",
+		"The identifier of the restore point to remove",
+	)
+}
+
+func (c *CmdRemoveRestorePoint) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.removeRestorePointOptions.DatabaseOptions)
+
+	return c.validateParse(logger)
+}
+
+// all validations of the arguments should go in here
+func (c *CmdRemoveRestorePoint) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	err := c.getCertFilesFromCertPaths(&c.removeRestorePointOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.removeRestorePointOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.removeRestorePointOptions.DatabaseOptions)
+}
+
+func (c *CmdRemoveRestorePoint) Analyze(_ vlog.Printer) error {
+	return nil
+}
+
+func (c *CmdRemoveRestorePoint) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.removeRestorePointOptions
+
+	err := vcc.VRemoveRestorePoint(options)
+	if err != nil {
+		vcc.LogError(err, "failed to remove the restore point", "archive", options.ArchiveName, "restorePointID", options.RestorePointID)
+		return err
+	}
+
+	vcc.PrintInfo("Successfully removed restore point %s from archive %s", options.RestorePointID, options.ArchiveName)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdRemoveRestorePoint
+func (c *CmdRemoveRestorePoint) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.removeRestorePointOptions.DatabaseOptions = *opt
+}
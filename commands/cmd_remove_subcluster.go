@@ -58,6 +58,10 @@ Examples:
   vcluster remove_subcluster --db-name test_db \
     --hosts 10.20.30.40,10.20.30.41,10.20.30.42 --subcluster sc1 \
     --data-path /data --depot-path /data
+
+  # Drain the user sessions for up to 5 minutes before removing the subcluster
+  vcluster remove_subcluster --subcluster sc1 --drain-seconds 300 \
+    --config /opt/vertica/config/vertica_cluster.yaml
 `,
 		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, eonModeFlag, dataPathFlag, depotPathFlag, passwordFlag},
 	)
@@ -88,6 +92,13 @@ func (c *CmdRemoveSubcluster) setLocalFlags(cmd *cobra.Command) {
 		true,
 		"Whether force delete directories if they are not empty",
 	)
+	cmd.Flags().IntVar(
+		&c.removeScOptions.DrainSeconds,
+		"drain-seconds",
+		0,
+		"Pause new connections to the subcluster and wait up to this many seconds for its user sessions "+
+			"to end before removing its nodes. The default value 0 removes the nodes without draining.",
+	)
 }
 
 func (c *CmdRemoveSubcluster) Parse(inputArgv []string, logger vlog.Printer) error {
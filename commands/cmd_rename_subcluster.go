@@ -0,0 +1,166 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdRenameSubcluster
+ *
+ * Implements ClusterCommand interface
+ *
+ * Parses CLI arguments for rename subcluster operation.
+ * Prepares the inputs for the library.
+ *
+ */
+type CmdRenameSubcluster struct {
+	CmdBase
+	renameScOptions vclusterops.VRenameSubclusterOptions
+}
+
+func makeCmdRenameSubcluster() *cobra.Command {
+	// CmdRenameSubcluster
+	newCmd := &CmdRenameSubcluster{}
+	newCmd.renameScOptions = vclusterops.VRenameSubclusterFactory()
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		renameSCSubCmd,
+		"Rename a subcluster",
+		`This command renames a subcluster in an existing Eon Mode database.
+
+You must provide the current subcluster name with the --subcluster option and
+the new name with the --new-name option. To rename a subcluster in a sandbox,
+provide the sandbox name with the --sandbox option.
+
+Examples:
+  # Rename a subcluster with config file
+  vcluster rename_subcluster --subcluster sc1 --new-name analytics \
+    --config /opt/vertica/config/vertica_cluster.yaml
+
+  # Rename a subcluster in a sandbox with user input
+  vcluster rename_subcluster --subcluster sc2 --new-name sc2_sand \
+    --sandbox sand --hosts 10.20.30.40,10.20.30.41 --db-name test_db
+`,
+		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, eonModeFlag, passwordFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	// require the current and the new name of the subcluster
+	markFlagsRequired(cmd, subclusterFlag, newSubclusterNameFlag)
+
+	// hide eon mode flag since we expect it to come from config file, not from user input
+	hideLocalFlags(cmd, []string{eonModeFlag})
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdRenameSubcluster) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.renameScOptions.SCName,
+		subclusterFlag,
+		"",
+		"The name of the subcluster to be renamed",
+	)
+	cmd.Flags().StringVar(
+		&c.renameScOptions.NewSCName,
+		newSubclusterNameFlag,
+		"",
+		"The new name of the subcluster",
+	)
+	cmd.Flags().StringVar(
+		&c.renameScOptions.Sandbox,
+		sandboxFlag,
+		"",
+		"The name of the sandbox that contains the subcluster",
+	)
+}
+
+func (c *CmdRenameSubcluster) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// reset some options that are not included in user input
+	c.ResetUserInputOptions(&c.renameScOptions.DatabaseOptions)
+
+	// rename_subcluster only works for an Eon db so we assume the user always runs this subcommand
+	// on an Eon db. When Eon mode cannot be found in config file, we set its value to true.
+	if !viper.IsSet(eonModeKey) {
+		c.renameScOptions.IsEon = true
+	}
+	return c.validateParse(logger)
+}
+
+func (c *CmdRenameSubcluster) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	err := c.getCertFilesFromCertPaths(&c.renameScOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.renameScOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.renameScOptions.DatabaseOptions)
+}
+
+func (c *CmdRenameSubcluster) Analyze(logger vlog.Printer) error {
+	logger.Info("Called method Analyze()")
+	return nil
+}
+
+func (c *CmdRenameSubcluster) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Calling method Run() for command " + renameSCSubCmd)
+
+	options := c.renameScOptions
+
+	err := vcc.VRenameSubcluster(&options)
+	if err != nil {
+		return err
+	}
+	vcc.PrintInfo("Successfully renamed subcluster %s to %s", options.SCName, options.NewSCName)
+
+	// update the subcluster name of the nodes in the config file
+	dbConfig, configErr := readConfig()
+	if configErr != nil {
+		vcc.PrintWarning("fail to read config file, skipping config file update, details: %s", configErr)
+		return nil
+	}
+	for _, n := range dbConfig.Nodes {
+		if n.Subcluster == options.SCName {
+			n.Subcluster = options.NewSCName
+		}
+	}
+	writeErr := dbConfig.write(options.ConfigPath, true /*forceOverwrite*/)
+	if writeErr != nil {
+		vcc.PrintWarning("fail to write the config file, details: %s", writeErr)
+	}
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdRenameSubcluster
+func (c *CmdRenameSubcluster) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.renameScOptions.DatabaseOptions = *opt
+}
@@ -27,5 +27,6 @@ func makeCmdReplication() *cobra.Command {
 in-progress replication operation.`)
 
 	cmd.AddCommand(makeCmdStartReplication())
+	cmd.AddCommand(makeCmdReplicationStatus())
 	return cmd
 }
@@ -0,0 +1,196 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdReplicationStatus
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdReplicationStatus struct {
+	statusOptions *vclusterops.VReplicationStatusDatabaseOptions
+	CmdBase
+	targetPasswordFile string
+}
+
+func makeCmdReplicationStatus() *cobra.Command {
+	newCmd := &CmdReplicationStatus{}
+	opt := vclusterops.VReplicationStatusDatabaseFactory()
+	newCmd.statusOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		replicationStatusSubCmd,
+		"Show the status of an asynchronous replication",
+		`This subcommand displays the progress of an asynchronous replication
+started with vcluster replication start --async.
+
+You must provide the transaction ID printed by vcluster replication start with
+the --transaction-id option. The status is reported by the target database,
+which you can describe with the --target-conn option or with the
+--target-db-name, --target-hosts, and --target-password-file options.
+
+Examples:
+  # Show the status of a replication with a connection file
+  vcluster replication status --transaction-id 45035996273704962 \
+    --target-conn /opt/vertica/config/target_connection.yaml
+
+  # Show the status of a replication with user input
+  vcluster replication status --transaction-id 45035996273704962 \
+    --target-db-name platform_db --target-hosts 10.20.30.43 --target-db-user dbadmin \
+    --target-password-file /path/to/password-file
+`,
+		[]string{configFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	// either target dbname+hosts or connection file must be provided
+	cmd.MarkFlagsOneRequired(targetConnFlag, targetDBNameFlag)
+	cmd.MarkFlagsOneRequired(targetConnFlag, targetHostsFlag)
+	markFlagsRequired(cmd, transactionIDFlag)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdReplicationStatus) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().Int64Var(
+		&c.statusOptions.TransactionID,
+		transactionIDFlag,
+		0,
+		"The transaction ID of the replication",
+	)
+	cmd.Flags().StringVar(
+		&c.statusOptions.DBName,
+		targetDBNameFlag,
+		"",
+		"The target database of the replication",
+	)
+	cmd.Flags().StringSliceVar(
+		&c.statusOptions.RawHosts,
+		targetHostsFlag,
+		[]string{},
+		"Comma-separated list of hosts in target database")
+	cmd.Flags().StringVar(
+		&c.statusOptions.UserName,
+		targetUserNameFlag,
+		"",
+		"The username for connecting to the target database",
+	)
+	cmd.Flags().StringVar(
+		&globals.connFile,
+		targetConnFlag,
+		"",
+		"The connection file created with the create_connection command, "+
+			"containing the database name, hosts, and password (if any) for the target database",
+	)
+	markFlagsFileName(cmd, map[string][]string{targetConnFlag: {"yaml"}})
+	cmd.Flags().StringVar(
+		&c.targetPasswordFile,
+		targetPasswordFileFlag,
+		"",
+		"Path to the file to read the password for target database. ",
+	)
+}
+
+func (c *CmdReplicationStatus) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	return c.validateParse(logger)
+}
+
+// all validations of the arguments should go in here
+func (c *CmdReplicationStatus) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	err := c.getCertFilesFromCertPaths(&c.statusOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	if len(c.statusOptions.RawHosts) > 0 {
+		err = util.ParseHostList(&c.statusOptions.RawHosts)
+		if err != nil {
+			return fmt.Errorf("must specify at least one target host")
+		}
+	}
+	return c.parseTargetPassword()
+}
+
+func (c *CmdReplicationStatus) parseTargetPassword() error {
+	options := c.statusOptions
+	if !viper.IsSet(targetPasswordFileKey) {
+		// reset password option to nil if password is not provided in cli
+		options.Password = nil
+		return nil
+	}
+	if c.targetPasswordFile == "" {
+		return fmt.Errorf("target password file path is empty")
+	}
+	password, err := c.passwordFileHelper(c.targetPasswordFile)
+	if err != nil {
+		return err
+	}
+	options.Password = &password
+	return nil
+}
+
+func (c *CmdReplicationStatus) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.statusOptions
+	status, err := vcc.VGetReplicationStatus(options)
+	if err != nil {
+		vcc.LogError(err, "fail to get replication status", "transactionID", options.TransactionID)
+		return err
+	}
+
+	switch {
+	case status.Failed:
+		vcc.PrintInfo("Replication %d failed in phase %s: %s", status.TransactionID, status.Phase, status.FailureDetail)
+	case status.Completed:
+		vcc.PrintInfo("Replication %d completed, %d/%d tables and %d bytes transferred",
+			status.TransactionID, status.TablesDone, status.TablesTotal, status.BytesTransferred)
+	default:
+		vcc.PrintInfo("Replication %d is in phase %s, %d/%d tables and %d/%d bytes transferred",
+			status.TransactionID, status.Phase, status.TablesDone, status.TablesTotal,
+			status.BytesTransferred, status.TotalBytes)
+	}
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance. The
+// database options are replaced by those of the target database.
+func (c *CmdReplicationStatus) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.statusOptions.DatabaseOptions = *opt
+	c.statusOptions.DBName = globals.targetDB
+	c.statusOptions.RawHosts = globals.targetHosts
+	c.statusOptions.Hosts = nil
+	c.statusOptions.UserName = globals.targetUserName
+	c.targetPasswordFile = globals.targetPasswordFile
+}
@@ -37,6 +37,9 @@ type CmdRestartNodes struct {
 
 	// comma-separated list of hosts
 	rawStartHostList []string
+
+	// <node_name>=<argument> values of --start-command-override
+	rawStartCommandOverrides []string
 }
 
 func makeCmdRestartNodes() *cobra.Command {
@@ -107,6 +110,14 @@ func (c *CmdRestartNodes) setLocalFlags(cmd *cobra.Command) {
 		util.DefaultTimeoutSeconds,
 		"The timeout (in seconds) to wait for polling node state operation",
 	)
+	cmd.Flags().StringArrayVar(
+		&c.rawStartCommandOverrides,
+		"start-command-override",
+		[]string{},
+		"Vertica argument to merge into the stored start command of a node, as <node_name>=<argument>, "+
+			"e.g., v_db_node0001=-D followed by \"v_db_node0001=/data/db/v_db_node0001_catalog\". "+
+			"This can be repeated, and the arguments of a node are merged in order",
+	)
 
 	// VER-90436: restart -> start
 	// users only input --restart or --start-hosts
@@ -152,6 +163,11 @@ func (c *CmdRestartNodes) validateParse(logger vlog.Printer) error {
 	if err != nil {
 		return err
 	}
+
+	c.restartNodesOptions.StartCommandOverrides, err = parseStartCommandOverrides(c.rawStartCommandOverrides)
+	if err != nil {
+		return err
+	}
 	return c.setDBPassword(&c.restartNodesOptions.DatabaseOptions)
 }
 
@@ -160,8 +176,16 @@ func (c *CmdRestartNodes) Run(vcc vclusterops.ClusterCommands) error {
 
 	options := c.restartNodesOptions
 
+	loadStartCommandsFromConfig(&options.DatabaseOptions)
+
 	// this is the instruction that will be used by both CLI and operator
-	err := vcc.VStartNodes(options)
+	results, err := vcc.VStartNodes(options)
+	for _, result := range results {
+		if result.State != util.NodeUpState && result.DBLogPath != "" {
+			vcc.PrintWarning("Node %s on host %s did not start, check its log at %s",
+				result.NodeName, result.Host, result.DBLogPath)
+		}
+	}
 	if err != nil {
 		return err
 	}
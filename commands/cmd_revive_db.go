@@ -53,11 +53,33 @@ The number of hosts that you provide to the --hosts option must match the
 number of hosts in the existing database. You can omit the hosts only if
 --display-only is specified.
 
+By default, the hosts are assigned to the nodes sorted by name. To control
+which host each node is revived on, e.g., when the hosts have different
+storage, provide the --node-host-map option to map the name of every node to
+one of the hosts.
+
+To revive the database onto fewer hosts, provide the --partial-revive option
+and map only the nodes to revive with --node-host-map. All of the primary
+nodes must be mapped. The other nodes are not revived, and can be replaced
+later, e.g., by removing them and adding new nodes with add_node.
+
+To revive a sandbox from its own catalog on the communal storage instead of
+the main cluster, e.g., to test the recovery of a sandboxed workload, provide
+the --sandbox option. The restore point options then select a restore point
+of the sandbox.
+
 The name of the database must be provided.
 
 To restore a database to a restore point, you must provide the
---restore-point-archive option, and specify the restore point with either the
---restore-point-index or --restore-point-id option.
+--restore-point-archive option, and specify the restore point with the
+--restore-point-index or --restore-point-id options, or both, or with the
+--restore-point-timestamp option. --restore-point-timestamp selects the most
+recent restore point that was created no later than the given time, e.g., right
+before a bad deployment.
+
+To list the restore points of an archive together with the description of the
+database, provide --display-only and --restore-point-archive without selecting
+a restore point.
 
 Examples:
   # Revive a database with user input and save the generated config file
@@ -71,6 +93,10 @@ Examples:
   vcluster revive_db --db-name test_db --communal-storage-location /communal \
     --display-only
 
+  # Describe the database and list the restore points of an archive
+  vcluster revive_db --db-name test_db --communal-storage-location /communal \
+    --display-only --restore-point-archive db
+
   # Revive a database with user input by restoring to a given restore point
   vcluster revive_db --db-name test_db \
     --hosts 10.20.30.40,10.20.30.41,10.20.30.42 \
@@ -78,6 +104,20 @@ Examples:
     --config /opt/vertica/config/vertica_cluster.yaml --force-removal \
     --ignore-cluster-lease --restore-point-archive db --restore-point-index 1
 
+  # Revive a database and save the progress on failure, so that running the
+  # same command again resumes from the failed step
+  vcluster revive_db --db-name test_db \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42 \
+    --communal-storage-location /communal \
+    --checkpoint-dir /opt/vertica/config
+
+  # Revive only the primary nodes of a database onto two hosts
+  vcluster revive_db --db-name test_db \
+    --hosts 10.20.30.40,10.20.30.41 \
+    --node-host-map v_test_db_node0001=10.20.30.40,v_test_db_node0002=10.20.30.41 \
+    --partial-revive \
+    --communal-storage-location /communal
+
 `,
 		[]string{dbNameFlag, hostsFlag, ipv6Flag, communalStorageLocationFlag, configFlag, outputFileFlag, configParamFlag},
 	)
@@ -107,6 +147,7 @@ func (c *CmdReviveDB) setLocalFlags(cmd *cobra.Command) {
 		"Prior to reviving a database, ensure the deletion of pre-existing database directories "+
 			"(excluding user storage directories)",
 	)
+	c.setTrashFlags(cmd, &c.reviveDBOptions.Trash)
 	cmd.Flags().BoolVar(
 		&c.reviveDBOptions.DisplayOnly,
 		"display-only",
@@ -138,8 +179,47 @@ func (c *CmdReviveDB) setLocalFlags(cmd *cobra.Command) {
 		"",
 		"The identifier of the restore point in the restore archive to restore from",
 	)
-	// only one of restore-point-index or restore-point-id" will be required
-	cmd.MarkFlagsMutuallyExclusive("restore-point-index", "restore-point-id")
+	cmd.Flags().StringVar(
+		&c.reviveDBOptions.CheckpointDir,
+		"checkpoint-dir",
+		"",
+		"Absolute path of a directory where the progress is saved if reviving the database fails. "+
+			"Running the command again with the same options resumes from the failed step",
+	)
+	cmd.Flags().BoolVar(
+		&c.reviveDBOptions.DisableRollback,
+		"no-rollback",
+		false,
+		"Keep the directories prepared on the hosts if reviving the database fails, to help debug the failure",
+	)
+	cmd.Flags().StringVar(
+		&c.reviveDBOptions.RestorePoint.Timestamp,
+		"restore-point-timestamp",
+		"",
+		"Restore from the most recent restore point in the restore archive that was created no later than "+
+			"this UTC timestamp, e.g., \"2024-03-04 08:32:33\" or 2024-03-04 for the end of the day",
+	)
+	cmd.Flags().StringToStringVar(
+		&c.reviveDBOptions.NodeHostMap,
+		"node-host-map",
+		map[string]string{},
+		"Comma-separated list of <node_name=host> pairs that specify the host to revive each node on",
+	)
+	cmd.Flags().StringVar(
+		&c.reviveDBOptions.Sandbox,
+		sandboxFlag,
+		"",
+		"Name of the sandbox to revive instead of the main cluster",
+	)
+	cmd.Flags().BoolVar(
+		&c.reviveDBOptions.PartialRevive,
+		"partial-revive",
+		false,
+		"Revive only the nodes in --node-host-map, which must include all of the primary nodes",
+	)
+	// the timestamp selects a restore point on its own, the index and the id may be combined
+	cmd.MarkFlagsMutuallyExclusive("restore-point-index", "restore-point-timestamp")
+	cmd.MarkFlagsMutuallyExclusive("restore-point-id", "restore-point-timestamp")
 }
 
 func (c *CmdReviveDB) Parse(inputArgv []string, logger vlog.Printer) error {
@@ -176,15 +256,27 @@ func (c *CmdReviveDB) validateParse(logger vlog.Printer) error {
 
 func (c *CmdReviveDB) Run(vcc vclusterops.ClusterCommands) error {
 	vcc.LogInfo("Called method Run()")
-	dbInfo, vdb, err := vcc.VReviveDatabase(c.reviveDBOptions)
+	result, vdb, err := vcc.VReviveDatabase(c.reviveDBOptions)
 	if err != nil {
-		vcc.LogError(err, "fail to revive database", "DBName", c.reviveDBOptions.DBName)
+		vcc.LogError(err, "fail to revive database", "DBName", c.reviveDBOptions.DBName,
+			"restorePoints", result.RestorePoints)
 		return err
 	}
 
 	if c.reviveDBOptions.DisplayOnly {
-		c.writeCmdOutputToFile(globals.file, []byte(dbInfo), vcc.GetLog())
-		vcc.LogInfo("database details: ", "db-info", dbInfo)
+		c.writeCmdOutputToFile(globals.file, []byte(result.DBInfo), vcc.GetLog())
+		vcc.LogInfo("database details: ", "db-info", result.DBInfo, "restorePoints", result.RestorePoints)
+		if c.reviveDBOptions.RestorePoint.Archive != "" {
+			vcc.PrintInfo("Restore points in archive %s: %v", c.reviveDBOptions.RestorePoint.Archive, result.RestorePoints)
+		}
+		if result.Description != nil {
+			// the description tells whether the hosts match the nodes of the database
+			missingHosts, extraHosts := result.Description.DiffHosts(c.reviveDBOptions.Hosts)
+			if len(missingHosts) > 0 || len(extraHosts) > 0 {
+				vcc.PrintWarning("the hosts do not match the nodes of the database, hosts without a node: %v, "+
+					"nodes on other hosts: %v", missingHosts, extraHosts)
+			}
+		}
 		return nil
 	}
 
@@ -202,6 +294,10 @@ func (c *CmdReviveDB) Run(vcc vclusterops.ClusterCommands) error {
 	}
 
 	vcc.PrintInfo("Successfully revived database %s", c.reviveDBOptions.DBName)
+	if len(result.DeferredNodes) > 0 {
+		vcc.PrintWarning("nodes %v were not revived, and need to be replaced before they can be started",
+			result.DeferredNodes)
+	}
 
 	return nil
 }
@@ -0,0 +1,190 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdRotateTLSCerts
+ *
+ * Parses arguments for VRotateTLSCertsOptions to pass down to
+ * VRotateTLSCerts.
+ *
+ * Implements ClusterCommand interface
+ */
+
+type CmdRotateTLSCerts struct {
+	CmdBase
+	rotateCertsOptions *vclusterops.VRotateTLSCertsOptions
+	certificateFile    string
+	privateKeyFile     string
+	caCertificateFile  string
+}
+
+func makeCmdRotateTLSCerts() *cobra.Command {
+	// CmdRotateTLSCerts
+	newCmd := &CmdRotateTLSCerts{}
+	opt := vclusterops.VRotateTLSCertsOptionsFactory()
+	newCmd.rotateCertsOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		rotateTLSCertsSubCmd,
+		"Rotate the TLS certificates of the node management agents and the HTTPS service",
+		`This command replaces the key, the certificate and the CA certificate that
+the node management agents and the HTTPS service use.
+
+It stages the new certificates on every host, imports them into the database,
+sets the HTTPS TLS configuration to use them, switches the agents to them, and
+then verifies that every service presents the new certificate chain before the
+previous certificates are removed. If any of these steps fails, the previous
+certificates and HTTPS TLS configuration are restored.
+
+Examples:
+  # Rotate the certificates with user input
+  vcluster rotate_tls_certs --db-name test_db \
+    --certificate-name https_cert_2024 --ca-certificate-name https_ca_2024 \
+    --certificate-file /path/to/https.crt --private-key-file /path/to/https.key \
+    --ca-certificate-file /path/to/ca.crt \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42
+
+  # Rotate the certificates with config file
+  vcluster rotate_tls_certs \
+    --certificate-name https_cert_2024 --ca-certificate-name https_ca_2024 \
+    --certificate-file /path/to/https.crt --private-key-file /path/to/https.key \
+    --ca-certificate-file /path/to/ca.crt \
+    --config /opt/vertica/config/vertica_cluster.yaml
+`,
+		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, passwordFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsRequired(cmd, "certificate-name", "ca-certificate-name", "certificate-file",
+		"private-key-file", "ca-certificate-file")
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdRotateTLSCerts) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.rotateCertsOptions.CertificateName,
+		"certificate-name",
+		"",
+		"The name of the new certificate in the database",
+	)
+	cmd.Flags().StringVar(
+		&c.rotateCertsOptions.CACertificateName,
+		"ca-certificate-name",
+		"",
+		"The name of the new CA certificate in the database",
+	)
+	cmd.Flags().StringVar(
+		&c.certificateFile,
+		"certificate-file",
+		"",
+		"Path of the PEM-encoded new certificate",
+	)
+	cmd.Flags().StringVar(
+		&c.privateKeyFile,
+		"private-key-file",
+		"",
+		"Path of the PEM-encoded private key of the new certificate",
+	)
+	cmd.Flags().StringVar(
+		&c.caCertificateFile,
+		"ca-certificate-file",
+		"",
+		"Path of the PEM-encoded CA certificate that signed the new certificate",
+	)
+}
+
+func (c *CmdRotateTLSCerts) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.rotateCertsOptions.DatabaseOptions)
+
+	return c.validateParse(logger)
+}
+
+// all validations of the arguments should go in here
+func (c *CmdRotateTLSCerts) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	files := []struct {
+		path  string
+		desc  string
+		value *string
+	}{
+		{c.certificateFile, "certificate", &c.rotateCertsOptions.CertificatePEM},
+		{c.privateKeyFile, "private key", &c.rotateCertsOptions.PrivateKeyPEM},
+		{c.caCertificateFile, "CA certificate", &c.rotateCertsOptions.CACertificatePEM},
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file.path)
+		if err != nil {
+			return fmt.Errorf("fail to read the %s file %s, details: %w", file.desc, file.path, err)
+		}
+		*file.value = string(content)
+	}
+
+	err := c.getCertFilesFromCertPaths(&c.rotateCertsOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.rotateCertsOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.rotateCertsOptions.DatabaseOptions)
+}
+
+func (c *CmdRotateTLSCerts) Analyze(_ vlog.Printer) error {
+	return nil
+}
+
+func (c *CmdRotateTLSCerts) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.rotateCertsOptions
+
+	err := vcc.VRotateTLSCerts(options)
+	if err != nil {
+		vcc.LogError(err, "failed to rotate the TLS certificates", "certificate", options.CertificateName)
+		return err
+	}
+
+	vcc.PrintInfo("Successfully rotated the TLS certificates to %s", options.CertificateName)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdRotateTLSCerts
+func (c *CmdRotateTLSCerts) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.rotateCertsOptions.DatabaseOptions = *opt
+}
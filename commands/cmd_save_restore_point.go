@@ -0,0 +1,131 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdSaveRestorePoint
+ *
+ * Parses arguments for VSaveRestorePointOptions to pass down to
+ * VSaveRestorePoint.
+ *
+ * Implements ClusterCommand interface
+ */
+
+type CmdSaveRestorePoint struct {
+	CmdBase
+	saveRestorePointOptions *vclusterops.VSaveRestorePointOptions
+}
+
+func makeCmdSaveRestorePoint() *cobra.Command {
+	// CmdSaveRestorePoint
+	newCmd := &CmdSaveRestorePoint{}
+	opt := vclusterops.VSaveRestorePointOptionsFactory()
+	newCmd.saveRestorePointOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		saveRestorePointSubCmd,
+		"Save a restore point to an archive",
+		`This command saves a restore point of an Eon Mode database to an archive that
+was created with create_archive. show_restore_points lists the saved restore
+points, and revive_db can restore the database to one of them.
+
+Examples:
+  # Save a restore point to the archive
+  vcluster save_restore_point --db-name test_db --archive-name daily \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42
+
+  # Save a restore point with config file
+  vcluster save_restore_point --archive-name db \
+    --config /opt/vertica/config/vertica_cluster.yaml
+`,
+		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, passwordFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsRequired(cmd, archiveNameFlag)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdSaveRestorePoint) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.saveRestorePointOptions.ArchiveName,
+		archiveNameFlag,
+		"",
+		"The name of the archive to save the restore point to",
+	)
+}
+
+func (c *CmdSaveRestorePoint) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.saveRestorePointOptions.DatabaseOptions)
+
+	return c.validateParse(logger)
+}
+
+// all validations of the arguments should go in here
+func (c *CmdSaveRestorePoint) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	err := c.getCertFilesFromCertPaths(&c.saveRestorePointOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.saveRestorePointOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.saveRestorePointOptions.DatabaseOptions)
+}
+
+func (c *CmdSaveRestorePoint) Analyze(_ vlog.Printer) error {
+	return nil
+}
+
+func (c *CmdSaveRestorePoint) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.saveRestorePointOptions
+
+	err := vcc.VSaveRestorePoint(options)
+	if err != nil {
+		vcc.LogError(err, "failed to save the restore point", "archive", options.ArchiveName)
+		return err
+	}
+
+	vcc.PrintInfo("Successfully saved a restore point to archive %s", options.ArchiveName)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdSaveRestorePoint
+func (c *CmdSaveRestorePoint) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.saveRestorePointOptions.DatabaseOptions = *opt
+}
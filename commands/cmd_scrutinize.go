@@ -98,13 +98,25 @@ specified hosts.
 The diagnostics are bundled together in a tar file and stored in 
 `+vclusterops.ScrutinizeOutputBasePath+`/VerticaScrutinize.<timestamp>.tar.
 
+If you use the --upload-location option, each host uploads its tarballs to
+<upload-location>/VerticaScrutinize.<timestamp>/ on the communal storage
+instead. Use the --config-param option to provide the credentials of the
+communal storage.
+
 Examples:
   # Scrutinize all nodes in the database with config file
   # option and password-based authentication
   vcluster scrutinize --db-name test_db --db-user dbadmin \
     --password testpassword --config /opt/vertica/config/vertica_cluster.yaml
+
+  # Scrutinize all nodes, including a catalog snapshot, and upload the
+  # results to communal storage
+  vcluster scrutinize --db-name test_db --include-catalog-snapshot \
+    --upload-location s3://bucket/scrutinize \
+    --config /opt/vertica/config/vertica_cluster.yaml \
+    --config-param awsauth=<access key>:<secret key>
 `,
-		[]string{dbNameFlag, hostsFlag, ipv6Flag, configFlag, catalogPathFlag, passwordFlag},
+		[]string{dbNameFlag, hostsFlag, ipv6Flag, configFlag, catalogPathFlag, passwordFlag, configParamFlag},
 	)
 
 	// local flags
@@ -134,21 +146,21 @@ func (c *CmdScrutinize) setLocalFlags(cmd *cobra.Command) {
 		&c.sOptions.LogAgeOldestTime,
 		"log-age-oldest-time",
 		"",
-		"Timestamp of the maximum age of archived vertica log files "+
+		"Timestamp of the maximum age of archived vertica log files and DC table rows "+
 			"to collect, formatted as "+vclusterops.ScrutinizeHelpTimeFormatDesc,
 	)
 	cmd.Flags().StringVar(
 		&c.sOptions.LogAgeNewestTime,
 		"log-age-newest-time",
 		"",
-		"Timestamp of the minimum age of archived vertica log files "+
+		"Timestamp of the minimum age of archived vertica log files and DC table rows "+
 			"to collect, formatted as "+vclusterops.ScrutinizeHelpTimeFormatDesc,
 	)
 	cmd.Flags().IntVar(
 		&c.sOptions.LogAgeHours,
 		"log-age-hours",
 		vclusterops.ScrutinizeLogMaxAgeHoursDefault,
-		"Maximum age of archived vertica log files to collect "+
+		"Maximum age of archived vertica log files and DC table rows to collect "+
 			"in hours, default "+fmt.Sprint(vclusterops.ScrutinizeLogMaxAgeHoursDefault),
 	)
 	cmd.MarkFlagsMutuallyExclusive("log-age-hours", "log-age-oldest-time")
@@ -191,6 +203,30 @@ func (c *CmdScrutinize) setLocalFlags(cmd *cobra.Command) {
 		false,
 		"Skip gathering linked and catalog shared libraries",
 	)
+	cmd.Flags().BoolVar(
+		&c.sOptions.IncludeCatalogSnapshot,
+		"include-catalog-snapshot",
+		false,
+		"Include a snapshot of the catalog of each node",
+	)
+	cmd.Flags().Int64Var(
+		&c.sOptions.LogSizeLimitBytes,
+		"log-size-limit-bytes",
+		vclusterops.ScrutinizeLogLimitBytesDefault,
+		"Maximum size in bytes of an individual vertica log file to collect",
+	)
+	cmd.Flags().Int64Var(
+		&c.sOptions.FileSizeLimitBytes,
+		"file-size-limit-bytes",
+		vclusterops.ScrutinizeFileLimitBytesDefault,
+		"Maximum size in bytes of an individual file or catalog snapshot to collect",
+	)
+	cmd.Flags().StringVar(
+		&c.sOptions.UploadLocation,
+		"upload-location",
+		"",
+		"Communal storage location to upload the tarballs to, instead of collecting them locally",
+	)
 }
 
 func (c *CmdScrutinize) Parse(inputArgv []string, logger vlog.Printer) error {
@@ -229,6 +265,10 @@ func (c *CmdScrutinize) validateParse(logger vlog.Printer) error {
 	if err != nil {
 		return err
 	}
+	err = c.setConfigParam(&c.sOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
 	return c.setDBPassword(&c.sOptions.DatabaseOptions)
 }
 
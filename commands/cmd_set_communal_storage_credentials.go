@@ -0,0 +1,165 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdSetCommunalStorageCredentials
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdSetCommunalStorageCredentials struct {
+	setCredentialsOptions *vclusterops.VSetCommunalStorageCredentialsOptions
+
+	CmdBase
+}
+
+func makeCmdSetCommunalStorageCredentials() *cobra.Command {
+	newCmd := &CmdSetCommunalStorageCredentials{}
+
+	opt := vclusterops.VSetCommunalStorageCredentialsOptionsFactory()
+	newCmd.setCredentialsOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		setCommunalCredsSubCmd,
+		"Rotate the communal storage credentials of a database",
+		`This command sets new credentials for the communal storage of an Eon Mode
+database, such as AWSAuth, GCSAuth, or AzureStorageCredentials.
+
+Before the credentials are changed, the description file of the database is read
+from the communal storage with the new credentials. The credentials are only set
+if the read succeeds, so that the database does not lose access to the communal
+storage. They are set at the database level, which applies them to all nodes.
+
+With --use-iam-role, the AWS keys are cleared so that the nodes access the
+communal storage with the IAM role of their instances.
+
+The credentials in the config parameter file are not updated. Update the file
+after the rotation if it is used to revive the database.
+
+Examples:
+  # Rotate the AWS keys with config file
+  vcluster set_communal_storage_credentials \
+    --credentials AWSAuth=new_key_id:new_secret_key \
+    --config /opt/vertica/config/vertica_cluster.yaml
+
+  # Switch to the IAM role with user input
+  vcluster set_communal_storage_credentials --use-iam-role \
+    --db-name test_db --hosts 10.20.30.40,10.20.30.41,10.20.30.42 \
+    --communal-storage-location s3://bucket/test_db --password testpassword
+`,
+		[]string{dbNameFlag, hostsFlag, ipv6Flag, eonModeFlag, configFlag, passwordFlag,
+			communalStorageLocationFlag, configParamFlag, outputFileFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	cmd.MarkFlagsOneRequired("credentials", "use-iam-role")
+
+	// hide eon mode flag since we expect it to come from config file, not from user input
+	hideLocalFlags(cmd, []string{eonModeFlag})
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdSetCommunalStorageCredentials) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringToStringVar(
+		&c.setCredentialsOptions.Credentials,
+		"credentials",
+		map[string]string{},
+		"Comma-separated list of NAME=VALUE pairs of the new credential parameters",
+	)
+	cmd.Flags().BoolVar(
+		&c.setCredentialsOptions.UseIAMRole,
+		"use-iam-role",
+		false,
+		"Clear the AWS keys to access the communal storage with the IAM role of the nodes",
+	)
+}
+
+func (c *CmdSetCommunalStorageCredentials) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.setCredentialsOptions.DatabaseOptions)
+
+	// communal storage only exists in an Eon db so we assume the user always runs this subcommand
+	// on an Eon db. When Eon mode cannot be found in config file, we set its value to true.
+	if !viper.IsSet(eonModeKey) {
+		c.setCredentialsOptions.IsEon = true
+	}
+
+	return c.validateParse(logger)
+}
+
+func (c *CmdSetCommunalStorageCredentials) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+	err := c.getCertFilesFromCertPaths(&c.setCredentialsOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.setCredentialsOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.setDBPassword(&c.setCredentialsOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setConfigParam(&c.setCredentialsOptions.DatabaseOptions)
+}
+
+func (c *CmdSetCommunalStorageCredentials) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.setCredentialsOptions
+
+	statuses, runErr := vcc.VSetCommunalStorageCredentials(options)
+	if len(statuses) > 0 {
+		bytes, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("fail to marshal the credential parameter statuses, details %w", err)
+		}
+		c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+	}
+	if runErr != nil {
+		vcc.LogError(runErr, "failed to set the communal storage credentials", "DBName", options.DBName)
+		return runErr
+	}
+	vcc.PrintInfo("Successfully set the communal storage credentials of database %s", options.DBName)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdSetCommunalStorageCredentials
+func (c *CmdSetCommunalStorageCredentials) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.setCredentialsOptions.DatabaseOptions = *opt
+}
@@ -0,0 +1,131 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"os/user"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdSetNodeMaintenance
+ *
+ * A subcommand putting nodes under maintenance
+ * in the config file.
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdSetNodeMaintenance struct {
+	CmdBase
+	sOptions         vclusterops.DatabaseOptions
+	maintenanceHosts []string
+	reason           string
+}
+
+func makeCmdSetNodeMaintenance() *cobra.Command {
+	newCmd := &CmdSetNodeMaintenance{}
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		setNodeMaintenanceSubCmd,
+		"Put nodes under maintenance",
+		`This command puts nodes under maintenance, so that other vcluster commands
+leave them alone while someone is working on their hosts.
+
+The maintenance flag is recorded in the config file. The stop_node and
+restart_node commands skip the nodes under maintenance, and print a warning
+about them. The other commands that change the state of nodes, such as
+stop_db, remove_node, and rebalance_shards, do not skip them but print a
+warning. Use clear_node_maintenance to clear the flag.
+
+Examples:
+  # Put a node under maintenance
+  vcluster set_node_maintenance --maintenance-hosts 10.20.30.41 \
+    --reason "replacing a disk" --config /opt/vertica/config/vertica_cluster.yaml
+`,
+		[]string{configFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsRequired(cmd, maintenanceHostsFlag, "reason")
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdSetNodeMaintenance) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(
+		&c.maintenanceHosts,
+		maintenanceHostsFlag,
+		[]string{},
+		"Comma-separated list of the hosts of the nodes to put under maintenance",
+	)
+	cmd.Flags().StringVar(
+		&c.reason,
+		"reason",
+		"",
+		"Why the nodes are under maintenance",
+	)
+}
+
+func (c *CmdSetNodeMaintenance) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogArgParse(&c.argv)
+
+	return util.ParseHostList(&c.maintenanceHosts)
+}
+
+func (c *CmdSetNodeMaintenance) Run(vcc vclusterops.ClusterCommands) error {
+	dbConfig, err := readConfig()
+	if err != nil {
+		return err
+	}
+	hosts, err := util.ResolveRawHostsToAddresses(c.maintenanceHosts, dbConfig.Ipv6)
+	if err != nil {
+		return err
+	}
+
+	info := MaintenanceInfo{
+		Reason: c.reason,
+		Since:  time.Now().UTC().Format(time.RFC3339),
+	}
+	if currentUser, userErr := user.Current(); userErr == nil {
+		info.Owner = currentUser.Username
+	}
+	err = dbConfig.setMaintenance(hosts, &info)
+	if err != nil {
+		return err
+	}
+	err = dbConfig.write(dbOptions.ConfigPath, true /*forceOverwrite*/)
+	if err != nil {
+		return fmt.Errorf("fail to write the config file, details: %w", err)
+	}
+
+	vcc.PrintInfo("Successfully put the nodes on hosts %v under maintenance", hosts)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance
+func (c *CmdSetNodeMaintenance) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.sOptions = *opt
+}
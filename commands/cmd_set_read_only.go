@@ -0,0 +1,133 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdSetReadOnly
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdSetReadOnly struct {
+	setReadOnlyOptions *vclusterops.VSetClusterReadOnlyOptions
+
+	CmdBase
+}
+
+func makeCmdSetReadOnly() *cobra.Command {
+	newCmd := &CmdSetReadOnly{}
+
+	opt := vclusterops.VSetClusterReadOnlyOptionsFactory()
+	newCmd.setReadOnlyOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		setReadOnlySubCmd,
+		"Put a database in read-only mode",
+		`This command puts the main cluster of a running database in read-only mode,
+for example before maintenance or a storage migration. Use --read-only=false
+to take the database out of read-only mode.
+
+The command waits until every UP node of the main cluster reports the new mode,
+or the timeout is reached. Sandboxes are not changed.
+
+Examples:
+  # Put the database in read-only mode with config file
+  vcluster set_read_only --db-name test_db \
+    --config /opt/vertica/config/vertica_cluster.yaml
+
+  # Take the database out of read-only mode with user input
+  vcluster set_read_only --read-only=false --db-name test_db \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42 --password testpassword
+`,
+		[]string{dbNameFlag, hostsFlag, ipv6Flag, configFlag, passwordFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdSetReadOnly) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&c.setReadOnlyOptions.ReadOnly,
+		"read-only",
+		true,
+		"Whether to put the database in read-only mode, or to take it out of it",
+	)
+	cmd.Flags().IntVar(
+		&c.setReadOnlyOptions.StatePollingTimeout,
+		"timeout",
+		util.DefaultStatePollingTimeout,
+		"The timeout in seconds to wait for the nodes to report the new mode",
+	)
+}
+
+func (c *CmdSetReadOnly) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.setReadOnlyOptions.DatabaseOptions)
+
+	return c.validateParse(logger)
+}
+
+func (c *CmdSetReadOnly) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+	err := c.getCertFilesFromCertPaths(&c.setReadOnlyOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.setReadOnlyOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.setReadOnlyOptions.DatabaseOptions)
+}
+
+func (c *CmdSetReadOnly) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.setReadOnlyOptions
+
+	err := vcc.VSetClusterReadOnly(options)
+	if err != nil {
+		vcc.LogError(err, "failed to set read-only mode", "DBName", options.DBName, "ReadOnly", options.ReadOnly)
+		return err
+	}
+	if options.ReadOnly {
+		vcc.PrintInfo("Successfully put database %s in read-only mode", options.DBName)
+	} else {
+		vcc.PrintInfo("Successfully took database %s out of read-only mode", options.DBName)
+	}
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdSetReadOnly
+func (c *CmdSetReadOnly) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.setReadOnlyOptions.DatabaseOptions = *opt
+}
@@ -0,0 +1,160 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdSetTLSConfig
+ *
+ * Parses arguments for VSetTLSConfigOptions to pass down to
+ * VSetTLSConfig.
+ *
+ * Implements ClusterCommand interface
+ */
+
+type CmdSetTLSConfig struct {
+	CmdBase
+	setTLSConfigOptions *vclusterops.VSetTLSConfigOptions
+	tlsConfigName       string
+}
+
+func makeCmdSetTLSConfig() *cobra.Command {
+	// CmdSetTLSConfig
+	newCmd := &CmdSetTLSConfig{}
+	opt := vclusterops.VSetTLSConfigOptionsFactory()
+	newCmd.setTLSConfigOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		setTLSConfigSubCmd,
+		"Set the certificates or the TLS mode of a TLS configuration",
+		`This command changes a TLS configuration of the database, and then verifies
+that all of the up nodes use the new settings.
+
+The --tls-config option selects the TLS configuration to change:
+  - server: the TLS configuration of the client-server connections
+  - https: the TLS configuration of the HTTPS service
+
+The certificates must already be imported into the database. The settings that
+are not provided are left unchanged.
+
+Examples:
+  # Require clients to present a certificate signed by a trusted CA
+  vcluster set_tls_config --db-name test_db --tls-config server \
+    --certificate server_cert --ca-certificates ca_cert --tls-mode VERIFY_CA \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42
+
+  # Rotate the certificate of the HTTPS service with config file
+  vcluster set_tls_config --tls-config https --certificate new_https_cert \
+    --config /opt/vertica/config/vertica_cluster.yaml
+`,
+		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, passwordFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	// require the name of the TLS configuration
+	markFlagsRequired(cmd, tlsConfigFlag)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdSetTLSConfig) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.tlsConfigName,
+		tlsConfigFlag,
+		"",
+		"The name of the TLS configuration to change, either server or https",
+	)
+	cmd.Flags().StringVar(
+		&c.setTLSConfigOptions.TLSConfig.Certificate,
+		"certificate",
+		"",
+		"The name of the certificate that the TLS configuration uses",
+	)
+	cmd.Flags().StringSliceVar(
+		&c.setTLSConfigOptions.TLSConfig.CACertificates,
+		"ca-certificates",
+		[]string{},
+		"Comma-separated list of the names of the CA certificates that the TLS configuration trusts",
+	)
+	cmd.Flags().StringVar(
+		&c.setTLSConfigOptions.TLSConfig.TLSMode,
+		"tls-mode",
+		"",
+		"The TLS mode, one of DISABLE, ENABLE, TRY_VERIFY, VERIFY_CA and VERIFY_FULL",
+	)
+}
+
+func (c *CmdSetTLSConfig) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.setTLSConfigOptions.DatabaseOptions)
+
+	return c.validateParse(logger)
+}
+
+// all validations of the arguments should go in here
+func (c *CmdSetTLSConfig) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	c.setTLSConfigOptions.TLSConfig.Name = vclusterops.TLSConfigName(c.tlsConfigName)
+
+	err := c.getCertFilesFromCertPaths(&c.setTLSConfigOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateParseBaseOptions(&c.setTLSConfigOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.setTLSConfigOptions.DatabaseOptions)
+}
+
+func (c *CmdSetTLSConfig) Analyze(_ vlog.Printer) error {
+	return nil
+}
+
+func (c *CmdSetTLSConfig) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.setTLSConfigOptions
+
+	err := vcc.VSetTLSConfig(options)
+	if err != nil {
+		vcc.LogError(err, "failed to set the TLS configuration", "tlsConfig", c.tlsConfigName)
+		return err
+	}
+
+	vcc.PrintInfo("Successfully set TLS configuration %s", c.tlsConfigName)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdSetTLSConfig
+func (c *CmdSetTLSConfig) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.setTLSConfigOptions.DatabaseOptions = *opt
+}
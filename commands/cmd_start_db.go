@@ -37,6 +37,9 @@ type CmdStartDB struct {
 	IgnoreClusterLease  bool // Ignore the cluster lease in communal storage
 	Unsafe              bool // Start database unsafely, skipping recovery.
 	Fast                bool // Attempt fast startup database
+
+	// <node_name>=<argument> values of --start-command-override
+	rawStartCommandOverrides []string
 }
 
 func makeCmdStartDB() *cobra.Command {
@@ -111,6 +114,26 @@ func (c *CmdStartDB) setLocalFlags(cmd *cobra.Command) {
 		false,
 		"Start the database on main cluster, but don't start any of the sandboxes",
 	)
+	cmd.Flags().BoolVar(
+		&c.startDBOptions.StagedStart,
+		"staged-start",
+		false,
+		"Start the primary nodes first, and start the secondary nodes after the primary nodes are up",
+	)
+	cmd.Flags().BoolVar(
+		&c.startDBOptions.AutoReIP,
+		"auto-re-ip",
+		false,
+		"Re-ip the nodes whose addresses changed before starting the database",
+	)
+	cmd.Flags().StringArrayVar(
+		&c.rawStartCommandOverrides,
+		"start-command-override",
+		[]string{},
+		"Vertica argument to merge into the stored start command of a node, as <node_name>=<argument>, "+
+			"e.g., v_db_node0001=-D followed by \"v_db_node0001=/data/db/v_db_node0001_catalog\". "+
+			"This can be repeated, and the arguments of a node are merged in order",
+	)
 }
 
 // setHiddenFlags will set the hidden flags the command has.
@@ -191,7 +214,9 @@ func (c *CmdStartDB) validateParse(logger vlog.Printer) error {
 	if err != nil {
 		return err
 	}
-	return nil
+
+	c.startDBOptions.StartCommandOverrides, err = parseStartCommandOverrides(c.rawStartCommandOverrides)
+	return err
 }
 func filterInputHosts(options *vclusterops.VStartDatabaseOptions, dbConfig *DatabaseConfig) []string {
 	filteredHosts := []string{}
@@ -67,6 +67,11 @@ Examples:
   vcluster replication start --config /opt/vertica/config/vertica_cluster.yaml \
     --target-conn /opt/vertica/config/target_connection.yaml 
 
+  # Replicate the tables of a schema asynchronously
+  vcluster replication start --config /opt/vertica/config/vertica_cluster.yaml \
+    --target-conn /opt/vertica/config/target_connection.yaml \
+    --include-pattern 'schema1.*' --exclude-pattern 'schema1.tmp_*' --async
+
   # Replicate data from a sandbox in the source database to a target database
   # specified in the connection file.
   vcluster replication start --config /opt/vertica/config/vertica_cluster.yaml \
@@ -98,6 +103,8 @@ Examples:
 	cmd.MarkFlagsOneRequired(targetConnFlag, targetDBNameFlag)
 	cmd.MarkFlagsOneRequired(targetConnFlag, targetHostsFlag)
 
+	cmd.MarkFlagsMutuallyExclusive(tableOrSchemaNameFlag, includePatternFlag)
+
 	// hide eon mode flag since we expect it to come from config file, not from user input
 	hideLocalFlags(cmd, []string{eonModeFlag})
 	return cmd
@@ -128,6 +135,30 @@ func (c *CmdStartReplication) setLocalFlags(cmd *cobra.Command) {
 		"",
 		"The username for connecting to the target database",
 	)
+	cmd.Flags().StringVar(
+		&c.startRepOptions.TableOrSchemaName,
+		tableOrSchemaNameFlag,
+		"",
+		"The schema or table to replicate, for example schema1 or schema1.table1",
+	)
+	cmd.Flags().StringVar(
+		&c.startRepOptions.IncludePattern,
+		includePatternFlag,
+		"",
+		"The pattern of the schemas or tables to replicate, for example 'schema1.*'",
+	)
+	cmd.Flags().StringVar(
+		&c.startRepOptions.ExcludePattern,
+		excludePatternFlag,
+		"",
+		"The pattern of the schemas or tables to leave out of the include pattern",
+	)
+	cmd.Flags().BoolVar(
+		&c.startRepOptions.Async,
+		asyncFlag,
+		false,
+		"Return once the replication is started, and print its transaction ID",
+	)
 	cmd.Flags().StringVar(
 		&c.startRepOptions.SourceTLSConfig,
 		sourceTLSConfigFlag,
@@ -234,11 +265,16 @@ func (c *CmdStartReplication) Run(vcc vclusterops.ClusterCommands) error {
 
 	options := c.startRepOptions
 
-	err := vcc.VReplicateDatabase(options)
+	status, err := vcc.VReplicateDatabase(options)
 	if err != nil {
 		vcc.LogError(err, "fail to replicate to database", "targetDB", options.TargetDB)
 		return err
 	}
+	if status.Async {
+		vcc.PrintInfo("Successfully started replication to database %s, transaction ID: %d",
+			options.TargetDB, status.TransactionID)
+		return nil
+	}
 	vcc.PrintInfo("Successfully replicate to database %s", options.TargetDB)
 	return nil
 }
@@ -124,6 +124,7 @@ func (c *CmdStartSubcluster) Run(vcc vclusterops.ClusterCommands) error {
 	vcc.V(1).Info("Called method Run()")
 
 	options := c.startScOptions
+	loadStartCommandsFromConfig(&options.DatabaseOptions)
 
 	err := vcc.VStartSubcluster(options)
 	if err != nil {
@@ -91,6 +91,13 @@ func (c *CmdStopDB) setLocalFlags(cmd *cobra.Command) {
 		false,
 		"Stop the database, but don't stop any of the sandboxes",
 	)
+	cmd.Flags().BoolVar(
+		&c.stopDBOptions.GracefulDrain,
+		"graceful-drain",
+		false,
+		util.GetEonFlagMsg("Pause new connections and wait up to --drain-seconds for the user sessions to end"+
+			" before stopping the database"),
+	)
 }
 
 // setHiddenFlags will set the hidden flags the command has.
@@ -72,6 +72,12 @@ func (c *CmdStopNode) setLocalFlags(cmd *cobra.Command) {
 		[]string{},
 		"Comma-separated list of host(s) to stop",
 	)
+	cmd.Flags().BoolVar(
+		&c.stopNodeOptions.EvictFromSpread,
+		"evict-from-spread",
+		false,
+		"Remove the stopped nodes from the spread configuration once they are down",
+	)
 }
 
 func (c *CmdStopNode) Parse(inputArgv []string, logger vlog.Printer) error {
@@ -108,6 +114,7 @@ func (c *CmdStopNode) Run(vcc vclusterops.ClusterCommands) error {
 		return err
 	}
 	vcc.PrintInfo("Successfully stopped the nodes %v", c.stopNodeOptions.StopHosts)
+	saveStartCommandsToConfig(vcc, &options.DatabaseOptions)
 	return nil
 }
 
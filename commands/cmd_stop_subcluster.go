@@ -153,6 +153,7 @@ func (c *CmdStopSubcluster) Run(vcc vclusterops.ClusterCommands) error {
 		return err
 	}
 	vcc.PrintInfo("Successfully stopped subcluster %s", options.SCName)
+	saveStartCommandsToConfig(vcc, &options.DatabaseOptions)
 	return nil
 }
 
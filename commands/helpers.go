@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"golang.org/x/term"
 )
@@ -49,3 +50,20 @@ func isK8sEnvironment() bool {
 	port, portSet := os.LookupEnv(kubernetesPort)
 	return portSet && port != ""
 }
+
+// parseStartCommandOverrides parses the <node_name>=<argument> values of
+// --start-command-override into the arguments of each node, in order
+func parseStartCommandOverrides(rawOverrides []string) (map[string][]string, error) {
+	if len(rawOverrides) == 0 {
+		return nil, nil
+	}
+	overrides := make(map[string][]string)
+	for _, rawOverride := range rawOverrides {
+		nodeName, arg, found := strings.Cut(rawOverride, "=")
+		if !found || nodeName == "" {
+			return nil, fmt.Errorf("the start command override %q must be given as <node_name>=<argument>", rawOverride)
+		}
+		overrides[nodeName] = append(overrides[nodeName], arg)
+	}
+	return overrides, nil
+}
@@ -143,3 +143,17 @@ func TestStartNode(t *testing.T) {
 	err = simulateVClusterCli("vcluster restart_node --restart node1=host1 --start-hosts host1")
 	assert.ErrorContains(t, err, "[restart start-hosts] were all set")
 }
+
+func TestParseStartCommandOverrides(t *testing.T) {
+	overrides, err := parseStartCommandOverrides([]string{"v_db_node0001=-D", "v_db_node0001=/data/my db/catalog",
+		"v_db_node0002=--debug"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"v_db_node0001": {"-D", "/data/my db/catalog"},
+		"v_db_node0002": {"--debug"},
+	}, overrides)
+
+	// the overrides must be given for a node
+	_, err = parseStartCommandOverrides([]string{"-D /data/catalog"})
+	assert.ErrorContains(t, err, "must be given as <node_name>=<argument>")
+}
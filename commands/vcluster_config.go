@@ -62,6 +62,20 @@ type NodeConfig struct {
 	DataPath    string `yaml:"dataPath" mapstructure:"dataPath"`
 	DepotPath   string `yaml:"depotPath" mapstructure:"depotPath"`
 	Sandbox     string `yaml:"sandbox" mapstructure:"sandbox"` // Name of the sandbox the node belongs to
+	// startup command of the node, saved when the node is stopped
+	StartCommand []string `yaml:"startCommand,omitempty" mapstructure:"startCommand"`
+	// set when the node is under maintenance, see set_node_maintenance
+	Maintenance *MaintenanceInfo `yaml:"maintenance,omitempty" mapstructure:"maintenance"`
+}
+
+// MaintenanceInfo records that someone is working on the host of a node, so
+// that vcluster commands leave the node alone until the flag is cleared
+type MaintenanceInfo struct {
+	Reason string `yaml:"reason" mapstructure:"reason"`
+	// the time when the node was put under maintenance, in RFC 3339 format
+	Since string `yaml:"since" mapstructure:"since"`
+	// the OS user who put the node under maintenance
+	Owner string `yaml:"owner,omitempty" mapstructure:"owner"`
 }
 
 // MakeDatabaseConfig() can create an instance of DatabaseConfig
@@ -198,6 +212,13 @@ func writeConfig(vdb *vclusterops.VCoordinationDatabase, forceOverwrite bool) er
 		return err
 	}
 
+	// the maintenance flags and the saved startup commands are not in the
+	// database, so keep the ones in the current config file
+	if oldConfig, readErr := readConfig(); readErr == nil {
+		dbConfig.copyMaintenanceInfo(oldConfig)
+		dbConfig.copyStartCommands(oldConfig)
+	}
+
 	// update db config with the given database info
 	err = dbConfig.write(dbOptions.ConfigPath, forceOverwrite)
 	if err != nil {
@@ -326,3 +347,155 @@ func (c *DatabaseConfig) getPathPrefixes() (catalogPrefix string,
 
 	return util.GetPathPrefix(c.Nodes[0].CatalogPath), util.GetPathPrefix(c.Nodes[0].DataPath), util.GetPathPrefix(c.Nodes[0].DepotPath)
 }
+
+// updateStartCommands saves the startup commands, keyed by node name, into
+// the nodes of the config. It returns true if any node is updated.
+func (c *DatabaseConfig) updateStartCommands(startupCommands map[string][]string) bool {
+	updated := false
+	for _, n := range c.Nodes {
+		if startCommand, ok := startupCommands[n.Name]; ok {
+			n.StartCommand = startCommand
+			updated = true
+		}
+	}
+	return updated
+}
+
+// copyStartCommands copies the saved startup commands of the nodes, matched by
+// name, from another config. The command of a node that was removed, or whose
+// address changed, is dropped, as it would start the node with stale arguments.
+func (c *DatabaseConfig) copyStartCommands(other *DatabaseConfig) {
+	oldNodes := make(map[string]*NodeConfig)
+	for _, n := range other.Nodes {
+		oldNodes[n.Name] = n
+	}
+	for _, n := range c.Nodes {
+		if oldNode, ok := oldNodes[n.Name]; ok && oldNode.Address == n.Address {
+			n.StartCommand = oldNode.StartCommand
+		}
+	}
+}
+
+// getStartCommands returns the startup commands saved in the config, keyed
+// by node name
+func (c *DatabaseConfig) getStartCommands() map[string][]string {
+	startupCommands := make(map[string][]string)
+	for _, n := range c.Nodes {
+		if len(n.StartCommand) > 0 {
+			startupCommands[n.Name] = n.StartCommand
+		}
+	}
+	return startupCommands
+}
+
+// setMaintenance puts the nodes on the given hosts under maintenance. It
+// returns an error if any of the hosts is not in the config.
+func (c *DatabaseConfig) setMaintenance(hosts []string, info *MaintenanceInfo) error {
+	return c.updateMaintenance(hosts, info)
+}
+
+// clearMaintenance clears the maintenance flag of the nodes on the given
+// hosts. It returns an error if any of the hosts is not in the config.
+func (c *DatabaseConfig) clearMaintenance(hosts []string) error {
+	return c.updateMaintenance(hosts, nil)
+}
+
+func (c *DatabaseConfig) updateMaintenance(hosts []string, info *MaintenanceInfo) error {
+	missingHosts := util.SliceDiff(hosts, c.getHosts())
+	if len(missingHosts) > 0 {
+		return fmt.Errorf("hosts %v are not in the database", missingHosts)
+	}
+	for _, n := range c.Nodes {
+		if util.StringInArray(n.Address, hosts) {
+			n.Maintenance = info
+		}
+	}
+	return nil
+}
+
+// getMaintenanceNodes returns the nodes that are under maintenance
+func (c *DatabaseConfig) getMaintenanceNodes() []*NodeConfig {
+	var nodes []*NodeConfig
+	for _, n := range c.Nodes {
+		if n.Maintenance != nil {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// copyMaintenanceInfo copies the maintenance flags of the nodes, matched by
+// name, from another config
+func (c *DatabaseConfig) copyMaintenanceInfo(other *DatabaseConfig) {
+	maintenanceInfo := make(map[string]*MaintenanceInfo)
+	for _, n := range other.getMaintenanceNodes() {
+		maintenanceInfo[n.Name] = n.Maintenance
+	}
+	for _, n := range c.Nodes {
+		if info, ok := maintenanceInfo[n.Name]; ok {
+			n.Maintenance = info
+		}
+	}
+}
+
+// excludeMaintenanceNodes warns about the nodes under maintenance, and
+// excludes them from the target hosts. It is only called for the commands
+// that honor vclusterops.TargetSelector, see isTargetSelectorCmd.
+func excludeMaintenanceNodes(vcc vclusterops.ClusterCommands, options *vclusterops.DatabaseOptions) {
+	dbConfig, err := readConfig()
+	if err != nil {
+		return
+	}
+	for _, n := range dbConfig.getMaintenanceNodes() {
+		vcc.PrintWarning("node %s on host %s is under maintenance since %s (%s), it will not be targeted",
+			n.Name, n.Address, n.Maintenance.Since, n.Maintenance.Reason)
+		options.TargetSelector.ExcludedHosts = append(options.TargetSelector.ExcludedHosts, n.Address)
+	}
+}
+
+// warnMaintenanceNodes warns about the nodes under maintenance that the
+// command may touch anyway. It is called for the commands that do not honor
+// vclusterops.TargetSelector, see isNodeTargetingCmd.
+func warnMaintenanceNodes(vcc vclusterops.ClusterCommands) {
+	dbConfig, err := readConfig()
+	if err != nil {
+		return
+	}
+	for _, n := range dbConfig.getMaintenanceNodes() {
+		vcc.PrintWarning("node %s on host %s is under maintenance since %s (%s), this command does not skip it",
+			n.Name, n.Address, n.Maintenance.Since, n.Maintenance.Reason)
+	}
+}
+
+// saveStartCommandsToConfig persists the startup commands of the stopped
+// nodes into the config file, so that a later restart can use them even if
+// no UP node remains to serve them. Failing to save them does not fail the
+// command.
+func saveStartCommandsToConfig(vcc vclusterops.ClusterCommands, options *vclusterops.DatabaseOptions) {
+	if len(options.StartupCommands) == 0 {
+		return
+	}
+	dbConfig, err := readConfig()
+	if err != nil {
+		vcc.PrintWarning("fail to read config file, skipping saving the startup commands, details: %v", err)
+		return
+	}
+	if !dbConfig.updateStartCommands(options.StartupCommands) {
+		return
+	}
+	err = dbConfig.write(options.ConfigPath, true /*forceOverwrite*/)
+	if err != nil {
+		vcc.PrintWarning("fail to save the startup commands to the config file, details: %v", err)
+	}
+}
+
+// loadStartCommandsFromConfig passes the startup commands saved by stop_node
+// or stop_subcluster to a start command, which uses them when no UP node can
+// serve them
+func loadStartCommandsFromConfig(options *vclusterops.DatabaseOptions) {
+	dbConfig, err := readConfig()
+	if err != nil {
+		return
+	}
+	options.StartupCommands = dbConfig.getStartCommands()
+}
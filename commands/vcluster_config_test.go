@@ -0,0 +1,95 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops"
+)
+
+func TestNodeMaintenance(t *testing.T) {
+	dbConfig := MakeDatabaseConfig()
+	dbConfig.Nodes = []*NodeConfig{
+		{Name: "v_test_db_node0001", Address: "192.168.1.101"},
+		{Name: "v_test_db_node0002", Address: "192.168.1.102"},
+	}
+	info := MaintenanceInfo{Reason: "replacing a disk", Since: "2024-05-01T10:00:00Z"}
+
+	err := dbConfig.setMaintenance([]string{"192.168.1.102"}, &info)
+	assert.NoError(t, err)
+	nodes := dbConfig.getMaintenanceNodes()
+	assert.Len(t, nodes, 1)
+	assert.Equal(t, "v_test_db_node0002", nodes[0].Name)
+
+	// unknown hosts are rejected
+	err = dbConfig.setMaintenance([]string{"192.168.1.103"}, &info)
+	assert.ErrorContains(t, err, "192.168.1.103")
+
+	// the flags survive a config regenerated from the database
+	newConfig := MakeDatabaseConfig()
+	newConfig.Nodes = []*NodeConfig{
+		{Name: "v_test_db_node0001", Address: "192.168.1.101"},
+		{Name: "v_test_db_node0002", Address: "192.168.1.102"},
+	}
+	newConfig.copyMaintenanceInfo(&dbConfig)
+	assert.Equal(t, &info, newConfig.Nodes[1].Maintenance)
+	assert.Nil(t, newConfig.Nodes[0].Maintenance)
+
+	err = dbConfig.clearMaintenance([]string{"192.168.1.102"})
+	assert.NoError(t, err)
+	assert.Empty(t, dbConfig.getMaintenanceNodes())
+
+	// only the commands that honor the target selector skip the nodes under maintenance
+	assert.True(t, isTargetSelectorCmd(stopNodeCmd))
+	assert.True(t, isTargetSelectorCmd(restartNodeSubCmd))
+	assert.False(t, isTargetSelectorCmd(stopDBSubCmd))
+	// the other commands that change the state of nodes warn about them
+	assert.False(t, isNodeTargetingCmd(restartNodeSubCmd))
+	for _, calledAs := range []string{stopDBSubCmd, removeNodeSubCmd, rebalanceShardsSubCmd, stopSCSubCmd} {
+		assert.True(t, isNodeTargetingCmd(calledAs))
+	}
+	assert.False(t, isNodeTargetingCmd(listAllNodesSubCmd))
+}
+
+func TestCopyStartCommands(t *testing.T) {
+	oldConfig := MakeDatabaseConfig()
+	oldConfig.Nodes = []*NodeConfig{
+		{Name: "v_test_db_node0001", Address: "192.168.1.101", StartCommand: []string{"/opt/vertica/bin/vertica", "-h", "192.168.1.101"}},
+		{Name: "v_test_db_node0002", Address: "192.168.1.102", StartCommand: []string{"/opt/vertica/bin/vertica", "-h", "192.168.1.102"}},
+		{Name: "v_test_db_node0003", Address: "192.168.1.103", StartCommand: []string{"/opt/vertica/bin/vertica", "-h", "192.168.1.103"}},
+	}
+
+	// node0002 got a new address, and node0003 was removed
+	newConfig := MakeDatabaseConfig()
+	newConfig.Nodes = []*NodeConfig{
+		{Name: "v_test_db_node0001", Address: "192.168.1.101"},
+		{Name: "v_test_db_node0002", Address: "192.168.1.202"},
+	}
+	newConfig.copyStartCommands(&oldConfig)
+	assert.Equal(t, map[string][]string{"v_test_db_node0001": oldConfig.Nodes[0].StartCommand}, newConfig.getStartCommands())
+
+	// re_ip clears the startup commands of the re-IPed nodes
+	c := CmdReIP{}
+	reIPOptions := vclusterops.VReIPFactory()
+	c.reIPOptions = &reIPOptions
+	c.reIPOptions.ReIPList = []vclusterops.ReIPInfo{{NodeName: "v_test_db_node0002", TargetAddress: "192.168.1.202"}}
+	c.UpdateConfig(&oldConfig)
+	assert.Equal(t, "192.168.1.202", oldConfig.Nodes[1].Address)
+	assert.Empty(t, oldConfig.Nodes[1].StartCommand)
+	assert.NotEmpty(t, oldConfig.Nodes[0].StartCommand)
+}
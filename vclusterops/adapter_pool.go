@@ -61,11 +61,16 @@ func makeAdapterPool(logger vlog.Printer) adapterPool {
 }
 
 type adapterToRequest struct {
+	host    string
 	adapter adapter
 	request hostHTTPRequest
 }
 
-func (pool *adapterPool) sendRequest(httpRequest *clusterHTTPRequest, spinner *yacspin.Spinner) error {
+// sendRequest sends the requests to the hosts concurrently, to at most
+// maxConcurrentHosts hosts at a time if it is positive. The requests that
+// fail with a transient error are sent again as the retry policy allows.
+func (pool *adapterPool) sendRequest(ctx context.Context, httpRequest *clusterHTTPRequest,
+	spinner *yacspin.Spinner, retryPolicy *RetryPolicy, maxConcurrentHosts int) error {
 	// build a collection of adapter to request
 	// we need this step as a host may not be in the pool
 	// in that case, we should not proceed
@@ -76,45 +81,102 @@ func (pool *adapterPool) sendRequest(httpRequest *clusterHTTPRequest, spinner *y
 		if !ok {
 			return fmt.Errorf("host %s is not found in the adapter pool", host)
 		}
-		ar := adapterToRequest{adapter: adpt, request: request}
+		ar := adapterToRequest{host: host, adapter: adpt, request: request}
 		adapterToRequestCollection = append(adapterToRequestCollection, ar)
 	}
 
-	hostCount := len(adapterToRequestCollection)
-
-	// result channel to collect result from each host
-	resultChannel := make(chan hostHTTPResult, hostCount)
-
 	// only track the progress of HTTP requests for vcluster CLI
 	if pool.logger.ForCli {
 		// use context to check whether a step has completed
-		ctx, cancelCtx := context.WithCancel(context.Background())
-		go progressCheck(ctx, httpRequest.Name, pool.logger, spinner)
+		progressCtx, cancelCtx := context.WithCancel(ctx)
+		go progressCheck(progressCtx, httpRequest.Name, pool.logger, spinner)
 		// cancel the progress check context when the result channel is closed
 		defer cancelCtx()
 	}
 
-	for i := 0; i < len(adapterToRequestCollection); i++ {
-		ar := adapterToRequestCollection[i]
-		// send request to the hosts
-		// each goroutine will handle one request for one host
-		request := ar.request
-		go ar.adapter.sendRequest(&request, resultChannel)
+	httpRequest.ResultCollection = make(map[string]hostHTTPResult)
+	if httpRequest.SendCount == nil {
+		httpRequest.SendCount = make(map[string]int)
+	}
+	pool.sendToHosts(ctx, httpRequest, adapterToRequestCollection, maxConcurrentHosts)
+	if !retryPolicy.isEnabled() {
+		return nil
+	}
+
+	for retry := 1; retry < retryPolicy.MaxAttempts; retry++ {
+		var retryCollection []adapterToRequest
+		for _, ar := range adapterToRequestCollection {
+			result := httpRequest.ResultCollection[ar.host]
+			if retryPolicy.isRetryable(&result) {
+				retryCollection = append(retryCollection, ar)
+			}
+		}
+		if len(retryCollection) == 0 {
+			return nil
+		}
+
+		backoff := retryPolicy.getBackoff(retry)
+		pool.logger.Info("retry the requests that failed with a transient error",
+			"request", httpRequest.Name, "hosts", len(retryCollection), "retry", retry, "backoff", backoff)
+		select {
+		case <-ctx.Done():
+			// keep the results of the last attempt
+			return nil
+		case <-time.After(backoff):
+		}
+		pool.sendToHosts(ctx, httpRequest, retryCollection, maxConcurrentHosts)
+		adapterToRequestCollection = retryCollection
+	}
+
+	return nil
+}
+
+// sendToHosts sends the requests concurrently, and saves the results into
+// httpRequest. A positive maxConcurrentHosts limits the requests in flight, so
+// that a large cluster does not overwhelm the local sockets and the agents.
+func (pool *adapterPool) sendToHosts(ctx context.Context, httpRequest *clusterHTTPRequest,
+	adapterToRequestCollection []adapterToRequest, maxConcurrentHosts int) {
+	hostCount := len(adapterToRequestCollection)
+
+	// result channel to collect result from each host
+	resultChannel := make(chan hostHTTPResult, hostCount)
+
+	concurrency := hostCount
+	if maxConcurrentHosts > 0 && maxConcurrentHosts < hostCount {
+		concurrency = maxConcurrentHosts
 	}
+	// a request holds a slot until its result is sent
+	slots := make(chan struct{}, concurrency)
+
+	go func() {
+		for i := 0; i < len(adapterToRequestCollection); i++ {
+			ar := adapterToRequestCollection[i]
+			slots <- struct{}{}
+			// send request to the hosts
+			// each goroutine will handle one request for one host
+			request := ar.request
+			go func() {
+				defer func() { <-slots }()
+				ar.adapter.sendRequest(ctx, &request, resultChannel)
+			}()
+		}
+	}()
 
 	// handle results
 	// we expect to receive the same number of results from the channel as the number of hosts
 	// before proceeding to the next steps
-	httpRequest.ResultCollection = make(map[string]hostHTTPResult)
 	for i := 0; i < hostCount; i++ {
 		result, ok := <-resultChannel
 		if ok {
+			result.retryCount = httpRequest.SendCount[result.host]
+			httpRequest.SendCount[result.host]++
 			httpRequest.ResultCollection[result.host] = result
+			pool.logger.Info("request completed", "op", httpRequest.Name, "host", result.host,
+				"statusCode", result.statusCode, "duration", result.duration, "retryCount", result.retryCount,
+				"idempotencyKey", httpRequest.RequestCollection[result.host].IdempotencyKey)
 		}
 	}
 	close(resultChannel)
-
-	return nil
 }
 
 // progressCheck checks whether a step (operation) has been completed.
@@ -36,8 +36,14 @@ type VAddNodeOptions struct {
 	DepotSize string
 	// Skip rebalance shards if true
 	SkipRebalanceShards *bool
+	// Rebalance the data across the nodes of an Enterprise database once the
+	// new nodes are UP. This is ignored in Eon mode, where the shards of the
+	// subcluster are rebalanced unless SkipRebalanceShards is set.
+	RebalanceCluster bool
 	// Use force remove if true
 	ForceRemoval bool
+	// move the directories removed by ForceRemoval to a trash location
+	Trash TrashOptions
 	// If the path is set, the NMA will store the Vertica start command at the path
 	// instead of executing it. This is useful in containerized environments where
 	// you may not want to have both the NMA and Vertica server in the same container.
@@ -62,6 +68,17 @@ func (options *VAddNodeOptions) setDefaultValues() {
 	options.SkipRebalanceShards = new(bool)
 }
 
+// SetSkipRebalanceShards sets whether rebalancing the shards is skipped
+func (options *VAddNodeOptions) SetSkipRebalanceShards(skip bool) {
+	options.SkipRebalanceShards = &skip
+}
+
+// isSkipRebalanceShards treats an unset SkipRebalanceShards as false, so
+// that options built without the factory do not panic
+func (options *VAddNodeOptions) isSkipRebalanceShards() bool {
+	return options.SkipRebalanceShards != nil && *options.SkipRebalanceShards
+}
+
 func (options *VAddNodeOptions) validateEonOptions() error {
 	if options.DepotPrefix != "" {
 		return util.ValidateRequiredAbsPath(options.DepotPrefix, "depot path")
@@ -78,6 +95,9 @@ func (options *VAddNodeOptions) validateRequiredOptions(logger vlog.Printer) err
 }
 
 func (options *VAddNodeOptions) validateExtraOptions() error {
+	if err := options.Trash.validate(); err != nil {
+		return err
+	}
 	// data prefix
 	if options.DataPrefix != "" {
 		return util.ValidateRequiredAbsPath(options.DataPrefix, "data path")
@@ -107,7 +127,7 @@ func (options *VAddNodeOptions) validateParseOptions(logger vlog.Printer) error
 
 // analyzeOptions will modify some options based on what is chosen
 func (options *VAddNodeOptions) analyzeOptions() (err error) {
-	options.NewHosts, err = util.ResolveRawHostsToAddresses(options.NewHosts, options.IPv6)
+	options.NewHosts, err = options.resolveRawHosts(options.NewHosts)
 	if err != nil {
 		return err
 	}
@@ -115,7 +135,7 @@ func (options *VAddNodeOptions) analyzeOptions() (err error) {
 	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
 	// resolve RawHosts to be IP addresses
 	if len(options.RawHosts) > 0 {
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -135,7 +155,9 @@ func (options *VAddNodeOptions) validateAnalyzeOptions(logger vlog.Printer) erro
 }
 
 // VAddNode adds one or more nodes to an existing database.
-// It returns a VCoordinationDatabase that contains catalog information and any error encountered.
+// It returns a VCoordinationDatabase that contains catalog information, with
+// the node states read again from the database once the new nodes are UP, and
+// any error encountered.
 func (vcc VClusterCommands) VAddNode(options *VAddNodeOptions) (VCoordinationDatabase, error) {
 	vdb := makeVCoordinationDatabase()
 
@@ -191,11 +213,24 @@ func (vcc VClusterCommands) VAddNode(options *VAddNodeOptions) (VCoordinationDat
 		return vdb, fmt.Errorf("fail to produce add node instructions, %w", err)
 	}
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	if runError := clusterOpEngine.run(vcc.Log); runError != nil {
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	if runError := clusterOpEngine.run(vcc.Context(), vcc.Log); runError != nil {
 		return vdb, fmt.Errorf("fail to complete add node operation, %w", runError)
 	}
+
+	// get the states of the new nodes from the database
+	updatedVDB := makeVCoordinationDatabase()
+	err = vcc.getVDBFromRunningDB(&updatedVDB, &options.DatabaseOptions)
+	if err != nil {
+		vcc.Log.PrintWarning("fail to read the database information after adding the nodes, details: %v", err)
+		return vdb, nil
+	}
+	for host, vnode := range vdb.HostNodeMap {
+		if updatedNode, ok := updatedVDB.HostNodeMap[host]; ok {
+			vnode.Name = updatedNode.Name
+			vnode.State = updatedNode.State
+		}
+	}
 	return vdb, nil
 }
 
@@ -300,9 +335,8 @@ func (vcc VClusterCommands) trimNodesInCatalog(vdb *VCoordinationDatabase,
 		instructions = append(instructions, &httpsDropNodeOp)
 	}
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	err := clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if err != nil {
 		vcc.Log.Error(err, "fail to trim nodes from catalog, %v")
 		return err
@@ -333,7 +367,8 @@ func (vcc VClusterCommands) trimNodesInCatalog(vdb *VCoordinationDatabase,
 //   - Poll node startup
 //   - Create depot on the new node (Eon mode only)
 //   - Sync catalog
-//   - Rebalance shards on subcluster (Eon mode only)
+//   - Rebalance shards on subcluster, and wait for the subscriptions to be ACTIVE (Eon mode only)
+//   - Rebalance the data across the nodes (Enterprise mode only, optional)
 func (vcc VClusterCommands) produceAddNodeInstructions(vdb *VCoordinationDatabase,
 	options *VAddNodeOptions) ([]clusterOp, error) {
 	var instructions []clusterOp
@@ -383,6 +418,10 @@ func (vcc VClusterCommands) produceAddNodeInstructions(vdb *VCoordinationDatabas
 	if err != nil {
 		return instructions, err
 	}
+	instructions, err = options.Trash.addTrashInstructions(instructions, vdb.Name, &nmaPrepareDirectoriesOp)
+	if err != nil {
+		return instructions, err
+	}
 	instructions = append(instructions,
 		&nmaPrepareDirectoriesOp,
 		&nmaNetworkProfileOp,
@@ -431,14 +470,32 @@ func (vcc VClusterCommands) prepareAdditionalEonInstructions(vdb *VCoordinationD
 			return instructions, err
 		}
 		instructions = append(instructions, &httpsSyncCatalogOp)
-		if !*options.SkipRebalanceShards {
+		if !options.isSkipRebalanceShards() {
 			httpsRBSCShardsOp, err := makeHTTPSRebalanceSubclusterShardsOp(
 				initiatorHost, usePassword, username, options.Password, options.SCName)
 			if err != nil {
 				return instructions, err
 			}
-			instructions = append(instructions, &httpsRBSCShardsOp)
+			// the new nodes serve queries once their subscriptions are ACTIVE
+			var nodesToPollSubs []string
+			for _, host := range vdb.HostList {
+				if vnode := vdb.HostNodeMap[host]; vnode.Sandbox == util.MainClusterSandbox {
+					nodesToPollSubs = append(nodesToPollSubs, vnode.Name)
+				}
+			}
+			httpsPollSubscriptionStateOp, err := makeHTTPSPollSubscriptionStateOp(initiatorHost,
+				usePassword, username, options.Password, &nodesToPollSubs)
+			if err != nil {
+				return instructions, err
+			}
+			instructions = append(instructions, &httpsRBSCShardsOp, &httpsPollSubscriptionStateOp)
+		}
+	} else if options.RebalanceCluster {
+		httpsRBCOp, err := makeHTTPSRebalanceClusterOp(initiatorHost, usePassword, username, options.Password)
+		if err != nil {
+			return instructions, err
 		}
+		instructions = append(instructions, &httpsRBCOp)
 	}
 
 	return instructions, nil
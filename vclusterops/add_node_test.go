@@ -0,0 +1,65 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getOpNames(instructions []clusterOp) []string {
+	var names []string
+	for _, op := range instructions {
+		names = append(names, op.getName())
+	}
+	return names
+}
+
+func TestAddNodeRebalanceInstructions(t *testing.T) {
+	options := VAddNodeOptionsFactory()
+	options.SCName = "sc1"
+	options.UserName = "dbadmin"
+	vdb := makeVCoordinationDatabase()
+	vdb.HostList = []string{"192.168.1.101", "192.168.1.102"}
+	vdb.HostNodeMap = vHostNodeMap{
+		"192.168.1.101": {Name: "v_db_node0001"},
+		"192.168.1.102": {Name: "v_db_node0002"},
+	}
+	initiator := []string{"192.168.1.101"}
+	newHosts := []string{"192.168.1.102"}
+
+	// the shards are rebalanced in Eon mode, and the subscriptions are polled
+	vdb.IsEon = true
+	instructions, err := VClusterCommands{}.prepareAdditionalEonInstructions(&vdb, &options, nil,
+		options.UserName, false, initiator, newHosts)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"HTTPSSyncCatalogOp", "HTTPSRebalanceSubclusterShardsOp", "HTTPSPollSubscriptionStateOp"},
+		getOpNames(instructions))
+
+	// the data of an Enterprise database is only rebalanced on request
+	vdb.IsEon = false
+	instructions, err = VClusterCommands{}.prepareAdditionalEonInstructions(&vdb, &options, nil,
+		options.UserName, false, initiator, newHosts)
+	assert.NoError(t, err)
+	assert.Empty(t, instructions)
+
+	options.RebalanceCluster = true
+	instructions, err = VClusterCommands{}.prepareAdditionalEonInstructions(&vdb, &options, nil,
+		options.UserName, false, initiator, newHosts)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"HTTPSRebalanceClusterOp"}, getOpNames(instructions))
+}
@@ -70,6 +70,13 @@ func (options *VAddSubclusterOptions) setDefaultValues() {
 	options.ControlSetSize = util.DefaultControlSetSize
 }
 
+// SetPassword sets the password of the database, which is also used to add
+// the nodes of the subcluster
+func (options *VAddSubclusterOptions) SetPassword(password string) {
+	options.DatabaseOptions.SetPassword(password)
+	options.VAddNodeOptions.SetPassword(password)
+}
+
 func (options *VAddSubclusterOptions) validateRequiredOptions(logger vlog.Printer) error {
 	err := options.validateBaseOptions(commandAddSubcluster, logger)
 	if err != nil {
@@ -155,7 +162,7 @@ func (options *VAddSubclusterOptions) analyzeOptions() (err error) {
 	// we analyze hostnames when it is set in user input, otherwise we use hosts in yaml config
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -163,7 +170,7 @@ func (options *VAddSubclusterOptions) analyzeOptions() (err error) {
 
 	// resolve SCRawHosts to be IP addresses
 	if len(options.SCRawHosts) > 0 {
-		options.SCHosts, err = util.ResolveRawHostsToAddresses(options.SCRawHosts, options.IPv6)
+		options.SCHosts, err = options.resolveRawHosts(options.SCRawHosts)
 		if err != nil {
 			return err
 		}
@@ -207,11 +214,10 @@ func (vcc VClusterCommands) VAddSubcluster(options *VAddSubclusterOptions) error
 	}
 
 	// Create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to add subcluster %s, %w", options.SCName, runError)
 	}
@@ -0,0 +1,124 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+)
+
+// NetworkName names a network that the nodes of a multi-homed cluster have an address on
+type NetworkName string
+
+const (
+	// ManagementNetwork is the network of the node management agents and
+	// the HTTPS service by default
+	ManagementNetwork NetworkName = "management"
+	// DataNetwork is the network of the spread and data traffic by default
+	DataNetwork NetworkName = "data"
+	// PublicNetwork is the network that the clients connect through
+	PublicNetwork NetworkName = "public"
+)
+
+// AddressBook keeps the addresses of the nodes of a multi-homed cluster, and
+// which network each kind of traffic uses. The addresses of the nodes on the
+// spread network are the ones in the catalog, which identify the hosts in
+// the options and in VCoordinationDatabase. The requests to the node
+// management agents and to the HTTPS service are sent to the addresses of
+// the nodes on their networks instead.
+type AddressBook struct {
+	// the addresses of the nodes, keyed by node name, then by network
+	Nodes map[string]map[NetworkName]string
+	// the network of the node management agents, ManagementNetwork if empty
+	NMANetwork NetworkName
+	// the network of the HTTPS service, ManagementNetwork if empty
+	HTTPSNetwork NetworkName
+	// the network of the spread traffic, DataNetwork if empty
+	SpreadNetwork NetworkName
+}
+
+func (book *AddressBook) getNMANetwork() NetworkName {
+	if book.NMANetwork == "" {
+		return ManagementNetwork
+	}
+	return book.NMANetwork
+}
+
+func (book *AddressBook) getHTTPSNetwork() NetworkName {
+	if book.HTTPSNetwork == "" {
+		return ManagementNetwork
+	}
+	return book.HTTPSNetwork
+}
+
+func (book *AddressBook) getSpreadNetwork() NetworkName {
+	if book.SpreadNetwork == "" {
+		return DataNetwork
+	}
+	return book.SpreadNetwork
+}
+
+func (book *AddressBook) validate() error {
+	if book == nil {
+		return nil
+	}
+	spreadNetwork := book.getSpreadNetwork()
+	spreadAddresses := make(map[string]string)
+	for nodeName, addresses := range book.Nodes {
+		address, ok := addresses[spreadNetwork]
+		if !ok || address == "" {
+			return fmt.Errorf("node %s has no address on the %s network in the address book", nodeName, spreadNetwork)
+		}
+		if otherNodeName, found := spreadAddresses[address]; found {
+			return fmt.Errorf("nodes %s and %s have the same address %s on the %s network in the address book",
+				otherNodeName, nodeName, address, spreadNetwork)
+		}
+		spreadAddresses[address] = nodeName
+	}
+	return nil
+}
+
+// GetNodeAddress returns the address of a node on a network, and whether it has one
+func (book *AddressBook) GetNodeAddress(nodeName string, network NetworkName) (string, bool) {
+	if book == nil {
+		return "", false
+	}
+	address, ok := book.Nodes[nodeName][network]
+	return address, ok && address != ""
+}
+
+// route returns the address that the requests to the agent, or to the HTTPS
+// service, of host are sent to. host is the address of a node on the spread
+// network. It returns false if the address book does not have the node, or
+// the node has no address on the network of the service.
+func (book *AddressBook) route(host string, isNMA bool) (string, bool) {
+	if book == nil {
+		return "", false
+	}
+	network := book.getHTTPSNetwork()
+	if isNMA {
+		network = book.getNMANetwork()
+	}
+	spreadNetwork := book.getSpreadNetwork()
+	if network == spreadNetwork {
+		return "", false
+	}
+	for nodeName, addresses := range book.Nodes {
+		if addresses[spreadNetwork] == host {
+			return book.GetNodeAddress(nodeName, network)
+		}
+	}
+	return "", false
+}
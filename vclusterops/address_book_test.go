@@ -0,0 +1,80 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTestAddressBook() *AddressBook {
+	return &AddressBook{Nodes: map[string]map[NetworkName]string{
+		"v_test_db_node0001": {DataNetwork: "10.20.0.1", ManagementNetwork: "192.168.1.101", PublicNetwork: "203.0.113.1"},
+		"v_test_db_node0002": {DataNetwork: "10.20.0.2", ManagementNetwork: "192.168.1.102"},
+		"v_test_db_node0003": {DataNetwork: "10.20.0.3"},
+	}}
+}
+
+func TestAddressBookValidation(t *testing.T) {
+	var book *AddressBook
+	assert.NoError(t, book.validate())
+	book = makeTestAddressBook()
+	assert.NoError(t, book.validate())
+
+	book.Nodes["v_test_db_node0004"] = map[NetworkName]string{ManagementNetwork: "192.168.1.104"}
+	assert.ErrorContains(t, book.validate(), "node v_test_db_node0004 has no address on the data network")
+	book.Nodes["v_test_db_node0004"][DataNetwork] = "10.20.0.3"
+	assert.ErrorContains(t, book.validate(), "have the same address 10.20.0.3 on the data network")
+}
+
+func TestAddressBookRoute(t *testing.T) {
+	book := makeTestAddressBook()
+	address, ok := book.route("10.20.0.1", true)
+	assert.True(t, ok)
+	assert.Equal(t, "192.168.1.101", address)
+	// a node without an address on the network of the service, or an unknown host, is not routed
+	_, ok = book.route("10.20.0.3", true)
+	assert.False(t, ok)
+	_, ok = book.route("10.20.0.9", true)
+	assert.False(t, ok)
+
+	// the HTTPS service can be on another network than the agents
+	book.HTTPSNetwork = PublicNetwork
+	address, ok = book.route("10.20.0.1", false)
+	assert.True(t, ok)
+	assert.Equal(t, "203.0.113.1", address)
+	address, ok = book.route("10.20.0.1", true)
+	assert.True(t, ok)
+	assert.Equal(t, "192.168.1.101", address)
+
+	// the requests to the agents and the HTTPS service go through the address book
+	cache := makeTransportCache(&TransportPolicy{}, &ProxyPolicy{}, nil)
+	cache.addressBook = book
+	cache.hostnames = map[string]string{"10.20.0.1": "node1.example.com", "10.20.0.3": "node3.example.com"}
+	assert.Equal(t, "https://192.168.1.101:5554", cache.baseURL("10.20.0.1", nmaPort, false))
+	assert.Equal(t, "https://203.0.113.1:8443", cache.baseURL("10.20.0.1", httpsPort, false))
+	assert.Equal(t, "https://node3.example.com:5554", cache.baseURL("10.20.0.3", nmaPort, false))
+}
+
+func TestVDBNodeAddress(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vnode := VCoordinationNode{Name: "v_test_db_node0001", Address: "10.20.0.1"}
+	assert.Equal(t, "10.20.0.1", vdb.GetNodeAddress(&vnode, PublicNetwork))
+	vdb.AddressBook = makeTestAddressBook()
+	assert.Equal(t, "203.0.113.1", vdb.GetNodeAddress(&vnode, PublicNetwork))
+	assert.Equal(t, vdb.AddressBook, vdb.copy(nil).AddressBook)
+}
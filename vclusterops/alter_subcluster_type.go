@@ -73,7 +73,7 @@ func (options *VAlterSubclusterTypeOptions) validateParseOptions(logger vlog.Pri
 	}
 
 	// need to provide a password or key and certs
-	if options.Password == nil && (options.Cert == "" || options.Key == "") {
+	if options.Password == nil && !options.hasCertsInOptions() {
 		// validate key and cert files in local file system
 		_, err = getCertFilePaths()
 		if err != nil {
@@ -102,7 +102,7 @@ func (options *VAlterSubclusterTypeOptions) validateParseOptions(logger vlog.Pri
 func (options *VAlterSubclusterTypeOptions) analyzeOptions() (err error) {
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -138,6 +138,12 @@ func (vcc VClusterCommands) VAlterSubclusterType(options *VAlterSubclusterTypeOp
 		return err
 	}
 
+	// the primary nodes of the cluster must keep a quorum after the change
+	err = checkAlterSubclusterTypeQuorum(&vdb, options.SCName, options.Sandbox, options.SCType)
+	if err != nil {
+		return err
+	}
+
 	// produce alter subcluster type instructions
 	instructions, err := vcc.produceAlterSubclusterTypeInstructions(options, &vdb)
 	if err != nil {
@@ -145,11 +151,10 @@ func (vcc VClusterCommands) VAlterSubclusterType(options *VAlterSubclusterTypeOp
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		if options.SCType == Secondary {
 			return fmt.Errorf("fail to promote subcluster: %w", runError)
@@ -162,6 +167,48 @@ func (vcc VClusterCommands) VAlterSubclusterType(options *VAlterSubclusterTypeOp
 	return nil
 }
 
+// checkAlterSubclusterTypeQuorum returns an error if the primary nodes of the
+// cluster that contains the subcluster would not keep a quorum once the
+// subcluster is promoted or demoted. scType is the current type of the
+// subcluster.
+func checkAlterSubclusterTypeQuorum(vdb *VCoordinationDatabase, scName, sandbox string,
+	scType SubclusterType) error {
+	var scNodeCount, primaryCount, upPrimaryCount int
+	for _, vnode := range vdb.HostNodeMap {
+		if vnode.Sandbox != sandbox {
+			continue
+		}
+		isPrimary := vnode.IsPrimary
+		if vnode.Subcluster == scName {
+			scNodeCount++
+			if isPrimary != (scType == Primary) {
+				return fmt.Errorf("subcluster %s is not a %s subcluster", scName, scType)
+			}
+			// the nodes of the subcluster will switch their type
+			isPrimary = !isPrimary
+		}
+		if isPrimary {
+			primaryCount++
+			if vnode.State == util.NodeUpState {
+				upPrimaryCount++
+			}
+		}
+	}
+
+	if scNodeCount == 0 {
+		return fmt.Errorf("subcluster %s does not exist or has no nodes", scName)
+	}
+	if primaryCount == 0 {
+		return fmt.Errorf("cannot demote subcluster %s: no primary node would remain", scName)
+	}
+	if upPrimaryCount*2 <= primaryCount {
+		return categorizeError(ErrQuorumLost,
+			fmt.Errorf("only %d of the %d primary nodes would be UP after altering subcluster %s, which is not a quorum",
+				upPrimaryCount, primaryCount, scName))
+	}
+	return nil
+}
+
 // The generated instructions will later perform the following operations necessary
 // for a successful alter subcluster type operation:
 //   - Promote subclusters using one of the up nodes in the main subcluster or a sandbox other than the target subcluster
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
@@ -60,3 +61,43 @@ func TestVAlterSubclusterTypeOptions_validateParseOptions(t *testing.T) {
 	err = opt.validateParseOptions(logger)
 	assert.ErrorContains(t, err, "promote or demote subclusters are only supported in Eon mode")
 }
+
+func TestCheckAlterSubclusterTypeQuorum(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	addNode := func(host, sc string, isPrimary bool, state string) {
+		vdb.HostNodeMap[host] = &VCoordinationNode{Address: host, Subcluster: sc,
+			IsPrimary: isPrimary, State: state}
+	}
+	addNode("192.168.1.101", "sc1", true, util.NodeUpState)
+	addNode("192.168.1.102", "sc1", true, util.NodeUpState)
+	addNode("192.168.1.103", "sc2", true, util.NodeUpState)
+	addNode("192.168.1.104", "sc3", false, util.NodeUpState)
+	addNode("192.168.1.105", "sc3", false, util.NodeDownState)
+	addNode("192.168.1.106", "sc4", false, util.NodeDownState)
+	addNode("192.168.1.107", "sc4", false, util.NodeDownState)
+	addNode("192.168.1.108", "sc4", false, util.NodeDownState)
+
+	// demote sc2: 2 UP primary nodes would remain
+	assert.NoError(t, checkAlterSubclusterTypeQuorum(&vdb, "sc2", "", Primary))
+	// promote sc3: 4 of 5 primary nodes would be UP
+	assert.NoError(t, checkAlterSubclusterTypeQuorum(&vdb, "sc3", "", Secondary))
+
+	// promote sc4: only 3 of the 6 primary nodes would be UP
+	err := checkAlterSubclusterTypeQuorum(&vdb, "sc4", "", Secondary)
+	assert.ErrorIs(t, err, ErrQuorumLost)
+
+	// the current type of the subcluster must match
+	err = checkAlterSubclusterTypeQuorum(&vdb, "sc3", "", Primary)
+	assert.ErrorContains(t, err, "is not a primary subcluster")
+
+	// unknown subcluster
+	err = checkAlterSubclusterTypeQuorum(&vdb, "sc5", "", Secondary)
+	assert.ErrorContains(t, err, "does not exist")
+
+	// demoting the only primary subcluster of a sandbox
+	addNode("192.168.1.109", "sc5", true, util.NodeUpState)
+	vdb.HostNodeMap["192.168.1.109"].Sandbox = "sand1"
+	err = checkAlterSubclusterTypeQuorum(&vdb, "sc5", "sand1", Primary)
+	assert.ErrorContains(t, err, "no primary node would remain")
+}
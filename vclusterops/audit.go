@@ -0,0 +1,213 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// AuditRecord describes a run of the op engine that sent at least one request
+// that may change the cluster
+type AuditRecord struct {
+	// name of the command, e.g., "stop_db", empty if the command did not
+	// report its name
+	Command string `json:"command"`
+	// ID of the run, which is also sent as the idempotency key of the requests
+	RunID string `json:"run_id"`
+	// the user given to WithAudit, or the OS user running the process
+	Initiator string `json:"initiator"`
+	// the options of the command, with the secrets redacted
+	Options map[string]any `json:"options"`
+	// sorted hosts that the ops sent requests to
+	Hosts     []string      `json:"hosts"`
+	StartTime time.Time     `json:"start_time"`
+	Duration  time.Duration `json:"duration"`
+	Succeeded bool          `json:"succeeded"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// AuditSink records the mutating runs of the commands, e.g., to meet the
+// compliance requirements of the tools that embed vclusterops. A failure to
+// record is logged and does not fail the command.
+type AuditSink interface {
+	Record(ctx context.Context, record AuditRecord) error
+}
+
+// AuditSinkFunc adapts a callback to an AuditSink
+type AuditSinkFunc func(ctx context.Context, record AuditRecord) error
+
+func (f AuditSinkFunc) Record(ctx context.Context, record AuditRecord) error {
+	return f(ctx, record)
+}
+
+// FileAuditSink appends the records to a file, one JSON object per line
+type FileAuditSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileAuditSink returns a sink that appends to the file at path, which is
+// created readable by its owner only if it does not exist
+func NewFileAuditSink(path string) *FileAuditSink {
+	return &FileAuditSink{path: path}
+}
+
+func (sink *FileAuditSink) Record(_ context.Context, record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("fail to marshal the audit record: %w", err)
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	const auditFilePerm = 0600
+	file, err := os.OpenFile(sink.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, auditFilePerm)
+	if err != nil {
+		return fmt.Errorf("fail to open the audit log %s: %w", sink.path, err)
+	}
+	defer file.Close()
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("fail to write to the audit log %s: %w", sink.path, err)
+	}
+	return nil
+}
+
+type auditConfig struct {
+	sink      AuditSink
+	initiator string
+}
+
+type auditKey struct{}
+
+// WithAudit returns a copy of vcc whose commands record their mutating runs
+// into sink. initiator is the user on whose behalf the commands run, and
+// defaults to the OS user running the process.
+func (vcc VClusterCommands) WithAudit(sink AuditSink, initiator string) VClusterCommands {
+	vcc.audit = &auditConfig{sink: sink, initiator: initiator}
+	return vcc
+}
+
+// engineAudit collects the audit record of a run. A nil engineAudit is a
+// run that is not audited.
+type engineAudit struct {
+	config   auditConfig
+	record   AuditRecord
+	hosts    map[string]bool
+	mutating bool
+}
+
+func startEngineAudit(ctx context.Context, runID string, options *DatabaseOptions) *engineAudit {
+	config, ok := ctx.Value(auditKey{}).(auditConfig)
+	if !ok || config.sink == nil {
+		return nil
+	}
+	audit := engineAudit{config: config, hosts: make(map[string]bool)}
+	audit.record = AuditRecord{
+		RunID:     runID,
+		Initiator: config.initiator,
+		StartTime: time.Now(),
+	}
+	if audit.record.Initiator == "" {
+		if osUser, err := user.Current(); err == nil {
+			audit.record.Initiator = osUser.Username
+		}
+	}
+	if options != nil {
+		audit.record.Command = options.commandName
+		audit.record.Options = options.redactedForAudit()
+	}
+	return &audit
+}
+
+// addOp records the hosts of an op that is about to run
+func (audit *engineAudit) addOp(info *OpInfo) {
+	if audit == nil {
+		return
+	}
+	audit.mutating = audit.mutating || info.Mutating
+	for _, host := range info.Hosts {
+		audit.hosts[host] = true
+	}
+}
+
+// finish sends the record of a mutating run to the sink
+func (audit *engineAudit) finish(ctx context.Context, logger vlog.Printer, runErr error) {
+	if audit == nil || !audit.mutating {
+		return
+	}
+	record := audit.record
+	record.Duration = time.Since(record.StartTime)
+	for host := range audit.hosts {
+		record.Hosts = append(record.Hosts, host)
+	}
+	sort.Strings(record.Hosts)
+	record.Succeeded = runErr == nil
+	if runErr != nil {
+		record.Error = runErr.Error()
+	}
+	if err := audit.config.sink.Record(ctx, record); err != nil {
+		logger.PrintWarning("Failed to record the audit of %s: %s", record.Command, err)
+	}
+}
+
+// redactedForAudit returns the options that describe the command, without
+// the password, the TLS keys, and the credentials in the configuration parameters
+func (opt *DatabaseOptions) redactedForAudit() map[string]any {
+	options := map[string]any{
+		"db_name":  opt.DBName,
+		"hosts":    opt.RawHosts,
+		"ipv6":     opt.IPv6,
+		"is_eon":   opt.IsEon,
+		"username": opt.UserName,
+	}
+	if opt.CatalogPrefix != "" {
+		options["catalog_prefix"] = opt.CatalogPrefix
+	}
+	if opt.DataPrefix != "" {
+		options["data_prefix"] = opt.DataPrefix
+	}
+	if opt.DepotPrefix != "" {
+		options["depot_prefix"] = opt.DepotPrefix
+	}
+	if opt.CommunalStorageLocation != "" {
+		options["communal_storage_location"] = opt.CommunalStorageLocation
+	}
+	if len(opt.ConfigurationParameters) > 0 {
+		configParams := make(map[string]string)
+		for key, value := range opt.ConfigurationParameters {
+			if isSensitiveConfigParameter(key) {
+				value = maskedValue
+			}
+			configParams[key] = value
+		}
+		options["config_param"] = configParams
+	}
+	if opt.Password != nil {
+		options["password"] = maskedValue
+	}
+	if opt.BearerToken != "" {
+		options["bearer_token"] = maskedValue
+	}
+	return options
+}
@@ -0,0 +1,126 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type mockGetOp struct {
+	mockOp
+}
+
+func (m *mockGetOp) prepare(_ *opEngineExecContext) error {
+	m.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{"host2": {Method: GetMethod}}
+	return nil
+}
+
+func TestAudit(t *testing.T) {
+	var records []AuditRecord
+	sink := AuditSinkFunc(func(_ context.Context, record AuditRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	vcc := VClusterCommands{}.WithAudit(sink, "alice")
+
+	options := DatabaseOptionsFactory()
+	options.DBName = "test_db"
+	options.RawHosts = []string{"host1", "host2"}
+	password := "secret"
+	options.Password = &password
+	options.Key = "tls-key"
+	options.ConfigurationParameters = map[string]string{"awsauth": "key:secret", "awsregion": "us-east-1"}
+	options.commandName = commandStopDB
+
+	// a run that only reads the cluster is not audited
+	getOp := mockGetOp{mockOp: makeMockOp(false)}
+	opEngn := options.makeClusterOpEngine([]clusterOp{&getOp})
+	assert.NoError(t, opEngn.run(vcc.Context(), vlog.Printer{}))
+	assert.Empty(t, records)
+
+	failingOp := mockFailingOp{mockOpWithResults{mockOp: makeMockOp(false)}}
+	getOp = mockGetOp{mockOp: makeMockOp(false)}
+	opEngn = options.makeClusterOpEngine([]clusterOp{&getOp, &failingOp})
+	err := opEngn.run(vcc.Context(), vlog.Printer{})
+	assert.Error(t, err)
+	assert.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, commandStopDB, record.Command)
+	assert.Equal(t, opEngn.execContext.idempotencyKey, record.RunID)
+	assert.Equal(t, "alice", record.Initiator)
+	assert.Equal(t, []string{"host1", "host2"}, record.Hosts)
+	assert.False(t, record.Succeeded)
+	assert.Contains(t, record.Error, "execute "+failingOp.name+" failed")
+
+	// the secrets are redacted
+	content, err := json.Marshal(record.Options)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "secret")
+	assert.NotContains(t, string(content), "tls-key")
+	assert.Equal(t, "us-east-1", record.Options["config_param"].(map[string]string)["awsregion"])
+	assert.Equal(t, "test_db", record.Options["db_name"])
+}
+
+func TestAuditWithContext(t *testing.T) {
+	sink := AuditSinkFunc(func(_ context.Context, _ AuditRecord) error { return nil })
+	options := DatabaseOptionsFactory()
+	// the runs are audited whatever the order of the setters
+	for _, vcc := range []VClusterCommands{
+		VClusterCommands{}.WithAudit(sink, "alice").WithContext(context.Background()),
+		VClusterCommands{}.WithContext(context.Background()).WithAudit(sink, "alice"),
+	} {
+		audit := startEngineAudit(vcc.Context(), "run-id", &options)
+		if assert.NotNil(t, audit) {
+			assert.Equal(t, "alice", audit.record.Initiator)
+		}
+	}
+}
+
+func TestFileAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink := NewFileAuditSink(path)
+	vcc := VClusterCommands{}.WithAudit(sink, "")
+
+	for i := 0; i < 2; i++ {
+		op := makeMockOp(false)
+		options := DatabaseOptionsFactory()
+		opEngn := options.makeClusterOpEngine([]clusterOp{&op})
+		assert.NoError(t, opEngn.run(vcc.Context(), vlog.Printer{}))
+	}
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 2)
+	var record AuditRecord
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.True(t, record.Succeeded)
+	assert.Equal(t, []string{"host1"}, record.Hosts)
+	// the initiator defaults to the OS user
+	assert.NotEmpty(t, record.Initiator)
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
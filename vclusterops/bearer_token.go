@@ -0,0 +1,86 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sync"
+)
+
+// TokenRefreshFunc returns a new bearer token of the database user, e.g.,
+// from an OAuth identity provider. It is given the token that the HTTPS
+// service rejected, or an empty string when no token has been issued yet.
+type TokenRefreshFunc func(rejectedToken string) (string, error)
+
+// bearerTokenSource holds the bearer token of the options. It is shared by
+// the requests of all of the runs of a command, so that a token refreshed by
+// a request is used by the requests sent after it.
+type bearerTokenSource struct {
+	mu      sync.Mutex
+	token   string
+	refresh TokenRefreshFunc
+}
+
+// getToken returns the current token, and asks for the first one if the
+// options do not provide it
+func (s *bearerTokenSource) getToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" || s.refresh == nil {
+		return s.token, nil
+	}
+	token, err := s.refresh("")
+	if err != nil {
+		return "", fmt.Errorf("fail to get a bearer token: %w", err)
+	}
+	s.token = token
+	return token, nil
+}
+
+// refreshToken replaces the token that a host rejected. If another request
+// has already replaced it, the new token is returned without a refresh. The
+// rejected token is returned if it cannot be refreshed.
+func (s *bearerTokenSource) refreshToken(rejectedToken string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != rejectedToken || s.refresh == nil {
+		return s.token, nil
+	}
+	token, err := s.refresh(rejectedToken)
+	if err != nil {
+		return "", fmt.Errorf("fail to refresh the bearer token: %w", err)
+	}
+	s.token = token
+	return token, nil
+}
+
+// hasBearerToken returns true if the requests authenticate the user with a
+// bearer token rather than with a password
+func (opt *DatabaseOptions) hasBearerToken() bool {
+	return opt.BearerToken != "" || opt.RefreshBearerToken != nil
+}
+
+// getBearerTokenSource returns the token source shared by the runs of the
+// command, nil if the options do not use a bearer token
+func (opt *DatabaseOptions) getBearerTokenSource() *bearerTokenSource {
+	if !opt.hasBearerToken() {
+		return nil
+	}
+	if opt.bearerToken == nil {
+		opt.bearerToken = &bearerTokenSource{token: opt.BearerToken, refresh: opt.RefreshBearerToken}
+	}
+	return opt.bearerToken
+}
@@ -0,0 +1,130 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestBearerTokenSource(t *testing.T) {
+	var refreshed []string
+	source := &bearerTokenSource{refresh: func(rejectedToken string) (string, error) {
+		refreshed = append(refreshed, rejectedToken)
+		return "token-" + string(rune('a'+len(refreshed)-1)), nil
+	}}
+
+	// the first token is asked for
+	token, err := source.getToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "token-a", token)
+	token, err = source.getToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "token-a", token)
+
+	// a rejected token is refreshed once, even if several requests reject it
+	token, err = source.refreshToken("token-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "token-b", token)
+	token, err = source.refreshToken("token-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "token-b", token)
+	assert.Equal(t, []string{"", "token-a"}, refreshed)
+
+	// a token without a refresh callback is kept
+	source = &bearerTokenSource{token: "static"}
+	token, err = source.refreshToken("static")
+	assert.NoError(t, err)
+	assert.Equal(t, "static", token)
+
+	source = &bearerTokenSource{refresh: func(string) (string, error) {
+		return "", errors.New("identity provider is down")
+	}}
+	_, err = source.getToken()
+	assert.ErrorContains(t, err, "fail to get a bearer token: identity provider is down")
+}
+
+func TestBearerTokenOptions(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	opt.DBName = "test_db"
+	opt.RawHosts = []string{"192.168.1.101"}
+	assert.Nil(t, opt.getBearerTokenSource())
+
+	opt.BearerToken = "token"
+	assert.True(t, opt.hasPassword())
+	assert.NoError(t, opt.validateBaseOptions(commandListConfigurationParams, vlog.Printer{}))
+	// the runs of a command share the token
+	assert.Same(t, opt.getBearerTokenSource(), opt.getBearerTokenSource())
+	opEngine := opt.makeClusterOpEngine(nil)
+	assert.Same(t, opt.getBearerTokenSource(), opEngine.bearerToken)
+	assert.Equal(t, maskedValue, opt.redactedForAudit()["bearer_token"])
+
+	opt.SetPassword("secret")
+	assert.ErrorContains(t, opt.validateBaseOptions(commandListConfigurationParams, vlog.Printer{}),
+		"the bearer token cannot be set with a password or a credential provider")
+}
+
+func TestBearerTokenRequests(t *testing.T) {
+	source := &bearerTokenSource{token: "expired", refresh: func(string) (string, error) {
+		return "fresh", nil
+	}}
+
+	dispatcher := makeHTTPRequestDispatcher(context.Background(), vlog.Printer{})
+	dispatcher.bearerToken = source
+	httpRequest := clusterHTTPRequest{RequestCollection: map[string]hostHTTPRequest{
+		"192.168.1.101": {Username: "dbadmin"},
+		"192.168.1.102": {},
+	}}
+	dispatcher.setDefaults(&httpRequest)
+	assert.Same(t, source, httpRequest.RequestCollection["192.168.1.101"].BearerToken)
+	assert.Nil(t, httpRequest.RequestCollection["192.168.1.102"].BearerToken)
+
+	// the expired token is rejected, and the request is sent again with a fresh one
+	var authHeaders []string
+	adapter := makeHTTPAdapter(vlog.Printer{})
+	adapter.host = "192.168.1.101"
+	adapter.transports = makeTransportCache(&TransportPolicy{}, nil, func(string, http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			authHeaders = append(authHeaders, req.Header.Get("Authorization"))
+			statusCode := http.StatusOK
+			if req.Header.Get("Authorization") != "Bearer fresh" {
+				statusCode = http.StatusUnauthorized
+			}
+			return &http.Response{StatusCode: statusCode, Header: http.Header{},
+				Body: io.NopCloser(strings.NewReader("{}"))}, nil
+		})
+	})
+	request := httpRequest.RequestCollection["192.168.1.101"]
+	request.Method = GetMethod
+	request.buildHTTPSEndpoint("nodes")
+	result := adapter.send(context.Background(), &request)
+	assert.NoError(t, result.err)
+	assert.Equal(t, []string{"Bearer expired", "Bearer fresh"}, authHeaders)
+
+	// a token that cannot be refreshed is rejected once
+	authHeaders = nil
+	request.BearerToken = &bearerTokenSource{token: "static"}
+	result = adapter.send(context.Background(), &request)
+	assert.True(t, result.isUnauthorizedRequest())
+	assert.Equal(t, []string{"Bearer static"}, authHeaders)
+}
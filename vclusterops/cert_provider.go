@@ -0,0 +1,148 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertProvider supplies the TLS credentials that the commands present to the
+// HTTPS service and to the node management agents. The methods are called
+// for every new connection, so a provider that refreshes its credentials,
+// e.g., after a rotation, is picked up by a long-running process without a
+// restart. A workload identity source, such as a SPIFFE X.509 SVID source,
+// can be adapted to it, and the certificates of a Kubernetes secret can be
+// read from the files it is mounted as with NewFileCertProvider.
+type CertProvider interface {
+	// GetClientCertificate returns the certificate and the private key that
+	// the client presents
+	GetClientCertificate() (*tls.Certificate, error)
+	// GetRootCAs returns the CA certificates that the client trusts
+	GetRootCAs() (*x509.CertPool, error)
+}
+
+// pemCertProvider holds credentials that never change
+type pemCertProvider struct {
+	cert    tls.Certificate
+	rootCAs *x509.CertPool
+}
+
+// NewPEMCertProvider returns a provider of the PEM-encoded key, certificate
+// and CA certificates, which may be empty
+func NewPEMCertProvider(keyPEM, certPEM, caCertPEM string) (CertProvider, error) {
+	cert, rootCAs, err := parseCertBundle([]byte(keyPEM), []byte(certPEM), []byte(caCertPEM))
+	if err != nil {
+		return nil, err
+	}
+	return &pemCertProvider{cert: cert, rootCAs: rootCAs}, nil
+}
+
+func (p *pemCertProvider) GetClientCertificate() (*tls.Certificate, error) {
+	return &p.cert, nil
+}
+
+func (p *pemCertProvider) GetRootCAs() (*x509.CertPool, error) {
+	return p.rootCAs, nil
+}
+
+// fileCertProvider reloads the credentials when one of their files changes
+type fileCertProvider struct {
+	keyFile, certFile, caCertFile string
+
+	mu      sync.Mutex
+	cert    tls.Certificate
+	rootCAs *x509.CertPool
+	// modification times of the files when they were loaded
+	modTimes [3]time.Time
+}
+
+// NewFileCertProvider returns a provider that reads the PEM-encoded key,
+// certificate and CA certificates from files, and reloads them when they
+// are modified. If a reload fails, e.g., because the files are being
+// replaced, the credentials loaded before are used.
+func NewFileCertProvider(keyFile, certFile, caCertFile string) (CertProvider, error) {
+	p := &fileCertProvider{keyFile: keyFile, certFile: certFile, caCertFile: caCertFile}
+	if err := p.reloadIfModified(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *fileCertProvider) GetClientCertificate() (*tls.Certificate, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.refresh()
+	cert := p.cert
+	return &cert, nil
+}
+
+func (p *fileCertProvider) GetRootCAs() (*x509.CertPool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.refresh()
+	return p.rootCAs, nil
+}
+
+// refresh keeps the loaded credentials if the files cannot be reloaded
+func (p *fileCertProvider) refresh() {
+	_ = p.reloadIfModified()
+}
+
+func (p *fileCertProvider) reloadIfModified() error {
+	var modTimes [3]time.Time
+	for i, path := range []string{p.keyFile, p.certFile, p.caCertFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("fail to read TLS credential file %s, details: %w", path, err)
+		}
+		modTimes[i] = info.ModTime()
+	}
+	if p.rootCAs != nil && modTimes == p.modTimes {
+		return nil
+	}
+
+	var contents [3][]byte
+	for i, path := range []string{p.keyFile, p.certFile, p.caCertFile} {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("fail to read TLS credential file %s, details: %w", path, err)
+		}
+		contents[i] = content
+	}
+	cert, rootCAs, err := parseCertBundle(contents[0], contents[1], contents[2])
+	if err != nil {
+		return err
+	}
+	p.cert, p.rootCAs, p.modTimes = cert, rootCAs, modTimes
+	return nil
+}
+
+func parseCertBundle(keyPEM, certPEM, caCertPEM []byte) (tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return cert, nil, fmt.Errorf("fail to load HTTPS certificates, details %w", err)
+	}
+	rootCAs := x509.NewCertPool()
+	if len(caCertPEM) > 0 && !rootCAs.AppendCertsFromPEM(caCertPEM) {
+		return cert, nil, fmt.Errorf("fail to load HTTPS CA certificates")
+	}
+	return cert, rootCAs, nil
+}
@@ -0,0 +1,101 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/tls"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func writeTestTLSBundle(t *testing.T, dir string, bundle *testTLSBundle, modTime time.Time) {
+	files := map[string]string{"key.pem": bundle.keyPEM, "cert.pem": bundle.certPEM, "ca.pem": bundle.caPEM}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		assert.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+}
+
+func TestPEMCertProvider(t *testing.T) {
+	bundle := makeTestTLSBundle(t)
+	provider, err := NewPEMCertProvider(bundle.keyPEM, bundle.certPEM, bundle.caPEM)
+	assert.NoError(t, err)
+	cert, err := provider.GetClientCertificate()
+	assert.NoError(t, err)
+	assert.Equal(t, bundle.cert.Certificate[0], cert.Certificate[0])
+
+	_, err = NewPEMCertProvider(bundle.keyPEM, bundle.certPEM, "not a certificate")
+	assert.ErrorContains(t, err, "fail to load HTTPS CA certificates")
+}
+
+func TestFileCertProvider(t *testing.T) {
+	dir := t.TempDir()
+	keyFile, certFile, caFile := filepath.Join(dir, "key.pem"), filepath.Join(dir, "cert.pem"), filepath.Join(dir, "ca.pem")
+	_, err := NewFileCertProvider(keyFile, certFile, caFile)
+	assert.ErrorContains(t, err, "fail to read TLS credential file")
+
+	first := makeTestTLSBundle(t)
+	writeTestTLSBundle(t, dir, &first, time.Now().Add(-time.Hour))
+	provider, err := NewFileCertProvider(keyFile, certFile, caFile)
+	assert.NoError(t, err)
+	cert, err := provider.GetClientCertificate()
+	assert.NoError(t, err)
+	assert.Equal(t, first.cert.Certificate[0], cert.Certificate[0])
+
+	// the rotated files are reloaded
+	second := makeTestTLSBundle(t)
+	writeTestTLSBundle(t, dir, &second, time.Now())
+	cert, err = provider.GetClientCertificate()
+	assert.NoError(t, err)
+	assert.Equal(t, second.cert.Certificate[0], cert.Certificate[0])
+
+	// a file being replaced does not lose the credentials loaded before
+	assert.NoError(t, os.WriteFile(keyFile, []byte("partial"), 0600))
+	cert, err = provider.GetClientCertificate()
+	assert.NoError(t, err)
+	assert.Equal(t, second.cert.Certificate[0], cert.Certificate[0])
+}
+
+func TestCertProviderInOptions(t *testing.T) {
+	bundle := makeTestTLSBundle(t)
+	provider, err := NewPEMCertProvider(bundle.keyPEM, bundle.certPEM, bundle.caPEM)
+	assert.NoError(t, err)
+
+	options := DatabaseOptionsFactory()
+	assert.False(t, options.hasCertsInOptions())
+	options.CertProvider = provider
+	assert.True(t, options.hasCertsInOptions())
+	opEngn := options.makeClusterOpEngine(nil)
+	assert.True(t, opEngn.shouldGetCertsFromOptions())
+
+	// the HTTP client asks the provider for the certificate in the handshake
+	adapter := makeHTTPAdapter(vlog.Printer{})
+	request := hostHTTPRequest{UseCertsInOptions: true, Certs: httpsCerts{provider: provider}}
+	client, err := adapter.setupHTTPClient(&request, false, nil)
+	assert.NoError(t, err)
+	tlsConfig := client.Transport.(*http.Transport).TLSClientConfig
+	assert.Empty(t, tlsConfig.Certificates)
+	cert, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, bundle.cert.Certificate[0], cert.Certificate[0])
+}
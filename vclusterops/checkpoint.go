@@ -0,0 +1,204 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+const checkpointFilePerm = 0600
+
+// opEngineCheckpoint records the progress of a failed engine run, so that a
+// later run of the same command can resume from the failed op
+type opEngineCheckpoint struct {
+	Command string   `json:"command"`
+	DBName  string   `json:"db_name"`
+	Hosts   []string `json:"hosts"`
+	// hash of the other options of the command that the progress depends on
+	OptionsKey string `json:"options_key,omitempty"`
+	// the database info that the command collected before the engine run
+	VDB *VCoordinationDatabase `json:"vdb,omitempty"`
+	// the result that revive_db collected before the engine run
+	ReviveResult *VReviveDatabaseResult `json:"revive_result,omitempty"`
+	// names of the ops that completed, in the order of the instructions
+	CompletedOps []string `json:"completed_ops"`
+	// the data that the completed ops left for the other ops
+	ExecContext execContextCheckpoint `json:"exec_context"`
+}
+
+// execContextCheckpoint is the part of opEngineExecContext that is saved
+// in a checkpoint
+type execContextCheckpoint struct {
+	NetworkProfiles               map[string]NetworkProfile `json:"network_profiles,omitempty"`
+	UpHosts                       []string                  `json:"up_hosts,omitempty"`
+	NodesInfo                     []NodeInfo                `json:"nodes_info,omitempty"`
+	SCNodesInfo                   []NodeInfo                `json:"sc_nodes_info,omitempty"`
+	UpScInfo                      map[string]string         `json:"up_sc_info,omitempty"`
+	UpHostsToSandboxes            map[string]string         `json:"up_hosts_to_sandboxes,omitempty"`
+	DefaultSCName                 string                    `json:"default_sc_name,omitempty"`
+	HostsWithLatestCatalog        []string                  `json:"hosts_with_latest_catalog,omitempty"`
+	PrimaryHostsWithLatestCatalog []string                  `json:"primary_hosts_with_latest_catalog,omitempty"`
+	StartupCommandMap             map[string][]string       `json:"startup_command_map,omitempty"`
+	DBInfo                        string                    `json:"db_info,omitempty"`
+	RestorePoints                 []RestorePoint            `json:"restore_points,omitempty"`
+	IdempotencyKey                string                    `json:"idempotency_key,omitempty"`
+}
+
+func (execContext *opEngineExecContext) saveCheckpoint() execContextCheckpoint {
+	return execContextCheckpoint{
+		NetworkProfiles:               execContext.networkProfiles,
+		UpHosts:                       execContext.upHosts,
+		NodesInfo:                     execContext.nodesInfo,
+		SCNodesInfo:                   execContext.scNodesInfo,
+		UpScInfo:                      execContext.upScInfo,
+		UpHostsToSandboxes:            execContext.upHostsToSandboxes,
+		DefaultSCName:                 execContext.defaultSCName,
+		HostsWithLatestCatalog:        execContext.hostsWithLatestCatalog,
+		PrimaryHostsWithLatestCatalog: execContext.primaryHostsWithLatestCatalog,
+		StartupCommandMap:             execContext.startupCommandMap,
+		DBInfo:                        execContext.dbInfo,
+		RestorePoints:                 execContext.restorePoints,
+		IdempotencyKey:                execContext.idempotencyKey,
+	}
+}
+
+func (execContext *opEngineExecContext) restoreCheckpoint(checkpoint *execContextCheckpoint) {
+	execContext.networkProfiles = checkpoint.NetworkProfiles
+	execContext.upHosts = checkpoint.UpHosts
+	execContext.nodesInfo = checkpoint.NodesInfo
+	execContext.scNodesInfo = checkpoint.SCNodesInfo
+	execContext.upScInfo = checkpoint.UpScInfo
+	execContext.upHostsToSandboxes = checkpoint.UpHostsToSandboxes
+	execContext.defaultSCName = checkpoint.DefaultSCName
+	execContext.hostsWithLatestCatalog = checkpoint.HostsWithLatestCatalog
+	execContext.primaryHostsWithLatestCatalog = checkpoint.PrimaryHostsWithLatestCatalog
+	execContext.startupCommandMap = checkpoint.StartupCommandMap
+	execContext.dbInfo = checkpoint.DBInfo
+	execContext.restorePoints = checkpoint.RestorePoints
+	// a checkpoint from an older version does not have the key
+	if checkpoint.IdempotencyKey != "" {
+		execContext.idempotencyKey = checkpoint.IdempotencyKey
+	}
+}
+
+// getCheckpointPath returns the path of the checkpoint file of a command,
+// or an empty string if checkpointing is disabled
+func (opt *DatabaseOptions) getCheckpointPath(command string) string {
+	if opt.CheckpointDir == "" {
+		return ""
+	}
+	return filepath.Join(opt.CheckpointDir, fmt.Sprintf("%s_%s.checkpoint.json", opt.DBName, command))
+}
+
+// makeCheckpointKey returns the hash of the options that a checkpoint is saved for
+func makeCheckpointKey(options ...any) (string, error) {
+	content, err := json.Marshal(options)
+	if err != nil {
+		return "", fmt.Errorf("fail to marshal the options of the checkpoint: %w", err)
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// loadCheckpoint reads the checkpoint that a failed run of the command left.
+// It returns nil if there is no checkpoint, or if the checkpoint was saved
+// for another database, other hosts, or other options of the command.
+func (opt *DatabaseOptions) loadCheckpoint(command, optionsKey string) (*opEngineCheckpoint, error) {
+	checkpointPath := opt.getCheckpointPath(command)
+	if checkpointPath == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(checkpointPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fail to read checkpoint file %s: %w", checkpointPath, err)
+	}
+	checkpoint := &opEngineCheckpoint{}
+	err = json.Unmarshal(content, checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse checkpoint file %s: %w", checkpointPath, err)
+	}
+	if checkpoint.Command != command || checkpoint.DBName != opt.DBName || checkpoint.OptionsKey != optionsKey ||
+		len(util.SliceDiff(checkpoint.Hosts, opt.Hosts)) > 0 || len(util.SliceDiff(opt.Hosts, checkpoint.Hosts)) > 0 {
+		return nil, nil
+	}
+	return checkpoint, nil
+}
+
+// makeResumableClusterOpEngine creates a VClusterOpEngine that saves a
+// checkpoint when an op fails, and that skips the ops completed in the given
+// checkpoint of a previous run. The vdb is saved in the checkpoint for the
+// command to reuse when it resumes.
+func (opt *DatabaseOptions) makeResumableClusterOpEngine(command, optionsKey string, instructions []clusterOp,
+	checkpoint *opEngineCheckpoint, vdb *VCoordinationDatabase) VClusterOpEngine {
+	clusterOpEngine := opt.makeClusterOpEngine(instructions)
+	clusterOpEngine.checkpointPath = opt.getCheckpointPath(command)
+	if clusterOpEngine.checkpointPath == "" {
+		return clusterOpEngine
+	}
+	if checkpoint == nil {
+		checkpoint = &opEngineCheckpoint{Command: command, DBName: opt.DBName, Hosts: opt.Hosts, OptionsKey: optionsKey}
+	}
+	checkpoint.VDB = vdb
+	clusterOpEngine.checkpoint = checkpoint
+	return clusterOpEngine
+}
+
+// getResumePoint returns the number of leading instructions that were
+// completed in the checkpoint. A checkpoint of other instructions is reset.
+func (checkpoint *opEngineCheckpoint) getResumePoint(instructions []clusterOp) int {
+	if len(checkpoint.CompletedOps) > len(instructions) {
+		checkpoint.CompletedOps = nil
+		return 0
+	}
+	for i, opName := range checkpoint.CompletedOps {
+		if instructions[i].getName() != opName {
+			checkpoint.CompletedOps = nil
+			return 0
+		}
+	}
+	return len(checkpoint.CompletedOps)
+}
+
+func (checkpoint *opEngineCheckpoint) save(checkpointPath string) error {
+	content, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("fail to marshal the checkpoint: %w", err)
+	}
+	err = os.WriteFile(checkpointPath, content, checkpointFilePerm)
+	if err != nil {
+		return fmt.Errorf("fail to write checkpoint file %s: %w", checkpointPath, err)
+	}
+	return nil
+}
+
+func removeCheckpoint(checkpointPath string) error {
+	err := os.Remove(checkpointPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("fail to remove checkpoint file %s: %w", checkpointPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+)
+
+type mockCheckpointOp struct {
+	mockOp
+	fail bool
+}
+
+func makeMockCheckpointOp(name string, fail bool) *mockCheckpointOp {
+	op := &mockCheckpointOp{mockOp: makeMockOp(false), fail: fail}
+	op.name = name
+	return op
+}
+
+func (m *mockCheckpointOp) execute(execContext *opEngineExecContext) error {
+	m.calledExecute = true
+	if m.fail {
+		return errors.New("mock failure")
+	}
+	execContext.SetUpHosts(append(execContext.upHosts, m.name))
+	return nil
+}
+
+func TestResumeFromCheckpoint(t *testing.T) {
+	options := DatabaseOptionsFactory()
+	options.DBName = "test_db"
+	options.Hosts = []string{"192.168.1.101"}
+	options.CheckpointDir = t.TempDir()
+	vdb := makeVCoordinationDatabase()
+	vdb.NumShards = 6
+	const checkpointKey = "test_key"
+
+	// the first run fails at the second op
+	checkpoint, err := options.loadCheckpoint(commandReviveDB, checkpointKey)
+	assert.NoError(t, err)
+	assert.Nil(t, checkpoint)
+	opEngn := options.makeResumableClusterOpEngine(commandReviveDB, checkpointKey, []clusterOp{
+		makeMockCheckpointOp("op1", false), makeMockCheckpointOp("op2", true),
+	}, checkpoint, &vdb)
+	opEngn.checkpoint.ReviveResult = &VReviveDatabaseResult{Description: &DBDescription{ShardCount: 6},
+		RestorePoints: []RestorePoint{{Archive: "archive", ID: "id1", Index: 1}}}
+	err = opEngn.run(context.Background(), vlog.Printer{})
+	assert.ErrorContains(t, err, "mock failure")
+	idempotencyKey := opEngn.execContext.idempotencyKey
+	assert.NotEmpty(t, idempotencyKey)
+
+	// a checkpoint for other hosts is ignored
+	otherOptions := options
+	otherOptions.Hosts = []string{"192.168.1.102"}
+	checkpoint, err = otherOptions.loadCheckpoint(commandReviveDB, checkpointKey)
+	assert.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	// a checkpoint for other options of the command is ignored
+	checkpoint, err = options.loadCheckpoint(commandReviveDB, "other_key")
+	assert.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	checkpoint, err = options.loadCheckpoint(commandReviveDB, checkpointKey)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"op1"}, checkpoint.CompletedOps)
+	assert.Equal(t, []string{"op1"}, checkpoint.ExecContext.UpHosts)
+	assert.Equal(t, 6, checkpoint.VDB.NumShards)
+	// the fields of the result that the resumed revive reads are saved
+	assert.Equal(t, 6, checkpoint.ReviveResult.Description.ShardCount)
+	assert.Equal(t, "id1", checkpoint.ReviveResult.RestorePoints[0].ID)
+
+	// the second run skips the completed op, and removes the checkpoint on success
+	op1 := makeMockCheckpointOp("op1", false)
+	op2 := makeMockCheckpointOp("op2", false)
+	opEngn = options.makeResumableClusterOpEngine(commandReviveDB, checkpointKey, []clusterOp{op1, op2}, checkpoint, &vdb)
+	err = opEngn.run(context.Background(), vlog.Printer{})
+	assert.NoError(t, err)
+	assert.False(t, op1.calledPrepare)
+	assert.True(t, op2.calledExecute)
+	upHosts, _ := opEngn.execContext.UpHosts()
+	assert.Equal(t, []string{"op1", "op2"}, upHosts)
+	// the resumed run sends the requests with the same idempotency key
+	assert.Equal(t, idempotencyKey, opEngn.execContext.idempotencyKey)
+	checkpoint, err = options.loadCheckpoint(commandReviveDB, checkpointKey)
+	assert.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	// a checkpoint of other instructions does not skip any op
+	checkpoint = &opEngineCheckpoint{CompletedOps: []string{"op3"}}
+	assert.Equal(t, 0, checkpoint.getResumePoint([]clusterOp{op1, op2}))
+	assert.Empty(t, checkpoint.CompletedOps)
+}
+
+func TestReviveCheckpointKey(t *testing.T) {
+	options := VReviveDBOptionsFactory()
+	options.CommunalStorageLocation = "s3://bucket/test_db"
+	options.RestorePoint = RestorePointPolicy{Archive: "archive", ID: "id1"}
+	options.NodeHostMap = map[string]string{"v_test_db_node0001": "192.168.1.101"}
+	checkpointKey, err := options.getCheckpointKey()
+	assert.NoError(t, err)
+	sameKey, err := options.getCheckpointKey()
+	assert.NoError(t, err)
+	assert.Equal(t, checkpointKey, sameKey)
+
+	// a change of any option that the revive depends on invalidates the checkpoint
+	changes := []func(options *VReviveDatabaseOptions){
+		func(options *VReviveDatabaseOptions) { options.CommunalStorageLocation = "s3://bucket/other_db" },
+		func(options *VReviveDatabaseOptions) { options.Sandbox = "sand1" },
+		func(options *VReviveDatabaseOptions) { options.RestorePoint.ID = "id2" },
+		func(options *VReviveDatabaseOptions) { options.RestorePoint.Index = 2 },
+		func(options *VReviveDatabaseOptions) { options.NodeHostMap["v_test_db_node0001"] = "192.168.1.102" },
+	}
+	for _, change := range changes {
+		changedOptions := options
+		changedOptions.NodeHostMap = maps.Clone(options.NodeHostMap)
+		change(&changedOptions)
+		changedKey, err := changedOptions.getCheckpointKey()
+		assert.NoError(t, err)
+		assert.NotEqual(t, checkpointKey, changedKey)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	clientTLSCheckTimeout = 10 * time.Second
+	// the code of the SSLRequest message that a client sends to ask the
+	// server to switch the connection to TLS
+	sslRequestCode = 80877103
+	// the size of the SSLRequest message, including the size itself
+	sslRequestSize = 8
+)
+
+// clientTLSCheckOp opens a new client connection to each of the up hosts, and
+// checks that the TLS handshake presents the expected server certificate. It
+// does not send any HTTP request, so it does not go through the dispatcher.
+type clientTLSCheckOp struct {
+	opBase
+	port     int
+	certDER  []byte
+	certName string
+	// the certificate that each host presented, in DER
+	hostCerts map[string][]byte
+}
+
+func makeClientTLSCheckOp(port int, certName string, certDER []byte) clientTLSCheckOp {
+	op := clientTLSCheckOp{}
+	op.name = "ClientTLSCheckOp"
+	op.description = "Verify certificate of new client connections"
+	op.port = port
+	op.certName = certName
+	op.certDER = certDER
+	return op
+}
+
+func (op *clientTLSCheckOp) prepare(execContext *opEngineExecContext) error {
+	upHosts, err := execContext.requireUpHosts(op.name)
+	if err != nil {
+		return err
+	}
+	op.hosts = upHosts
+	return nil
+}
+
+// loadCertsIfNeeded does nothing, as the op does not use the HTTPS service
+func (op *clientTLSCheckOp) loadCertsIfNeeded(_ *httpsCerts, _ bool) error {
+	return nil
+}
+
+func (op *clientTLSCheckOp) execute(execContext *opEngineExecContext) error {
+	op.hostCerts = make(map[string][]byte)
+	var allErrs error
+	for _, host := range op.hosts {
+		cert, err := op.getServerCertificate(execContext, host)
+		if err != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] fail to negotiate TLS with host %s, details: %w", op.name, host, err))
+			continue
+		}
+		op.hostCerts[host] = cert
+	}
+	if allErrs != nil {
+		return allErrs
+	}
+	return op.processResult(execContext)
+}
+
+// getServerCertificate asks the server to switch a new client connection to
+// TLS, and returns the certificate that the server presents in the handshake.
+// The connection goes through the address book and the proxy of the command,
+// like the HTTP requests, and is aborted when the command is canceled.
+func (op *clientTLSCheckOp) getServerCertificate(execContext *opEngineExecContext, host string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(execContext.ctx, clientTLSCheckTimeout)
+	defer cancel()
+	conn, err := execContext.dispatcher.transports.dialContext(ctx, host, op.port)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return nil, err
+	}
+	// the deadline does not stop the exchange when the command is canceled
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	sslRequest := make([]byte, sslRequestSize)
+	binary.BigEndian.PutUint32(sslRequest[0:4], sslRequestSize)
+	binary.BigEndian.PutUint32(sslRequest[4:8], sslRequestCode)
+	if _, err = conn.Write(sslRequest); err != nil {
+		return nil, err
+	}
+	answer := make([]byte, 1)
+	if _, err = io.ReadFull(conn, answer); err != nil {
+		return nil, err
+	}
+	if answer[0] != 'S' {
+		return nil, errors.New("the server does not accept TLS connections")
+	}
+
+	// the certificate is compared with the expected one below, rather
+	// than verified against a CA
+	tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+	if err = tlsConn.HandshakeContext(ctx); err != nil {
+		return nil, err
+	}
+	peerCerts := tlsConn.ConnectionState().PeerCertificates
+	if len(peerCerts) == 0 {
+		return nil, errors.New("the server did not present a certificate")
+	}
+	return peerCerts[0].Raw, nil
+}
+
+func (op *clientTLSCheckOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+	for host, cert := range op.hostCerts {
+		if !bytes.Equal(cert, op.certDER) {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] host %s did not present certificate %s to a new client connection",
+				op.name, host, op.certName))
+			continue
+		}
+		op.logger.Info("host presented the expected certificate", "host", host, "certificate", op.certName)
+	}
+	return allErrs
+}
+
+func (op *clientTLSCheckOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// makeTestCertificate returns a self-signed certificate
+func makeTestCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "vertica"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// serveClientTLS accepts one connection, answers the SSLRequest and
+// completes the TLS handshake with the certificate
+func serveClientTLS(listener net.Listener, cert tls.Certificate) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	sslRequest := make([]byte, sslRequestSize)
+	if _, err = io.ReadFull(conn, sslRequest); err != nil {
+		return
+	}
+	if _, err = conn.Write([]byte{'S'}); err != nil {
+		return
+	}
+	tlsConn := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+	_ = tlsConn.Handshake()
+}
+
+func TestClientTLSCheckOp(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	deployed := makeTestCertificate(t)
+	other := makeTestCertificate(t)
+
+	// the host presents the deployed certificate
+	go serveClientTLS(listener, deployed)
+	op := makeClientTLSCheckOp(port, "server_cert", deployed.Certificate[0])
+	op.hosts = []string{"127.0.0.1"}
+	execContext := makeOpEngineExecContext(context.Background(), op.logger)
+	assert.NoError(t, op.execute(&execContext))
+
+	// the host still presents another certificate
+	go serveClientTLS(listener, other)
+	err = op.execute(&execContext)
+	assert.ErrorContains(t, err, "did not present certificate server_cert")
+
+	// the connection goes through the proxy, to the rewritten address of the host
+	var dialedAddresses []string
+	proxyPolicy := ProxyPolicy{
+		Dialer: dialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialedAddresses = append(dialedAddresses, address)
+			var d net.Dialer
+			return d.DialContext(ctx, network, listener.Addr().String())
+		}),
+		AddressRewrites: map[string]string{"192.168.1.101": "node1.example.com"},
+	}
+	execContext.dispatcher.transports = makeTransportCache(&TransportPolicy{}, &proxyPolicy, nil)
+	op.hosts = []string{"192.168.1.101"}
+	go serveClientTLS(listener, deployed)
+	assert.NoError(t, op.execute(&execContext))
+	assert.Equal(t, []string{fmt.Sprintf("node1.example.com:%d", port)}, dialedAddresses)
+
+	// a canceled command does not connect
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	execContext.ctx = ctx
+	assert.ErrorContains(t, op.execute(&execContext), "fail to negotiate TLS with host 192.168.1.101")
+}
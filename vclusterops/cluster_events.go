@@ -0,0 +1,336 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	defaultClusterEventPollInterval = 10 * time.Second
+	clusterEventBufferSize          = 64
+)
+
+type ClusterEventType string
+
+const (
+	// the cluster state could not be polled
+	ClusterEventPollFailed ClusterEventType = "PollFailed"
+	// a node changed to UP
+	ClusterEventNodeUp ClusterEventType = "NodeUp"
+	// a node changed to DOWN
+	ClusterEventNodeDown ClusterEventType = "NodeDown"
+	// a node changed to a state other than UP or DOWN, e.g., UNKNOWN
+	ClusterEventNodeStateChanged ClusterEventType = "NodeStateChanged"
+	// a node moved into a sandbox, out of it, or to another sandbox
+	ClusterEventSandboxChanged ClusterEventType = "SandboxChanged"
+	// the value of a configuration parameter was set, changed or reset to its default
+	ClusterEventConfigParameterChanged ClusterEventType = "ConfigParameterChanged"
+)
+
+// ClusterEvent is a change of the cluster state between two polls
+type ClusterEvent struct {
+	Type ClusterEventType
+	Time time.Time
+	// the node that the event is about, empty if it is not about a node
+	NodeName string
+	Host     string
+	// the sandbox of a configuration parameter, empty for the main cluster
+	Sandbox         string
+	ConfigParameter string
+	// the state, the sandbox or the value before and after the change
+	OldValue string
+	NewValue string
+	Err      error
+}
+
+// VClusterEventOptions are the options of SubscribeClusterEvents
+type VClusterEventOptions struct {
+	/* part 1: basic db info */
+	DatabaseOptions
+
+	/* part 2: subscription options */
+	// interval between two polls of the cluster state
+	PollInterval time.Duration
+	// whether to also poll the configuration parameters, which needs a
+	// user name since they are read through a database connection
+	WatchConfigParameters bool
+}
+
+func VClusterEventOptionsFactory() VClusterEventOptions {
+	options := VClusterEventOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	options.PollInterval = defaultClusterEventPollInterval
+
+	return options
+}
+
+func (options *VClusterEventOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandClusterEvents, logger)
+	if err != nil {
+		return err
+	}
+	if options.PollInterval <= 0 {
+		return fmt.Errorf("the poll interval of the cluster events must be positive")
+	}
+	return nil
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VClusterEventOptions) analyzeOptions() (err error) {
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VClusterEventOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// ClusterEventSubscription polls the state of a database in the background,
+// and sends its changes as events. It is created by SubscribeClusterEvents.
+type ClusterEventSubscription struct {
+	events chan ClusterEvent
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	pollInterval time.Duration
+	log          vlog.Printer
+	now          func() time.Time
+	// returns the current state of the database
+	fetchSnapshot func() (clusterSnapshot, error)
+	// the state of the last successful poll, nil before the first one
+	lastSnapshot *clusterSnapshot
+}
+
+// clusterSnapshot is the state of a database that the events are computed from
+type clusterSnapshot struct {
+	// node name -> node
+	nodes map[string]*VCoordinationNode
+	// sandbox, name, level and node of the parameter -> parameter
+	configParameters map[string]ConfigurationParameter
+}
+
+// SubscribeClusterEvents starts polling the node states, the sandboxes and,
+// optionally, the configuration parameters of a database, and sends their
+// changes as events. The first poll only records the state. The HTTPS
+// service has no endpoint to wait for changes, so the changes that are
+// undone between two polls are not seen. It runs until Stop is called or
+// the context of vcc is done. The caller must read the events, which are
+// sent in order and are not dropped, or the subscription blocks.
+func (vcc VClusterCommands) SubscribeClusterEvents(options *VClusterEventOptions) (*ClusterEventSubscription, error) {
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+	if options.WatchConfigParameters {
+		err = options.validateUserName(vcc.Log)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// the options may change while the subscription runs
+	dbOptions := options.DatabaseOptions
+	watchConfigParameters := options.WatchConfigParameters
+
+	ctx, cancel := context.WithCancel(vcc.Context())
+	subscriptionVcc := vcc.WithContext(ctx)
+	subscription := makeClusterEventSubscription(options.PollInterval, vcc.Log, cancel)
+	subscription.fetchSnapshot = func() (clusterSnapshot, error) {
+		snapshot := clusterSnapshot{nodes: make(map[string]*VCoordinationNode)}
+		vdb := makeVCoordinationDatabase()
+		// the report only holds the latest poll
+		dbOptions.resetReport()
+		err := subscriptionVcc.getVDBFromRunningDBIncludeSandbox(&vdb, &dbOptions, AnySandbox)
+		if err != nil {
+			return snapshot, err
+		}
+		for _, vnode := range vdb.HostNodeMap {
+			snapshot.nodes[vnode.Name] = vnode
+		}
+		if !watchConfigParameters {
+			return snapshot, nil
+		}
+
+		listOptions := VListConfigurationParametersOptionsFactory()
+		listOptions.DatabaseOptions = dbOptions
+		configParameters, err := subscriptionVcc.VListConfigurationParameters(&listOptions)
+		if err != nil {
+			return snapshot, err
+		}
+		snapshot.configParameters = make(map[string]ConfigurationParameter)
+		for _, parameter := range configParameters {
+			snapshot.configParameters[configParameterKey(&parameter)] = parameter
+		}
+		return snapshot, nil
+	}
+
+	go subscription.run(ctx)
+	return subscription, nil
+}
+
+func makeClusterEventSubscription(pollInterval time.Duration, log vlog.Printer,
+	cancel context.CancelFunc) *ClusterEventSubscription {
+	return &ClusterEventSubscription{
+		events:       make(chan ClusterEvent, clusterEventBufferSize),
+		cancel:       cancel,
+		done:         make(chan struct{}),
+		pollInterval: pollInterval,
+		log:          log,
+		now:          time.Now,
+	}
+}
+
+// Events returns the channel of the cluster events. It is closed once the
+// subscription stops.
+func (s *ClusterEventSubscription) Events() <-chan ClusterEvent {
+	return s.events
+}
+
+// Stop stops the subscription, and waits for the current poll to abort
+func (s *ClusterEventSubscription) Stop() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *ClusterEventSubscription) run(ctx context.Context) {
+	defer close(s.done)
+	defer close(s.events)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for {
+		s.poll(ctx)
+		select {
+		case <-ctx.Done():
+			s.log.Info("cluster event subscription stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll fetches the state of the database once, and sends its changes since
+// the last successful poll
+func (s *ClusterEventSubscription) poll(ctx context.Context) {
+	snapshot, err := s.fetchSnapshot()
+	if err != nil {
+		if ctx.Err() == nil {
+			s.emit(ctx, ClusterEvent{Type: ClusterEventPollFailed, Err: err})
+		}
+		return
+	}
+	if s.lastSnapshot != nil {
+		for _, event := range diffClusterSnapshots(s.lastSnapshot, &snapshot) {
+			s.emit(ctx, event)
+		}
+	}
+	s.lastSnapshot = &snapshot
+}
+
+// emit sends an event, unless the subscription is stopped
+func (s *ClusterEventSubscription) emit(ctx context.Context, event ClusterEvent) {
+	event.Time = s.now()
+	select {
+	case s.events <- event:
+	case <-ctx.Done():
+	}
+}
+
+func configParameterKey(parameter *ConfigurationParameter) string {
+	return fmt.Sprintf("%s|%s|%s|%s", parameter.Sandbox, parameter.ConfigParameter, parameter.Level, parameter.NodeName)
+}
+
+// diffClusterSnapshots returns the events between two states of a database,
+// sorted by node or parameter. The nodes that are added or removed between
+// the states have no events.
+func diffClusterSnapshots(oldSnapshot, newSnapshot *clusterSnapshot) []ClusterEvent {
+	var events []ClusterEvent
+
+	nodeNames := maps.Keys(newSnapshot.nodes)
+	slices.Sort(nodeNames)
+	for _, nodeName := range nodeNames {
+		newNode := newSnapshot.nodes[nodeName]
+		oldNode, ok := oldSnapshot.nodes[nodeName]
+		if !ok {
+			continue
+		}
+		if oldNode.State != newNode.State {
+			eventType := ClusterEventNodeStateChanged
+			switch newNode.State {
+			case util.NodeUpState:
+				eventType = ClusterEventNodeUp
+			case util.NodeDownState:
+				eventType = ClusterEventNodeDown
+			}
+			events = append(events, ClusterEvent{Type: eventType, NodeName: nodeName, Host: newNode.Address,
+				OldValue: oldNode.State, NewValue: newNode.State})
+		}
+		if oldNode.Sandbox != newNode.Sandbox {
+			events = append(events, ClusterEvent{Type: ClusterEventSandboxChanged, NodeName: nodeName,
+				Host: newNode.Address, OldValue: oldNode.Sandbox, NewValue: newNode.Sandbox})
+		}
+	}
+
+	// the parameters are not compared if either state did not poll them
+	if oldSnapshot.configParameters == nil || newSnapshot.configParameters == nil {
+		return events
+	}
+	keys := maps.Keys(oldSnapshot.configParameters)
+	for key := range newSnapshot.configParameters {
+		if _, ok := oldSnapshot.configParameters[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		oldParameter, hasOld := oldSnapshot.configParameters[key]
+		newParameter, hasNew := newSnapshot.configParameters[key]
+		// only the parameters that differ from their defaults are listed, so
+		// a missing parameter has its default value
+		switch {
+		case !hasOld:
+			oldParameter = newParameter
+			oldParameter.CurrentValue = newParameter.DefaultValue
+		case !hasNew:
+			newParameter = oldParameter
+			newParameter.CurrentValue = oldParameter.DefaultValue
+		}
+		if oldParameter.CurrentValue == newParameter.CurrentValue {
+			continue
+		}
+		events = append(events, ClusterEvent{Type: ClusterEventConfigParameterChanged, NodeName: newParameter.NodeName,
+			Sandbox: newParameter.Sandbox, ConfigParameter: newParameter.ConfigParameter,
+			OldValue: oldParameter.CurrentValue, NewValue: newParameter.CurrentValue})
+	}
+	return events
+}
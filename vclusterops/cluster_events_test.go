@@ -0,0 +1,110 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestDiffClusterSnapshots(t *testing.T) {
+	oldSnapshot := clusterSnapshot{
+		nodes: map[string]*VCoordinationNode{
+			"v_test_db_node0001": {Name: "v_test_db_node0001", Address: "192.168.1.101", State: util.NodeUpState},
+			"v_test_db_node0002": {Name: "v_test_db_node0002", Address: "192.168.1.102", State: util.NodeUpState},
+			"v_test_db_node0003": {Name: "v_test_db_node0003", Address: "192.168.1.103", State: util.NodeDownState},
+		},
+		configParameters: map[string]ConfigurationParameter{
+			"|MaxClientSessions|DATABASE|": {ConfigParameter: "MaxClientSessions", Level: "DATABASE",
+				CurrentValue: "100", DefaultValue: "50"},
+			"|DepotOperationsForQuery|DATABASE|": {ConfigParameter: "DepotOperationsForQuery", Level: "DATABASE",
+				CurrentValue: "FETCHES", DefaultValue: "ALL"},
+		},
+	}
+	newSnapshot := clusterSnapshot{
+		nodes: map[string]*VCoordinationNode{
+			"v_test_db_node0001": {Name: "v_test_db_node0001", Address: "192.168.1.101", State: util.NodeUpState},
+			"v_test_db_node0002": {Name: "v_test_db_node0002", Address: "192.168.1.102", State: util.NodeDownState},
+			"v_test_db_node0003": {Name: "v_test_db_node0003", Address: "192.168.1.103", State: util.NodeUpState,
+				Sandbox: "sand"},
+			// added nodes have no events
+			"v_test_db_node0004": {Name: "v_test_db_node0004", Address: "192.168.1.104", State: util.NodeUpState},
+		},
+		configParameters: map[string]ConfigurationParameter{
+			"|MaxClientSessions|DATABASE|": {ConfigParameter: "MaxClientSessions", Level: "DATABASE",
+				CurrentValue: "200", DefaultValue: "50"},
+			"sand|EnableDepotWarmingFromPeers|DATABASE|": {Sandbox: "sand", ConfigParameter: "EnableDepotWarmingFromPeers",
+				Level: "DATABASE", CurrentValue: "1", DefaultValue: "0"},
+		},
+	}
+
+	events := diffClusterSnapshots(&oldSnapshot, &newSnapshot)
+	assert.Equal(t, []ClusterEvent{
+		{Type: ClusterEventNodeDown, NodeName: "v_test_db_node0002", Host: "192.168.1.102",
+			OldValue: util.NodeUpState, NewValue: util.NodeDownState},
+		{Type: ClusterEventNodeUp, NodeName: "v_test_db_node0003", Host: "192.168.1.103",
+			OldValue: util.NodeDownState, NewValue: util.NodeUpState},
+		{Type: ClusterEventSandboxChanged, NodeName: "v_test_db_node0003", Host: "192.168.1.103",
+			OldValue: "", NewValue: "sand"},
+		// set in a sandbox
+		{Type: ClusterEventConfigParameterChanged, Sandbox: "sand", ConfigParameter: "EnableDepotWarmingFromPeers",
+			OldValue: "0", NewValue: "1"},
+		// reset to its default
+		{Type: ClusterEventConfigParameterChanged, ConfigParameter: "DepotOperationsForQuery",
+			OldValue: "FETCHES", NewValue: "ALL"},
+		{Type: ClusterEventConfigParameterChanged, ConfigParameter: "MaxClientSessions",
+			OldValue: "100", NewValue: "200"},
+	}, events)
+
+	// the parameters are not compared when they were not polled
+	newSnapshot.configParameters = nil
+	assert.Len(t, diffClusterSnapshots(&oldSnapshot, &newSnapshot), 3)
+}
+
+func TestClusterEventSubscriptionPoll(t *testing.T) {
+	subscription := makeClusterEventSubscription(time.Minute, vlog.Printer{}, func() {})
+	state := util.NodeUpState
+	var fetchErr error
+	subscription.fetchSnapshot = func() (clusterSnapshot, error) {
+		return clusterSnapshot{nodes: map[string]*VCoordinationNode{
+			"v_test_db_node0001": {Name: "v_test_db_node0001", Address: "192.168.1.101", State: state},
+		}}, fetchErr
+	}
+	ctx := context.Background()
+
+	// the first poll only records the state
+	subscription.poll(ctx)
+	assert.Empty(t, subscription.events)
+
+	// a failed poll keeps the last state
+	fetchErr = fmt.Errorf("no UP nodes")
+	subscription.poll(ctx)
+	assert.Equal(t, ClusterEventPollFailed, (<-subscription.events).Type)
+
+	fetchErr = nil
+	state = util.NodeDownState
+	subscription.poll(ctx)
+	event := <-subscription.events
+	assert.Equal(t, ClusterEventNodeDown, event.Type)
+	assert.Equal(t, "v_test_db_node0001", event.NodeName)
+	assert.Empty(t, subscription.events)
+}
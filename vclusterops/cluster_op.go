@@ -21,6 +21,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -74,6 +75,7 @@ const (
 	SuccessCode        = 200
 	MultipleChoiceCode = 300
 	UnauthorizedCode   = 401
+	ForbiddenCode      = 403
 	InternalErrorCode  = 500
 )
 
@@ -84,13 +86,19 @@ type hostHTTPResult struct {
 	statusCode int
 	host       string
 	content    string
-	err        error // This is set if the http response with a status code that is not 2XX
+	err        error         // This is set if the http response with a status code that is not 2XX
+	duration   time.Duration // time spent waiting for the response
+	retryCount int           // number of times the request was sent to the host before this one
 }
 
 type httpsResponseStatus struct {
 	StatusCode int `json:"status"`
 }
 
+func (httpsResponseStatus) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
 const respSuccStatusCode = 0
 
 // The HTTP response with a 401 status code can have several scenarios:
@@ -161,6 +169,12 @@ func (hostResult *hostHTTPResult) isEOF() bool {
 	return hostResult.status == EOF
 }
 
+// isUnreachable returns true if the host did not send a response, e.g., the
+// connection was refused or timed out
+func (hostResult *hostHTTPResult) isUnreachable() bool {
+	return hostResult.isException() || hostResult.isEOF() || hostResult.isTimeout()
+}
+
 // getStatusString converts ResultStatus to string
 func (status resultStatus) getStatusString() string {
 	if status == FAILURE {
@@ -190,6 +204,9 @@ type clusterOp interface {
 	finalize(execContext *opEngineExecContext) error
 	processResult(execContext *opEngineExecContext) error
 	logResponse(host string, result hostHTTPResult)
+	getOpReport() OpReport
+	getOpInfo() OpInfo
+	getWarnings() []OpWarning
 	logPrepare()
 	logExecute()
 	logFinalize()
@@ -211,10 +228,24 @@ type opBase struct {
 	clusterHTTPRequest clusterHTTPRequest
 	skipExecute        bool // This can be set during prepare if we determine no work is needed
 	spinner            *yacspin.Spinner
+	warnings           []OpWarning // warnings emitted by the op, returned to the caller
+	// hosts whose failed results are expected by the op, so they are not reported as host failures
+	expectedFailureHosts map[string]bool
 }
 
 type opResponseMap map[string]string
 
+func (opResponseMap) supportedVersions() []string {
+	return []string{HTTPVersion1, NMAVersion1}
+}
+
+// stringResponse is the response of the endpoints that return a JSON string
+type stringResponse string
+
+func (stringResponse) supportedVersions() []string {
+	return []string{HTTPVersion1, NMAVersion1}
+}
+
 func (op *opBase) getName() string {
 	return op.name
 }
@@ -229,22 +260,22 @@ func (op *opBase) parseAndCheckResponse(host, responseContent string, responseOb
 		op.logger.Error(err, "fail to parse response on host, detail", "host", host)
 		return err
 	}
-	op.logger.Info("JSON response", "host", host, "responseObj", responseObj)
+	op.logger.Info("JSON response", "host", host, "responseObj", redactForLog(responseObj))
 	return nil
 }
 
 func (op *opBase) parseAndCheckMapResponse(host, responseContent string) (opResponseMap, error) {
 	var responseObj opResponseMap
-	err := op.parseAndCheckResponse(host, responseContent, &responseObj)
+	err := op.parseAndValidateResponse(host, responseContent, &responseObj)
 
 	return responseObj, err
 }
 
 func (op *opBase) parseAndCheckStringResponse(host, responseContent string) (string, error) {
-	var responseStr string
-	err := op.parseAndCheckResponse(host, responseContent, &responseStr)
+	var responseStr stringResponse
+	err := op.parseAndValidateResponse(host, responseContent, &responseStr)
 
-	return responseStr, err
+	return string(responseStr), err
 }
 
 func (op *opBase) setClusterHTTPRequestName() {
@@ -268,13 +299,13 @@ func (op *opBase) setupSpinner() {
 		cfg := yacspin.Config{
 			Frequency:         100 * time.Millisecond,
 			CharSet:           yacspin.CharSets[11],
-			Suffix:            " " + op.description,
+			Suffix:            " " + localizeOpDescription(op.name, op.description),
 			SuffixAutoColon:   true,
-			Message:           "in progress",
+			Message:           localize(MsgOpInProgress),
 			StopCharacter:     "✔",
 			StopColors:        []string{"fgGreen"},
 			StopFailCharacter: "✘",
-			StopFailMessage:   "failed",
+			StopFailMessage:   localize(MsgOpFailed),
 			StopFailColors:    []string{"fgRed"},
 		}
 		spinner, err := yacspin.New(cfg)
@@ -342,8 +373,9 @@ func (op *opBase) stopFailSpinnerWithMessage(errMsg string, v ...any) {
 func (op *opBase) logResponse(host string, result hostHTTPResult) {
 	if result.err != nil {
 		op.logger.PrintError("[%s] result from host %s summary %s, details: %+v",
-			op.name, host, result.status.getStatusString(), result.err)
+			op.name, host, result.status.getStatusString(), redactError(result.err))
 	} else {
+		result.content = redactSecrets(result.content)
 		op.logger.Log.Info("Request succeeded",
 			"op name", op.name, "host", host, "details", result)
 	}
@@ -362,14 +394,35 @@ func (op *opBase) logFinalize() {
 }
 
 func (op *opBase) runExecute(execContext *opEngineExecContext) error {
+	op.expectedFailureHosts = nil
 	err := execContext.dispatcher.sendRequest(&op.clusterHTTPRequest, op.spinner)
 	if err != nil {
-		op.logger.Error(err, "Fail to dispatch request, detail", "dispatch request", op.clusterHTTPRequest)
+		op.logger.Error(redactError(err), "Fail to dispatch request, detail",
+			"dispatch request", redactForLog(op.clusterHTTPRequest.RequestCollection))
 		return err
 	}
 	return nil
 }
 
+// markExpectedFailure marks the failed result of the host as an outcome the op
+// expects, e.g., a DOWN node when polling for the nodes to go down, so the
+// result is not reported as a failure of the host
+func (op *opBase) markExpectedFailure(host string) {
+	if op.expectedFailureHosts == nil {
+		op.expectedFailureHosts = make(map[string]bool)
+	}
+	op.expectedFailureHosts[host] = true
+}
+
+// setIdempotencyKey sets the idempotency key of the run on the requests of
+// the op, for the NMA endpoints that deduplicate the retried requests
+func (op *opBase) setIdempotencyKey(execContext *opEngineExecContext) {
+	for host, request := range op.clusterHTTPRequest.RequestCollection {
+		request.IdempotencyKey = execContext.getIdempotencyKey(op.name, host)
+		op.clusterHTTPRequest.RequestCollection[host] = request
+	}
+}
+
 // if found certs in the options, we add the certs to http requests of each instruction
 func (op *opBase) loadCertsIfNeeded(certs *httpsCerts, findCertsInOptions bool) error {
 	if !findCertsInOptions {
@@ -391,6 +444,7 @@ func (op *opBase) loadCertsIfNeeded(certs *httpsCerts, findCertsInOptions bool)
 		request.Certs.key = certs.key
 		request.Certs.cert = certs.cert
 		request.Certs.caCert = certs.caCert
+		request.Certs.provider = certs.provider
 		op.clusterHTTPRequest.RequestCollection[host] = request
 	}
 	return nil
@@ -430,35 +484,6 @@ func (op *opBase) checkResponseStatusCode(resp httpsResponseStatus, host string)
 	return nil
 }
 
-/* Sensitive fields in request body
- */
-type sensitiveFields struct {
-	DBPassword         string            `json:"db_password"`
-	AWSAccessKeyID     string            `json:"aws_access_key_id"`
-	AWSSecretAccessKey string            `json:"aws_secret_access_key"`
-	Parameters         map[string]string `json:"parameters"`
-}
-
-func (maskedData *sensitiveFields) maskSensitiveInfo() {
-	const maskedValue = "******"
-	sensitiveKeyParams := map[string]bool{
-		"awsauth":                 true,
-		"awssessiontoken":         true,
-		"gcsauth":                 true,
-		"azurestoragecredentials": true,
-	}
-	maskedData.DBPassword = maskedValue
-	maskedData.AWSAccessKeyID = maskedValue
-	maskedData.AWSSecretAccessKey = maskedValue
-	for key := range maskedData.Parameters {
-		// Mask the value if the keys are credentials
-		keyLowerCase := strings.ToLower(key)
-		if sensitiveKeyParams[keyLowerCase] {
-			maskedData.Parameters[key] = maskedValue
-		}
-	}
-}
-
 /* Cluster HTTPS ops basic fields
  * which are needed for https requests using password auth
  * specify whether to use password auth explicitly
@@ -499,6 +524,7 @@ type ClusterCommands interface {
 
 	VAddNode(options *VAddNodeOptions) (VCoordinationDatabase, error)
 	VStopNode(options *VStopNodeOptions) error
+	VRollingRestart(options *VRollingRestartOptions) (RollingRestartResult, error)
 	VAddSubcluster(options *VAddSubclusterOptions) error
 	VCreateDatabase(options *VCreateDatabaseOptions) (VCoordinationDatabase, error)
 	VDropDatabase(options *VDropDatabaseOptions) error
@@ -507,22 +533,43 @@ type ClusterCommands interface {
 	VReIP(options *VReIPOptions) error
 	VRemoveNode(options *VRemoveNodeOptions) (VCoordinationDatabase, error)
 	VRemoveSubcluster(removeScOpt *VRemoveScOptions) (VCoordinationDatabase, error)
-	VReviveDatabase(options *VReviveDatabaseOptions) (dbInfo string, vdbPtr *VCoordinationDatabase, err error)
+	VReviveDatabase(options *VReviveDatabaseOptions) (result VReviveDatabaseResult, vdbPtr *VCoordinationDatabase, err error)
 	VSandbox(options *VSandboxOptions) error
 	VScrutinize(options *VScrutinizeOptions) error
 	VShowRestorePoints(options *VShowRestorePointsOptions) (restorePoints []RestorePoint, err error)
 	VStartDatabase(options *VStartDatabaseOptions) (vdbPtr *VCoordinationDatabase, err error)
-	VStartNodes(options *VStartNodesOptions) error
+	VStartNodes(options *VStartNodesOptions) ([]NodeStartResult, error)
 	VStartSubcluster(startScOpt *VStartScOptions) error
 	VStopDatabase(options *VStopDatabaseOptions) error
-	VReplicateDatabase(options *VReplicationDatabaseOptions) error
+	VReplicateDatabase(options *VReplicationDatabaseOptions) (ReplicationStatus, error)
+	VGetReplicationStatus(options *VReplicationStatusDatabaseOptions) (ReplicationStatus, error)
 	VFetchCoordinationDatabase(options *VFetchCoordinationDatabaseOptions) (VCoordinationDatabase, error)
 	VUnsandbox(options *VUnsandboxOptions) error
 	VStopSubcluster(options *VStopSubclusterOptions) error
 	VAlterSubclusterType(options *VAlterSubclusterTypeOptions) error
 	VPromoteSandboxToMain(options *VPromoteSandboxToMainOptions) error
+	VPromoteSandboxToMainPreflight(options *VPromoteSandboxToMainOptions) (PromoteSandboxToMainReport, error)
 	VRenameSubcluster(options *VRenameSubclusterOptions) error
 	VFetchNodesDetails(options *VFetchNodesDetailsOptions) (NodesDetails, error)
+	VFetchNodeStateDetails(options *VFetchNodeStateOptions) (NodeStatesReport, error)
+	VHealthCheck(options *VHealthCheckOptions) (HealthCheckReport, error)
+	VTailLog(options *VTailLogOptions, lines chan<- string) error
+	VManageDepot(options *VManageDepotOptions) ([]DepotActionResult, error)
+	VRebalanceShards(options *VRebalanceShardsOptions) (RebalanceJob, error)
+	VGetRebalanceJobStatus(options *VRebalanceJobStatusOptions) (RebalanceJob, error)
+	VSetCommunalStorageCredentials(options *VSetCommunalStorageCredentialsOptions) ([]ConfigurationParameterStatus, error)
+	VPurgeTrash(options *VPurgeTrashOptions) (map[string][]string, error)
+	VUpgradeDatabase(options *VUpgradeDatabaseOptions) (PlanReport, error)
+	VSetClusterReadOnly(options *VSetClusterReadOnlyOptions) error
+	StartNodeWatchdog(options *VNodeWatchdogOptions) (*NodeWatchdog, error)
+	SubscribeClusterEvents(options *VClusterEventOptions) (*ClusterEventSubscription, error)
+	VRotateTLSCerts(options *VRotateTLSCertsOptions) error
+	VSetTLSConfig(options *VSetTLSConfigOptions) error
+	VDeployServerCertificate(options *VDeployServerCertificateOptions) error
+	VCreateArchive(options *VCreateArchiveOptions) error
+	VSaveRestorePoint(options *VSaveRestorePointOptions) error
+	VRemoveRestorePoint(options *VRemoveRestorePointOptions) error
+	VGetClusterLease(options *VGetClusterLeaseOptions) (ClusterLease, error)
 }
 
 type VClusterCommandsLogger struct {
@@ -561,4 +608,43 @@ func (vcc VClusterCommandsLogger) PrintError(msg string, v ...any) {
 // (e.g. create db, add node, etc.).
 type VClusterCommands struct {
 	VClusterCommandsLogger
+	// the context of the commands, see WithContext
+	ctx context.Context
+	// the settings below are kept out of ctx, so that WithContext does not
+	// drop them, and are added to the context by Context()
+	hooks            []OpHook
+	tracer           Tracer
+	audit            *auditConfig
+	transportWrapper TransportWrapper
+}
+
+// WithContext returns a copy of vcc whose commands run with the given context.
+// Canceling the context, or reaching its deadline, aborts the in-flight HTTP
+// requests of a command and stops it before its next step.
+func (vcc VClusterCommands) WithContext(ctx context.Context) VClusterCommands {
+	vcc.ctx = ctx
+	return vcc
+}
+
+// Context returns the context of the commands, with the settings of the
+// commands, e.g., the hooks, the tracer, the audit sink and the transport
+// wrapper. It is never nil, and defaults to context.Background().
+func (vcc VClusterCommands) Context() context.Context {
+	ctx := vcc.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(vcc.hooks) > 0 {
+		ctx = context.WithValue(ctx, opHooksKey{}, vcc.hooks)
+	}
+	if vcc.tracer != nil {
+		ctx = context.WithValue(ctx, tracerKey{}, vcc.tracer)
+	}
+	if vcc.audit != nil {
+		ctx = context.WithValue(ctx, auditKey{}, *vcc.audit)
+	}
+	if vcc.transportWrapper != nil {
+		ctx = context.WithValue(ctx, transportWrapperKey{}, vcc.transportWrapper)
+	}
+	return ctx
 }
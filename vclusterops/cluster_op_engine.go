@@ -16,7 +16,11 @@
 package vclusterops
 
 import (
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"time"
 
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
@@ -25,53 +29,214 @@ type VClusterOpEngine struct {
 	instructions []clusterOp
 	certs        *httpsCerts
 	execContext  *opEngineExecContext
+	// collects the per-host request details of each op
+	report *OperationReport
+	// how the requests that fail with a transient error are retried
+	retryPolicy *RetryPolicy
+	// limits how long the requests, the ops, and the run can take
+	timeoutPolicy *TimeoutPolicy
+	// called around the ops, registered through VClusterCommands.WithHooks
+	hooks []OpHook
+	// maximum number of hosts that an op sends requests to at a time, 0 for no limit
+	maxConcurrentHosts int
+	// how the certificates of the hosts are verified, nil to skip the verification
+	tlsVerification *TLSVerificationPolicy
+	// configures the connections that the ops share
+	transportPolicy *TransportPolicy
+	// how the hosts are reached, nil to connect to them directly
+	proxyPolicy *ProxyPolicy
+	// wraps the transports, nil for the one registered through
+	// VClusterCommands.WithTransportWrapper, if any
+	wrapTransport TransportWrapper
+	// the hostnames that the requests are sent to instead of the addresses
+	// of their hosts, keyed by address
+	hostnames map[string]string
+	// the addresses of the nodes of a multi-homed cluster
+	addressBook *AddressBook
+	// supplies the password of the requests that use one but do not have it
+	credentialProvider CredentialProvider
+	// authenticates the requests that use a password but do not have it,
+	// instead of credentialProvider
+	bearerToken *bearerTokenSource
+	// the progress of the run, which is saved to checkpointPath when an op
+	// fails. It is nil when checkpointing is disabled.
+	checkpoint     *opEngineCheckpoint
+	checkpointPath string
+	// the options of the command, nil for the engines not built from options
+	options *DatabaseOptions
+	// collects the audit record of the run, nil when the run is not audited
+	audit *engineAudit
+	// the connections shared with the other commands of a plan, which the run
+	// does not close. It is nil for the run to open its own connections.
+	sharedTransports *transportCache
+	// whether the changes of the completed ops are kept when a later op fails
+	rollbackDisabled bool
+	// undo the changes of the ops that have run, in the order of the ops
+	compensations []opCompensation
 }
 
 func makeClusterOpEngine(instructions []clusterOp, certs *httpsCerts) VClusterOpEngine {
 	newClusterOpEngine := VClusterOpEngine{}
 	newClusterOpEngine.instructions = instructions
 	newClusterOpEngine.certs = certs
+	newClusterOpEngine.report = &OperationReport{}
 	return newClusterOpEngine
 }
 
+// generateIdempotencyKey generates a random key that identifies a run
+func generateIdempotencyKey() (string, error) {
+	const keySize = 16
+	bytes := make([]byte, keySize)
+	if _, err := crand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes for the idempotency key: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
 func (opEngine *VClusterOpEngine) shouldGetCertsFromOptions() bool {
-	return (opEngine.certs.key != "" && opEngine.certs.cert != "")
+	return opEngine.certs.provider != nil || (opEngine.certs.key != "" && opEngine.certs.cert != "")
 }
 
-func (opEngine *VClusterOpEngine) run(logger vlog.Printer) error {
-	execContext := makeOpEngineExecContext(logger)
+// run runs the instructions in order. Canceling ctx, or reaching the timeout
+// of the run, aborts the in-flight requests of the running op, and the
+// remaining ops are not run.
+func (opEngine *VClusterOpEngine) run(ctx context.Context, logger vlog.Printer) error {
+	var engineTimeout time.Duration
+	if opEngine.timeoutPolicy != nil {
+		engineTimeout = opEngine.timeoutPolicy.EngineTimeout
+	}
+	ctx, cancel := withTimeout(ctx, engineTimeout)
+	defer cancel()
+
+	opEngine.hooks = getOpHooks(ctx)
+	idempotencyKey, err := generateIdempotencyKey()
+	if err != nil {
+		return err
+	}
+	// the logs of the run carry its ID, so that the entries of concurrent runs
+	// can be told apart in the structured logs
+	logger = logger.WithValues("runID", idempotencyKey)
+	// the ops and their requests are traced as children of the run
+	ctx, span := getTracer(ctx).Start(ctx, runSpanName)
+	span.SetAttribute(idempotencyKeyAttribute, idempotencyKey)
+	opEngine.audit = startEngineAudit(ctx, idempotencyKey, opEngine.options)
+
+	execContext := makeOpEngineExecContext(ctx, logger)
+	execContext.idempotencyKey = idempotencyKey
+	execContext.dispatcher.retryPolicy = opEngine.retryPolicy
+	execContext.dispatcher.requestTimeout = opEngine.timeoutPolicy.getRequestTimeoutSeconds()
+	execContext.dispatcher.maxConcurrentHosts = opEngine.maxConcurrentHosts
+	execContext.dispatcher.tlsVerification = opEngine.tlsVerification
+	execContext.dispatcher.credentialProvider = opEngine.credentialProvider
+	execContext.dispatcher.bearerToken = opEngine.bearerToken
+	// the ops reuse the connections to the hosts until the end of the run
+	if opEngine.sharedTransports != nil {
+		execContext.dispatcher.transports = opEngine.sharedTransports
+	} else {
+		wrapTransport := opEngine.wrapTransport
+		if wrapTransport == nil {
+			wrapTransport = getTransportWrapper(ctx)
+		}
+		execContext.dispatcher.transports = makeTransportCache(opEngine.transportPolicy,
+			opEngine.proxyPolicy, wrapTransport)
+		defer execContext.dispatcher.transports.close()
+	}
+	execContext.dispatcher.transports.hostnames = opEngine.hostnames
+	execContext.dispatcher.transports.addressBook = opEngine.addressBook
 	opEngine.execContext = &execContext
 
-	return opEngine.runWithExecContext(logger, &execContext)
+	err = opEngine.runWithExecContext(logger, &execContext)
+	err = opEngine.checkPrivilegeError(err)
+	opEngine.audit.finish(ctx, logger, err)
+	span.End(err)
+	return err
 }
 
 func (opEngine *VClusterOpEngine) runWithExecContext(logger vlog.Printer, execContext *opEngineExecContext) error {
 	findCertsInOptions := opEngine.shouldGetCertsFromOptions()
 
-	for _, op := range opEngine.instructions {
-		err := opEngine.runInstruction(logger, execContext, op, findCertsInOptions)
+	resumePoint := 0
+	if opEngine.checkpoint != nil {
+		resumePoint = opEngine.checkpoint.getResumePoint(opEngine.instructions)
+		if resumePoint > 0 {
+			execContext.restoreCheckpoint(&opEngine.checkpoint.ExecContext)
+			logger.PrintInfo("Resuming from the checkpoint of a previous run, %d completed steps are skipped", resumePoint)
+		}
+	}
+
+	for i, op := range opEngine.instructions {
+		if i < resumePoint {
+			logger.Info("skip the op completed in a previous run", "op", op.getName())
+			continue
+		}
+		if err := execContext.ctx.Err(); err != nil {
+			opEngine.saveCheckpoint(logger, execContext)
+			opEngine.compensate(logger, execContext)
+			return fmt.Errorf("%s is not run because the operation is canceled: %w", op.getName(), err)
+		}
+		execContext.instructionIndex = i
+		err := opEngine.runInstructionWithFallback(logger, execContext, op, findCertsInOptions)
+		opEngine.registerCompensation(logger, op)
 		if err != nil {
+			opEngine.saveCheckpoint(logger, execContext)
+			opEngine.compensate(logger, execContext)
 			return err
 		}
+		if opEngine.checkpoint != nil {
+			opEngine.checkpoint.CompletedOps = append(opEngine.checkpoint.CompletedOps, op.getName())
+		}
 	}
 
+	if opEngine.checkpoint != nil {
+		if err := removeCheckpoint(opEngine.checkpointPath); err != nil {
+			logger.PrintWarning(err.Error())
+		}
+	}
 	return nil
 }
 
+// saveCheckpoint saves the progress of a failed run. Failing to save it does
+// not fail the run, as the command can still be run again from the start.
+func (opEngine *VClusterOpEngine) saveCheckpoint(logger vlog.Printer, execContext *opEngineExecContext) {
+	if opEngine.checkpoint == nil {
+		return
+	}
+	opEngine.checkpoint.ExecContext = execContext.saveCheckpoint()
+	if err := opEngine.checkpoint.save(opEngine.checkpointPath); err != nil {
+		logger.PrintWarning(err.Error())
+		return
+	}
+	logger.PrintInfo("Saved the progress to %s, run the command again to resume from the failed step", opEngine.checkpointPath)
+}
+
 func (opEngine *VClusterOpEngine) runInstruction(
 	logger vlog.Printer, execContext *opEngineExecContext,
-	op clusterOp, findCertsInOptions bool) error {
+	op clusterOp, findCertsInOptions bool) (err error) {
 	op.setLogger(logger)
 	op.setupBasicInfo()
 	op.setupSpinner()
 	defer op.cleanupSpinner()
+	// warnings are collected even if the op fails
+	defer func() { opEngine.report.addWarnings(op.getWarnings()) }()
+
+	// the op runs with its own timeout, after which its requests are aborted
+	engineCtx := execContext.ctx
+	spanCtx, span := getTracer(engineCtx).Start(engineCtx, op.getName())
+	defer func() { span.End(err) }()
+	opCtx, cancel := withTimeout(spanCtx, opEngine.timeoutPolicy.getOpTimeout(op.getName()))
+	execContext.setContext(opCtx)
+	defer func() {
+		cancel()
+		execContext.setContext(engineCtx)
+	}()
 
 	op.logPrepare()
-	err := op.prepare(execContext)
+	err = op.prepare(execContext)
 	if err != nil {
 		return fmt.Errorf("prepare %s failed, details: %w", op.getName(), err)
 	}
 
+	span.SetAttribute(opSkippedAttribute, op.isSkipExecute())
 	if !op.isSkipExecute() {
 		// start the progress spinner
 		op.startSpinner()
@@ -84,14 +249,31 @@ func (opEngine *VClusterOpEngine) runInstruction(
 			return fmt.Errorf("loadCertsIfNeeded for %s failed, details: %w", op.getName(), err)
 		}
 
+		opInfo := op.getOpInfo()
+		span.SetAttribute(opDescriptionAttribute, opInfo.Description)
+		opEngine.audit.addOp(&opInfo)
+		err = runBeforeOpHooks(execContext.ctx, opEngine.hooks, opInfo)
+		if err != nil {
+			op.stopFailSpinnerWithMessage(err.Error())
+			return err
+		}
+
 		// execute an instruction
 		op.logExecute()
+		executeStart := time.Now()
 		err = op.execute(execContext)
+		opInfo.Duration = time.Since(executeStart)
+		opReport := op.getOpReport()
+		if fallbackOp, ok := op.(initiatorFallbackOp); ok {
+			opReport.Initiator = fallbackOp.getInitiator()
+		}
+		opEngine.report.addOpReport(opReport)
+		runAfterOpHooks(execContext.ctx, opEngine.hooks, opInfo, &opReport, err)
 		if err != nil {
 			// here we do not return an error as the spinner error does not
 			// affect the functionality
 			op.stopFailSpinner()
-			return fmt.Errorf("execute %s failed, details: %w", op.getName(), err)
+			return withHostErrors(fmt.Errorf("execute %s failed, details: %w", op.getName(), redactError(err)), opReport.hostErrors())
 		}
 	}
 
@@ -15,11 +15,22 @@
 
 package vclusterops
 
-import "github.com/vertica/vcluster/vclusterops/vlog"
+import (
+	"context"
+	"fmt"
 
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// opEngineExecContext holds the data that ops exchange during an engine run.
+// Ops should go through the typed accessors below (e.g., UpHosts(),
+// StartupCommands(), RestorePoints()) rather than the bare fields, so that an
+// op running before the data is produced gets an error instead of a nil value.
 type opEngineExecContext struct {
+	// canceling it aborts the ops and their in-flight requests
+	ctx             context.Context
 	dispatcher      requestDispatcher
-	networkProfiles map[string]networkProfile
+	networkProfiles map[string]NetworkProfile
 	nmaVDatabase    nmaVDatabase
 	upHosts         []string // a sorted host list that contains all up nodes
 	nodesInfo       []NodeInfo
@@ -34,15 +45,115 @@ type opEngineExecContext struct {
 	primaryHostsWithLatestCatalog []string
 	startupCommandMap             map[string][]string // store start up command map to start nodes
 	dbInfo                        string              // store the db info that retrieved from communal storage
+	clusterLeaseExpiration        string              // store the cluster lease expiration in the description file
+	dbDescription                 *DBDescription      // store the typed content of the description file
 	restorePoints                 []RestorePoint      // store list existing restore points that queried from an archive
 	systemTableList               systemTableListInfo // used for staging system tables
+	configParameterValues         map[string]string   // store the values of the config parameters queried from the database
+	// store the outcome of setting each config parameter
+	configParameterStatuses []ConfigurationParameterStatus
+	// store the non-default config parameters listed from the database and the sandboxes
+	configParameters []ConfigurationParameter
+	// identifies the requests of the run to the NMA endpoints that deduplicate them
+	idempotencyKey string
+	// index of the running instruction, which keeps the idempotency keys of
+	// the ops of the same name apart
+	instructionIndex int
 	// hosts on which the wrong authentication occurred
 	hostsWithWrongAuth []string
+	// store the outcome of starting each node, keyed by host
+	nodeStartResults map[string]*NodeStartResult
 }
 
-func makeOpEngineExecContext(logger vlog.Printer) opEngineExecContext {
+func makeOpEngineExecContext(ctx context.Context, logger vlog.Printer) opEngineExecContext {
 	newOpEngineExecContext := opEngineExecContext{}
-	newOpEngineExecContext.dispatcher = makeHTTPRequestDispatcher(logger)
+	newOpEngineExecContext.ctx = ctx
+	newOpEngineExecContext.dispatcher = makeHTTPRequestDispatcher(ctx, logger)
 
 	return newOpEngineExecContext
 }
+
+// UpHosts returns the sorted list of UP hosts found by a previous op, and
+// whether any UP host has been found
+func (execContext *opEngineExecContext) UpHosts() ([]string, bool) {
+	return execContext.upHosts, len(execContext.upHosts) > 0
+}
+
+// getIdempotencyKey returns the idempotency key of the request of an op to a
+// host, which is the same for the retries of the request within the run, and
+// when the run resumes from a checkpoint
+func (execContext *opEngineExecContext) getIdempotencyKey(opName, host string) string {
+	return fmt.Sprintf("%s-%d-%s-%s", execContext.idempotencyKey, execContext.instructionIndex, opName, host)
+}
+
+// getNodeStartResult returns the start outcome of a host that ops fill in,
+// adding it if needed
+func (execContext *opEngineExecContext) getNodeStartResult(host string) *NodeStartResult {
+	if execContext.nodeStartResults == nil {
+		execContext.nodeStartResults = make(map[string]*NodeStartResult)
+	}
+	result, ok := execContext.nodeStartResults[host]
+	if !ok {
+		result = &NodeStartResult{Host: host}
+		execContext.nodeStartResults[host] = result
+	}
+	return result
+}
+
+// setContext replaces the context that the ops and their requests run with
+func (execContext *opEngineExecContext) setContext(ctx context.Context) {
+	execContext.ctx = ctx
+	execContext.dispatcher.ctx = ctx
+}
+
+// SetUpHosts saves the UP hosts found by an op
+func (execContext *opEngineExecContext) SetUpHosts(upHosts []string) {
+	execContext.upHosts = upHosts
+}
+
+// requireUpHosts returns the UP hosts, or an error naming the op if no
+// previous op has found any UP host
+func (execContext *opEngineExecContext) requireUpHosts(opName string) ([]string, error) {
+	upHosts, ok := execContext.UpHosts()
+	if !ok {
+		return nil, categorizeError(ErrNodeDown, fmt.Errorf(`[%s] Cannot find any up hosts in OpEngineExecContext`, opName))
+	}
+	return upHosts, nil
+}
+
+// StartupCommands returns the map from node name to startup command, and
+// whether the map has been retrieved from a running database
+func (execContext *opEngineExecContext) StartupCommands() (map[string][]string, bool) {
+	return execContext.startupCommandMap, execContext.startupCommandMap != nil
+}
+
+// SetStartupCommands saves the startup commands retrieved by an op
+func (execContext *opEngineExecContext) SetStartupCommands(startupCommands map[string][]string) {
+	execContext.startupCommandMap = startupCommands
+}
+
+// NetworkProfiles returns the network profiles of the hosts, keyed by host,
+// and whether they have been retrieved
+func (execContext *opEngineExecContext) NetworkProfiles() (map[string]NetworkProfile, bool) {
+	return execContext.networkProfiles, execContext.networkProfiles != nil
+}
+
+// SetNetworkProfiles saves the network profiles retrieved by an op
+func (execContext *opEngineExecContext) SetNetworkProfiles(profiles map[string]NetworkProfile) {
+	execContext.networkProfiles = profiles
+}
+
+// RestorePoints returns the restore points listed from an archive, and whether
+// they have been listed. An empty list with true means the archive has no
+// matching restore point.
+func (execContext *opEngineExecContext) RestorePoints() ([]RestorePoint, bool) {
+	return execContext.restorePoints, execContext.restorePoints != nil
+}
+
+// SetRestorePoints saves the restore points listed by an op
+func (execContext *opEngineExecContext) SetRestorePoints(restorePoints []RestorePoint) {
+	if restorePoints == nil {
+		restorePoints = []RestorePoint{}
+	}
+	execContext.restorePoints = restorePoints
+}
@@ -16,8 +16,15 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vertica/vcluster/vclusterops/vlog"
@@ -75,7 +82,7 @@ func TestSkipExecuteOp(t *testing.T) {
 	instructions := []clusterOp{&opWithSkipDisabled, &opWithSkipEnabled}
 	certs := httpsCerts{key: "key", cert: "cert", caCert: "ca-cert"}
 	opEngn := makeClusterOpEngine(instructions, &certs)
-	err := opEngn.run(vlog.Printer{})
+	err := opEngn.run(context.Background(), vlog.Printer{})
 	assert.Equal(t, nil, err)
 	assert.True(t, opWithSkipDisabled.calledPrepare)
 	assert.True(t, opWithSkipDisabled.calledExecute)
@@ -84,3 +91,213 @@ func TestSkipExecuteOp(t *testing.T) {
 	assert.False(t, opWithSkipEnabled.calledExecute)
 	assert.True(t, opWithSkipEnabled.calledFinalize)
 }
+
+func TestExecContextAccessors(t *testing.T) {
+	execContext := makeOpEngineExecContext(context.Background(), vlog.Printer{})
+
+	// nothing has been produced yet
+	_, ok := execContext.UpHosts()
+	assert.False(t, ok)
+	_, err := execContext.requireUpHosts("testOp")
+	assert.ErrorContains(t, err, "[testOp] Cannot find any up hosts")
+	_, ok = execContext.StartupCommands()
+	assert.False(t, ok)
+	_, ok = execContext.RestorePoints()
+	assert.False(t, ok)
+
+	execContext.SetUpHosts([]string{"host1", "host2"})
+	upHosts, err := execContext.requireUpHosts("testOp")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"host1", "host2"}, upHosts)
+
+	execContext.SetStartupCommands(map[string][]string{"v_db_node0001": {"/opt/vertica/bin/vertica"}})
+	startupCommands, ok := execContext.StartupCommands()
+	assert.True(t, ok)
+	assert.Len(t, startupCommands, 1)
+
+	// an empty list of restore points is still a valid listing result
+	execContext.SetRestorePoints(nil)
+	restorePoints, ok := execContext.RestorePoints()
+	assert.True(t, ok)
+	assert.Empty(t, restorePoints)
+}
+
+type mockOpWithResults struct {
+	mockOp
+	results map[string]hostHTTPResult
+}
+
+func (m *mockOpWithResults) execute(_ *opEngineExecContext) error {
+	m.clusterHTTPRequest.ResultCollection = m.results
+	m.addWarning("host1", "Skipping host %s", "host1")
+	return nil
+}
+
+func TestOperationReportCollection(t *testing.T) {
+	op := mockOpWithResults{
+		mockOp: makeMockOp(false),
+		results: map[string]hostHTTPResult{
+			"host2": {host: "host2", statusCode: SuccessCode, duration: 3 * time.Second, retryCount: 2},
+			"host1": {host: "host1", statusCode: InternalErrorCode, duration: time.Second},
+		},
+	}
+	skippedOp := makeMockOp(true)
+	options := DatabaseOptionsFactory()
+	opEngn := options.makeClusterOpEngine([]clusterOp{&op, &skippedOp})
+	err := opEngn.run(context.Background(), vlog.Printer{})
+	assert.NoError(t, err)
+
+	// the skipped op did not send any request so it should not be in the report
+	report := options.GetOperationReport()
+	assert.Len(t, report.Ops, 1)
+	assert.Equal(t, op.name, report.Ops[0].OpName)
+	assert.Equal(t, "host1", report.Ops[0].Hosts[0].Host)
+	assert.Equal(t, InternalErrorCode, report.Ops[0].Hosts[0].StatusCode)
+
+	opName, slowest, found := report.GetSlowestRequest()
+	assert.True(t, found)
+	assert.Equal(t, op.name, opName)
+	assert.Equal(t, "host2", slowest.Host)
+	assert.Equal(t, 2, slowest.RetryCount)
+	assert.Len(t, report.GetHostReports("host2"), 1)
+
+	// warnings are returned apart from the request details
+	assert.Equal(t, []OpWarning{{OpName: op.name, Host: "host1", Message: "Skipping host host1"}}, report.Warnings)
+}
+
+func TestCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// no op is run after the context is canceled
+	op := makeMockOp(false)
+	opEngn := makeClusterOpEngine([]clusterOp{&op}, &httpsCerts{})
+	err := opEngn.run(ctx, vlog.Printer{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, op.calledPrepare)
+
+	// the polling stops waiting when the context is canceled
+	err = sleepWithContext(ctx, time.Hour)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// the commands default to a background context
+	vcc := VClusterCommands{}
+	assert.NoError(t, vcc.Context().Err())
+	assert.ErrorIs(t, vcc.WithContext(ctx).Context().Err(), context.Canceled)
+}
+
+type mockFailingOp struct {
+	mockOpWithResults
+}
+
+func (m *mockFailingOp) execute(_ *opEngineExecContext) error {
+	m.clusterHTTPRequest.ResultCollection = m.results
+	return fmt.Errorf("[%s] HTTPS call failed on host host1", m.name)
+}
+
+func TestHostErrorReporting(t *testing.T) {
+	okOp := mockOpWithResults{
+		mockOp: makeMockOp(false),
+		results: map[string]hostHTTPResult{
+			"host1": {host: "host1", statusCode: SuccessCode},
+			"host2": {host: "host2", statusCode: SuccessCode},
+			"host3": {host: "host3", statusCode: SuccessCode},
+		},
+	}
+	connErr := fmt.Errorf("internal error")
+	failingOp := mockFailingOp{mockOpWithResults{
+		mockOp: makeMockOp(false),
+		results: map[string]hostHTTPResult{
+			"host1": {host: "host1", statusCode: InternalErrorCode, content: `{"detail": "boom"}`, err: connErr},
+			"host3": {host: "host3", statusCode: SuccessCode},
+		},
+	}}
+	failingOp.name = "failing-op"
+	options := DatabaseOptionsFactory()
+	opEngn := options.makeClusterOpEngine([]clusterOp{&okOp, &failingOp})
+	err := opEngn.run(context.Background(), vlog.Printer{})
+	assert.ErrorContains(t, err, "execute failing-op failed")
+
+	// the error of the op carries the failures of the hosts
+	var hostErr *HostError
+	assert.ErrorAs(t, err, &hostErr)
+	assert.Equal(t, "failing-op", hostErr.OpName)
+	assert.Equal(t, "host1", hostErr.Host)
+	assert.Equal(t, InternalErrorCode, hostErr.StatusCode)
+	assert.Equal(t, `{"detail": "boom"}`, hostErr.Body)
+	assert.ErrorIs(t, err, connErr)
+
+	report := options.GetOperationReport()
+	assert.Len(t, report.HostErrors(), 1)
+	assert.Equal(t, []string{"host1"}, report.FailedHosts())
+	assert.Equal(t, []string{"host2", "host3"}, report.SucceededHosts())
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	key1, err := generateIdempotencyKey()
+	assert.NoError(t, err)
+	key2, err := generateIdempotencyKey()
+	assert.NoError(t, err)
+	assert.Len(t, key1, 32)
+	assert.NotEqual(t, key1, key2)
+
+	// each request has its own key, built from the run, the instruction and the host
+	execContext := makeOpEngineExecContext(context.Background(), vlog.Printer{})
+	execContext.idempotencyKey = key1
+	execContext.instructionIndex = 2
+	hostNodeMap := makeVHostNodeMap()
+	hostNodeMap["host1"] = &VCoordinationNode{CatalogPath: "/data/v_test_db_node0001_catalog"}
+	hostNodeMap["host2"] = &VCoordinationNode{CatalogPath: "/data/v_test_db_node0002_catalog"}
+	op, err := makeNMAPrepareDirectoriesOp(hostNodeMap, false, false)
+	assert.NoError(t, err)
+	op.setupBasicInfo()
+	assert.NoError(t, op.prepare(&execContext))
+	assert.Len(t, op.clusterHTTPRequest.RequestCollection, 2)
+	for host, request := range op.clusterHTTPRequest.RequestCollection {
+		assert.Equal(t, key1+"-2-"+op.name+"-"+host, request.IdempotencyKey)
+	}
+
+	// the same op at another step of the run gets other keys
+	assert.NotEqual(t, execContext.getIdempotencyKey(op.name, "host1"), key1+"-3-"+op.name+"-host1")
+	execContext.instructionIndex = 3
+	assert.Equal(t, key1+"-3-"+op.name+"-host1", execContext.getIdempotencyKey(op.name, "host1"))
+}
+
+// TestEnginesAreBuiltFromOptions checks that the commands build their engines
+// with DatabaseOptions.makeClusterOpEngine, which applies the authentication,
+// the policies and the report of the options
+func TestEnginesAreBuiltFromOptions(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	assert.NoError(t, err)
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		assert.NoError(t, err)
+		for _, decl := range f.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+			// the only functions that may build an engine by themselves
+			if funcDecl.Name.Name == "makeClusterOpEngine" {
+				continue
+			}
+			ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+				switch node := n.(type) {
+				case *ast.CallExpr:
+					if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "makeClusterOpEngine" {
+						t.Errorf("%s: %s builds an engine without the options", fset.Position(node.Pos()), funcDecl.Name.Name)
+					}
+				case *ast.CompositeLit:
+					if ident, ok := node.Type.(*ast.Ident); ok && ident.Name == "VClusterOpEngine" {
+						t.Errorf("%s: %s builds an engine without the options", fset.Position(node.Pos()), funcDecl.Name.Name)
+					}
+				}
+				return true
+			})
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// compensatingOp is implemented by the ops that make changes on the hosts
+// that a failed run should not leave behind, such as the directories that
+// nmaPrepareDirectoriesOp creates
+type compensatingOp interface {
+	clusterOp
+	// getCompensation returns the op that undoes the changes made by the op,
+	// or nil when there is nothing to undo. It is called after the op runs,
+	// even if the op failed, as a failed op can still have changed some hosts.
+	getCompensation() (clusterOp, error)
+}
+
+// registerCompensation keeps the compensation of an op that has run, so that
+// it is run if a later op fails
+func (opEngine *VClusterOpEngine) registerCompensation(logger vlog.Printer, op clusterOp) {
+	compOp, ok := op.(compensatingOp)
+	if !ok || !opEngine.shouldCompensate() {
+		return
+	}
+	compensation, err := compOp.getCompensation()
+	if err != nil {
+		logger.PrintWarning("Fail to produce the rollback of %s, its changes will not be rolled back: %s",
+			op.getName(), err.Error())
+		return
+	}
+	if compensation != nil {
+		opEngine.compensations = append(opEngine.compensations, opCompensation{opName: op.getName(), op: compensation})
+	}
+}
+
+// opCompensation is the op that undoes the changes made by the named op
+type opCompensation struct {
+	opName string
+	op     clusterOp
+}
+
+// shouldCompensate tells whether the changes of the ops are rolled back when
+// a later op fails. A resumable run keeps them, as the ops completed before
+// the failure are skipped when the command is run again.
+func (opEngine *VClusterOpEngine) shouldCompensate() bool {
+	return !opEngine.rollbackDisabled && opEngine.checkpoint == nil
+}
+
+// compensate runs the compensations of the ops that have run, in the reverse
+// order of the ops. They run even if the run was canceled, and a failed
+// compensation does not stop the others, as each one rolls back a different op.
+func (opEngine *VClusterOpEngine) compensate(logger vlog.Printer, execContext *opEngineExecContext) {
+	if len(opEngine.compensations) == 0 {
+		return
+	}
+	logger.PrintInfo("Rolling back the changes of %d steps", len(opEngine.compensations))
+
+	engineCtx := execContext.ctx
+	execContext.setContext(detachedContext{engineCtx})
+	defer execContext.setContext(engineCtx)
+
+	findCertsInOptions := opEngine.shouldGetCertsFromOptions()
+	for i := len(opEngine.compensations) - 1; i >= 0; i-- {
+		compensation := opEngine.compensations[i]
+		err := opEngine.runInstruction(logger, execContext, compensation.op, findCertsInOptions)
+		if err != nil {
+			logger.PrintWarning("Fail to roll back the changes of %s: %s", compensation.opName, err.Error())
+			opEngine.report.addWarnings([]OpWarning{{OpName: compensation.opName,
+				Message: "fail to roll back the changes of the op: " + err.Error()}})
+			continue
+		}
+		opEngine.report.RolledBackOps = append(opEngine.report.RolledBackOps, compensation.opName)
+	}
+	opEngine.compensations = nil
+}
+
+// detachedContext keeps the values of its parent but not its cancellation,
+// so that the changes of a canceled run can still be rolled back
+type detachedContext struct {
+	parent context.Context
+}
+
+func (ctx detachedContext) Deadline() (deadline time.Time, ok bool) {
+	return time.Time{}, false
+}
+
+func (ctx detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (ctx detachedContext) Err() error {
+	return nil
+}
+
+func (ctx detachedContext) Value(key any) any {
+	return ctx.parent.Value(key)
+}
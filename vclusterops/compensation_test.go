@@ -0,0 +1,143 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type mockRecordingOp struct {
+	mockOp
+	executed *[]string
+}
+
+func makeMockRecordingOp(name string, executed *[]string) *mockRecordingOp {
+	op := &mockRecordingOp{mockOp: makeMockOp(false), executed: executed}
+	op.name = name
+	return op
+}
+
+func (m *mockRecordingOp) execute(_ *opEngineExecContext) error {
+	*m.executed = append(*m.executed, m.name)
+	return nil
+}
+
+type mockCompensatingOp struct {
+	mockRecordingOp
+	compensation *mockRecordingOp
+}
+
+func (m *mockCompensatingOp) getCompensation() (clusterOp, error) {
+	if m.compensation == nil {
+		return nil, nil
+	}
+	return m.compensation, nil
+}
+
+func TestCompensation(t *testing.T) {
+	var executed []string
+	makeInstructions := func() []clusterOp {
+		op1 := &mockCompensatingOp{mockRecordingOp: *makeMockRecordingOp("op1", &executed),
+			compensation: makeMockRecordingOp("undo-op1", &executed)}
+		// an op with nothing to undo
+		op2 := &mockCompensatingOp{mockRecordingOp: *makeMockRecordingOp("op2", &executed)}
+		op3 := &mockCompensatingOp{mockRecordingOp: *makeMockRecordingOp("op3", &executed),
+			compensation: makeMockRecordingOp("undo-op3", &executed)}
+		failingOp := &mockFailingOp{mockOpWithResults{mockOp: makeMockOp(false)}}
+		failingOp.name = "failing-op"
+		return []clusterOp{op1, op2, op3, failingOp}
+	}
+
+	// the compensations run in the reverse order of the ops
+	options := DatabaseOptionsFactory()
+	opEngn := options.makeClusterOpEngine(makeInstructions())
+	err := opEngn.run(context.Background(), vlog.Printer{})
+	assert.ErrorContains(t, err, "execute failing-op failed")
+	assert.Equal(t, []string{"op1", "op2", "op3", "undo-op3", "undo-op1"}, executed)
+	assert.Equal(t, []string{"op3", "op1"}, options.GetOperationReport().RolledBackOps)
+
+	// the changes are kept when the rollback is disabled
+	executed = nil
+	options = DatabaseOptionsFactory()
+	options.DisableRollback = true
+	opEngn = options.makeClusterOpEngine(makeInstructions())
+	err = opEngn.run(context.Background(), vlog.Printer{})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"op1", "op2", "op3"}, executed)
+	assert.Empty(t, options.GetOperationReport().RolledBackOps)
+
+	// nothing is rolled back when the run succeeds
+	executed = nil
+	options = DatabaseOptionsFactory()
+	instructions := makeInstructions()
+	opEngn = options.makeClusterOpEngine(instructions[:3])
+	err = opEngn.run(context.Background(), vlog.Printer{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"op1", "op2", "op3"}, executed)
+}
+
+func TestPrepareDirectoriesCompensation(t *testing.T) {
+	hostNodeMap := makeVHostNodeMap()
+	hostNodeMap["host1"] = &VCoordinationNode{CatalogPath: "/data/test_db/v_test_db_node0001_catalog/Catalog",
+		StorageLocations: []string{"/data/test_db/v_test_db_node0001_data"}}
+	hostNodeMap["host2"] = &VCoordinationNode{CatalogPath: "/data/test_db/v_test_db_node0002_catalog"}
+	op, err := makeNMAPrepareDirectoriesOp(hostNodeMap, false, true)
+	assert.NoError(t, err)
+	op.setupBasicInfo()
+
+	// nothing to undo before the op runs
+	compensation, err := op.getCompensation()
+	assert.NoError(t, err)
+	assert.Nil(t, compensation)
+
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"host1": {host: "host1", statusCode: SuccessCode, content: `{"/data/test_db": "created",
+			"/data/test_db/v_test_db_node0001_catalog": "created", "/data/test_db/v_test_db_node0001_data": "created",
+			"/data/test_db/procedures": "created", "/opt/vertica/config/logrotate": "created"}`},
+		"host2": {host: "host2", statusCode: SuccessCode, content: `{"/data/test_db": "exists",
+			"/data/test_db/v_test_db_node0002_catalog": "created"}`},
+	}
+	assert.NoError(t, op.processResult(nil))
+
+	// only the node directories created by the op are deleted, not the shared
+	// database directory nor the config shared by the databases of the host
+	compensation, err = op.getCompensation()
+	assert.NoError(t, err)
+	deleteDirsOp, ok := compensation.(*nmaDeleteDirectoriesOp)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"host1", "host2"}, deleteDirsOp.hosts)
+	expected := map[string][]string{
+		"host1": {"/data/test_db/v_test_db_node0001_catalog", "/data/test_db/v_test_db_node0001_data"},
+		"host2": {"/data/test_db/v_test_db_node0002_catalog"},
+	}
+	for host, directories := range expected {
+		var params deleteDirParams
+		assert.NoError(t, json.Unmarshal([]byte(deleteDirsOp.hostRequestBodyMap[host]), &params))
+		assert.Equal(t, directories, params.Directories)
+		assert.True(t, params.ForceDelete)
+	}
+}
+
+func TestRemoveNestedPaths(t *testing.T) {
+	paths := []string{"/data/db/node_catalog", "/data/db-other", "/data/db", "/depot/db/"}
+	assert.Equal(t, []string{"/data/db", "/data/db-other", "/depot/db/"}, removeNestedPaths(paths))
+	assert.Empty(t, removeNestedPaths(nil))
+}
@@ -0,0 +1,126 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// ConfigurationAuditRecord records a change of a configuration parameter
+// made by VSetConfigurationParameters. The values of a parameter that holds a
+// credential, such as AWSAuth, are masked.
+type ConfigurationAuditRecord struct {
+	DBName          string    `json:"db_name"`
+	Sandbox         string    `json:"sandbox,omitempty"`
+	ConfigParameter string    `json:"config_parameter"`
+	OldValue        string    `json:"old_value"`
+	NewValue        string    `json:"new_value"`
+	Level           string    `json:"level,omitempty"`
+	User            string    `json:"user"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+const auditFilePerm = 0600
+
+// appendConfigurationAuditRecord appends the record to the audit file as a
+// line of JSON. The file is created if it does not exist. The values of a
+// sensitive parameter are masked, so that a credential never reaches the file.
+func appendConfigurationAuditRecord(auditFilePath string, record *ConfigurationAuditRecord) error {
+	if isSensitiveConfigParameter(record.ConfigParameter) {
+		maskedRecord := *record
+		maskedRecord.OldValue = maskedValue
+		maskedRecord.NewValue = maskedValue
+		record = &maskedRecord
+	}
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("fail to marshal the audit record, detail %w", err)
+	}
+	file, err := os.OpenFile(auditFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, auditFilePerm)
+	if err != nil {
+		return fmt.Errorf("fail to open the audit file %s, detail %w", auditFilePath, err)
+	}
+	_, err = file.Write(append(recordBytes, '\n'))
+	return errors.Join(err, file.Close())
+}
+
+type VShowConfigurationAuditOptions struct {
+	// path of the audit file written by VSetConfigurationParameters
+	AuditFilePath string
+	// Optional arguments to list only the records that
+	// meet the specified condition(s)
+	DBName          string
+	ConfigParameter string
+}
+
+func VShowConfigurationAuditOptionsFactory() VShowConfigurationAuditOptions {
+	return VShowConfigurationAuditOptions{}
+}
+
+func (options *VShowConfigurationAuditOptions) validateParseOptions() error {
+	return util.ValidateRequiredAbsPath(options.AuditFilePath, "audit file path")
+}
+
+func (options *VShowConfigurationAuditOptions) isSelected(record *ConfigurationAuditRecord) bool {
+	if options.DBName != "" && record.DBName != options.DBName {
+		return false
+	}
+	return options.ConfigParameter == "" || record.ConfigParameter == options.ConfigParameter
+}
+
+// VShowConfigurationAudit returns the configuration changes recorded in the
+// audit file, oldest first. A missing audit file means that no change was
+// recorded yet.
+func (vcc VClusterCommands) VShowConfigurationAudit(options *VShowConfigurationAuditOptions) ([]ConfigurationAuditRecord, error) {
+	var records []ConfigurationAuditRecord
+	err := options.validateParseOptions()
+	if err != nil {
+		return records, err
+	}
+
+	file, err := os.Open(options.AuditFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return records, nil
+	}
+	if err != nil {
+		return records, fmt.Errorf("fail to open the audit file %s, detail %w", options.AuditFilePath, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		var record ConfigurationAuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return records, fmt.Errorf("fail to parse line %d of the audit file %s, detail %w",
+				lineNum, options.AuditFilePath, err)
+		}
+		if options.isSelected(&record) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return records, fmt.Errorf("fail to read the audit file %s, detail %w", options.AuditFilePath, err)
+	}
+	vcc.Log.Info("configuration audit records", "count", len(records))
+	return records, nil
+}
@@ -0,0 +1,91 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigurationAudit(t *testing.T) {
+	vcc := VClusterCommands{}
+	options := VShowConfigurationAuditOptionsFactory()
+	options.AuditFilePath = filepath.Join(t.TempDir(), "config_audit.log")
+
+	// no record is written yet
+	records, err := vcc.VShowConfigurationAudit(&options)
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+
+	first := ConfigurationAuditRecord{DBName: "test_db", ConfigParameter: "MaxClientSessions",
+		OldValue: "50", NewValue: "100", User: "dbadmin", Timestamp: time.Now().UTC()}
+	second := ConfigurationAuditRecord{DBName: "test_db", ConfigParameter: "EnableSSL",
+		OldValue: "0", NewValue: "1", Level: "node", User: "dbadmin", Timestamp: time.Now().UTC()}
+	assert.NoError(t, appendConfigurationAuditRecord(options.AuditFilePath, &first))
+	assert.NoError(t, appendConfigurationAuditRecord(options.AuditFilePath, &second))
+
+	records, err = vcc.VShowConfigurationAudit(&options)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "EnableSSL", records[1].ConfigParameter)
+	assert.True(t, first.Timestamp.Equal(records[0].Timestamp))
+
+	// filter by parameter name
+	options.ConfigParameter = "MaxClientSessions"
+	records, err = vcc.VShowConfigurationAudit(&options)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "50", records[0].OldValue)
+	assert.Equal(t, "100", records[0].NewValue)
+
+	// the audit file path must be absolute
+	options.AuditFilePath = "config_audit.log"
+	_, err = vcc.VShowConfigurationAudit(&options)
+	assert.Error(t, err)
+}
+
+func TestConfigurationAuditMasksSecrets(t *testing.T) {
+	vcc := VClusterCommands{}
+	options := VSetConfigurationParameterOptionsFactory()
+	options.DBName = "test_db"
+	options.AuditFilePath = filepath.Join(t.TempDir(), "config_audit.log")
+	options.ConfigParameters = map[string]string{"AWSAuth": "AKIANEWKEY:new-secret", "MaxClientSessions": "100"}
+	statuses := []ConfigurationParameterStatus{
+		{ConfigParameter: "AWSAuth", SetStatus: configParameterSetSuccess},
+		{ConfigParameter: "MaxClientSessions", SetStatus: configParameterSetSuccess},
+	}
+	vcc.auditConfigurationParameters(&options, statuses,
+		map[string]string{"AWSAuth": "AKIAOLDKEY:old-secret", "MaxClientSessions": "50"})
+
+	content, err := os.ReadFile(options.AuditFilePath)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "secret")
+	assert.NotContains(t, string(content), "AKIA")
+
+	showOptions := VShowConfigurationAuditOptionsFactory()
+	showOptions.AuditFilePath = options.AuditFilePath
+	records, err := vcc.VShowConfigurationAudit(&showOptions)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, maskedValue, records[0].OldValue)
+	assert.Equal(t, maskedValue, records[0].NewValue)
+	// the other parameters are kept as they are
+	assert.Equal(t, "100", records[1].NewValue)
+}
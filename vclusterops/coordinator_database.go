@@ -49,6 +49,9 @@ type VCoordinationDatabase struct {
 	AwsSecretKey            string
 	NumShards               int
 
+	// the K-safety that the database design is marked with in the catalog
+	KSafety int
+
 	// authentication
 	LicensePathOnNode string
 
@@ -57,6 +60,10 @@ type VCoordinationDatabase struct {
 
 	PrimaryUpNodes        []string
 	FirstStartAfterRevive bool
+
+	// the addresses of the nodes on each network of a multi-homed cluster,
+	// nil if the nodes only have the addresses in HostNodeMap
+	AddressBook *AddressBook
 }
 
 type vHostNodeMap map[string]*VCoordinationNode
@@ -73,6 +80,7 @@ func (vdb *VCoordinationDatabase) setFromBasicDBOptions(options *VCreateDatabase
 	// we trust the information in the config file
 	// so we do not perform validation here
 	vdb.Name = options.DBName
+	vdb.AddressBook = options.AddressBook
 	vdb.CatalogPrefix = options.CatalogPrefix
 	vdb.DataPrefix = options.DataPrefix
 	vdb.DepotPrefix = options.DepotPrefix
@@ -185,9 +193,11 @@ func (vdb *VCoordinationDatabase) copy(targetHosts []string) VCoordinationDataba
 		AwsIDKey:                vdb.AwsIDKey,
 		AwsSecretKey:            vdb.AwsSecretKey,
 		NumShards:               vdb.NumShards,
+		KSafety:                 vdb.KSafety,
 		LicensePathOnNode:       vdb.LicensePathOnNode,
 		Ipv6:                    vdb.Ipv6,
 		PrimaryUpNodes:          util.CopySlice(vdb.PrimaryUpNodes),
+		AddressBook:             vdb.AddressBook,
 	}
 
 	if len(targetHosts) == 0 {
@@ -212,6 +222,15 @@ func (vdb *VCoordinationDatabase) copyHostNodeMap(targetHosts []string) vHostNod
 	return util.FilterMapByKey(vdb.HostNodeMap, targetHosts)
 }
 
+// GetNodeAddress returns the address of a node on a network, which is the
+// address of the node in HostNodeMap if the address book does not have one
+func (vdb *VCoordinationDatabase) GetNodeAddress(vnode *VCoordinationNode, network NetworkName) string {
+	if address, ok := vdb.AddressBook.GetNodeAddress(vnode.Name, network); ok {
+		return address
+	}
+	return vnode.Address
+}
+
 // genNodeNameToHostMap generates a map, with node name as key and
 // host ip as value, from HostNodeMap.
 func (vdb *VCoordinationDatabase) genNodeNameToHostMap() map[string]string {
@@ -337,6 +356,10 @@ type VCoordinationNode struct {
 	IsControlNode bool
 }
 
+func (VCoordinationNode) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
 func makeVCoordinationNode() VCoordinationNode {
 	return VCoordinationNode{}
 }
@@ -0,0 +1,156 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type VCreateArchiveOptions struct {
+	/* part 1: basic db info */
+	DatabaseOptions
+
+	/* part 2: archive info */
+	// name of the archive to create
+	ArchiveName string
+	// maximum number of restore points that the archive keeps, the oldest
+	// restore point is removed when a new one exceeds it. Zero means no limit.
+	NumRestorePoints int
+}
+
+func VCreateArchiveOptionsFactory() VCreateArchiveOptions {
+	options := VCreateArchiveOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VCreateArchiveOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandCreateArchive, logger)
+	if err != nil {
+		return err
+	}
+
+	// need to provide a password or key and certs
+	if options.Password == nil && !options.hasCertsInOptions() {
+		// validate key and cert files in local file system
+		_, err = getCertFilePaths()
+		if err != nil {
+			// in case that the key or cert files do not exist
+			return fmt.Errorf("must provide a password, key and certificates explicitly," +
+				" or key and certificate files in the default paths")
+		}
+	}
+
+	if options.NumRestorePoints < 0 {
+		return fmt.Errorf("the number of restore points must not be negative, but got %d", options.NumRestorePoints)
+	}
+	return validateArchiveName(options.ArchiveName)
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VCreateArchiveOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VCreateArchiveOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	return options.setUsePassword(logger)
+}
+
+// VCreateArchive creates an archive in the communal storage of the database,
+// to which restore points can then be saved. It returns any error encountered.
+func (vcc VClusterCommands) VCreateArchive(options *VCreateArchiveOptions) error {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	// validate and analyze options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	// produce create archive instructions
+	instructions, err := vcc.produceCreateArchiveInstructions(options)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	// create a VClusterOpEngine, and add certs to the engine
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+
+	// give the instructions to the VClusterOpEngine to run
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if runError != nil {
+		return fmt.Errorf("fail to create archive %s: %w", options.ArchiveName, runError)
+	}
+
+	return nil
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful create archive operation:
+//   - Get up nodes through HTTPS call
+//   - Create the archive through one of the up nodes
+func (vcc VClusterCommands) produceCreateArchiveInstructions(options *VCreateArchiveOptions) ([]clusterOp, error) {
+	var instructions []clusterOp
+
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password, CreateArchiveCmd)
+	if err != nil {
+		return instructions, err
+	}
+
+	httpsCreateArchiveOp, err := makeHTTPSCreateArchiveOp(options.usePassword, options.UserName,
+		options.Password, options.ArchiveName, options.NumRestorePoints)
+	if err != nil {
+		return instructions, err
+	}
+
+	instructions = append(instructions,
+		&httpsGetUpNodesOp,
+		&httpsCreateArchiveOp,
+	)
+	return instructions, nil
+}
+
+// validateArchiveName checks that the name of an archive is set and does not
+// contain special characters
+func validateArchiveName(archiveName string) error {
+	if archiveName == "" {
+		return fmt.Errorf("must specify an archive name")
+	}
+	return util.ValidateName(archiveName, "archive", false)
+}
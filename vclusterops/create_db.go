@@ -40,12 +40,18 @@ type VCreateDatabaseOptions struct {
 	ShardCount               int    // number of shards in the database"
 	DepotSize                string // depot size with two supported formats: % and KMGT, e.g., 50% or 10G
 	GetAwsCredentialsFromEnv bool   // whether get AWS credentials from environmental variables
+	ExpectedNodeCount        int    // number of nodes that the database is expected to grow to
 	// part 3: optional info
 	ForceCleanupOnFailure     bool // whether force remove existing directories on failure
 	ForceRemovalAtCreation    bool // whether force remove existing directories before creating the database
 	ForceOverwriteFile        bool // whether force overwrite existing config and config param files
 	SkipPackageInstall        bool // whether skip package installation
 	TimeoutNodeStartupSeconds int  // timeout in seconds for polling node start up state
+	// where to move the directories removed by ForceRemovalAtCreation
+	Trash TrashOptions
+	// declarative layout of the subclusters of an Eon database. The nodes of
+	// the subclusters are placed on the hosts of the database.
+	Topology *VTopologyTemplate
 
 	/* part 3: new params originally in installer generated admintools.conf, now in create db op */
 
@@ -104,7 +110,7 @@ func (options *VCreateDatabaseOptions) validateRequiredOptions(logger vlog.Print
 	}
 
 	// validate required parameters with default values
-	if options.Password == nil {
+	if !options.hasPassword() {
 		options.Password = new(string)
 		*options.Password = ""
 		logger.Info("no password specified, using none")
@@ -199,7 +205,7 @@ func validateDepotSize(size string) (bool, error) {
 
 func (options *VCreateDatabaseOptions) validateEonOptions() error {
 	if options.CommunalStorageLocation != "" {
-		err := util.ValidateCommunalStorageLocation(options.CommunalStorageLocation)
+		err := util.ValidateCommunalStorage(options.CommunalStorageLocation, options.ConfigurationParameters)
 		if err != nil {
 			return err
 		}
@@ -228,6 +234,25 @@ func (options *VCreateDatabaseOptions) validateEonOptions() error {
 	return nil
 }
 
+// checkShardCount warns about a shard count that prevents the database from
+// scaling elastically, and recommends one that does not
+func (options *VCreateDatabaseOptions) checkShardCount(logger vlog.Printer) error {
+	if options.CommunalStorageLocation == "" || options.ShardCount == 0 {
+		return nil
+	}
+	advice, err := CheckShardCount(options.ShardCount, len(options.RawHosts), options.ExpectedNodeCount)
+	if err != nil {
+		return err
+	}
+	for _, warning := range advice.Warnings {
+		logger.PrintWarning("%s", warning)
+	}
+	if len(advice.Warnings) > 0 {
+		logger.PrintWarning("Consider a shard count of %d, or a multiple of it", advice.RecommendedShardCount)
+	}
+	return nil
+}
+
 func (options *VCreateDatabaseOptions) validateExtraOptions() error {
 	if options.Broadcast && options.P2p {
 		return fmt.Errorf("cannot use both Broadcast and Point-to-point networking mode")
@@ -236,7 +261,7 @@ func (options *VCreateDatabaseOptions) validateExtraOptions() error {
 	if options.LargeCluster != util.DefaultLargeCluster && (options.LargeCluster < 1 || options.LargeCluster > util.MaxLargeCluster) {
 		return fmt.Errorf("must specify a valid large cluster value in range [1, 120]")
 	}
-	return nil
+	return options.Trash.validate()
 }
 
 func (options *VCreateDatabaseOptions) validateParseOptions(logger vlog.Printer) error {
@@ -250,6 +275,10 @@ func (options *VCreateDatabaseOptions) validateParseOptions(logger vlog.Printer)
 	if err != nil {
 		return err
 	}
+	err = options.checkShardCount(logger)
+	if err != nil {
+		return err
+	}
 	// batch 3: validate all other params
 	err = options.validateExtraOptions()
 	if err != nil {
@@ -262,7 +291,7 @@ func (options *VCreateDatabaseOptions) validateParseOptions(logger vlog.Printer)
 func (options *VCreateDatabaseOptions) analyzeOptions() error {
 	// resolve RawHosts to be IP addresses
 	if len(options.RawHosts) > 0 {
-		hostAddresses, err := util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		hostAddresses, err := options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -287,6 +316,10 @@ func (options *VCreateDatabaseOptions) validateAnalyzeOptions(logger vlog.Printe
 func (vcc VClusterCommands) VCreateDatabase(options *VCreateDatabaseOptions) (VCoordinationDatabase, error) {
 	vcc.Log.Info("starting VCreateDatabase")
 
+	if options.Topology != nil {
+		return vcc.createDatabaseWithTopology(options)
+	}
+
 	/*
 	 *   - Produce Instructions
 	 *   - Create a VClusterOpEngine
@@ -306,11 +339,10 @@ func (vcc VClusterCommands) VCreateDatabase(options *VCreateDatabaseOptions) (VC
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	err = clusterOpEngine.run(vcc.Log)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if err != nil {
 		vcc.Log.Error(err, "fail to create database")
 		return vdb, err
@@ -372,7 +404,10 @@ func (vcc VClusterCommands) produceCreateDBBootstrapInstructions(
 	var instructions []clusterOp
 
 	hosts := vdb.HostList
-	initiator := getInitiator(hosts)
+	initiator, err := options.selectInitiator(vcc.Context(), hosts)
+	if err != nil {
+		return instructions, err
+	}
 
 	nmaHealthOp := makeNMAHealthOp(hosts)
 
@@ -380,7 +415,7 @@ func (vcc VClusterCommands) produceCreateDBBootstrapInstructions(
 	nmaVerticaVersionOp := makeNMACheckVerticaVersionOp(hosts, true, vdb.IsEon)
 
 	// need username for https operations
-	err := options.validateUserName(vcc.Log)
+	err = options.validateUserName(vcc.Log)
 	if err != nil {
 		return instructions, err
 	}
@@ -417,6 +452,12 @@ func (vcc VClusterCommands) produceCreateDBBootstrapInstructions(
 		&nmaHealthOp,
 		&nmaVerticaVersionOp,
 		&checkDBRunningOp,
+	)
+	instructions, err = options.Trash.addTrashInstructions(instructions, options.DBName, &nmaPrepareDirectoriesOp)
+	if err != nil {
+		return instructions, err
+	}
+	instructions = append(instructions,
 		&nmaPrepareDirectoriesOp,
 		&nmaNetworkProfileOp,
 		&nmaBootstrapCatalogOp,
@@ -0,0 +1,180 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// VSubclusterTemplate describes a subcluster of a VTopologyTemplate
+type VSubclusterTemplate struct {
+	Name      string
+	IsPrimary bool
+	// number of nodes in the subcluster. When Hosts is empty, the nodes are
+	// placed in order on the database hosts that no other subcluster lists.
+	NodeCount int
+	// hosts of the nodes in the subcluster
+	Hosts []string
+}
+
+// VTopologyTemplate is a declarative layout of an Eon database. The first
+// subcluster, which must be primary, is created with the database, and the
+// other subclusters are added with their nodes once the database is UP.
+type VTopologyTemplate struct {
+	// number of shards in the database, which overrides ShardCount of the
+	// create database options when it is set
+	ShardCount  int
+	Subclusters []VSubclusterTemplate
+}
+
+// subclusterPlacement is a subcluster of a topology template with its hosts
+type subclusterPlacement struct {
+	name      string
+	isPrimary bool
+	rawHosts  []string
+}
+
+// planTopology validates the topology template, and places the nodes of its
+// subclusters on the database hosts
+func (options *VCreateDatabaseOptions) planTopology() ([]subclusterPlacement, error) {
+	topology := options.Topology
+	if options.CommunalStorageLocation == "" {
+		return nil, fmt.Errorf("topology templates are only supported in Eon mode")
+	}
+	if len(topology.Subclusters) == 0 {
+		return nil, fmt.Errorf("the topology template must have at least one subcluster")
+	}
+	if topology.ShardCount < 0 {
+		return nil, fmt.Errorf("the shard count of the topology template must not be negative")
+	}
+	if !topology.Subclusters[0].IsPrimary {
+		return nil, fmt.Errorf("the first subcluster %s of the topology template must be primary",
+			topology.Subclusters[0].Name)
+	}
+
+	// the hosts that the templates list explicitly are not placed again
+	scNames := make(map[string]bool)
+	hostSC := make(map[string]string)
+	for _, sc := range topology.Subclusters {
+		if err := util.ValidateScName(sc.Name); err != nil {
+			return nil, err
+		}
+		if scNames[sc.Name] {
+			return nil, fmt.Errorf("subcluster %s is duplicated in the topology template", sc.Name)
+		}
+		scNames[sc.Name] = true
+
+		if len(sc.Hosts) > 0 && sc.NodeCount != 0 && sc.NodeCount != len(sc.Hosts) {
+			return nil, fmt.Errorf("subcluster %s has %d nodes but %d hosts", sc.Name, sc.NodeCount, len(sc.Hosts))
+		}
+		for _, host := range sc.Hosts {
+			if otherSC, ok := hostSC[host]; ok {
+				return nil, fmt.Errorf("host %s is in both subclusters %s and %s", host, otherSC, sc.Name)
+			}
+			hostSC[host] = sc.Name
+		}
+	}
+	var freeHosts []string
+	for _, host := range options.RawHosts {
+		if _, ok := hostSC[host]; !ok {
+			freeHosts = append(freeHosts, host)
+		}
+	}
+
+	placements := make([]subclusterPlacement, 0, len(topology.Subclusters))
+	for _, sc := range topology.Subclusters {
+		placement := subclusterPlacement{name: sc.Name, isPrimary: sc.IsPrimary, rawHosts: sc.Hosts}
+		if len(sc.Hosts) == 0 {
+			if sc.NodeCount <= 0 {
+				return nil, fmt.Errorf("subcluster %s must have a node count or hosts", sc.Name)
+			}
+			if sc.NodeCount > len(freeHosts) {
+				return nil, fmt.Errorf("not enough hosts for the %d nodes of subcluster %s, %d hosts left",
+					sc.NodeCount, sc.Name, len(freeHosts))
+			}
+			placement.rawHosts = freeHosts[:sc.NodeCount]
+			freeHosts = freeHosts[sc.NodeCount:]
+		}
+		placements = append(placements, placement)
+	}
+	if len(freeHosts) > 0 {
+		return nil, fmt.Errorf("hosts %v are not placed in any subcluster of the topology template", freeHosts)
+	}
+
+	return placements, nil
+}
+
+// createDatabaseWithTopology creates the database with the first subcluster of
+// the topology template, then renames it and adds the other subclusters and
+// their nodes. It returns the VCoordinationDatabase of the whole database.
+func (vcc VClusterCommands) createDatabaseWithTopology(options *VCreateDatabaseOptions) (VCoordinationDatabase, error) {
+	placements, err := options.planTopology()
+	if err != nil {
+		return makeVCoordinationDatabase(), err
+	}
+
+	createOptions := *options
+	createOptions.Topology = nil
+	createOptions.RawHosts = placements[0].rawHosts
+	if options.Topology.ShardCount > 0 {
+		createOptions.ShardCount = options.Topology.ShardCount
+	}
+	vdb, err := vcc.VCreateDatabase(&createOptions)
+	if err != nil {
+		return vdb, err
+	}
+
+	// the nodes of the first subcluster are the initiators of the later operations
+	dbOptions := createOptions.DatabaseOptions
+	dbOptions.IsEon = true
+
+	if placements[0].name != DefaultSC {
+		renameOptions := VRenameSubclusterFactory()
+		renameOptions.DatabaseOptions = dbOptions
+		renameOptions.SCName = DefaultSC
+		renameOptions.NewSCName = placements[0].name
+		err = vcc.VRenameSubcluster(&renameOptions)
+		if err != nil {
+			return vdb, fmt.Errorf("fail to apply the topology template: %w", err)
+		}
+	}
+
+	for _, placement := range placements[1:] {
+		vcc.Log.PrintInfo("Adding subcluster %s with hosts %v", placement.name, placement.rawHosts)
+		scOptions := VAddSubclusterOptionsFactory()
+		scOptions.DatabaseOptions = dbOptions
+		scOptions.SCName = placement.name
+		scOptions.IsPrimary = placement.isPrimary
+		err = vcc.VAddSubcluster(&scOptions)
+		if err != nil {
+			return vdb, fmt.Errorf("fail to apply the topology template: %w", err)
+		}
+
+		nodeOptions := VAddNodeOptionsFactory()
+		nodeOptions.DatabaseOptions = dbOptions
+		nodeOptions.SCName = placement.name
+		nodeOptions.NewHosts = placement.rawHosts
+		nodeOptions.DepotSize = options.DepotSize
+		vdb, err = vcc.VAddNode(&nodeOptions)
+		if err != nil {
+			return vdb, fmt.Errorf("fail to apply the topology template: %w", err)
+		}
+	}
+
+	return vdb, nil
+}
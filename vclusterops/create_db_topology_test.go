@@ -0,0 +1,79 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanTopology(t *testing.T) {
+	options := VCreateDatabaseOptionsFactory()
+	options.CommunalStorageLocation = "s3://bucket/test_db"
+	options.RawHosts = []string{"192.168.1.101", "192.168.1.102", "192.168.1.103", "192.168.1.104", "192.168.1.105"}
+	options.Topology = &VTopologyTemplate{
+		ShardCount: 6,
+		Subclusters: []VSubclusterTemplate{
+			{Name: "primary_sc", IsPrimary: true, NodeCount: 3},
+			{Name: "analytics_sc", NodeCount: 1},
+			{Name: "etl_sc", Hosts: []string{"192.168.1.102"}},
+		},
+	}
+
+	// the nodes are placed on the hosts that no subcluster lists
+	placements, err := options.planTopology()
+	assert.NoError(t, err)
+	assert.Equal(t, []subclusterPlacement{
+		{name: "primary_sc", isPrimary: true, rawHosts: []string{"192.168.1.101", "192.168.1.103", "192.168.1.104"}},
+		{name: "analytics_sc", rawHosts: []string{"192.168.1.105"}},
+		{name: "etl_sc", rawHosts: []string{"192.168.1.102"}},
+	}, placements)
+
+	// every host must be placed
+	options.Topology.Subclusters[0].NodeCount = 2
+	_, err = options.planTopology()
+	assert.ErrorContains(t, err, "hosts [192.168.1.105] are not placed")
+
+	// not enough hosts
+	options.Topology.Subclusters[0].NodeCount = 4
+	_, err = options.planTopology()
+	assert.ErrorContains(t, err, "not enough hosts for the 1 nodes of subcluster analytics_sc")
+	options.Topology.Subclusters[0].NodeCount = 3
+
+	// a host in two subclusters
+	options.Topology.Subclusters[1].Hosts = []string{"192.168.1.102"}
+	_, err = options.planTopology()
+	assert.ErrorContains(t, err, "host 192.168.1.102 is in both subclusters analytics_sc and etl_sc")
+	options.Topology.Subclusters[1].Hosts = nil
+
+	// the first subcluster must be primary
+	options.Topology.Subclusters[0].IsPrimary = false
+	_, err = options.planTopology()
+	assert.ErrorContains(t, err, "must be primary")
+	options.Topology.Subclusters[0].IsPrimary = true
+
+	// duplicate subcluster
+	options.Topology.Subclusters[2].Name = "analytics_sc"
+	_, err = options.planTopology()
+	assert.ErrorContains(t, err, "subcluster analytics_sc is duplicated")
+	options.Topology.Subclusters[2].Name = "etl_sc"
+
+	// only in Eon mode
+	options.CommunalStorageLocation = ""
+	_, err = options.planTopology()
+	assert.ErrorContains(t, err, "only supported in Eon mode")
+}
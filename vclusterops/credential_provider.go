@@ -0,0 +1,75 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CredentialProvider supplies the password of a database user. It is asked
+// when the requests are sent rather than when the options are built, so that
+// the callers do not keep the password in the options, and a rotated password
+// is used by the next request. An OS keychain, Vault, or AWS Secrets Manager
+// can be used through a CredentialProviderFunc that calls its client.
+type CredentialProvider interface {
+	GetPassword(userName string) (string, error)
+}
+
+// CredentialProviderFunc is a function that implements CredentialProvider
+type CredentialProviderFunc func(userName string) (string, error)
+
+func (f CredentialProviderFunc) GetPassword(userName string) (string, error) {
+	return f(userName)
+}
+
+type envCredentialProvider struct {
+	envVar string
+}
+
+// NewEnvCredentialProvider returns a CredentialProvider that reads the
+// password from an environment variable
+func NewEnvCredentialProvider(envVar string) CredentialProvider {
+	return &envCredentialProvider{envVar: envVar}
+}
+
+func (p *envCredentialProvider) GetPassword(_ string) (string, error) {
+	password, ok := os.LookupEnv(p.envVar)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", p.envVar)
+	}
+	return password, nil
+}
+
+type fileCredentialProvider struct {
+	path string
+}
+
+// NewFileCredentialProvider returns a CredentialProvider that reads the
+// password from a file, e.g., a mounted Kubernetes secret. The file is read
+// again for every password, and a trailing newline is removed.
+func NewFileCredentialProvider(path string) CredentialProvider {
+	return &fileCredentialProvider{path: path}
+}
+
+func (p *fileCredentialProvider) GetPassword(_ string) (string, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return "", fmt.Errorf("fail to read the password file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
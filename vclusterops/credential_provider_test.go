@@ -0,0 +1,125 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestCredentialProviders(t *testing.T) {
+	t.Setenv("VCLUSTER_TEST_PASSWORD", "env-secret")
+	password, err := NewEnvCredentialProvider("VCLUSTER_TEST_PASSWORD").GetPassword("dbadmin")
+	assert.NoError(t, err)
+	assert.Equal(t, "env-secret", password)
+	_, err = NewEnvCredentialProvider("VCLUSTER_TEST_MISSING_PASSWORD").GetPassword("dbadmin")
+	assert.ErrorContains(t, err, "environment variable VCLUSTER_TEST_MISSING_PASSWORD is not set")
+
+	// the file is read again for each password
+	path := filepath.Join(t.TempDir(), "password")
+	assert.NoError(t, os.WriteFile(path, []byte("file-secret\n"), 0600))
+	provider := NewFileCredentialProvider(path)
+	password, err = provider.GetPassword("dbadmin")
+	assert.NoError(t, err)
+	assert.Equal(t, "file-secret", password)
+	assert.NoError(t, os.WriteFile(path, []byte("rotated-secret"), 0600))
+	password, err = provider.GetPassword("dbadmin")
+	assert.NoError(t, err)
+	assert.Equal(t, "rotated-secret", password)
+	_, err = NewFileCredentialProvider(filepath.Join(t.TempDir(), "missing")).GetPassword("dbadmin")
+	assert.ErrorContains(t, err, "fail to read the password file")
+}
+
+func TestCredentialProviderOptions(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	opt.UserName = "dbadmin"
+	opt.DBName = "test_db"
+	opt.RawHosts = []string{"192.168.1.101"}
+	assert.False(t, opt.hasPassword())
+	password, err := opt.getRequestDataPassword()
+	assert.NoError(t, err)
+	assert.Nil(t, password)
+
+	opt.CredentialProvider = CredentialProviderFunc(func(userName string) (string, error) {
+		return userName + "-secret", nil
+	})
+	assert.True(t, opt.hasPassword())
+	assert.NoError(t, opt.setUsePassword(vlog.Printer{}))
+	assert.True(t, opt.usePassword)
+	password, err = opt.getRequestDataPassword()
+	assert.NoError(t, err)
+	assert.Equal(t, "dbadmin-secret", *password)
+	// the password is not kept in the options
+	assert.Nil(t, opt.Password)
+
+	opt.SetPassword("secret")
+	assert.ErrorContains(t, opt.validateBaseOptions(commandReplicationStatus, vlog.Printer{}),
+		"only one of the password and the credential provider can be set")
+}
+
+func TestCredentialProviderRequests(t *testing.T) {
+	provider := CredentialProviderFunc(func(userName string) (string, error) {
+		return userName + "-secret", nil
+	})
+
+	// the provider is only set on the requests that authenticate with a password
+	dispatcher := makeHTTPRequestDispatcher(context.Background(), vlog.Printer{})
+	dispatcher.credentialProvider = provider
+	password := "secret"
+	httpRequest := clusterHTTPRequest{RequestCollection: map[string]hostHTTPRequest{
+		"192.168.1.101": {Username: "dbadmin"},
+		"192.168.1.102": {Username: "dbadmin", Password: &password},
+		"192.168.1.103": {},
+	}}
+	dispatcher.setDefaults(&httpRequest)
+	assert.NotNil(t, httpRequest.RequestCollection["192.168.1.101"].CredentialProvider)
+	assert.Nil(t, httpRequest.RequestCollection["192.168.1.102"].CredentialProvider)
+	assert.Nil(t, httpRequest.RequestCollection["192.168.1.103"].CredentialProvider)
+
+	// the password is asked for when the request is sent
+	var authHeaders []string
+	adapter := makeHTTPAdapter(vlog.Printer{})
+	adapter.host = "192.168.1.101"
+	adapter.transports = makeTransportCache(&TransportPolicy{}, nil, func(string, http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			user, password, _ := req.BasicAuth()
+			authHeaders = append(authHeaders, user+":"+password)
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{},
+				Body: io.NopCloser(strings.NewReader("{}"))}, nil
+		})
+	})
+	request := httpRequest.RequestCollection["192.168.1.101"]
+	request.Method = GetMethod
+	request.buildHTTPSEndpoint("nodes")
+	result := adapter.send(context.Background(), &request)
+	assert.NoError(t, result.err)
+	assert.Equal(t, []string{"dbadmin:dbadmin-secret"}, authHeaders)
+
+	request.CredentialProvider = CredentialProviderFunc(func(string) (string, error) {
+		return "", errors.New("vault is sealed")
+	})
+	result = adapter.send(context.Background(), &request)
+	assert.ErrorContains(t, result.err, "fail to get the password of user dbadmin: vault is sealed")
+}
@@ -0,0 +1,216 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type VDeployServerCertificateOptions struct {
+	/* part 1: basic db info */
+	DatabaseOptions
+
+	/* part 2: certificate deployment options */
+	// name of the certificate in the database
+	CertificateName string
+	// the certificate and its private key, PEM-encoded
+	CertificatePEM string
+	PrivateKeyPEM  string
+	// names of the CA certificates, already imported into the database,
+	// that the server TLS configuration trusts. Left unchanged if empty.
+	CACertificates []string
+	// TLS mode of the server TLS configuration. Left unchanged if empty.
+	TLSMode string
+	// port on which the nodes accept client connections
+	ClientPort int
+
+	// DER encoding of the certificate, parsed from CertificatePEM
+	certDER []byte
+}
+
+func VDeployServerCertificateOptionsFactory() VDeployServerCertificateOptions {
+	options := VDeployServerCertificateOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	options.ClientPort = util.DefaultClientPort
+	return options
+}
+
+func (options *VDeployServerCertificateOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandDeployServerCertificate, logger)
+	if err != nil {
+		return err
+	}
+
+	// need to provide a password or key and certs
+	if options.Password == nil && !options.hasCertsInOptions() {
+		// validate key and cert files in local file system
+		_, err = getCertFilePaths()
+		if err != nil {
+			// in case that the key or cert files do not exist
+			return fmt.Errorf("must provide a password, key and certificates explicitly," +
+				" or key and certificate files in the default paths")
+		}
+	}
+
+	return options.validateExtraOptions()
+}
+
+func (options *VDeployServerCertificateOptions) validateExtraOptions() error {
+	if options.CertificateName == "" {
+		return fmt.Errorf("must specify a certificate name")
+	}
+	if options.ClientPort <= 0 {
+		return fmt.Errorf("invalid client port %d", options.ClientPort)
+	}
+	// the key must match the certificate, otherwise the server cannot use it
+	if _, err := tls.X509KeyPair([]byte(options.CertificatePEM), []byte(options.PrivateKeyPEM)); err != nil {
+		return fmt.Errorf("invalid certificate or private key, details: %w", err)
+	}
+	block, _ := pem.Decode([]byte(options.CertificatePEM))
+	if block == nil {
+		return fmt.Errorf("invalid certificate, no PEM block found")
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return fmt.Errorf("invalid certificate, details: %w", err)
+	}
+	options.certDER = block.Bytes
+
+	tlsConfig := options.getTLSConfig()
+	return tlsConfig.validate()
+}
+
+// getTLSConfig returns the server TLS configuration that uses the certificate
+func (options *VDeployServerCertificateOptions) getTLSConfig() TLSConfig {
+	return TLSConfig{
+		Name:           ServerTLSConfig,
+		Certificate:    options.CertificateName,
+		CACertificates: options.CACertificates,
+		TLSMode:        options.TLSMode,
+	}
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VDeployServerCertificateOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VDeployServerCertificateOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	return options.setUsePassword(logger)
+}
+
+// VDeployServerCertificate deploys a certificate for the client-server
+// connections. It imports the certificate into the database, makes the server
+// TLS configuration use it, and then checks that each up node presents it to
+// a new client connection. It returns any error encountered.
+func (vcc VClusterCommands) VDeployServerCertificate(options *VDeployServerCertificateOptions) error {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	// validate and analyze options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	// produce deploy server certificate instructions
+	instructions, err := vcc.produceDeployServerCertificateInstructions(options)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	// create a VClusterOpEngine, and add certs to the engine
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+
+	// give the instructions to the VClusterOpEngine to run
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if runError != nil {
+		return fmt.Errorf("fail to deploy certificate %s: %w", options.CertificateName, runError)
+	}
+
+	return nil
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful certificate deployment:
+//   - Get up nodes through HTTPS call
+//   - Import the certificate into the catalog, which distributes it to all nodes
+//   - Set the server TLS configuration to use the certificate
+//   - Verify that all of the up nodes use the new TLS configuration
+//   - Verify that all of the up nodes present the certificate to new client connections
+func (vcc VClusterCommands) produceDeployServerCertificateInstructions(
+	options *VDeployServerCertificateOptions) ([]clusterOp, error) {
+	var instructions []clusterOp
+
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password, DeployServerCertificateCmd)
+	if err != nil {
+		return instructions, err
+	}
+
+	httpsImportCertificateOp, err := makeHTTPSImportCertificateOp(options.usePassword, options.UserName,
+		options.Password, options.CertificateName, options.CertificatePEM, options.PrivateKeyPEM)
+	if err != nil {
+		return instructions, err
+	}
+
+	tlsConfig := options.getTLSConfig()
+	httpsSetTLSConfigOp, err := makeHTTPSSetTLSConfigOp(options.usePassword, options.UserName,
+		options.Password, &tlsConfig)
+	if err != nil {
+		return instructions, err
+	}
+
+	httpsCheckTLSConfigOp, err := makeHTTPSCheckTLSConfigOp(options.usePassword, options.UserName,
+		options.Password, &tlsConfig)
+	if err != nil {
+		return instructions, err
+	}
+
+	clientTLSCheckOp := makeClientTLSCheckOp(options.ClientPort, options.CertificateName, options.certDER)
+
+	instructions = append(instructions,
+		&httpsGetUpNodesOp,
+		&httpsImportCertificateOp,
+		&httpsSetTLSConfigOp,
+		&httpsCheckTLSConfigOp,
+		&clientTLSCheckOp,
+	)
+	return instructions, nil
+}
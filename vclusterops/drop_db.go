@@ -17,14 +17,22 @@ package vclusterops
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/vertica/vcluster/vclusterops/util"
+	"golang.org/x/exp/maps"
 )
 
 // VDropDatabaseOptions adds to VCreateDatabaseOptions the option to force delete directories.
 type VDropDatabaseOptions struct {
 	VCreateDatabaseOptions
-	ForceDelete bool // whether force delete directories
+	ForceDelete   bool // whether force delete directories
+	StopIfRunning bool // whether stop the database first if it is running
+	// whether delete all objects under the communal storage location, which
+	// makes the database unrecoverable. Only for an Eon database.
+	DeleteCommunalStorage bool
+	// must be the communal storage location to confirm DeleteCommunalStorage
+	CommunalStorageDeletionConfirmation string
 }
 
 func VDropDatabaseOptionsFactory() VDropDatabaseOptions {
@@ -39,7 +47,7 @@ func VDropDatabaseOptionsFactory() VDropDatabaseOptions {
 // returns any error encountered.
 func (options *VDropDatabaseOptions) analyzeOptions() error {
 	if len(options.RawHosts) > 0 {
-		hostAddresses, err := util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		hostAddresses, err := options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -50,6 +58,7 @@ func (options *VDropDatabaseOptions) analyzeOptions() error {
 }
 
 func (options *VDropDatabaseOptions) validateParseOptions() error {
+	options.resetReport()
 	if options.DBName == "" {
 		return fmt.Errorf("database name must be provided")
 	}
@@ -58,6 +67,27 @@ func (options *VDropDatabaseOptions) validateParseOptions() error {
 	if err != nil {
 		return err
 	}
+	return options.validateCommunalStorageDeletion()
+}
+
+// validateCommunalStorageDeletion checks that the deletion of the communal
+// storage is explicitly confirmed with its location, so that it cannot be
+// deleted by a mistyped flag or a wrong config file
+func (options *VDropDatabaseOptions) validateCommunalStorageDeletion() error {
+	if !options.DeleteCommunalStorage {
+		return nil
+	}
+	if !options.IsEon {
+		return fmt.Errorf("communal storage can only be deleted for an Eon database")
+	}
+	err := util.ValidateCommunalStorage(options.CommunalStorageLocation, options.ConfigurationParameters)
+	if err != nil {
+		return err
+	}
+	if options.CommunalStorageDeletionConfirmation != options.CommunalStorageLocation {
+		return fmt.Errorf("the confirmation %q does not match the communal storage location %q, "+
+			"the communal storage is not deleted", options.CommunalStorageDeletionConfirmation, options.CommunalStorageLocation)
+	}
 	return nil
 }
 
@@ -90,6 +120,13 @@ func (vcc VClusterCommands) VDropDatabase(options *VDropDatabaseOptions) error {
 		return err
 	}
 
+	if options.StopIfRunning {
+		err = vcc.stopDatabaseIfRunning(options)
+		if err != nil {
+			return err
+		}
+	}
+
 	// produce drop_db instructions
 	instructions, err := vcc.produceDropDBInstructions(&vdb, options)
 	if err != nil {
@@ -97,18 +134,70 @@ func (vcc VClusterCommands) VDropDatabase(options *VDropDatabaseOptions) error {
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to drop database: %w", runError)
 	}
 
+	if options.DeleteCommunalStorage {
+		vcc.Log.PrintWarning("Deleted communal storage %s of database %s", options.CommunalStorageLocation, options.DBName)
+	}
+
+	return nil
+}
+
+// stopDatabaseIfRunning stops the database if any of its nodes is up. Each
+// sandbox that is running is stopped on its own, then the main cluster.
+func (vcc VClusterCommands) stopDatabaseIfRunning(options *VDropDatabaseOptions) error {
+	vdb := makeVCoordinationDatabase()
+	err := vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, AnySandbox)
+	if err != nil {
+		vcc.Log.Info("database is not running, skip stopping it", "details", err.Error())
+		return nil
+	}
+
+	for _, sandbox := range getRunningSandboxes(&vdb) {
+		stopDBOptions := VStopDatabaseOptionsFactory()
+		stopDBOptions.DatabaseOptions = options.DatabaseOptions
+		if sandbox == util.MainClusterSandbox {
+			vcc.Log.PrintInfo("Stopping database %s before dropping it", options.DBName)
+			stopDBOptions.MainCluster = true
+		} else {
+			vcc.Log.PrintInfo("Stopping sandbox %s of database %s before dropping it", sandbox, options.DBName)
+			stopDBOptions.SandboxName = sandbox
+		}
+		err = vcc.VStopDatabase(&stopDBOptions)
+		if err != nil {
+			return fmt.Errorf("fail to stop %s of database %s before dropping it: %w",
+				clusterDisplayName(sandbox), options.DBName, err)
+		}
+	}
 	return nil
 }
 
+// getRunningSandboxes returns the sorted sandboxes that have a node that is not
+// DOWN, with the main cluster last, as the sandboxes are stopped before it
+func getRunningSandboxes(vdb *VCoordinationDatabase) []string {
+	runningSandboxes := make(map[string]bool)
+	for _, vnode := range vdb.HostNodeMap {
+		if vnode.State != util.NodeDownState {
+			runningSandboxes[vnode.Sandbox] = true
+		}
+	}
+	sandboxes := maps.Keys(runningSandboxes)
+	sort.Slice(sandboxes, func(i, j int) bool {
+		// the main cluster, whose sandbox name is empty, goes last
+		if (sandboxes[i] == util.MainClusterSandbox) != (sandboxes[j] == util.MainClusterSandbox) {
+			return sandboxes[j] == util.MainClusterSandbox
+		}
+		return sandboxes[i] < sandboxes[j]
+	})
+	return sandboxes
+}
+
 // produceDropDBInstructions will build a list of instructions to execute for
 // the drop db operation
 //
@@ -117,12 +206,13 @@ func (vcc VClusterCommands) VDropDatabase(options *VDropDatabaseOptions) error {
 //   - Check NMA connectivity
 //   - Check to see if any dbs running
 //   - Delete directories
+//   - Delete communal storage (optional)
 func (vcc VClusterCommands) produceDropDBInstructions(vdb *VCoordinationDatabase, options *VDropDatabaseOptions) ([]clusterOp, error) {
 	var instructions []clusterOp
 
 	hosts := vdb.HostList
 	usePassword := false
-	if options.Password != nil {
+	if options.hasPassword() {
 		usePassword = true
 		err := options.validateUserName(vcc.Log)
 		if err != nil {
@@ -151,5 +241,16 @@ func (vcc VClusterCommands) produceDropDBInstructions(vdb *VCoordinationDatabase
 		&nmaDeleteDirectoriesOp,
 	)
 
+	// the communal storage is deleted last, so that the database can still
+	// be revived if the local directories fail to be deleted
+	if options.DeleteCommunalStorage {
+		nmaDeleteCommunalStorageOp, err := makeNMADeleteCommunalStorageOp(hosts[0], options.DBName,
+			options.CommunalStorageLocation, options.ConfigurationParameters)
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, &nmaDeleteCommunalStorageOp)
+	}
+
 	return instructions, nil
 }
@@ -0,0 +1,87 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+func TestValidateCommunalStorageDeletion(t *testing.T) {
+	options := VDropDatabaseOptionsFactory()
+	options.DBName = "test_db"
+	options.IsEon = true
+	options.CommunalStorageLocation = "s3://bucket/test_db"
+
+	// nothing to confirm without the deletion
+	assert.NoError(t, options.validateParseOptions())
+
+	options.DeleteCommunalStorage = true
+	err := options.validateParseOptions()
+	assert.ErrorContains(t, err, "does not match the communal storage location")
+
+	options.CommunalStorageDeletionConfirmation = "s3://bucket/other_db"
+	err = options.validateParseOptions()
+	assert.ErrorContains(t, err, "does not match the communal storage location")
+
+	options.CommunalStorageDeletionConfirmation = options.CommunalStorageLocation
+	assert.NoError(t, options.validateParseOptions())
+
+	options.IsEon = false
+	err = options.validateParseOptions()
+	assert.ErrorContains(t, err, "only be deleted for an Eon database")
+}
+
+func TestProduceDropDBInstructions(t *testing.T) {
+	options := VDropDatabaseOptionsFactory()
+	options.DBName = "test_db"
+	options.IsEon = true
+	options.CommunalStorageLocation = "s3://bucket/test_db"
+	vdb := makeVCoordinationDatabase()
+	vdb.HostList = []string{"192.168.1.101", "192.168.1.102"}
+
+	vcc := VClusterCommands{}
+	instructions, err := vcc.produceDropDBInstructions(&vdb, &options)
+	assert.NoError(t, err)
+	assert.Len(t, instructions, 3)
+
+	// the communal storage is deleted last, from a single host
+	options.DeleteCommunalStorage = true
+	instructions, err = vcc.produceDropDBInstructions(&vdb, &options)
+	assert.NoError(t, err)
+	assert.Len(t, instructions, 4)
+	deleteOp, ok := instructions[3].(*nmaDeleteCommunalStorageOp)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"192.168.1.101"}, deleteOp.hosts)
+	assert.Contains(t, deleteOp.requestBody, `"communal_location":"s3://bucket/test_db"`)
+}
+
+func TestGetRunningSandboxes(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = vHostNodeMap{
+		"192.168.1.101": {Address: "192.168.1.101", State: util.NodeUpState},
+		"192.168.1.102": {Address: "192.168.1.102", State: util.NodeUpState, Sandbox: "sand2"},
+		"192.168.1.103": {Address: "192.168.1.103", State: util.NodeUpState, Sandbox: "sand1"},
+		"192.168.1.104": {Address: "192.168.1.104", State: util.NodeDownState, Sandbox: "sand3"},
+	}
+	// the sandboxes are stopped before the main cluster
+	assert.Equal(t, []string{"sand1", "sand2", util.MainClusterSandbox}, getRunningSandboxes(&vdb))
+
+	vdb.HostNodeMap["192.168.1.101"].State = util.NodeDownState
+	assert.Equal(t, []string{"sand1", "sand2"}, getRunningSandboxes(&vdb))
+}
@@ -0,0 +1,225 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The categories of the errors that the operations return. Callers can check
+// the category of an error with errors.Is to automate the remediation, e.g.,
+// errors.Is(err, ErrQuorumLost), and get the details through errors.As on the
+// typed error of the category, e.g., *ClusterLeaseNotExpiredError.
+var (
+	// ErrClusterLeaseActive means that another cluster holds an unexpired
+	// lease on the communal storage
+	ErrClusterLeaseActive = errors.New("the cluster lease on the communal storage is active")
+	// ErrNMAUnreachable means that the node management agent (NMA) does not respond
+	ErrNMAUnreachable = errors.New("the node management agent is unreachable")
+	// ErrQuorumLost means that not enough primary nodes are UP or have
+	// succeeded to keep the cluster quorum
+	ErrQuorumLost = errors.New("the cluster quorum is lost")
+	// ErrNodeDown means that the operation cannot find the UP nodes it requires
+	ErrNodeDown = errors.New("the required nodes are down")
+	// ErrCatalogMismatch means that the catalog does not match the input, or
+	// no node has the latest catalog
+	ErrCatalogMismatch = errors.New("the catalog does not match")
+	// ErrInsufficientPrivilege means that the database user does not have
+	// the privilege that the command requires
+	ErrInsufficientPrivilege = errors.New("the database user has insufficient privileges")
+)
+
+// categorizedError attaches a category to an error without changing its message
+type categorizedError struct {
+	category error
+	err      error
+}
+
+// categorizeError wraps err so that errors.Is(err, category) holds
+func categorizeError(category, err error) error {
+	return &categorizedError{category: category, err: err}
+}
+
+func (e *categorizedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *categorizedError) Unwrap() []error {
+	return []error{e.category, e.err}
+}
+
+// ErrorHints suggests to the user how to recover from an error
+type ErrorHints struct {
+	// NextAction is the suggested next action, e.g., "wait for the lease to expire"
+	NextAction string
+	// OptionName is the name of the option that is relevant to the error, if any
+	OptionName string
+	// DocAnchor is the anchor of the section of the vcluster documentation
+	// that describes the error
+	DocAnchor string
+}
+
+// hintedError is implemented by the errors that carry remediation hints
+type hintedError interface {
+	error
+	getHints() ErrorHints
+}
+
+// GetErrorHints returns the remediation hints of the first error in the
+// chain of err that has them
+func GetErrorHints(err error) (hints ErrorHints, found bool) {
+	var hinted hintedError
+	if errors.As(err, &hinted) {
+		return hinted.getHints(), true
+	}
+	return hints, false
+}
+
+func makeClusterLeaseNotExpiredError(expiration string) *ClusterLeaseNotExpiredError {
+	return &ClusterLeaseNotExpiredError{
+		Expiration: expiration,
+		Hints: ErrorHints{
+			NextAction: "Stop the other cluster that uses the communal storage and retry after the lease expires," +
+				" or ignore the lease if you are sure that no other cluster is running",
+			OptionName: "IgnoreClusterLease",
+			DocAnchor:  "cluster-lease",
+		},
+	}
+}
+
+func (e *ClusterLeaseNotExpiredError) getHints() ErrorHints {
+	return e.Hints
+}
+
+func (e *ClusterLeaseNotExpiredError) Is(target error) bool {
+	return target == ErrClusterLeaseActive
+}
+
+// NMAUnreachableError is returned when the node management agent (NMA) on a
+// host does not respond
+type NMAUnreachableError struct {
+	Host  string
+	Err   error
+	Hints ErrorHints
+}
+
+func makeNMAUnreachableError(host string, err error) *NMAUnreachableError {
+	return &NMAUnreachableError{
+		Host: host,
+		Err:  err,
+		Hints: ErrorHints{
+			NextAction: "Start the node management agent on the host, and check that its port is not blocked by a firewall",
+			OptionName: "RawHosts",
+			DocAnchor:  "node-management-agent",
+		},
+	}
+}
+
+func (e *NMAUnreachableError) Error() string {
+	return fmt.Sprintf("%s: %v", localize(MsgNMAUnreachable, e.Host), e.Err)
+}
+
+func (e *NMAUnreachableError) Unwrap() error {
+	return e.Err
+}
+
+func (e *NMAUnreachableError) getHints() ErrorHints {
+	return e.Hints
+}
+
+func (e *NMAUnreachableError) Is(target error) bool {
+	return target == ErrNMAUnreachable
+}
+
+// WrongCredentialError is returned when the HTTPS service of a host rejects
+// the password or the certificate
+type WrongCredentialError struct {
+	OpName string
+	Host   string
+	Hints  ErrorHints
+}
+
+func makeWrongCredentialError(opName, host string) *WrongCredentialError {
+	return &WrongCredentialError{
+		OpName: opName,
+		Host:   host,
+		Hints: ErrorHints{
+			NextAction: "Check the password of the database user, or the key and the certificate used to connect to the HTTPS service",
+			OptionName: "Password",
+			DocAnchor:  "https-authentication",
+		},
+	}
+}
+
+func (e *WrongCredentialError) Error() string {
+	return localize(MsgWrongCredential, e.OpName, e.Host)
+}
+
+func (e *WrongCredentialError) getHints() ErrorHints {
+	return e.Hints
+}
+
+// InsufficientPrivilegeError is returned when the database user does not have
+// the privilege that the command requires, either because the declared
+// UserPrivilege is not enough or because the HTTPS service of a host rejects
+// the user
+type InsufficientPrivilegeError struct {
+	Command  string
+	UserName string
+	Required PrivilegeLevel
+	// the host that rejected the user, empty if the command failed before
+	// sending any request
+	Host  string
+	Err   error
+	Hints ErrorHints
+}
+
+func makeInsufficientPrivilegeError(command, userName string, required PrivilegeLevel,
+	host string, err error) *InsufficientPrivilegeError {
+	return &InsufficientPrivilegeError{
+		Command:  command,
+		UserName: userName,
+		Required: required,
+		Host:     host,
+		Err:      err,
+		Hints: ErrorHints{
+			NextAction: "Run the command as the database superuser, or grant the privileges that it requires to the database user",
+			OptionName: "UserName",
+			DocAnchor:  "command-privileges",
+		},
+	}
+}
+
+func (e *InsufficientPrivilegeError) Error() string {
+	msg := localize(MsgInsufficientPrivilege, e.UserName, e.Required, e.Command)
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+func (e *InsufficientPrivilegeError) Unwrap() error {
+	return e.Err
+}
+
+func (e *InsufficientPrivilegeError) getHints() ErrorHints {
+	return e.Hints
+}
+
+func (e *InsufficientPrivilegeError) Is(target error) bool {
+	return target == ErrInsufficientPrivilege
+}
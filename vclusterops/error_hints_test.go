@@ -0,0 +1,66 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCategories(t *testing.T) {
+	// the typed errors match their categories through the wrapping of the engine
+	err := fmt.Errorf("execute op failed, details: %w", makeClusterLeaseNotExpiredError("2024-03-04 08:05:00"))
+	assert.ErrorIs(t, err, ErrClusterLeaseActive)
+	assert.NotErrorIs(t, err, ErrNMAUnreachable)
+	leaseErr := &ClusterLeaseNotExpiredError{}
+	assert.True(t, errors.As(err, &leaseErr))
+	assert.Equal(t, "2024-03-04 08:05:00", leaseErr.Expiration)
+
+	timeoutErr := errors.New("connection timed out")
+	err = errors.Join(errors.New("other failure"), makeNMAUnreachableError("192.168.1.101", timeoutErr))
+	assert.ErrorIs(t, err, ErrNMAUnreachable)
+	// the cause of the typed error is still reachable
+	assert.ErrorIs(t, err, timeoutErr)
+
+	// only a host that did not answer is unreachable
+	const host = "192.168.1.101"
+	op := makeNMAHealthOp([]string{host})
+	op.setupBasicInfo()
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: EXCEPTION, host: host, err: errors.New("connection refused")},
+	}
+	assert.ErrorIs(t, op.processResult(nil), ErrNMAUnreachable)
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: FAILURE, statusCode: InternalErrorCode, host: host, err: errors.New("internal error")},
+	}
+	err = op.processResult(nil)
+	assert.ErrorContains(t, err, "NMA on host 192.168.1.101 is not healthy")
+	assert.NotErrorIs(t, err, ErrNMAUnreachable)
+
+	assert.ErrorIs(t, &ReIPNoClusterQuorumError{Detail: "no quorum"}, ErrQuorumLost)
+	assert.ErrorIs(t, &ReviveDBNodeCountMismatchError{}, ErrCatalogMismatch)
+
+	// a categorized error keeps its message
+	causeErr := errors.New("all nodes must be up or standby")
+	err = categorizeError(ErrNodeDown, causeErr)
+	assert.EqualError(t, err, "all nodes must be up or standby")
+	assert.ErrorIs(t, fmt.Errorf("remove_node failed: %w", err), ErrNodeDown)
+	assert.ErrorIs(t, err, causeErr)
+	assert.NotErrorIs(t, err, ErrQuorumLost)
+}
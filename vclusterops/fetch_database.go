@@ -45,7 +45,7 @@ func (options *VFetchCoordinationDatabaseOptions) validateParseOptions(logger vl
 func (options *VFetchCoordinationDatabaseOptions) analyzeOptions() error {
 	// resolve RawHosts to be IP addresses
 	if len(options.RawHosts) > 0 {
-		hostAddresses, err := util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		hostAddresses, err := options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -101,11 +101,10 @@ func (vcc VClusterCommands) VFetchCoordinationDatabase(options *VFetchCoordinati
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 
 	// nmaVDB is an object obtained from the read catalog editor result
 	// we use nmaVDB data to complete vdb
@@ -23,6 +23,12 @@ func VFetchNodeStateOptionsFactory() VFetchNodeStateOptions {
 }
 
 func (options *VFetchNodeStateOptions) validateParseOptions(vcc VClusterCommands) error {
+	options.commandName = commandFetchNodeState
+	options.resetReport()
+	if err := options.validatePrivilege(commandFetchNodeState); err != nil {
+		return err
+	}
+
 	if len(options.RawHosts) == 0 {
 		return fmt.Errorf("must specify a host or host list")
 	}
@@ -36,7 +42,7 @@ func (options *VFetchNodeStateOptions) validateParseOptions(vcc VClusterCommands
 
 func (options *VFetchNodeStateOptions) analyzeOptions() error {
 	if len(options.RawHosts) > 0 {
-		hostAddresses, err := util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		hostAddresses, err := options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -81,11 +87,10 @@ func (vcc VClusterCommands) VFetchNodeState(options *VFetchNodeStateOptions) ([]
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	nodeStates := clusterOpEngine.execContext.nodesInfo
 	if runError == nil {
 		// fill node version
@@ -171,7 +176,7 @@ func (vcc VClusterCommands) produceListAllNodesInstructions(
 
 	// validate user name
 	usePassword := false
-	if options.Password != nil {
+	if options.hasPassword() {
 		usePassword = true
 		err := options.validateUserName(vcc.Log)
 		if err != nil {
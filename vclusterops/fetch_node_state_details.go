@@ -0,0 +1,227 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// NodeStateDetails is the reconciled state of a node
+type NodeStateDetails struct {
+	Name             string   `json:"name"`
+	Address          string   `json:"address"`
+	Subcluster       string   `json:"subcluster"`
+	Sandbox          string   `json:"sandbox"`
+	State            string   `json:"state"`
+	IsPrimary        bool     `json:"is_primary"`
+	CatalogVersion   int64    `json:"catalog_version"`
+	UpSince          string   `json:"up_since"`
+	UptimeSeconds    int64    `json:"uptime_seconds"`
+	DepotFillPercent float64  `json:"depot_fill_percent"`
+	Version          string   `json:"version"`
+	CatalogPath      string   `json:"catalog_path"`
+	DepotPath        string   `json:"depot_path"`
+	DataPaths        []string `json:"data_paths"`
+	// reporting host -> state, for the hosts whose view disagrees with the reconciled state
+	ConflictingViews map[string]string `json:"conflicting_views,omitempty"`
+}
+
+// NodeStatesReport is the result of VFetchNodeStateDetails
+type NodeStatesReport struct {
+	Nodes []NodeStateDetails `json:"nodes"`
+	// hosts that did not return their view of the cluster
+	UnreachableHosts []string `json:"unreachable_hosts,omitempty"`
+	// SplitBrain is set when the UP nodes of a cluster do not see each other as UP
+	SplitBrain bool `json:"split_brain"`
+	// the sandboxes with a split brain, the main cluster is an empty string
+	SplitBrainClusters []string `json:"split_brain_clusters,omitempty"`
+}
+
+// VFetchNodeStateDetails collects the node states from every reachable host, and
+// reconciles the views into a single state per node. A conflicting view is kept in
+// ConflictingViews of the node, and a partitioned cluster is reported as a split brain.
+func (vcc VClusterCommands) VFetchNodeStateDetails(options *VFetchNodeStateOptions) (NodeStatesReport, error) {
+	var report NodeStatesReport
+
+	err := options.validateAnalyzeOptions(vcc)
+	if err != nil {
+		return report, err
+	}
+
+	usePassword := false
+	if options.hasPassword() {
+		usePassword = true
+		err = options.validateUserName(vcc.Log)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	hostNodeStateViews := make(map[string][]NodeStateDetails)
+	httpsGetClusterNodeStatesOp, err := makeHTTPSGetClusterNodeStatesOp(options.Hosts,
+		usePassword, options.UserName, options.Password, hostNodeStateViews)
+	if err != nil {
+		return report, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	instructions := []clusterOp{&httpsGetClusterNodeStatesOp}
+
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		return report, fmt.Errorf("fail to fetch node states: %w", err)
+	}
+
+	report = reconcileNodeStateViews(hostNodeStateViews)
+	report.UnreachableHosts = util.SliceDiff(options.Hosts, maps.Keys(hostNodeStateViews))
+	slices.Sort(report.UnreachableHosts)
+	if report.SplitBrain {
+		vcc.Log.PrintWarning("Split brain detected in the clusters %q", report.SplitBrainClusters)
+	}
+	return report, nil
+}
+
+// reconcileNodeStateViews merges the views of the reporting hosts. Only the
+// hosts in the same cluster (main cluster or sandbox) as a node can see its state,
+// so their views are preferred. Among them, the majority state wins and a tie is
+// broken by the node's own view.
+func reconcileNodeStateViews(hostNodeStateViews map[string][]NodeStateDetails) NodeStatesReport {
+	var report NodeStatesReport
+
+	reporters := maps.Keys(hostNodeStateViews)
+	slices.Sort(reporters)
+
+	// find the cluster of each reporting host from its own entry
+	reporterCluster := make(map[string]string)
+	for _, reporter := range reporters {
+		for i := range hostNodeStateViews[reporter] {
+			if n := &hostNodeStateViews[reporter][i]; n.Address == reporter {
+				reporterCluster[reporter] = n.Sandbox
+				break
+			}
+		}
+	}
+
+	// node address -> reporting host -> view of the node
+	nodeViews := make(map[string]map[string]*NodeStateDetails)
+	for _, reporter := range reporters {
+		for i := range hostNodeStateViews[reporter] {
+			n := &hostNodeStateViews[reporter][i]
+			if _, ok := nodeViews[n.Address]; !ok {
+				nodeViews[n.Address] = make(map[string]*NodeStateDetails)
+			}
+			nodeViews[n.Address][reporter] = n
+		}
+	}
+
+	for _, views := range nodeViews {
+		report.Nodes = append(report.Nodes, reconcileNodeViews(views, reporterCluster))
+	}
+	slices.SortFunc(report.Nodes, func(a, b NodeStateDetails) int { return strings.Compare(a.Name, b.Name) })
+
+	report.SplitBrainClusters = findSplitBrainClusters(reporters, reporterCluster, nodeViews)
+	report.SplitBrain = len(report.SplitBrainClusters) > 0
+	return report
+}
+
+func reconcileNodeViews(views map[string]*NodeStateDetails, reporterCluster map[string]string) NodeStateDetails {
+	var candidates []string
+	for reporter, view := range views {
+		if cluster, ok := reporterCluster[reporter]; ok && cluster == view.Sandbox {
+			candidates = append(candidates, reporter)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = maps.Keys(views)
+	}
+	slices.Sort(candidates)
+
+	stateCount := make(map[string]int)
+	for _, reporter := range candidates {
+		stateCount[views[reporter].State]++
+	}
+	var state string
+	for _, reporter := range candidates {
+		s := views[reporter].State
+		if stateCount[s] > stateCount[state] {
+			state = s
+		}
+	}
+	// the node's own view breaks a tie
+	selfView, hasSelfView := views[views[candidates[0]].Address]
+	if hasSelfView && stateCount[selfView.State] == stateCount[state] {
+		state = selfView.State
+	}
+
+	// the node's own view has the most accurate local details
+	var node NodeStateDetails
+	if hasSelfView && selfView.State == state {
+		node = *selfView
+	} else {
+		for _, reporter := range candidates {
+			if views[reporter].State == state {
+				node = *views[reporter]
+				break
+			}
+		}
+	}
+
+	for _, reporter := range candidates {
+		if s := views[reporter].State; s != state {
+			if node.ConflictingViews == nil {
+				node.ConflictingViews = make(map[string]string)
+			}
+			node.ConflictingViews[reporter] = s
+		}
+	}
+	return node
+}
+
+// findSplitBrainClusters returns the clusters in which two UP hosts
+// each see the other one as not UP
+func findSplitBrainClusters(reporters []string, reporterCluster map[string]string,
+	nodeViews map[string]map[string]*NodeStateDetails) []string {
+	seesUp := func(reporter, host string) bool {
+		view, ok := nodeViews[host][reporter]
+		return ok && view.State == util.NodeUpState
+	}
+
+	var upReporters []string
+	for _, reporter := range reporters {
+		if _, ok := reporterCluster[reporter]; ok && seesUp(reporter, reporter) {
+			upReporters = append(upReporters, reporter)
+		}
+	}
+
+	var clusters []string
+	for i, a := range upReporters {
+		for _, b := range upReporters[i+1:] {
+			cluster := reporterCluster[a]
+			if cluster != reporterCluster[b] || slices.Contains(clusters, cluster) {
+				continue
+			}
+			if !seesUp(a, b) && !seesUp(b, a) {
+				clusters = append(clusters, cluster)
+			}
+		}
+	}
+	slices.Sort(clusters)
+	return clusters
+}
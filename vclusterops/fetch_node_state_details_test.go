@@ -0,0 +1,97 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+func makeNodeStateView(states map[string]string, sandbox string) []NodeStateDetails {
+	var view []NodeStateDetails
+	for host, state := range states {
+		view = append(view, NodeStateDetails{Name: "node_" + host, Address: host, State: state, Sandbox: sandbox})
+	}
+	return view
+}
+
+func TestReconcileNodeStateViews(t *testing.T) {
+	const h1, h2, h3, h4 = "192.168.1.101", "192.168.1.102", "192.168.1.103", "192.168.1.104"
+	up, down := util.NodeUpState, util.NodeDownState
+
+	// all hosts agree
+	views := map[string][]NodeStateDetails{
+		h1: makeNodeStateView(map[string]string{h1: up, h2: up, h3: down}, ""),
+		h2: makeNodeStateView(map[string]string{h1: up, h2: up, h3: down}, ""),
+	}
+	report := reconcileNodeStateViews(views)
+	assert.False(t, report.SplitBrain)
+	assert.Len(t, report.Nodes, 3)
+	assert.Equal(t, h1, report.Nodes[0].Address)
+	assert.Equal(t, down, report.Nodes[2].State)
+	assert.Nil(t, report.Nodes[2].ConflictingViews)
+
+	// the majority wins, and the minority view is kept
+	views[h3] = makeNodeStateView(map[string]string{h1: up, h2: up, h3: up}, "")
+	report = reconcileNodeStateViews(views)
+	assert.False(t, report.SplitBrain)
+	assert.Equal(t, down, report.Nodes[2].State)
+	assert.Equal(t, map[string]string{h3: up}, report.Nodes[2].ConflictingViews)
+
+	// two partitions that see each other as DOWN
+	views = map[string][]NodeStateDetails{
+		h1: makeNodeStateView(map[string]string{h1: up, h2: up, h3: down, h4: down}, ""),
+		h3: makeNodeStateView(map[string]string{h1: down, h2: down, h3: up, h4: up}, ""),
+	}
+	report = reconcileNodeStateViews(views)
+	assert.True(t, report.SplitBrain)
+	assert.Equal(t, []string{""}, report.SplitBrainClusters)
+	// a tie is broken by the node's own view
+	assert.Equal(t, up, report.Nodes[0].State)
+	assert.Equal(t, map[string]string{h3: down}, report.Nodes[0].ConflictingViews)
+
+	// a sandbox sees the main cluster nodes as DOWN, which is not a conflict
+	views = map[string][]NodeStateDetails{
+		h1: append(makeNodeStateView(map[string]string{h1: up, h2: up}, ""),
+			makeNodeStateView(map[string]string{h3: down}, "sand1")...),
+		h3: append(makeNodeStateView(map[string]string{h1: down, h2: down}, ""),
+			makeNodeStateView(map[string]string{h3: up}, "sand1")...),
+	}
+	report = reconcileNodeStateViews(views)
+	assert.False(t, report.SplitBrain)
+	for _, n := range report.Nodes {
+		assert.Equal(t, up, n.State)
+		assert.Nil(t, n.ConflictingViews)
+	}
+}
+
+func TestAsNodeStateDetails(t *testing.T) {
+	now := time.Date(2024, 4, 5, 12, 0, 0, 0, time.UTC)
+	node := nodeStateDetailInfo{CatalogVersion: 10, UpSince: "2024-04-05T11:00:00Z", DepotUsagePercent: 37.5}
+	node.Name = "v_test_db_node0001"
+	node.State = util.NodeUpState
+	n := node.asNodeStateDetails(now)
+	assert.Equal(t, int64(3600), n.UptimeSeconds)
+	assert.Equal(t, int64(10), n.CatalogVersion)
+	assert.Equal(t, 37.5, n.DepotFillPercent)
+
+	// no uptime for a DOWN node
+	node.UpSince = ""
+	assert.Zero(t, node.asNodeStateDetails(now).UptimeSeconds)
+}
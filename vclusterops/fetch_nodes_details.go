@@ -18,7 +18,6 @@ package vclusterops
 import (
 	"fmt"
 
-	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
@@ -59,6 +58,10 @@ type StorageLocations struct {
 	StorageLocList []StorageLocation `json:"storage_location_list"`
 }
 
+func (StorageLocations) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 type NodeDetails struct {
 	NodeState
 	StorageLocations
@@ -96,7 +99,7 @@ func (options *VFetchNodesDetailsOptions) validateParseOptions(logger vlog.Print
 func (options *VFetchNodesDetailsOptions) analyzeOptions() (err error) {
 	// resolve RawHosts to be IP addresses
 	if len(options.RawHosts) > 0 {
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -125,6 +128,10 @@ func (vcc VClusterCommands) VFetchNodesDetails(options *VFetchNodesDetailsOption
 	if err != nil {
 		return nodesDetails, err
 	}
+	err = options.runPreflightProbe(vcc)
+	if err != nil {
+		return nodesDetails, err
+	}
 
 	hostsWithNodeDetails := make(hostNodeDetailsMap, len(options.Hosts))
 
@@ -133,10 +140,9 @@ func (vcc VClusterCommands) VFetchNodesDetails(options *VFetchNodesDetailsOption
 		return nodesDetails, fmt.Errorf("fail to produce instructions: %w", err)
 	}
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
-	err = clusterOpEngine.run(vcc.Log)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if err != nil {
 		return nodesDetails, fmt.Errorf("failed to fetch node details on hosts %v: %w", options.Hosts, err)
 	}
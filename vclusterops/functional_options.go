@@ -0,0 +1,197 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+// The functional options build the options of a command in one expression,
+// on top of the defaults of its factory, e.g.,
+//
+//	options := VReviveDBOptionsFactoryWith(
+//		WithDBName("test_db"),
+//		WithHosts("10.20.30.40", "10.20.30.41"),
+//		WithCommunalStorageLocation("s3://bucket/test_db"),
+//		WithTimeout(600),
+//	)
+//
+// An option can only be passed to the commands that have the field it sets,
+// which is checked at compile time.
+
+// DatabaseOption sets a field of DatabaseOptions, which all of the commands have
+type DatabaseOption func(*DatabaseOptions)
+
+// ReviveDBOption sets a field of VReviveDatabaseOptions
+type ReviveDBOption interface {
+	applyToReviveDB(*VReviveDatabaseOptions)
+}
+
+// StartDBOption sets a field of VStartDatabaseOptions
+type StartDBOption interface {
+	applyToStartDB(*VStartDatabaseOptions)
+}
+
+// StopDBOption sets a field of VStopDatabaseOptions
+type StopDBOption interface {
+	applyToStopDB(*VStopDatabaseOptions)
+}
+
+func (o DatabaseOption) applyToReviveDB(options *VReviveDatabaseOptions) { o(&options.DatabaseOptions) }
+func (o DatabaseOption) applyToStartDB(options *VStartDatabaseOptions)   { o(&options.DatabaseOptions) }
+func (o DatabaseOption) applyToStopDB(options *VStopDatabaseOptions)     { o(&options.DatabaseOptions) }
+
+// ApplyOptions sets the fields of the options
+func (opt *DatabaseOptions) ApplyOptions(databaseOptions ...DatabaseOption) {
+	for _, o := range databaseOptions {
+		o(opt)
+	}
+}
+
+// WithDBName sets the name of the database
+func WithDBName(dbName string) DatabaseOption {
+	return func(opt *DatabaseOptions) {
+		opt.DBName = dbName
+	}
+}
+
+// WithHosts sets the hosts, which are resolved to IP addresses when the command runs
+func WithHosts(hosts ...string) DatabaseOption {
+	return func(opt *DatabaseOptions) {
+		opt.RawHosts = hosts
+	}
+}
+
+// WithIPv6 sets whether the hosts are resolved to IPv6 addresses
+func WithIPv6(ipv6 bool) DatabaseOption {
+	return func(opt *DatabaseOptions) {
+		opt.IPv6 = ipv6
+	}
+}
+
+// WithDualStack sets whether the hosts can have addresses of both families
+func WithDualStack(dualStack bool) DatabaseOption {
+	return func(opt *DatabaseOptions) {
+		opt.DualStack = dualStack
+	}
+}
+
+// WithCommunalStorageLocation sets the communal storage location of an Eon database
+func WithCommunalStorageLocation(location string) DatabaseOption {
+	return func(opt *DatabaseOptions) {
+		opt.CommunalStorageLocation = location
+	}
+}
+
+// WithUserName sets the name of the database user
+func WithUserName(userName string) DatabaseOption {
+	return func(opt *DatabaseOptions) {
+		opt.UserName = userName
+	}
+}
+
+// WithPassword sets the password of the database user
+func WithPassword(password string) DatabaseOption {
+	return func(opt *DatabaseOptions) {
+		opt.SetPassword(password)
+	}
+}
+
+// WithTLS sets the TLS key, certificate and CA certificate
+func WithTLS(key, cert, caCert string) DatabaseOption {
+	return func(opt *DatabaseOptions) {
+		opt.Key = key
+		opt.Cert = cert
+		opt.CaCert = caCert
+	}
+}
+
+// WithConfigurationParameters sets the configuration parameters of the database
+func WithConfigurationParameters(configurationParameters map[string]string) DatabaseOption {
+	return func(opt *DatabaseOptions) {
+		opt.ConfigurationParameters = configurationParameters
+	}
+}
+
+// TimeoutOption is the timeout in seconds of the longest step of a command,
+// which is both a ReviveDBOption and a StartDBOption
+type TimeoutOption int
+
+// WithTimeout sets the timeout in seconds of loading the remote catalog for
+// revive_db, and of polling the node states for start_db
+func WithTimeout(seconds int) TimeoutOption {
+	return TimeoutOption(seconds)
+}
+
+func (o TimeoutOption) applyToReviveDB(options *VReviveDatabaseOptions) {
+	options.LoadCatalogTimeout = uint(o)
+}
+
+func (o TimeoutOption) applyToStartDB(options *VStartDatabaseOptions) {
+	options.StatePollingTimeout = int(o)
+}
+
+type reviveDBOptionFunc func(*VReviveDatabaseOptions)
+
+func (f reviveDBOptionFunc) applyToReviveDB(options *VReviveDatabaseOptions) { f(options) }
+
+// WithRestorePoint sets the restore point to revive the database to
+func WithRestorePoint(restorePoint RestorePointPolicy) ReviveDBOption {
+	return reviveDBOptionFunc(func(options *VReviveDatabaseOptions) {
+		options.RestorePoint = restorePoint
+	})
+}
+
+// WithIgnoreClusterLease sets whether revive_db ignores the cluster lease
+func WithIgnoreClusterLease(ignore bool) ReviveDBOption {
+	return reviveDBOptionFunc(func(options *VReviveDatabaseOptions) {
+		options.IgnoreClusterLease = ignore
+	})
+}
+
+type stopDBOptionFunc func(*VStopDatabaseOptions)
+
+func (f stopDBOptionFunc) applyToStopDB(options *VStopDatabaseOptions) { f(options) }
+
+// WithDrainSeconds sets the time in seconds that stop_db waits for the users to disconnect
+func WithDrainSeconds(drainSeconds int) StopDBOption {
+	return stopDBOptionFunc(func(options *VStopDatabaseOptions) {
+		options.SetDrainSeconds(drainSeconds)
+	})
+}
+
+// VReviveDBOptionsFactoryWith makes the options of revive_db with the given options
+func VReviveDBOptionsFactoryWith(reviveDBOptions ...ReviveDBOption) VReviveDatabaseOptions {
+	options := VReviveDBOptionsFactory()
+	for _, o := range reviveDBOptions {
+		o.applyToReviveDB(&options)
+	}
+	return options
+}
+
+// VStartDatabaseOptionsFactoryWith makes the options of start_db with the given options
+func VStartDatabaseOptionsFactoryWith(startDBOptions ...StartDBOption) VStartDatabaseOptions {
+	options := VStartDatabaseOptionsFactory()
+	for _, o := range startDBOptions {
+		o.applyToStartDB(&options)
+	}
+	return options
+}
+
+// VStopDatabaseOptionsFactoryWith makes the options of stop_db with the given options
+func VStopDatabaseOptionsFactoryWith(stopDBOptions ...StopDBOption) VStopDatabaseOptions {
+	options := VStopDatabaseOptionsFactory()
+	for _, o := range stopDBOptions {
+		o.applyToStopDB(&options)
+	}
+	return options
+}
@@ -0,0 +1,54 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalOptions(t *testing.T) {
+	reviveOptions := VReviveDBOptionsFactoryWith(
+		WithDBName("test_db"),
+		WithHosts("10.20.30.40", "10.20.30.41"),
+		WithCommunalStorageLocation("/communal"),
+		WithPassword("secret"),
+		WithTimeout(600),
+		WithIgnoreClusterLease(true),
+	)
+	assert.Equal(t, "test_db", reviveOptions.DBName)
+	assert.Equal(t, []string{"10.20.30.40", "10.20.30.41"}, reviveOptions.RawHosts)
+	assert.Equal(t, "/communal", reviveOptions.CommunalStorageLocation)
+	assert.Equal(t, "secret", *reviveOptions.Password)
+	assert.Equal(t, uint(600), reviveOptions.LoadCatalogTimeout)
+	assert.True(t, reviveOptions.IgnoreClusterLease)
+
+	// the defaults of the factory are kept
+	startOptions := VStartDatabaseOptionsFactoryWith(WithDBName("test_db"))
+	assert.Equal(t, VStartDatabaseOptionsFactory().StatePollingTimeout, startOptions.StatePollingTimeout)
+	startOptions = VStartDatabaseOptionsFactoryWith(WithTimeout(30), WithTLS("key", "cert", "ca-cert"))
+	assert.Equal(t, 30, startOptions.StatePollingTimeout)
+	assert.Equal(t, "ca-cert", startOptions.CaCert)
+
+	stopOptions := VStopDatabaseOptionsFactoryWith(WithDrainSeconds(0))
+	assert.Equal(t, 0, *stopOptions.DrainSeconds)
+
+	// the options common to all of the commands also apply to the options made by a factory
+	stopOptions.ApplyOptions(WithUserName("dbadmin"), WithIPv6(true))
+	assert.Equal(t, "dbadmin", stopOptions.UserName)
+	assert.True(t, stopOptions.IPv6)
+}
@@ -0,0 +1,158 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type VGetClusterLeaseOptions struct {
+	DatabaseOptions
+}
+
+func VGetClusterLeaseOptionsFactory() VGetClusterLeaseOptions {
+	options := VGetClusterLeaseOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+// ClusterLease is the lease that a running cluster holds on the communal
+// storage of a database, which prevents another cluster from reviving it
+type ClusterLease struct {
+	// hosts of the nodes in the description file, which are the hosts of
+	// the cluster that holds the lease
+	OwnerHosts []string `json:"owner_hosts"`
+	// UTC time when the lease expires
+	Expiration time.Time `json:"expiration"`
+	// seconds until the lease expires, zero if it has expired
+	RemainingSeconds int64 `json:"remaining_seconds"`
+	IsExpired        bool  `json:"is_expired"`
+}
+
+func (options *VGetClusterLeaseOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandGetClusterLease, logger)
+	if err != nil {
+		return err
+	}
+	return util.ValidateCommunalStorage(options.CommunalStorageLocation, options.ConfigurationParameters)
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VGetClusterLeaseOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VGetClusterLeaseOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// VGetClusterLease reads the cluster lease from the description file on the
+// communal storage, without reviving the database. It tells whether the
+// database can be revived without IgnoreClusterLease, or how long to wait.
+func (vcc VClusterCommands) VGetClusterLease(options *VGetClusterLeaseOptions) (lease ClusterLease, err error) {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	// validate and analyze options
+	err = options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return lease, err
+	}
+
+	// produce get cluster lease instructions
+	vdb := makeVCoordinationDatabase()
+	instructions, err := vcc.produceGetClusterLeaseInstructions(options, &vdb)
+	if err != nil {
+		return lease, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	// create a VClusterOpEngine, and add certs to the engine
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+
+	// give the instructions to the VClusterOpEngine to run
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if runError != nil {
+		return lease, fmt.Errorf("fail to get cluster lease: %w", runError)
+	}
+
+	return makeClusterLease(clusterOpEngine.execContext.clusterLeaseExpiration, &vdb, time.Now().UTC())
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful get_cluster_lease:
+//   - Check NMA connectivity
+//   - Download the description file from the communal storage on the initiator
+func (vcc VClusterCommands) produceGetClusterLeaseInstructions(options *VGetClusterLeaseOptions,
+	vdb *VCoordinationDatabase) ([]clusterOp, error) {
+	var instructions []clusterOp
+
+	nmaHealthOp := makeNMAHealthOp(options.Hosts)
+
+	currConfigFileSrcPath := options.getCurrConfigFilePath()
+	nmaDownLoadFileOp, err := makeNMADownloadFileOp(options.Hosts, currConfigFileSrcPath, currConfigFileDestPath,
+		catalogPath, options.ConfigurationParameters, vdb)
+	if err != nil {
+		return instructions, err
+	}
+	initiator, err := options.selectInitiator(vcc.Context(), options.Hosts)
+	if err != nil {
+		return instructions, err
+	}
+	nmaDownLoadFileOp.setInitiator(initiator)
+
+	instructions = append(instructions,
+		&nmaHealthOp,
+		&nmaDownLoadFileOp)
+	return instructions, nil
+}
+
+// makeClusterLease builds the cluster lease from the lease expiration and
+// the nodes in the description file
+func makeClusterLease(clusterLeaseExpiration string, vdb *VCoordinationDatabase, utcNow time.Time) (lease ClusterLease, err error) {
+	lease.Expiration, err = time.Parse(expirationStringLayout, clusterLeaseExpiration)
+	if err != nil {
+		return lease, fmt.Errorf("fail to convert cluster-lease-expiration string to a time: %w", err)
+	}
+	lease.IsExpired = !utcNow.Before(lease.Expiration)
+	if !lease.IsExpired {
+		lease.RemainingSeconds = int64(lease.Expiration.Sub(utcNow).Seconds())
+	}
+	for _, vnode := range vdb.HostNodeMap {
+		lease.OwnerHosts = append(lease.OwnerHosts, vnode.Address)
+	}
+	sort.Strings(lease.OwnerHosts)
+	return lease, nil
+}
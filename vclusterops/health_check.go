@@ -0,0 +1,386 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// HealthSeverity is the severity of a health check result. The severities
+// are ordered, so that the severity of a report is its most severe result.
+type HealthSeverity int
+
+const (
+	HealthOK HealthSeverity = iota
+	HealthWarning
+	HealthCritical
+)
+
+func (s HealthSeverity) String() string {
+	switch s {
+	case HealthOK:
+		return "OK"
+	case HealthWarning:
+		return "WARNING"
+	case HealthCritical:
+		return "CRITICAL"
+	}
+	return fmt.Sprintf("HealthSeverity(%d)", int(s))
+}
+
+func (s HealthSeverity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// the checks run by VHealthCheck
+const (
+	HealthCheckNMA                  = "nma_health"
+	HealthCheckNodeState            = "node_state"
+	HealthCheckDiskSpace            = "disk_space"
+	HealthCheckClockSkew            = "clock_skew"
+	HealthCheckNodeStateConsistency = "node_state_consistency"
+)
+
+type HealthCheckResult struct {
+	Check    string         `json:"check"`
+	Host     string         `json:"host,omitempty"`
+	Severity HealthSeverity `json:"severity"`
+	Message  string         `json:"message"`
+}
+
+type HealthCheckReport struct {
+	// the most severe result
+	Severity HealthSeverity      `json:"severity"`
+	Results  []HealthCheckResult `json:"results"`
+}
+
+// IsReady returns true if no check is critical, so the cluster can serve queries
+func (report *HealthCheckReport) IsReady() bool {
+	return report.Severity < HealthCritical
+}
+
+func (report *HealthCheckReport) add(check, host string, severity HealthSeverity, format string, args ...any) {
+	report.Results = append(report.Results, HealthCheckResult{
+		Check:    check,
+		Host:     host,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+	report.Severity = util.Max(report.Severity, severity)
+}
+
+type VHealthCheckOptions struct {
+	DatabaseOptions
+	// disk usage percentages of a database path that raise a warning or a critical result
+	DiskWarningPercent  float64
+	DiskCriticalPercent float64
+	// clock skew between hosts that raises a warning or a critical result
+	ClockSkewWarningSeconds  float64
+	ClockSkewCriticalSeconds float64
+}
+
+const (
+	defaultDiskWarningPercent       = 80
+	defaultDiskCriticalPercent      = 95
+	defaultClockSkewWarningSeconds  = 1
+	defaultClockSkewCriticalSeconds = 5
+	fullDiskPercent                 = 100
+)
+
+func VHealthCheckOptionsFactory() VHealthCheckOptions {
+	options := VHealthCheckOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VHealthCheckOptions) setDefaultValues() {
+	options.DatabaseOptions.setDefaultValues()
+	options.DiskWarningPercent = defaultDiskWarningPercent
+	options.DiskCriticalPercent = defaultDiskCriticalPercent
+	options.ClockSkewWarningSeconds = defaultClockSkewWarningSeconds
+	options.ClockSkewCriticalSeconds = defaultClockSkewCriticalSeconds
+}
+
+func (options *VHealthCheckOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandHealthCheck, logger)
+	if err != nil {
+		return err
+	}
+	if options.DiskWarningPercent <= 0 || options.DiskWarningPercent > options.DiskCriticalPercent ||
+		options.DiskCriticalPercent > fullDiskPercent {
+		return fmt.Errorf("the disk usage thresholds must satisfy 0 < warning (%.1f) <= critical (%.1f) <= 100",
+			options.DiskWarningPercent, options.DiskCriticalPercent)
+	}
+	if options.ClockSkewWarningSeconds <= 0 || options.ClockSkewWarningSeconds > options.ClockSkewCriticalSeconds {
+		return fmt.Errorf("the clock skew thresholds must satisfy 0 < warning (%.1f) <= critical (%.1f)",
+			options.ClockSkewWarningSeconds, options.ClockSkewCriticalSeconds)
+	}
+	return nil
+}
+
+func (options *VHealthCheckOptions) analyzeOptions() (err error) {
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VHealthCheckOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	return options.setUsePasswordAndValidateUsernameIfNeeded(logger)
+}
+
+// VHealthCheck runs a one-shot diagnostic of the cluster: NMA health, node states,
+// disk space of the database paths, clock skew between hosts, and whether the hosts agree on the node states.
+// A failed check is reported in the returned report rather than as an error, so the
+// report can be used by readiness probes. An error is returned only for invalid options.
+func (vcc VClusterCommands) VHealthCheck(options *VHealthCheckOptions) (HealthCheckReport, error) {
+	var report HealthCheckReport
+
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return report, err
+	}
+
+	nmaHosts := vcc.checkNMAHealth(options, &report)
+	nodes, ok := vcc.checkNodeStates(options, &report)
+	if len(nmaHosts) > 0 {
+		if ok {
+			vcc.checkDiskSpace(options, nodes, nmaHosts, &report)
+		} else {
+			report.add(HealthCheckDiskSpace, "", HealthWarning,
+				"skipped, the database paths are unknown because the node states are unavailable")
+		}
+		vcc.checkClockSkew(options, nmaHosts, &report)
+	}
+
+	vcc.Log.Info("health check report", "severity", report.Severity, "results", report.Results)
+	return report, nil
+}
+
+// checkNMAHealth returns the hosts with a healthy NMA
+func (vcc VClusterCommands) checkNMAHealth(options *VHealthCheckOptions, report *HealthCheckReport) []string {
+	var vdb VCoordinationDatabase
+	nmaGetHealthyNodesOp := makeNMAGetHealthyNodesOp(options.Hosts, &vdb)
+	clusterOpEngine := options.makeClusterOpEngine([]clusterOp{&nmaGetHealthyNodesOp})
+	err := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		vcc.Log.Info("NMA health check failed", "error", err)
+	}
+
+	for _, host := range options.Hosts {
+		if slices.Contains(vdb.HostList, host) {
+			report.add(HealthCheckNMA, host, HealthOK, "NMA is healthy")
+		} else {
+			report.add(HealthCheckNMA, host, HealthCritical, "NMA is down or unresponsive")
+		}
+	}
+	return vdb.HostList
+}
+
+// checkNodeStates checks the node states and whether the hosts agree on them. It returns
+// false if no host returned the node states.
+func (vcc VClusterCommands) checkNodeStates(options *VHealthCheckOptions,
+	report *HealthCheckReport) ([]NodeStateDetails, bool) {
+	hostNodeStateViews := make(map[string][]NodeStateDetails)
+	httpsGetClusterNodeStatesOp, err := makeHTTPSGetClusterNodeStatesOp(options.Hosts,
+		options.usePassword, options.UserName, options.Password, hostNodeStateViews)
+	if err == nil {
+		clusterOpEngine := options.makeClusterOpEngine([]clusterOp{&httpsGetClusterNodeStatesOp})
+		err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	}
+	if len(hostNodeStateViews) == 0 {
+		report.add(HealthCheckNodeState, "", HealthCritical, "no host returned the node states: %v", err)
+		report.add(HealthCheckNodeStateConsistency, "", HealthWarning,
+			"skipped, the node states are unavailable")
+		return nil, false
+	}
+
+	states := reconcileNodeStateViews(hostNodeStateViews)
+	addNodeStateResults(states, report)
+	return states.Nodes, true
+}
+
+func addNodeStateResults(states NodeStatesReport, report *HealthCheckReport) {
+	// the quorum of each cluster, the main cluster is an empty string
+	primaryCount := make(map[string]int)
+	upPrimaryCount := make(map[string]int)
+	consistent := true
+	for i := range states.Nodes {
+		n := &states.Nodes[i]
+		if n.IsPrimary {
+			primaryCount[n.Sandbox]++
+		}
+		if n.State == util.NodeUpState {
+			if n.IsPrimary {
+				upPrimaryCount[n.Sandbox]++
+			}
+			report.add(HealthCheckNodeState, n.Address, HealthOK, "node %s is UP", n.Name)
+		} else {
+			report.add(HealthCheckNodeState, n.Address, HealthWarning, "node %s is %s", n.Name, n.State)
+		}
+		if len(n.ConflictingViews) > 0 {
+			consistent = false
+			report.add(HealthCheckNodeStateConsistency, n.Address, HealthWarning,
+				"hosts disagree on the state of node %s: %v", n.Name, n.ConflictingViews)
+		}
+	}
+
+	for cluster, count := range primaryCount {
+		if upPrimaryCount[cluster]*2 <= count {
+			report.add(HealthCheckNodeState, "", HealthCritical, "%s lost the quorum, %d of %d primary nodes are UP",
+				clusterDisplayName(cluster), upPrimaryCount[cluster], count)
+		}
+	}
+
+	for _, cluster := range states.SplitBrainClusters {
+		consistent = false
+		report.add(HealthCheckNodeStateConsistency, "", HealthCritical,
+			"split brain in %s, its hosts see different sets of UP nodes", clusterDisplayName(cluster))
+	}
+	if consistent {
+		report.add(HealthCheckNodeStateConsistency, "", HealthOK, "all hosts agree on the node states")
+	}
+}
+
+func clusterDisplayName(sandbox string) string {
+	if sandbox == util.MainClusterSandbox {
+		return "the main cluster"
+	}
+	return fmt.Sprintf("sandbox %s", sandbox)
+}
+
+func (vcc VClusterCommands) checkDiskSpace(options *VHealthCheckOptions, nodes []NodeStateDetails,
+	nmaHosts []string, report *HealthCheckReport) {
+	hostPaths := make(map[string][]string)
+	for i := range nodes {
+		n := &nodes[i]
+		if !slices.Contains(nmaHosts, n.Address) {
+			continue
+		}
+		paths := []string{n.CatalogPath}
+		if n.DepotPath != "" {
+			paths = append(paths, n.DepotPath)
+		}
+		hostPaths[n.Address] = append(paths, n.DataPaths...)
+	}
+	if len(hostPaths) == 0 {
+		return
+	}
+
+	hostDiskUsage := make(map[string][]PathDiskUsage)
+	nmaGetDiskUsageOp, err := makeNMAGetDiskUsageOp(hostPaths, hostDiskUsage)
+	if err == nil {
+		clusterOpEngine := options.makeClusterOpEngine([]clusterOp{&nmaGetDiskUsageOp})
+		err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	}
+	if err != nil {
+		vcc.Log.Info("disk usage check failed", "error", err)
+	}
+	addDiskSpaceResults(options, hostPaths, hostDiskUsage, report)
+}
+
+func addDiskSpaceResults(options *VHealthCheckOptions, hostPaths map[string][]string,
+	hostDiskUsage map[string][]PathDiskUsage, report *HealthCheckReport) {
+	hosts := maps.Keys(hostPaths)
+	slices.Sort(hosts)
+	for _, host := range hosts {
+		usages, ok := hostDiskUsage[host]
+		if !ok {
+			report.add(HealthCheckDiskSpace, host, HealthWarning, "failed to get the disk usage")
+			continue
+		}
+		for i := range usages {
+			usage := &usages[i]
+			used := usage.UsedPercent()
+			switch {
+			case used >= options.DiskCriticalPercent:
+				report.add(HealthCheckDiskSpace, host, HealthCritical, "%s is %.1f%% full", usage.Path, used)
+			case used >= options.DiskWarningPercent:
+				report.add(HealthCheckDiskSpace, host, HealthWarning, "%s is %.1f%% full", usage.Path, used)
+			default:
+				report.add(HealthCheckDiskSpace, host, HealthOK, "%s is %.1f%% full", usage.Path, used)
+			}
+		}
+	}
+}
+
+func (vcc VClusterCommands) checkClockSkew(options *VHealthCheckOptions, nmaHosts []string,
+	report *HealthCheckReport) {
+	hostClockOffsets := make(map[string]time.Duration)
+	nmaGetHostTimeOp := makeNMAGetHostTimeOp(nmaHosts, hostClockOffsets)
+	clusterOpEngine := options.makeClusterOpEngine([]clusterOp{&nmaGetHostTimeOp})
+	err := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		vcc.Log.Info("clock check failed", "error", err)
+	}
+	addClockSkewResult(options, nmaHosts, hostClockOffsets, report)
+}
+
+func addClockSkewResult(options *VHealthCheckOptions, nmaHosts []string, hostClockOffsets map[string]time.Duration,
+	report *HealthCheckReport) {
+	if len(nmaHosts) < 2 {
+		report.add(HealthCheckClockSkew, "", HealthOK, "skipped, fewer than two hosts to compare")
+		return
+	}
+	for _, host := range nmaHosts {
+		if _, ok := hostClockOffsets[host]; !ok {
+			report.add(HealthCheckClockSkew, host, HealthWarning, "failed to get the clock")
+		}
+	}
+	// a failed probe must not pass for a healthy clock
+	if len(hostClockOffsets) < 2 {
+		report.add(HealthCheckClockSkew, "", HealthWarning,
+			"the clock skew is unknown, fewer than two hosts returned their clock")
+		return
+	}
+
+	var earliest, latest string
+	for host, offset := range hostClockOffsets {
+		if earliest == "" || offset < hostClockOffsets[earliest] {
+			earliest = host
+		}
+		if latest == "" || offset > hostClockOffsets[latest] {
+			latest = host
+		}
+	}
+	skew := (hostClockOffsets[latest] - hostClockOffsets[earliest]).Seconds()
+
+	severity := HealthOK
+	if skew >= options.ClockSkewCriticalSeconds {
+		severity = HealthCritical
+	} else if skew >= options.ClockSkewWarningSeconds {
+		severity = HealthWarning
+	}
+	report.add(HealthCheckClockSkew, "", severity, "the clock skew is %.3fs between hosts %s and %s",
+		skew, earliest, latest)
+}
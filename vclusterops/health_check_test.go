@@ -0,0 +1,134 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestHealthCheckOptions(t *testing.T) {
+	options := VHealthCheckOptionsFactory()
+	options.DBName = "test_db"
+	options.RawHosts = []string{"192.168.1.101"}
+	assert.NoError(t, options.validateParseOptions(vlog.Printer{}))
+
+	// negative: the warning threshold is above the critical one
+	options.DiskWarningPercent = 96
+	assert.ErrorContains(t, options.validateParseOptions(vlog.Printer{}), "disk usage thresholds")
+	options.DiskWarningPercent = defaultDiskWarningPercent
+	options.ClockSkewCriticalSeconds = 0.5
+	assert.ErrorContains(t, options.validateParseOptions(vlog.Printer{}), "clock skew thresholds")
+}
+
+func TestHealthCheckReport(t *testing.T) {
+	var report HealthCheckReport
+	assert.True(t, report.IsReady())
+	report.add(HealthCheckNMA, "192.168.1.101", HealthWarning, "warning")
+	assert.True(t, report.IsReady())
+	report.add(HealthCheckNMA, "192.168.1.102", HealthCritical, "critical")
+	report.add(HealthCheckNMA, "192.168.1.103", HealthOK, "ok")
+	assert.Equal(t, HealthCritical, report.Severity)
+	assert.False(t, report.IsReady())
+
+	bytes, err := json.Marshal(report.Results[1])
+	assert.NoError(t, err)
+	assert.Contains(t, string(bytes), `"severity":"CRITICAL"`)
+}
+
+func TestAddNodeStateResults(t *testing.T) {
+	states := NodeStatesReport{Nodes: []NodeStateDetails{
+		{Name: "node1", Address: "192.168.1.101", State: util.NodeUpState, IsPrimary: true},
+		{Name: "node2", Address: "192.168.1.102", State: util.NodeDownState, IsPrimary: true},
+		{Name: "node3", Address: "192.168.1.103", State: util.NodeUpState, IsPrimary: true},
+	}}
+	var report HealthCheckReport
+	addNodeStateResults(states, &report)
+	assert.Equal(t, HealthWarning, report.Severity)
+
+	// two of three primary nodes down lose the quorum
+	states.Nodes[2].State = util.NodeDownState
+	report = HealthCheckReport{}
+	addNodeStateResults(states, &report)
+	assert.Equal(t, HealthCritical, report.Severity)
+
+	// a split brain means the hosts disagree on the node states
+	states.Nodes[2].State = util.NodeUpState
+	states.SplitBrain = true
+	states.SplitBrainClusters = []string{util.MainClusterSandbox}
+	report = HealthCheckReport{}
+	addNodeStateResults(states, &report)
+	assert.Equal(t, HealthCritical, report.Severity)
+	assert.Equal(t, HealthCheckNodeStateConsistency, report.Results[len(report.Results)-1].Check)
+}
+
+func TestAddDiskSpaceResults(t *testing.T) {
+	options := VHealthCheckOptionsFactory()
+	const gb = 1 << 30
+	hostPaths := map[string][]string{
+		"192.168.1.101": {"/data/catalog"},
+		"192.168.1.102": {"/data/catalog"},
+	}
+	hostDiskUsage := map[string][]PathDiskUsage{
+		"192.168.1.101": {{Path: "/data/catalog", TotalBytes: 100 * gb, AvailableBytes: 50 * gb}},
+	}
+	var report HealthCheckReport
+	addDiskSpaceResults(&options, hostPaths, hostDiskUsage, &report)
+	// no response from the second host
+	assert.Equal(t, HealthWarning, report.Severity)
+	assert.Equal(t, HealthOK, report.Results[0].Severity)
+
+	hostDiskUsage["192.168.1.102"] = []PathDiskUsage{{Path: "/data/catalog", TotalBytes: 100 * gb, AvailableBytes: 2 * gb}}
+	report = HealthCheckReport{}
+	addDiskSpaceResults(&options, hostPaths, hostDiskUsage, &report)
+	assert.Equal(t, HealthCritical, report.Severity)
+	assert.Contains(t, report.Results[1].Message, "98.0% full")
+}
+
+func TestAddClockSkewResult(t *testing.T) {
+	options := VHealthCheckOptionsFactory()
+	hosts := []string{"192.168.1.101", "192.168.1.102"}
+	var report HealthCheckReport
+	addClockSkewResult(&options, hosts[:1], map[string]time.Duration{"192.168.1.101": 0}, &report)
+	assert.Equal(t, HealthOK, report.Severity)
+
+	// negative: a host that failed the probe leaves the skew unknown
+	report = HealthCheckReport{}
+	addClockSkewResult(&options, hosts, map[string]time.Duration{"192.168.1.101": 0}, &report)
+	assert.Equal(t, HealthWarning, report.Severity)
+	assert.Equal(t, "192.168.1.102", report.Results[0].Host)
+	assert.Contains(t, report.Results[1].Message, "the clock skew is unknown")
+
+	report = HealthCheckReport{}
+	addClockSkewResult(&options, hosts, map[string]time.Duration{
+		"192.168.1.101": -time.Second,
+		"192.168.1.102": time.Second,
+	}, &report)
+	assert.Equal(t, HealthWarning, report.Severity)
+	assert.Contains(t, report.Results[0].Message, "2.000s between hosts 192.168.1.101 and 192.168.1.102")
+
+	report = HealthCheckReport{}
+	addClockSkewResult(&options, hosts, map[string]time.Duration{
+		"192.168.1.101": 0,
+		"192.168.1.102": 10 * time.Second,
+	}, &report)
+	assert.Equal(t, HealthCritical, report.Severity)
+}
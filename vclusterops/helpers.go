@@ -58,7 +58,7 @@ func produceTransferConfigOps(instructions *[]clusterOp, sourceConfigHost,
 // Get catalog path after we have db information from /catalog/database endpoint
 func updateCatalogPathMapFromCatalogEditor(hosts []string, nmaVDB *nmaVDatabase, catalogPathMap map[string]string) error {
 	if len(hosts) == 0 {
-		return fmt.Errorf("[%s] fail to get host with highest catalog version", nmaVDB.Name)
+		return categorizeError(ErrCatalogMismatch, fmt.Errorf("[%s] fail to get host with highest catalog version", nmaVDB.Name))
 	}
 	for _, host := range hosts {
 		vnode, ok := nmaVDB.HostNodeMap[host]
@@ -143,6 +143,10 @@ type nodesStateInfo struct {
 	NodeList []*nodeStateInfo `json:"node_list"`
 }
 
+func (nodesStateInfo) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 // getInitiatorHost returns as initiator the first primary up node that is not
 // in the list of hosts to skip.
 func getInitiatorHost(primaryUpNodes, hostsToSkip []string) (string, error) {
@@ -174,9 +178,11 @@ func getInitiatorHostInCluster(name, sandbox, scname string, vdb *VCoordinationD
 	}
 	if upHost == "" {
 		if sandbox == "" {
-			return nil, fmt.Errorf(`[%s] cannot find any up hosts for subcluster %s in main subcluster`, name, scname)
+			return nil, categorizeError(ErrNodeDown,
+				fmt.Errorf(`[%s] cannot find any up hosts for subcluster %s in main subcluster`, name, scname))
 		}
-		return nil, fmt.Errorf("[%s] cannot find any up hosts for subcluster %s in the sandbox %s", name, scname, sandbox)
+		return nil, categorizeError(ErrNodeDown,
+			fmt.Errorf("[%s] cannot find any up hosts for subcluster %s in the sandbox %s", name, scname, sandbox))
 	}
 	// use first up host to execute https post request
 	initiatorHost := []string{upHost}
@@ -198,9 +204,9 @@ func getInitiatorHostForReplication(name, sandbox string, hosts []string, vdb *V
 	sourceHosts = util.SliceCommon(hosts, sourceHosts)
 	if len(sourceHosts) == 0 {
 		if sandbox == "" {
-			return nil, fmt.Errorf("[%s] cannot find any up hosts from source database", name)
+			return nil, categorizeError(ErrNodeDown, fmt.Errorf("[%s] cannot find any up hosts from source database", name))
 		}
-		return nil, fmt.Errorf("[%s] cannot find any up hosts in the sandbox %s", name, sandbox)
+		return nil, categorizeError(ErrNodeDown, fmt.Errorf("[%s] cannot find any up hosts in the sandbox %s", name, sandbox))
 	}
 
 	initiatorHost := []string{getInitiator(sourceHosts)}
@@ -258,9 +264,8 @@ func (vcc VClusterCommands) getVDBFromRunningDBImpl(vdb *VCoordinationDatabase,
 		instructions = append(instructions, &httpsUpdateNodeState)
 	}
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	err = clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if err != nil {
 		return fmt.Errorf("fail to retrieve database configurations, %w", err)
 	}
@@ -284,9 +289,8 @@ func (vcc VClusterCommands) getClusterInfoFromRunningDB(vdb *VCoordinationDataba
 	var instructions []clusterOp
 	instructions = append(instructions, &httpsGetClusterInfoOp)
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	err = clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if err != nil {
 		return fmt.Errorf("fail to retrieve cluster configurations, %w", err)
 	}
@@ -443,9 +447,8 @@ func (vcc *VClusterCommands) doReIP(options *DatabaseOptions, scName string,
 		}
 		instructions = append(instructions, &httpsReloadSpreadOp)
 	}
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	err = clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if err != nil {
 		return fmt.Errorf("failed to re-ip nodes of subcluster %q: %w", scName, err)
 	}
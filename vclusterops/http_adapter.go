@@ -17,6 +17,7 @@ package vclusterops
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -37,6 +38,8 @@ type httpAdapter struct {
 	opBase
 	host            string
 	respBodyHandler responseBodyHandler
+	// shares the connections to the host with the other ops of the run
+	transports *transportCache
 }
 
 func makeHTTPAdapter(logger vlog.Printer) httpAdapter {
@@ -86,7 +89,42 @@ type certificatePaths struct {
 	caFile   string
 }
 
-func (adapter *httpAdapter) sendRequest(request *hostHTTPRequest, resultChannel chan<- hostHTTPResult) {
+func (adapter *httpAdapter) sendRequest(ctx context.Context, request *hostHTTPRequest, resultChannel chan<- hostHTTPResult) {
+	ctx, span := startRequestSpan(ctx, adapter.host, request)
+	result := adapter.send(ctx, request)
+	endRequestSpan(span, &result)
+	resultChannel <- result
+}
+
+// send sends the request to the host of the adapter, and returns its result.
+// A request rejected because of its bearer token is sent again once with a
+// refreshed token.
+func (adapter *httpAdapter) send(ctx context.Context, request *hostHTTPRequest) hostHTTPResult {
+	if request.BearerToken == nil || request.IsNMACommand {
+		return adapter.sendWithToken(ctx, request, "")
+	}
+	token, err := request.BearerToken.getToken()
+	if err != nil {
+		return adapter.makeExceptionResult(err)
+	}
+	result := adapter.sendWithToken(ctx, request, token)
+	if !result.isUnauthorizedRequest() {
+		return result
+	}
+	newToken, err := request.BearerToken.refreshToken(token)
+	if err != nil {
+		return adapter.makeExceptionResult(err)
+	}
+	if newToken == token {
+		return result
+	}
+	adapter.logger.Info("Bearer token refreshed, sending the request again", "host", adapter.host)
+	return adapter.sendWithToken(ctx, request, newToken)
+}
+
+// sendWithToken sends the request, authenticated with the bearer token if
+// it is not empty
+func (adapter *httpAdapter) sendWithToken(ctx context.Context, request *hostHTTPRequest, token string) hostHTTPResult {
 	// build query params
 	queryParams := buildQueryParamString(request.QueryParams)
 
@@ -98,9 +136,9 @@ func (adapter *httpAdapter) sendRequest(request *hostHTTPRequest, resultChannel
 		port = httpsPort
 	}
 
-	requestURL := fmt.Sprintf("https://%s:%d/%s%s",
-		adapter.host,
-		port,
+	localNMA := request.IsNMACommand && adapter.transports.usesLocalNMA(adapter.host)
+	requestURL := fmt.Sprintf("%s/%s%s",
+		adapter.transports.baseURL(adapter.host, port, localNMA),
 		request.Endpoint,
 		queryParams)
 	adapter.logger.Info("Request URL", "URL", requestURL)
@@ -108,15 +146,13 @@ func (adapter *httpAdapter) sendRequest(request *hostHTTPRequest, resultChannel
 	// whether use password (for HTTPS endpoints only)
 	usePassword, err := whetherUsePassword(request)
 	if err != nil {
-		resultChannel <- adapter.makeExceptionResult(err)
-		return
+		return adapter.makeExceptionResult(err)
 	}
 
 	// HTTP client
-	client, err := adapter.setupHTTPClient(request, usePassword, resultChannel)
+	client, err := adapter.setupHTTPClient(request, usePassword, nil)
 	if err != nil {
-		resultChannel <- adapter.makeExceptionResult(err)
-		return
+		return adapter.makeExceptionResult(err)
 	}
 
 	// set up request body
@@ -127,39 +163,54 @@ func (adapter *httpAdapter) sendRequest(request *hostHTTPRequest, resultChannel
 		requestBody = bytes.NewBuffer([]byte(request.RequestData))
 	}
 
-	// build HTTP request
-	req, err := http.NewRequest(request.Method, requestURL, requestBody)
+	// build HTTP request, which is aborted when the context is canceled
+	req, err := http.NewRequestWithContext(ctx, request.Method, requestURL, requestBody)
 	if err != nil {
 		err = fmt.Errorf("fail to build request %v on host %s, details %w",
 			request.Endpoint, adapter.host, err)
-		resultChannel <- adapter.makeExceptionResult(err)
-		return
+		return adapter.makeExceptionResult(err)
 	}
 	// close the connection after sending the request (for clients)
 	req.Close = true
-
-	// set username and password
-	// which is only used for HTTPS endpoints
-	if usePassword {
-		req.SetBasicAuth(request.Username, *request.Password)
+	if request.IdempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, request.IdempotencyKey)
+	}
+	// propagate the trace context so that the logs of the host can be correlated
+	getTracer(ctx).Inject(ctx, req.Header)
+
+	// set the bearer token, or the username and password
+	// which are only used for HTTPS endpoints
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if usePassword {
+		password, err := request.getPassword()
+		if err != nil {
+			return adapter.makeExceptionResult(err)
+		}
+		req.SetBasicAuth(request.Username, password)
 	}
 
 	// send HTTP request
+	startTime := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
 		err = fmt.Errorf("fail to send request %v on host %s, details %w",
 			request.Endpoint, adapter.host, err)
+		var result hostHTTPResult
 		if errors.Is(err, io.EOF) {
-			resultChannel <- adapter.makeEOFResult(err)
+			result = adapter.makeEOFResult(err)
 		} else {
-			resultChannel <- adapter.makeExceptionResult(err)
+			result = adapter.makeExceptionResult(err)
 		}
-		return
+		result.duration = time.Since(startTime)
+		return result
 	}
 	defer resp.Body.Close()
 
 	// generate and return the result
-	resultChannel <- adapter.generateResult(resp)
+	result := adapter.generateResult(resp)
+	result.duration = time.Since(startTime)
+	return result
 }
 
 func (adapter *httpAdapter) generateResult(resp *http.Response) hostHTTPResult {
@@ -269,7 +320,7 @@ func (adapter *httpAdapter) extractErrorFromResponse(header http.Header, respBod
 	if header.Get("Content-Type") == rfc7807.ContentType {
 		return rfc7807.GenerateErrorFromResponse(respBody)
 	}
-	return fmt.Errorf("status code %d returned from host %s: %s", statusCode, adapter.host, respBody)
+	return fmt.Errorf("status code %d returned from host %s: %s", statusCode, adapter.host, redactSecrets(respBody))
 }
 
 func whetherUsePassword(request *hostHTTPRequest) (bool, error) {
@@ -278,7 +329,7 @@ func whetherUsePassword(request *hostHTTPRequest) (bool, error) {
 	}
 
 	// in case that password is provided
-	if request.Password != nil {
+	if request.Password != nil || request.CredentialProvider != nil || request.BearerToken != nil {
 		return true, nil
 	}
 
@@ -353,17 +404,45 @@ func (adapter *httpAdapter) setupHTTPClient(
 		requestTimeout = time.Duration(0) // a Timeout of zero means no timeout.
 	}
 
+	key := transportKey{
+		host:              adapter.host,
+		usePassword:       usePassword,
+		useCertsInOptions: request.UseCertsInOptions,
+		tlsVerification:   request.TLSVerification.cacheKey(),
+		localNMA:          request.IsNMACommand && adapter.transports.usesLocalNMA(adapter.host),
+	}
+	transport, err := adapter.transports.getTransport(key, func() (*tls.Config, error) {
+		return adapter.buildTLSConfig(request, usePassword)
+	})
+	if err != nil {
+		return client, err
+	}
+	client = &http.Client{
+		Timeout:   time.Second * requestTimeout,
+		Transport: transport,
+	}
+	return client, nil
+}
+
+func (adapter *httpAdapter) buildTLSConfig(request *hostHTTPRequest, usePassword bool) (*tls.Config, error) {
+	var tlsConfig *tls.Config
 	if usePassword {
-		// TODO: we have to use `InsecureSkipVerify: true` here,
-		//       as password is used
-		//nolint:gosec
-		client = &http.Client{
-			Timeout: time.Second * requestTimeout,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: true,
-				},
+		// the certificates are not verified unless a TLS verification
+		// policy is set, see apply() below
+		tlsConfig = &tls.Config{}
+	} else if request.UseCertsInOptions && request.Certs.provider != nil {
+		provider := request.Certs.provider
+		caCertPool, err := provider.GetRootCAs()
+		if err != nil {
+			return nil, fmt.Errorf("fail to load HTTPS CA certificates, details %w", err)
+		}
+		tlsConfig = &tls.Config{
+			// the provider is asked in each handshake, so that a
+			// rotated certificate is used without a restart
+			GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return provider.GetClientCertificate()
 			},
+			RootCAs: caCertPool,
 		}
 	} else {
 		var cert tls.Certificate
@@ -375,25 +454,17 @@ func (adapter *httpAdapter) setupHTTPClient(
 			cert, caCertPool, err = adapter.buildCertsFromFile()
 		}
 		if err != nil {
-			return client, err
+			return nil, err
 		}
-		// for both http and nma, we have to use `InsecureSkipVerify: true` here
-		// because the certs are self signed at this time
-		// TODO: update the InsecureSkipVerify once we start to use non-self-signed certs
-
-		//nolint:gosec
-		client = &http.Client{
-			Timeout: time.Second * requestTimeout,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					Certificates:       []tls.Certificate{cert},
-					RootCAs:            caCertPool,
-					InsecureSkipVerify: true,
-				},
-			},
+		tlsConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			RootCAs:      caCertPool,
 		}
 	}
-	return client, nil
+	// for both http and nma, the certificates are self signed by default,
+	// so they are only verified when the request has a verification policy
+	request.TLSVerification.apply(tlsConfig, adapter.host)
+	return tlsConfig, nil
 }
 
 func buildQueryParamString(queryParams map[string]string) string {
@@ -15,6 +15,8 @@
 
 package vclusterops
 
+import "fmt"
+
 type hostHTTPRequest struct {
 	Method       string
 	Endpoint     string
@@ -25,18 +27,48 @@ type hostHTTPRequest struct {
 	// string pointer is used here as we need to check whether the password has been set
 	Password *string // optional, for HTTPS endpoints only
 	Timeout  int     // optional, set it if an Op needs longer time to complete
+	// optional, sent to the NMA endpoints that support it so that they do not
+	// execute a retried request again if the first attempt succeeded
+	IdempotencyKey string
+	// optional, supplies the password of Username when the request is sent
+	// if Password is not set, for HTTPS endpoints only
+	CredentialProvider CredentialProvider
+	// optional, authenticates the user with a bearer token instead of a
+	// password, for HTTPS endpoints only
+	BearerToken *bearerTokenSource
 
 	// optional, for calling NMA/Vertica HTTPS endpoints. If Username/Password is set, that takes precedence over this for HTTPS calls.
 	UseCertsInOptions bool
 	Certs             httpsCerts
+	// optional, how the certificate of the host is verified. It is not
+	// verified if not set.
+	TLSVerification *TLSVerificationPolicy
 }
 
 type httpsCerts struct {
 	key    string
 	cert   string
 	caCert string
+	// takes precedence over the PEM-encoded strings when set
+	provider CertProvider
+}
+
+// getPassword returns the password of the request, from its credential
+// provider if the password is not set
+func (req *hostHTTPRequest) getPassword() (string, error) {
+	if req.Password != nil {
+		return *req.Password, nil
+	}
+	password, err := req.CredentialProvider.GetPassword(req.Username)
+	if err != nil {
+		return "", fmt.Errorf("fail to get the password of user %s: %w", req.Username, err)
+	}
+	return password, nil
 }
 
+// idempotencyKeyHeader is the header that carries the idempotency key of a request
+const idempotencyKeyHeader = "Idempotency-Key"
+
 func (req *hostHTTPRequest) buildNMAEndpoint(url string) {
 	req.IsNMACommand = true
 	req.Endpoint = NMACurVersion + url
@@ -53,4 +85,7 @@ type clusterHTTPRequest struct {
 	ResultCollection  map[string]hostHTTPResult
 	SemVar            semVer
 	Name              string
+	// number of times a request has been sent to each host,
+	// ops that poll a host will send the same request several times
+	SendCount map[string]int
 }
@@ -16,6 +16,8 @@
 package vclusterops
 
 import (
+	"context"
+
 	"github.com/theckman/yacspin"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
@@ -23,10 +25,27 @@ import (
 type requestDispatcher struct {
 	opBase
 	pool adapterPool
+	// the requests are aborted when it is canceled
+	ctx context.Context
+	// how the requests that fail with a transient error are retried
+	retryPolicy *RetryPolicy
+	// timeout in seconds of the requests that do not set their own, 0 for the default
+	requestTimeout int
+	// maximum number of hosts that are sent requests at a time, 0 for no limit
+	maxConcurrentHosts int
+	// how the certificates of the hosts are verified, nil to skip the verification
+	tlsVerification *TLSVerificationPolicy
+	// supplies the password of the requests, see hostHTTPRequest.CredentialProvider
+	credentialProvider CredentialProvider
+	// authenticates the requests, see hostHTTPRequest.BearerToken
+	bearerToken *bearerTokenSource
+	// the transports shared by the ops of a run, nil to not share them
+	transports *transportCache
 }
 
-func makeHTTPRequestDispatcher(logger vlog.Printer) requestDispatcher {
+func makeHTTPRequestDispatcher(ctx context.Context, logger vlog.Printer) requestDispatcher {
 	newHTTPRequestDispatcher := requestDispatcher{}
+	newHTTPRequestDispatcher.ctx = ctx
 	newHTTPRequestDispatcher.name = "HTTPRequestDispatcher"
 	newHTTPRequestDispatcher.logger = logger.WithName(newHTTPRequestDispatcher.name)
 
@@ -41,6 +60,7 @@ func (dispatcher *requestDispatcher) setup(hosts []string) {
 	for _, host := range hosts {
 		adapter := makeHTTPAdapter(dispatcher.logger)
 		adapter.host = host
+		adapter.transports = dispatcher.transports
 		dispatcher.pool.connections[host] = &adapter
 	}
 }
@@ -53,11 +73,34 @@ func (dispatcher *requestDispatcher) setupForDownload(hosts []string,
 	for _, host := range hosts {
 		adapter := makeHTTPDownloadAdapter(dispatcher.logger, hostToFilePathsMap[host])
 		adapter.host = host
+		adapter.transports = dispatcher.transports
 		dispatcher.pool.connections[host] = &adapter
 	}
 }
 
 func (dispatcher *requestDispatcher) sendRequest(httpRequest *clusterHTTPRequest, spinner *yacspin.Spinner) error {
 	dispatcher.logger.Info("HTTP request dispatcher's sendRequest is called")
-	return dispatcher.pool.sendRequest(httpRequest, spinner)
+	dispatcher.setDefaults(httpRequest)
+	return dispatcher.pool.sendRequest(dispatcher.ctx, httpRequest, spinner,
+		dispatcher.retryPolicy, dispatcher.maxConcurrentHosts)
+}
+
+// setDefaults applies the request timeout, the TLS verification policy, and
+// the credential provider of the dispatcher to the requests that do not set
+// their own
+func (dispatcher *requestDispatcher) setDefaults(httpRequest *clusterHTTPRequest) {
+	for host, request := range httpRequest.RequestCollection {
+		if request.Timeout == 0 && dispatcher.requestTimeout > 0 {
+			request.Timeout = dispatcher.requestTimeout
+		}
+		if request.TLSVerification == nil {
+			request.TLSVerification = dispatcher.tlsVerification
+		}
+		// the ops set the user name of the requests that authenticate with a password
+		if request.Username != "" && request.Password == nil && request.CredentialProvider == nil && request.BearerToken == nil {
+			request.CredentialProvider = dispatcher.credentialProvider
+			request.BearerToken = dispatcher.bearerToken
+		}
+		httpRequest.RequestCollection[host] = request
+	}
 }
@@ -0,0 +1,280 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// TransportPolicy configures the HTTP connections to the hosts, which are
+// shared by the ops of a command, so that a command that runs many ops
+// does not connect and handshake with every host for every op
+type TransportPolicy struct {
+	// do not reuse a connection for more than one request
+	DisableKeepAlives bool
+	// maximum number of idle connections kept to a host, 0 for the default of net/http
+	MaxIdleConnsPerHost int
+	// how long an idle connection is kept, 0 for no limit
+	IdleConnTimeout time.Duration
+	// the hosts whose node management agent is reached locally
+	LocalNMA LocalNMAPolicy
+}
+
+// LocalNMAPolicy makes the commands that run on a node, e.g., inside its
+// container, reach the node management agent of the node through a unix
+// socket or the loopback interface instead of the external interface
+type LocalNMAPolicy struct {
+	// the hosts whose agent is local to the command
+	Hosts []string
+	// path of the unix socket that the agent listens on. The agent is reached
+	// through the loopback interface if it is empty.
+	SocketPath string
+	// the agent serves plain HTTP on the unix socket, which saves the TLS handshakes
+	PlainHTTP bool
+}
+
+// loopbackAddress is the address of the local agent when there is no unix socket
+const loopbackAddress = "127.0.0.1"
+
+func (policy *TransportPolicy) validate() error {
+	if policy.MaxIdleConnsPerHost < 0 {
+		return fmt.Errorf("max idle connections per host must not be negative, got %d", policy.MaxIdleConnsPerHost)
+	}
+	if policy.IdleConnTimeout < 0 {
+		return fmt.Errorf("idle connection timeout must not be negative, got %s", policy.IdleConnTimeout)
+	}
+	if policy.LocalNMA.PlainHTTP && policy.LocalNMA.SocketPath == "" {
+		return errors.New("plain HTTP to the local node management agent requires a unix socket")
+	}
+	return nil
+}
+
+// isLocalNMA returns whether the agent of host is reached locally
+func (policy *TransportPolicy) isLocalNMA(host string) bool {
+	return policy != nil && util.StringInArray(host, policy.LocalNMA.Hosts)
+}
+
+// TransportWrapper wraps the transport that sends the requests to host, e.g.,
+// to sign the requests, to go through a proxy, or to inject faults in tests.
+// base is an *http.Transport that has the TLS config of the command, which can
+// be cloned to change its settings. A wrapper is called once per host and way
+// to authenticate in a run, and the result is shared by the ops of the run.
+type TransportWrapper func(host string, base http.RoundTripper) http.RoundTripper
+
+type transportWrapperKey struct{}
+
+// WithTransportWrapper returns a copy of vcc whose commands send their
+// requests through the transports returned by wrap, unless the options of
+// a command have their own wrapper
+func (vcc VClusterCommands) WithTransportWrapper(wrap TransportWrapper) VClusterCommands {
+	vcc.transportWrapper = wrap
+	return vcc
+}
+
+// getTransportWrapper returns the transport wrapper registered in ctx, or nil
+func getTransportWrapper(ctx context.Context) TransportWrapper {
+	wrap, _ := ctx.Value(transportWrapperKey{}).(TransportWrapper)
+	return wrap
+}
+
+// transportKey identifies the transports that can be shared by requests:
+// the TLS config of a transport depends on how the requests authenticate,
+// and the certificates of the options are the same for a whole run
+type transportKey struct {
+	host              string
+	usePassword       bool
+	useCertsInOptions bool
+	// the TLS verification policy, by value so that the commands sharing the
+	// connections of a plan reuse them when their policies are the same
+	tlsVerification string
+	// the request is sent to the local agent, see LocalNMAPolicy
+	localNMA bool
+}
+
+// transportCache keeps the transports of an engine run. A nil cache
+// creates a new transport for every request.
+type transportCache struct {
+	mu     sync.Mutex
+	policy *TransportPolicy
+	// wraps the transports, nil to use them as they are
+	wrap TransportWrapper
+	// the hostnames that the requests are sent to instead of the addresses
+	// of their hosts, which are then resolved when a connection is made
+	hostnames map[string]string
+	// the addresses of the nodes on the networks of the services, nil if
+	// the hosts are sent the requests at their own addresses
+	addressBook *AddressBook
+	// connects through the proxy of the command, if any
+	proxy      *proxyDialer
+	transports map[transportKey]http.RoundTripper
+	// the transports before they are wrapped, to close their connections
+	baseTransports []*http.Transport
+	// resumes the TLS sessions of the closed connections
+	sessionCache tls.ClientSessionCache
+}
+
+func makeTransportCache(policy *TransportPolicy, proxyPolicy *ProxyPolicy, wrap TransportWrapper) *transportCache {
+	return &transportCache{
+		policy:       policy,
+		wrap:         wrap,
+		proxy:        &proxyDialer{policy: proxyPolicy},
+		transports:   make(map[transportKey]http.RoundTripper),
+		sessionCache: tls.NewLRUClientSessionCache(0),
+	}
+}
+
+// getTransport returns the transport of key, which is created with the TLS
+// config from buildTLSConfig if there is no such transport yet
+func (cache *transportCache) getTransport(key transportKey,
+	buildTLSConfig func() (*tls.Config, error)) (http.RoundTripper, error) {
+	if cache == nil {
+		tlsConfig, err := buildTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		return &http.Transport{TLSClientConfig: tlsConfig}, nil
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if transport, ok := cache.transports[key]; ok {
+		return transport, nil
+	}
+	tlsConfig, err := buildTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.ClientSessionCache = cache.sessionCache
+	transport := &http.Transport{TLSClientConfig: tlsConfig}
+	if cache.policy != nil {
+		transport.DisableKeepAlives = cache.policy.DisableKeepAlives
+		transport.MaxIdleConnsPerHost = cache.policy.MaxIdleConnsPerHost
+		transport.IdleConnTimeout = cache.policy.IdleConnTimeout
+	}
+	if key.localNMA {
+		// the local agent is not reached through the proxy
+		if socketPath := cache.policy.LocalNMA.SocketPath; socketPath != "" {
+			var dialer net.Dialer
+			transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socketPath)
+			}
+		}
+	} else if err := cache.proxy.configure(transport); err != nil {
+		return nil, err
+	}
+	cache.baseTransports = append(cache.baseTransports, transport)
+	var roundTripper http.RoundTripper = transport
+	if cache.wrap != nil {
+		roundTripper = cache.wrap(key.host, transport)
+	}
+	cache.transports[key] = roundTripper
+	return roundTripper, nil
+}
+
+// usesLocalNMA returns whether the requests to the agent of host are sent locally
+func (cache *transportCache) usesLocalNMA(host string) bool {
+	return cache != nil && cache.policy.isLocalNMA(host)
+}
+
+// baseURL returns the scheme and the address of the URLs for the port of host
+func (cache *transportCache) baseURL(host string, port int, localNMA bool) string {
+	scheme := "https"
+	var address string
+	if localNMA {
+		localPolicy := &cache.policy.LocalNMA
+		if localPolicy.SocketPath == "" {
+			address = net.JoinHostPort(loopbackAddress, strconv.Itoa(port))
+		} else {
+			// the connections go to the socket whatever the address is
+			address = net.JoinHostPort(host, strconv.Itoa(port))
+			if localPolicy.PlainHTTP {
+				scheme = "http"
+			}
+		}
+	} else {
+		address = cache.getAddress(host, port)
+	}
+	// the zone ID of a scoped IPv6 address is escaped in a URL, see RFC 6874
+	return scheme + "://" + strings.Replace(address, "%", "%25", 1)
+}
+
+// getAddress returns the address that the connections to the port of host are
+// made to. It is the address of the node on the network of the service in a
+// multi-homed cluster, or the hostname of the host, which the proxy policy can
+// rewrite.
+func (cache *transportCache) getAddress(host string, port int) string {
+	var proxyPolicy *ProxyPolicy
+	var addressBook *AddressBook
+	if cache != nil {
+		proxyPolicy = cache.proxy.policy
+		addressBook = cache.addressBook
+	}
+	routed, isRouted := addressBook.route(host, port == nmaPort)
+	if isRouted {
+		host = routed
+	}
+	// the address can be rewritten by the proxy policy
+	address := proxyPolicy.rewriteAddress(host, port)
+	if hostname, ok := cache.getHostname(host); ok && !isRouted && address == net.JoinHostPort(host, strconv.Itoa(port)) {
+		address = net.JoinHostPort(hostname, strconv.Itoa(port))
+	}
+	return address
+}
+
+// dialContext opens a TCP connection to the port of host, for the connections
+// that are not HTTP requests, e.g., the client connections to the database. It
+// goes to the address from getAddress, through the proxy of the command.
+func (cache *transportCache) dialContext(ctx context.Context, host string, port int) (net.Conn, error) {
+	var proxy *proxyDialer
+	if cache != nil {
+		proxy = cache.proxy
+	}
+	return proxy.dialContext(ctx, "tcp", cache.getAddress(host, port))
+}
+
+// getHostname returns the hostname that the requests to host are sent to, if any
+func (cache *transportCache) getHostname(host string) (string, bool) {
+	if cache == nil {
+		return "", false
+	}
+	hostname, ok := cache.hostnames[host]
+	return hostname, ok
+}
+
+// close closes the connections kept by the transports, and the connection
+// to the jump host, which is done at the end of the run
+func (cache *transportCache) close() {
+	if cache == nil {
+		return
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for _, transport := range cache.baseTransports {
+		transport.CloseIdleConnections()
+	}
+	cache.proxy.close()
+}
@@ -0,0 +1,235 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestTransportPolicyValidation(t *testing.T) {
+	policy := TransportPolicy{MaxIdleConnsPerHost: 4, IdleConnTimeout: time.Minute}
+	assert.NoError(t, policy.validate())
+	policy.MaxIdleConnsPerHost = -1
+	assert.ErrorContains(t, policy.validate(), "max idle connections per host must not be negative")
+	policy = TransportPolicy{IdleConnTimeout: -time.Second}
+	assert.ErrorContains(t, policy.validate(), "idle connection timeout must not be negative")
+	policy = TransportPolicy{LocalNMA: LocalNMAPolicy{Hosts: []string{"192.168.1.101"}, PlainHTTP: true}}
+	assert.ErrorContains(t, policy.validate(), "requires a unix socket")
+}
+
+func TestTransportCache(t *testing.T) {
+	builds := 0
+	buildTLSConfig := func() (*tls.Config, error) {
+		builds++
+		return &tls.Config{}, nil //nolint:gosec
+	}
+
+	cache := makeTransportCache(&TransportPolicy{MaxIdleConnsPerHost: 4, IdleConnTimeout: time.Minute}, nil, nil)
+	key := transportKey{host: "192.168.1.101", usePassword: true}
+	transport, err := cache.getTransport(key, buildTLSConfig)
+	assert.NoError(t, err)
+	httpTransport := transport.(*http.Transport)
+	assert.Equal(t, 4, httpTransport.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, httpTransport.IdleConnTimeout)
+	assert.NotNil(t, httpTransport.TLSClientConfig.ClientSessionCache)
+
+	// the same key shares the transport
+	sameTransport, err := cache.getTransport(key, buildTLSConfig)
+	assert.NoError(t, err)
+	assert.Same(t, transport, sameTransport)
+	assert.Equal(t, 1, builds)
+
+	// another host or another way to authenticate does not
+	otherTransport, err := cache.getTransport(transportKey{host: "192.168.1.102", usePassword: true}, buildTLSConfig)
+	assert.NoError(t, err)
+	assert.NotSame(t, transport, otherTransport)
+	otherTransport, err = cache.getTransport(transportKey{host: "192.168.1.101"}, buildTLSConfig)
+	assert.NoError(t, err)
+	assert.NotSame(t, transport, otherTransport)
+	assert.Equal(t, 3, builds)
+
+	// a failure is not cached
+	_, err = cache.getTransport(transportKey{host: "192.168.1.103"}, func() (*tls.Config, error) {
+		return nil, errors.New("bad certificate")
+	})
+	assert.ErrorContains(t, err, "bad certificate")
+	assert.Len(t, cache.transports, 3)
+
+	// without a cache, every request gets its own transport
+	var noCache *transportCache
+	transport, err = noCache.getTransport(key, buildTLSConfig)
+	assert.NoError(t, err)
+	sameTransport, err = noCache.getTransport(key, buildTLSConfig)
+	assert.NoError(t, err)
+	assert.NotSame(t, transport, sameTransport)
+	noCache.close()
+}
+
+func TestTransportCacheReusesConnections(t *testing.T) {
+	var connections int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	send := func(cache *transportCache) {
+		transport, err := cache.getTransport(transportKey{host: "127.0.0.1"}, func() (*tls.Config, error) {
+			return &tls.Config{InsecureSkipVerify: true}, nil //nolint:gosec
+		})
+		assert.NoError(t, err)
+		client := http.Client{Transport: transport}
+		resp, err := client.Get(server.URL)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// the ops of a run share a connection
+	cache := makeTransportCache(&TransportPolicy{}, nil, nil)
+	for i := 0; i < 3; i++ {
+		send(cache)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&connections))
+	cache.close()
+
+	// unless the keep-alives are disabled
+	cache = makeTransportCache(&TransportPolicy{DisableKeepAlives: true}, nil, nil)
+	for i := 0; i < 3; i++ {
+		send(cache)
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(&connections))
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTransportWrapper(t *testing.T) {
+	var wrappedHosts []string
+	var requestURLs []string
+	wrap := func(host string, base http.RoundTripper) http.RoundTripper {
+		wrappedHosts = append(wrappedHosts, host)
+		assert.IsType(t, &http.Transport{}, base)
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requestURLs = append(requestURLs, req.URL.String())
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(`{"node_count": 3}`)),
+			}, nil
+		})
+	}
+
+	// a wrapper registered in VClusterCommands is found in the context
+	vcc := VClusterCommands{}
+	assert.Nil(t, getTransportWrapper(vcc.Context()))
+	vcc = vcc.WithTransportWrapper(wrap)
+	assert.NotNil(t, getTransportWrapper(vcc.Context()))
+	// the wrapper is kept whatever the order of the setters
+	assert.NotNil(t, getTransportWrapper(vcc.WithContext(context.Background()).Context()))
+	assert.NotNil(t, getTransportWrapper(VClusterCommands{}.WithContext(context.Background()).WithTransportWrapper(wrap).Context()))
+
+	adapter := makeHTTPAdapter(vlog.Printer{})
+	adapter.host = "192.168.1.101"
+	adapter.transports = makeTransportCache(&TransportPolicy{}, nil, getTransportWrapper(vcc.Context()))
+	password := "secret"
+	request := hostHTTPRequest{Method: GetMethod, Username: "dbadmin", Password: &password}
+	request.buildHTTPSEndpoint("nodes")
+	for i := 0; i < 2; i++ {
+		result := adapter.send(context.Background(), &request)
+		assert.NoError(t, result.err)
+		assert.Equal(t, `{"node_count": 3}`, result.content)
+	}
+	// the transport is wrapped once, and used by both requests
+	assert.Equal(t, []string{"192.168.1.101"}, wrappedHosts)
+	assert.Equal(t, []string{"https://192.168.1.101:8443/v1/nodes", "https://192.168.1.101:8443/v1/nodes"}, requestURLs)
+}
+
+func TestLocalNMA(t *testing.T) {
+	policy := TransportPolicy{LocalNMA: LocalNMAPolicy{Hosts: []string{"192.168.1.101"}}}
+	cache := makeTransportCache(&policy, &ProxyPolicy{URL: "http://proxy:3128"}, nil)
+	assert.True(t, cache.usesLocalNMA("192.168.1.101"))
+	assert.False(t, cache.usesLocalNMA("192.168.1.102"))
+	assert.Equal(t, "https://127.0.0.1:5554", cache.baseURL("192.168.1.101", nmaPort, true))
+	assert.Equal(t, "https://192.168.1.102:5554", cache.baseURL("192.168.1.102", nmaPort, false))
+	assert.Equal(t, "https://[fe80::1%25eth0]:8443", cache.baseURL("fe80::1%eth0", httpsPort, false))
+
+	// the local agent is not reached through the proxy
+	buildTLSConfig := func() (*tls.Config, error) { return &tls.Config{}, nil } //nolint:gosec
+	transport, err := cache.getTransport(transportKey{host: "192.168.1.101", localNMA: true}, buildTLSConfig)
+	assert.NoError(t, err)
+	assert.Nil(t, transport.(*http.Transport).Proxy)
+	transport, err = cache.getTransport(transportKey{host: "192.168.1.101"}, buildTLSConfig)
+	assert.NoError(t, err)
+	assert.NotNil(t, transport.(*http.Transport).Proxy)
+}
+
+func TestLocalNMAOverUnixSocket(t *testing.T) {
+	// the path of a unix socket is limited to about 100 characters
+	dir, err := os.MkdirTemp("", "nma")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "nma.sock")
+	listener, err := net.Listen("unix", socketPath)
+	assert.NoError(t, err)
+	var paths []string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = w.Write([]byte(`{"healthy": "true"}`))
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	bundle := makeTestTLSBundle(t)
+	adapter := makeHTTPAdapter(vlog.Printer{})
+	adapter.host = "192.168.1.101"
+	adapter.transports = makeTransportCache(&TransportPolicy{LocalNMA: LocalNMAPolicy{
+		Hosts:      []string{adapter.host},
+		SocketPath: socketPath,
+		PlainHTTP:  true,
+	}}, nil, nil)
+	defer adapter.transports.close()
+	request := hostHTTPRequest{Method: GetMethod, UseCertsInOptions: true,
+		Certs: httpsCerts{key: bundle.keyPEM, cert: bundle.certPEM, caCert: bundle.caPEM}}
+	request.buildNMAEndpoint("health")
+	result := adapter.send(context.Background(), &request)
+	assert.NoError(t, result.err)
+	assert.Equal(t, `{"healthy": "true"}`, result.content)
+	assert.Equal(t, []string{"/v1/health"}, paths)
+}
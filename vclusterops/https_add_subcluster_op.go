@@ -94,12 +94,13 @@ func (op *httpsAddSubclusterOp) setupClusterHTTPRequest(hosts []string) error {
 }
 
 func (op *httpsAddSubclusterOp) prepare(execContext *opEngineExecContext) error {
-	if len(execContext.upHosts) == 0 {
-		return fmt.Errorf(`[%s] Cannot find any up hosts in OpEngineExecContext`, op.name)
+	upHosts, err := execContext.requireUpHosts(op.name)
+	if err != nil {
+		return err
 	}
 	// use first up host to execute https post request, this host will be the initiator
-	hosts := []string{execContext.upHosts[0]}
-	err := op.setupRequestBody(hosts)
+	hosts := []string{upHosts[0]}
+	err = op.setupRequestBody(hosts)
 	if err != nil {
 		return err
 	}
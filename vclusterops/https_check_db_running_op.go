@@ -162,19 +162,19 @@ func (op *httpsCheckRunningDBOp) prepare(execContext *opEngineExecContext) error
 }
 
 func (op *httpsCheckRunningDBOp) generateHintMessage(host, dbName string) (msg string) {
-	generalMsg := fmt.Sprintf("[%s] Detected HTTPS service running on host %s", op.name, host)
+	generalMsg := localize(MsgHTTPSServiceRunningOnHost, op.name, host)
 	switch op.opType {
 	case CreateDB:
-		msg = fmt.Sprintf("%s, please stop the HTTPS service before creating a new database.", generalMsg)
+		msg = localize(MsgStopHTTPSBeforeCreateDB, generalMsg)
 	case DropDB:
-		msg = fmt.Sprintf("%s, please stop the HTTPS service before dropping the existing database.", generalMsg)
+		msg = localize(MsgStopHTTPSBeforeDropDB, generalMsg)
 	case ReIP:
-		msg = fmt.Sprintf("%s, please consider using restart_node to re-ip nodes for the running database.", generalMsg)
+		msg = localize(MsgUseRestartNodeToReIP, generalMsg)
 	case StopDB, StartDB, ReviveDB, StopSC:
 		msg = fmt.Sprintf("%s.", generalMsg)
 	}
 	if dbName != "" {
-		msg += fmt.Sprintf(" Database %s is still running on host %s", dbName, host)
+		msg += localize(MsgDatabaseStillRunningOnHost, dbName, host)
 	}
 	return msg
 }
@@ -296,9 +296,11 @@ func (op *httpsCheckRunningDBOp) processResult(_ *opEngineExecContext) error {
 		}
 		if result.isFailing() && !result.isHTTPRunning() {
 			downHosts[host] = true
+			op.markExpectedFailure(host)
 			continue
 		} else if result.isException() {
 			exceptionHosts[host] = true
+			op.markExpectedFailure(host)
 			continue
 		}
 
@@ -306,7 +308,7 @@ func (op *httpsCheckRunningDBOp) processResult(_ *opEngineExecContext) error {
 
 		// a passing result means that the db isn't down
 		nodesStates := nodesStateInfo{}
-		err := op.parseAndCheckResponse(host, result.content, &nodesStates)
+		err := op.parseAndValidateResponse(host, result.content, &nodesStates)
 		// parsing shouldn't fail in normal circumstances (even if response is rfc error), checking for err
 		// here just as a guardrail
 		if err != nil {
@@ -382,7 +384,7 @@ func (op *httpsCheckRunningDBOp) checkProcessedResult(sandboxedHosts map[string]
 	// no DB is running on hosts, return a passed result
 	if len(upHosts) == 0 {
 		if op.sandbox != "" || op.mainCluster {
-			op.logger.PrintWarning("All the nodes in the database are down")
+			op.addWarning("", "All the nodes in the database are down")
 		}
 		return true
 	}
@@ -450,7 +452,9 @@ func (op *httpsCheckRunningDBOp) pollForDBDown(execContext *opEngineExecContext)
 			break
 		}
 		if count > 0 {
-			time.Sleep(PollingInterval * time.Second)
+			if err = sleepWithContext(execContext.ctx, PollingInterval*time.Second); err != nil {
+				return err
+			}
 		}
 		err = execContext.dispatcher.sendRequest(&op.clusterHTTPRequest, op.spinner)
 		if err != nil {
@@ -111,7 +111,7 @@ func (op *httpsCheckNodeStateOp) processResult(execContext *opEngineExecContext)
 		// parse the /nodes endpoint response
 		respondingNodeCount++
 		nodesStates := nodesStateInfo{}
-		err := op.parseAndCheckResponse(host, result.content, &nodesStates)
+		err := op.parseAndValidateResponse(host, result.content, &nodesStates)
 		if err != nil {
 			err = fmt.Errorf("[%s] fail to parse result on host %s: %w",
 				op.name, host, err)
@@ -66,12 +66,13 @@ func (op *httpsCheckSubclusterOp) setupClusterHTTPRequest(hosts []string) error
 }
 
 func (op *httpsCheckSubclusterOp) prepare(execContext *opEngineExecContext) error {
-	if len(execContext.upHosts) == 0 {
-		return fmt.Errorf(`[%s] Cannot find any up hosts in OpEngineExecContext`, op.name)
+	upHosts, err := execContext.requireUpHosts(op.name)
+	if err != nil {
+		return err
 	}
-	execContext.dispatcher.setup(execContext.upHosts)
+	execContext.dispatcher.setup(upHosts)
 
-	return op.setupClusterHTTPRequest(execContext.upHosts)
+	return op.setupClusterHTTPRequest(upHosts)
 }
 
 func (op *httpsCheckSubclusterOp) execute(execContext *opEngineExecContext) error {
@@ -89,6 +90,10 @@ type scInfo struct {
 	CtlSetSize  int    `json:"control_set_size"`
 }
 
+func (scInfo) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 func (op *httpsCheckSubclusterOp) processResult(_ *opEngineExecContext) error {
 	var err error
 
@@ -117,7 +122,7 @@ func (op *httpsCheckSubclusterOp) processResult(_ *opEngineExecContext) error {
 			}
 		*/
 		subclusterInfo := scInfo{}
-		err = op.parseAndCheckResponse(host, result.content, &subclusterInfo)
+		err = op.parseAndValidateResponse(host, result.content, &subclusterInfo)
 		if err != nil {
 			return fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
 		}
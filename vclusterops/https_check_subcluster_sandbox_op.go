@@ -83,6 +83,10 @@ type scResps struct {
 	SCInfoList []subclusterSandboxInfo `json:"subcluster_list"`
 }
 
+func (scResps) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 func (op *httpsCheckSubclusterSandboxOp) processResult(execContext *opEngineExecContext) error {
 	var allErrs error
 	keysToRemove := make(map[string]struct{})
@@ -125,7 +129,7 @@ func (op *httpsCheckSubclusterSandboxOp) processResult(execContext *opEngineExec
 			}
 		*/
 		subclusterResp := scResps{}
-		err := op.parseAndCheckResponse(host, result.content, &subclusterResp)
+		err := op.parseAndValidateResponse(host, result.content, &subclusterResp)
 		if err != nil {
 			err = fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
 			allErrs = errors.Join(allErrs, err)
@@ -0,0 +1,163 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// httpsCheckTLSConfigOp verifies that all of the up hosts report the
+// expected TLS configuration
+type httpsCheckTLSConfigOp struct {
+	opBase
+	opHTTPSBase
+	tlsConfig TLSConfig
+	// optional, saves the settings that the hosts report, e.g., to restore
+	// them later
+	reportedConfig *TLSConfig
+}
+
+func makeHTTPSCheckTLSConfigOp(useHTTPPassword bool, userName string, httpsPassword *string,
+	tlsConfig *TLSConfig) (httpsCheckTLSConfigOp, error) {
+	op := httpsCheckTLSConfigOp{}
+	op.name = "HTTPSCheckTLSConfigOp"
+	op.description = "Verify TLS configuration"
+	op.useHTTPPassword = useHTTPPassword
+	op.tlsConfig = *tlsConfig
+
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+
+	return op, nil
+}
+
+func (op *httpsCheckTLSConfigOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildHTTPSEndpoint("tls/" + string(op.tlsConfig.Name))
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsCheckTLSConfigOp) prepare(execContext *opEngineExecContext) error {
+	upHosts, err := execContext.requireUpHosts(op.name)
+	if err != nil {
+		return err
+	}
+	op.hosts = upHosts
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsCheckTLSConfigOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+// tlsConfigResponse is the response of GET /v1/tls/{config}, e.g.,
+//
+//	{
+//	  "name": "server",
+//	  "certificate": "server_cert",
+//	  "ca_certificates": ["ca_cert"],
+//	  "tls_mode": "VERIFY_CA"
+//	}
+type tlsConfigResponse struct {
+	Name           string   `json:"name"`
+	Certificate    string   `json:"certificate"`
+	CACertificates []string `json:"ca_certificates"`
+	TLSMode        string   `json:"tls_mode"`
+}
+
+func (tlsConfigResponse) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
+func (op *httpsCheckTLSConfigOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return makeWrongCredentialError(op.name, host)
+		}
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		response := tlsConfigResponse{}
+		err := op.parseAndValidateResponse(host, result.content, &response)
+		if err != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err))
+			continue
+		}
+		allErrs = errors.Join(allErrs, op.compare(host, &response))
+		if op.reportedConfig != nil {
+			*op.reportedConfig = TLSConfig{Name: op.tlsConfig.Name, Certificate: response.Certificate,
+				CACertificates: response.CACertificates, TLSMode: response.TLSMode}
+		}
+	}
+
+	return allErrs
+}
+
+// compare returns an error for each of the requested settings that the host
+// does not report
+func (op *httpsCheckTLSConfigOp) compare(host string, response *tlsConfigResponse) (err error) {
+	if op.tlsConfig.Certificate != "" && response.Certificate != op.tlsConfig.Certificate {
+		err = errors.Join(err, fmt.Errorf("[%s] host %s uses certificate %q for TLS configuration %s, expected %q",
+			op.name, host, response.Certificate, op.tlsConfig.Name, op.tlsConfig.Certificate))
+	}
+	if len(op.tlsConfig.CACertificates) > 0 &&
+		(len(util.SliceDiff(response.CACertificates, op.tlsConfig.CACertificates)) > 0 ||
+			len(util.SliceDiff(op.tlsConfig.CACertificates, response.CACertificates)) > 0) {
+		err = errors.Join(err, fmt.Errorf("[%s] host %s uses CA certificates [%s] for TLS configuration %s, expected [%s]",
+			op.name, host, strings.Join(response.CACertificates, ","), op.tlsConfig.Name,
+			strings.Join(op.tlsConfig.CACertificates, ",")))
+	}
+	if op.tlsConfig.TLSMode != "" && !strings.EqualFold(response.TLSMode, op.tlsConfig.TLSMode) {
+		err = errors.Join(err, fmt.Errorf("[%s] host %s uses TLS mode %s for TLS configuration %s, expected %s",
+			op.name, host, response.TLSMode, op.tlsConfig.Name, op.tlsConfig.TLSMode))
+	}
+	return err
+}
+
+func (op *httpsCheckTLSConfigOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
@@ -66,7 +66,7 @@ func (op *httpsConvertSandboxToMainOp) setupClusterHTTPRequest(hosts []string) e
 
 func (op *httpsConvertSandboxToMainOp) prepare(execContext *opEngineExecContext) error {
 	if len(op.hosts) == 0 {
-		return fmt.Errorf("[%s] cannot find any up hosts in the sandbox %s", op.name, op.sandbox)
+		return categorizeError(ErrNodeDown, fmt.Errorf("[%s] cannot find any up hosts in the sandbox %s", op.name, op.sandbox))
 	}
 	execContext.dispatcher.setup(op.hosts)
 
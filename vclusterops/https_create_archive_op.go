@@ -0,0 +1,127 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+type httpsCreateArchiveOp struct {
+	opBase
+	opHTTPSBase
+	archiveName      string
+	numRestorePoints int
+}
+
+func makeHTTPSCreateArchiveOp(useHTTPPassword bool, userName string, httpsPassword *string,
+	archiveName string, numRestorePoints int) (httpsCreateArchiveOp, error) {
+	op := httpsCreateArchiveOp{}
+	op.name = "HTTPSCreateArchiveOp"
+	op.description = "Create archive"
+	op.useHTTPPassword = useHTTPPassword
+	op.archiveName = archiveName
+	op.numRestorePoints = numRestorePoints
+
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+
+	return op, nil
+}
+
+func (op *httpsCreateArchiveOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildHTTPSEndpoint("archives/" + op.archiveName)
+		// zero means that the number of restore points is not limited
+		if op.numRestorePoints > 0 {
+			httpRequest.QueryParams = map[string]string{"num-restore-points": strconv.Itoa(op.numRestorePoints)}
+		}
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsCreateArchiveOp) prepare(execContext *opEngineExecContext) error {
+	// the archive is in the catalog, so one up host is enough
+	upHosts, err := execContext.requireUpHosts(op.name)
+	if err != nil {
+		return err
+	}
+	op.hosts = upHosts[:1]
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsCreateArchiveOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsCreateArchiveOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return makeWrongCredentialError(op.name, host)
+		}
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// decode the json-format response
+		// The successful response object will be a dictionary:
+		/*
+			{
+				"detail": ""
+			}
+		*/
+		_, err := op.parseAndCheckMapResponse(host, result.content)
+		if err != nil {
+			return fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
+		}
+
+		return nil
+	}
+
+	return allErrs
+}
+
+func (op *httpsCreateArchiveOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
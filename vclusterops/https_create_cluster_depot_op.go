@@ -101,6 +101,10 @@ type createDepotClusterRsp struct {
 	ClusterRsp []createDepotNodeRsp `json:"depots"`
 }
 
+func (createDepotClusterRsp) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 func (op *httpsCreateDepotOp) processResult(_ *opEngineExecContext) error {
 	var allErrs error
 
@@ -131,7 +135,7 @@ func (op *httpsCreateDepotOp) processResult(_ *opEngineExecContext) error {
 		  ]
 		} */
 		response := createDepotClusterRsp{}
-		err := op.parseAndCheckResponse(host, result.content, &response)
+		err := op.parseAndValidateResponse(host, result.content, &response)
 		if err != nil {
 			err = fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
 			allErrs = errors.Join(allErrs, err)
@@ -103,6 +103,10 @@ func (op *httpsCreateNodeOp) finalize(_ *opEngineExecContext) error {
 
 type httpsCreateNodeResponse map[string][]map[string]string
 
+func (httpsCreateNodeResponse) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 func (op *httpsCreateNodeOp) processResult(_ *opEngineExecContext) error {
 	var allErrs error
 
@@ -114,7 +118,7 @@ func (op *httpsCreateNodeOp) processResult(_ *opEngineExecContext) error {
 			// {'created_nodes': [{'name': 'v_running_db_node0002', 'catalog_path': '/data/v_running_db_node0002_catalog'},
 			//                    {'name': 'v_running_db_node0003', 'catalog_path': '/data/v_running_db_node0003_catalog'}]}
 			var responseObj httpsCreateNodeResponse
-			err := op.parseAndCheckResponse(host, result.content, &responseObj)
+			err := op.parseAndValidateResponse(host, result.content, &responseObj)
 
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
@@ -98,6 +98,10 @@ type namespaceListResponse struct {
 	NamespaceList []namespace `json:"namespace_list"`
 }
 
+func (namespaceListResponse) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 func (op *httpsDisallowMultipleNamespacesOp) processResult(_ *opEngineExecContext) error {
 	var allErrs error
 
@@ -135,7 +139,7 @@ func (op *httpsDisallowMultipleNamespacesOp) processResult(_ *opEngineExecContex
 			}
 		*/
 		namespaceResponse := namespaceListResponse{}
-		err := op.parseAndCheckResponse(host, result.content, &namespaceResponse)
+		err := op.parseAndValidateResponse(host, result.content, &namespaceResponse)
 		if err != nil {
 			allErrs = errors.Join(allErrs, err)
 			continue
@@ -95,6 +95,10 @@ type scResp struct {
 	SCInfoList []subclusterInfo `json:"subcluster_list"`
 }
 
+func (scResp) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 func (op *httpsFindSubclusterOp) processResult(execContext *opEngineExecContext) error {
 	var allErrs error
 
@@ -134,7 +138,7 @@ func (op *httpsFindSubclusterOp) processResult(execContext *opEngineExecContext)
 			}
 		*/
 		subclusterResp := scResp{}
-		err := op.parseAndCheckResponse(host, result.content, &subclusterResp)
+		err := op.parseAndValidateResponse(host, result.content, &subclusterResp)
 		if err != nil {
 			err = fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
 			allErrs = errors.Join(allErrs, err)
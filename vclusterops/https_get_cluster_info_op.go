@@ -86,6 +86,11 @@ type clusterStateInfo struct {
 	IsEon                    bool     `json:"is_eon"`
 	DBName                   string   `json:"db_name"`
 	CommunalStorageLocations []string `json:"commnual_storage_locations"`
+	KSafety                  int      `json:"k_safety"`
+}
+
+func (clusterStateInfo) supportedVersions() []string {
+	return []string{HTTPVersion1}
 }
 
 func (op *httpsGetClusterInfoOp) processResult(_ *opEngineExecContext) error {
@@ -94,14 +99,13 @@ func (op *httpsGetClusterInfoOp) processResult(_ *opEngineExecContext) error {
 		op.logResponse(host, result)
 
 		if result.isUnauthorizedRequest() {
-			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
-				op.name, host)
+			return makeWrongCredentialError(op.name, host)
 		}
 
 		if result.isPassing() {
 			// unmarshal the response content
 			clusterState := clusterStateInfo{}
-			err := op.parseAndCheckResponse(host, result.content, &clusterState)
+			err := op.parseAndValidateResponse(host, result.content, &clusterState)
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
 				return appendHTTPSFailureError(allErrs)
@@ -111,6 +115,7 @@ func (op *httpsGetClusterInfoOp) processResult(_ *opEngineExecContext) error {
 			op.vdb.IsEon = clusterState.IsEon
 			op.vdb.UseDepot = clusterState.IsEon
 			op.vdb.Name = clusterState.DBName
+			op.vdb.KSafety = clusterState.KSafety
 			if op.vdb.Name != op.dbName {
 				err = fmt.Errorf(`[%s] database %s is running on host %s, rather than database %s`, op.name, op.vdb.Name, host, op.dbName)
 				allErrs = errors.Join(allErrs, err)
@@ -0,0 +1,179 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// httpsGetClusterNodeStatesOp collects the view of the cluster from every
+// reachable host, unlike httpsCheckNodeStateOp which stops at the first
+// responding host. The views are reconciled later by the caller.
+type httpsGetClusterNodeStatesOp struct {
+	opBase
+	opHTTPSBase
+	// reporting host -> the node states seen by that host
+	hostNodeStateViews map[string][]NodeStateDetails
+}
+
+func makeHTTPSGetClusterNodeStatesOp(hosts []string,
+	useHTTPPassword bool,
+	userName string,
+	httpsPassword *string,
+	hostNodeStateViews map[string][]NodeStateDetails,
+) (httpsGetClusterNodeStatesOp, error) {
+	op := httpsGetClusterNodeStatesOp{}
+	op.name = "HTTPSGetClusterNodeStatesOp"
+	op.description = "Collect node states from all reachable hosts"
+	op.hosts = hosts
+	op.useHTTPPassword = useHTTPPassword
+
+	err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+	if err != nil {
+		return op, err
+	}
+
+	op.userName = userName
+	op.httpsPassword = httpsPassword
+	op.hostNodeStateViews = hostNodeStateViews
+	return op, nil
+}
+
+func (op *httpsGetClusterNodeStatesOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildHTTPSEndpoint("nodes")
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsGetClusterNodeStatesOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsGetClusterNodeStatesOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+// nodeStateDetailInfo is a node entry of the /nodes response,
+// including the fields that the other /nodes callers do not need
+type nodeStateDetailInfo struct {
+	nodeStateInfo
+	CatalogVersion    int64   `json:"catalog_version"`
+	UpSince           string  `json:"up_since"`
+	DepotUsagePercent float64 `json:"depot_usage_percent"`
+}
+
+type nodesStateDetailInfo struct {
+	NodeList []*nodeStateDetailInfo `json:"node_list"`
+}
+
+func (nodesStateDetailInfo) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
+func (node *nodeStateDetailInfo) asNodeStateDetails(now time.Time) NodeStateDetails {
+	n := NodeStateDetails{
+		Name:             node.Name,
+		Address:          node.Address,
+		Subcluster:       node.Subcluster,
+		Sandbox:          node.Sandbox,
+		State:            node.State,
+		IsPrimary:        node.IsPrimary,
+		CatalogVersion:   node.CatalogVersion,
+		UpSince:          node.UpSince,
+		DepotFillPercent: node.DepotUsagePercent,
+		Version:          node.Version,
+		CatalogPath:      node.CatalogPath,
+		DepotPath:        node.DepotPath,
+		DataPaths:        node.StorageLocations,
+	}
+	// up_since is null for the nodes that are not UP
+	if node.UpSince != "" {
+		upSince, err := time.Parse(time.RFC3339, node.UpSince)
+		if err == nil && now.After(upSince) {
+			n.UptimeSeconds = int64(now.Sub(upSince).Seconds())
+		}
+	}
+	return n
+}
+
+func (op *httpsGetClusterNodeStatesOp) processResult(execContext *opEngineExecContext) error {
+	var allErrs error
+	now := time.Now()
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			op.logger.PrintError("[%s] unauthorized request: %s", op.name, result.content)
+			execContext.hostsWithWrongAuth = append(execContext.hostsWithWrongAuth, host)
+			// we assume that we will get the same error across other nodes
+			return errors.Join(allErrs, result.err)
+		}
+
+		if !result.isPassing() {
+			// an unreachable host is reported by the caller, we continue to the next host
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// example response:
+		// {"node_list": [{"name": "v_test_db_node0001", "address": "192.168.1.101", "state": "UP",
+		//   "subcluster_name": "default_subcluster", "sandbox_name": "", "is_primary": true,
+		//   "build_info": "v24.3.0-a0efe9ba3abb08d9e6472ffc29c8e0949b5998d2", "catalog_version": 1043,
+		//   "up_since": "2024-04-05T12:33:19-04:00", "depot_usage_percent": 37.5}, ...]}
+		nodesStates := nodesStateDetailInfo{}
+		err := op.parseAndValidateResponse(host, result.content, &nodesStates)
+		if err != nil {
+			err = fmt.Errorf("[%s] fail to parse result on host %s: %w", op.name, host, err)
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+
+		view := make([]NodeStateDetails, 0, len(nodesStates.NodeList))
+		for _, node := range nodesStates.NodeList {
+			view = append(view, node.asNodeStateDetails(now))
+		}
+		op.hostNodeStateViews[host] = view
+	}
+
+	if len(op.hostNodeStateViews) == 0 {
+		return errors.Join(fmt.Errorf("[%s] no host returned the node states", op.name), allErrs)
+	}
+	return nil
+}
+
+func (op *httpsGetClusterNodeStatesOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
@@ -81,6 +81,10 @@ type nodeStateResp struct {
 	NodeStates []NodeState `json:"node_list"`
 }
 
+func (nodeStateResp) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 func (op *httpsGetLocalNodeStateOp) processResult(_ *opEngineExecContext) error {
 	for host, result := range op.clusterHTTPRequest.ResultCollection {
 		op.logResponse(host, result)
@@ -122,7 +126,7 @@ func (op *httpsGetLocalNodeStateOp) processResult(_ *opEngineExecContext) error
 			}
 		*/
 		resp := nodeStateResp{}
-		err := op.parseAndCheckResponse(host, result.content, &resp)
+		err := op.parseAndValidateResponse(host, result.content, &resp)
 		if err != nil {
 			return fmt.Errorf(`[%s] failed to parse result on host %s, details: %w`, op.name, host, err)
 		}
@@ -118,7 +118,7 @@ func (op *httpsGetStorageLocsOp) processResult(_ *opEngineExecContext) error {
 			}
 		*/
 		storageLocs := StorageLocations{}
-		err := op.parseAndCheckResponse(host, result.content, &storageLocs)
+		err := op.parseAndValidateResponse(host, result.content, &storageLocs)
 		if err != nil {
 			return fmt.Errorf(`[%s] failed to parse result on host %s, details: %w`, op.name, host, err)
 		}
@@ -113,14 +113,13 @@ func (op *httpsGetNodesInfoOp) processResult(_ *opEngineExecContext) error {
 		op.logResponse(host, result)
 
 		if result.isUnauthorizedRequest() {
-			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
-				op.name, host)
+			return makeWrongCredentialError(op.name, host)
 		}
 
 		if result.isPassing() {
 			// parse the /nodes endpoint response
 			nodesStates := nodesStateInfo{}
-			err := op.parseAndCheckResponse(host, result.content, &nodesStates)
+			err := op.parseAndValidateResponse(host, result.content, &nodesStates)
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
 				break
@@ -161,7 +160,7 @@ func (op *httpsGetNodesInfoOp) processResult(_ *opEngineExecContext) error {
 				dbPath := "/" + node.Database
 				index := strings.Index(node.CatalogPath, dbPath)
 				if index == -1 {
-					op.logger.PrintWarning("[%s] failed to get catalog prefix because catalog path %s does not contain database name %s",
+					op.addWarning(host, "[%s] failed to get catalog prefix because catalog path %s does not contain database name %s",
 						op.name, node.CatalogPath, node.Database)
 				}
 				op.vdb.CatalogPrefix = node.CatalogPath[:index]
@@ -0,0 +1,161 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+const (
+	replicationStatusCompleted = "completed"
+	replicationStatusFailed    = "failed"
+)
+
+type httpsGetReplicationStatusOp struct {
+	opBase
+	opHTTPSBase
+	transactionID int64
+	status        *ReplicationStatus
+}
+
+func makeHTTPSGetReplicationStatusOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, transactionID int64, status *ReplicationStatus) (httpsGetReplicationStatusOp, error) {
+	op := httpsGetReplicationStatusOp{}
+	op.name = "HTTPSGetReplicationStatusOp"
+	op.description = "Get replication status"
+	op.hosts = hosts
+	op.useHTTPPassword = useHTTPPassword
+	op.transactionID = transactionID
+	op.status = status
+
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+
+	return op, nil
+}
+
+func (op *httpsGetReplicationStatusOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildHTTPSEndpoint("replicate/status")
+		httpRequest.QueryParams = map[string]string{"transaction_id": strconv.FormatInt(op.transactionID, 10)}
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsGetReplicationStatusOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsGetReplicationStatusOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+// The response of the target database should look like
+/*	{
+		"transaction_id": 45035996273704962,
+		"phase": "data_transfer",
+		"status": "running",
+		"bytes_transferred": 1048576,
+		"total_bytes": 4194304,
+		"tables_done": 3,
+		"tables_total": 10,
+		"error": ""
+	}
+*/
+type replicationStatusResponse struct {
+	TransactionID    int64  `json:"transaction_id"`
+	Phase            string `json:"phase"`
+	Status           string `json:"status"`
+	BytesTransferred int64  `json:"bytes_transferred"`
+	TotalBytes       int64  `json:"total_bytes"`
+	TablesDone       int    `json:"tables_done"`
+	TablesTotal      int    `json:"tables_total"`
+	Error            string `json:"error"`
+}
+
+func (replicationStatusResponse) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
+func (op *httpsGetReplicationStatusOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return makeWrongCredentialError(op.name, host)
+		}
+
+		if result.isPassing() {
+			statusRsp := replicationStatusResponse{}
+			err := op.parseAndValidateResponse(host, result.content, &statusRsp)
+			if err != nil {
+				allErrs = errors.Join(allErrs, err)
+				continue
+			}
+			if statusRsp.TransactionID != op.transactionID {
+				allErrs = errors.Join(allErrs, fmt.Errorf("[%s] host %s returned the status of transaction %d, rather than %d",
+					op.name, host, statusRsp.TransactionID, op.transactionID))
+				continue
+			}
+			op.updateStatus(&statusRsp)
+			// any host of the target database can report the status
+			return nil
+		}
+		allErrs = errors.Join(allErrs, result.err)
+	}
+	return appendHTTPSFailureError(allErrs)
+}
+
+func (op *httpsGetReplicationStatusOp) updateStatus(statusRsp *replicationStatusResponse) {
+	op.status.Phase = statusRsp.Phase
+	op.status.BytesTransferred = statusRsp.BytesTransferred
+	op.status.TotalBytes = statusRsp.TotalBytes
+	op.status.TablesDone = statusRsp.TablesDone
+	op.status.TablesTotal = statusRsp.TablesTotal
+	op.status.Completed = statusRsp.Status == replicationStatusCompleted
+	op.status.Failed = statusRsp.Status == replicationStatusFailed
+	op.status.FailureDetail = statusRsp.Error
+}
+
+func (op *httpsGetReplicationStatusOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+)
+
+// httpsGetSubscriptionsOp gets the ACTIVE shard subscriptions of the nodes
+// from the first host that responds
+type httpsGetSubscriptionsOp struct {
+	opBase
+	opHTTPSBase
+	// the names of the nodes with an ACTIVE subscription, keyed by shard name
+	shardSubscribers *map[string][]string
+}
+
+func makeHTTPSGetSubscriptionsOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, shardSubscribers *map[string][]string) (httpsGetSubscriptionsOp, error) {
+	op := httpsGetSubscriptionsOp{}
+	op.name = "HTTPSGetSubscriptionsOp"
+	op.description = "Get shard subscriptions"
+	op.hosts = hosts
+	op.shardSubscribers = shardSubscribers
+	err := op.validateAndSetUsernameAndPassword(op.name, useHTTPPassword, userName, httpsPassword)
+	return op, err
+}
+
+func (op *httpsGetSubscriptionsOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildHTTPSEndpoint("subscriptions")
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsGetSubscriptionsOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsGetSubscriptionsOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsGetSubscriptionsOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *httpsGetSubscriptionsOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+		var subscriptList subscriptionList
+		err := op.parseAndValidateResponse(host, result.content, &subscriptList)
+		if err != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] fail to parse result on host %s, details: %w", op.name, host, err))
+			continue
+		}
+		shardSubscribers := make(map[string][]string)
+		for _, s := range subscriptList.SubscriptionList {
+			if s.SubscriptionState == "ACTIVE" {
+				shardSubscribers[s.ShardName] = append(shardSubscribers[s.ShardName], s.Nodename)
+			}
+		}
+		*op.shardSubscribers = shardSubscribers
+		return nil
+	}
+
+	return allErrs
+}
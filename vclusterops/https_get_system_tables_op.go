@@ -17,7 +17,6 @@ package vclusterops
 
 import (
 	"errors"
-	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
@@ -67,9 +66,10 @@ func (op *httpsGetSystemTablesOp) setupClusterHTTPRequest(hosts []string) error
 }
 
 func (op *httpsGetSystemTablesOp) prepare(execContext *opEngineExecContext) error {
-	host := getInitiatorFromUpHosts(execContext.upHosts, op.hosts)
+	upHosts, _ := execContext.UpHosts()
+	host := getInitiatorFromUpHosts(upHosts, op.hosts)
 	if host == "" {
-		op.logger.PrintWarning("no up hosts among user specified hosts to collect system tables from, skipping the operation")
+		op.addWarning("", "no up hosts among user specified hosts to collect system tables from, skipping the operation")
 		op.skipExecute = true
 		return nil
 	}
@@ -98,20 +98,23 @@ type systemTableListInfo struct {
 	SystemTableList []systemTableInfo `json:"system_table_list"`
 }
 
+func (systemTableListInfo) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 func (op *httpsGetSystemTablesOp) processResult(execContext *opEngineExecContext) error {
 	var allErrs error
 	for host, result := range op.clusterHTTPRequest.ResultCollection {
 		op.logResponse(host, result)
 
 		if result.isUnauthorizedRequest() {
-			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
-				op.name, host)
+			return makeWrongCredentialError(op.name, host)
 		}
 
 		if result.isPassing() {
 			// unmarshal the response content
 			systemTableList := systemTableListInfo{}
-			err := op.parseAndCheckResponse(host, result.content, &systemTableList)
+			err := op.parseAndValidateResponse(host, result.content, &systemTableList)
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
 				return appendHTTPSFailureError(allErrs)
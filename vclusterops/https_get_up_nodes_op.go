@@ -35,6 +35,16 @@ const (
 	UnsandboxCmd
 	ManageConnectionDrainingCmd
 	SetConfigurationParametersCmd
+	StopNodeCmd
+	SetTLSConfigCmd
+	DeployServerCertificateCmd
+	CreateArchiveCmd
+	SaveRestorePointCmd
+	RemoveRestorePointCmd
+	ListConfigurationParametersCmd
+	ReIPCmd
+	SetReadOnlyCmd
+	RotateTLSCertsCmd
 )
 
 type CommandType int
@@ -48,6 +58,10 @@ type httpsGetUpNodesOp struct {
 	sandbox     string
 	mainCluster bool
 	scName      string
+	// when set, only the UP nodes in op.sandbox are collected
+	sandboxScoped bool
+	// when set, only the UP nodes that it selects are collected
+	targetSelector *TargetSelector
 }
 
 func makeHTTPSGetUpNodesOp(dbName string, hosts []string,
@@ -95,6 +109,21 @@ func (op *httpsGetUpNodesOp) allowNoUpHosts() {
 	op.noUpHostsOk = true
 }
 
+// restrictToSandbox makes the op ignore the UP nodes that are not in the given
+// sandbox, so that the later ops cannot pick a node outside of it as the initiator.
+// Use util.MainClusterSandbox to restrict the discovery to the main cluster.
+func (op *httpsGetUpNodesOp) restrictToSandbox(sandbox string) {
+	op.sandbox = sandbox
+	op.mainCluster = sandbox == util.MainClusterSandbox
+	op.sandboxScoped = true
+}
+
+// selectTargets makes the op ignore the UP nodes that are not selected by the
+// target selector
+func (op *httpsGetUpNodesOp) selectTargets(selector *TargetSelector) {
+	op.targetSelector = selector
+}
+
 func (op *httpsGetUpNodesOp) setupClusterHTTPRequest(hosts []string) error {
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
@@ -168,12 +197,13 @@ func (op *httpsGetUpNodesOp) processResult(execContext *opEngineExecContext) err
 			}
 			// Connection refused: node is down
 			downHosts = append(downHosts, host)
+			op.markExpectedFailure(host)
 			continue
 		}
 
 		// Parse response from /nodes to validate input
 		nodesStates := nodesStateInfo{}
-		err := op.parseAndCheckResponse(host, result.content, &nodesStates)
+		err := op.parseAndValidateResponse(host, result.content, &nodesStates)
 		if err != nil {
 			err = fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
 			allErrs = errors.Join(allErrs, err)
@@ -221,7 +251,9 @@ func isCompleteScanRequired(cmdType CommandType) bool {
 	return cmdType == SandboxCmd || cmdType == StopDBCmd ||
 		cmdType == UnsandboxCmd || cmdType == StopSubclusterCmd ||
 		cmdType == ManageConnectionDrainingCmd ||
-		cmdType == SetConfigurationParametersCmd
+		cmdType == SetConfigurationParametersCmd ||
+		cmdType == ListConfigurationParametersCmd ||
+		cmdType == SetReadOnlyCmd
 }
 
 func (op *httpsGetUpNodesOp) finalize(_ *opEngineExecContext) error {
@@ -262,11 +294,19 @@ func (op *httpsGetUpNodesOp) processHostLists(upHosts mapset.Set[string], upScIn
 		}
 	}
 	if upHosts.Cardinality() > 0 {
-		execContext.upHosts = upHosts.ToSlice()
+		sortedUpHosts := upHosts.ToSlice()
 		// sorting the up hosts will be helpful for picking up the initiator in later instructions
-		sort.Strings(execContext.upHosts)
+		sort.Strings(sortedUpHosts)
+		execContext.SetUpHosts(sortedUpHosts)
 		return true, nil
 	}
+	if op.sandboxScoped && len(exceptionHosts) == 0 && len(downHosts) == 0 {
+		if op.mainCluster {
+			errMsg = fmt.Errorf("[%s] no UP nodes detected in the main cluster of database %s", op.name, op.DBName)
+		} else {
+			errMsg = fmt.Errorf("[%s] no UP nodes detected in sandbox %s of database %s", op.name, op.sandbox, op.DBName)
+		}
+	}
 	if len(exceptionHosts) > 0 {
 		op.logger.PrintError(`[%s] fail to call https endpoint of database %s on hosts %s`, op.name, op.DBName, exceptionHosts)
 		errMsg = errors.Join(errMsg, fmt.Errorf("failed to access node on hosts %v", exceptionHosts))
@@ -320,11 +360,15 @@ func (op *httpsGetUpNodesOp) collectUpHosts(nodesStates nodesStateInfo, host str
 		if op.scName != "" && node.Subcluster == op.scName {
 			foundSC = true
 		}
-		if node.State == util.NodeUpState {
+		if node.State == util.NodeUpState && !op.isCollectable(node) {
+			op.logger.Info("skip UP node that is not targeted", "address", node.Address, "sandbox", node.Sandbox,
+				"subcluster", node.Subcluster)
+		} else if node.State == util.NodeUpState {
 			upHosts.Add(node.Address)
 			upScInfo[node.Address] = node.Subcluster
 			if op.cmdType == ManageConnectionDrainingCmd ||
 				op.cmdType == SetConfigurationParametersCmd ||
+				op.cmdType == ListConfigurationParametersCmd ||
 				op.cmdType == StopDBCmd {
 				sandboxInfo[node.Address] = node.Sandbox
 			}
@@ -357,6 +401,14 @@ func (op *httpsGetUpNodesOp) collectUpHosts(nodesStates nodesStateInfo, host str
 	return err
 }
 
+// isCollectable returns true if the node can be collected as an UP node
+func (op *httpsGetUpNodesOp) isCollectable(node *nodeStateInfo) bool {
+	if op.sandboxScoped && node.Sandbox != op.sandbox {
+		return false
+	}
+	return op.targetSelector == nil || op.targetSelector.isSelected(node.Address, node.Subcluster, node.IsPrimary)
+}
+
 func (op *httpsGetUpNodesOp) collectUnsandboxingHosts(nodesStates nodesStateInfo, sandboxInfo map[string]string) {
 	mainNodeFound := false
 	sandboxNodeFound := false
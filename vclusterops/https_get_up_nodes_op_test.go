@@ -0,0 +1,71 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestCollectUpHostsInSandbox(t *testing.T) {
+	const dbName = "test_db"
+	nodesStates := nodesStateInfo{NodeList: []*nodeStateInfo{
+		{Address: "192.168.1.101", State: util.NodeUpState, Database: dbName, Subcluster: "sc1"},
+		{Address: "192.168.1.102", State: util.NodeUpState, Database: dbName, Subcluster: "sc2", Sandbox: "sand"},
+		{Address: "192.168.1.103", State: util.NodeDownState, Database: dbName, Subcluster: "sc1"},
+	}}
+
+	collect := func(op *httpsGetUpNodesOp) (mapset.Set[string], map[string]string) {
+		upHosts := mapset.NewSet[string]()
+		sandboxInfo := make(map[string]string)
+		err := op.collectUpHosts(nodesStates, "192.168.1.101", upHosts, make(map[string]string), sandboxInfo,
+			mapset.NewSet[NodeInfo](), mapset.NewSet[NodeInfo]())
+		assert.NoError(t, err)
+		return upHosts, sandboxInfo
+	}
+
+	// without a restriction, the UP nodes of all sandboxes are collected
+	op, err := makeHTTPSGetUpNodesOp(dbName, nil, false, "", nil, SetConfigurationParametersCmd)
+	assert.NoError(t, err)
+	upHosts, sandboxInfo := collect(&op)
+	assert.ElementsMatch(t, []string{"192.168.1.101", "192.168.1.102"}, upHosts.ToSlice())
+	assert.Len(t, sandboxInfo, 2)
+
+	// restricted to the main cluster
+	op.restrictToSandbox(util.MainClusterSandbox)
+	upHosts, sandboxInfo = collect(&op)
+	assert.ElementsMatch(t, []string{"192.168.1.101"}, upHosts.ToSlice())
+	assert.Equal(t, map[string]string{"192.168.1.101": ""}, sandboxInfo)
+
+	// restricted to a sandbox
+	op.restrictToSandbox("sand")
+	upHosts, sandboxInfo = collect(&op)
+	assert.ElementsMatch(t, []string{"192.168.1.102"}, upHosts.ToSlice())
+	assert.Equal(t, map[string]string{"192.168.1.102": "sand"}, sandboxInfo)
+
+	// no UP node in the sandbox is an error even if all hosts answered
+	op.restrictToSandbox("other")
+	upHosts, _ = collect(&op)
+	assert.Equal(t, 0, upHosts.Cardinality())
+	execContext := makeOpEngineExecContext(context.Background(), vlog.Printer{})
+	_, errMsg := op.processHostLists(upHosts, nil, nil, nil, nil, &execContext)
+	assert.ErrorContains(t, errMsg, "no UP nodes detected in sandbox other")
+}
@@ -0,0 +1,142 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// httpsImportCertificateOp imports a certificate and its private key into the
+// catalog, which then distributes them to all of the nodes
+type httpsImportCertificateOp struct {
+	opBase
+	opHTTPSBase
+	certName        string
+	hostRequestBody string
+}
+
+type importCertificateRequestData struct {
+	Name        string `json:"name"`
+	Certificate string `json:"certificate"`
+	PrivateKey  string `json:"private_key,omitempty"`
+}
+
+func makeHTTPSImportCertificateOp(useHTTPPassword bool, userName string, httpsPassword *string,
+	certName, certPEM, keyPEM string) (httpsImportCertificateOp, error) {
+	op := httpsImportCertificateOp{}
+	op.name = "HTTPSImportCertificateOp"
+	op.description = "Import certificate"
+	op.useHTTPPassword = useHTTPPassword
+	op.certName = certName
+
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+
+	requestData := importCertificateRequestData{
+		Name:        certName,
+		Certificate: certPEM,
+		PrivateKey:  keyPEM,
+	}
+	dataBytes, err := json.Marshal(requestData)
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.hostRequestBody = string(dataBytes)
+
+	return op, nil
+}
+
+func (op *httpsImportCertificateOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildHTTPSEndpoint("certificates")
+		httpRequest.RequestData = op.hostRequestBody
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsImportCertificateOp) prepare(execContext *opEngineExecContext) error {
+	// the certificates are in the catalog, so one up host is enough
+	upHosts, err := execContext.requireUpHosts(op.name)
+	if err != nil {
+		return err
+	}
+	op.hosts = upHosts[:1]
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsImportCertificateOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsImportCertificateOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return makeWrongCredentialError(op.name, host)
+		}
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// decode the json-format response
+		// The successful response object will be a dictionary:
+		/*
+			{
+				"detail": ""
+			}
+		*/
+		_, err := op.parseAndCheckMapResponse(host, result.content)
+		if err != nil {
+			return fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
+		}
+
+		return nil
+	}
+
+	return allErrs
+}
+
+func (op *httpsImportCertificateOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 )
@@ -72,11 +73,12 @@ func (op *httpsInstallPackagesOp) setupClusterHTTPRequest(hosts []string) error
 func (op *httpsInstallPackagesOp) prepare(execContext *opEngineExecContext) error {
 	// If no hosts passed in, we will find the hosts from execute-context
 	if len(op.hosts) == 0 {
-		if len(execContext.upHosts) == 0 {
-			return fmt.Errorf(`[%s] Cannot find any up hosts in OpEngineExecContext`, op.name)
+		upHosts, err := execContext.requireUpHosts(op.name)
+		if err != nil {
+			return err
 		}
 		// use first up host to execute https post request
-		op.hosts = []string{execContext.upHosts[0]}
+		op.hosts = []string{upHosts[0]}
 	}
 	execContext.dispatcher.setup(op.hosts)
 
@@ -114,11 +116,49 @@ structs, will look like this:
 }
 */
 
+// The install status of a package, as returned by the packages endpoint.
+// The endpoint is not consistent in the case, so compare them with PackageStatus.HasStatus.
+const (
+	PackageInstallSuccess = "success"
+	PackageInstallSkipped = "skipped"
+	PackageInstallFailure = "failure"
+)
+
 // InstallPackageStatus provides status for each package install attempted.
 type InstallPackageStatus struct {
 	Packages []PackageStatus `json:"packages"`
 }
 
+func (InstallPackageStatus) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
+// getPackageNames returns the names of the packages with the given install status
+func (status *InstallPackageStatus) getPackageNames(installStatus string) []string {
+	names := []string{}
+	for i := range status.Packages {
+		if status.Packages[i].HasStatus(installStatus) {
+			names = append(names, status.Packages[i].PackageName)
+		}
+	}
+	return names
+}
+
+// InstalledPackages returns the names of the packages that were installed
+func (status *InstallPackageStatus) InstalledPackages() []string {
+	return status.getPackageNames(PackageInstallSuccess)
+}
+
+// SkippedPackages returns the names of the packages that were already installed
+func (status *InstallPackageStatus) SkippedPackages() []string {
+	return status.getPackageNames(PackageInstallSkipped)
+}
+
+// FailedPackages returns the names of the packages that failed to install
+func (status *InstallPackageStatus) FailedPackages() []string {
+	return status.getPackageNames(PackageInstallFailure)
+}
+
 // PackageStatus has install status for a single package.
 type PackageStatus struct {
 	// Name of the package this status is for
@@ -128,6 +168,11 @@ type PackageStatus struct {
 	InstallStatus string `json:"install_status"`
 }
 
+// HasStatus returns true if the package has the given install status, ignoring the case
+func (s *PackageStatus) HasStatus(installStatus string) bool {
+	return strings.EqualFold(s.InstallStatus, installStatus)
+}
+
 func (op *httpsInstallPackagesOp) processResult(_ *opEngineExecContext) error {
 	var allErrs error
 
@@ -139,7 +184,7 @@ func (op *httpsInstallPackagesOp) processResult(_ *opEngineExecContext) error {
 			continue
 		}
 
-		err := op.parseAndCheckResponse(host, result.content, &op.status)
+		err := op.parseAndValidateResponse(host, result.content, &op.status)
 		if err != nil {
 			allErrs = errors.Join(allErrs, err)
 			continue
@@ -158,6 +203,9 @@ func (op *httpsInstallPackagesOp) processResult(_ *opEngineExecContext) error {
 		} else {
 			op.logger.V(1).Info(msg)
 		}
+		if failedPackages := op.status.FailedPackages(); len(failedPackages) > 0 {
+			op.logger.PrintWarning("[%s] packages %v failed to install", op.name, failedPackages)
+		}
 	}
 	return allErrs
 }
@@ -0,0 +1,51 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstallPackagesOpStatus(t *testing.T) {
+	const host = "192.168.1.101"
+	op, err := makeHTTPSInstallPackagesOp([]string{host}, false, "", nil, true, false)
+	assert.NoError(t, err)
+	op.setupBasicInfo()
+
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: SUCCESS, statusCode: SuccessCode, host: host, content: `{"packages": [
+			{"package_name": "ComplexTypes", "install_status": "Success"},
+			{"package_name": "DelimitedExport", "install_status": "skipped"},
+			{"package_name": "flextable", "install_status": "Failure"},
+			{"package_name": "kafka", "install_status": "success"}
+		]}`},
+	}
+	// a failed package does not fail the op
+	assert.NoError(t, op.processResult(nil))
+	assert.Equal(t, []string{"ComplexTypes", "kafka"}, op.status.InstalledPackages())
+	assert.Equal(t, []string{"DelimitedExport"}, op.status.SkippedPackages())
+	assert.Equal(t, []string{"flextable"}, op.status.FailedPackages())
+
+	// negative: no package in the response
+	op.status = InstallPackageStatus{}
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: SUCCESS, statusCode: SuccessCode, host: host, content: `{"packages": []}`},
+	}
+	assert.ErrorContains(t, op.processResult(nil), "does not have status for any packages")
+	assert.Empty(t, op.status.FailedPackages())
+}
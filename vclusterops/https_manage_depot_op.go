@@ -0,0 +1,156 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+type httpsManageDepotOp struct {
+	opBase
+	opHTTPSBase
+	hostNodeMap vHostNodeMap
+	action      DepotAction
+	depotSize   string
+	requestBody string
+	// results of the hosts, in the order of op.hosts
+	results *[]DepotActionResult
+}
+
+type warmDepotRequestData struct {
+	Objects []string `json:"objects"`
+}
+
+// makeHTTPSManageDepotOp makes an op that calls the vertica-http service of each
+// host to resize, clear, or warm the depot of the node on that host
+func makeHTTPSManageDepotOp(hosts []string, vdb *VCoordinationDatabase, action DepotAction,
+	depotSize string, objects []string, useHTTPPassword bool, userName string, httpsPassword *string,
+	results *[]DepotActionResult) (httpsManageDepotOp, error) {
+	op := httpsManageDepotOp{}
+	op.name = "HTTPSManageDepotOp"
+	op.description = fmt.Sprintf("Run depot action %s", action)
+	op.hosts = hosts
+	op.hostNodeMap = vdb.HostNodeMap
+	op.action = action
+	op.depotSize = depotSize
+	op.results = results
+	op.useHTTPPassword = useHTTPPassword
+
+	err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+	if err != nil {
+		return op, err
+	}
+	op.userName = userName
+	op.httpsPassword = httpsPassword
+
+	if action == DepotActionWarm {
+		dataBytes, err := json.Marshal(warmDepotRequestData{Objects: objects})
+		if err != nil {
+			return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+		}
+		op.requestBody = string(dataBytes)
+	}
+	return op, nil
+}
+
+func (op *httpsManageDepotOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		endpoint := "nodes/" + op.hostNodeMap[host].Name + "/depot"
+		switch op.action {
+		case DepotActionResize:
+			httpRequest.Method = PutMethod
+			httpRequest.QueryParams = map[string]string{"size": op.depotSize}
+		case DepotActionClear:
+			httpRequest.Method = DeleteMethod
+			endpoint += "/contents"
+		case DepotActionWarm:
+			httpRequest.Method = PostMethod
+			endpoint += "/warm"
+			httpRequest.RequestData = op.requestBody
+		default:
+			return fmt.Errorf("[%s] unknown depot action %q", op.name, op.action)
+		}
+		httpRequest.buildHTTPSEndpoint(endpoint)
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsManageDepotOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsManageDepotOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsManageDepotOp) processResult(_ *opEngineExecContext) error {
+	var failedHosts []string
+
+	// every host gets a result, so that the caller can retry the failed ones
+	for _, host := range op.hosts {
+		result := op.clusterHTTPRequest.ResultCollection[host]
+		op.logResponse(host, result)
+
+		actionResult := DepotActionResult{Host: host, NodeName: op.hostNodeMap[host].Name}
+		if result.isPassing() {
+			/* decode the json-format response
+			The successful response object will be a dictionary like below:
+			{
+				"detail": "Depot resized to 10G"
+			}
+			*/
+			resp, err := op.parseAndCheckMapResponse(host, result.content)
+			if err != nil {
+				actionResult.Message = err.Error()
+			} else {
+				actionResult.Succeeded = true
+				actionResult.Message = resp["detail"]
+			}
+		} else if result.err != nil {
+			actionResult.Message = result.err.Error()
+		}
+
+		if !actionResult.Succeeded {
+			failedHosts = append(failedHosts, host)
+		}
+		*op.results = append(*op.results, actionResult)
+	}
+
+	if len(failedHosts) > 0 {
+		return fmt.Errorf("[%s] failed to %s the depot on hosts %v", op.name, op.action, failedHosts)
+	}
+	return nil
+}
+
+func (op *httpsManageDepotOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
@@ -99,6 +99,10 @@ type markDesignKSafeRsp struct {
 	Detail string `json:"detail"`
 }
 
+func (markDesignKSafeRsp) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 func (op *httpsMarkDesignKSafeOp) processResult(_ *opEngineExecContext) error {
 	var allErrs error
 
@@ -114,7 +118,7 @@ func (op *httpsMarkDesignKSafeOp) processResult(_ *opEngineExecContext) error {
 		// The response object will be a dictionary, an example:
 		// {"detail": "Marked design 0-safe"}
 		markDesignKSafeResponse := markDesignKSafeRsp{}
-		err := op.parseAndCheckResponse(host, result.content, &markDesignKSafeResponse)
+		err := op.parseAndValidateResponse(host, result.content, &markDesignKSafeResponse)
 		if err != nil {
 			err = fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
 			allErrs = errors.Join(allErrs, err)
@@ -0,0 +1,196 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"golang.org/x/exp/slices"
+)
+
+// httpsPollActiveSessionsOp waits for the user sessions of the database to
+// end after the new connections are paused. Reaching the timeout is not an
+// error, as the sessions left are closed when the database stops.
+type httpsPollActiveSessionsOp struct {
+	opBase
+	opHTTPSBase
+	timeout   int
+	sandbox   string
+	initiator string
+	// when set, only the sessions on these nodes are waited for
+	nodeNames []string
+	// number of user sessions in the last response
+	activeSessionCount int
+}
+
+func makeHTTPSPollActiveSessionsOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, sandbox string, timeout int) (httpsPollActiveSessionsOp, error) {
+	op := httpsPollActiveSessionsOp{}
+	op.name = "HTTPSPollActiveSessionsOp"
+	op.description = "Wait for user sessions to end"
+	op.hosts = hosts
+	op.sandbox = sandbox
+	op.timeout = timeout
+	op.useHTTPPassword = useHTTPPassword
+
+	err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+	if err != nil {
+		return op, err
+	}
+	op.userName = userName
+	op.httpsPassword = httpsPassword
+
+	return op, nil
+}
+
+func (op *httpsPollActiveSessionsOp) getPollingTimeout() int {
+	// a negative value indicates no timeout and should never be used for this op
+	return util.Max(op.timeout, 0)
+}
+
+func (op *httpsPollActiveSessionsOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.Timeout = defaultHTTPSRequestTimeoutSeconds
+		httpRequest.buildHTTPSEndpoint("sessions")
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsPollActiveSessionsOp) prepare(execContext *opEngineExecContext) error {
+	// select an up host in the sandbox as the initiator
+	initiator, err := getInitiatorInSandbox(op.sandbox, op.hosts, execContext.upHostsToSandboxes)
+	if err != nil {
+		return err
+	}
+	op.initiator = initiator
+	execContext.dispatcher.setup([]string{op.initiator})
+
+	return op.setupClusterHTTPRequest([]string{op.initiator})
+}
+
+func (op *httpsPollActiveSessionsOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsPollActiveSessionsOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+// The content of the response should look like
+/* "session_list": [
+	{
+	  "session_id": "v_test_db_node0001-12345:0x67",
+	  "node_name": "v_test_db_node0001",
+	  "user_name": "dbadmin",
+	  "is_internal": false
+	},
+	...
+  ]
+*/
+type sessionList struct {
+	SessionList []sessionInfo `json:"session_list"`
+}
+
+func (sessionList) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
+type sessionInfo struct {
+	SessionID  string `json:"session_id"`
+	NodeName   string `json:"node_name"`
+	UserName   string `json:"user_name"`
+	IsInternal bool   `json:"is_internal"`
+}
+
+// countUserSessions returns the number of the sessions that are opened by
+// the users, excluding the internal sessions of the database. If nodeNames
+// is not empty, only the sessions on those nodes are counted.
+func (sessions *sessionList) countUserSessions(nodeNames []string) int {
+	count := 0
+	for _, session := range sessions.SessionList {
+		if len(nodeNames) > 0 && !slices.Contains(nodeNames, session.NodeName) {
+			continue
+		}
+		if !session.IsInternal {
+			count++
+		}
+	}
+	return count
+}
+
+func (op *httpsPollActiveSessionsOp) processResult(execContext *opEngineExecContext) error {
+	err := pollState(op, execContext)
+	if errors.Is(err, errPollingTimeout) {
+		closedWhen := "the database stops"
+		if len(op.nodeNames) > 0 {
+			closedWhen = "the nodes are removed"
+		}
+		op.addWarning("", "%d user session(s) are still active after %d seconds, they will be closed when %s",
+			op.activeSessionCount, op.timeout, closedWhen)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fail to wait for user sessions to end, %w", err)
+	}
+
+	return nil
+}
+
+func (op *httpsPollActiveSessionsOp) shouldStopPolling() (bool, error) {
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isPasswordAndCertificateError(op.logger) {
+			return true, makeWrongCredentialError(op.name, host)
+		}
+
+		if result.isPassing() {
+			var sessions sessionList
+			err := op.parseAndValidateResponse(host, result.content, &sessions)
+			if err != nil {
+				return true, err
+			}
+
+			op.activeSessionCount = sessions.countUserSessions(op.nodeNames)
+			if op.activeSessionCount > 0 {
+				op.logger.PrintInfo("[%s] %d user session(s) are still active", op.name, op.activeSessionCount)
+				return false, nil
+			}
+
+			op.logger.PrintInfo("All user sessions have ended")
+			return true, nil
+		}
+	}
+
+	// this could happen if ResultCollection is empty
+	op.logger.PrintError("[%s] empty result received from the provided hosts %v", op.name, op.hosts)
+	return false, nil
+}
@@ -0,0 +1,83 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestPollActiveSessions(t *testing.T) {
+	op, err := makeHTTPSPollActiveSessionsOp([]string{"192.168.1.101"}, true, "dbadmin", new(string), "", 0)
+	assert.NoError(t, err)
+	op.setLogger(vlog.Printer{})
+
+	// the internal sessions do not block the drain
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {host: "192.168.1.101", statusCode: SuccessCode, content: `{"session_list": [
+			{"session_id": "s1", "node_name": "v_test_db_node0001", "user_name": "dbadmin", "is_internal": true},
+			{"session_id": "s2", "node_name": "v_test_db_node0001", "user_name": "user1", "is_internal": false}]}`},
+	}
+	stop, err := op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.False(t, stop)
+	assert.Equal(t, 1, op.activeSessionCount)
+
+	// reaching the timeout is a warning, so that the database still stops
+	execContext := makeOpEngineExecContext(context.Background(), vlog.Printer{})
+	err = op.processResult(&execContext)
+	assert.NoError(t, err)
+	assert.Len(t, op.getWarnings(), 1)
+
+	op.clusterHTTPRequest.ResultCollection["192.168.1.101"] = hostHTTPResult{host: "192.168.1.101",
+		statusCode: SuccessCode, content: `{"session_list": []}`}
+	stop, err = op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.True(t, stop)
+}
+
+func TestGracefulDrainInstructions(t *testing.T) {
+	options := VStopDatabaseOptionsFactory()
+	options.IsEon = true
+	options.GracefulDrain = true
+	options.DBName = "test_db"
+	options.UserName = "dbadmin"
+	options.SetDrainSeconds(30)
+	assert.NoError(t, options.validateEonOptions(vlog.Printer{}))
+	assert.True(t, options.isGracefulDrain())
+
+	drainOps, err := options.produceGracefulDrainOps(false)
+	assert.NoError(t, err)
+	assert.Len(t, drainOps, 2)
+	assert.Equal(t, "NMAManageConnectionsOp", drainOps[0].getName())
+	assert.Equal(t, "HTTPSPollActiveSessionsOp", drainOps[1].getName())
+
+	// the paused connections are resumed if a later op fails
+	compensation, err := drainOps[0].(compensatingOp).getCompensation()
+	assert.NoError(t, err)
+	resumeOp := compensation.(*nmaManageConnectionsOp)
+	assert.Equal(t, ActionResume, resumeOp.action)
+	assert.Equal(t, options.Hosts, resumeOp.hosts)
+
+	// a graceful drain without a drain time, or of an Enterprise database, is rejected
+	options.SetDrainSeconds(0)
+	assert.ErrorContains(t, options.validateEonOptions(vlog.Printer{}), "requires a positive drain time")
+	options.IsEon = false
+	assert.ErrorContains(t, options.validateEonOptions(vlog.Printer{}), "only available in Eon mode")
+}
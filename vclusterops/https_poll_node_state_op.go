@@ -187,7 +187,7 @@ func (op *httpsPollNodeStateOp) shouldStopPolling() (bool, error) {
 
 		// when we get timeout error, we know that the host is unreachable/dead
 		if result.isTimeout() {
-			return true, fmt.Errorf("[%s] cannot connect to host %s, please check if the host is still alive", op.name, host)
+			return true, errors.New(localize(MsgCannotConnectToHost, op.name, host))
 		}
 
 		// VER-88185 vcluster start_db - password related issues
@@ -202,14 +202,13 @@ func (op *httpsPollNodeStateOp) shouldStopPolling() (bool, error) {
 				return false, fmt.Errorf("[%s] wrong password/certificate for https service on host %s, but the nodes' startup have been in progress."+
 					"Please use vsql to check the nodes' status and manually run sync_catalog vsql command 'select sync_catalog()'", op.name, host)
 			case CreateDBCmd:
-				return true, fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
-					op.name, host)
+				return true, makeWrongCredentialError(op.name, host)
 			}
 		}
 		if result.isPassing() {
 			// parse the /nodes/{node} endpoint response
 			nodesInformation := nodesInfo{}
-			err := op.parseAndCheckResponse(host, result.content, &nodesInformation)
+			err := op.parseAndValidateResponse(host, result.content, &nodesInformation)
 			if err != nil {
 				op.logger.PrintError("[%s] fail to parse result on host %s, details: %s",
 					op.name, host, err)
@@ -256,24 +255,25 @@ func (op *httpsPollNodeStateOp) shouldStopPollingForDown() (bool, error) {
 
 		// when we get timeout error, we know that the host is unreachable/dead
 		if result.isTimeout() {
-			return true, fmt.Errorf("[%s] cannot connect to host %s, please check if the host is still alive", op.name, host)
+			return true, errors.New(localize(MsgCannotConnectToHost, op.name, host))
 		}
 
 		// We don't need to wait until timeout to determine if all nodes are down or not.
 		// If we find the wrong password for the HTTPS service on any hosts, we should fail immediately.
 		// We also need to let user know to wait until all nodes are down
 		if result.isPasswordAndCertificateError(op.logger) {
-			return true, fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
-				op.name, host)
+			return true, makeWrongCredentialError(op.name, host)
 		}
 		if !result.isPassing() {
 			allErrs = errors.Join(allErrs, result.err)
 		}
 		if result.isFailing() && !result.isHTTPRunning() {
 			downHosts[host] = true
+			op.markExpectedFailure(host)
 			continue
 		} else if result.isException() {
 			exceptionHosts[host] = true
+			op.markExpectedFailure(host)
 			continue
 		}
 
@@ -0,0 +1,150 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"golang.org/x/exp/slices"
+)
+
+type httpsPollReadOnlyOp struct {
+	opBase
+	opHTTPSBase
+	timeout  int
+	readOnly bool
+	// the UP hosts whose node has not reported the expected mode yet
+	pendingHosts []string
+}
+
+// makeHTTPSPollReadOnlyOp waits for the node of every UP host to report the
+// expected read-only mode
+func makeHTTPSPollReadOnlyOp(useHTTPPassword bool, userName string, httpsPassword *string,
+	readOnly bool, timeout int) (httpsPollReadOnlyOp, error) {
+	op := httpsPollReadOnlyOp{}
+	op.name = "HTTPSPollReadOnlyOp"
+	op.description = "Wait for the nodes to change read-only mode"
+	op.readOnly = readOnly
+	op.timeout = timeout
+	op.useHTTPPassword = useHTTPPassword
+
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+	return op, nil
+}
+
+func (op *httpsPollReadOnlyOp) getPollingTimeout() int {
+	// a negative value indicates no timeout and should never be used for this op
+	return util.Max(op.timeout, 0)
+}
+
+func (op *httpsPollReadOnlyOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.Timeout = defaultHTTPSRequestTimeoutSeconds
+		httpRequest.buildHTTPSEndpoint("node")
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsPollReadOnlyOp) prepare(execContext *opEngineExecContext) error {
+	upHosts, err := execContext.requireUpHosts(op.name)
+	if err != nil {
+		return err
+	}
+	op.hosts = upHosts
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsPollReadOnlyOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsPollReadOnlyOp) processResult(execContext *opEngineExecContext) error {
+	err := pollState(op, execContext)
+	if err != nil {
+		return fmt.Errorf("the nodes on hosts %v did not become %s, %w",
+			op.pendingHosts, readOnlyModeName(op.readOnly), err)
+	}
+
+	return nil
+}
+
+func (op *httpsPollReadOnlyOp) shouldStopPolling() (bool, error) {
+	op.pendingHosts = []string{}
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isPasswordAndCertificateError(op.logger) {
+			return true, makeWrongCredentialError(op.name, host)
+		}
+
+		if !result.isPassing() {
+			op.pendingHosts = append(op.pendingHosts, host)
+			continue
+		}
+
+		// the response has the state of the node on the host, see
+		// httpsGetLocalNodeStateOp for an example
+		resp := nodeStateResp{}
+		err := op.parseAndValidateResponse(host, result.content, &resp)
+		if err != nil {
+			return true, fmt.Errorf("[%s] fail to parse result on host %s, details: %w", op.name, host, err)
+		}
+		if len(resp.NodeStates) != 1 || resp.NodeStates[0].IsReadOnly != op.readOnly {
+			op.pendingHosts = append(op.pendingHosts, host)
+		}
+	}
+	slices.Sort(op.pendingHosts)
+
+	if len(op.pendingHosts) > 0 {
+		op.logger.Info("waiting for the nodes to change read-only mode", "hosts", op.pendingHosts)
+		return false, nil
+	}
+	op.logger.PrintInfo("All UP nodes are %s", readOnlyModeName(op.readOnly))
+	return true, nil
+}
+
+func (op *httpsPollReadOnlyOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func readOnlyModeName(readOnly bool) string {
+	if readOnly {
+		return "read-only"
+	}
+	return "read-write"
+}
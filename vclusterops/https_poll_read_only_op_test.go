@@ -0,0 +1,60 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollReadOnlyOp(t *testing.T) {
+	op, err := makeHTTPSPollReadOnlyOp(false, "", nil, true /*readOnly*/, 10)
+	assert.NoError(t, err)
+	op.setupBasicInfo()
+
+	nodeResult := func(host string, readOnly bool) hostHTTPResult {
+		content := fmt.Sprintf(`{"node_list": [{"name": "v_test_db_node0001", "address": %q, "state": "UP",
+			"database": "test_db", "is_readonly": %t}]}`, host, readOnly)
+		return hostHTTPResult{status: SUCCESS, statusCode: SuccessCode, host: host, content: content}
+	}
+
+	// one node is still read-write
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": nodeResult("192.168.1.101", true),
+		"192.168.1.102": nodeResult("192.168.1.102", false),
+	}
+	stop, err := op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.False(t, stop)
+	assert.Equal(t, []string{"192.168.1.102"}, op.pendingHosts)
+
+	// all nodes are read-only
+	op.clusterHTTPRequest.ResultCollection["192.168.1.102"] = nodeResult("192.168.1.102", true)
+	stop, err = op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.True(t, stop)
+	assert.Empty(t, op.pendingHosts)
+
+	// a failed request keeps the host pending
+	op.clusterHTTPRequest.ResultCollection["192.168.1.102"] = hostHTTPResult{status: FAILURE,
+		statusCode: InternalErrorCode, host: "192.168.1.102", err: fmt.Errorf("internal error")}
+	stop, err = op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.False(t, stop)
+	assert.Equal(t, []string{"192.168.1.102"}, op.pendingHosts)
+}
@@ -0,0 +1,181 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+const (
+	rebalanceJobRunning   = "RUNNING"
+	rebalanceJobCompleted = "COMPLETED"
+	rebalanceJobFailed    = "FAILED"
+)
+
+type httpsPollRebalanceJobOp struct {
+	opBase
+	opHTTPSBase
+	timeout int
+	// when set, the job is read once rather than polled until completion
+	pollOnce bool
+	jobID    *string
+	job      *RebalanceJob
+}
+
+// makeHTTPSPollRebalanceJobOp polls the rebalance job until it completes. The job ID
+// is read when the op is prepared, so that it can be set by a previous op.
+func makeHTTPSPollRebalanceJobOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, timeout int, jobID *string, job *RebalanceJob) (httpsPollRebalanceJobOp, error) {
+	op := httpsPollRebalanceJobOp{}
+	op.name = "HTTPSPollRebalanceJobOp"
+	op.description = "Wait for rebalance job to complete"
+	op.hosts = hosts
+	op.timeout = timeout
+	op.jobID = jobID
+	op.job = job
+
+	op.useHTTPPassword = useHTTPPassword
+	err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+	if err != nil {
+		return op, err
+	}
+	op.userName = userName
+	op.httpsPassword = httpsPassword
+	return op, nil
+}
+
+// readOnce reads the state of the job without waiting for it to complete
+func (op *httpsPollRebalanceJobOp) readOnce() {
+	op.pollOnce = true
+	op.description = "Get rebalance job progress"
+}
+
+func (op *httpsPollRebalanceJobOp) getPollingTimeout() int {
+	// a negative value indicates no timeout
+	return op.timeout
+}
+
+func (op *httpsPollRebalanceJobOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.Timeout = defaultHTTPSRequestTimeoutSeconds
+		httpRequest.buildHTTPSEndpoint("rebalance/jobs/" + *op.jobID)
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsPollRebalanceJobOp) prepare(execContext *opEngineExecContext) error {
+	if *op.jobID == "" {
+		return fmt.Errorf("[%s] the rebalance job ID is not set", op.name)
+	}
+	op.job.JobID = *op.jobID
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsPollRebalanceJobOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsPollRebalanceJobOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *httpsPollRebalanceJobOp) processResult(execContext *opEngineExecContext) error {
+	if op.pollOnce {
+		_, err := op.shouldStopPolling()
+		if err == nil && op.job.State == "" {
+			err = fmt.Errorf("[%s] no host returned the rebalance job %s", op.name, op.job.JobID)
+		}
+		return err
+	}
+
+	err := pollState(op, execContext)
+	if err != nil {
+		return fmt.Errorf("rebalance job %s did not complete, %w", op.job.JobID, err)
+	}
+	return nil
+}
+
+// rebalanceJobResponse is the response of the rebalance/jobs endpoint
+type rebalanceJobResponse struct {
+	JobID           string  `json:"job_id"`
+	SubclusterName  string  `json:"subcluster_name"`
+	State           string  `json:"state"`
+	ProgressPercent float64 `json:"progress_percent"`
+	ElapsedSeconds  float64 `json:"elapsed_seconds"`
+	Detail          string  `json:"detail"`
+}
+
+func (rebalanceJobResponse) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
+func (op *httpsPollRebalanceJobOp) shouldStopPolling() (bool, error) {
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isPasswordAndCertificateError(op.logger) {
+			return true, makeWrongCredentialError(op.name, host)
+		}
+		if !result.isPassing() {
+			continue
+		}
+
+		// example response:
+		// {"job_id": "45035996273705058", "subcluster_name": "sc1", "state": "RUNNING",
+		//  "progress_percent": 42.5, "elapsed_seconds": 85, "detail": ""}
+		resp := rebalanceJobResponse{}
+		err := op.parseAndValidateResponse(host, result.content, &resp)
+		if err != nil {
+			return true, fmt.Errorf("[%s] fail to parse result on host %s, details: %w", op.name, host, err)
+		}
+		if resp.JobID != op.job.JobID {
+			return true, fmt.Errorf("[%s] host %s returned rebalance job %s rather than %s",
+				op.name, host, resp.JobID, op.job.JobID)
+		}
+		op.job.update(&resp)
+
+		switch resp.State {
+		case rebalanceJobCompleted:
+			op.logger.PrintInfo("Rebalance of subcluster %s completed", resp.SubclusterName)
+			return true, nil
+		case rebalanceJobFailed:
+			return true, fmt.Errorf("rebalance of subcluster %s failed: %s", resp.SubclusterName, resp.Detail)
+		}
+		op.logger.PrintInfo("Rebalance of subcluster %s is %.1f%% done, about %d seconds remaining",
+			resp.SubclusterName, resp.ProgressPercent, op.job.ETASeconds)
+		return op.pollOnce, nil
+	}
+
+	// this could happen if no host responded
+	op.logger.PrintWarning("[%s] no host returned the rebalance job %s", op.name, op.job.JobID)
+	return false, nil
+}
@@ -180,20 +180,19 @@ func (op *httpsPollSubclusterNodeStateOp) shouldStopPolling() (bool, error) {
 
 		// when we get timeout error, we know that the host is unreachable/dead
 		if result.isTimeout() {
-			return true, fmt.Errorf("[%s] cannot connect to host %s, please check if the host is still alive", op.name, host)
+			return true, errors.New(localize(MsgCannotConnectToHost, op.name, host))
 		}
 
 		// We don't need to wait until timeout to determine if all nodes are up or not.
 		// If we find the wrong password for the HTTPS service on any hosts, we should fail immediately.
 		// We also need to let user know to wait until all nodes are up
 		if result.isPasswordAndCertificateError(op.logger) {
-			return true, fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
-				op.name, host)
+			return true, makeWrongCredentialError(op.name, host)
 		}
 		if result.isPassing() {
 			// parse the /nodes/{node} endpoint response
 			nodesInformation := nodesInfo{}
-			err := op.parseAndCheckResponse(host, result.content, &nodesInformation)
+			err := op.parseAndValidateResponse(host, result.content, &nodesInformation)
 			if err != nil {
 				return true, err
 			}
@@ -238,8 +237,7 @@ func (op *httpsPollSubclusterNodeStateOp) shouldStopPollingForDown() (bool, erro
 		// If we find the wrong password for the HTTPS service on any hosts, we should fail immediately.
 		// We also need to let user know to wait until all nodes are DOWN
 		if result.isPasswordAndCertificateError(op.logger) {
-			return true, fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
-				op.name, host)
+			return true, makeWrongCredentialError(op.name, host)
 		}
 		if !result.isPassing() {
 			allErrs = errors.Join(allErrs, result.err)
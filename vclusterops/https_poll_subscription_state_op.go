@@ -113,6 +113,10 @@ type subscriptionList struct {
 	SubscriptionList []subscriptionInfo `json:"subscription_list"`
 }
 
+func (subscriptionList) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 type subscriptionInfo struct {
 	Nodename          string `json:"node_name"`
 	ShardName         string `json:"shard_name"`
@@ -136,12 +140,11 @@ func (op *httpsPollSubscriptionStateOp) shouldStopPolling() (bool, error) {
 		op.logResponse(host, result)
 
 		if result.isPasswordAndCertificateError(op.logger) {
-			return true, fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
-				op.name, host)
+			return true, makeWrongCredentialError(op.name, host)
 		}
 
 		if result.isPassing() {
-			err := op.parseAndCheckResponse(host, result.content, &subscriptList)
+			err := op.parseAndValidateResponse(host, result.content, &subscriptList)
 			if err != nil {
 				op.logger.PrintError("[%s] fail to parse result on host %s, details: %s",
 					op.name, host, err)
@@ -0,0 +1,90 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+// httpsProbeOp finds the hosts whose HTTPS service is running. A host that
+// rejects the credentials is running, and a host that does not respond is
+// not. The op does not fail when the service is down on some hosts.
+type httpsProbeOp struct {
+	opBase
+	opHTTPSBase
+	// the hosts whose HTTPS service responded
+	runningHosts *[]string
+}
+
+func makeHTTPSProbeOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, runningHosts *[]string) (httpsProbeOp, error) {
+	op := httpsProbeOp{}
+	op.name = "HTTPSProbeOp"
+	op.description = "Check HTTPS service health"
+	op.hosts = hosts
+	op.runningHosts = runningHosts
+	err := op.validateAndSetUsernameAndPassword(op.name, useHTTPPassword, userName, httpsPassword)
+	return op, err
+}
+
+func (op *httpsProbeOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.Timeout = healthRequestTimeoutSeconds
+		httpRequest.buildHTTPSEndpoint("nodes")
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsProbeOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsProbeOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsProbeOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *httpsProbeOp) processResult(_ *opEngineExecContext) error {
+	*op.runningHosts = []string{}
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+		// the op only probes whether the service is running, so no result is a failure of the host
+		if !result.isPassing() {
+			op.markExpectedFailure(host)
+		}
+
+		if result.isHTTPRunning() {
+			*op.runningHosts = append(*op.runningHosts, host)
+		} else {
+			op.logger.Info("HTTPS service is not running", "Host", host)
+		}
+	}
+
+	return nil
+}
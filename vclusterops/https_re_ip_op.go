@@ -34,6 +34,10 @@ func (e *ReIPNoClusterQuorumError) Error() string {
 	return e.Detail
 }
 
+func (e *ReIPNoClusterQuorumError) Is(target error) bool {
+	return target == ErrQuorumLost
+}
+
 type httpsReIPOp struct {
 	opBase
 	opHTTPSBase
@@ -131,7 +135,7 @@ func (op *httpsReIPOp) prepare(execContext *opEngineExecContext) error {
 	// when there isn't any incoming hosts,
 	// use up hosts to execute the HTTP re-IP endpoint
 	if len(op.hosts) == 0 {
-		op.hosts = execContext.upHosts
+		op.hosts, _ = execContext.UpHosts()
 	}
 	execContext.dispatcher.setup(op.hosts)
 	return op.setupClusterHTTPRequest(op.nodeNamesToReIP)
@@ -151,8 +155,7 @@ func (op *httpsReIPOp) processResult(_ *opEngineExecContext) error {
 		op.logResponse(host, result)
 
 		if result.isUnauthorizedRequest() {
-			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
-				op.name, host)
+			return makeWrongCredentialError(op.name, host)
 		}
 
 		if !result.isPassing() {
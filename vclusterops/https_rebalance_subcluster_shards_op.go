@@ -28,6 +28,8 @@ type httpsRebalanceSubclusterShardsOp struct {
 	opBase
 	opHTTPSBase
 	scName string
+	// when set, the rebalance runs in the background and the ID of the rebalance job is saved here
+	jobID *string
 }
 
 // makeHTTPSRebalanceSubclusterShardsOp creates an op that calls vertica-http service to rebalance shards of a subcluster
@@ -51,11 +53,21 @@ func makeHTTPSRebalanceSubclusterShardsOp(bootstrapHost []string, useHTTPPasswor
 	return op, nil
 }
 
+// runAsync starts the rebalance without waiting for it to complete. The ID of
+// the rebalance job is saved in jobID, to poll the job with httpsPollRebalanceJobOp.
+func (op *httpsRebalanceSubclusterShardsOp) runAsync(jobID *string) {
+	op.jobID = jobID
+	op.description = "Start rebalance of subcluster shards"
+}
+
 func (op *httpsRebalanceSubclusterShardsOp) setupClusterHTTPRequest(hosts []string) error {
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = PostMethod
 		httpRequest.buildHTTPSEndpoint("subclusters/" + op.scName + "/rebalance")
+		if op.jobID != nil {
+			httpRequest.QueryParams = map[string]string{"async": "true"}
+		}
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -111,12 +123,25 @@ func (op *httpsRebalanceSubclusterShardsOp) processResult(_ *opEngineExecContext
 			  "detail": "REBALANCED SHARDS"
 			}
 		*/
+		// or, in async mode:
+		/*
+			{
+			  "job_id": "45035996273705058"
+			}
+		*/
 		resp, err := op.parseAndCheckMapResponse(host, result.content)
 		if err != nil {
 			err = fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
 			allErrs = errors.Join(allErrs, err)
 			return allErrs
 		}
+		if op.jobID != nil {
+			if resp["job_id"] == "" {
+				return errors.Join(allErrs, fmt.Errorf(`[%s] response from host %s has no rebalance job ID`, op.name, host))
+			}
+			*op.jobID = resp["job_id"]
+			return nil
+		}
 		// verify if the response's content is correct
 		if resp["detail"] != HTTPSSuccMsg {
 			err = fmt.Errorf(`[%s] response detail should be '%s' but got '%s'`, op.name, HTTPSSuccMsg, resp["detail"])
@@ -68,7 +68,7 @@ func (op *httpsReloadSpreadOp) setupClusterHTTPRequest(hosts []string) error {
 func (op *httpsReloadSpreadOp) prepare(execContext *opEngineExecContext) error {
 	// If the host input is an empty string, we find up hosts to update the host input
 	if len(op.hosts) == 0 {
-		op.hosts = execContext.upHosts
+		op.hosts, _ = execContext.UpHosts()
 	}
 	execContext.dispatcher.setup(op.hosts)
 
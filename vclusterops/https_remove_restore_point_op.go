@@ -0,0 +1,122 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+type httpsRemoveRestorePointOp struct {
+	opBase
+	opHTTPSBase
+	archiveName    string
+	restorePointID string
+}
+
+func makeHTTPSRemoveRestorePointOp(useHTTPPassword bool, userName string, httpsPassword *string,
+	archiveName, restorePointID string) (httpsRemoveRestorePointOp, error) {
+	op := httpsRemoveRestorePointOp{}
+	op.name = "HTTPSRemoveRestorePointOp"
+	op.description = "Remove restore point"
+	op.useHTTPPassword = useHTTPPassword
+	op.archiveName = archiveName
+	op.restorePointID = restorePointID
+
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+
+	return op, nil
+}
+
+func (op *httpsRemoveRestorePointOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = DeleteMethod
+		httpRequest.buildHTTPSEndpoint("archives/" + op.archiveName + "/restore-points/" + op.restorePointID)
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsRemoveRestorePointOp) prepare(execContext *opEngineExecContext) error {
+	// the restore point is removed for the whole database, so one up host is enough
+	upHosts, err := execContext.requireUpHosts(op.name)
+	if err != nil {
+		return err
+	}
+	op.hosts = upHosts[:1]
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsRemoveRestorePointOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsRemoveRestorePointOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return makeWrongCredentialError(op.name, host)
+		}
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// decode the json-format response
+		// The successful response object will be a dictionary:
+		/*
+			{
+				"detail": ""
+			}
+		*/
+		_, err := op.parseAndCheckMapResponse(host, result.content)
+		if err != nil {
+			return fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
+		}
+
+		return nil
+	}
+
+	return allErrs
+}
+
+func (op *httpsRemoveRestorePointOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+type httpsSetReadOnlyOp struct {
+	opBase
+	opHTTPSBase
+	readOnly bool
+}
+
+// makeHTTPSSetReadOnlyOp puts the database in read-only mode, or takes it out
+// of it. The request is sent to the first UP host, which applies it to the
+// whole cluster.
+func makeHTTPSSetReadOnlyOp(useHTTPPassword bool, userName string, httpsPassword *string,
+	readOnly bool) (httpsSetReadOnlyOp, error) {
+	op := httpsSetReadOnlyOp{}
+	op.name = "HTTPSSetReadOnlyOp"
+	op.description = "Set read-only mode"
+	op.readOnly = readOnly
+	op.useHTTPPassword = useHTTPPassword
+
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+	return op, nil
+}
+
+func (op *httpsSetReadOnlyOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PutMethod
+		httpRequest.buildHTTPSEndpoint("cluster/read-only")
+		httpRequest.QueryParams = map[string]string{"read_only": strconv.FormatBool(op.readOnly)}
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsSetReadOnlyOp) prepare(execContext *opEngineExecContext) error {
+	upHosts, err := execContext.requireUpHosts(op.name)
+	if err != nil {
+		return err
+	}
+	// use first up host to execute https put request
+	op.hosts = []string{upHosts[0]}
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsSetReadOnlyOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsSetReadOnlyOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// example response:
+		// {"detail": "Database is now read-only"}
+		_, err := op.parseAndCheckMapResponse(host, result.content)
+		if err != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] fail to parse result on host %s, details: %w",
+				op.name, host, err))
+		}
+	}
+
+	return allErrs
+}
+
+func (op *httpsSetReadOnlyOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+type httpsSetTLSConfigOp struct {
+	opBase
+	opHTTPSBase
+	tlsConfig TLSConfig
+}
+
+func makeHTTPSSetTLSConfigOp(useHTTPPassword bool, userName string, httpsPassword *string,
+	tlsConfig *TLSConfig) (httpsSetTLSConfigOp, error) {
+	op := httpsSetTLSConfigOp{}
+	op.name = "HTTPSSetTLSConfigOp"
+	op.description = "Set TLS configuration"
+	op.useHTTPPassword = useHTTPPassword
+	op.tlsConfig = *tlsConfig
+
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+
+	return op, nil
+}
+
+func (op *httpsSetTLSConfigOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PutMethod
+		httpRequest.buildHTTPSEndpoint("tls/" + string(op.tlsConfig.Name))
+		httpRequest.QueryParams = make(map[string]string)
+		if op.tlsConfig.Certificate != "" {
+			httpRequest.QueryParams["certificate"] = op.tlsConfig.Certificate
+		}
+		if len(op.tlsConfig.CACertificates) > 0 {
+			httpRequest.QueryParams["ca-certificates"] = strings.Join(op.tlsConfig.CACertificates, ",")
+		}
+		if op.tlsConfig.TLSMode != "" {
+			httpRequest.QueryParams["tls-mode"] = op.tlsConfig.TLSMode
+		}
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsSetTLSConfigOp) prepare(execContext *opEngineExecContext) error {
+	// the TLS configuration is in the catalog, so one up host is enough
+	upHosts, err := execContext.requireUpHosts(op.name)
+	if err != nil {
+		return err
+	}
+	op.hosts = upHosts[:1]
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsSetTLSConfigOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsSetTLSConfigOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return makeWrongCredentialError(op.name, host)
+		}
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// decode the json-format response
+		// The successful response object will be a dictionary:
+		/*
+			{
+				"detail": ""
+			}
+		*/
+		_, err := op.parseAndCheckMapResponse(host, result.content)
+		if err != nil {
+			return fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
+		}
+
+		return nil
+	}
+
+	return allErrs
+}
+
+func (op *httpsSetTLSConfigOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
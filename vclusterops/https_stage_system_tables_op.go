@@ -193,9 +193,10 @@ func (op *httpsStageSystemTablesOp) setupClusterHTTPRequest(hosts []string, sche
 }
 
 func (op *httpsStageSystemTablesOp) prepare(execContext *opEngineExecContext) error {
-	host := getInitiatorFromUpHosts(execContext.upHosts, op.hosts)
+	upHosts, _ := execContext.UpHosts()
+	host := getInitiatorFromUpHosts(upHosts, op.hosts)
 	if host == "" {
-		op.logger.PrintWarning("no up hosts among user specified hosts to collect system tables from, skipping the operation")
+		op.addWarning("", "no up hosts among user specified hosts to collect system tables from, skipping the operation")
 		op.skipExecute = true
 		return nil
 	}
@@ -229,7 +230,7 @@ func (op *httpsStageSystemTablesOp) execute(execContext *opEngineExecContext) er
 			// deterministic
 			if errors.Is(err, op.timeoutError) {
 				op.logger.Error(err, "Halting system table staging")
-				op.logger.PrintWarning("Timed out staging table %s.%s. Skipping remaining system tables.",
+				op.addWarning("", "Timed out staging table %s.%s. Skipping remaining system tables.",
 					systemTableInfo.Schema, systemTableInfo.TableName)
 				break
 			}
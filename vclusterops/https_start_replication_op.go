@@ -35,6 +35,13 @@ type httpsStartReplicationOp struct {
 	targetPassword     *string
 	tlsConfig          string
 	vdb                *VCoordinationDatabase
+	// objects to replicate, the whole database if none is set
+	tableOrSchemaName string
+	includePattern    string
+	excludePattern    string
+	async             bool
+	// filled once the replication is started or completed
+	status *ReplicationStatus
 }
 
 func makeHTTPSStartReplicationOp(dbName string, sourceHosts []string,
@@ -79,6 +86,10 @@ type replicateRequestData struct {
 	TargetUserName string  `json:"user,omitempty"`
 	TargetPassword *string `json:"password,omitempty"`
 	TLSConfig      string  `json:"tls_config,omitempty"`
+	// objects to replicate
+	TableOrSchemaName string `json:"table_or_schema_name,omitempty"`
+	IncludePattern    string `json:"include_pattern,omitempty"`
+	ExcludePattern    string `json:"exclude_pattern,omitempty"`
 }
 
 func (op *httpsStartReplicationOp) setupRequestBody(hosts []string) error {
@@ -91,6 +102,9 @@ func (op *httpsStartReplicationOp) setupRequestBody(hosts []string) error {
 		replicateData.TargetUserName = op.targetUserName
 		replicateData.TargetPassword = op.targetPassword
 		replicateData.TLSConfig = op.tlsConfig
+		replicateData.TableOrSchemaName = op.tableOrSchemaName
+		replicateData.IncludePattern = op.includePattern
+		replicateData.ExcludePattern = op.excludePattern
 
 		dataBytes, err := json.Marshal(replicateData)
 		if err != nil {
@@ -107,7 +121,11 @@ func (op *httpsStartReplicationOp) setupClusterHTTPRequest(hosts []string) error
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = PostMethod
-		httpRequest.buildHTTPSEndpoint("replicate/start")
+		if op.async {
+			httpRequest.buildHTTPSEndpoint("replicate/async")
+		} else {
+			httpRequest.buildHTTPSEndpoint("replicate/start")
+		}
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -157,6 +175,14 @@ func (op *httpsStartReplicationOp) processResult(_ *opEngineExecContext) error {
 			continue
 		}
 
+		if op.async {
+			err := op.processAsyncResult(host, result.content)
+			if err != nil {
+				allErrs = errors.Join(allErrs, err)
+			}
+			continue
+		}
+
 		// decode the json-format response
 		// The successful response object will be a dictionary as below:
 		// {"detail": "REPLICATE"}
@@ -172,12 +198,42 @@ func (op *httpsStartReplicationOp) processResult(_ *opEngineExecContext) error {
 		if startRepRsp["detail"] != startReplicationOpSuccMsg {
 			err = fmt.Errorf(`[%s] response detail should be '%s' but got '%s'`, op.name, startReplicationOpSuccMsg, startRepRsp["detail"])
 			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+		if op.status != nil {
+			op.status.Completed = true
 		}
 	}
 
 	return allErrs
 }
 
+type asyncReplicationResponse struct {
+	TransactionID int64 `json:"transaction_id"`
+}
+
+func (asyncReplicationResponse) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
+// processAsyncResult records the transaction ID of a started replication.
+// The successful response object will be a dictionary as below:
+// {"transaction_id": 45035996273704962}
+func (op *httpsStartReplicationOp) processAsyncResult(host, content string) error {
+	var asyncRsp asyncReplicationResponse
+	err := op.parseAndValidateResponse(host, content, &asyncRsp)
+	if err != nil {
+		return fmt.Errorf("[%s] fail to parse result on host %s, details: %w", op.name, host, err)
+	}
+	if asyncRsp.TransactionID == 0 {
+		return fmt.Errorf("[%s] response from host %s does not contain a transaction ID", op.name, host)
+	}
+	if op.status != nil {
+		op.status.TransactionID = asyncRsp.TransactionID
+	}
+	return nil
+}
+
 func (op *httpsStartReplicationOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
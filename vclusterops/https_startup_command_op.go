@@ -17,7 +17,6 @@ package vclusterops
 
 import (
 	"errors"
-	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 )
@@ -32,6 +31,9 @@ type httpsStartUpCommandOp struct {
 	vdb     *VCoordinationDatabase
 	cmdType CommandType
 	sandbox string
+	// startup commands saved by an earlier stop, used when no UP host can
+	// serve the startup commands
+	savedStartupCommands map[string][]string
 }
 
 func makeHTTPSStartUpCommandOp(useHTTPPassword bool, userName string, httpsPassword *string,
@@ -101,6 +103,39 @@ func makeHTTPSStartUpCommandWithSandboxOp(useHTTPPassword bool, userName string,
 	return op, nil
 }
 
+// Save the startup commands of the nodes before they are stopped. The commands are
+// retrieved from an UP primary node of the sandbox the nodes belong to.
+func makeHTTPSStartUpCommandOpBeforeStopNodes(useHTTPPassword bool, userName string, httpsPassword *string,
+	vdb *VCoordinationDatabase, sandbox string) (httpsStartUpCommandOp, error) {
+	op, err := makeHTTPSStartUpCommandWithSandboxOp(useHTTPPassword, userName, httpsPassword, vdb, sandbox)
+	op.description = "Save startup commands of the nodes"
+	op.cmdType = StopNodeCmd
+	return op, err
+}
+
+// Save the startup commands of the nodes in the target subcluster before it is stopped.
+// The commands are retrieved from an UP node of the subcluster, found by a previous op.
+func makeHTTPSStartUpCommandOpBeforeStopSC(useHTTPPassword bool, userName string,
+	httpsPassword *string) (httpsStartUpCommandOp, error) {
+	op := httpsStartUpCommandOp{}
+	op.name = startupOp
+	op.description = "Save startup commands of the nodes"
+	op.useHTTPPassword = useHTTPPassword
+	op.cmdType = StopSubclusterCmd
+
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+
+	return op, nil
+}
+
 func (op *httpsStartUpCommandOp) setupClusterHTTPRequest(hosts []string) error {
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
@@ -120,14 +155,19 @@ func (op *httpsStartUpCommandOp) setupClusterHTTPRequest(hosts []string) error {
 func (op *httpsStartUpCommandOp) prepare(execContext *opEngineExecContext) error {
 	// Use the /v1/startup/command endpoint for a primary Up host to view every start command of existing nodes
 	// With sandboxes in a cluster, we need to ensure that we pick a main cluster UP host
-	if op.cmdType == UnsandboxCmd {
+	switch op.cmdType {
+	case UnsandboxCmd:
 		for h, sb := range execContext.upHostsToSandboxes {
 			if sb == "" {
 				op.hosts = append(op.hosts, h)
 				break
 			}
 		}
-	} else {
+	case StopSubclusterCmd:
+		if len(execContext.nodesInfo) > 0 {
+			op.hosts = []string{execContext.nodesInfo[0].Address}
+		}
+	default:
 		var primaryUpHosts []string
 		for host, vnode := range op.vdb.HostNodeMap {
 			if vnode.IsPrimary && vnode.State == util.NodeUpState && vnode.Sandbox == op.sandbox {
@@ -137,6 +177,19 @@ func (op *httpsStartUpCommandOp) prepare(execContext *opEngineExecContext) error
 		}
 		op.hosts = primaryUpHosts
 	}
+	if len(op.hosts) == 0 {
+		if len(op.savedStartupCommands) > 0 {
+			op.logger.PrintInfo("[%s] No UP host can serve the startup commands, using the saved startup commands", op.name)
+			execContext.SetStartupCommands(op.savedStartupCommands)
+			op.skipExecute = true
+			return nil
+		}
+		// saving the startup commands before a stop is best effort
+		if op.cmdType == StopNodeCmd || op.cmdType == StopSubclusterCmd {
+			op.skipExecute = true
+			return nil
+		}
+	}
 	execContext.dispatcher.setup(op.hosts)
 
 	return op.setupClusterHTTPRequest(op.hosts)
@@ -150,18 +203,24 @@ func (op *httpsStartUpCommandOp) execute(execContext *opEngineExecContext) error
 	return op.processResult(execContext)
 }
 
+// httpsStartUpCommandResponse is the v1 response of the HTTPS startup/commands
+// endpoint, a map from node name to its startup command
+type httpsStartUpCommandResponse map[string][]string
+
+func (httpsStartUpCommandResponse) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 func (op *httpsStartUpCommandOp) processResult(execContext *opEngineExecContext) error {
 	var allErrs error
 	for host, result := range op.clusterHTTPRequest.ResultCollection {
 		op.logResponse(host, result)
 
 		if result.isUnauthorizedRequest() {
-			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
-				op.name, host)
+			return makeWrongCredentialError(op.name, host)
 		}
 
 		if result.isPassing() {
-			type HTTPStartUpCommandResponse map[string][]string
 			/* "v_practice_db_node0001": [
 				  "\/opt\/vertica\/bin\/vertica",
 				  "-D",
@@ -197,17 +256,22 @@ func (op *httpsStartUpCommandOp) processResult(execContext *opEngineExecContext)
 				  "ipv4"
 			    ],
 			*/
-			var responseObj HTTPStartUpCommandResponse
-			err := op.parseAndCheckResponse(host, result.content, &responseObj)
+			var responseObj httpsStartUpCommandResponse
+			err := op.parseAndValidateResponse(host, result.content, &responseObj)
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
 				continue
 			}
-			execContext.startupCommandMap = responseObj
+			execContext.SetStartupCommands(responseObj)
 			return nil
 		}
 		allErrs = errors.Join(allErrs, result.err)
 	}
+	if len(op.savedStartupCommands) > 0 {
+		op.logger.PrintInfo("[%s] Failed to retrieve the startup commands, using the saved startup commands. Details: %v",
+			op.name, allErrs)
+		execContext.SetStartupCommands(op.savedStartupCommands)
+	}
 	return nil
 }
 
@@ -80,7 +80,7 @@ func (op *httpsStopDBOp) prepare(execContext *opEngineExecContext) error {
 	// case 2: stop db on the main cluster -- send stop db request to on UP host of the main cluster.
 	// case 3: stop db on every host -- send stop db request to one UP host of the given sandbox and to one UP host of the main cluster.
 	if len(execContext.upHostsToSandboxes) == 0 {
-		return fmt.Errorf(`[%s] Cannot find any up hosts in OpEngineExecContext`, op.name)
+		return categorizeError(ErrNodeDown, fmt.Errorf(`[%s] Cannot find any up hosts in OpEngineExecContext`, op.name))
 	}
 	sandboxOnly := false
 	var mainHost string
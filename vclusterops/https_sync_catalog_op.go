@@ -93,11 +93,12 @@ func (op *httpsSyncCatalogOp) prepare(execContext *opEngineExecContext) error {
 			// use first up host in subcluster to execute https post request
 			op.hosts = []string{execContext.nodesInfo[0].Address}
 		} else {
-			if len(execContext.upHosts) == 0 {
-				return fmt.Errorf(`[%s] Cannot find any up hosts in OpEngineExecContext`, op.name)
+			upHosts, err := execContext.requireUpHosts(op.name)
+			if err != nil {
+				return err
 			}
 			// use first up host to execute https post request
-			op.hosts = []string{execContext.upHosts[0]}
+			op.hosts = []string{upHosts[0]}
 		}
 	}
 	execContext.dispatcher.setup(op.hosts)
@@ -111,7 +111,7 @@ func (op *httpsUpdateNodeStateOp) processResult(execContext *opEngineExecContext
 
 		// parse the /nodes/<host_ip> endpoint response
 		nodesInformation := nodesInfo{}
-		err := op.parseAndCheckResponse(host, result.content, &nodesInformation)
+		err := op.parseAndValidateResponse(host, result.content, &nodesInformation)
 		if err != nil {
 			return fmt.Errorf("[%s] fail to parse result on host %s: %w",
 				op.name, host, err)
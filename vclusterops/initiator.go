@@ -0,0 +1,250 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/slices"
+)
+
+// InitiatorStrategy selects the host that the ops needing a single bootstrap
+// host run on, e.g., the host that downloads the description file in
+// revive_db. The candidates are given in the order of the input hosts, and
+// the strategy must return one of them. The first candidate is selected when
+// the options do not set a strategy.
+type InitiatorStrategy interface {
+	SelectInitiator(ctx context.Context, candidates []string) (string, error)
+}
+
+// InitiatorStrategyFunc is a function that implements InitiatorStrategy
+type InitiatorStrategyFunc func(ctx context.Context, candidates []string) (string, error)
+
+func (f InitiatorStrategyFunc) SelectInitiator(ctx context.Context, candidates []string) (string, error) {
+	return f(ctx, candidates)
+}
+
+// FirstHostInitiator selects the first candidate
+type FirstHostInitiator struct{}
+
+func (FirstHostInitiator) SelectInitiator(_ context.Context, candidates []string) (string, error) {
+	return getInitiator(candidates), nil
+}
+
+// PinnedInitiator selects the given host, which must be one of the candidates
+type PinnedInitiator struct {
+	Host string
+}
+
+func (s PinnedInitiator) SelectInitiator(_ context.Context, candidates []string) (string, error) {
+	if !slices.Contains(candidates, s.Host) {
+		return "", fmt.Errorf("the pinned initiator %s is not among the hosts %v", s.Host, candidates)
+	}
+	return s.Host, nil
+}
+
+// RandomInitiator selects a random candidate, which spreads the load of the
+// bootstrap ops over the hosts
+type RandomInitiator struct{}
+
+func (RandomInitiator) SelectInitiator(_ context.Context, candidates []string) (string, error) {
+	return candidates[rand.Intn(len(candidates))], nil //nolint:gosec
+}
+
+// LowestLatencyInitiator probes the candidates concurrently, and selects the
+// one that responds first. The candidates that fail the probe are skipped,
+// so an unhealthy first host does not fail the command.
+type LowestLatencyInitiator struct {
+	// how long a probe can take, 5 seconds if not set
+	Timeout time.Duration
+	// checks that the host is reachable. It opens a TCP connection to the port
+	// of the node management agent if not set, which should be replaced when
+	// the hosts are reached through a proxy or a unix socket.
+	Probe func(ctx context.Context, host string) error
+}
+
+const defaultInitiatorProbeTimeout = 5 * time.Second
+
+func (s LowestLatencyInitiator) SelectInitiator(ctx context.Context, candidates []string) (string, error) {
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = defaultInitiatorProbeTimeout
+	}
+	probe := s.Probe
+	if probe == nil {
+		probe = probeNMAPort
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	latencies := make(map[string]time.Duration)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, host := range candidates {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			startTime := time.Now()
+			if err := probe(ctx, host); err != nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			latencies[host] = time.Since(startTime)
+		}(host)
+	}
+	wg.Wait()
+
+	var initiator string
+	for _, host := range candidates {
+		latency, ok := latencies[host]
+		if ok && (initiator == "" || latency < latencies[initiator]) {
+			initiator = host
+		}
+	}
+	if initiator == "" {
+		return "", categorizeError(ErrNMAUnreachable, fmt.Errorf("none of the hosts %v responded to the probe", candidates))
+	}
+	return initiator, nil
+}
+
+func probeNMAPort(ctx context.Context, host string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(nmaPort)))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// LatestCatalogInitiator selects the candidate with the latest catalog. A tie
+// is broken by the order of the candidates, and the first candidate is
+// selected if none of them has a known catalog version.
+type LatestCatalogInitiator struct {
+	// catalog version keyed by host
+	CatalogVersions map[string]int64
+}
+
+// NewLatestCatalogInitiator returns a LatestCatalogInitiator with the catalog
+// versions of the nodes of a report of VFetchNodeStateDetails
+func NewLatestCatalogInitiator(report *NodeStatesReport) LatestCatalogInitiator {
+	s := LatestCatalogInitiator{CatalogVersions: make(map[string]int64)}
+	for i := range report.Nodes {
+		s.CatalogVersions[report.Nodes[i].Address] = report.Nodes[i].CatalogVersion
+	}
+	return s
+}
+
+func (s LatestCatalogInitiator) SelectInitiator(_ context.Context, candidates []string) (string, error) {
+	initiator := getInitiator(candidates)
+	latestVersion := int64(-1)
+	for _, host := range candidates {
+		if version, ok := s.CatalogVersions[host]; ok && version > latestVersion {
+			initiator = host
+			latestVersion = version
+		}
+	}
+	return initiator, nil
+}
+
+// selectInitiator selects the initiator among the hosts with the strategy
+// of the options
+func (opt *DatabaseOptions) selectInitiator(ctx context.Context, hosts []string) (string, error) {
+	if len(hosts) == 0 {
+		return "", fmt.Errorf("no hosts to select the initiator from")
+	}
+	if opt.InitiatorStrategy == nil {
+		return getInitiator(hosts), nil
+	}
+	initiator, err := opt.InitiatorStrategy.SelectInitiator(ctx, hosts)
+	if err != nil {
+		return "", fmt.Errorf("fail to select the initiator: %w", err)
+	}
+	if !slices.Contains(hosts, initiator) {
+		return "", fmt.Errorf("the selected initiator %s is not among the hosts %v", initiator, hosts)
+	}
+	return initiator, nil
+}
+
+// initiatorFallbackOp is implemented by the ops that run on a single initiator,
+// and that can run on another host instead when the initiator is unreachable
+type initiatorFallbackOp interface {
+	clusterOp
+	setInitiator(initiator string)
+	getInitiator() string
+	// the hosts that the op can run on, in the order they are tried
+	getFallbackInitiators() []string
+}
+
+// runInstructionWithFallback runs the op, and runs it again on the next
+// candidate host while the op needs a single initiator that is unreachable.
+// The host that the op ended up running on is recorded in its OpReport.
+func (opEngine *VClusterOpEngine) runInstructionWithFallback(logger vlog.Printer, execContext *opEngineExecContext,
+	op clusterOp, findCertsInOptions bool) error {
+	err := opEngine.runInstruction(logger, execContext, op, findCertsInOptions)
+	fallbackOp, ok := op.(initiatorFallbackOp)
+	if !ok {
+		return err
+	}
+	tried := []string{fallbackOp.getInitiator()}
+	for err != nil && execContext.ctx.Err() == nil && isHostUnreachable(err, fallbackOp.getInitiator()) {
+		next := ""
+		for _, host := range fallbackOp.getFallbackInitiators() {
+			if !slices.Contains(tried, host) {
+				next = host
+				break
+			}
+		}
+		if next == "" {
+			return err
+		}
+		failedHost := fallbackOp.getInitiator()
+		message := fmt.Sprintf("the initiator %s is unreachable, the op runs on %s instead", failedHost, next)
+		logger.PrintWarning("[%s] %s", op.getName(), message)
+		opEngine.report.addWarnings([]OpWarning{{OpName: op.getName(), Host: failedHost, Message: message}})
+
+		fallbackOp.setInitiator(next)
+		tried = append(tried, next)
+		err = opEngine.runInstruction(logger, execContext, op, findCertsInOptions)
+	}
+	return err
+}
+
+// isHostUnreachable returns true if an op failed because the host did not respond
+func isHostUnreachable(err error, host string) bool {
+	if errors.Is(err, ErrNMAUnreachable) {
+		return true
+	}
+	var failures *hostFailuresError
+	if !errors.As(err, &failures) {
+		return false
+	}
+	for _, hostErr := range failures.hostErrors {
+		// no response was received from the host
+		if hostErr.Host == host && hostErr.StatusCode == 0 {
+			return true
+		}
+	}
+	return false
+}
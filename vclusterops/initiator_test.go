@@ -0,0 +1,202 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/slices"
+)
+
+func TestInitiatorStrategies(t *testing.T) {
+	ctx := context.Background()
+	hosts := []string{"192.168.1.101", "192.168.1.102", "192.168.1.103"}
+
+	initiator, err := FirstHostInitiator{}.SelectInitiator(ctx, hosts)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.101", initiator)
+
+	initiator, err = PinnedInitiator{Host: "192.168.1.102"}.SelectInitiator(ctx, hosts)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.102", initiator)
+	_, err = PinnedInitiator{Host: "192.168.1.104"}.SelectInitiator(ctx, hosts)
+	assert.ErrorContains(t, err, "the pinned initiator 192.168.1.104 is not among the hosts")
+
+	initiator, err = RandomInitiator{}.SelectInitiator(ctx, hosts)
+	assert.NoError(t, err)
+	assert.Contains(t, hosts, initiator)
+
+	report := NodeStatesReport{Nodes: []NodeStateDetails{
+		{Address: "192.168.1.101", CatalogVersion: 10},
+		{Address: "192.168.1.102", CatalogVersion: 12},
+		{Address: "192.168.1.103", CatalogVersion: 12},
+	}}
+	initiator, err = NewLatestCatalogInitiator(&report).SelectInitiator(ctx, hosts)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.102", initiator)
+	initiator, err = LatestCatalogInitiator{}.SelectInitiator(ctx, hosts)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.101", initiator)
+}
+
+func TestLowestLatencyInitiator(t *testing.T) {
+	ctx := context.Background()
+	hosts := []string{"192.168.1.101", "192.168.1.102", "192.168.1.103"}
+
+	// the first host is unhealthy and the second one is slow
+	strategy := LowestLatencyInitiator{Probe: func(_ context.Context, host string) error {
+		switch host {
+		case "192.168.1.101":
+			return errors.New("connection refused")
+		case "192.168.1.102":
+			time.Sleep(50 * time.Millisecond)
+		}
+		return nil
+	}}
+	initiator, err := strategy.SelectInitiator(ctx, hosts)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.103", initiator)
+
+	// the probes that do not complete in time are failed
+	strategy = LowestLatencyInitiator{Timeout: 10 * time.Millisecond, Probe: func(ctx context.Context, _ string) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	_, err = strategy.SelectInitiator(ctx, hosts)
+	assert.ErrorIs(t, err, ErrNMAUnreachable)
+}
+
+func TestSelectInitiator(t *testing.T) {
+	ctx := context.Background()
+	hosts := []string{"192.168.1.101", "192.168.1.102"}
+	opt := DatabaseOptionsFactory()
+
+	initiator, err := opt.selectInitiator(ctx, hosts)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.101", initiator)
+	_, err = opt.selectInitiator(ctx, nil)
+	assert.Error(t, err)
+
+	opt.InitiatorStrategy = PinnedInitiator{Host: "192.168.1.102"}
+	initiator, err = opt.selectInitiator(ctx, hosts)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.102", initiator)
+
+	// a strategy cannot select a host that is not a candidate
+	opt.InitiatorStrategy = InitiatorStrategyFunc(func(context.Context, []string) (string, error) {
+		return "192.168.1.104", nil
+	})
+	_, err = opt.selectInitiator(ctx, hosts)
+	assert.ErrorContains(t, err, "the selected initiator 192.168.1.104 is not among the hosts")
+
+	// the file is downloaded on the selected initiator
+	vdb := makeVCoordinationDatabase()
+	op, err := makeNMADownloadFileOp(hosts, "/communal/cluster_config.json", currConfigFileDestPath,
+		"/catalog", nil, &vdb)
+	assert.NoError(t, err)
+	op.setInitiator("192.168.1.102")
+	assert.Equal(t, []string{"192.168.1.102"}, op.hosts)
+	assert.Contains(t, op.hostRequestBodyMap["192.168.1.102"], "/communal/cluster_config.json")
+	assert.Len(t, op.hostRequestBodyMap, 1)
+	assert.Equal(t, hosts, op.newNodes)
+}
+
+type mockInitiatorOp struct {
+	mockOp
+	candidates  []string
+	unreachable []string
+	// status code of the responses of the reachable hosts
+	statusCode int
+}
+
+func (m *mockInitiatorOp) prepare(_ *opEngineExecContext) error {
+	return m.setupClusterHTTPRequest(m.hosts)
+}
+
+func (m *mockInitiatorOp) execute(_ *opEngineExecContext) error {
+	host := m.hosts[0]
+	if slices.Contains(m.unreachable, host) {
+		m.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+			host: {host: host, status: EXCEPTION, err: errors.New("connection refused")},
+		}
+		return errors.New("connection refused")
+	}
+	result := hostHTTPResult{host: host, status: SUCCESS, statusCode: m.statusCode}
+	if m.statusCode != SuccessCode {
+		result.status = FAILURE
+		result.err = errors.New("internal error")
+	}
+	m.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{host: result}
+	return result.err
+}
+
+func (m *mockInitiatorOp) setInitiator(initiator string) {
+	m.hosts = []string{initiator}
+}
+
+func (m *mockInitiatorOp) getInitiator() string {
+	return m.hosts[0]
+}
+
+func (m *mockInitiatorOp) getFallbackInitiators() []string {
+	return m.candidates
+}
+
+func TestInitiatorFallback(t *testing.T) {
+	hosts := []string{"192.168.1.101", "192.168.1.102", "192.168.1.103"}
+	makeOp := func(unreachable []string, statusCode int) mockInitiatorOp {
+		op := mockInitiatorOp{mockOp: makeMockOp(false), candidates: hosts, unreachable: unreachable, statusCode: statusCode}
+		op.name = "MockInitiatorOp"
+		op.hosts = []string{hosts[0]}
+		return op
+	}
+
+	// the op falls back to the first reachable host
+	op := makeOp([]string{"192.168.1.101", "192.168.1.102"}, SuccessCode)
+	options := DatabaseOptionsFactory()
+	opEngine := options.makeClusterOpEngine([]clusterOp{&op})
+	assert.NoError(t, opEngine.run(context.Background(), vlog.Printer{}))
+	report := options.GetOperationReport()
+	initiator, found := report.GetInitiator("MockInitiatorOp")
+	assert.True(t, found)
+	assert.Equal(t, "192.168.1.103", initiator)
+	assert.Len(t, report.Ops, 3)
+	assert.Len(t, report.Warnings, 2)
+	assert.Equal(t, "192.168.1.101", report.Warnings[0].Host)
+
+	// the op fails when no host is reachable
+	op = makeOp(hosts, SuccessCode)
+	options = DatabaseOptionsFactory()
+	opEngine = options.makeClusterOpEngine([]clusterOp{&op})
+	assert.ErrorContains(t, opEngine.run(context.Background(), vlog.Printer{}), "connection refused")
+	assert.Len(t, options.GetOperationReport().Ops, 3)
+
+	// the op does not fall back when the initiator responds with an error
+	op = makeOp(nil, InternalErrorCode)
+	options = DatabaseOptionsFactory()
+	opEngine = options.makeClusterOpEngine([]clusterOp{&op})
+	assert.Error(t, opEngine.run(context.Background(), vlog.Printer{}))
+	report = options.GetOperationReport()
+	assert.Len(t, report.Ops, 1)
+	initiator, _ = report.GetInitiator("MockInitiatorOp")
+	assert.Equal(t, "192.168.1.101", initiator)
+	assert.Empty(t, report.Warnings)
+}
@@ -18,7 +18,6 @@ package vclusterops
 import (
 	"fmt"
 
-	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
@@ -50,7 +49,7 @@ func (options *VInstallPackagesOptions) analyzeOptions() (err error) {
 	// we analyze hostnames when it is set in user input, otherwise we use hosts in yaml config
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -66,6 +65,9 @@ func (options *VInstallPackagesOptions) validateAnalyzeOptions(logger vlog.Print
 	return options.analyzeOptions()
 }
 
+// VInstallPackages installs the default packages in the database. It returns the
+// install status of each package. When some packages fail to install, the status
+// is returned along with an error that lists the failed packages.
 func (vcc VClusterCommands) VInstallPackages(options *VInstallPackagesOptions) (*InstallPackageStatus, error) {
 	/*
 	 *   - Produce Instructions
@@ -86,18 +88,21 @@ func (vcc VClusterCommands) VInstallPackages(options *VInstallPackagesOptions) (
 		return nil, fmt.Errorf("fail to production instructions: %w", err)
 	}
 
-	// Create a VClusterOpEngine. No need for certs since this operation doesn't
-	// talk to the NMA.
-	clusterOpEngine := makeClusterOpEngine(instructions, &httpsCerts{})
+	// Create a VClusterOpEngine, which takes the authentication, the policies
+	// and the report from the options
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		return nil, fmt.Errorf("fail to install packages: %w", runError)
 	}
 	if len(status.Packages) == 0 {
 		return nil, fmt.Errorf("did not flow back the install package status")
 	}
+	if failedPackages := status.FailedPackages(); len(failedPackages) > 0 {
+		return status, fmt.Errorf("fail to install packages %v", failedPackages)
+	}
 
 	return status, nil
 }
@@ -112,7 +117,7 @@ func (vcc VClusterCommands) VInstallPackages(options *VInstallPackagesOptions) (
 func (vcc *VClusterCommands) produceInstallPackagesInstructions(opts *VInstallPackagesOptions) ([]clusterOp, *InstallPackageStatus, error) {
 	// when password is specified, we will use username/password to call https endpoints
 	usePassword := false
-	if opts.Password != nil {
+	if opts.hasPassword() {
 		usePassword = true
 		err := opts.validateUserName(vcc.Log)
 		if err != nil {
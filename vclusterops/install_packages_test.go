@@ -0,0 +1,86 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testInstallPackagesResponse = `{"packages": [{"package_name": "ComplexTypes", "install_status": "success"}]}`
+
+// makeInstallPackagesTransport mocks the responses of an UP node of test_db
+// at 192.168.1.101, and records the Authorization header of the requests
+func makeInstallPackagesTransport(authHeaders *[]string) TransportWrapper {
+	return func(_ string, _ http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			*authHeaders = append(*authHeaders, req.Header.Get("Authorization"))
+			body := `{"node_list": [{"name": "v_test_db_node0001", "address": "192.168.1.101", "state": "UP",
+				"database": "test_db", "is_primary": true, "subcluster_name": "default_subcluster"}]}`
+			if strings.HasSuffix(req.URL.Path, "/packages") {
+				body = testInstallPackagesResponse
+			}
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{},
+				Body: io.NopCloser(strings.NewReader(body))}, nil
+		})
+	}
+}
+
+func TestInstallPackagesWithCredentialProvider(t *testing.T) {
+	var authHeaders []string
+	options := VInstallPackagesOptionsFactory()
+	options.DBName = "test_db"
+	options.RawHosts = []string{"192.168.1.101"}
+	options.UserName = "dbadmin"
+	options.CredentialProvider = CredentialProviderFunc(func(_ string) (string, error) {
+		return "provided-password", nil
+	})
+	options.WrapTransport = makeInstallPackagesTransport(&authHeaders)
+
+	status, err := VClusterCommands{}.VInstallPackages(&options)
+	assert.NoError(t, err)
+	assert.Len(t, status.Packages, 1)
+	// every request authenticates with the password of the provider
+	req := http.Request{Header: http.Header{}}
+	req.SetBasicAuth("dbadmin", "provided-password")
+	assert.Len(t, authHeaders, 2)
+	for _, header := range authHeaders {
+		assert.Equal(t, req.Header.Get("Authorization"), header)
+	}
+	// the engine records the report of the ops into the options
+	report := options.GetOperationReport()
+	assert.Equal(t, []string{"192.168.1.101"}, report.SucceededHosts())
+}
+
+func TestInstallPackagesWithBearerToken(t *testing.T) {
+	var authHeaders []string
+	options := VInstallPackagesOptionsFactory()
+	options.DBName = "test_db"
+	options.RawHosts = []string{"192.168.1.101"}
+	options.UserName = "dbadmin"
+	options.BearerToken = "test-token"
+	options.WrapTransport = makeInstallPackagesTransport(&authHeaders)
+
+	status, err := VClusterCommands{}.VInstallPackages(&options)
+	assert.NoError(t, err)
+	assert.Len(t, status.Packages, 1)
+	// every request carries the token
+	assert.Equal(t, []string{"Bearer test-token", "Bearer test-token"}, authHeaders)
+}
@@ -0,0 +1,165 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type VListConfigurationParametersOptions struct {
+	DatabaseOptions
+}
+
+func VListConfigurationParametersOptionsFactory() VListConfigurationParametersOptions {
+	opt := VListConfigurationParametersOptions{}
+	// set default values to the params
+	opt.setDefaultValues()
+
+	return opt
+}
+
+func (opt *VListConfigurationParametersOptions) validateParseOptions(logger vlog.Printer) error {
+	err := opt.validateBaseOptions(commandListConfigurationParams, logger)
+	if err != nil {
+		return err
+	}
+
+	// need to provide a password or key and certs
+	if opt.Password == nil && !opt.hasCertsInOptions() {
+		// validate key and cert files in local file system
+		_, err := getCertFilePaths()
+		if err != nil {
+			// in case that the key or cert files do not exist
+			return fmt.Errorf("must provide a password, key and certificates explicitly," +
+				" or key and certificate files in the default paths")
+		}
+	}
+	return nil
+}
+
+func (opt *VListConfigurationParametersOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(opt.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		opt.Hosts, err = opt.resolveRawHosts(opt.RawHosts)
+		if err != nil {
+			return err
+		}
+		opt.normalizePaths()
+	}
+	return nil
+}
+
+func (opt *VListConfigurationParametersOptions) validateAnalyzeOptions(log vlog.Printer) error {
+	if err := opt.validateParseOptions(log); err != nil {
+		return err
+	}
+	if err := opt.analyzeOptions(); err != nil {
+		return err
+	}
+	if err := opt.setUsePassword(log); err != nil {
+		return err
+	}
+	// username is always required when local db connection is made
+	return opt.validateUserName(log)
+}
+
+// VListConfigurationParameters lists the configuration parameters whose values
+// differ from their defaults, in the main cluster and in each sandbox that has
+// an UP node. The parameters are sorted by sandbox, name and node.
+func (vcc VClusterCommands) VListConfigurationParameters(
+	options *VListConfigurationParametersOptions) (configParameters []ConfigurationParameter, err error) {
+	// validate and analyze all options
+	err = options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return configParameters, err
+	}
+	err = options.runPreflightProbe(vcc)
+	if err != nil {
+		return configParameters, err
+	}
+
+	// produce list configuration parameters instructions
+	instructions, err := vcc.produceListConfigurationParametersInstructions(options)
+	if err != nil {
+		return configParameters, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	// Create a VClusterOpEngine, and add certs to the engine
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+
+	// Give the instructions to the VClusterOpEngine to run
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if runError != nil {
+		return configParameters, fmt.Errorf("fail to list configuration parameters: %w", runError)
+	}
+
+	configParameters = clusterOpEngine.execContext.configParameters
+	sortConfigurationParameters(configParameters)
+	return configParameters, nil
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful list configuration parameters action.
+//   - Check NMA connectivity
+//   - Check UP nodes and sandboxes info
+//   - List the non-default configuration parameters in the main cluster and each sandbox
+func (vcc VClusterCommands) produceListConfigurationParametersInstructions(
+	options *VListConfigurationParametersOptions) ([]clusterOp, error) {
+	var instructions []clusterOp
+
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password,
+		ListConfigurationParametersCmd)
+	if err != nil {
+		return instructions, err
+	}
+
+	nmaHealthOp := makeNMAHealthOp(options.Hosts)
+
+	password, err := options.getRequestDataPassword()
+	if err != nil {
+		return instructions, err
+	}
+	nmaListConfigOp, err := makeNMAListConfigurationParametersOp(options.Hosts,
+		options.UserName, options.DBName, password, options.usePassword)
+	if err != nil {
+		return instructions, err
+	}
+
+	instructions = append(instructions,
+		&nmaHealthOp,
+		&httpsGetUpNodesOp,
+		&nmaListConfigOp,
+	)
+
+	return instructions, nil
+}
+
+func sortConfigurationParameters(configParameters []ConfigurationParameter) {
+	sort.SliceStable(configParameters, func(i, j int) bool {
+		if configParameters[i].Sandbox != configParameters[j].Sandbox {
+			return configParameters[i].Sandbox < configParameters[j].Sandbox
+		}
+		if configParameters[i].ConfigParameter != configParameters[j].ConfigParameter {
+			return configParameters[i].ConfigParameter < configParameters[j].ConfigParameter
+		}
+		return configParameters[i].NodeName < configParameters[j].NodeName
+	})
+}
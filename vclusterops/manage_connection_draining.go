@@ -18,7 +18,6 @@ package vclusterops
 import (
 	"fmt"
 
-	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
@@ -47,6 +46,10 @@ type VManageConnectionDrainingOptions struct {
 
 	// the hostname to redirect client connections to, only used when action is redirect
 	RedirectHostname string
+
+	// if set, only the UP nodes of Sandbox, or of the main cluster when
+	// Sandbox is empty, can be picked to manage the connections
+	RestrictToSandbox bool
 }
 
 func VManageConnectionDrainingOptionsFactory() VManageConnectionDrainingOptions {
@@ -76,7 +79,7 @@ func (opt *VManageConnectionDrainingOptions) validateParseOptions(logger vlog.Pr
 	}
 
 	// need to provide a password or key and certs
-	if opt.Password == nil && (opt.Cert == "" || opt.Key == "") {
+	if opt.Password == nil && !opt.hasCertsInOptions() {
 		// validate key and cert files in local file system
 		_, err := getCertFilePaths()
 		if err != nil {
@@ -112,7 +115,7 @@ func (opt *VManageConnectionDrainingOptions) analyzeOptions() (err error) {
 	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
 	if len(opt.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		opt.Hosts, err = util.ResolveRawHostsToAddresses(opt.RawHosts, opt.IPv6)
+		opt.Hosts, err = opt.resolveRawHosts(opt.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -153,11 +156,10 @@ func (vcc VClusterCommands) VManageConnectionDraining(options *VManageConnection
 	}
 
 	// Create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to %v connections: %w", options.Action, runError)
 	}
@@ -183,10 +185,17 @@ func (vcc VClusterCommands) produceManageConnectionDrainingInstructions(
 	if err != nil {
 		return instructions, err
 	}
+	if options.RestrictToSandbox {
+		httpsGetUpNodesOp.restrictToSandbox(options.Sandbox)
+	}
 
+	password, err := options.getRequestDataPassword()
+	if err != nil {
+		return instructions, err
+	}
 	nmaManageConnectionsOp, err := makeNMAManageConnectionsOp(options.Hosts,
 		options.UserName, options.DBName, options.Sandbox, options.SCName,
-		options.RedirectHostname, options.Action, options.Password,
+		options.RedirectHostname, options.Action, password,
 		options.usePassword)
 	if err != nil {
 		return instructions, err
@@ -0,0 +1,201 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	DepotActionResize DepotAction = "resize"
+	DepotActionClear  DepotAction = "clear"
+	DepotActionWarm   DepotAction = "warm"
+)
+
+type DepotAction string
+
+type VManageDepotOptions struct {
+	/* part 1: basic db info */
+	DatabaseOptions
+
+	/* part 2: manage depot options */
+	// the depot action to be performed: resize, clear, or warm
+	Action DepotAction
+
+	// the nodes to run the action on, if empty all UP nodes of the main cluster are implied
+	NodeNames []string
+
+	// the new depot size, only used when action is resize. Two formats are
+	// supported: % and KMGT, e.g., 50% or 10G
+	DepotSize string
+
+	// the tables or projections to load into the depot, only used when action is warm
+	Objects []string
+}
+
+// DepotActionResult is the result of a depot action on a host
+type DepotActionResult struct {
+	Host      string `json:"host"`
+	NodeName  string `json:"node_name"`
+	Succeeded bool   `json:"succeeded"`
+	Message   string `json:"message"`
+}
+
+func VManageDepotOptionsFactory() VManageDepotOptions {
+	opt := VManageDepotOptions{}
+	// set default values to the params
+	opt.setDefaultValues()
+
+	return opt
+}
+
+func (opt *VManageDepotOptions) validateParseOptions(logger vlog.Printer) error {
+	if !opt.IsEon {
+		return fmt.Errorf("depot management is only supported in Eon mode")
+	}
+
+	err := opt.validateBaseOptions(commandManageDepot, logger)
+	if err != nil {
+		return err
+	}
+
+	return opt.validateExtraOptions()
+}
+
+func (opt *VManageDepotOptions) validateExtraOptions() error {
+	switch opt.Action {
+	case DepotActionResize:
+		if opt.DepotSize == "" {
+			return fmt.Errorf("must specify a depot size when depot action is %q", DepotActionResize)
+		}
+		validDepotSize, err := validateDepotSize(opt.DepotSize)
+		if !validDepotSize {
+			return err
+		}
+	case DepotActionClear:
+	case DepotActionWarm:
+		if len(opt.Objects) == 0 {
+			return fmt.Errorf("must specify the objects to load when depot action is %q", DepotActionWarm)
+		}
+	default:
+		return fmt.Errorf("depot action %q is invalid, must be one of %q, %q, or %q",
+			opt.Action, DepotActionResize, DepotActionClear, DepotActionWarm)
+	}
+	return nil
+}
+
+func (opt *VManageDepotOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(opt.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		opt.Hosts, err = opt.resolveRawHosts(opt.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (opt *VManageDepotOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := opt.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := opt.analyzeOptions(); err != nil {
+		return err
+	}
+	return opt.setUsePasswordAndValidateUsernameIfNeeded(logger)
+}
+
+// VManageDepot resizes, clears, or warms the depot of the selected nodes. It returns
+// the result of each node, including the failed ones, and an error if the action
+// failed on any node.
+func (vcc VClusterCommands) VManageDepot(options *VManageDepotOptions) ([]DepotActionResult, error) {
+	var results []DepotActionResult
+
+	// validate and analyze all options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return results, err
+	}
+
+	vdb := makeVCoordinationDatabase()
+	err = vcc.getVDBFromRunningDB(&vdb, &options.DatabaseOptions)
+	if err != nil {
+		return results, err
+	}
+
+	hosts, err := getDepotActionHosts(&vdb, options.NodeNames)
+	if err != nil {
+		return results, err
+	}
+
+	httpsManageDepotOp, err := makeHTTPSManageDepotOp(hosts, &vdb, options.Action, options.DepotSize,
+		options.Objects, options.usePassword, options.UserName, options.Password, &results)
+	if err != nil {
+		return results, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	clusterOpEngine := options.makeClusterOpEngine([]clusterOp{&httpsManageDepotOp})
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if runError != nil {
+		return results, fmt.Errorf("fail to %s the depot: %w", options.Action, runError)
+	}
+
+	return results, nil
+}
+
+// getDepotActionHosts returns the hosts of the nodes to run a depot action on. The
+// nodes must be UP nodes of the main cluster. If no nodes are given, all UP nodes
+// of the main cluster are returned.
+func getDepotActionHosts(vdb *VCoordinationDatabase, nodeNames []string) ([]string, error) {
+	var hosts []string
+	var missingNodes, notUpNodes []string
+	for _, h := range vdb.HostList {
+		vnode := vdb.HostNodeMap[h]
+		if vnode.Sandbox != util.MainClusterSandbox {
+			continue
+		}
+		if len(nodeNames) > 0 && !slices.Contains(nodeNames, vnode.Name) {
+			continue
+		}
+		if vnode.State != util.NodeUpState {
+			notUpNodes = append(notUpNodes, vnode.Name)
+			continue
+		}
+		hosts = append(hosts, h)
+	}
+
+	nodeNameToHost := vdb.genNodeNameToHostMap()
+	for _, name := range nodeNames {
+		if _, ok := nodeNameToHost[name]; !ok {
+			missingNodes = append(missingNodes, name)
+		}
+	}
+	if len(missingNodes) > 0 {
+		return nil, fmt.Errorf("nodes %v do not exist in the database", missingNodes)
+	}
+	if len(nodeNames) > 0 && len(notUpNodes) > 0 {
+		return nil, fmt.Errorf("the depot of nodes %v cannot be managed because they are not UP", notUpNodes)
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("no UP nodes in the main cluster to manage the depot of")
+	}
+	return hosts, nil
+}
@@ -0,0 +1,125 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+func TestValidateManageDepotOptions(t *testing.T) {
+	opt := VManageDepotOptionsFactory()
+	opt.Action = DepotActionResize
+	opt.DepotSize = "50%"
+	assert.NoError(t, opt.validateExtraOptions())
+
+	// negative: invalid depot size
+	opt.DepotSize = "200%"
+	assert.Error(t, opt.validateExtraOptions())
+	opt.DepotSize = ""
+	assert.ErrorContains(t, opt.validateExtraOptions(), "must specify a depot size")
+
+	// negative: no object to warm the depot with
+	opt.Action = DepotActionWarm
+	assert.ErrorContains(t, opt.validateExtraOptions(), "must specify the objects")
+	opt.Objects = []string{"public.t1"}
+	assert.NoError(t, opt.validateExtraOptions())
+
+	opt.Action = DepotActionClear
+	assert.NoError(t, opt.validateExtraOptions())
+
+	// negative: unknown action
+	opt.Action = "flush"
+	assert.ErrorContains(t, opt.validateExtraOptions(), `depot action "flush" is invalid`)
+}
+
+func makeDepotTestVDB() VCoordinationDatabase {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	addNode := func(host, name, state, sandbox string) {
+		vdb.HostList = append(vdb.HostList, host)
+		vdb.HostNodeMap[host] = &VCoordinationNode{Address: host, Name: name, State: state, Sandbox: sandbox}
+	}
+	addNode("192.168.1.101", "v_test_db_node0001", util.NodeUpState, "")
+	addNode("192.168.1.102", "v_test_db_node0002", util.NodeUpState, "")
+	addNode("192.168.1.103", "v_test_db_node0003", util.NodeDownState, "")
+	addNode("192.168.1.104", "v_test_db_node0004", util.NodeUpState, "sand1")
+	return vdb
+}
+
+func TestGetDepotActionHosts(t *testing.T) {
+	vdb := makeDepotTestVDB()
+
+	// all UP nodes of the main cluster
+	hosts, err := getDepotActionHosts(&vdb, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.102"}, hosts)
+
+	hosts, err = getDepotActionHosts(&vdb, []string{"v_test_db_node0002"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.102"}, hosts)
+
+	// negative: unknown or DOWN node
+	_, err = getDepotActionHosts(&vdb, []string{"v_test_db_node0005"})
+	assert.ErrorContains(t, err, "do not exist in the database")
+	_, err = getDepotActionHosts(&vdb, []string{"v_test_db_node0003"})
+	assert.ErrorContains(t, err, "not UP")
+}
+
+func TestManageDepotOp(t *testing.T) {
+	vdb := makeDepotTestVDB()
+	hosts := []string{"192.168.1.101", "192.168.1.102"}
+	var results []DepotActionResult
+
+	op, err := makeHTTPSManageDepotOp(hosts, &vdb, DepotActionResize, "10G", nil,
+		false, "", nil, &results)
+	assert.NoError(t, err)
+	op.setupBasicInfo()
+	assert.NoError(t, op.setupClusterHTTPRequest(hosts))
+	request := op.clusterHTTPRequest.RequestCollection["192.168.1.102"]
+	assert.Equal(t, PutMethod, request.Method)
+	assert.Contains(t, request.Endpoint, "nodes/v_test_db_node0002/depot")
+	assert.Equal(t, "10G", request.QueryParams["size"])
+
+	// every host gets a result, even if the action failed on some
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: SUCCESS, statusCode: SuccessCode, host: "192.168.1.101",
+			content: `{"detail": "Depot resized to 10G"}`},
+		"192.168.1.102": {status: FAILURE, statusCode: http.StatusBadRequest, host: "192.168.1.102",
+			err: fmt.Errorf("not enough disk space")},
+	}
+	err = op.processResult(nil)
+	assert.ErrorContains(t, err, "failed to resize the depot on hosts [192.168.1.102]")
+	assert.Equal(t, []DepotActionResult{
+		{Host: "192.168.1.101", NodeName: "v_test_db_node0001", Succeeded: true, Message: "Depot resized to 10G"},
+		{Host: "192.168.1.102", NodeName: "v_test_db_node0002", Message: "not enough disk space"},
+	}, results)
+
+	// warm the depot with a list of objects
+	op, err = makeHTTPSManageDepotOp(hosts, &vdb, DepotActionWarm, "", []string{"public.t1", "public.t2"},
+		false, "", nil, &results)
+	assert.NoError(t, err)
+	op.setupBasicInfo()
+	assert.NoError(t, op.setupClusterHTTPRequest(hosts))
+	request = op.clusterHTTPRequest.RequestCollection["192.168.1.101"]
+	assert.Equal(t, PostMethod, request.Method)
+	assert.Contains(t, request.Endpoint, "nodes/v_test_db_node0001/depot/warm")
+	assert.Equal(t, `{"objects":["public.t1","public.t2"]}`, request.RequestData)
+}
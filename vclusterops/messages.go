@@ -0,0 +1,120 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sync"
+)
+
+// MessageID identifies a user-facing message of vclusterops. The IDs are
+// stable, so a Localizer can use them as the keys of its translations.
+type MessageID string
+
+// IDs of the user-facing error and status messages
+const (
+	MsgClusterLeaseNotExpired        MessageID = "ClusterLeaseNotExpired"
+	MsgReviveDBNodeCountMismatch     MessageID = "ReviveDBNodeCountMismatch"
+	MsgRestorePointIDNotFound        MessageID = "RestorePointIDNotFound"
+	MsgRestorePointIndexNotFound     MessageID = "RestorePointIndexNotFound"
+	MsgRestorePointTimestampNotFound MessageID = "RestorePointTimestampNotFound"
+	MsgSubclusterNotSandboxed        MessageID = "SubclusterNotSandboxed"
+	MsgRemoveDefaultSubcluster       MessageID = "RemoveDefaultSubcluster"
+	MsgCannotConnectToHost           MessageID = "CannotConnectToHost"
+	MsgHTTPSServiceRunningOnHost     MessageID = "HTTPSServiceRunningOnHost"
+	MsgStopHTTPSBeforeCreateDB       MessageID = "StopHTTPSBeforeCreateDB"
+	MsgStopHTTPSBeforeDropDB         MessageID = "StopHTTPSBeforeDropDB"
+	MsgUseRestartNodeToReIP          MessageID = "UseRestartNodeToReIP"
+	MsgDatabaseStillRunningOnHost    MessageID = "DatabaseStillRunningOnHost"
+	MsgNMAUnreachable                MessageID = "NMAUnreachable"
+	MsgWrongCredential               MessageID = "WrongCredential"
+	MsgInsufficientPrivilege         MessageID = "InsufficientPrivilege"
+	MsgOpInProgress                  MessageID = "OpInProgress"
+	MsgOpFailed                      MessageID = "OpFailed"
+
+	// msgOpDescriptionPrefix is followed by the op name to build the ID of
+	// the description of an op, e.g., "OpDescription.NMAHealthOp"
+	msgOpDescriptionPrefix = "OpDescription."
+)
+
+// defaultMessages is the English message catalog. The templates use the
+// verbs of the fmt package and the arguments are always given in this order.
+var defaultMessages = map[MessageID]string{
+	MsgClusterLeaseNotExpired: "revive database cannot continue because the communal storage location might still be in use." +
+		" The cluster lease will expire at %s(UTC)." +
+		" Please ensure that the other cluster has stopped and try revive_db after the cluster lease expiration",
+	MsgReviveDBNodeCountMismatch: `[%s] nodes mismatch found on host %s: the number of the new nodes in --hosts is %d,` +
+		` but the number of the old nodes in description file is %d`,
+	MsgRestorePointIDNotFound:        "restore point with ID %s not found in archive %q",
+	MsgRestorePointIndexNotFound:     "restore point with index %d not found in archive %q",
+	MsgRestorePointTimestampNotFound: "no restore point created at or before %s found in archive %q",
+	MsgSubclusterNotSandboxed:        "cannot unsandbox a regular subcluster [%s]",
+	MsgRemoveDefaultSubcluster:       "cannot remove the default subcluster '%s'",
+	MsgCannotConnectToHost:           "[%s] cannot connect to host %s, please check if the host is still alive",
+	MsgHTTPSServiceRunningOnHost:     "[%s] Detected HTTPS service running on host %s",
+	MsgStopHTTPSBeforeCreateDB:       "%s, please stop the HTTPS service before creating a new database.",
+	MsgStopHTTPSBeforeDropDB:         "%s, please stop the HTTPS service before dropping the existing database.",
+	MsgUseRestartNodeToReIP:          "%s, please consider using restart_node to re-ip nodes for the running database.",
+	MsgDatabaseStillRunningOnHost:    " Database %s is still running on host %s",
+	MsgNMAUnreachable:                "the node management agent is not reachable on host %s",
+	MsgWrongCredential:               "[%s] wrong password/certificate for https service on host %s",
+	MsgInsufficientPrivilege:         "user %s does not have the %s privilege required by %s",
+	MsgOpInProgress:                  "in progress",
+	MsgOpFailed:                      "failed",
+}
+
+// Localizer translates the user-facing messages of vclusterops. Localize is
+// given the ID and the English template of a message, and returns the
+// template to use instead. The returned template must consume the same
+// arguments, in the same order, as the English one. Returning the English
+// template keeps the message untranslated.
+type Localizer interface {
+	Localize(id MessageID, template string) string
+}
+
+var (
+	localizerMutex sync.RWMutex
+	localizer      Localizer
+)
+
+// SetLocalizer sets the Localizer used for all of the user-facing messages.
+// Passing nil restores the English messages.
+func SetLocalizer(l Localizer) {
+	localizerMutex.Lock()
+	defer localizerMutex.Unlock()
+	localizer = l
+}
+
+// getMessageTemplate returns the template of the message after localization
+func getMessageTemplate(id MessageID, template string) string {
+	localizerMutex.RLock()
+	defer localizerMutex.RUnlock()
+	if localizer == nil {
+		return template
+	}
+	return localizer.Localize(id, template)
+}
+
+// localize formats the message with the given ID from the message catalog
+func localize(id MessageID, v ...any) string {
+	return fmt.Sprintf(getMessageTemplate(id, defaultMessages[id]), v...)
+}
+
+// localizeOpDescription returns the description of an op, as shown next to
+// its progress spinner
+func localizeOpDescription(opName, description string) string {
+	return getMessageTemplate(MessageID(msgOpDescriptionPrefix+opName), description)
+}
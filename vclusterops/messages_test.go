@@ -0,0 +1,46 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapLocalizer map[MessageID]string
+
+func (l mapLocalizer) Localize(id MessageID, template string) string {
+	if translated, ok := l[id]; ok {
+		return translated
+	}
+	return template
+}
+
+func TestLocalizer(t *testing.T) {
+	err := &SubclusterNotSandboxedError{SCName: "sc1"}
+	assert.Equal(t, "cannot unsandbox a regular subcluster [sc1]", err.Error())
+
+	SetLocalizer(mapLocalizer{
+		MsgSubclusterNotSandboxed:              "le sous-cluster [%s] n'est pas isolé",
+		msgOpDescriptionPrefix + "NMAHealthOp": "Vérifier l'état de NMA",
+	})
+	defer SetLocalizer(nil)
+	assert.Equal(t, "le sous-cluster [sc1] n'est pas isolé", err.Error())
+	assert.Equal(t, "Vérifier l'état de NMA", localizeOpDescription("NMAHealthOp", "Check NMA service health"))
+	// messages without a translation fall back to English
+	assert.Equal(t, "cannot remove the default subcluster 'sc1'", (&removeDefaultSubclusterError{Name: "sc1"}).Error())
+}
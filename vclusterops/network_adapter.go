@@ -15,9 +15,12 @@
 
 package vclusterops
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 type adapter interface {
-	sendRequest(*hostHTTPRequest, chan<- hostHTTPResult)
+	sendRequest(context.Context, *hostHTTPRequest, chan<- hostHTTPResult)
 	generateResult(*http.Response) hostHTTPResult
 }
@@ -60,7 +60,11 @@ func makeNMABootstrapCatalogOp(
 	// usually, only one node need bootstrap catalog
 	op.hosts = bootstrapHosts
 
-	err := op.setupRequestBody(vdb, options)
+	password, err := options.getRequestDataPassword()
+	if err != nil {
+		return op, err
+	}
+	err = op.setupRequestBody(vdb, options, *password)
 	if err != nil {
 		return op, err
 	}
@@ -68,7 +72,8 @@ func makeNMABootstrapCatalogOp(
 	return op, nil
 }
 
-func (op *nmaBootstrapCatalogOp) setupRequestBody(vdb *VCoordinationDatabase, options *VCreateDatabaseOptions) error {
+func (op *nmaBootstrapCatalogOp) setupRequestBody(vdb *VCoordinationDatabase, options *VCreateDatabaseOptions,
+	password string) error {
 	op.hostRequestBodyMap = make(map[string]bootstrapCatalogRequestData)
 
 	for _, host := range op.hosts {
@@ -104,7 +109,7 @@ func (op *nmaBootstrapCatalogOp) setupRequestBody(vdb *VCoordinationDatabase, op
 		bootstrapData.SpreadLoggingLevel = options.SpreadLoggingLevel
 		bootstrapData.Ipv6 = options.IPv6
 		bootstrapData.SuperuserName = options.UserName
-		bootstrapData.DBPassword = *options.Password
+		bootstrapData.DBPassword = password
 
 		// Flag to generate certs and tls configuration
 		bootstrapData.GenerateHTTPCerts = options.GenerateHTTPCerts
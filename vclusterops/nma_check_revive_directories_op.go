@@ -0,0 +1,211 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"golang.org/x/exp/maps"
+)
+
+// nmaCheckReviveDirectoriesOp checks the directories of the nodes before they are
+// prepared for a revive: the paths of a node must not be nested inside each other,
+// and the file systems must have enough free space for the catalog and the depot.
+type nmaCheckReviveDirectoriesOp struct {
+	opBase
+	hostNodeMap vHostNodeMap
+	// the space required by the catalog and the depot of a node, zero to only
+	// check that the paths are on a file system that the NMA can read
+	catalogBytes uint64
+	depotBytes   uint64
+	// host -> the paths to check
+	hostPaths          map[string][]string
+	hostRequestBodyMap map[string]string
+}
+
+func makeNMACheckReviveDirectoriesOp(hostNodeMap vHostNodeMap,
+	desc *DBDescription) (nmaCheckReviveDirectoriesOp, error) {
+	op := nmaCheckReviveDirectoriesOp{}
+	op.name = "NMACheckReviveDirectoriesOp"
+	op.description = "Check disk space and paths of database directories"
+	op.hostNodeMap = hostNodeMap
+	op.hosts = maps.Keys(hostNodeMap)
+	sort.Strings(op.hosts)
+	// the sizes are unknown when the revive resumes from a checkpoint
+	if desc != nil {
+		op.catalogBytes = desc.CatalogSizeBytes
+		op.depotBytes = desc.DepotSizeBytes
+	}
+
+	op.hostPaths = make(map[string][]string)
+	op.hostRequestBodyMap = make(map[string]string)
+	for host, vnode := range hostNodeMap {
+		paths := getNodeDirectories(vnode)
+		op.hostPaths[host] = paths
+		dataBytes, err := json.Marshal(diskUsageRequestData{Paths: paths})
+		if err != nil {
+			return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+		}
+		op.hostRequestBodyMap[host] = string(dataBytes)
+	}
+
+	return op, nil
+}
+
+// getNodeDirectories returns the distinct catalog, depot, and storage location paths of a node
+func getNodeDirectories(vnode *VCoordinationNode) []string {
+	var paths []string
+	for _, path := range append([]string{vnode.CatalogPath, vnode.DepotPath}, vnode.StorageLocations...) {
+		if path == "" {
+			continue
+		}
+		path = filepath.Clean(path)
+		if !util.StringInArray(path, paths) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// findNestedPaths returns a description of each pair of paths where one is inside the other
+func findNestedPaths(paths []string) []string {
+	var nested []string
+	for _, outer := range paths {
+		for _, inner := range paths {
+			if outer != inner && strings.HasPrefix(inner, strings.TrimSuffix(outer, "/")+"/") {
+				nested = append(nested, fmt.Sprintf("%s is inside %s", inner, outer))
+			}
+		}
+	}
+	return nested
+}
+
+func (op *nmaCheckReviveDirectoriesOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildNMAEndpoint("disk-usage")
+		httpRequest.RequestData = op.hostRequestBodyMap[host]
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaCheckReviveDirectoriesOp) prepare(execContext *opEngineExecContext) error {
+	// nested paths are found without any request, so fail fast on them
+	var report []string
+	for _, host := range op.hosts {
+		for _, nested := range findNestedPaths(op.hostPaths[host]) {
+			report = append(report, fmt.Sprintf("host %s: %s", host, nested))
+		}
+	}
+	if len(report) > 0 {
+		return fmt.Errorf("[%s] the database directories must not be nested inside each other:\n%s",
+			op.name, strings.Join(report, "\n"))
+	}
+
+	execContext.dispatcher.setup(op.hosts)
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaCheckReviveDirectoriesOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaCheckReviveDirectoriesOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaCheckReviveDirectoriesOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+	var report []string
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// the paths do not exist yet on a new host, so the NMA reports the
+		// file systems of their closest existing parent directories
+		resp := diskUsageResponse{}
+		err := op.parseAndValidateResponse(host, result.content, &resp)
+		if err != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] fail to parse result on host %s, details: %w",
+				op.name, host, err))
+			continue
+		}
+		report = append(report, op.checkDiskSpace(host, resp.DiskUsage)...)
+	}
+
+	if len(report) > 0 {
+		sort.Strings(report)
+		allErrs = errors.Join(allErrs, fmt.Errorf("[%s] not enough disk space to revive the database:\n%s",
+			op.name, strings.Join(report, "\n")))
+	}
+	return allErrs
+}
+
+// checkDiskSpace returns a description of each path of the host without enough free space.
+// The paths on the same file system share its free space.
+func (op *nmaCheckReviveDirectoriesOp) checkDiskSpace(host string, diskUsage []PathDiskUsage) []string {
+	vnode := op.hostNodeMap[host]
+	usageByPath := make(map[string]PathDiskUsage)
+	for _, usage := range diskUsage {
+		usageByPath[filepath.Clean(usage.Path)] = usage
+	}
+
+	// a file system is identified by its size and free space, which are reported the same for all of its paths
+	type fileSystem struct{ totalBytes, availableBytes uint64 }
+	requiredBytes := make(map[fileSystem]uint64)
+	fileSystemPaths := make(map[fileSystem][]string)
+	var report []string
+	for _, path := range op.hostPaths[host] {
+		usage, ok := usageByPath[path]
+		if !ok {
+			report = append(report, fmt.Sprintf("host %s: no disk usage of %s", host, path))
+			continue
+		}
+		fs := fileSystem{usage.TotalBytes, usage.AvailableBytes}
+		switch path {
+		case filepath.Clean(vnode.CatalogPath):
+			requiredBytes[fs] += op.catalogBytes
+		case filepath.Clean(vnode.DepotPath):
+			requiredBytes[fs] += op.depotBytes
+		}
+		fileSystemPaths[fs] = append(fileSystemPaths[fs], path)
+	}
+	for fs, required := range requiredBytes {
+		if required > fs.availableBytes {
+			report = append(report, fmt.Sprintf("host %s: %s need %d bytes but %d bytes are available",
+				host, strings.Join(fileSystemPaths[fs], ", "), required, fs.availableBytes))
+		}
+	}
+	return report
+}
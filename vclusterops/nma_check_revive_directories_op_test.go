@@ -0,0 +1,93 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeReviveDirectoriesTestOp(t *testing.T, catalogBytes, depotBytes uint64) nmaCheckReviveDirectoriesOp {
+	hostNodeMap := makeVHostNodeMap()
+	hostNodeMap["192.168.1.101"] = &VCoordinationNode{
+		Address:          "192.168.1.101",
+		CatalogPath:      "/data/test_db/v_test_db_node0001_catalog",
+		DepotPath:        "/depot/test_db/v_test_db_node0001_depot",
+		StorageLocations: []string{"/data/test_db/v_test_db_node0001_data"},
+	}
+	desc := DBDescription{CatalogSizeBytes: catalogBytes, DepotSizeBytes: depotBytes}
+	op, err := makeNMACheckReviveDirectoriesOp(hostNodeMap, &desc)
+	assert.NoError(t, err)
+	op.setupBasicInfo()
+	return op
+}
+
+func TestFindNestedPaths(t *testing.T) {
+	assert.Empty(t, findNestedPaths([]string{"/data/db/catalog", "/data/db/data", "/data/db/catalog2"}))
+	assert.Equal(t, []string{"/data/db/catalog/data is inside /data/db/catalog"},
+		findNestedPaths([]string{"/data/db/catalog", "/data/db/catalog/data"}))
+	assert.Equal(t, []string{"/data/depot is inside /"}, findNestedPaths([]string{"/", "/data/depot"}))
+
+	// the duplicated paths of a node are checked once
+	vnode := VCoordinationNode{CatalogPath: "/data/catalog/", DepotPath: "/data/depot",
+		StorageLocations: []string{"/data/depot", "/data/data"}}
+	assert.Equal(t, []string{"/data/catalog", "/data/depot", "/data/data"}, getNodeDirectories(&vnode))
+}
+
+func TestCheckReviveDirectoriesOp(t *testing.T) {
+	const host = "192.168.1.101"
+	const gb = 1 << 30
+	setResult := func(op *nmaCheckReviveDirectoriesOp, dataAvailable, depotAvailable string) {
+		op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+			host: {status: SUCCESS, statusCode: SuccessCode, host: host, content: `{"disk_usage": [
+				{"path": "/data/test_db/v_test_db_node0001_catalog", "total_bytes": 107374182400, "available_bytes": ` +
+				dataAvailable + `},
+				{"path": "/data/test_db/v_test_db_node0001_data", "total_bytes": 107374182400, "available_bytes": ` +
+				dataAvailable + `},
+				{"path": "/depot/test_db/v_test_db_node0001_depot", "total_bytes": 53687091200, "available_bytes": ` +
+				depotAvailable + `}]}`},
+		}
+	}
+
+	// enough space
+	op := makeReviveDirectoriesTestOp(t, 2*gb, 20*gb)
+	setResult(&op, "10737418240", "32212254720")
+	assert.NoError(t, op.processResult(nil))
+
+	// not enough space for the depot
+	op = makeReviveDirectoriesTestOp(t, 2*gb, 40*gb)
+	setResult(&op, "10737418240", "32212254720")
+	err := op.processResult(nil)
+	assert.ErrorContains(t, err, "not enough disk space to revive the database")
+	assert.ErrorContains(t, err, "host 192.168.1.101: /depot/test_db/v_test_db_node0001_depot need 42949672960 bytes")
+
+	// unknown sizes only require the disk usage of each path
+	op = makeReviveDirectoriesTestOp(t, 0, 0)
+	setResult(&op, "0", "0")
+	assert.NoError(t, op.processResult(nil))
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: SUCCESS, statusCode: SuccessCode, host: host, content: `{"disk_usage": []}`},
+	}
+	assert.ErrorContains(t, op.processResult(nil), "no disk usage of /data/test_db/v_test_db_node0001_catalog")
+
+	// negative: nested paths fail the op before any request
+	op.hostPaths[host] = append(op.hostPaths[host], "/data/test_db/v_test_db_node0001_catalog/depot")
+	execContext := makeOpEngineExecContext(context.Background(), op.logger)
+	err = op.prepare(&execContext)
+	assert.ErrorContains(t, err, "host 192.168.1.101: /data/test_db/v_test_db_node0001_catalog/depot is inside")
+}
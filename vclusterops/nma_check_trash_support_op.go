@@ -0,0 +1,83 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type nmaCheckTrashSupportOp struct {
+	opBase
+}
+
+// makeNMACheckTrashSupportOp checks that the NMA of each host can move the
+// directories of a force cleanup to a trash location. An NMA that does not know
+// the trash ignores the trash path and deletes the directories, so the check must
+// run before them.
+func makeNMACheckTrashSupportOp(hosts []string) nmaCheckTrashSupportOp {
+	op := nmaCheckTrashSupportOp{}
+	op.name = "NMACheckTrashSupportOp"
+	op.description = "Check that NMA can move directories to the trash"
+	op.hosts = hosts
+	return op
+}
+
+func (op *nmaCheckTrashSupportOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildNMAEndpoint("directories/trash")
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaCheckTrashSupportOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaCheckTrashSupportOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaCheckTrashSupportOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaCheckTrashSupportOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.statusCode == http.StatusNotFound {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] NMA on host %s does not support moving directories to a trash, "+
+				"upgrade it, or allow the directories to be deleted", op.name, host))
+		} else if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+		}
+	}
+
+	return allErrs
+}
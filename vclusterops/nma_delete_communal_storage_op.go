@@ -0,0 +1,101 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type nmaDeleteCommunalStorageOp struct {
+	opBase
+	requestBody string
+}
+
+type deleteCommunalStorageRequestData struct {
+	DBName           string            `json:"db_name"`
+	CommunalLocation string            `json:"communal_location"`
+	Parameters       map[string]string `json:"parameters,omitempty"`
+}
+
+// makeNMADeleteCommunalStorageOp deletes all objects under the communal storage
+// location of a database. The deletion is run from a single host, since the
+// communal storage is shared by all of them.
+func makeNMADeleteCommunalStorageOp(host, dbName, communalLocation string,
+	configurationParameters map[string]string) (nmaDeleteCommunalStorageOp, error) {
+	op := nmaDeleteCommunalStorageOp{}
+	op.name = "NMADeleteCommunalStorageOp"
+	op.description = "Delete communal storage"
+	op.hosts = []string{host}
+
+	requestData := deleteCommunalStorageRequestData{
+		DBName:           dbName,
+		CommunalLocation: communalLocation,
+		Parameters:       configurationParameters,
+	}
+	dataBytes, err := json.Marshal(requestData)
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.requestBody = string(dataBytes)
+
+	return op, nil
+}
+
+func (op *nmaDeleteCommunalStorageOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildNMAEndpoint("vertica/communal-storage/delete")
+		httpRequest.RequestData = op.requestBody
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaDeleteCommunalStorageOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaDeleteCommunalStorageOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaDeleteCommunalStorageOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaDeleteCommunalStorageOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+		}
+	}
+
+	return allErrs
+}
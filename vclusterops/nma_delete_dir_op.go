@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -55,6 +57,50 @@ func makeNMADeleteDirsSandboxOp(
 	return op, nil
 }
 
+// makeNMADeleteCreatedDirectoriesOp deletes the directories that a previous
+// op created, keyed by host
+func makeNMADeleteCreatedDirectoriesOp(hostDirectories map[string][]string) (nmaDeleteDirectoriesOp, error) {
+	op := nmaDeleteDirectoriesOp{}
+	op.name = delDirOpName
+	op.description = "Delete the directories created on Vertica hosts"
+	op.forceDelete = true
+	op.hostRequestBodyMap = make(map[string]string)
+	for host, directories := range hostDirectories {
+		p := deleteDirParams{}
+		// deleting a directory deletes the directories inside it
+		p.Directories = removeNestedPaths(directories)
+		p.ForceDelete = op.forceDelete
+		dataBytes, err := json.Marshal(p)
+		if err != nil {
+			return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail: %w", op.name, err)
+		}
+		op.hostRequestBodyMap[host] = string(dataBytes)
+		op.hosts = append(op.hosts, host)
+	}
+	slices.Sort(op.hosts)
+	return op, nil
+}
+
+// removeNestedPaths returns the sorted paths that are not inside another path
+func removeNestedPaths(paths []string) []string {
+	sorted := slices.Clone(paths)
+	slices.Sort(sorted)
+	var outermost []string
+	for _, path := range sorted {
+		nested := false
+		for _, outer := range outermost {
+			if len(findNestedPaths([]string{outer, path})) > 0 {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			outermost = append(outermost, path)
+		}
+	}
+	return outermost
+}
+
 func (op *nmaDeleteDirectoriesOp) buildRequestBody(
 	vdb *VCoordinationDatabase,
 	forceDelete bool,
@@ -87,14 +87,14 @@ func (op *nmaDownloadConfigOp) prepare(execContext *opEngineExecContext) error {
 			if nmaVDB.SpreadEncryption != "" {
 				hostsWithLatestCatalog = getPrimaryHostsWithLatestCatalog(&nmaVDB, execContext.hostsWithLatestCatalog, execContext)
 				if len(hostsWithLatestCatalog) == 0 {
-					return fmt.Errorf("could not find at least one primary host with the latest catalog")
+					return categorizeError(ErrCatalogMismatch, fmt.Errorf("could not find at least one primary host with the latest catalog"))
 				}
 			} else {
 				// If the host input is a nil value, we find the host with the latest catalog version to update the host input.
 				// Otherwise, we use the host input.
 				hostsWithLatestCatalog = execContext.hostsWithLatestCatalog
 				if len(hostsWithLatestCatalog) == 0 {
-					return fmt.Errorf("could not find at least one host with the latest catalog")
+					return categorizeError(ErrCatalogMismatch, fmt.Errorf("could not find at least one host with the latest catalog"))
 				}
 			}
 			hostWithLatestCatalog := hostsWithLatestCatalog[:1]
@@ -123,7 +123,7 @@ func (op *nmaDownloadConfigOp) prepare(execContext *opEngineExecContext) error {
 			}
 		}
 		if len(primaryUpHosts) == 0 {
-			return fmt.Errorf("could not find any primary up nodes")
+			return categorizeError(ErrNodeDown, fmt.Errorf("could not find any primary up nodes"))
 		}
 		op.hosts = primaryUpHosts
 	}
@@ -22,6 +22,9 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"golang.org/x/exp/slices"
 )
 
 type leaseCheckOption int
@@ -46,7 +49,9 @@ type nmaDownloadFileOp struct {
 	// vdb will be used to save downloaded file info for revive_db
 	vdb *VCoordinationDatabase
 	// newNodes is used to verify node number in http response for revive_db
-	newNodes           []string
+	newNodes []string
+	// whether newNodes may be fewer than the nodes of the database, for a partial revive
+	partialRevive      bool
 	displayOnly        bool
 	ignoreClusterLease bool
 	forRevive          bool
@@ -66,13 +71,11 @@ type downloadFileRequestData struct {
 // communal storage location when there is an active cluster lease on it.
 type ClusterLeaseNotExpiredError struct {
 	Expiration string
+	Hints      ErrorHints
 }
 
 func (e *ClusterLeaseNotExpiredError) Error() string {
-	return fmt.Sprintf("revive database cannot continue because the communal storage location might still be in use."+
-		" The cluster lease will expire at %s(UTC)."+
-		" Please ensure that the other cluster has stopped and try revive_db after the cluster lease expiration",
-		e.Expiration)
+	return localize(MsgClusterLeaseNotExpired, e.Expiration)
 }
 
 // ReviveDBNodeCountMismatchError is the error that is returned when the number of
@@ -85,9 +88,11 @@ type ReviveDBNodeCountMismatchError struct {
 }
 
 func (e *ReviveDBNodeCountMismatchError) Error() string {
-	return fmt.Sprintf(`[%s] nodes mismatch found on host %s: the number of the new nodes in --hosts is %d,`+
-		` but the number of the old nodes in description file is %d`,
-		e.ReviveDBStep, e.FailureHost, e.NumOfNewNodes, e.NumOfOldNodes)
+	return localize(MsgReviveDBNodeCountMismatch, e.ReviveDBStep, e.FailureHost, e.NumOfNewNodes, e.NumOfOldNodes)
+}
+
+func (e *ReviveDBNodeCountMismatchError) Is(target error) bool {
+	return target == ErrCatalogMismatch
 }
 
 func makeNMADownloadFileOp(newNodes []string, sourceFilePath, destinationFilePath, catalogPath string,
@@ -120,6 +125,22 @@ func makeNMADownloadFileOp(newNodes []string, sourceFilePath, destinationFilePat
 	return op, nil
 }
 
+// setInitiator replaces the host that downloads the file
+func (op *nmaDownloadFileOp) setInitiator(initiator string) {
+	requestBody := op.hostRequestBodyMap[op.hosts[0]]
+	op.hosts = []string{initiator}
+	op.hostRequestBodyMap = map[string]string{initiator: requestBody}
+}
+
+func (op *nmaDownloadFileOp) getInitiator() string {
+	return op.hosts[0]
+}
+
+// any of the new nodes can download the file
+func (op *nmaDownloadFileOp) getFallbackInitiators() []string {
+	return op.newNodes
+}
+
 func makeNMADownloadFileOpForRevive(newNodes []string, sourceFilePath, destinationFilePath, catalogPath string,
 	configurationParameters map[string]string, vdb *VCoordinationDatabase, displayOnly, ignoreClusterLease bool) (nmaDownloadFileOp, error) {
 	op, err := makeNMADownloadFileOp(newNodes, sourceFilePath, destinationFilePath,
@@ -195,18 +216,41 @@ type downloadResponse struct {
 	FileContent string `json:"file_content"`
 }
 
+func (downloadResponse) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
 type fileContent struct {
 	ClusterLeaseExpiration string `json:"ClusterLeaseExpiration"`
-	NodeList               []struct {
-		Name        string `json:"name"`
-		Address     string `json:"address"`
-		CatalogPath string `json:"catalogPath"`
-		IsPrimary   bool   `json:"isPrimary"`
+	Database               struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"Database"`
+	ShardList []struct {
+		Name string `json:"name"`
+	} `json:"Shard"`
+	NodeList []struct {
+		Name          string `json:"name"`
+		Address       string `json:"address"`
+		CatalogPath   string `json:"catalogPath"`
+		IsPrimary     bool   `json:"isPrimary"`
+		SubclusterOid uint64 `json:"subclusterOid"`
+		// size of the catalog of the node in bytes, zero if unknown
+		CatalogSize uint64 `json:"catalogSize"`
 	} `json:"Node"`
+	SubclusterList []struct {
+		Name      string `json:"name"`
+		Oid       uint64 `json:"oid"`
+		IsPrimary bool   `json:"isPrimary"`
+		// empty for a subcluster of the main cluster
+		Sandbox string `json:"sandbox"`
+	} `json:"Subcluster"`
 	StorageLocations []struct {
 		Name  string `json:"name"`
 		Path  string `json:"path"`
 		Usage int    `json:"usage"`
+		// maximum size of the location in bytes, zero if unknown
+		Size uint64 `json:"size"`
 	} `json:"StorageLocation"`
 }
 
@@ -218,7 +262,7 @@ func (op *nmaDownloadFileOp) processResult(execContext *opEngineExecContext) err
 
 		if result.isPassing() {
 			response := downloadResponse{}
-			err := op.parseAndCheckResponse(host, result.content, &response)
+			err := op.parseAndValidateResponse(host, result.content, &response)
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
 				break
@@ -232,19 +276,25 @@ func (op *nmaDownloadFileOp) processResult(execContext *opEngineExecContext) err
 				break
 			}
 
+			// file content in the response is a string, we need to unmarshal it again
+			descFileContent := fileContent{}
+			err = op.parseAndCheckResponse(host, response.FileContent, &descFileContent)
+
 			// for --display-only, we only need the file content
 			if op.displayOnly && op.forRevive {
 				execContext.dbInfo = response.FileContent
+				if err == nil {
+					execContext.dbDescription = makeDBDescription(&descFileContent)
+				}
 				return nil
 			}
 
-			// file content in the response is a string, we need to unmarshal it again
-			descFileContent := fileContent{}
-			err = op.parseAndCheckResponse(host, response.FileContent, &descFileContent)
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
 				break
 			}
+			execContext.dbDescription = makeDBDescription(&descFileContent)
+			execContext.clusterLeaseExpiration = descFileContent.ClusterLeaseExpiration
 
 			if op.forRevive {
 				if op.leaseCheckOption != skipLeaseCheck {
@@ -259,7 +309,11 @@ func (op *nmaDownloadFileOp) processResult(execContext *opEngineExecContext) err
 					return nil
 				}
 
-				if len(descFileContent.NodeList) != len(op.newNodes) {
+				nodeCountMismatch := len(descFileContent.NodeList) != len(op.newNodes)
+				if op.partialRevive {
+					nodeCountMismatch = len(descFileContent.NodeList) < len(op.newNodes)
+				}
+				if nodeCountMismatch {
 					err := &ReviveDBNodeCountMismatchError{
 						ReviveDBStep:  op.name,
 						FailureHost:   host,
@@ -282,6 +336,58 @@ func (op *nmaDownloadFileOp) processResult(execContext *opEngineExecContext) err
 	return appendHTTPSFailureError(allErrs)
 }
 
+// replicaShardName is the name of the shard that every node subscribes to,
+// which is not counted as a segment shard of the database
+const replicaShardName = "replica"
+
+// makeDBDescription converts the content of the description file to
+// the typed database description returned to the callers
+func makeDBDescription(descFileContent *fileContent) *DBDescription {
+	desc := DBDescription{
+		Name:                   descFileContent.Database.Name,
+		Version:                descFileContent.Database.Version,
+		ClusterLeaseExpiration: descFileContent.ClusterLeaseExpiration,
+	}
+	for _, shard := range descFileContent.ShardList {
+		if shard.Name != replicaShardName {
+			desc.ShardCount++
+		}
+	}
+	subclusters := make(map[uint64]*DBDescriptionSubcluster)
+	for _, sc := range descFileContent.SubclusterList {
+		desc.Subclusters = append(desc.Subclusters, DBDescriptionSubcluster{
+			Name:      sc.Name,
+			IsPrimary: sc.IsPrimary,
+			Sandbox:   sc.Sandbox,
+		})
+		subclusters[sc.Oid] = &desc.Subclusters[len(desc.Subclusters)-1]
+		if sc.Sandbox != "" && !slices.Contains(desc.Sandboxes, sc.Sandbox) {
+			desc.Sandboxes = append(desc.Sandboxes, sc.Sandbox)
+		}
+	}
+	for _, node := range descFileContent.NodeList {
+		descNode := DBDescriptionNode{
+			Name:        node.Name,
+			Address:     node.Address,
+			CatalogPath: node.CatalogPath,
+			IsPrimary:   node.IsPrimary,
+		}
+		if sc, ok := subclusters[node.SubclusterOid]; ok {
+			descNode.Subcluster = sc.Name
+			descNode.Sandbox = sc.Sandbox
+		}
+		desc.Nodes = append(desc.Nodes, descNode)
+		desc.CatalogSizeBytes = util.Max(desc.CatalogSizeBytes, node.CatalogSize)
+	}
+	for _, location := range descFileContent.StorageLocations {
+		desc.StorageLocations = append(desc.StorageLocations, location.Path)
+		if location.Usage == depotStorageType {
+			desc.DepotSizeBytes = util.Max(desc.DepotSizeBytes, location.Size)
+		}
+	}
+	return &desc
+}
+
 // buildVDBFromClusterConfig can build a vdb using cluster_config.json
 func (op *nmaDownloadFileOp) buildVDBFromClusterConfig(descFileContent fileContent) error {
 	op.vdb.HostNodeMap = makeVHostNodeMap()
@@ -328,7 +434,7 @@ func (op *nmaDownloadFileOp) buildVDBFromClusterConfig(descFileContent fileConte
 
 func (op *nmaDownloadFileOp) clusterLeaseCheck(clusterLeaseExpiration string) error {
 	if op.ignoreClusterLease {
-		op.logger.PrintWarning("Skipping cluster lease check")
+		op.addWarning("", "Skipping cluster lease check")
 		return nil
 	}
 
@@ -341,7 +447,7 @@ func (op *nmaDownloadFileOp) clusterLeaseCheck(clusterLeaseExpiration string) er
 
 	// current time < expire time, it means that the cluster lease is not expired
 	if utcNow.Before(utcExpiration) {
-		return &ClusterLeaseNotExpiredError{Expiration: clusterLeaseExpiration}
+		return makeClusterLeaseNotExpiredError(clusterLeaseExpiration)
 	}
 
 	op.logger.PrintInfo("Cluster lease check has passed. We proceed to revive the database")
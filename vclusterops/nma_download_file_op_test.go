@@ -16,7 +16,9 @@
 package vclusterops
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -37,9 +39,80 @@ func TestClusteLeaseExpiryError(t *testing.T) {
 	ok := errors.As(err, &clusterLeaseErr)
 	assert.True(t, ok)
 	assert.Contains(t, err.Error(), "The cluster lease will expire at")
+	// the error tells the user which option can skip the check
+	hints, found := GetErrorHints(fmt.Errorf("revive failed: %w", err))
+	assert.True(t, found)
+	assert.Equal(t, "IgnoreClusterLease", hints.OptionName)
 
 	// Success case
 	fakeLeaseTime = time.Now().UTC().Add(-time.Minute * time.Duration(5))
 	err = op.clusterLeaseCheck(fakeLeaseTime.Format(expirationStringLayout))
 	assert.NoError(t, err)
 }
+
+func TestMakeClusterLease(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	vdb.HostNodeMap["192.168.1.102"] = &VCoordinationNode{Name: "v_test_db_node0002", Address: "192.168.1.102"}
+	vdb.HostNodeMap["192.168.1.101"] = &VCoordinationNode{Name: "v_test_db_node0001", Address: "192.168.1.101"}
+	utcNow := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+	// the lease has not expired
+	lease, err := makeClusterLease("2024-03-04 08:05:00.5", &vdb, utcNow)
+	assert.NoError(t, err)
+	assert.False(t, lease.IsExpired)
+	assert.Equal(t, int64(300), lease.RemainingSeconds)
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.102"}, lease.OwnerHosts)
+
+	// the lease has expired
+	lease, err = makeClusterLease("2024-03-04 07:55:00", &vdb, utcNow)
+	assert.NoError(t, err)
+	assert.True(t, lease.IsExpired)
+	assert.Zero(t, lease.RemainingSeconds)
+
+	_, err = makeClusterLease("not a time", &vdb, utcNow)
+	assert.ErrorContains(t, err, "fail to convert cluster-lease-expiration string to a time")
+}
+
+func TestMakeDBDescription(t *testing.T) {
+	const descFile = `{"ClusterLeaseExpiration": "2024-03-04 08:05:00",
+		"Database": {"name": "test_db", "version": "v24.2.0"},
+		"Shard": [{"name": "replica"}, {"name": "segment0001"}, {"name": "segment0002"}],
+		"Node": [{"name": "v_test_db_node0001", "address": "192.168.1.101",
+			"catalogPath": "/data/test_db/v_test_db_node0001_catalog/Catalog", "isPrimary": true,
+			"subclusterOid": 45035996273704988, "catalogSize": 1073741824},
+			{"name": "v_test_db_node0002", "address": "192.168.1.102",
+			"catalogPath": "/data/test_db/v_test_db_node0002_catalog/Catalog", "isPrimary": false,
+			"subclusterOid": 45035996273705010}],
+		"Subcluster": [{"name": "default_subcluster", "oid": 45035996273704988, "isPrimary": true},
+			{"name": "sc1", "oid": 45035996273705010, "isPrimary": false, "sandbox": "sand1"}],
+		"StorageLocation": [{"name": "__location_0", "path": "/data/test_db/v_test_db_node0001_data", "usage": 1},
+			{"name": "__location_1", "path": "/data/test_db/v_test_db_node0001_depot", "usage": 5, "size": 21474836480}]}`
+
+	descFileContent := fileContent{}
+	err := json.Unmarshal([]byte(descFile), &descFileContent)
+	assert.NoError(t, err)
+
+	desc := makeDBDescription(&descFileContent)
+	assert.Equal(t, "test_db", desc.Name)
+	assert.Equal(t, "v24.2.0", desc.Version)
+	// the replica shard is not counted
+	assert.Equal(t, 2, desc.ShardCount)
+	assert.Equal(t, "2024-03-04 08:05:00", desc.ClusterLeaseExpiration)
+	assert.Equal(t, []DBDescriptionNode{{Name: "v_test_db_node0001", Address: "192.168.1.101",
+		CatalogPath: "/data/test_db/v_test_db_node0001_catalog/Catalog", IsPrimary: true, Subcluster: "default_subcluster"},
+		{Name: "v_test_db_node0002", Address: "192.168.1.102",
+			CatalogPath: "/data/test_db/v_test_db_node0002_catalog/Catalog", Subcluster: "sc1", Sandbox: "sand1"}}, desc.Nodes)
+	assert.Equal(t, []string{"/data/test_db/v_test_db_node0001_data", "/data/test_db/v_test_db_node0001_depot"},
+		desc.StorageLocations)
+	assert.Equal(t, uint64(1073741824), desc.CatalogSizeBytes)
+	assert.Equal(t, uint64(21474836480), desc.DepotSizeBytes)
+	assert.Equal(t, []DBDescriptionSubcluster{{Name: "default_subcluster", IsPrimary: true},
+		{Name: "sc1", Sandbox: "sand1"}}, desc.Subclusters)
+	assert.Equal(t, []string{"sand1"}, desc.Sandboxes)
+
+	// compare with the intended topology
+	missingHosts, extraHosts := desc.DiffHosts([]string{"192.168.1.103", "192.168.1.101"})
+	assert.Equal(t, []string{"192.168.1.103"}, missingHosts)
+	assert.Equal(t, []string{"192.168.1.102"}, extraHosts)
+}
@@ -0,0 +1,141 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/maps"
+)
+
+type nmaGetDiskUsageOp struct {
+	opBase
+	hostRequestBodyMap map[string]string
+	// host -> disk usage of the requested paths
+	hostDiskUsage map[string][]PathDiskUsage
+}
+
+// PathDiskUsage is the usage of the file system that a path lives on
+type PathDiskUsage struct {
+	Path           string `json:"path"`
+	TotalBytes     uint64 `json:"total_bytes"`
+	AvailableBytes uint64 `json:"available_bytes"`
+}
+
+// UsedPercent returns the percentage of the file system in use
+func (usage *PathDiskUsage) UsedPercent() float64 {
+	if usage.TotalBytes == 0 {
+		return 0
+	}
+	const fullPercent = 100
+	return float64(usage.TotalBytes-usage.AvailableBytes) * fullPercent / float64(usage.TotalBytes)
+}
+
+type diskUsageRequestData struct {
+	Paths []string `json:"paths"`
+}
+
+type diskUsageResponse struct {
+	DiskUsage []PathDiskUsage `json:"disk_usage"`
+}
+
+func (diskUsageResponse) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
+func makeNMAGetDiskUsageOp(hostPaths map[string][]string,
+	hostDiskUsage map[string][]PathDiskUsage) (nmaGetDiskUsageOp, error) {
+	op := nmaGetDiskUsageOp{}
+	op.name = "NMAGetDiskUsageOp"
+	op.description = "Get disk usage of database paths"
+	op.hosts = maps.Keys(hostPaths)
+	op.hostDiskUsage = hostDiskUsage
+
+	err := op.setupRequestBody(hostPaths)
+	return op, err
+}
+
+func (op *nmaGetDiskUsageOp) setupRequestBody(hostPaths map[string][]string) error {
+	op.hostRequestBodyMap = make(map[string]string)
+	for host, paths := range hostPaths {
+		dataBytes, err := json.Marshal(diskUsageRequestData{Paths: paths})
+		if err != nil {
+			return fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+		}
+		op.hostRequestBodyMap[host] = string(dataBytes)
+	}
+
+	return nil
+}
+
+func (op *nmaGetDiskUsageOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildNMAEndpoint("disk-usage")
+		httpRequest.RequestData = op.hostRequestBodyMap[host]
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaGetDiskUsageOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaGetDiskUsageOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaGetDiskUsageOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaGetDiskUsageOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// example response:
+		// {"disk_usage": [{"path": "/data/test_db/v_test_db_node0001_catalog",
+		//   "total_bytes": 107374182400, "available_bytes": 53687091200}]}
+		resp := diskUsageResponse{}
+		err := op.parseAndValidateResponse(host, result.content, &resp)
+		if err != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] fail to parse result on host %s, details: %w",
+				op.name, host, err))
+			continue
+		}
+		op.hostDiskUsage[host] = resp.DiskUsage
+	}
+
+	return allErrs
+}
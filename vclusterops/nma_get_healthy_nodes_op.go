@@ -79,15 +79,15 @@ func (op *nmaGetHealthyNodesOp) processResult(_ *opEngineExecContext) error {
 				op.vdb.HostList = append(op.vdb.HostList, host)
 			} else {
 				op.logger.Error(err, "NMA health check response malformed from host", "Host", host)
-				op.logger.PrintWarning("Skipping unhealthy host %s", host)
+				op.addWarning(host, "Skipping unhealthy host %s", host)
 			}
 		} else {
 			op.logger.Error(result.err, "Host is not reachable", "Host", host)
-			op.logger.PrintWarning("Skipping unreachable host %s", host)
+			op.addWarning(host, "Skipping unreachable host %s", host)
 		}
 	}
 	if len(op.vdb.HostList) == 0 {
-		return fmt.Errorf("NMA is down or unresponsive on all hosts")
+		return categorizeError(ErrNMAUnreachable, fmt.Errorf("NMA is down or unresponsive on all hosts"))
 	}
 
 	return nil
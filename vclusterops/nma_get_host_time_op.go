@@ -0,0 +1,108 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type nmaGetHostTimeOp struct {
+	opBase
+	// host -> offset of the host clock from the local clock
+	hostClockOffsets map[string]time.Duration
+}
+
+type hostTimeResponse struct {
+	Time string `json:"time"`
+}
+
+func (hostTimeResponse) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
+func makeNMAGetHostTimeOp(hosts []string, hostClockOffsets map[string]time.Duration) nmaGetHostTimeOp {
+	op := nmaGetHostTimeOp{}
+	op.name = "NMAGetHostTimeOp"
+	op.description = "Get clock of hosts"
+	op.hosts = hosts
+	op.hostClockOffsets = hostClockOffsets
+	return op
+}
+
+func (op *nmaGetHostTimeOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildNMAEndpoint("time")
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaGetHostTimeOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaGetHostTimeOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaGetHostTimeOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaGetHostTimeOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+	// the requests are sent in parallel, so the offsets of the hosts
+	// are comparable even though the local clock may be off
+	now := time.Now()
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// example response: {"time": "2024-04-05T16:33:19.975952Z"}
+		resp := hostTimeResponse{}
+		err := op.parseAndValidateResponse(host, result.content, &resp)
+		if err != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] fail to parse result on host %s, details: %w",
+				op.name, host, err))
+			continue
+		}
+		hostTime, err := time.Parse(time.RFC3339Nano, resp.Time)
+		if err != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] invalid time %q on host %s: %w",
+				op.name, resp.Time, host, err))
+			continue
+		}
+		op.hostClockOffsets[host] = hostTime.Sub(now)
+	}
+
+	return allErrs
+}
@@ -82,11 +82,11 @@ func (op *nmaGetNodesInfoOp) processResult(_ *opEngineExecContext) error {
 
 		if result.isPassing() {
 			var vnode VCoordinationNode
-			err := op.parseAndCheckResponse(host, result.content, &vnode)
+			err := op.parseAndValidateResponse(host, result.content, &vnode)
 			if err != nil {
 				if op.ignoreInternalErrors {
 					op.logger.Error(err, "NMA node info response malformed from host", "Host", host)
-					op.logger.PrintWarning("Host %s returned unparsable node info. Skipping host.", host)
+					op.addWarning(host, "Host %s returned unparsable node info. Skipping host.", host)
 				} else {
 					return errors.Join(allErrs, err)
 				}
@@ -96,10 +96,10 @@ func (op *nmaGetNodesInfoOp) processResult(_ *opEngineExecContext) error {
 			}
 		} else if result.isInternalError() && op.ignoreInternalErrors {
 			op.logger.Error(result.err, "NMA node info reported internal error", "Host", host)
-			op.logger.PrintWarning("Host %s reported internal error to node info query. Skipping host.", host)
+			op.addWarning(host, "Host %s reported internal error to node info query. Skipping host.", host)
 		} else if result.isTimeout() && op.ignoreInternalErrors {
 			// it's unlikely for a node to pass health check but time out here, so leave default timeout limit
-			op.logger.PrintWarning("Host %s timed out on node info query. Skipping host.", host)
+			op.addWarning(host, "Host %s timed out on node info query. Skipping host.", host)
 		} else {
 			allErrs = errors.Join(allErrs, result.err)
 		}
@@ -83,15 +83,16 @@ func (op *nmaGetScrutinizeTarOp) createOutputDir() error {
 func (op *nmaGetScrutinizeTarOp) prepare(execContext *opEngineExecContext) error {
 	// for the system table batch
 	if op.useInitiator {
-		if len(execContext.upHosts) == 0 {
-			op.logger.PrintWarning("no up hosts to collect system tables from, skipping the operation")
+		upHosts, ok := execContext.UpHosts()
+		if !ok {
+			op.addWarning("", "no up hosts to collect system tables from, skipping the operation")
 			op.skipExecute = true
 			return nil
 		}
 
-		host := getInitiatorFromUpHosts(execContext.upHosts, op.hosts)
+		host := getInitiatorFromUpHosts(upHosts, op.hosts)
 		if host == "" {
-			op.logger.PrintWarning("no up hosts among user specified hosts to collect system tables from, skipping the operation")
+			op.addWarning("", "no up hosts among user specified hosts to collect system tables from, skipping the operation")
 			op.skipExecute = true
 			return nil
 		}
@@ -141,7 +142,7 @@ func (op *nmaGetScrutinizeTarOp) processResult(_ *opEngineExecContext) error {
 				"Node", op.hostNodeNameMap[host],
 				"Batch", op.batch)
 			if result.isInternalError() {
-				op.logger.PrintWarning("Failed to tar batch %s on host %s. Skipping.", op.batch, host)
+				op.addWarning(host, "Failed to tar batch %s on host %s. Skipping.", op.batch, host)
 			} else {
 				err := fmt.Errorf("failed to retrieve tarball batch %s on host %s, details %w",
 					op.batch, host, result.err)
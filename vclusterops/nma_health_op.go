@@ -17,6 +17,7 @@ package vclusterops
 
 import (
 	"errors"
+	"fmt"
 )
 
 type nmaHealthOp struct {
@@ -71,8 +72,11 @@ func (op *nmaHealthOp) processResult(_ *opEngineExecContext) error {
 			if err != nil {
 				return errors.Join(allErrs, err)
 			}
+		} else if result.isUnreachable() {
+			allErrs = errors.Join(allErrs, makeNMAUnreachableError(host, result.err))
 		} else {
-			allErrs = errors.Join(allErrs, result.err)
+			// the NMA answered, but with an error
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] NMA on host %s is not healthy: %w", op.name, host, result.err))
 		}
 	}
 
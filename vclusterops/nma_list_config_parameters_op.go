@@ -0,0 +1,195 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+type nmaListConfigurationParametersOp struct {
+	opBase
+	hostRequestBody string
+	// the sandboxes of the initiators, keyed by the initiators
+	initiatorsToSandboxes map[string]string
+	// when set, only the parameters in op.sandbox are listed
+	sandboxScoped bool
+	sandbox       string
+}
+
+type listConfigurationParametersData struct {
+	sqlEndpointData
+	// whether the parameters with the default values are listed too
+	IncludeDefaults bool `json:"include_defaults,omitempty"`
+}
+
+// ConfigurationParameter is a configuration parameter whose value differs
+// from its default
+type ConfigurationParameter struct {
+	// the sandbox that the value is set in, empty for the main cluster
+	Sandbox         string `json:"sandbox"`
+	ConfigParameter string `json:"parameter_name"`
+	CurrentValue    string `json:"current_value"`
+	DefaultValue    string `json:"default_value"`
+	// the level that the value is set at, e.g., DATABASE or NODE
+	Level string `json:"level"`
+	// the node that the value is set for, only for the node level
+	NodeName string `json:"node_name,omitempty"`
+	// the user who last changed the value
+	ChangedBy string `json:"changed_by"`
+	// the type of the value, e.g., integer, boolean or varchar
+	DataType string `json:"data_type,omitempty"`
+}
+
+// configurationParameterList is the v1 response of the NMA config-parameters endpoint
+type configurationParameterList []ConfigurationParameter
+
+func (configurationParameterList) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
+func makeNMAListConfigurationParametersOp(hosts []string,
+	username, dbName string, password *string, useHTTPPassword bool) (nmaListConfigurationParametersOp, error) {
+	op := nmaListConfigurationParametersOp{}
+	op.name = "NMAListConfigurationParametersOp"
+	op.description = "List non-default configuration parameters"
+	op.hosts = hosts
+
+	err := op.setupRequestBody(username, dbName, password, useHTTPPassword, false /*includeDefaults*/)
+	if err != nil {
+		return op, err
+	}
+
+	return op, nil
+}
+
+// makeNMAListAllConfigurationParametersOp makes an op that lists all of the
+// configuration parameters in the given sandbox, including the ones with the
+// default values, e.g., to know the valid parameter names
+func makeNMAListAllConfigurationParametersOp(hosts []string,
+	username, dbName, sandbox string, password *string, useHTTPPassword bool) (nmaListConfigurationParametersOp, error) {
+	op := nmaListConfigurationParametersOp{}
+	op.name = "NMAListConfigurationParametersOp"
+	op.description = "List configuration parameters"
+	op.hosts = hosts
+	op.sandbox = sandbox
+	op.sandboxScoped = true
+
+	err := op.setupRequestBody(username, dbName, password, useHTTPPassword, true /*includeDefaults*/)
+	if err != nil {
+		return op, err
+	}
+
+	return op, nil
+}
+
+func (op *nmaListConfigurationParametersOp) setupRequestBody(username, dbName string, password *string,
+	useDBPassword, includeDefaults bool) error {
+	err := ValidateSQLEndpointData(op.name,
+		useDBPassword, username, password, dbName)
+	if err != nil {
+		return err
+	}
+	listConfigData := listConfigurationParametersData{}
+	listConfigData.sqlEndpointData = createSQLEndpointData(username, dbName, useDBPassword, password)
+	listConfigData.IncludeDefaults = includeDefaults
+
+	dataBytes, err := json.Marshal(listConfigData)
+	if err != nil {
+		return fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+
+	op.hostRequestBody = string(dataBytes)
+
+	return nil
+}
+
+func (op *nmaListConfigurationParametersOp) setupClusterHTTPRequest(initiators []string) error {
+	for _, initiator := range initiators {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildNMAEndpoint("configuration/list")
+		httpRequest.RequestData = op.hostRequestBody
+		op.clusterHTTPRequest.RequestCollection[initiator] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaListConfigurationParametersOp) prepare(execContext *opEngineExecContext) error {
+	// select an up host in the main cluster and in each sandbox as the initiators
+	op.initiatorsToSandboxes = make(map[string]string)
+	sandboxesWithInitiator := make(map[string]bool)
+	hosts := append([]string{}, op.hosts...)
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		sandbox, ok := execContext.upHostsToSandboxes[host]
+		if !ok || sandboxesWithInitiator[sandbox] || (op.sandboxScoped && sandbox != op.sandbox) {
+			continue
+		}
+		sandboxesWithInitiator[sandbox] = true
+		op.initiatorsToSandboxes[host] = sandbox
+	}
+	if len(op.initiatorsToSandboxes) == 0 {
+		return fmt.Errorf("[%s] no hosts among %v are UP", op.name, op.hosts)
+	}
+
+	initiators := make([]string, 0, len(op.initiatorsToSandboxes))
+	for initiator := range op.initiatorsToSandboxes {
+		initiators = append(initiators, initiator)
+	}
+	execContext.dispatcher.setup(initiators)
+	return op.setupClusterHTTPRequest(initiators)
+}
+
+func (op *nmaListConfigurationParametersOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaListConfigurationParametersOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaListConfigurationParametersOp) processResult(execContext *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isPassing() {
+			var configParameters configurationParameterList
+			err := op.parseAndValidateResponse(host, result.content, &configParameters)
+			if err != nil {
+				allErrs = errors.Join(allErrs, err)
+				continue
+			}
+			for i := range configParameters {
+				configParameters[i].Sandbox = op.initiatorsToSandboxes[host]
+			}
+			execContext.configParameters = append(execContext.configParameters, configParameters...)
+		} else {
+			allErrs = errors.Join(allErrs, result.err)
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,64 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestNmaListConfigurationParametersOp(t *testing.T) {
+	password := "config-test-password-op"
+	op, err := makeNMAListConfigurationParametersOp([]string{"host1", "host2", "host3", "host4"},
+		"dbadmin", "test_db", &password, true)
+	assert.NoError(t, err)
+
+	// one UP host of the main cluster and of each sandbox is an initiator
+	execContext := makeOpEngineExecContext(context.Background(), vlog.Printer{})
+	execContext.upHostsToSandboxes = map[string]string{"host1": "", "host2": "", "host3": "sand1"}
+	op.setupBasicInfo()
+	err = op.prepare(&execContext)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"host1": "", "host3": "sand1"}, op.initiatorsToSandboxes)
+	assert.Len(t, op.clusterHTTPRequest.RequestCollection, 2)
+
+	// the parameters are tagged with the sandbox of the initiator
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"host1": {host: "host1", statusCode: SuccessCode, status: SUCCESS,
+			content: `[{"parameter_name": "MaxClientSessions", "current_value": "100", "default_value": "50",
+				"level": "DATABASE", "changed_by": "dbadmin"}]`},
+		"host3": {host: "host3", statusCode: SuccessCode, status: SUCCESS,
+			content: `[{"parameter_name": "EnableSSL", "current_value": "1", "default_value": "0",
+				"level": "NODE", "node_name": "v_test_db_node0003", "changed_by": "admin2"}]`},
+	}
+	err = op.processResult(&execContext)
+	assert.NoError(t, err)
+	configParameters := execContext.configParameters
+	sortConfigurationParameters(configParameters)
+	assert.Equal(t, []ConfigurationParameter{
+		{ConfigParameter: "MaxClientSessions", CurrentValue: "100", DefaultValue: "50", Level: "DATABASE", ChangedBy: "dbadmin"},
+		{Sandbox: "sand1", ConfigParameter: "EnableSSL", CurrentValue: "1", DefaultValue: "0", Level: "NODE",
+			NodeName: "v_test_db_node0003", ChangedBy: "admin2"},
+	}, configParameters)
+
+	// no UP host
+	execContext.upHostsToSandboxes = nil
+	err = op.prepare(&execContext)
+	assert.ErrorContains(t, err, "no hosts among")
+}
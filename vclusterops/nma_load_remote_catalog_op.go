@@ -30,6 +30,8 @@ type nmaLoadRemoteCatalogOp struct {
 	timeout                 uint
 	primaryNodeCount        uint
 	restorePoint            *RestorePointPolicy
+	// bootstrap the catalog of this sandbox instead of the main cluster
+	sandbox string
 }
 
 type loadRemoteCatalogRequestData struct {
@@ -46,6 +48,7 @@ type loadRemoteCatalogRequestData struct {
 	RestorePointArchive string              `json:"restore_point_archive,omitempty"`
 	RestorePointIndex   int                 `json:"restore_point_index,omitempty"`
 	RestorePointID      string              `json:"restore_point_id,omitempty"`
+	Sandbox             string              `json:"sandbox,omitempty"`
 }
 
 func makeNMALoadRemoteCatalogOp(oldHosts []string, configurationParameters map[string]string,
@@ -101,6 +104,7 @@ func (op *nmaLoadRemoteCatalogOp) setupRequestBody(execContext *opEngineExecCont
 		requestData.StorageLocations = vNode.StorageLocations
 		requestData.NodeAddresses = nodeAddresses
 		requestData.Parameters = op.configurationParameters
+		requestData.Sandbox = op.sandbox
 		if op.restorePoint != nil {
 			requestData.RestorePointArchive = op.restorePoint.Archive
 			requestData.RestorePointIndex = op.restorePoint.Index
@@ -163,7 +167,7 @@ func (op *nmaLoadRemoteCatalogOp) processResult(_ *opEngineExecContext) error {
 
 		if result.isPassing() {
 			response := httpsResponseStatus{}
-			err := op.parseAndCheckResponse(host, result.content, &response)
+			err := op.parseAndValidateResponse(host, result.content, &response)
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
 				continue
@@ -187,7 +191,8 @@ func (op *nmaLoadRemoteCatalogOp) processResult(_ *opEngineExecContext) error {
 
 	// quorum check
 	if !op.hasQuorum(successPrimaryNodeCount, op.primaryNodeCount) {
-		err := fmt.Errorf("[%s] fail to load catalog on enough primary nodes. Success count: %d", op.name, successPrimaryNodeCount)
+		err := categorizeError(ErrQuorumLost,
+			fmt.Errorf("[%s] fail to load catalog on enough primary nodes. Success count: %d", op.name, successPrimaryNodeCount))
 		op.logger.Error(err, "fail to load catalog, detail")
 		allErrs = errors.Join(allErrs, err)
 		return allErrs
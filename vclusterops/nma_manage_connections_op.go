@@ -76,7 +76,7 @@ func (op *nmaManageConnectionsOp) setupRequestBody(
 
 	op.hostRequestBody = string(dataBytes)
 
-	op.logger.Info("request data", "op name", op.name, "hostRequestBody", op.hostRequestBody)
+	op.logger.Info("request data", "op name", op.name, "hostRequestBody", redactForLog(op.hostRequestBody))
 
 	return nil
 }
@@ -114,6 +114,19 @@ func (op *nmaManageConnectionsOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
 
+// getCompensation resumes the connections that the op paused, so that a failed
+// command does not leave the database refusing new connections
+func (op *nmaManageConnectionsOp) getCompensation() (clusterOp, error) {
+	if op.action != ActionPause {
+		return nil, nil
+	}
+	resumeOp := nmaManageConnectionsOp{hostRequestBody: op.hostRequestBody, sandbox: op.sandbox, action: ActionResume}
+	resumeOp.name = op.name
+	resumeOp.description = "Resume connections on Vertica hosts"
+	resumeOp.hosts = op.hosts
+	return &resumeOp, nil
+}
+
 func (op *nmaManageConnectionsOp) processResult(_ *opEngineExecContext) error {
 	var allErrs error
 
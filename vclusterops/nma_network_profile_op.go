@@ -18,12 +18,14 @@ package vclusterops
 import (
 	"errors"
 	"fmt"
-
-	"github.com/vertica/vcluster/vclusterops/util"
+	"net"
 )
 
 type nmaNetworkProfileOp struct {
 	opBase
+	// when set, the addresses in the re-ip list are validated against the
+	// network profiles before any catalog change is made
+	reIPList []ReIPInfo
 }
 
 func makeNMANetworkProfileOp(hosts []string) nmaNetworkProfileOp {
@@ -34,6 +36,19 @@ func makeNMANetworkProfileOp(hosts []string) nmaNetworkProfileOp {
 	return op
 }
 
+// makeNMANetworkProfileOpForReIP gets the network profiles of the target
+// addresses in the re-ip list, and validates that the addresses live on the
+// interfaces of the hosts
+func makeNMANetworkProfileOpForReIP(reIPList []ReIPInfo) nmaNetworkProfileOp {
+	var hosts []string
+	for _, info := range reIPList {
+		hosts = append(hosts, info.TargetAddress)
+	}
+	op := makeNMANetworkProfileOp(hosts)
+	op.reIPList = reIPList
+	return op
+}
+
 func (op *nmaNetworkProfileOp) setupClusterHTTPRequest(hosts []string) error {
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
@@ -64,18 +79,25 @@ func (op *nmaNetworkProfileOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
 
-type networkProfile struct {
-	Name      string
-	Address   string
-	Subnet    string
-	Netmask   string
-	Broadcast string
+// NetworkProfile describes the network interface that an address of a host
+// lives on. It is the v1 response of the NMA network-profiles endpoint.
+type NetworkProfile struct {
+	// name of the network interface, e.g., eth0
+	Name      string `json:"name" validate:"required"`
+	Address   string `json:"address" validate:"required"`
+	Subnet    string `json:"subnet" validate:"required"`
+	Netmask   string `json:"netmask" validate:"required"`
+	Broadcast string `json:"broadcast" validate:"required"`
+}
+
+func (NetworkProfile) supportedVersions() []string {
+	return []string{NMAVersion1}
 }
 
 func (op *nmaNetworkProfileOp) processResult(execContext *opEngineExecContext) error {
 	var allErrs error
 
-	allNetProfiles := make(map[string]networkProfile)
+	allNetProfiles := make(map[string]NetworkProfile)
 
 	for host, result := range op.clusterHTTPRequest.ResultCollection {
 		op.logResponse(host, result)
@@ -94,13 +116,54 @@ func (op *nmaNetworkProfileOp) processResult(execContext *opEngineExecContext) e
 	}
 
 	// save network profiles to execContext
-	execContext.networkProfiles = allNetProfiles
+	execContext.SetNetworkProfiles(allNetProfiles)
+	if allErrs != nil {
+		return allErrs
+	}
 
+	return op.validateReIPList(allNetProfiles)
+}
+
+// validateReIPList checks that each target address in the re-ip list lives
+// on an interface of its host, and that the control address and the control
+// broadcast, when given, are in the subnet of that interface
+func (op *nmaNetworkProfileOp) validateReIPList(profiles map[string]NetworkProfile) error {
+	var allErrs error
+	for _, info := range op.reIPList {
+		profile, ok := profiles[info.TargetAddress]
+		if !ok {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] unable to find network profile for address %s",
+				op.name, info.TargetAddress))
+			continue
+		}
+		if !isSameIP(profile.Address, info.TargetAddress) {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] address %s does not live on any interface of the host,"+
+				" the closest interface %s has address %s", op.name, info.TargetAddress, profile.Name, profile.Address))
+			continue
+		}
+		_, subnet, err := net.ParseCIDR(profile.Subnet)
+		if err != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] invalid subnet %s in the network profile of address %s: %w",
+				op.name, profile.Subnet, info.TargetAddress, err))
+			continue
+		}
+		for _, addr := range []string{info.TargetControlAddress, info.TargetControlBroadcast} {
+			if addr != "" && !subnet.Contains(net.ParseIP(addr)) {
+				allErrs = errors.Join(allErrs, fmt.Errorf("[%s] address %s is not in the subnet %s of interface %s on host %s",
+					op.name, addr, profile.Subnet, profile.Name, info.TargetAddress))
+			}
+		}
+	}
 	return allErrs
 }
 
-func (op *nmaNetworkProfileOp) parseResponse(host, resultContent string) (networkProfile, error) {
-	var responseObj networkProfile
+func isSameIP(addr1, addr2 string) bool {
+	ip1, ip2 := net.ParseIP(addr1), net.ParseIP(addr2)
+	return ip1 != nil && ip1.Equal(ip2)
+}
+
+func (op *nmaNetworkProfileOp) parseResponse(host, resultContent string) (NetworkProfile, error) {
+	var responseObj NetworkProfile
 
 	// the response_obj will be a dictionary like the following:
 	// {
@@ -110,13 +173,8 @@ func (op *nmaNetworkProfileOp) parseResponse(host, resultContent string) (networ
 	//   "netmask" : "255.255.0.0"
 	//   "broadcast": "192.168.255.255"
 	// }
-	err := op.parseAndCheckResponse(host, resultContent, &responseObj)
-	if err != nil {
-		return responseObj, err
-	}
-
 	// check whether any field is empty
-	err = util.CheckMissingFields(responseObj)
+	err := op.parseAndValidateResponse(host, resultContent, &responseObj)
 
 	return responseObj, err
 }
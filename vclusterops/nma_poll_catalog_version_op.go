@@ -0,0 +1,211 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"golang.org/x/exp/slices"
+)
+
+// nmaPollCatalogVersionOp waits for the catalog of the started nodes to
+// catch up with the catalog of an UP node, which is used as the leader. The
+// catalog versions are read through the NMA catalog editor endpoint.
+type nmaPollCatalogVersionOp struct {
+	opBase
+	vdb     *VCoordinationDatabase
+	sandbox string
+	timeout int
+	// the UP node the other hosts compare their catalog version with
+	leader string
+	// the latest global catalog version of the leader
+	leaderVersion int64
+	// the latest global catalog version of each started host
+	hostVersions map[string]int64
+}
+
+func makeNMAPollCatalogVersionOp(hosts []string, vdb *VCoordinationDatabase,
+	sandbox string, timeout int) nmaPollCatalogVersionOp {
+	op := nmaPollCatalogVersionOp{}
+	op.name = "NMAPollCatalogVersionOp"
+	op.description = "Wait for the catalog of the nodes to be synced"
+	op.hosts = hosts
+	op.vdb = vdb
+	op.sandbox = sandbox
+	op.timeout = timeout
+	op.hostVersions = make(map[string]int64)
+	return op
+}
+
+func (op *nmaPollCatalogVersionOp) getPollingTimeout() int {
+	return util.Max(op.timeout, 0)
+}
+
+func (op *nmaPollCatalogVersionOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		vnode, ok := op.vdb.HostNodeMap[host]
+		if !ok {
+			return fmt.Errorf("[%s] cannot find host %s in the catalog", op.name, host)
+		}
+
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildNMAEndpoint("catalog/database")
+		httpRequest.QueryParams = map[string]string{"catalog_path": vnode.CatalogPath}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+// getLeaderCandidates returns the hosts that are not started by the op,
+// with the primary hosts first
+func (op *nmaPollCatalogVersionOp) getLeaderCandidates() []string {
+	var primaryHosts, secondaryHosts []string
+	for _, host := range op.vdb.HostList {
+		vnode, ok := op.vdb.HostNodeMap[host]
+		if !ok || slices.Contains(op.hosts, host) {
+			continue
+		}
+		if vnode.IsPrimary {
+			primaryHosts = append(primaryHosts, host)
+		} else {
+			secondaryHosts = append(secondaryHosts, host)
+		}
+	}
+	return append(primaryHosts, secondaryHosts...)
+}
+
+func (op *nmaPollCatalogVersionOp) prepare(execContext *opEngineExecContext) error {
+	leader, err := getInitiatorInSandbox(op.sandbox, op.getLeaderCandidates(), execContext.upHostsToSandboxes)
+	if err != nil {
+		return err
+	}
+	op.leader = leader
+
+	hosts := append([]string{op.leader}, op.hosts...)
+	execContext.dispatcher.setup(hosts)
+	return op.setupClusterHTTPRequest(hosts)
+}
+
+func (op *nmaPollCatalogVersionOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaPollCatalogVersionOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaPollCatalogVersionOp) processResult(execContext *opEngineExecContext) error {
+	err := pollState(op, execContext)
+
+	// record the catalog versions even if they have not caught up
+	for _, host := range op.hosts {
+		result := execContext.getNodeStartResult(host)
+		result.CatalogVersion = op.hostVersions[host]
+		result.CatalogSynced = op.isHostSynced(host)
+	}
+
+	if errors.Is(err, errPollingTimeout) {
+		return categorizeError(ErrCatalogMismatch,
+			fmt.Errorf("[%s] the catalog of hosts %v is still behind version %d of host %s after %d seconds",
+				op.name, op.getUnsyncedHosts(), op.leaderVersion, op.leader, op.timeout))
+	}
+	if err != nil {
+		return fmt.Errorf("fail to wait for the catalog of the nodes to be synced, %w", err)
+	}
+
+	return nil
+}
+
+func (op *nmaPollCatalogVersionOp) isHostSynced(host string) bool {
+	version, ok := op.hostVersions[host]
+	return ok && version >= op.leaderVersion
+}
+
+func (op *nmaPollCatalogVersionOp) getUnsyncedHosts() []string {
+	var hosts []string
+	for _, host := range op.hosts {
+		if !op.isHostSynced(host) {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// parseGlobalVersion returns the global catalog version in the response of
+// the catalog editor endpoint
+func (op *nmaPollCatalogVersionOp) parseGlobalVersion(host, content string) (int64, error) {
+	nmaVDB := nmaVDatabase{}
+	err := op.parseAndValidateResponse(host, content, &nmaVDB)
+	if err != nil {
+		return 0, err
+	}
+	return nmaVDB.Versions.Global.Int64()
+}
+
+func (op *nmaPollCatalogVersionOp) shouldStopPolling() (bool, error) {
+	leaderResult, ok := op.clusterHTTPRequest.ResultCollection[op.leader]
+	if !ok {
+		// this could happen if ResultCollection is empty
+		op.logger.PrintError("[%s] empty result received from the leader host %s", op.name, op.leader)
+		return false, nil
+	}
+	op.logResponse(op.leader, leaderResult)
+	if !leaderResult.isPassing() {
+		return true, fmt.Errorf("[%s] fail to read the catalog of the leader host %s, details: %w",
+			op.name, op.leader, leaderResult.err)
+	}
+	leaderVersion, err := op.parseGlobalVersion(op.leader, leaderResult.content)
+	if err != nil {
+		return true, fmt.Errorf("[%s] fail to parse the catalog version of host %s, details: %w", op.name, op.leader, err)
+	}
+	op.leaderVersion = leaderVersion
+
+	for _, host := range op.hosts {
+		result, ok := op.clusterHTTPRequest.ResultCollection[host]
+		if !ok {
+			continue
+		}
+		op.logResponse(host, result)
+		// the catalog of a node that just started may not be readable yet
+		if !result.isPassing() {
+			continue
+		}
+		version, err := op.parseGlobalVersion(host, result.content)
+		if err != nil {
+			op.logger.PrintWarning("[%s] fail to parse the catalog version of host %s, details: %v", op.name, host, err)
+			continue
+		}
+		op.hostVersions[host] = version
+	}
+
+	unsyncedHosts := op.getUnsyncedHosts()
+	if len(unsyncedHosts) > 0 {
+		op.logger.PrintInfo("[%s] the catalog of hosts %v is behind version %d of host %s",
+			op.name, unsyncedHosts, op.leaderVersion, op.leader)
+		return false, nil
+	}
+
+	op.logger.PrintInfo("The catalog of all started nodes is synced")
+	return true, nil
+}
@@ -0,0 +1,78 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestPollCatalogVersion(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostList = []string{"192.168.1.101", "192.168.1.102", "192.168.1.103"}
+	vdb.HostNodeMap = vHostNodeMap{
+		"192.168.1.101": {Name: "v_db_node0001", CatalogPath: "/data/db/v_db_node0001_catalog", IsPrimary: true},
+		"192.168.1.102": {Name: "v_db_node0002", CatalogPath: "/data/db/v_db_node0002_catalog", State: util.NodeDownState},
+		"192.168.1.103": {Name: "v_db_node0003", CatalogPath: "/data/db/v_db_node0003_catalog", State: util.NodeDownState},
+	}
+	op := makeNMAPollCatalogVersionOp([]string{"192.168.1.102", "192.168.1.103"}, &vdb, "", 0)
+	op.setLogger(vlog.Printer{})
+	op.setupBasicInfo()
+
+	// the leader is an UP node that is not started by the op
+	execContext := makeOpEngineExecContext(context.Background(), vlog.Printer{})
+	execContext.upHostsToSandboxes = map[string]string{"192.168.1.101": ""}
+	assert.NoError(t, op.prepare(&execContext))
+	assert.Equal(t, "192.168.1.101", op.leader)
+	assert.Len(t, op.clusterHTTPRequest.RequestCollection, 3)
+
+	// a host whose catalog cannot be read yet is still behind
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {host: "192.168.1.101", statusCode: SuccessCode, content: `{"versions": {"global": 10}}`},
+		"192.168.1.102": {host: "192.168.1.102", statusCode: SuccessCode, content: `{"versions": {"global": 10}}`},
+		"192.168.1.103": {host: "192.168.1.103", statusCode: InternalErrorCode, err: errors.New("catalog is locked")},
+	}
+	stop, err := op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.False(t, stop)
+	assert.Equal(t, []string{"192.168.1.103"}, op.getUnsyncedHosts())
+
+	// reaching the timeout is a catalog mismatch, and the versions are kept
+	err = op.processResult(&execContext)
+	assert.ErrorIs(t, err, ErrCatalogMismatch)
+	assert.True(t, execContext.getNodeStartResult("192.168.1.102").CatalogSynced)
+	assert.False(t, execContext.getNodeStartResult("192.168.1.103").CatalogSynced)
+
+	op.clusterHTTPRequest.ResultCollection["192.168.1.103"] = hostHTTPResult{host: "192.168.1.103",
+		statusCode: SuccessCode, content: `{"versions": {"global": 11}}`}
+	stop, err = op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.True(t, stop)
+	assert.Equal(t, int64(11), op.hostVersions["192.168.1.103"])
+
+	// the start results carry the node names and the dbLog paths
+	execContext.getNodeStartResult("192.168.1.102").DBLogPath = "/data/db/v_db_node0002_catalog/dbLog"
+	results := makeNodeStartResults(&execContext, []string{"192.168.1.103", "192.168.1.102"}, &vdb, false)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "v_db_node0002", results[0].NodeName)
+	assert.Equal(t, "/data/db/v_db_node0002_catalog/dbLog", results[0].DBLogPath)
+	assert.Equal(t, util.NodeDownState, results[1].State)
+}
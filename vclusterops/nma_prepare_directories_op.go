@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"golang.org/x/exp/maps"
 )
@@ -28,6 +29,13 @@ type nmaPrepareDirectoriesOp struct {
 	hostRequestBodyMap map[string]string
 	forceCleanup       bool
 	forRevive          bool
+	hostNodeMap        vHostNodeMap
+	// when set, the force cleanup moves the existing directories under this path
+	trashPath string
+	// accept a response that does not confirm the move to the trash path
+	allowDeleteFallback bool
+	// host -> the directories that the op created on the host
+	createdDirectories map[string][]string
 }
 
 type prepareDirectoriesRequestData struct {
@@ -38,6 +46,7 @@ type prepareDirectoriesRequestData struct {
 	ForceCleanup         bool     `json:"force_cleanup"`
 	ForRevive            bool     `json:"for_revive"`
 	IgnoreParent         bool     `json:"ignore_parent"`
+	TrashPath            string   `json:"trash_path,omitempty"`
 }
 
 func makeNMAPrepareDirectoriesOp(hostNodeMap vHostNodeMap,
@@ -47,6 +56,7 @@ func makeNMAPrepareDirectoriesOp(hostNodeMap vHostNodeMap,
 	op.description = "Create necessary directories on Vertica hosts"
 	op.forceCleanup = forceCleanup
 	op.forRevive = forRevive
+	op.hostNodeMap = hostNodeMap
 
 	err := op.setupRequestBody(hostNodeMap)
 	if err != nil {
@@ -70,6 +80,7 @@ func (op *nmaPrepareDirectoriesOp) setupRequestBody(hostNodeMap vHostNodeMap) er
 		prepareDirData.ForceCleanup = op.forceCleanup
 		prepareDirData.ForRevive = op.forRevive
 		prepareDirData.IgnoreParent = false
+		prepareDirData.TrashPath = op.trashPath
 
 		dataBytes, err := json.Marshal(prepareDirData)
 		if err != nil {
@@ -78,11 +89,29 @@ func (op *nmaPrepareDirectoriesOp) setupRequestBody(hostNodeMap vHostNodeMap) er
 
 		op.hostRequestBodyMap[host] = string(dataBytes)
 	}
-	op.logger.Info("request data", "op name", op.name, "hostRequestBodyMap", op.hostRequestBodyMap)
+	op.logger.Info("request data", "op name", op.name, "hostRequestBodyMap", redactForLog(op.hostRequestBodyMap))
 
 	return nil
 }
 
+// moveToTrash makes the force cleanup move the existing directories under the
+// trash path, instead of deleting them. The trash path must not be inside the
+// directories to clean up. Unless allowDeleteFallback is set, the op fails on a
+// host whose response does not confirm that the directories were moved.
+func (op *nmaPrepareDirectoriesOp) moveToTrash(trashPath string, allowDeleteFallback bool) error {
+	for host, vnode := range op.hostNodeMap {
+		for _, path := range getNodeDirectories(vnode) {
+			if len(findNestedPaths([]string{path, trashPath})) > 0 {
+				return fmt.Errorf("[%s] trash path %s and directory %s on host %s must not be nested inside each other",
+					op.name, trashPath, path, host)
+			}
+		}
+	}
+	op.trashPath = trashPath
+	op.allowDeleteFallback = allowDeleteFallback
+	return op.setupRequestBody(op.hostNodeMap)
+}
+
 func (op *nmaPrepareDirectoriesOp) setupClusterHTTPRequest(hosts []string) error {
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
@@ -97,7 +126,14 @@ func (op *nmaPrepareDirectoriesOp) setupClusterHTTPRequest(hosts []string) error
 
 func (op *nmaPrepareDirectoriesOp) prepare(execContext *opEngineExecContext) error {
 	execContext.dispatcher.setup(op.hosts)
-	return op.setupClusterHTTPRequest(op.hosts)
+	err := op.setupClusterHTTPRequest(op.hosts)
+	if err != nil {
+		return err
+	}
+	// the NMA does not fail a retried request on the directories that the
+	// first attempt created
+	op.setIdempotencyKey(execContext)
+	return nil
 }
 
 func (op *nmaPrepareDirectoriesOp) execute(execContext *opEngineExecContext) error {
@@ -125,10 +161,16 @@ func (op *nmaPrepareDirectoriesOp) processResult(_ *opEngineExecContext) error {
 			//  '/data/good/v_good_node0003_data': 'created',
 			//  '/data/good/v_good_node0003_depot': 'created',
 			//  '/opt/vertica/config/logrotate': 'created'}
-			_, err := op.parseAndCheckMapResponse(host, result.content)
+			responseObj, err := op.parseAndCheckMapResponse(host, result.content)
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
+				continue
 			}
+			if err := op.checkMovedToTrash(host, responseObj); err != nil {
+				allErrs = errors.Join(allErrs, err)
+				continue
+			}
+			op.saveCreatedDirectories(host, responseObj)
 		} else {
 			allErrs = errors.Join(allErrs, result.err)
 		}
@@ -136,3 +178,87 @@ func (op *nmaPrepareDirectoriesOp) processResult(_ *opEngineExecContext) error {
 
 	return allErrs
 }
+
+// preparedDirectoryCreated is the status of a directory that did not exist
+// before the op
+const preparedDirectoryCreated = "created"
+
+// trashPathKey is the key of the response that holds the path that the existing
+// directories were moved to
+const trashPathKey = "trash_path"
+
+// checkMovedToTrash checks that the response confirms that the existing directories
+// were moved to the trash path. An NMA that does not support the trash deletes them
+// and does not return the trash path.
+func (op *nmaPrepareDirectoriesOp) checkMovedToTrash(host string, responseObj opResponseMap) error {
+	if op.trashPath == "" || responseObj[trashPathKey] == op.trashPath {
+		return nil
+	}
+	if op.allowDeleteFallback {
+		op.logger.PrintWarning("[%s] NMA on host %s did not confirm that the existing directories were moved to %s, "+
+			"they may have been deleted", op.name, host, op.trashPath)
+		return nil
+	}
+	return fmt.Errorf("[%s] NMA on host %s did not confirm that the existing directories were moved to %s",
+		op.name, host, op.trashPath)
+}
+
+// sharedConfigDirectory holds the config of all of the databases on a host,
+// e.g., the logrotate config, so it is never deleted by a rollback
+const sharedConfigDirectory = "/opt/vertica/config"
+
+// saveCreatedDirectories keeps the created directories that belong to the node
+// of the host, i.e., its catalog, depot and storage location directories. The
+// other directories, like the database directory and the shared config
+// directory, can be used by the other nodes and databases on the host.
+func (op *nmaPrepareDirectoriesOp) saveCreatedDirectories(host string, responseObj opResponseMap) {
+	vnode, ok := op.hostNodeMap[host]
+	if !ok {
+		return
+	}
+	nodeDirectories := []string{getCatalogPath(vnode.CatalogPath)}
+	if vnode.DepotPath != "" {
+		nodeDirectories = append(nodeDirectories, vnode.DepotPath)
+	}
+	nodeDirectories = append(nodeDirectories, vnode.StorageLocations...)
+	nodeDirectories = append(nodeDirectories, vnode.UserStorageLocations...)
+
+	var createdDirectories []string
+	for path, status := range responseObj {
+		if status != preparedDirectoryCreated || isPathUnder(path, sharedConfigDirectory) {
+			continue
+		}
+		for _, nodeDirectory := range nodeDirectories {
+			if isPathUnder(path, nodeDirectory) {
+				createdDirectories = append(createdDirectories, path)
+				break
+			}
+		}
+	}
+	if len(createdDirectories) == 0 {
+		return
+	}
+	if op.createdDirectories == nil {
+		op.createdDirectories = make(map[string][]string)
+	}
+	op.createdDirectories[host] = createdDirectories
+}
+
+// isPathUnder returns true if path is dir or is inside it
+func isPathUnder(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/")
+}
+
+// getCompensation deletes the node directories that the op created, so that
+// a failed run does not leave half-prepared hosts behind. The directories
+// that existed before the op are kept.
+func (op *nmaPrepareDirectoriesOp) getCompensation() (clusterOp, error) {
+	if len(op.createdDirectories) == 0 {
+		return nil, nil
+	}
+	deleteDirsOp, err := makeNMADeleteCreatedDirectoriesOp(op.createdDirectories)
+	if err != nil {
+		return nil, err
+	}
+	return &deleteDirsOp, nil
+}
@@ -75,15 +75,16 @@ func (op *nmaPrepareScrutinizeDirectoriesOp) setupRequestBody(hostNodeNameMap ma
 
 		op.hostRequestBodyMap[host] = string(dataBytes)
 	}
-	op.logger.Info("request data", "op name", op.name, "hostRequestBodyMap", op.hostRequestBodyMap)
+	op.logger.Info("request data", "op name", op.name, "hostRequestBodyMap", redactForLog(op.hostRequestBodyMap))
 
 	return nil
 }
 
 func (op *nmaPrepareScrutinizeDirectoriesOp) prepare(execContext *opEngineExecContext) error {
-	host := getInitiatorFromUpHosts(execContext.upHosts, op.hosts)
+	upHosts, _ := execContext.UpHosts()
+	host := getInitiatorFromUpHosts(upHosts, op.hosts)
 	if host == "" {
-		op.logger.PrintWarning("no up hosts among user specified hosts to collect system tables from, skipping the operation")
+		op.addWarning("", "no up hosts among user specified hosts to collect system tables from, skipping the operation")
 		op.skipExecute = true
 		return nil
 	}
@@ -111,6 +112,10 @@ type prepareScrutinizeDirsResp struct {
 	StagingDir string `json:"staging_dir"`
 }
 
+func (prepareScrutinizeDirsResp) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
 func (op *nmaPrepareScrutinizeDirectoriesOp) processResult(_ *opEngineExecContext) error {
 	var allErrs error
 
@@ -119,7 +124,7 @@ func (op *nmaPrepareScrutinizeDirectoriesOp) processResult(_ *opEngineExecContex
 
 		if result.isPassing() {
 			resp := prepareScrutinizeDirsResp{}
-			err := op.parseAndCheckResponse(host, result.content, &resp)
+			err := op.parseAndValidateResponse(host, result.content, &resp)
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
 			}
@@ -0,0 +1,117 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type nmaPurgeTrashOp struct {
+	opBase
+	requestBody string
+	// host -> the purged directories
+	hostPurgedPaths map[string][]string
+}
+
+type purgeTrashRequestData struct {
+	TrashLocation  string `json:"trash_location"`
+	OlderThanHours int    `json:"older_than_hours"`
+}
+
+type purgeTrashResponse struct {
+	PurgedPaths []string `json:"purged_paths"`
+}
+
+func (purgeTrashResponse) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
+// makeNMAPurgeTrashOp deletes the directories in the trash location that were
+// moved there more than olderThanHours ago, or all of them if it is zero
+func makeNMAPurgeTrashOp(hosts []string, trashLocation string, olderThanHours int,
+	hostPurgedPaths map[string][]string) (nmaPurgeTrashOp, error) {
+	op := nmaPurgeTrashOp{}
+	op.name = "NMAPurgeTrashOp"
+	op.description = "Purge directories in the trash location"
+	op.hosts = hosts
+	op.hostPurgedPaths = hostPurgedPaths
+
+	dataBytes, err := json.Marshal(purgeTrashRequestData{TrashLocation: trashLocation, OlderThanHours: olderThanHours})
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.requestBody = string(dataBytes)
+
+	return op, nil
+}
+
+func (op *nmaPurgeTrashOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildNMAEndpoint("directories/trash/purge")
+		httpRequest.RequestData = op.requestBody
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaPurgeTrashOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaPurgeTrashOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaPurgeTrashOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaPurgeTrashOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// example response:
+		// {"purged_paths": ["/data/trash/test_db_20240304T080500Z"]}
+		resp := purgeTrashResponse{}
+		err := op.parseAndValidateResponse(host, result.content, &resp)
+		if err != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] fail to parse result on host %s, details: %w",
+				op.name, host, err))
+			continue
+		}
+		op.hostPurgedPaths[host] = resp.PurgedPaths
+	}
+
+	return allErrs
+}
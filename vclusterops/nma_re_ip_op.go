@@ -53,6 +53,13 @@ type ReIPInfo struct {
 	TargetControlBroadcast string `json:"control_broadcast"`
 }
 
+// reIPInfoList is the v1 response of the NMA re-ip endpoint
+type reIPInfoList []ReIPInfo
+
+func (reIPInfoList) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
 type reIPParams struct {
 	CatalogPath  string     `json:"catalog_path"`
 	ReIPInfoList []ReIPInfo `json:"re_ip_list"`
@@ -73,7 +80,7 @@ func (op *nmaReIPOp) updateRequestBody(_ *opEngineExecContext) error {
 		op.hostRequestBodyMap[host] = string(dataBytes)
 	}
 
-	op.logger.Info("request data", "op name", op.name, "hostRequestBodyMap", op.hostRequestBodyMap)
+	op.logger.Info("request data", "op name", op.name, "hostRequestBodyMap", redactForLog(op.hostRequestBodyMap))
 	return nil
 }
 
@@ -218,7 +225,8 @@ func (op *nmaReIPOp) prepare(execContext *opEngineExecContext) error {
 
 	// quorum check
 	if !op.hasQuorum(uint(len(op.hosts)), op.primaryNodeCount) {
-		return fmt.Errorf("failed quorum check, not enough primaries exist with: %d", len(op.hosts))
+		return categorizeError(ErrQuorumLost,
+			fmt.Errorf("failed quorum check, not enough primaries exist with: %d", len(op.hosts)))
 	}
 
 	// update re-ip list
@@ -269,8 +277,8 @@ func (op *nmaReIPOp) processResult(_ *opEngineExecContext) error {
 		op.logResponse(host, result)
 
 		if result.isPassing() {
-			var reIPResult []ReIPInfo
-			err := op.parseAndCheckResponse(host, result.content, &reIPResult)
+			var reIPResult reIPInfoList
+			err := op.parseAndValidateResponse(host, result.content, &reIPResult)
 			if err != nil {
 				err = fmt.Errorf("[%s] fail to parse result on host %s, details: %w",
 					op.name, host, err)
@@ -288,7 +296,7 @@ func (op *nmaReIPOp) processResult(_ *opEngineExecContext) error {
 	// quorum check
 	if !op.hasQuorum(successCount, op.primaryNodeCount) {
 		// VER-88054 rollback the commits
-		err := fmt.Errorf("failed quroum check for re-ip update. Success count: %d", successCount)
+		err := categorizeError(ErrQuorumLost, fmt.Errorf("failed quroum check for re-ip update. Success count: %d", successCount))
 		allErrs = errors.Join(allErrs, err)
 	}
 
@@ -179,6 +179,10 @@ type nmaVDatabase struct {
 	PrimaryNodeCount uint `json:",omitempty"`
 }
 
+func (nmaVDatabase) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
 func (op *nmaReadCatalogEditorOp) processResult(execContext *opEngineExecContext) error {
 	var allErrs error
 	var hostsWithLatestCatalog []string
@@ -190,7 +194,7 @@ func (op *nmaReadCatalogEditorOp) processResult(execContext *opEngineExecContext
 
 		if result.isPassing() {
 			nmaVDB := nmaVDatabase{}
-			err := op.parseAndCheckResponse(host, result.content, &nmaVDB)
+			err := op.parseAndValidateResponse(host, result.content, &nmaVDB)
 			if err != nil {
 				err = fmt.Errorf("[%s] fail to parse result on host %s, details: %w",
 					op.name, host, err)
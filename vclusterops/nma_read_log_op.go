@@ -0,0 +1,109 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type nmaReadLogOp struct {
+	opBase
+	logPath   string
+	offset    int64
+	tailLines int
+	result    *logReadResult
+}
+
+// logReadResult is the response of the NMA logs/read endpoint
+type logReadResult struct {
+	// the content read from the offset, or the last lines when the offset is negative
+	Content string `json:"content"`
+	// the offset to read from in the next request
+	NextOffset int64 `json:"next_offset"`
+}
+
+func (logReadResult) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
+// makeNMAReadLogOp reads a log file on a host from the offset. A negative
+// offset reads the last tailLines lines of the file instead.
+func makeNMAReadLogOp(host, logPath string, offset int64, tailLines int,
+	result *logReadResult) nmaReadLogOp {
+	op := nmaReadLogOp{}
+	op.name = "NMAReadLogOp"
+	op.description = "Read log file"
+	op.hosts = []string{host}
+	op.logPath = logPath
+	op.offset = offset
+	op.tailLines = tailLines
+	op.result = result
+	return op
+}
+
+func (op *nmaReadLogOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildNMAEndpoint("logs/read")
+		httpRequest.QueryParams = map[string]string{"path": op.logPath}
+		if op.offset < 0 {
+			httpRequest.QueryParams["tail_lines"] = strconv.Itoa(op.tailLines)
+		} else {
+			httpRequest.QueryParams["offset"] = strconv.FormatInt(op.offset, 10)
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaReadLogOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaReadLogOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaReadLogOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaReadLogOp) processResult(_ *opEngineExecContext) error {
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		// the log content is not logged, as it can be large
+		if !result.isPassing() {
+			return fmt.Errorf("[%s] fail to read %s on host %s: %w", op.name, op.logPath, host, result.err)
+		}
+
+		// example response:
+		// {"content": "2024-04-05 12:33:19.975 Init Session:0x7f... <INFO> ...\n", "next_offset": 1048576}
+		err := op.parseAndValidateResponse(host, result.content, op.result)
+		if err != nil {
+			return fmt.Errorf("[%s] fail to parse result on host %s, details: %w", op.name, host, err)
+		}
+	}
+
+	return nil
+}
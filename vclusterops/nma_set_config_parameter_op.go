@@ -19,13 +19,34 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"golang.org/x/exp/maps"
 )
 
 type nmaSetConfigurationParameterOp struct {
 	opBase
-	hostRequestBody string
-	sandbox         string
-	initiator       string
+	// names of the parameters to set, in the order that they are set
+	configParameters []string
+	// request bodies keyed by the parameter names
+	hostRequestBodies map[string]string
+	sandbox           string
+	initiator         string
+	// whether the parameters are checked against the parameters listed by a
+	// previous op before they are set
+	validateNames bool
+	// the values of the parameters, keyed by the parameter names
+	values map[string]string
+	// the details of the requests, kept to restore the parameters that were
+	// set when a later parameter fails
+	username      string
+	dbName        string
+	level         string
+	password      *string
+	useDBPassword bool
 }
 
 type setConfigurationParameterData struct {
@@ -35,18 +56,35 @@ type setConfigurationParameterData struct {
 	Level           string `json:"level"`
 }
 
+// makeNMASetConfigurationParameterOp makes an op that sets the given
+// parameters to their values one by one, in the order of their names. The
+// parameters are set all or none: when a parameter fails to be set, the
+// parameters set before it are restored to their old values, which a
+// previous nmaGetConfigurationParameterOp must have read.
 func makeNMASetConfigurationParameterOp(hosts []string,
-	username, dbName, sandbox, configParameter, value, level string,
+	username, dbName, sandbox string, configParameters map[string]string, level string,
 	password *string, useHTTPPassword bool) (nmaSetConfigurationParameterOp, error) {
 	op := nmaSetConfigurationParameterOp{}
 	op.name = "NMASetConfigurationParameterOp"
 	op.description = "Set configuration parameter value"
 	op.hosts = hosts
 	op.sandbox = sandbox
+	op.hostRequestBodies = make(map[string]string)
+	op.values = configParameters
+	op.username = username
+	op.dbName = dbName
+	op.level = level
+	op.password = password
+	op.useDBPassword = useHTTPPassword
 
-	err := op.setupRequestBody(username, dbName, configParameter, value, level, password, useHTTPPassword)
-	if err != nil {
-		return op, err
+	op.configParameters = maps.Keys(configParameters)
+	sort.Strings(op.configParameters)
+	for _, configParameter := range op.configParameters {
+		err := op.setupRequestBody(username, dbName, configParameter, configParameters[configParameter],
+			level, password, useHTTPPassword)
+		if err != nil {
+			return op, err
+		}
 	}
 
 	return op, nil
@@ -71,9 +109,17 @@ func (op *nmaSetConfigurationParameterOp) setupRequestBody(
 		return fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
 	}
 
-	op.hostRequestBody = string(dataBytes)
+	op.hostRequestBodies[configParameter] = string(dataBytes)
 
-	op.logger.Info("request data", "op name", op.name, "hostRequestBody", op.hostRequestBody)
+	// never log the value of a credential
+	if isSensitiveConfigParameter(configParameter) {
+		setConfigData.Value = maskedValue
+		dataBytes, err = json.Marshal(setConfigData)
+		if err != nil {
+			return fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+		}
+	}
+	op.logger.Info("request data", "op name", op.name, "hostRequestBody", string(dataBytes))
 
 	return nil
 }
@@ -82,13 +128,20 @@ func (op *nmaSetConfigurationParameterOp) setupClusterHTTPRequest(initiator stri
 	httpRequest := hostHTTPRequest{}
 	httpRequest.Method = PutMethod
 	httpRequest.buildNMAEndpoint("configuration/set")
-	httpRequest.RequestData = op.hostRequestBody
+	if len(op.configParameters) > 0 {
+		httpRequest.RequestData = op.hostRequestBodies[op.configParameters[0]]
+	}
 	op.clusterHTTPRequest.RequestCollection[initiator] = httpRequest
 
 	return nil
 }
 
 func (op *nmaSetConfigurationParameterOp) prepare(execContext *opEngineExecContext) error {
+	if op.validateNames {
+		if err := op.checkConfigParameters(execContext.configParameters); err != nil {
+			return err
+		}
+	}
 	// select an up host in the sandbox as the initiator
 	initiator, err := getInitiatorInSandbox(op.sandbox, op.hosts, execContext.upHostsToSandboxes)
 	if err != nil {
@@ -99,14 +152,88 @@ func (op *nmaSetConfigurationParameterOp) prepare(execContext *opEngineExecConte
 	return op.setupClusterHTTPRequest(op.initiator)
 }
 
+// execute sets the parameters one by one, and stops at the first parameter
+// that fails to be set. The parameters set before it are then restored to
+// their old values, so that the parameters are set all or none. The status
+// of each parameter tells what happened to it.
 func (op *nmaSetConfigurationParameterOp) execute(execContext *opEngineExecContext) error {
+	for i, configParameter := range op.configParameters {
+		err := op.sendRequest(execContext, op.hostRequestBodies[configParameter])
+		if err == nil {
+			continue
+		}
+		setErr := fmt.Errorf("fail to set configuration parameter %s: %w", configParameter, err)
+		restoreErr := op.restoreConfigParameters(execContext, op.configParameters[:i], configParameter)
+		execContext.configParameterStatuses = append(execContext.configParameterStatuses,
+			ConfigurationParameterStatus{ConfigParameter: configParameter, SetStatus: configParameterSetFailure, Error: err.Error()})
+		for _, skippedParameter := range op.configParameters[i+1:] {
+			execContext.configParameterStatuses = append(execContext.configParameterStatuses,
+				ConfigurationParameterStatus{ConfigParameter: skippedParameter, SetStatus: configParameterSetFailure,
+					Error: fmt.Sprintf("not set because configuration parameter %s failed to be set", configParameter)})
+		}
+		return errors.Join(setErr, restoreErr)
+	}
+	for _, configParameter := range op.configParameters {
+		execContext.configParameterStatuses = append(execContext.configParameterStatuses,
+			ConfigurationParameterStatus{ConfigParameter: configParameter, SetStatus: configParameterSetSuccess})
+	}
+	return nil
+}
+
+// sendRequest sends a request body to the initiator and checks its response
+func (op *nmaSetConfigurationParameterOp) sendRequest(execContext *opEngineExecContext, requestData string) error {
+	httpRequest := op.clusterHTTPRequest.RequestCollection[op.initiator]
+	httpRequest.RequestData = requestData
+	op.clusterHTTPRequest.RequestCollection[op.initiator] = httpRequest
 	if err := op.runExecute(execContext); err != nil {
 		return err
 	}
-
 	return op.processResult(execContext)
 }
 
+// restoreConfigParameters sets the parameters that were set back to their old
+// values, in the reverse order, after failedParameter failed to be set
+func (op *nmaSetConfigurationParameterOp) restoreConfigParameters(execContext *opEngineExecContext,
+	setParameters []string, failedParameter string) error {
+	var allErrs error
+	for i := len(setParameters) - 1; i >= 0; i-- {
+		configParameter := setParameters[i]
+		status := ConfigurationParameterStatus{ConfigParameter: configParameter, SetStatus: configParameterSetFailure,
+			Error: fmt.Sprintf("restored to its old value because configuration parameter %s failed to be set", failedParameter)}
+		err := op.restoreConfigParameter(execContext, configParameter)
+		if err != nil {
+			status.NotRestored = true
+			status.Error = fmt.Sprintf("set, but not restored to its old value after configuration parameter %s failed to be set: %s",
+				failedParameter, err)
+			allErrs = errors.Join(allErrs, fmt.Errorf("fail to restore configuration parameter %s: %w", configParameter, err))
+		}
+		execContext.configParameterStatuses = append(execContext.configParameterStatuses, status)
+	}
+	return allErrs
+}
+
+func (op *nmaSetConfigurationParameterOp) restoreConfigParameter(execContext *opEngineExecContext, configParameter string) error {
+	oldValue, ok := execContext.configParameterValues[configParameter]
+	if !ok {
+		return fmt.Errorf("the old value is unknown")
+	}
+	// the database does not return the value of a credential
+	if isMaskedValue(oldValue) {
+		return fmt.Errorf("the old value is masked by the database")
+	}
+	setConfigData := setConfigurationParameterData{}
+	setConfigData.sqlEndpointData = createSQLEndpointData(op.username, op.dbName, op.useDBPassword, op.password)
+	setConfigData.ConfigParameter = configParameter
+	setConfigData.Value = oldValue
+	setConfigData.Level = op.level
+	dataBytes, err := json.Marshal(setConfigData)
+	if err != nil {
+		return fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.logger.Info("restore the configuration parameter", "op name", op.name, "configParameter", configParameter)
+	return op.sendRequest(execContext, string(dataBytes))
+}
+
 func (op *nmaSetConfigurationParameterOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -129,3 +256,53 @@ func (op *nmaSetConfigurationParameterOp) processResult(_ *opEngineExecContext)
 
 	return allErrs
 }
+
+// checkConfigParameters rejects the parameters that are not in the listed
+// parameters, with a suggestion for a likely typo, and the values that do not
+// match the types of the parameters
+func (op *nmaSetConfigurationParameterOp) checkConfigParameters(listedParameters []ConfigurationParameter) error {
+	parametersByName := make(map[string]*ConfigurationParameter)
+	var names []string
+	for i := range listedParameters {
+		parametersByName[strings.ToLower(listedParameters[i].ConfigParameter)] = &listedParameters[i]
+		names = append(names, listedParameters[i].ConfigParameter)
+	}
+
+	var allErrs error
+	for _, configParameter := range op.configParameters {
+		listedParameter, found := parametersByName[strings.ToLower(configParameter)]
+		if !found {
+			err := fmt.Errorf("unknown configuration parameter %s", configParameter)
+			if closest, ok := util.FindClosestString(configParameter, names); ok {
+				err = fmt.Errorf("unknown configuration parameter %s, did you mean %s?", configParameter, closest)
+			}
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+		err := checkConfigParameterValue(listedParameter, op.values[configParameter])
+		allErrs = errors.Join(allErrs, err)
+	}
+	if allErrs != nil {
+		return fmt.Errorf("[%s] %w", op.name, allErrs)
+	}
+	return nil
+}
+
+// checkConfigParameterValue checks that the value can be set to a parameter
+// of an integer or a boolean type. "null" clears any parameter.
+func checkConfigParameterValue(configParameter *ConfigurationParameter, value string) error {
+	if strings.EqualFold(value, "null") {
+		return nil
+	}
+	switch strings.ToLower(configParameter.DataType) {
+	case "integer":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("configuration parameter %s must be an integer, but got %q", configParameter.ConfigParameter, value)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("configuration parameter %s must be a boolean, but got %q", configParameter.ConfigParameter, value)
+		}
+	}
+	return nil
+}
@@ -16,14 +16,19 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
 func TestNmaSetConfigurationParameterOp_SetupRequestBody(t *testing.T) {
-	op := &nmaSetConfigurationParameterOp{}
+	op := &nmaSetConfigurationParameterOp{hostRequestBodies: make(map[string]string)}
 
 	username := "config-test-user-op"
 	dbName := "config-test-db-op"
@@ -46,7 +51,7 @@ func TestNmaSetConfigurationParameterOp_SetupRequestBody(t *testing.T) {
 	expectedBytes, _ := json.Marshal(expectedData)
 	expectedRequestBody := string(expectedBytes)
 
-	assert.Equal(t, expectedRequestBody, op.hostRequestBody)
+	assert.Equal(t, expectedRequestBody, op.hostRequestBodies[configParameter])
 
 	err = op.setupRequestBody("", dbName, configParameter, value, level, &password, useDBPassword)
 	assert.Error(t, err)
@@ -57,3 +62,109 @@ func TestNmaSetConfigurationParameterOp_SetupRequestBody(t *testing.T) {
 	err = op.setupRequestBody(username, dbName, configParameter, value, level, nil, useDBPassword)
 	assert.Error(t, err)
 }
+
+func TestMakeNmaSetConfigurationParametersOp(t *testing.T) {
+	password := "config-test-password-op"
+	configParameters := map[string]string{"MaxClientSessions": "100", "EnableSSL": "1"}
+	op, err := makeNMASetConfigurationParameterOp([]string{"host1"}, "dbadmin", "test_db", "",
+		configParameters, "", &password, true)
+	assert.NoError(t, err)
+
+	// the parameters are set in the order of their names, with one request for each
+	assert.Equal(t, []string{"EnableSSL", "MaxClientSessions"}, op.configParameters)
+	assert.Len(t, op.hostRequestBodies, 2)
+	requestData := setConfigurationParameterData{}
+	err = json.Unmarshal([]byte(op.hostRequestBodies["MaxClientSessions"]), &requestData)
+	assert.NoError(t, err)
+	assert.Equal(t, "100", requestData.Value)
+}
+
+func TestCheckConfigurationParameters(t *testing.T) {
+	listedParameters := []ConfigurationParameter{
+		{ConfigParameter: "MaxClientSessions", DataType: "integer"},
+		{ConfigParameter: "EnableSSL", DataType: "boolean"},
+		{ConfigParameter: "DefaultIdleSessionTimeout", DataType: "varchar(128)"},
+	}
+	op := nmaSetConfigurationParameterOp{
+		configParameters: []string{"maxclientsessions", "DefaultIdleSessionTimeout", "EnableSSL"},
+		values:           map[string]string{"maxclientsessions": "100", "DefaultIdleSessionTimeout": "1 hour", "EnableSSL": "null"},
+	}
+	assert.NoError(t, op.checkConfigParameters(listedParameters))
+
+	// a typo gets a suggestion
+	op.configParameters = []string{"MaxClientSesions"}
+	op.values = map[string]string{"MaxClientSesions": "100"}
+	err := op.checkConfigParameters(listedParameters)
+	assert.ErrorContains(t, err, "unknown configuration parameter MaxClientSesions, did you mean MaxClientSessions?")
+
+	// the values must match the types
+	op.configParameters = []string{"MaxClientSessions", "EnableSSL"}
+	op.values = map[string]string{"MaxClientSessions": "many", "EnableSSL": "maybe"}
+	err = op.checkConfigParameters(listedParameters)
+	assert.ErrorContains(t, err, "configuration parameter MaxClientSessions must be an integer")
+	assert.ErrorContains(t, err, "configuration parameter EnableSSL must be a boolean")
+}
+
+func TestSetConfigurationParametersAllOrNone(t *testing.T) {
+	bundle := makeTestTLSBundle(t)
+	provider, err := NewPEMCertProvider(bundle.keyPEM, bundle.certPEM, bundle.caPEM)
+	assert.NoError(t, err)
+
+	// MaxClientSessions fails to be set
+	var sentValues []string
+	wrap := func(string, http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requestData := setConfigurationParameterData{}
+			body, _ := io.ReadAll(req.Body)
+			_ = json.Unmarshal(body, &requestData)
+			sentValues = append(sentValues, requestData.ConfigParameter+"="+requestData.Value)
+			statusCode := http.StatusOK
+			if requestData.ConfigParameter == "MaxClientSessions" {
+				statusCode = http.StatusInternalServerError
+			}
+			return &http.Response{StatusCode: statusCode, Header: http.Header{},
+				Body: io.NopCloser(strings.NewReader(`"ok"`))}, nil
+		})
+	}
+	runSetConfigOp := func(configParameters map[string]string) ([]ConfigurationParameterStatus, error) {
+		sentValues = nil
+		password := "password"
+		op, err := makeNMASetConfigurationParameterOp([]string{"host1"}, "dbadmin", "test_db", "",
+			configParameters, "", &password, true)
+		assert.NoError(t, err)
+		execContext := makeOpEngineExecContext(context.Background(), vlog.Printer{})
+		execContext.dispatcher.transports = makeTransportCache(&TransportPolicy{}, nil, wrap)
+		execContext.upHostsToSandboxes = map[string]string{"host1": ""}
+		execContext.configParameterValues = map[string]string{"EnableSSL": "0", "MaxClientSessions": "50"}
+		clusterOpEngine := makeClusterOpEngine([]clusterOp{&op}, &httpsCerts{provider: provider})
+		err = clusterOpEngine.runWithExecContext(vlog.Printer{}, &execContext)
+		return execContext.configParameterStatuses, err
+	}
+
+	// the parameter set before the failed one is restored, and the ones after it are not set
+	statuses, err := runSetConfigOp(map[string]string{"EnableSSL": "1", "MaxClientSessions": "100", "TempParameter": "1"})
+	assert.ErrorContains(t, err, "fail to set configuration parameter MaxClientSessions")
+	assert.Equal(t, []string{"EnableSSL=1", "MaxClientSessions=100", "EnableSSL=0"}, sentValues)
+	assert.Len(t, statuses, 3)
+	for _, status := range statuses {
+		assert.Equal(t, configParameterSetFailure, status.SetStatus)
+	}
+	assert.Equal(t, "EnableSSL", statuses[0].ConfigParameter)
+	assert.Contains(t, statuses[0].Error, "restored to its old value")
+	assert.Equal(t, "TempParameter", statuses[2].ConfigParameter)
+	assert.Contains(t, statuses[2].Error, "not set because configuration parameter MaxClientSessions failed to be set")
+
+	// a parameter whose old value is unknown cannot be restored
+	statuses, err = runSetConfigOp(map[string]string{"AWSRegion": "us-east-1", "MaxClientSessions": "100"})
+	assert.ErrorContains(t, err, "fail to restore configuration parameter AWSRegion: the old value is unknown")
+	assert.Contains(t, statuses[0].Error, "set, but not restored to its old value")
+
+	// all of the parameters are set when none fails
+	statuses, err = runSetConfigOp(map[string]string{"EnableSSL": "1", "TempParameter": "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"EnableSSL=1", "TempParameter=1"}, sentValues)
+	assert.Equal(t, []ConfigurationParameterStatus{
+		{ConfigParameter: "EnableSSL", SetStatus: configParameterSetSuccess},
+		{ConfigParameter: "TempParameter", SetStatus: configParameterSetSuccess},
+	}, statuses)
+}
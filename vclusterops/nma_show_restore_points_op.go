@@ -29,6 +29,10 @@ type nmaShowRestorePointsOp struct {
 	communalLocation        string
 	configurationParameters map[string]string
 	filterOptions           ShowRestorePointFilterOptions
+	// list the restore points of this sandbox instead of the main cluster
+	sandbox string
+	// the hosts that the op can run on when its host is unreachable
+	fallbackInitiators []string
 }
 
 // Optional arguments to list only restore points that
@@ -55,6 +59,7 @@ type showRestorePointsRequestData struct {
 	EndTimestamp     string            `json:"end_timestamp,omitempty"`
 	ArchiveID        string            `json:"archive_id,omitempty"`
 	ArchiveIndex     string            `json:"archive_index,omitempty"`
+	Sandbox          string            `json:"sandbox,omitempty"`
 }
 
 // This op is used to show restore points in a database
@@ -95,6 +100,7 @@ func (op *nmaShowRestorePointsOp) setupRequestBody() (map[string]string, error)
 		requestData.EndTimestamp = op.filterOptions.EndTimestamp
 		requestData.ArchiveID = op.filterOptions.ArchiveID
 		requestData.ArchiveIndex = op.filterOptions.ArchiveIndex
+		requestData.Sandbox = op.sandbox
 
 		dataBytes, err := json.Marshal(requestData)
 		if err != nil {
@@ -117,6 +123,18 @@ func (op *nmaShowRestorePointsOp) setupClusterHTTPRequest(hostRequestBodyMap map
 	return nil
 }
 
+func (op *nmaShowRestorePointsOp) setInitiator(initiator string) {
+	op.hosts = []string{initiator}
+}
+
+func (op *nmaShowRestorePointsOp) getInitiator() string {
+	return op.hosts[0]
+}
+
+func (op *nmaShowRestorePointsOp) getFallbackInitiators() []string {
+	return op.fallbackInitiators
+}
+
 func (op *nmaShowRestorePointsOp) prepare(execContext *opEngineExecContext) error {
 	hostRequestBodyMap, err := op.setupRequestBody()
 	if err != nil {
@@ -154,6 +172,27 @@ type RestorePoint struct {
 	VerticaVersion string `json:"vertica_version,omitempty"`
 }
 
+// restorePointList is the v1 response of the NMA restore-points endpoint
+type restorePointList []RestorePoint
+
+func (restorePointList) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
+// skipInvalidRestorePoints drops the restore points without an archive or an ID,
+// which cannot be selected, so that one bad entry does not fail the listing
+func (op *nmaShowRestorePointsOp) skipInvalidRestorePoints(host string, restorePoints restorePointList) []RestorePoint {
+	validRestorePoints := make([]RestorePoint, 0, len(restorePoints))
+	for i := range restorePoints {
+		if restorePoints[i].Archive == "" || restorePoints[i].ID == "" {
+			op.addWarning(host, "Skipping restore point %d in the response of host %s, which has no archive or ID", i, host)
+			continue
+		}
+		validRestorePoints = append(validRestorePoints, restorePoints[i])
+	}
+	return validRestorePoints
+}
+
 /*
 Sample response from the NMA restore-points endpoint:
 [
@@ -182,15 +221,15 @@ func (op *nmaShowRestorePointsOp) processResult(execContext *opEngineExecContext
 		op.logResponse(host, result)
 
 		if result.isPassing() {
-			var responseObj []RestorePoint
-			err := op.parseAndCheckResponse(host, result.content, &responseObj)
+			var responseObj restorePointList
+			err := op.parseAndValidateResponse(host, result.content, &responseObj)
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
 				continue
 			}
 
 			op.logger.PrintInfo("[%s] response: %v", op.name, result.content)
-			execContext.restorePoints = responseObj
+			execContext.SetRestorePoints(op.skipInvalidRestorePoints(host, responseObj))
 			return nil
 		}
 
@@ -150,12 +150,12 @@ func (op *nmaSpreadSecurityOp) setRuntimeParms(execContext *opEngineExecContext)
 	// that we copy the spread.conf from during start db.
 	hostsWithLatestCatalog := execContext.hostsWithLatestCatalog
 	if len(hostsWithLatestCatalog) == 0 {
-		return fmt.Errorf("could not find at least one host with the latest catalog")
+		return categorizeError(ErrCatalogMismatch, fmt.Errorf("could not find at least one host with the latest catalog"))
 	}
 	// Use only a primary host with the latest catalog as the sourceConfigHost
 	primaryHostsWithLatestCatalog := getPrimaryHostsWithLatestCatalog(&execContext.nmaVDatabase, hostsWithLatestCatalog, execContext)
 	if len(primaryHostsWithLatestCatalog) == 0 {
-		return fmt.Errorf("could not find at least one primary host with the latest catalog")
+		return categorizeError(ErrCatalogMismatch, fmt.Errorf("could not find at least one primary host with the latest catalog"))
 	}
 	op.hosts = []string{primaryHostsWithLatestCatalog[0]}
 	op.catalogPathMap = make(map[string]string, len(op.hosts))
@@ -0,0 +1,107 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type nmaStageCatalogOp struct {
+	scrutinizeOpBase
+	sizeLimitBytes int64
+}
+
+type stageCatalogRequestData struct {
+	CatalogPath    string `json:"catalog_path"`
+	SizeLimitBytes int64  `json:"size_limit_bytes"`
+}
+
+type stageCatalogResponseData struct {
+	Name      string `json:"name"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+func makeNMAStageCatalogOp(
+	id string,
+	hosts []string,
+	hostNodeNameMap, hostCatPathMap map[string]string,
+	sizeLimitBytes int64) (nmaStageCatalogOp, error) {
+	// base members
+	op := nmaStageCatalogOp{}
+	op.name = "NMAStageCatalogOp"
+	op.description = "Stage catalog snapshot"
+	op.hosts = hosts
+	// scrutinize members
+	op.id = id
+	op.batch = scrutinizeBatchNormal
+	op.hostNodeNameMap = hostNodeNameMap
+	op.hostCatPathMap = hostCatPathMap
+	op.httpMethod = PostMethod
+	op.urlSuffix = "/catalog"
+
+	// custom members
+	op.sizeLimitBytes = sizeLimitBytes
+
+	// the caller is responsible for making sure hosts and maps match up exactly
+	err := validateHostMaps(hosts, hostNodeNameMap, hostCatPathMap)
+	return op, err
+}
+
+func (op *nmaStageCatalogOp) setupRequestBody(hosts []string) error {
+	op.hostRequestBodyMap = make(map[string]string, len(hosts))
+	for _, host := range hosts {
+		stageCatalogData := stageCatalogRequestData{}
+		stageCatalogData.CatalogPath = op.hostCatPathMap[host]
+		stageCatalogData.SizeLimitBytes = op.sizeLimitBytes
+
+		dataBytes, err := json.Marshal(stageCatalogData)
+		if err != nil {
+			return fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+		}
+
+		op.hostRequestBodyMap[host] = string(dataBytes)
+	}
+
+	return nil
+}
+
+func (op *nmaStageCatalogOp) prepare(execContext *opEngineExecContext) error {
+	err := op.setupRequestBody(op.hosts)
+	if err != nil {
+		return err
+	}
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaStageCatalogOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaStageCatalogOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaStageCatalogOp) processResult(_ *opEngineExecContext) error {
+	fileList := make([]stageCatalogResponseData, 0)
+	return processStagedItemsResult(&op.scrutinizeOpBase, fileList)
+}
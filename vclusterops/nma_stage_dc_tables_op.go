@@ -22,10 +22,14 @@ import (
 
 type nmaStageDCTablesOp struct {
 	scrutinizeOpBase
+	dcAgeMaxHours int // The maximum age of DC table rows in hours to retrieve
+	dcAgeMinHours int // The minimum age of DC table rows in hours to retrieve
 }
 
 type stageDCTablesRequestData struct {
-	CatalogPath string `json:"catalog_path"`
+	CatalogPath   string `json:"catalog_path"`
+	DCAgeMaxHours int    `json:"dc_max_age_hours,omitempty"`
+	DCAgeMinHours int    `json:"dc_min_age_hours,omitempty"`
 }
 
 type stageDCTablesResponseData struct {
@@ -36,7 +40,8 @@ func makeNMAStageDCTablesOp(
 	id string,
 	hosts []string,
 	hostNodeNameMap map[string]string,
-	hostCatPathMap map[string]string) (nmaStageDCTablesOp, error) {
+	hostCatPathMap map[string]string,
+	dcAgeMaxHours, dcAgeMinHours int) (nmaStageDCTablesOp, error) {
 	// base members
 	op := nmaStageDCTablesOp{}
 	op.name = "NMAStageDCTablesOp"
@@ -51,6 +56,10 @@ func makeNMAStageDCTablesOp(
 	op.httpMethod = PostMethod
 	op.urlSuffix = "/data_collector"
 
+	// custom members
+	op.dcAgeMaxHours = dcAgeMaxHours
+	op.dcAgeMinHours = dcAgeMinHours
+
 	// the caller is responsible for making sure hosts and maps match up exactly
 	err := validateHostMaps(hosts, hostNodeNameMap, hostCatPathMap)
 	return op, err
@@ -61,6 +70,8 @@ func (op *nmaStageDCTablesOp) setupRequestBody(hosts []string) error {
 	for _, host := range hosts {
 		stageDCTablesData := stageDCTablesRequestData{}
 		stageDCTablesData.CatalogPath = op.hostCatPathMap[host]
+		stageDCTablesData.DCAgeMaxHours = op.dcAgeMaxHours
+		stageDCTablesData.DCAgeMinHours = op.dcAgeMinHours
 
 		dataBytes, err := json.Marshal(stageDCTablesData)
 		if err != nil {
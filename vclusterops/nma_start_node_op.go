@@ -19,6 +19,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
 )
 
 type nmaStartNodeOp struct {
@@ -27,6 +30,8 @@ type nmaStartNodeOp struct {
 	hostRequestBodyMap map[string]string
 	vdb                *VCoordinationDatabase
 	sandbox            bool
+	// node name -> extra vertica arguments merged into the start command of the node
+	startCommandOverrides map[string][]string
 }
 
 type startNodeRequestData struct {
@@ -63,14 +68,16 @@ func (op *nmaStartNodeOp) updateRequestBody(execContext *opEngineExecContext) er
 	// This case is used for certain operations (e.g., start_db, create_db) when the database is down,
 	// and we need to use the NMA catalog/database endpoint.
 	// Otherwise, we can use the startup command file from the HTTPS startup/commands endpoint when the database is up.
-	if execContext.startupCommandMap != nil {
+	if startupCommandMap, ok := execContext.StartupCommands(); ok {
 		// map {host: startCommand} e.g.,
 		// {ip1:[/opt/vertica/bin/vertica -D /data/practice_db/v_practice_db_node0001_catalog -C
 		// practice_db -n v_practice_db_node0001 -h 192.168.1.101 -p 5433 -P 4803 -Y ipv4]}
 		hostStartCommandMap := make(map[string][]string)
+		hostNodeNameMap := make(map[string]string)
 		if !op.sandbox {
 			for host, vnode := range op.vdb.HostNodeMap {
-				hoststartCommand, ok := execContext.startupCommandMap[vnode.Name]
+				hostNodeNameMap[host] = vnode.Name
+				hoststartCommand, ok := startupCommandMap[vnode.Name]
 				if ok {
 					hostStartCommandMap[host] = hoststartCommand
 				}
@@ -81,14 +88,15 @@ func (op *nmaStartNodeOp) updateRequestBody(execContext *opEngineExecContext) er
 			}
 			for _, vnode := range execContext.scNodesInfo {
 				op.hosts = append(op.hosts, vnode.Address)
-				hoststartCommand, ok := execContext.startupCommandMap[vnode.Name]
+				hostNodeNameMap[vnode.Address] = vnode.Name
+				hoststartCommand, ok := startupCommandMap[vnode.Name]
 				if ok {
 					hostStartCommandMap[vnode.Address] = hoststartCommand
 				}
 			}
 		}
 		for _, host := range op.hosts {
-			err := op.updateHostRequestBodyMapFromNodeStartCommand(host, hostStartCommandMap[host])
+			err := op.updateHostRequestBodyMapFromNodeStartCommand(host, hostNodeNameMap[host], hostStartCommandMap[host])
 			if err != nil {
 				return err
 			}
@@ -101,7 +109,7 @@ func (op *nmaStartNodeOp) updateRequestBody(execContext *opEngineExecContext) er
 				return fmt.Errorf("[%s] the bootstrap node (%s) is not found from the catalog editor information: %+v",
 					op.name, host, execContext.nmaVDatabase)
 			}
-			err := op.updateHostRequestBodyMapFromNodeStartCommand(host, node.StartCommand)
+			err := op.updateHostRequestBodyMapFromNodeStartCommand(host, node.Name, node.StartCommand)
 			if err != nil {
 				return err
 			}
@@ -110,7 +118,65 @@ func (op *nmaStartNodeOp) updateRequestBody(execContext *opEngineExecContext) er
 	return nil
 }
 
-func (op *nmaStartNodeOp) updateHostRequestBodyMapFromNodeStartCommand(host string, hostStartCommand []string) error {
+func isStartCommandFlag(arg string) bool {
+	return strings.HasPrefix(arg, "-")
+}
+
+// validateStartCommandOverrides returns an error if the overrides of a node
+// do not start with a flag, as a value cannot be merged without its flag
+func validateStartCommandOverrides(overrides map[string][]string) error {
+	for nodeName, args := range overrides {
+		if nodeName == "" {
+			return fmt.Errorf("the start command overrides must be given for a node name")
+		}
+		if len(args) > 0 && !isStartCommandFlag(args[0]) {
+			return fmt.Errorf("the start command overrides of node %s must start with a flag, but got %q", nodeName, args[0])
+		}
+	}
+	return nil
+}
+
+// mergeStartCommand returns a copy of the start command with the override
+// arguments merged in. The value of a flag that is already in the command is
+// replaced, and the other flags are appended. A value cannot start with "-",
+// as it would be taken as a flag.
+func mergeStartCommand(startCommand, args []string) []string {
+	merged := slices.Clone(startCommand)
+	for i := 0; i < len(args); i++ {
+		flag := args[i]
+		var value []string
+		if i+1 < len(args) && !isStartCommandFlag(args[i+1]) {
+			value = args[i+1 : i+2]
+			i++
+		}
+
+		// the first argument is the vertica binary
+		index := -1
+		if len(merged) > 1 {
+			if j := slices.Index(merged[1:], flag); j >= 0 {
+				index = j + 1
+			}
+		}
+		switch {
+		case index < 0:
+			merged = append(merged, flag)
+			merged = append(merged, value...)
+		case len(value) == 0:
+			// the flag is already set
+		case index+1 < len(merged) && !isStartCommandFlag(merged[index+1]):
+			merged[index+1] = value[0]
+		default:
+			merged = slices.Insert(merged, index+1, value[0])
+		}
+	}
+	return merged
+}
+
+func (op *nmaStartNodeOp) updateHostRequestBodyMapFromNodeStartCommand(host, nodeName string, hostStartCommand []string) error {
+	if overrides := op.startCommandOverrides[nodeName]; len(overrides) > 0 {
+		hostStartCommand = mergeStartCommand(hostStartCommand, overrides)
+		op.logger.Info("merged the start command overrides", "host", host, "node", nodeName, "startCommand", hostStartCommand)
+	}
 	startNodeData := startNodeRequestData{
 		StartCommand: hostStartCommand,
 		StartupConf:  op.startupConf,
@@ -144,7 +210,13 @@ func (op *nmaStartNodeOp) prepare(execContext *opEngineExecContext) error {
 
 	execContext.dispatcher.setup(op.hosts)
 
-	return op.setupClusterHTTPRequest(op.hosts)
+	err = op.setupClusterHTTPRequest(op.hosts)
+	if err != nil {
+		return err
+	}
+	// the NMA does not start a node again for a retried request
+	op.setIdempotencyKey(execContext)
+	return nil
 }
 
 func (op *nmaStartNodeOp) execute(execContext *opEngineExecContext) error {
@@ -164,7 +236,11 @@ type startNodeResponse struct {
 	ReturnCode int    `json:"return_code"`
 }
 
-func (op *nmaStartNodeOp) processResult(_ *opEngineExecContext) error {
+func (startNodeResponse) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
+func (op *nmaStartNodeOp) processResult(execContext *opEngineExecContext) error {
 	var allErrs error
 
 	for host, result := range op.clusterHTTPRequest.ResultCollection {
@@ -176,11 +252,12 @@ func (op *nmaStartNodeOp) processResult(_ *opEngineExecContext) error {
 			// 'return_code', 0 }
 
 			responseObj := startNodeResponse{}
-			err := op.parseAndCheckResponse(host, result.content, &responseObj)
+			err := op.parseAndValidateResponse(host, result.content, &responseObj)
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
 				continue
 			}
+			execContext.getNodeStartResult(host).DBLogPath = responseObj.DBLogPath
 
 			if responseObj.ReturnCode != 0 {
 				err = fmt.Errorf(`[%s] return_code should be 0 but got %d`, op.name, responseObj.ReturnCode)
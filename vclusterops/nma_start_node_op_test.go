@@ -1,11 +1,13 @@
 package vclusterops
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
@@ -22,7 +24,7 @@ func TestStartNodeOp(t *testing.T) {
 	certs := httpsCerts{}
 	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
 
-	execContext := makeOpEngineExecContext(vl)
+	execContext := makeOpEngineExecContext(context.Background(), vl)
 	clusterOpEngine.execContext = &execContext
 	execContext.nmaVDatabase = nmaVDatabase{}
 	execContext.nmaVDatabase.HostNodeMap = make(map[string]*nmaVNode)
@@ -44,3 +46,79 @@ func TestStartNodeOp(t *testing.T) {
 	assert.Equal(t, len(startNodeData.StartCommand), len(startCmd))
 	assert.Equal(t, startNodeData.StartupConf, startupConf)
 }
+
+func TestStartNodeOpWithSavedStartupCommands(t *testing.T) {
+	vl := vlog.Printer{}
+	hosts := []string{"host1"}
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	vdb.HostNodeMap[hosts[0]] = &VCoordinationNode{Name: "v_db_node0001", Address: hosts[0], State: util.NodeDownState}
+	startCmd := []string{"/opt/vertica/bin/vertica", "-D", "/data/db/v_db_node0001_catalog"}
+
+	// no UP host can serve the startup commands, so the commands saved
+	// when the node was stopped are used
+	startUpCommandOp, err := makeHTTPSStartUpCommandOp(false, "", nil, &vdb)
+	assert.NoError(t, err)
+	startUpCommandOp.savedStartupCommands = map[string][]string{"v_db_node0001": startCmd}
+	startNodeOp := makeNMAStartNodeOpWithVDB(hosts, "", &vdb)
+	startNodeOp.skipExecute = true
+	clusterOpEngine := makeClusterOpEngine([]clusterOp{&startUpCommandOp, &startNodeOp}, &httpsCerts{})
+
+	err = clusterOpEngine.run(context.Background(), vl)
+	assert.NoError(t, err)
+	assert.True(t, startUpCommandOp.isSkipExecute())
+	startNodeData := startNodeRequestData{}
+	err = json.Unmarshal([]byte(startNodeOp.hostRequestBodyMap[hosts[0]]), &startNodeData)
+	assert.NoError(t, err)
+	assert.Equal(t, startCmd, startNodeData.StartCommand)
+}
+
+func TestMergeStartCommand(t *testing.T) {
+	startCmd := []string{"/opt/vertica/bin/vertica", "-D", "/data/db/v_db_node0001_catalog", "-C", "db", "-Y", "ipv4"}
+
+	// the value of an existing flag is replaced, and new flags are appended
+	merged := mergeStartCommand(startCmd, []string{"-D", "/data/new db/v_db_node0001_catalog", "--debug", "-p", "5434"})
+	assert.Equal(t, []string{"/opt/vertica/bin/vertica", "-D", "/data/new db/v_db_node0001_catalog", "-C", "db", "-Y", "ipv4",
+		"--debug", "-p", "5434"}, merged)
+	// the stored command is not modified
+	assert.Equal(t, "/data/db/v_db_node0001_catalog", startCmd[2])
+
+	// a flag that is already set is not added twice
+	merged = mergeStartCommand([]string{"/opt/vertica/bin/vertica", "--debug"}, []string{"--debug"})
+	assert.Equal(t, []string{"/opt/vertica/bin/vertica", "--debug"}, merged)
+
+	// a value is added to a bare flag
+	merged = mergeStartCommand([]string{"/opt/vertica/bin/vertica", "-x", "-C", "db"}, []string{"-x", "1"})
+	assert.Equal(t, []string{"/opt/vertica/bin/vertica", "-x", "1", "-C", "db"}, merged)
+
+	assert.NoError(t, validateStartCommandOverrides(map[string][]string{"v_db_node0001": {"-D", "/data"}, "v_db_node0002": {"--debug"}}))
+	assert.ErrorContains(t, validateStartCommandOverrides(map[string][]string{"v_db_node0001": {"/data"}}),
+		"the start command overrides of node v_db_node0001 must start with a flag")
+	assert.ErrorContains(t, validateStartCommandOverrides(map[string][]string{"": {"--debug"}}), "must be given for a node name")
+}
+
+func TestStartNodeOpWithStartCommandOverrides(t *testing.T) {
+	vl := vlog.Printer{}
+	hosts := []string{"host1", "host2"}
+	op := makeNMAStartNodeOp(hosts, "")
+	op.skipExecute = true
+	// the overrides only apply to their node
+	op.startCommandOverrides = map[string][]string{"v_db_node0001": {"-D", "/data/new/v_db_node0001_catalog"}}
+	clusterOpEngine := makeClusterOpEngine([]clusterOp{&op}, &httpsCerts{})
+
+	execContext := makeOpEngineExecContext(context.Background(), vl)
+	execContext.nmaVDatabase.HostNodeMap = map[string]*nmaVNode{
+		"host1": {Name: "v_db_node0001", StartCommand: []string{"/opt/vertica/bin/vertica", "-D", "/data/db/v_db_node0001_catalog"}},
+		"host2": {Name: "v_db_node0002", StartCommand: []string{"/opt/vertica/bin/vertica", "-D", "/data/db/v_db_node0002_catalog"}},
+	}
+	err := clusterOpEngine.runWithExecContext(vl, &execContext)
+	assert.NoError(t, err)
+
+	expected := map[string]string{"host1": "/data/new/v_db_node0001_catalog", "host2": "/data/db/v_db_node0002_catalog"}
+	for host, catalogPath := range expected {
+		startNodeData := startNodeRequestData{}
+		err = json.Unmarshal([]byte(op.hostRequestBodyMap[host]), &startNodeData)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/opt/vertica/bin/vertica", "-D", catalogPath}, startNodeData.StartCommand)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// tlsCertsAction is a step of the rotation of the TLS certificates that the
+// node management agent and the HTTPS service read from the certificate
+// directory of a host
+type tlsCertsAction string
+
+const (
+	// write the new bundle beside the current one, without using it yet
+	tlsCertsStage tlsCertsAction = "stage"
+	// replace the current bundle with the staged one, keep the current one
+	// as a backup, and reload the TLS listeners of the agent
+	tlsCertsActivate tlsCertsAction = "activate"
+	// restore the backup and reload, which does nothing on a host whose
+	// bundle was not replaced
+	tlsCertsRollback tlsCertsAction = "rollback"
+	// remove the backup once the new bundle is known to be served
+	tlsCertsCleanup tlsCertsAction = "cleanup"
+)
+
+type tlsCertsRequestData struct {
+	PrivateKey    string `json:"private_key"`
+	Certificate   string `json:"certificate"`
+	CACertificate string `json:"ca_certificate"`
+}
+
+// nmaTLSCertsOp runs a step of the certificate rotation on the agent of
+// every host, including the hosts of the down nodes
+type nmaTLSCertsOp struct {
+	opBase
+	action          tlsCertsAction
+	hostRequestBody string
+}
+
+// makeNMATLSCertsOp creates the op of a rotation step. The bundle is only
+// sent to stage it, and is ignored by the other steps.
+func makeNMATLSCertsOp(hosts []string, action tlsCertsAction, bundle *tlsCertsRequestData) (nmaTLSCertsOp, error) {
+	op := nmaTLSCertsOp{}
+	op.name = "NMATLSCertsOp"
+	op.description = fmt.Sprintf("Run %s step of TLS certificate rotation", action)
+	op.hosts = hosts
+	op.action = action
+
+	if action == tlsCertsStage {
+		dataBytes, err := json.Marshal(bundle)
+		if err != nil {
+			return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+		}
+		op.hostRequestBody = string(dataBytes)
+	}
+
+	return op, nil
+}
+
+func (op *nmaTLSCertsOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildNMAEndpoint("tls/certs/" + string(op.action))
+		httpRequest.RequestData = op.hostRequestBody
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaTLSCertsOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaTLSCertsOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaTLSCertsOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// the successful response is a dictionary, e.g., {"detail": ""}
+		_, err := op.parseAndCheckMapResponse(host, result.content)
+		if err != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err))
+		}
+	}
+
+	return allErrs
+}
+
+func (op *nmaTLSCertsOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
@@ -0,0 +1,161 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// nmaUploadScrutinizeTarOp makes each host tar a batch and upload the tarball
+// to communal storage, as an alternative to nmaGetScrutinizeTarOp
+type nmaUploadScrutinizeTarOp struct {
+	scrutinizeOpBase
+	uploadLocation          string
+	configurationParameters map[string]string
+	useInitiator            bool
+}
+
+type uploadScrutinizeTarRequestData struct {
+	DestinationFilePath string            `json:"destination_file_path"`
+	Parameters          map[string]string `json:"parameters,omitempty"`
+}
+
+func makeNMAUploadScrutinizeTarOp(
+	id, batch string,
+	hosts []string,
+	hostNodeNameMap map[string]string,
+	uploadLocation string,
+	configurationParameters map[string]string) (nmaUploadScrutinizeTarOp, error) {
+	// base members
+	op := nmaUploadScrutinizeTarOp{}
+	op.name = "NMAUploadScrutinizeTarOp"
+	op.description = fmt.Sprintf("Create and upload tar files for batch %s", batch)
+	op.hosts = hosts
+
+	// scrutinize members
+	op.id = id
+	op.batch = batch
+	op.hostNodeNameMap = hostNodeNameMap
+	op.httpMethod = PostMethod
+	op.urlSuffix = "/upload"
+
+	// custom members
+	op.uploadLocation = strings.TrimSuffix(uploadLocation, "/")
+	op.configurationParameters = configurationParameters
+
+	// the caller is responsible for making sure hosts and maps match up exactly
+	err := validateHostMaps(hosts, hostNodeNameMap)
+	return op, err
+}
+
+// useSingleHost indicates that the tarball should only be uploaded from the first
+// up node
+func (op *nmaUploadScrutinizeTarOp) useSingleHost() {
+	op.useInitiator = true
+}
+
+func (op *nmaUploadScrutinizeTarOp) setupRequestBody(hosts []string) error {
+	op.hostRequestBodyMap = make(map[string]string, len(hosts))
+	for _, host := range hosts {
+		uploadData := uploadScrutinizeTarRequestData{}
+		// same layout as the local tarball: {location}/{id}/{node}-{batch}.tgz
+		uploadData.DestinationFilePath = fmt.Sprintf("%s/%s/%s-%s.tgz",
+			op.uploadLocation, op.id, op.hostNodeNameMap[host], op.batch)
+		uploadData.Parameters = op.configurationParameters
+
+		dataBytes, err := json.Marshal(uploadData)
+		if err != nil {
+			return fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+		}
+
+		op.hostRequestBodyMap[host] = string(dataBytes)
+	}
+
+	return nil
+}
+
+func (op *nmaUploadScrutinizeTarOp) prepare(execContext *opEngineExecContext) error {
+	// for the system table batch
+	if op.useInitiator {
+		upHosts, ok := execContext.UpHosts()
+		if !ok {
+			op.addWarning("", "no up hosts to upload system tables from, skipping the operation")
+			op.skipExecute = true
+			return nil
+		}
+
+		host := getInitiatorFromUpHosts(upHosts, op.hosts)
+		if host == "" {
+			op.addWarning("", "no up hosts among user specified hosts to upload system tables from, skipping the operation")
+			op.skipExecute = true
+			return nil
+		}
+
+		op.hosts = []string{host}
+	}
+
+	err := op.setupRequestBody(op.hosts)
+	if err != nil {
+		return err
+	}
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaUploadScrutinizeTarOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaUploadScrutinizeTarOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaUploadScrutinizeTarOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isPassing() {
+			op.logger.Info("Uploaded tarball",
+				"Host", host,
+				"Node", op.hostNodeNameMap[host],
+				"Batch", op.batch)
+		} else {
+			op.logger.Error(result.err, "Failed to upload tarball",
+				"Host", host,
+				"Node", op.hostNodeNameMap[host],
+				"Batch", op.batch)
+			if result.isInternalError() {
+				op.addWarning(host, "Failed to tar batch %s on host %s. Skipping.", op.batch, host)
+			} else {
+				err := fmt.Errorf("failed to upload tarball batch %s on host %s, details %w",
+					op.batch, host, result.err)
+				allErrs = errors.Join(allErrs, err)
+			}
+		}
+	}
+
+	return allErrs
+}
@@ -131,7 +131,7 @@ func (op *nmaVerticaVersionOp) prepareSandboxVers(execContext *opEngineExecConte
 	}
 	// Add Up main cluster hosts
 	if len(execContext.upHostsToSandboxes) == 0 {
-		return fmt.Errorf(`[%s] Cannot find any up hosts in OpEngineExecContext`, op.name)
+		return categorizeError(ErrNodeDown, fmt.Errorf(`[%s] Cannot find any up hosts in OpEngineExecContext`, op.name))
 	}
 	for h, sb := range execContext.upHostsToSandboxes {
 		if sb == "" {
@@ -203,12 +203,16 @@ func (op *nmaVerticaVersionOp) finalize(_ *opEngineExecContext) error {
 
 type nmaVerticaVersionOpResponse map[string]string
 
+func (nmaVerticaVersionOpResponse) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
 func (op *nmaVerticaVersionOp) parseAndCheckResponse(host, resultContent string) error {
 	// each result is a pair {"vertica_version": <vertica version string>}
 	// example result:
 	// {"vertica_version": "Vertica Analytic Database v12.0.3"}
 	var responseObj nmaVerticaVersionOpResponse
-	err := util.GetJSONLogErrors(resultContent, &responseObj, op.name, op.logger)
+	err := op.parseAndValidateResponse(host, resultContent, &responseObj)
 	if err != nil {
 		return err
 	}
@@ -42,6 +42,10 @@ type nodesInfo struct {
 	NodeList []NodeInfo `json:"node_list"`
 }
 
+func (nodesInfo) supportedVersions() []string {
+	return []string{HTTPVersion1}
+}
+
 // findHosts looks for hosts in a list of NodesInfo.
 // If found, return true; if not found, return false.
 func (info *nodesInfo) findHosts(hosts []string) bool {
@@ -0,0 +1,330 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	defaultWatchdogPollInterval       = 30 * time.Second
+	defaultWatchdogMaxRestartAttempts = 3
+	defaultWatchdogCoolDown           = 5 * time.Minute
+	watchdogEventBufferSize           = 64
+)
+
+// NodeWatchdogPolicy controls when the node watchdog restarts a DOWN node
+type NodeWatchdogPolicy struct {
+	// interval between two polls of the node states
+	PollInterval time.Duration
+	// the restart attempts of a node before the watchdog gives up on it, zero
+	// for no limit. The count is reset once the node is seen UP again.
+	MaxRestartAttempts int
+	// the minimum time between two restart attempts of the same node
+	CoolDown time.Duration
+}
+
+type NodeWatchdogEventType string
+
+const (
+	// the node states could not be polled
+	NodeWatchdogPollFailed NodeWatchdogEventType = "PollFailed"
+	// a node is seen DOWN for the first time since it was UP
+	NodeWatchdogNodeDown NodeWatchdogEventType = "NodeDown"
+	// a restart attempt of a node starts
+	NodeWatchdogRestarting NodeWatchdogEventType = "Restarting"
+	// a node was restarted and is UP
+	NodeWatchdogRestarted NodeWatchdogEventType = "Restarted"
+	// a restart attempt of a node failed
+	NodeWatchdogRestartFailed NodeWatchdogEventType = "RestartFailed"
+	// a node is still DOWN after the maximum restart attempts, and the
+	// watchdog does not restart it until it is seen UP again
+	NodeWatchdogGaveUp NodeWatchdogEventType = "GaveUp"
+)
+
+// NodeWatchdogEvent is sent on the event channel of the node watchdog
+type NodeWatchdogEvent struct {
+	Type NodeWatchdogEventType
+	Time time.Time
+	// the node that the event is about, empty for NodeWatchdogPollFailed
+	NodeName string
+	Host     string
+	// the restart attempt of the node, starting from 1
+	Attempt int
+	Err     error
+}
+
+// VNodeWatchdogOptions are the options of StartNodeWatchdog
+type VNodeWatchdogOptions struct {
+	/* part 1: basic db info */
+	DatabaseOptions
+
+	/* part 2: watchdog options */
+	Policy NodeWatchdogPolicy
+	// timeout for polling the states of the nodes that a restart attempt starts
+	StatePollingTimeout int
+	// names of the nodes that the watchdog never restarts, e.g., the nodes
+	// stopped on purpose. The watchdog also skips the nodes that TargetSelector
+	// does not select, like the hosts under maintenance.
+	ExcludedNodes []string
+}
+
+func VNodeWatchdogOptionsFactory() VNodeWatchdogOptions {
+	options := VNodeWatchdogOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	options.Policy = NodeWatchdogPolicy{
+		PollInterval:       defaultWatchdogPollInterval,
+		MaxRestartAttempts: defaultWatchdogMaxRestartAttempts,
+		CoolDown:           defaultWatchdogCoolDown,
+	}
+	options.StatePollingTimeout = util.DefaultStatePollingTimeout
+
+	return options
+}
+
+func (options *VNodeWatchdogOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandNodeWatchdog, logger)
+	if err != nil {
+		return err
+	}
+	if options.Policy.PollInterval <= 0 {
+		return fmt.Errorf("the poll interval of the node watchdog must be positive")
+	}
+	if options.Policy.MaxRestartAttempts < 0 || options.Policy.CoolDown < 0 {
+		return fmt.Errorf("the maximum restart attempts and the cool-down of the node watchdog must not be negative")
+	}
+	return nil
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VNodeWatchdogOptions) analyzeOptions() (err error) {
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return options.TargetSelector.resolveExcludedHosts(&options.DatabaseOptions)
+}
+
+func (options *VNodeWatchdogOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// NodeWatchdog polls the node states of a database in the background, and
+// restarts the nodes that go DOWN. It is created by StartNodeWatchdog.
+type NodeWatchdog struct {
+	events chan NodeWatchdogEvent
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	policy NodeWatchdogPolicy
+	// the nodes that the watchdog restarts
+	selector      TargetSelector
+	excludedNodes []string
+	log           vlog.Printer
+	now           func() time.Time
+	// returns the nodes of the database, with their states
+	fetchNodes func() (vHostNodeMap, error)
+	// starts the given nodes (node name -> host)
+	startNodes func(nodes map[string]string) error
+	// node name -> restart attempts since the node was last seen UP
+	restarts map[string]*nodeRestartState
+}
+
+type nodeRestartState struct {
+	attempts    int
+	lastAttempt time.Time
+	gaveUp      bool
+}
+
+// StartNodeWatchdog starts a watchdog that polls the node states at the
+// interval of the policy, and restarts the DOWN nodes with start_node. It
+// runs until Stop is called or the context of vcc is done. The caller must
+// read the events, which are sent in order and are not dropped, or the
+// watchdog blocks.
+func (vcc VClusterCommands) StartNodeWatchdog(options *VNodeWatchdogOptions) (*NodeWatchdog, error) {
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+	// the options may change while the watchdog runs
+	dbOptions := options.DatabaseOptions
+	statePollingTimeout := options.StatePollingTimeout
+
+	ctx, cancel := context.WithCancel(vcc.Context())
+	watchdogVcc := vcc.WithContext(ctx)
+	watchdog := makeNodeWatchdog(options.Policy, vcc.Log, cancel)
+	watchdog.selector = options.TargetSelector
+	watchdog.excludedNodes = slices.Clone(options.ExcludedNodes)
+	watchdog.fetchNodes = func() (vHostNodeMap, error) {
+		vdb := makeVCoordinationDatabase()
+		// the report only holds the latest poll
+		dbOptions.resetReport()
+		err := watchdogVcc.getVDBFromRunningDBIncludeSandbox(&vdb, &dbOptions, AnySandbox)
+		return vdb.HostNodeMap, err
+	}
+	watchdog.startNodes = func(nodes map[string]string) error {
+		startNodesOptions := VStartNodesOptionsFactory()
+		startNodesOptions.DatabaseOptions = dbOptions
+		startNodesOptions.Nodes = nodes
+		startNodesOptions.StatePollingTimeout = statePollingTimeout
+		_, err := watchdogVcc.VStartNodes(&startNodesOptions)
+		return err
+	}
+
+	go watchdog.run(ctx)
+	return watchdog, nil
+}
+
+func makeNodeWatchdog(policy NodeWatchdogPolicy, log vlog.Printer, cancel context.CancelFunc) *NodeWatchdog {
+	return &NodeWatchdog{
+		events:   make(chan NodeWatchdogEvent, watchdogEventBufferSize),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		policy:   policy,
+		log:      log,
+		now:      time.Now,
+		restarts: make(map[string]*nodeRestartState),
+	}
+}
+
+// Events returns the channel of the watchdog events. It is closed once the
+// watchdog stops.
+func (w *NodeWatchdog) Events() <-chan NodeWatchdogEvent {
+	return w.events
+}
+
+// Stop stops the watchdog, and waits for the current poll or restart to abort
+func (w *NodeWatchdog) Stop() {
+	w.cancel()
+	<-w.done
+}
+
+func (w *NodeWatchdog) run(ctx context.Context) {
+	defer close(w.done)
+	defer close(w.events)
+
+	ticker := time.NewTicker(w.policy.PollInterval)
+	defer ticker.Stop()
+	for {
+		w.checkNodes(ctx)
+		select {
+		case <-ctx.Done():
+			w.log.Info("node watchdog stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkNodes polls the node states once, and restarts the DOWN nodes that
+// the policy allows
+func (w *NodeWatchdog) checkNodes(ctx context.Context) {
+	hostNodeMap, err := w.fetchNodes()
+	if err != nil {
+		if ctx.Err() == nil {
+			w.emit(ctx, NodeWatchdogEvent{Type: NodeWatchdogPollFailed, Err: err})
+		}
+		return
+	}
+
+	hosts := maps.Keys(hostNodeMap)
+	slices.Sort(hosts)
+	now := w.now()
+	nodesToStart := make(map[string]string)
+	for _, host := range hosts {
+		vnode := hostNodeMap[host]
+		if !w.isWatched(vnode) {
+			delete(w.restarts, vnode.Name)
+			continue
+		}
+		if vnode.State != util.NodeDownState {
+			if vnode.State == util.NodeUpState {
+				delete(w.restarts, vnode.Name)
+			}
+			continue
+		}
+
+		state, ok := w.restarts[vnode.Name]
+		if !ok {
+			state = &nodeRestartState{}
+			w.restarts[vnode.Name] = state
+			w.emit(ctx, NodeWatchdogEvent{Type: NodeWatchdogNodeDown, NodeName: vnode.Name, Host: host})
+		}
+		if state.gaveUp {
+			continue
+		}
+		if w.policy.MaxRestartAttempts > 0 && state.attempts >= w.policy.MaxRestartAttempts {
+			state.gaveUp = true
+			w.emit(ctx, NodeWatchdogEvent{Type: NodeWatchdogGaveUp, NodeName: vnode.Name, Host: host,
+				Attempt: state.attempts})
+			continue
+		}
+		if state.attempts > 0 && now.Sub(state.lastAttempt) < w.policy.CoolDown {
+			continue
+		}
+
+		state.attempts++
+		state.lastAttempt = now
+		nodesToStart[vnode.Name] = host
+		w.emit(ctx, NodeWatchdogEvent{Type: NodeWatchdogRestarting, NodeName: vnode.Name, Host: host,
+			Attempt: state.attempts})
+	}
+	if len(nodesToStart) == 0 {
+		return
+	}
+
+	w.log.PrintInfo("[node watchdog] restarting nodes %v", nodesToStart)
+	err = w.startNodes(nodesToStart)
+	nodeNames := maps.Keys(nodesToStart)
+	slices.Sort(nodeNames)
+	for _, nodeName := range nodeNames {
+		event := NodeWatchdogEvent{Type: NodeWatchdogRestarted, NodeName: nodeName, Host: nodesToStart[nodeName],
+			Attempt: w.restarts[nodeName].attempts}
+		if err != nil {
+			event.Type = NodeWatchdogRestartFailed
+			event.Err = err
+		}
+		w.emit(ctx, event)
+	}
+}
+
+// isWatched returns true if the watchdog restarts the node when it is DOWN
+func (w *NodeWatchdog) isWatched(vnode *VCoordinationNode) bool {
+	return !slices.Contains(w.excludedNodes, vnode.Name) && w.selector.isNodeSelected(vnode)
+}
+
+// emit sends an event, unless the watchdog is stopped
+func (w *NodeWatchdog) emit(ctx context.Context, event NodeWatchdogEvent) {
+	event.Time = w.now()
+	select {
+	case w.events <- event:
+	case <-ctx.Done():
+	}
+}
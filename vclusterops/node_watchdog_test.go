@@ -0,0 +1,135 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestNodeWatchdogCheckNodes(t *testing.T) {
+	policy := NodeWatchdogPolicy{PollInterval: time.Minute, MaxRestartAttempts: 2, CoolDown: 5 * time.Minute}
+	watchdog := makeNodeWatchdog(policy, vlog.Printer{}, func() {})
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	watchdog.now = func() time.Time { return now }
+
+	nodeState := util.NodeDownState
+	watchdog.fetchNodes = func() (vHostNodeMap, error) {
+		return vHostNodeMap{
+			"192.168.1.101": {Name: "v_test_db_node0001", Address: "192.168.1.101", State: util.NodeUpState},
+			"192.168.1.102": {Name: "v_test_db_node0002", Address: "192.168.1.102", State: nodeState},
+		}, nil
+	}
+	var startErr error
+	var startedNodes []map[string]string
+	watchdog.startNodes = func(nodes map[string]string) error {
+		startedNodes = append(startedNodes, nodes)
+		return startErr
+	}
+	readEvents := func() (types []NodeWatchdogEventType) {
+		for len(watchdog.events) > 0 {
+			types = append(types, (<-watchdog.events).Type)
+		}
+		return types
+	}
+	ctx := context.Background()
+
+	// the DOWN node is restarted at once
+	startErr = fmt.Errorf("node did not come up")
+	watchdog.checkNodes(ctx)
+	assert.Equal(t, []NodeWatchdogEventType{NodeWatchdogNodeDown, NodeWatchdogRestarting, NodeWatchdogRestartFailed},
+		readEvents())
+	assert.Equal(t, []map[string]string{{"v_test_db_node0002": "192.168.1.102"}}, startedNodes)
+
+	// no restart during the cool-down
+	now = now.Add(time.Minute)
+	watchdog.checkNodes(ctx)
+	assert.Empty(t, readEvents())
+	assert.Len(t, startedNodes, 1)
+
+	// second attempt after the cool-down
+	now = now.Add(5 * time.Minute)
+	watchdog.checkNodes(ctx)
+	assert.Equal(t, []NodeWatchdogEventType{NodeWatchdogRestarting, NodeWatchdogRestartFailed}, readEvents())
+	assert.Len(t, startedNodes, 2)
+
+	// the watchdog gives up after the maximum attempts
+	now = now.Add(5 * time.Minute)
+	watchdog.checkNodes(ctx)
+	assert.Equal(t, []NodeWatchdogEventType{NodeWatchdogGaveUp}, readEvents())
+	now = now.Add(5 * time.Minute)
+	watchdog.checkNodes(ctx)
+	assert.Empty(t, readEvents())
+	assert.Len(t, startedNodes, 2)
+
+	// the attempts are reset once the node is UP again
+	nodeState = util.NodeUpState
+	watchdog.checkNodes(ctx)
+	assert.Empty(t, watchdog.restarts)
+	nodeState = util.NodeDownState
+	startErr = nil
+	watchdog.checkNodes(ctx)
+	assert.Equal(t, []NodeWatchdogEventType{NodeWatchdogNodeDown, NodeWatchdogRestarting, NodeWatchdogRestarted},
+		readEvents())
+	assert.Len(t, startedNodes, 3)
+
+	// a failed poll is reported
+	watchdog.fetchNodes = func() (vHostNodeMap, error) { return nil, fmt.Errorf("no UP nodes") }
+	watchdog.checkNodes(ctx)
+	assert.Equal(t, []NodeWatchdogEventType{NodeWatchdogPollFailed}, readEvents())
+}
+
+func TestNodeWatchdogExcludedNodes(t *testing.T) {
+	watchdog := makeNodeWatchdog(NodeWatchdogPolicy{PollInterval: time.Minute}, vlog.Printer{}, func() {})
+	watchdog.fetchNodes = func() (vHostNodeMap, error) {
+		return vHostNodeMap{
+			"192.168.1.101": {Name: "v_test_db_node0001", Address: "192.168.1.101", State: util.NodeDownState},
+			"192.168.1.102": {Name: "v_test_db_node0002", Address: "192.168.1.102", State: util.NodeDownState},
+			"192.168.1.103": {Name: "v_test_db_node0003", Address: "192.168.1.103", State: util.NodeDownState},
+		}, nil
+	}
+	var startedNodes []map[string]string
+	watchdog.startNodes = func(nodes map[string]string) error {
+		startedNodes = append(startedNodes, nodes)
+		return nil
+	}
+
+	// node0001 was stopped on purpose, and the host of node0002 is under maintenance
+	watchdog.excludedNodes = []string{"v_test_db_node0001"}
+	watchdog.selector.ExcludedHosts = []string{"192.168.1.102"}
+	watchdog.checkNodes(context.Background())
+	assert.Equal(t, []map[string]string{{"v_test_db_node0003": "192.168.1.103"}}, startedNodes)
+	assert.NotContains(t, watchdog.restarts, "v_test_db_node0001")
+	assert.NotContains(t, watchdog.restarts, "v_test_db_node0002")
+}
+
+func TestNodeWatchdogStop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	watchdog := makeNodeWatchdog(NodeWatchdogPolicy{PollInterval: time.Hour}, vlog.Printer{}, cancel)
+	watchdog.fetchNodes = func() (vHostNodeMap, error) { return vHostNodeMap{}, nil }
+	go watchdog.run(ctx)
+
+	watchdog.Stop()
+	// the event channel is closed once the watchdog stops
+	_, ok := <-watchdog.Events()
+	assert.False(t, ok)
+}
@@ -0,0 +1,136 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// OpInfo describes an op of a command to the hooks
+type OpInfo struct {
+	Name        string
+	Description string
+	// sorted hosts that the op sends requests to
+	Hosts []string
+	// endpoints of the requests keyed by host
+	Endpoints map[string]string
+	// query parameters of the requests keyed by host, with the values of the
+	// secrets masked
+	QueryParams map[string]map[string]string
+	// JSON bodies of the requests keyed by host, with the secrets masked, so a
+	// policy can check what the op asks for, e.g., a forced removal. The hosts
+	// whose request has no body are not in the map.
+	RequestBodies map[string]string
+	// whether the op sends a request that may change the cluster, i.e., any
+	// request other than a GET
+	Mutating bool
+	// time spent executing the op, only set when AfterOp is called
+	Duration time.Duration
+}
+
+// OpHook is called by the op engine around every op that sends requests, so
+// that library consumers can add custom logging, metrics, or policy checks
+// without changing the engine. The ops that are skipped at runtime, e.g.,
+// because there is no work to do, do not call the hooks.
+type OpHook interface {
+	// BeforeOp is called before the op sends its requests. Returning an error
+	// fails the command without running the op, e.g., to block an op that a
+	// policy does not allow.
+	BeforeOp(ctx context.Context, op OpInfo) error
+	// OnHostResult is called with the result of the last request that the op
+	// sent to each host, whether the op succeeds or not
+	OnHostResult(ctx context.Context, op OpInfo, result HostRequestReport)
+	// AfterOp is called after the op runs, with its error if it failed
+	AfterOp(ctx context.Context, op OpInfo, err error)
+}
+
+// NopOpHook implements OpHook with methods that do nothing. Embed it to
+// implement only some of the methods.
+type NopOpHook struct{}
+
+func (NopOpHook) BeforeOp(_ context.Context, _ OpInfo) error { return nil }
+
+func (NopOpHook) OnHostResult(_ context.Context, _ OpInfo, _ HostRequestReport) {}
+
+func (NopOpHook) AfterOp(_ context.Context, _ OpInfo, _ error) {}
+
+type opHooksKey struct{}
+
+// WithHooks returns a copy of vcc whose commands call the given hooks, after
+// the hooks that are already registered
+func (vcc VClusterCommands) WithHooks(hooks ...OpHook) VClusterCommands {
+	vcc.hooks = append(append([]OpHook(nil), vcc.hooks...), hooks...)
+	return vcc
+}
+
+// getOpHooks returns a copy of the hooks registered in ctx
+func getOpHooks(ctx context.Context) []OpHook {
+	hooks, _ := ctx.Value(opHooksKey{}).([]OpHook)
+	return append([]OpHook(nil), hooks...)
+}
+
+// getOpInfo describes the op after its requests are prepared
+func (op *opBase) getOpInfo() OpInfo {
+	info := OpInfo{Name: op.name, Description: op.description, Endpoints: make(map[string]string),
+		QueryParams: make(map[string]map[string]string), RequestBodies: make(map[string]string)}
+	for host, request := range op.clusterHTTPRequest.RequestCollection {
+		info.Hosts = append(info.Hosts, host)
+		info.Endpoints[host] = request.Endpoint
+		info.Mutating = info.Mutating || request.Method != GetMethod
+		if len(request.QueryParams) > 0 {
+			info.QueryParams[host] = redactQueryParams(request.QueryParams)
+		}
+		if request.RequestData != "" {
+			info.RequestBodies[host] = redactSecrets(request.RequestData)
+		}
+	}
+	sort.Strings(info.Hosts)
+	return info
+}
+
+// redactQueryParams copies the query parameters with the values of the secrets masked
+func redactQueryParams(queryParams map[string]string) map[string]string {
+	redacted := make(map[string]string, len(queryParams))
+	for key, value := range queryParams {
+		if isSensitiveQueryParam(key) {
+			value = maskedValue
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+// runBeforeOpHooks stops at the first hook that rejects the op
+func runBeforeOpHooks(ctx context.Context, hooks []OpHook, info OpInfo) error {
+	for _, hook := range hooks {
+		if err := hook.BeforeOp(ctx, info); err != nil {
+			return fmt.Errorf("[%s] the op is rejected by a hook: %w", info.Name, err)
+		}
+	}
+	return nil
+}
+
+func runAfterOpHooks(ctx context.Context, hooks []OpHook, info OpInfo, opReport *OpReport, err error) {
+	for _, hook := range hooks {
+		for _, hostReport := range opReport.Hosts {
+			hook.OnHostResult(ctx, info, hostReport)
+		}
+		hook.AfterOp(ctx, info, err)
+	}
+}
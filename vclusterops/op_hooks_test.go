@@ -0,0 +1,119 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type recordingOpHook struct {
+	NopOpHook
+	events []string
+}
+
+func (h *recordingOpHook) BeforeOp(_ context.Context, op OpInfo) error {
+	h.events = append(h.events, "before "+op.Name)
+	return nil
+}
+
+func (h *recordingOpHook) OnHostResult(_ context.Context, op OpInfo, result HostRequestReport) {
+	h.events = append(h.events, "result "+op.Name+" "+result.Host)
+}
+
+func (h *recordingOpHook) AfterOp(_ context.Context, op OpInfo, err error) {
+	h.events = append(h.events, "after "+op.Name)
+}
+
+type blockingOpHook struct {
+	NopOpHook
+}
+
+func (blockingOpHook) BeforeOp(_ context.Context, op OpInfo) error {
+	if op.Name == "blocked-op" {
+		return errors.New("not allowed in production")
+	}
+	return nil
+}
+
+func TestOpHooks(t *testing.T) {
+	op := mockOpWithResults{
+		mockOp:  makeMockOp(false),
+		results: map[string]hostHTTPResult{"host1": {host: "host1", statusCode: SuccessCode}},
+	}
+	skippedOp := makeMockOp(true)
+	recorder := recordingOpHook{}
+	vcc := VClusterCommands{}.WithHooks(&recorder)
+	options := DatabaseOptionsFactory()
+	opEngn := options.makeClusterOpEngine([]clusterOp{&op, &skippedOp})
+	err := opEngn.run(vcc.Context(), vlog.Printer{})
+	assert.NoError(t, err)
+	// the skipped op does not call the hooks
+	assert.Equal(t, []string{"before " + op.name, "result " + op.name + " host1", "after " + op.name}, recorder.events)
+
+	// a hook can reject an op, which is then not run
+	blockedOp := makeMockOp(false)
+	blockedOp.name = "blocked-op"
+	recorder = recordingOpHook{}
+	vcc = vcc.WithHooks(blockingOpHook{})
+	opEngn = options.makeClusterOpEngine([]clusterOp{&blockedOp})
+	err = opEngn.run(vcc.Context(), vlog.Printer{})
+	assert.ErrorContains(t, err, "not allowed in production")
+	assert.False(t, blockedOp.calledExecute)
+	// the hooks registered earlier are kept
+	assert.Equal(t, []string{"before blocked-op"}, recorder.events)
+}
+
+func TestOpHooksWithContext(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	hook := recordingOpHook{}
+
+	// the hooks are kept whatever the order of the setters
+	for _, vcc := range []VClusterCommands{
+		VClusterCommands{}.WithHooks(&hook).WithContext(ctx),
+		VClusterCommands{}.WithContext(ctx).WithHooks(&hook),
+	} {
+		assert.Len(t, getOpHooks(vcc.Context()), 1)
+		assert.Equal(t, "value", vcc.Context().Value(ctxKey{}))
+	}
+
+	// a context derived from the one of the commands does not repeat the hooks
+	vcc := VClusterCommands{}.WithHooks(&hook)
+	vcc = vcc.WithContext(vcc.Context())
+	assert.Len(t, getOpHooks(vcc.Context()), 1)
+}
+
+func TestOpInfoRequests(t *testing.T) {
+	op := opBase{name: "test-op"}
+	op.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{
+		"host1": {Method: PostMethod, Endpoint: "nodes/delete",
+			QueryParams: map[string]string{"force": "true", "password": "secret"},
+			RequestData: `{"force_delete": true, "db_password": "secret"}`},
+		"host2": {Method: GetMethod, Endpoint: "nodes"},
+	}
+	info := op.getOpInfo()
+	assert.True(t, info.Mutating)
+	// a policy can see what the op asks for, without the secrets
+	assert.Equal(t, map[string]map[string]string{"host1": {"force": "true", "password": maskedValue}}, info.QueryParams)
+	assert.Len(t, info.RequestBodies, 1)
+	assert.Contains(t, info.RequestBodies["host1"], `"force_delete": true`)
+	assert.NotContains(t, info.RequestBodies["host1"], "secret")
+}
@@ -0,0 +1,139 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+const (
+	opDurationMetric          = "vcluster_op_duration_seconds"
+	opsMetric                 = "vcluster_ops_total"
+	opErrorsMetric            = "vcluster_op_errors_total"
+	hostRequestsMetric        = "vcluster_host_requests_total"
+	hostRequestDurationMetric = "vcluster_host_request_duration_seconds"
+	hostRequestRetriesMetric  = "vcluster_host_request_retries_total"
+)
+
+// MetricsRegistry creates the collectors of the op engine metrics. The library
+// does not depend on a metrics client, so the callers implement it over their
+// own registry. With Prometheus, NewCounterVec can register and return a
+// prometheus.CounterVec wrapped as
+//
+//	func (c promCounter) Add(v float64, labels ...string) { c.WithLabelValues(labels...).Add(v) }
+//
+// and NewHistogramVec a prometheus.HistogramVec wrapped the same way.
+type MetricsRegistry interface {
+	NewCounterVec(name, help string, labelNames []string) (MetricCounterVec, error)
+	NewHistogramVec(name, help string, labelNames []string) (MetricHistogramVec, error)
+}
+
+// MetricCounterVec is a counter partitioned by label values
+type MetricCounterVec interface {
+	Add(value float64, labelValues ...string)
+}
+
+// MetricHistogramVec is a histogram partitioned by label values
+type MetricHistogramVec interface {
+	Observe(value float64, labelValues ...string)
+}
+
+// OpMetricsHook is an OpHook that records the metrics of the ops and their
+// requests in a MetricsRegistry:
+//   - vcluster_op_duration_seconds{op}: histogram of the time spent running the ops
+//   - vcluster_ops_total{op}, vcluster_op_errors_total{op}: ops run and failed
+//   - vcluster_host_requests_total{op,host,status_code}: requests sent to each host
+//   - vcluster_host_request_duration_seconds{op}: histogram of the request latencies
+//   - vcluster_host_request_retries_total{op,host}: requests sent again to each host
+type OpMetricsHook struct {
+	NopOpHook
+	opDuration          MetricHistogramVec
+	ops                 MetricCounterVec
+	opErrors            MetricCounterVec
+	hostRequests        MetricCounterVec
+	hostRequestDuration MetricHistogramVec
+	hostRequestRetries  MetricCounterVec
+}
+
+// NewOpMetricsHook creates the collectors of the op engine metrics in registry
+func NewOpMetricsHook(registry MetricsRegistry) (*OpMetricsHook, error) {
+	hook := OpMetricsHook{}
+	counters := []struct {
+		collector *MetricCounterVec
+		name      string
+		help      string
+		labels    []string
+	}{
+		{&hook.ops, opsMetric, "Number of ops run by the vcluster op engine", []string{"op"}},
+		{&hook.opErrors, opErrorsMetric, "Number of ops that failed in the vcluster op engine", []string{"op"}},
+		{&hook.hostRequests, hostRequestsMetric, "Number of requests sent to each host by the vcluster ops",
+			[]string{"op", "host", "status_code"}},
+		{&hook.hostRequestRetries, hostRequestRetriesMetric, "Number of requests sent again to each host by the vcluster ops",
+			[]string{"op", "host"}},
+	}
+	for _, c := range counters {
+		collector, err := registry.NewCounterVec(c.name, c.help, c.labels)
+		if err != nil {
+			return nil, fmt.Errorf("fail to create metric %s: %w", c.name, err)
+		}
+		*c.collector = collector
+	}
+
+	histograms := []struct {
+		collector *MetricHistogramVec
+		name      string
+		help      string
+	}{
+		{&hook.opDuration, opDurationMetric, "Time spent running the ops of the vcluster op engine"},
+		{&hook.hostRequestDuration, hostRequestDurationMetric, "Time spent waiting for the responses of the hosts"},
+	}
+	for _, h := range histograms {
+		collector, err := registry.NewHistogramVec(h.name, h.help, []string{"op"})
+		if err != nil {
+			return nil, fmt.Errorf("fail to create metric %s: %w", h.name, err)
+		}
+		*h.collector = collector
+	}
+	return &hook, nil
+}
+
+// WithMetrics returns a copy of vcc whose commands record the op engine
+// metrics in registry
+func (vcc VClusterCommands) WithMetrics(registry MetricsRegistry) (VClusterCommands, error) {
+	hook, err := NewOpMetricsHook(registry)
+	if err != nil {
+		return vcc, err
+	}
+	return vcc.WithHooks(hook), nil
+}
+
+func (hook *OpMetricsHook) OnHostResult(_ context.Context, op OpInfo, result HostRequestReport) {
+	hook.hostRequests.Add(1, op.Name, result.Host, strconv.Itoa(result.StatusCode))
+	hook.hostRequestDuration.Observe(result.Duration.Seconds(), op.Name)
+	if result.RetryCount > 0 {
+		hook.hostRequestRetries.Add(float64(result.RetryCount), op.Name, result.Host)
+	}
+}
+
+func (hook *OpMetricsHook) AfterOp(_ context.Context, op OpInfo, err error) {
+	hook.ops.Add(1, op.Name)
+	if err != nil {
+		hook.opErrors.Add(1, op.Name)
+	}
+	hook.opDuration.Observe(op.Duration.Seconds(), op.Name)
+}
@@ -0,0 +1,96 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// fakeMetricsRegistry keeps the values of the metrics keyed by "name{labels}"
+type fakeMetricsRegistry struct {
+	values  map[string]float64
+	failing string
+}
+
+type fakeMetric struct {
+	registry *fakeMetricsRegistry
+	name     string
+}
+
+func (m fakeMetric) Add(value float64, labelValues ...string) {
+	m.registry.values[m.name+"{"+strings.Join(labelValues, ",")+"}"] += value
+}
+
+func (m fakeMetric) Observe(value float64, labelValues ...string) {
+	m.Add(value, labelValues...)
+}
+
+func (r *fakeMetricsRegistry) NewCounterVec(name, _ string, _ []string) (MetricCounterVec, error) {
+	if name == r.failing {
+		return nil, errors.New("duplicate metric")
+	}
+	return fakeMetric{registry: r, name: name}, nil
+}
+
+func (r *fakeMetricsRegistry) NewHistogramVec(name, _ string, _ []string) (MetricHistogramVec, error) {
+	return fakeMetric{registry: r, name: name}, nil
+}
+
+func TestOpMetricsHook(t *testing.T) {
+	registry := fakeMetricsRegistry{values: make(map[string]float64)}
+	vcc, err := VClusterCommands{}.WithMetrics(&registry)
+	assert.NoError(t, err)
+
+	okOp := mockOpWithResults{
+		mockOp: makeMockOp(false),
+		results: map[string]hostHTTPResult{
+			"host1": {host: "host1", statusCode: SuccessCode, duration: time.Second, retryCount: 2},
+			"host2": {host: "host2", statusCode: SuccessCode, duration: 2 * time.Second},
+		},
+	}
+	failingOp := mockFailingOp{mockOpWithResults{
+		mockOp:  makeMockOp(false),
+		results: map[string]hostHTTPResult{"host1": {host: "host1", statusCode: InternalErrorCode}},
+	}}
+	failingOp.name = "failing-op"
+	options := DatabaseOptionsFactory()
+	opEngn := options.makeClusterOpEngine([]clusterOp{&okOp, &failingOp})
+	err = opEngn.run(vcc.Context(), vlog.Printer{})
+	assert.Error(t, err)
+
+	values := registry.values
+	assert.Equal(t, 1.0, values[opsMetric+"{"+okOp.name+"}"])
+	assert.Equal(t, 1.0, values[opsMetric+"{failing-op}"])
+	assert.Zero(t, values[opErrorsMetric+"{"+okOp.name+"}"])
+	assert.Equal(t, 1.0, values[opErrorsMetric+"{failing-op}"])
+	assert.Equal(t, 1.0, values[hostRequestsMetric+"{"+okOp.name+",host1,200}"])
+	assert.Equal(t, 1.0, values[hostRequestsMetric+"{failing-op,host1,500}"])
+	assert.Equal(t, 2.0, values[hostRequestRetriesMetric+"{"+okOp.name+",host1}"])
+	assert.Equal(t, 3.0, values[hostRequestDurationMetric+"{"+okOp.name+"}"])
+	_, ok := values[opDurationMetric+"{failing-op}"]
+	assert.True(t, ok)
+
+	// a collector that cannot be created fails the hook
+	registry = fakeMetricsRegistry{values: make(map[string]float64), failing: opErrorsMetric}
+	_, err = NewOpMetricsHook(&registry)
+	assert.ErrorContains(t, err, "fail to create metric "+opErrorsMetric)
+}
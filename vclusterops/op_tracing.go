@@ -0,0 +1,102 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	runSpanName = "vcluster.run"
+	// attributes of the spans
+	idempotencyKeyAttribute = "vcluster.idempotency_key"
+	opDescriptionAttribute  = "vcluster.op.description"
+	opSkippedAttribute      = "vcluster.op.skipped"
+	hostAttribute           = "server.address"
+	methodAttribute         = "http.request.method"
+	endpointAttribute       = "url.path"
+	statusCodeAttribute     = "http.response.status_code"
+)
+
+// Tracer starts the spans of the op engine: one span per run of a command,
+// a child span per op, and a child span of the op per request sent to a host.
+// The library does not depend on a tracing client, so the callers implement
+// it over their own. With OpenTelemetry, Start can call the Start method of a
+// trace.Tracer, and Inject can call
+//
+//	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+//
+// so that the NMA and Vertica logs of a request can be correlated with its span.
+type Tracer interface {
+	// Start starts a span as a child of the span in ctx, if any, and returns
+	// a copy of ctx that carries the new span
+	Start(ctx context.Context, name string) (context.Context, Span)
+	// Inject writes the trace context of the span in ctx into the headers of
+	// a request, e.g., the traceparent header
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is a unit of work started by a Tracer
+type Span interface {
+	SetAttribute(key string, value any)
+	// End ends the span, with the error of the work if it failed
+	End(err error)
+}
+
+type nopTracer struct{}
+
+func (nopTracer) Start(ctx context.Context, _ string) (context.Context, Span) { return ctx, nopSpan{} }
+
+func (nopTracer) Inject(_ context.Context, _ http.Header) {}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttribute(_ string, _ any) {}
+
+func (nopSpan) End(_ error) {}
+
+type tracerKey struct{}
+
+// WithTracer returns a copy of vcc whose commands trace their ops and
+// requests with tracer
+func (vcc VClusterCommands) WithTracer(tracer Tracer) VClusterCommands {
+	vcc.tracer = tracer
+	return vcc
+}
+
+// getTracer returns the tracer registered in ctx, or a tracer that does nothing
+func getTracer(ctx context.Context) Tracer {
+	if tracer, ok := ctx.Value(tracerKey{}).(Tracer); ok {
+		return tracer
+	}
+	return nopTracer{}
+}
+
+func startRequestSpan(ctx context.Context, host string, request *hostHTTPRequest) (context.Context, Span) {
+	ctx, span := getTracer(ctx).Start(ctx, request.Method+" "+request.Endpoint)
+	span.SetAttribute(hostAttribute, host)
+	span.SetAttribute(methodAttribute, request.Method)
+	span.SetAttribute(endpointAttribute, request.Endpoint)
+	return ctx, span
+}
+
+func endRequestSpan(span Span, result *hostHTTPResult) {
+	if result.statusCode != 0 {
+		span.SetAttribute(statusCodeAttribute, result.statusCode)
+	}
+	span.End(result.err)
+}
@@ -0,0 +1,142 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type spanParentKey struct{}
+
+type recordedSpan struct {
+	name       string
+	parent     string
+	attributes map[string]any
+	ended      bool
+	err        error
+}
+
+func (s *recordedSpan) SetAttribute(key string, value any) { s.attributes[key] = value }
+
+func (s *recordedSpan) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+// recordingTracer keeps the started spans, and injects the name of the span
+// in ctx as the trace context
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (tracer *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent, _ := ctx.Value(spanParentKey{}).(string)
+	span := &recordedSpan{name: name, parent: parent, attributes: make(map[string]any)}
+	tracer.spans = append(tracer.spans, span)
+	return context.WithValue(ctx, spanParentKey{}, name), span
+}
+
+func (tracer *recordingTracer) Inject(ctx context.Context, header http.Header) {
+	parent, _ := ctx.Value(spanParentKey{}).(string)
+	header.Set("traceparent", parent)
+}
+
+func TestOpEngineTracing(t *testing.T) {
+	okOp := makeMockOp(false)
+	skippedOp := makeMockOp(true)
+	skippedOp.name = "skipped-op"
+	failingOp := mockFailingOp{mockOpWithResults{mockOp: makeMockOp(false)}}
+	failingOp.name = "failing-op"
+
+	tracer := recordingTracer{}
+	vcc := VClusterCommands{}.WithTracer(&tracer)
+	options := DatabaseOptionsFactory()
+	opEngn := options.makeClusterOpEngine([]clusterOp{&okOp, &skippedOp, &failingOp})
+	err := opEngn.run(vcc.Context(), vlog.Printer{})
+	assert.Error(t, err)
+
+	// one span for the run, with a child span per op
+	assert.Len(t, tracer.spans, 4)
+	runSpan := tracer.spans[0]
+	assert.Equal(t, runSpanName, runSpan.name)
+	assert.Equal(t, "", runSpan.parent)
+	assert.Equal(t, opEngn.execContext.idempotencyKey, runSpan.attributes[idempotencyKeyAttribute])
+	assert.ErrorIs(t, runSpan.err, err)
+	for i, op := range []clusterOp{&okOp, &skippedOp, &failingOp} {
+		span := tracer.spans[i+1]
+		assert.Equal(t, op.getName(), span.name)
+		assert.Equal(t, runSpanName, span.parent)
+		assert.True(t, span.ended)
+	}
+	assert.Equal(t, true, tracer.spans[2].attributes[opSkippedAttribute])
+	assert.NoError(t, tracer.spans[1].err)
+	assert.ErrorContains(t, tracer.spans[3].err, "failing-op")
+
+	// the commands do not trace without a tracer
+	_, ok := getTracer(context.Background()).(nopTracer)
+	assert.True(t, ok)
+}
+
+func TestTracerWithContext(t *testing.T) {
+	tracer := recordingTracer{}
+	// the tracer is kept whatever the order of the setters
+	for _, vcc := range []VClusterCommands{
+		VClusterCommands{}.WithTracer(&tracer).WithContext(context.Background()),
+		VClusterCommands{}.WithContext(context.Background()).WithTracer(&tracer),
+	} {
+		assert.Equal(t, &tracer, getTracer(vcc.Context()))
+	}
+}
+
+func TestRequestTracing(t *testing.T) {
+	tracer := recordingTracer{}
+	ctx := VClusterCommands{}.WithTracer(&tracer).Context()
+	ctx, _ = tracer.Start(ctx, "test-op")
+
+	// nothing listens on the port, so the request fails without a status code
+	adapter := makeHTTPAdapter(vlog.Printer{})
+	adapter.host = "127.0.0.1"
+	password := "secret"
+	request := hostHTTPRequest{Method: GetMethod, Password: &password, Timeout: 1}
+	request.buildHTTPSEndpoint("nodes")
+	resultChannel := make(chan hostHTTPResult, 1)
+	adapter.sendRequest(ctx, &request, resultChannel)
+	result := <-resultChannel
+	assert.Error(t, result.err)
+
+	span := tracer.spans[1]
+	assert.Equal(t, "GET "+request.Endpoint, span.name)
+	assert.Equal(t, "test-op", span.parent)
+	assert.Equal(t, "127.0.0.1", span.attributes[hostAttribute])
+	assert.Equal(t, request.Endpoint, span.attributes[endpointAttribute])
+	assert.NotContains(t, span.attributes, statusCodeAttribute)
+	assert.Equal(t, result.err, span.err)
+
+	// the status code of a response is recorded
+	ctx, span2 := startRequestSpan(ctx, "host1", &request)
+	endRequestSpan(span2, &hostHTTPResult{statusCode: InternalErrorCode})
+	assert.Equal(t, InternalErrorCode, tracer.spans[2].attributes[statusCodeAttribute])
+
+	// the trace context of the request span is sent in the headers
+	header := http.Header{}
+	getTracer(ctx).Inject(ctx, header)
+	assert.Equal(t, "GET "+request.Endpoint, header.Get("traceparent"))
+}
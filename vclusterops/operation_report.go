@@ -0,0 +1,352 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// HostRequestReport describes the last request that an op sent to a host
+type HostRequestReport struct {
+	Host string
+	// HTTP status code of the response, 0 if no response was received
+	StatusCode int
+	// time spent waiting for the response
+	Duration time.Duration
+	// number of times the request was sent again after the first attempt,
+	// e.g., by ops that poll a host until it reaches some state
+	RetryCount int
+	// the failure of the request, nil if the request succeeded
+	Error *HostError
+}
+
+// HostError is the failure of a request that an op sent to a host
+type HostError struct {
+	OpName string
+	Host   string
+	// HTTP status code of the response, 0 if no response was received
+	StatusCode int
+	// body of the response with the secrets redacted, empty if no response was received
+	Body string
+	Err  error
+}
+
+func (e *HostError) Error() string {
+	return fmt.Sprintf("[%s] request to host %s failed with status code %d: %v", e.OpName, e.Host, e.StatusCode, redactError(e.Err))
+}
+
+func (e *HostError) Unwrap() error {
+	return e.Err
+}
+
+// OpReport describes the requests that a single op sent to the hosts
+type OpReport struct {
+	OpName string
+	// the host that the op ran on, for the ops that need a single initiator
+	Initiator string
+	// reports of each host, sorted by host
+	Hosts []HostRequestReport
+}
+
+// OpWarning is a problem that an op worked around instead of failing, e.g.,
+// a host was skipped or the cluster lease check was ignored
+type OpWarning struct {
+	OpName string
+	// the host that the warning is about, empty if it is not specific to a host
+	Host    string
+	Message string
+}
+
+// OperationReport collects the per-host request details of every op that
+// a command ran, in the order the ops ran. It gives callers visibility into
+// slow hosts even when the command eventually succeeded.
+type OperationReport struct {
+	Ops []OpReport
+	// warnings of all ops, kept apart from errors so callers can surface
+	// them without digging through the logs
+	Warnings []OpWarning
+	// the hosts that the pre-flight probe found unreachable, and that the
+	// command proceeded without
+	SkippedHosts []string
+	// the ops whose changes were rolled back after a later op failed
+	RolledBackOps []string
+	// the outcome of each target of the last run of a command that can partly
+	// succeed, sorted by target, see TargetOutcome
+	Outcomes []TargetOutcome
+	// the overall status of the last run of a command that can partly
+	// succeed, empty for the other commands
+	Status OperationStatus
+}
+
+// OperationStatus is the overall outcome of a command that acts on several targets
+type OperationStatus string
+
+const (
+	OperationSuccess        OperationStatus = "Success"
+	OperationPartialSuccess OperationStatus = "PartialSuccess"
+	OperationFailure        OperationStatus = "Failure"
+)
+
+// TargetOutcome is the outcome of a command on one of its targets: a host for
+// VStartNodes, and a configuration parameter for VSetConfigurationParameters
+type TargetOutcome struct {
+	Target    string
+	Succeeded bool
+	// why the command failed on the target, nil if it succeeded
+	Err error
+}
+
+// PartialSuccessError is returned by the commands that succeed on some of their
+// targets but not on the others. The outcome of each target is in the
+// OperationReport of the options.
+type PartialSuccessError struct {
+	SucceededTargets []string
+	FailedTargets    []string
+	Err              error
+}
+
+func (e *PartialSuccessError) Error() string {
+	return fmt.Sprintf("succeeded on %v but failed on %v: %v", e.SucceededTargets, e.FailedTargets, e.Err)
+}
+
+func (e *PartialSuccessError) Unwrap() error {
+	return e.Err
+}
+
+// GetHostReports returns the reports of all requests sent to the given host
+func (report *OperationReport) GetHostReports(host string) []HostRequestReport {
+	var hostReports []HostRequestReport
+	for i := range report.Ops {
+		for _, hostReport := range report.Ops[i].Hosts {
+			if hostReport.Host == host {
+				hostReports = append(hostReports, hostReport)
+			}
+		}
+	}
+	return hostReports
+}
+
+// GetSlowestRequest returns the name of the op and the report of the request
+// that took the longest time. It returns false if no request was sent.
+func (report *OperationReport) GetSlowestRequest() (opName string, hostReport HostRequestReport, found bool) {
+	for i := range report.Ops {
+		for _, r := range report.Ops[i].Hosts {
+			if !found || r.Duration > hostReport.Duration {
+				opName, hostReport, found = report.Ops[i].OpName, r, true
+			}
+		}
+	}
+	return opName, hostReport, found
+}
+
+// FailedTargets returns the sorted targets that the last run of a command failed on
+func (report *OperationReport) FailedTargets() []string {
+	var targets []string
+	for _, outcome := range report.Outcomes {
+		if !outcome.Succeeded {
+			targets = append(targets, outcome.Target)
+		}
+	}
+	return targets
+}
+
+// setOutcomes replaces the outcomes of the last run, and derives its status
+func (report *OperationReport) setOutcomes(outcomes []TargetOutcome) {
+	slices.SortFunc(outcomes, func(a, b TargetOutcome) int { return strings.Compare(a.Target, b.Target) })
+	report.Outcomes = outcomes
+	failedCount := len(report.FailedTargets())
+	switch {
+	case failedCount == 0:
+		report.Status = OperationSuccess
+	case failedCount == len(outcomes):
+		report.Status = OperationFailure
+	default:
+		report.Status = OperationPartialSuccess
+	}
+}
+
+// partialSuccessError returns err as a PartialSuccessError when the last run
+// succeeded on some of its targets
+func (report *OperationReport) partialSuccessError(err error) error {
+	if err == nil || report.Status != OperationPartialSuccess {
+		return err
+	}
+	partialErr := &PartialSuccessError{FailedTargets: report.FailedTargets(), Err: err}
+	for _, outcome := range report.Outcomes {
+		if outcome.Succeeded {
+			partialErr.SucceededTargets = append(partialErr.SucceededTargets, outcome.Target)
+		}
+	}
+	return partialErr
+}
+
+// hostErrorsOf returns the failures of the requests sent to the host by the
+// ops from the given index, joined in the order the ops ran, or nil if none failed
+func (report *OperationReport) hostErrorsOf(host string, firstOp int) error {
+	var allErrs error
+	for i := firstOp; i < len(report.Ops); i++ {
+		for _, hostErr := range report.Ops[i].hostErrors() {
+			if hostErr.Host == host {
+				allErrs = errors.Join(allErrs, hostErr)
+			}
+		}
+	}
+	return allErrs
+}
+
+// GetInitiator returns the host that the last run of the given op ran on, for
+// the ops that need a single initiator. It can differ from the selected
+// initiator when the op fell back to another host. It returns false if the op
+// did not run or does not need an initiator.
+func (report *OperationReport) GetInitiator(opName string) (string, bool) {
+	for i := len(report.Ops) - 1; i >= 0; i-- {
+		if report.Ops[i].OpName == opName && report.Ops[i].Initiator != "" {
+			return report.Ops[i].Initiator, true
+		}
+	}
+	return "", false
+}
+
+// HostErrors returns the failures of all requests, in the order the ops ran
+func (report *OperationReport) HostErrors() []*HostError {
+	var hostErrors []*HostError
+	for i := range report.Ops {
+		hostErrors = append(hostErrors, report.Ops[i].hostErrors()...)
+	}
+	return hostErrors
+}
+
+// FailedHosts returns the sorted hosts that at least one request failed on
+func (report *OperationReport) FailedHosts() []string {
+	failedHosts := make(map[string]bool)
+	for _, hostErr := range report.HostErrors() {
+		failedHosts[hostErr.Host] = true
+	}
+	return sortedKeys(failedHosts)
+}
+
+// SucceededHosts returns the sorted hosts that all requests succeeded on
+func (report *OperationReport) SucceededHosts() []string {
+	succeededHosts := make(map[string]bool)
+	for i := range report.Ops {
+		for _, hostReport := range report.Ops[i].Hosts {
+			succeededHosts[hostReport.Host] = true
+		}
+	}
+	for _, hostErr := range report.HostErrors() {
+		delete(succeededHosts, hostErr.Host)
+	}
+	return sortedKeys(succeededHosts)
+}
+
+func sortedKeys(hostSet map[string]bool) []string {
+	hosts := maps.Keys(hostSet)
+	slices.Sort(hosts)
+	return hosts
+}
+
+func (opReport *OpReport) hostErrors() []*HostError {
+	var hostErrors []*HostError
+	for _, hostReport := range opReport.Hosts {
+		if hostReport.Error != nil {
+			hostErrors = append(hostErrors, hostReport.Error)
+		}
+	}
+	return hostErrors
+}
+
+func (report *OperationReport) addOpReport(opReport OpReport) {
+	if len(opReport.Hosts) == 0 {
+		return
+	}
+	report.Ops = append(report.Ops, opReport)
+}
+
+func (report *OperationReport) addWarnings(warnings []OpWarning) {
+	report.Warnings = append(report.Warnings, warnings...)
+}
+
+// getOpReport builds the report from the results of the last requests of the op
+func (op *opBase) getOpReport() OpReport {
+	opReport := OpReport{OpName: op.name}
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		hostReport := HostRequestReport{
+			Host:       host,
+			StatusCode: result.statusCode,
+			Duration:   result.duration,
+			RetryCount: result.retryCount,
+		}
+		if !result.isPassing() && !op.expectedFailureHosts[host] {
+			hostReport.Error = &HostError{
+				OpName:     op.name,
+				Host:       host,
+				StatusCode: result.statusCode,
+				Body:       redactSecrets(result.content),
+				Err:        result.err,
+			}
+		}
+		opReport.Hosts = append(opReport.Hosts, hostReport)
+	}
+	sort.Slice(opReport.Hosts, func(i, j int) bool {
+		return opReport.Hosts[i].Host < opReport.Hosts[j].Host
+	})
+	return opReport
+}
+
+// hostFailuresError attaches the failures of the hosts to the error of an op
+// without changing its message, so callers can get them through errors.As
+type hostFailuresError struct {
+	err        error
+	hostErrors []*HostError
+}
+
+// withHostErrors returns err itself if no host failed
+func withHostErrors(err error, hostErrors []*HostError) error {
+	if len(hostErrors) == 0 {
+		return err
+	}
+	return &hostFailuresError{err: err, hostErrors: hostErrors}
+}
+
+func (e *hostFailuresError) Error() string {
+	return e.err.Error()
+}
+
+func (e *hostFailuresError) Unwrap() []error {
+	errs := []error{e.err}
+	for _, hostErr := range e.hostErrors {
+		errs = append(errs, hostErr)
+	}
+	return errs
+}
+
+// addWarning logs a warning and keeps it so that it is returned to the caller
+func (op *opBase) addWarning(host, msg string, v ...any) {
+	message := fmt.Sprintf(msg, v...)
+	op.logger.PrintWarning("%s", message)
+	op.warnings = append(op.warnings, OpWarning{OpName: op.name, Host: host, Message: message})
+}
+
+func (op *opBase) getWarnings() []OpWarning {
+	return op.warnings
+}
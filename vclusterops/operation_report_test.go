@@ -0,0 +1,166 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+func TestOperationStatus(t *testing.T) {
+	report := OperationReport{}
+	report.setOutcomes([]TargetOutcome{{Target: "192.168.1.102", Succeeded: true}, {Target: "192.168.1.101", Succeeded: true}})
+	assert.Equal(t, OperationSuccess, report.Status)
+	assert.Equal(t, "192.168.1.101", report.Outcomes[0].Target)
+	assert.Empty(t, report.FailedTargets())
+	assert.NoError(t, report.partialSuccessError(nil))
+
+	runErr := errors.New("fail to restart node")
+	report.setOutcomes([]TargetOutcome{
+		{Target: "192.168.1.101", Succeeded: true},
+		{Target: "192.168.1.102", Err: runErr},
+	})
+	assert.Equal(t, OperationPartialSuccess, report.Status)
+	assert.Equal(t, []string{"192.168.1.102"}, report.FailedTargets())
+	err := report.partialSuccessError(runErr)
+	var partialErr *PartialSuccessError
+	assert.True(t, errors.As(err, &partialErr))
+	assert.Equal(t, []string{"192.168.1.101"}, partialErr.SucceededTargets)
+	assert.Equal(t, []string{"192.168.1.102"}, partialErr.FailedTargets)
+	assert.ErrorIs(t, err, runErr)
+
+	report.setOutcomes([]TargetOutcome{{Target: "192.168.1.101", Err: runErr}})
+	assert.Equal(t, OperationFailure, report.Status)
+	assert.Equal(t, runErr, report.partialSuccessError(runErr))
+}
+
+func TestStartNodesOutcomes(t *testing.T) {
+	options := VStartNodesOptionsFactory()
+	options.Nodes = map[string]string{"v_db_node0001": "192.168.1.101", "v_db_node0002": "192.168.1.102"}
+	// the ops of a previous run are not considered
+	options.report.addOpReport(OpReport{OpName: "NMAStartNodeOp", Hosts: []HostRequestReport{
+		{Host: "192.168.1.101", Error: &HostError{OpName: "NMAStartNodeOp", Host: "192.168.1.101", Err: errors.New("old error")}},
+	}})
+	firstOp := len(options.report.Ops)
+	options.report.addOpReport(OpReport{OpName: "HTTPSPollNodeStateOp", Hosts: []HostRequestReport{
+		{Host: "192.168.1.101", StatusCode: SuccessCode},
+		{Host: "192.168.1.102", Error: &HostError{OpName: "HTTPSPollNodeStateOp", Host: "192.168.1.102", Err: errors.New("timeout")}},
+	}})
+	results := []NodeStartResult{
+		{Host: "192.168.1.101", State: util.NodeUpState},
+		{Host: "192.168.1.102", State: util.NodeDownState},
+	}
+	options.setOutcomes(results, firstOp, errors.New("fail to restart node"))
+	report := options.GetOperationReport()
+	assert.Equal(t, OperationPartialSuccess, report.Status)
+	assert.True(t, report.Outcomes[0].Succeeded)
+	assert.ErrorContains(t, report.Outcomes[1].Err, "timeout")
+	assert.NotContains(t, report.Outcomes[1].Err.Error(), "old error")
+
+	// the rerun only starts the failed node
+	_, err := VClusterCommands{}.VRerunFailedStartNodes(&options)
+	assert.Error(t, err)
+	assert.Equal(t, map[string]string{"v_db_node0002": "192.168.1.102"}, options.Nodes)
+
+	options = VStartNodesOptionsFactory()
+	_, err = VClusterCommands{}.VRerunFailedStartNodes(&options)
+	assert.ErrorContains(t, err, "cannot rerun start nodes before the nodes are started once")
+}
+
+func TestSetConfigurationParametersOutcomes(t *testing.T) {
+	options := VSetConfigurationParameterOptionsFactory()
+	options.ConfigParameters = map[string]string{"MaxClientSessions": "100", "UnknownParameter": "1", "EnableSSL": "1"}
+	options.setOutcomes([]ConfigurationParameterStatus{
+		{ConfigParameter: "MaxClientSessions", SetStatus: configParameterSetSuccess},
+		{ConfigParameter: "UnknownParameter", SetStatus: "Failure", Error: "unknown parameter"},
+		{ConfigParameter: "EnableSSL", SetStatus: configParameterSetSuccess},
+	}, errors.New("fail to set UnknownParameter"))
+	report := options.GetOperationReport()
+	assert.Equal(t, OperationPartialSuccess, report.Status)
+	assert.Equal(t, []string{"UnknownParameter"}, report.FailedTargets())
+	assert.EqualError(t, report.Outcomes[2].Err, "unknown parameter")
+
+	// the rerun only sets the failed parameter
+	_, err := VClusterCommands{}.VRerunFailedConfigurationParameters(&options)
+	assert.Error(t, err)
+	assert.Equal(t, map[string]string{"UnknownParameter": "1"}, options.ConfigParameters)
+
+	// all of the parameters fail when the run fails before setting them
+	options = VSetConfigurationParameterOptionsFactory()
+	options.ConfigParameter = "MaxClientSessions"
+	options.setOutcomes(nil, errors.New("no up hosts"))
+	report = options.GetOperationReport()
+	assert.Equal(t, OperationFailure, report.Status)
+	assert.Equal(t, []string{"MaxClientSessions"}, report.FailedTargets())
+}
+
+func TestReportOfReusedOptions(t *testing.T) {
+	bundle := makeTestTLSBundle(t)
+	provider, err := NewPEMCertProvider(bundle.keyPEM, bundle.certPEM, bundle.caPEM)
+	assert.NoError(t, err)
+
+	statusCode := http.StatusInternalServerError
+	options := VReplicationStatusDatabaseFactory()
+	options.DBName = "test_db"
+	options.RawHosts = []string{"192.168.1.101"}
+	options.TransactionID = 45035996273704962
+	options.CertProvider = provider
+	options.WrapTransport = func(_ string, _ http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(_ *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: statusCode, Header: http.Header{},
+				Body: io.NopCloser(strings.NewReader(`{"transaction_id": 45035996273704962, "status": "running"}`))}, nil
+		})
+	}
+
+	vcc := VClusterCommands{}
+	_, err = vcc.VGetReplicationStatus(&options)
+	assert.Error(t, err)
+	report := options.GetOperationReport()
+	assert.Equal(t, []string{"192.168.1.101"}, report.FailedHosts())
+
+	// the report only holds the second run
+	statusCode = http.StatusOK
+	_, err = vcc.VGetReplicationStatus(&options)
+	assert.NoError(t, err)
+	report = options.GetOperationReport()
+	assert.Len(t, report.Ops, 1)
+	assert.Empty(t, report.FailedHosts())
+	assert.Equal(t, []string{"192.168.1.101"}, report.SucceededHosts())
+}
+
+func TestExpectedFailuresAreNotHostFailures(t *testing.T) {
+	var runningHosts []string
+	op, err := makeHTTPSProbeOp([]string{"192.168.1.101", "192.168.1.102"}, false, "", nil, &runningHosts)
+	assert.NoError(t, err)
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: SUCCESS, statusCode: http.StatusOK, host: "192.168.1.101"},
+		"192.168.1.102": {status: FAILURE, statusCode: http.StatusUnauthorized, host: "192.168.1.102",
+			err: errors.New("unauthorized")},
+	}
+	assert.NoError(t, op.processResult(nil))
+	assert.ElementsMatch(t, []string{"192.168.1.101", "192.168.1.102"}, runningHosts)
+
+	report := OperationReport{}
+	report.addOpReport(op.getOpReport())
+	assert.Empty(t, report.FailedHosts())
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.102"}, report.SucceededHosts())
+}
@@ -0,0 +1,257 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// Plan queues several commands that run one after another against the same
+// database, e.g., revive, then start, then install packages. The steps share
+// the hosts, the credentials and the certificates of Plan.Options, so the
+// hosts are resolved once, and the reports of all steps are consolidated.
+// The steps also share the connections to the hosts, which are configured by
+// the transport policy, the proxy policy and the transport wrapper of
+// Plan.Options.
+type Plan struct {
+	// options shared by all of the steps. A step uses its own value of an
+	// option when it is set, and the value in Options otherwise.
+	Options DatabaseOptions
+	// by default the plan stops at the first failed step. If set, the plan
+	// runs the remaining steps instead, and returns all of the errors.
+	ContinueOnFailure bool
+	// optional, called when a step starts, and when it ends or is skipped
+	OnProgress func(progress PlanProgress)
+
+	steps []planStep
+}
+
+// PlanStepState is the state of a step of a plan
+type PlanStepState string
+
+const (
+	PlanStepRunning   PlanStepState = "Running"
+	PlanStepSucceeded PlanStepState = "Succeeded"
+	PlanStepFailed    PlanStepState = "Failed"
+	PlanStepSkipped   PlanStepState = "Skipped"
+)
+
+// PlanProgress tells that a step of a plan changed its state
+type PlanProgress struct {
+	// 1-based position of the step in the plan
+	Step       int
+	TotalSteps int
+	Name       string
+	State      PlanStepState
+	// the error of a failed step
+	Err error
+}
+
+type planStep struct {
+	name    string
+	options *DatabaseOptions
+	run     func(vcc VClusterCommands) error
+}
+
+// PlanStepResult is the outcome of a step of a plan
+type PlanStepResult struct {
+	Name string
+	// the error of the step, nil if it succeeded or was skipped
+	Err error
+	// true if the step did not run because a previous step failed
+	Skipped bool
+	// request details of the ops that the step ran
+	Report OperationReport
+}
+
+// PlanReport is the consolidated report of all of the steps of a plan, in
+// the order they were queued
+type PlanReport struct {
+	Steps []PlanStepResult
+}
+
+// Failed returns true if any step of the plan failed
+func (report *PlanReport) Failed() bool {
+	for i := range report.Steps {
+		if report.Steps[i].Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func MakePlan(options *DatabaseOptions) Plan {
+	return Plan{Options: *options}
+}
+
+// AddStep queues a step. options must be the DatabaseOptions embedded in the
+// options of the command that run calls, so that the plan can fill in the
+// shared options before the step runs.
+func (plan *Plan) AddStep(name string, options *DatabaseOptions, run func(vcc VClusterCommands) error) {
+	plan.steps = append(plan.steps, planStep{name: name, options: options, run: run})
+}
+
+func (plan *Plan) AddReviveDatabase(options *VReviveDatabaseOptions) {
+	plan.AddStep(commandReviveDB, &options.DatabaseOptions, func(vcc VClusterCommands) error {
+		_, _, err := vcc.VReviveDatabase(options)
+		return err
+	})
+}
+
+func (plan *Plan) AddStartDatabase(options *VStartDatabaseOptions) {
+	plan.AddStep(commandStartDB, &options.DatabaseOptions, func(vcc VClusterCommands) error {
+		_, err := vcc.VStartDatabase(options)
+		return err
+	})
+}
+
+func (plan *Plan) AddStopDatabase(options *VStopDatabaseOptions) {
+	plan.AddStep(commandStopDB, &options.DatabaseOptions, func(vcc VClusterCommands) error {
+		return vcc.VStopDatabase(options)
+	})
+}
+
+func (plan *Plan) AddSaveRestorePoint(options *VSaveRestorePointOptions) {
+	plan.AddStep(commandSaveRestorePoint, &options.DatabaseOptions, func(vcc VClusterCommands) error {
+		return vcc.VSaveRestorePoint(options)
+	})
+}
+
+func (plan *Plan) AddInstallPackages(options *VInstallPackagesOptions) {
+	plan.AddStep(commandInstallPackages, &options.DatabaseOptions, func(vcc VClusterCommands) error {
+		_, err := vcc.VInstallPackages(options)
+		return err
+	})
+}
+
+func (plan *Plan) AddSetConfigurationParameter(options *VSetConfigurationParameterOptions) {
+	plan.AddStep(commandSetConfigurationParameter, &options.DatabaseOptions, func(vcc VClusterCommands) error {
+		return vcc.VSetConfigurationParameters(options)
+	})
+}
+
+// reportProgress tells the caller that a step changed its state
+func (plan *Plan) reportProgress(index int, state PlanStepState, err error) {
+	if plan.OnProgress == nil {
+		return
+	}
+	plan.OnProgress(PlanProgress{Step: index + 1, TotalSteps: len(plan.steps),
+		Name: plan.steps[index].name, State: state, Err: err})
+}
+
+// resolveHosts resolves the shared hosts once for all of the steps
+func (plan *Plan) resolveHosts() (err error) {
+	if len(plan.Options.RawHosts) == 0 {
+		return nil
+	}
+	plan.Options.Hosts, err = plan.Options.resolveRawHosts(plan.Options.RawHosts)
+	return err
+}
+
+// applySharedOptions fills in the options that the step does not set
+func (plan *Plan) applySharedOptions(options *DatabaseOptions, transports *transportCache) {
+	shared := &plan.Options
+	if len(options.RawHosts) == 0 && len(options.Hosts) == 0 {
+		// the commands resolve their raw hosts, which are the resolved
+		// addresses of the shared hosts so they are not resolved again
+		options.RawHosts = util.CopySlice(shared.Hosts)
+		options.Hosts = util.CopySlice(shared.Hosts)
+		options.IPv6 = shared.IPv6
+	}
+	if options.DBName == "" {
+		options.DBName = shared.DBName
+	}
+	if options.UserName == "" {
+		options.UserName = shared.UserName
+	}
+	// a step that authenticates in its own way does not take any of the
+	// shared credentials, as a bearer token cannot be set with a password
+	if options.Password == nil && options.CredentialProvider == nil && !options.hasBearerToken() {
+		options.Password = shared.Password
+		options.CredentialProvider = shared.CredentialProvider
+		options.BearerToken = shared.BearerToken
+		options.RefreshBearerToken = shared.RefreshBearerToken
+	}
+	if options.Key == "" && options.Cert == "" {
+		options.Key = shared.Key
+		options.Cert = shared.Cert
+		options.CaCert = shared.CaCert
+	}
+	if options.CertProvider == nil {
+		options.CertProvider = shared.CertProvider
+	}
+	if options.ConfigPath == "" {
+		options.ConfigPath = shared.ConfigPath
+	}
+	if options.LogPath == "" {
+		options.LogPath = shared.LogPath
+	}
+	// a step that wraps its own transports opens its own connections
+	if options.WrapTransport == nil {
+		options.sharedTransports = transports
+	}
+}
+
+// VRunPlan runs the steps of the plan in order. It returns the consolidated
+// report of the steps, and the errors of the failed steps.
+func (vcc VClusterCommands) VRunPlan(plan *Plan) (report PlanReport, err error) {
+	err = plan.resolveHosts()
+	if err != nil {
+		return report, err
+	}
+
+	// the steps reuse the connections to the hosts until the end of the plan
+	wrapTransport := plan.Options.WrapTransport
+	if wrapTransport == nil {
+		wrapTransport = getTransportWrapper(vcc.Context())
+	}
+	transports := makeTransportCache(&plan.Options.TransportPolicy, &plan.Options.ProxyPolicy, wrapTransport)
+	defer transports.close()
+
+	var allErrs error
+	failed := false
+	for i, step := range plan.steps {
+		result := PlanStepResult{Name: step.name}
+		if failed && !plan.ContinueOnFailure {
+			result.Skipped = true
+			report.Steps = append(report.Steps, result)
+			plan.reportProgress(i, PlanStepSkipped, nil)
+			continue
+		}
+
+		vcc.Log.PrintInfo("[plan] running step %d/%d: %s", i+1, len(plan.steps), step.name)
+		plan.reportProgress(i, PlanStepRunning, nil)
+		plan.applySharedOptions(step.options, transports)
+		result.Err = step.run(vcc)
+		// the options of the step may be reused outside of the plan
+		step.options.sharedTransports = nil
+		result.Report = step.options.GetOperationReport()
+		if result.Err != nil {
+			failed = true
+			vcc.Log.PrintError("[plan] step %d/%d %s failed: %s", i+1, len(plan.steps), step.name, result.Err)
+			allErrs = errors.Join(allErrs, fmt.Errorf("step %d (%s) failed: %w", i+1, step.name, result.Err))
+			plan.reportProgress(i, PlanStepFailed, result.Err)
+		} else {
+			plan.reportProgress(i, PlanStepSucceeded, nil)
+		}
+		report.Steps = append(report.Steps, result)
+	}
+
+	return report, allErrs
+}
@@ -0,0 +1,171 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunPlan(t *testing.T) {
+	vcc := VClusterCommands{}
+	password := "test-password"
+	shared := DatabaseOptionsFactory()
+	shared.DBName = "test_db"
+	shared.Hosts = []string{"192.168.1.101", "192.168.1.102"}
+	shared.Password = &password
+	shared.Cert = "cert"
+	shared.Key = "key"
+
+	first := DatabaseOptionsFactory()
+	second := DatabaseOptionsFactory()
+	second.DBName = "other_db"
+	third := DatabaseOptionsFactory()
+	var ran []string
+	makeRun := func(name string, err error) func(VClusterCommands) error {
+		return func(VClusterCommands) error {
+			ran = append(ran, name)
+			return err
+		}
+	}
+
+	// stop on failure by default
+	plan := MakePlan(&shared)
+	plan.AddStep("first", &first, makeRun("first", nil))
+	plan.AddStep("second", &second, makeRun("second", errors.New("second failed")))
+	plan.AddStep("third", &third, makeRun("third", nil))
+	report, err := vcc.VRunPlan(&plan)
+	assert.ErrorContains(t, err, "step 2 (second) failed")
+	assert.Equal(t, []string{"first", "second"}, ran)
+	assert.True(t, report.Failed())
+	assert.Len(t, report.Steps, 3)
+	assert.True(t, report.Steps[2].Skipped)
+
+	// the shared options are applied, unless a step sets its own
+	assert.Equal(t, shared.Hosts, first.Hosts)
+	assert.Equal(t, "test_db", first.DBName)
+	assert.Equal(t, &password, first.Password)
+	assert.Equal(t, "cert", first.Cert)
+	assert.Equal(t, "other_db", second.DBName)
+
+	// continue on failure
+	ran = nil
+	plan.ContinueOnFailure = true
+	report, err = vcc.VRunPlan(&plan)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"first", "second", "third"}, ran)
+	assert.False(t, report.Steps[2].Skipped)
+	assert.NoError(t, report.Steps[2].Err)
+}
+
+func TestRunPlanWithCredentialProvider(t *testing.T) {
+	var authHeaders []string
+	shared := DatabaseOptionsFactory()
+	shared.DBName = "test_db"
+	shared.RawHosts = []string{"192.168.1.101"}
+	shared.UserName = "dbadmin"
+	shared.CredentialProvider = CredentialProviderFunc(func(_ string) (string, error) {
+		return "provided-password", nil
+	})
+	wrapped := 0
+	wrap := makeInstallPackagesTransport(&authHeaders)
+	shared.WrapTransport = func(host string, base http.RoundTripper) http.RoundTripper {
+		wrapped++
+		return wrap(host, base)
+	}
+
+	installOptions := []VInstallPackagesOptions{VInstallPackagesOptionsFactory(), VInstallPackagesOptionsFactory()}
+	plan := MakePlan(&shared)
+	for i := range installOptions {
+		plan.AddInstallPackages(&installOptions[i])
+	}
+	report, err := VClusterCommands{}.VRunPlan(&plan)
+	assert.NoError(t, err)
+
+	// every step logs in with the password of the shared provider
+	req := http.Request{Header: http.Header{}}
+	req.SetBasicAuth("dbadmin", "provided-password")
+	assert.Len(t, authHeaders, 4)
+	for _, header := range authHeaders {
+		assert.Equal(t, req.Header.Get("Authorization"), header)
+	}
+	// the steps share the connections to the host
+	assert.Equal(t, 1, wrapped)
+	// the report of each step is filled in
+	for i := range report.Steps {
+		assert.Equal(t, []string{"192.168.1.101"}, report.Steps[i].Report.SucceededHosts())
+	}
+}
+
+func TestRunPlanWithSharedHosts(t *testing.T) {
+	bundle := makeTestTLSBundle(t)
+	provider, err := NewPEMCertProvider(bundle.keyPEM, bundle.certPEM, bundle.caPEM)
+	assert.NoError(t, err)
+
+	shared := DatabaseOptionsFactory()
+	shared.DBName = "test_db"
+	shared.RawHosts = []string{"192.168.1.101"}
+	shared.CertProvider = provider
+	wrapped := 0
+	shared.WrapTransport = func(_ string, _ http.RoundTripper) http.RoundTripper {
+		wrapped++
+		return roundTripperFunc(func(_ *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{},
+				Body: io.NopCloser(strings.NewReader(`{"transaction_id": 45035996273704962, "status": "running"}`))}, nil
+		})
+	}
+
+	// the revive step only has the hosts of the plan
+	reviveOptions := VReviveDBOptionsFactory()
+	reviveOptions.CommunalStorageLocation = "/communal"
+	statusOptions := []VReplicationStatusDatabaseOptions{VReplicationStatusDatabaseFactory(), VReplicationStatusDatabaseFactory()}
+	plan := MakePlan(&shared)
+	plan.ContinueOnFailure = true
+	plan.AddReviveDatabase(&reviveOptions)
+	for i := range statusOptions {
+		options := &statusOptions[i]
+		options.TransactionID = 45035996273704962
+		plan.AddStep(commandReplicationStatus, &options.DatabaseOptions, func(vcc VClusterCommands) error {
+			_, err := vcc.VGetReplicationStatus(options)
+			return err
+		})
+	}
+	var progress []PlanProgress
+	plan.OnProgress = func(p PlanProgress) { progress = append(progress, p) }
+
+	report, _ := VClusterCommands{}.VRunPlan(&plan)
+	assert.Equal(t, []string{"192.168.1.101"}, reviveOptions.RawHosts)
+	// it gets past the validation and reaches the hosts, whose mocked
+	// responses are not the ones that revive expects
+	assert.ErrorContains(t, report.Steps[0].Err, "NMAHealthOp")
+	assert.NoError(t, report.Steps[1].Err)
+	assert.NoError(t, report.Steps[2].Err)
+
+	// the steps share the connections to the hosts
+	assert.Equal(t, 1, wrapped)
+	assert.Nil(t, statusOptions[0].sharedTransports)
+
+	// the progress of each step is reported
+	assert.Len(t, progress, 6)
+	assert.Equal(t, PlanProgress{Step: 1, TotalSteps: 3, Name: commandReviveDB, State: PlanStepRunning}, progress[0])
+	assert.Equal(t, PlanStepSucceeded, progress[5].State)
+	assert.Equal(t, 3, progress[5].Step)
+}
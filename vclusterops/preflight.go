@@ -0,0 +1,120 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"golang.org/x/exp/slices"
+)
+
+// HostSubsetPolicy declares how many of its hosts a command needs to proceed
+type HostSubsetPolicy int
+
+const (
+	// RequireAllHosts fails the command if any host is unreachable
+	RequireAllHosts HostSubsetPolicy = iota
+	// RequireQuorum lets the command proceed if more than half of the hosts
+	// are reachable
+	RequireQuorum
+	// RequireAnyHost lets the command proceed if any host is reachable
+	RequireAnyHost
+)
+
+// HostRequirement is what a command needs from its hosts, which the pre-flight
+// probe checks before the instructions of the command are built
+type HostRequirement struct {
+	Policy HostSubsetPolicy
+	// whether a host is only reachable when its HTTPS service is running, for
+	// the commands that run against an UP database. Otherwise, a host is
+	// reachable when its node management agent (NMA) responds.
+	NeedsHTTPS bool
+}
+
+// commandHostRequirements are the commands that can proceed with a subset of
+// their hosts. Any other command requires all of its hosts.
+var commandHostRequirements = map[string]HostRequirement{
+	commandStartDB:                 {Policy: RequireQuorum},
+	commandFetchNodesDetails:       {Policy: RequireAnyHost, NeedsHTTPS: true},
+	commandListConfigurationParams: {Policy: RequireAnyHost, NeedsHTTPS: true},
+}
+
+// GetHostRequirement returns what a command needs from its hosts, given the
+// name that the command validates its options with, e.g., "start_db"
+func GetHostRequirement(commandName string) HostRequirement {
+	return commandHostRequirements[commandName]
+}
+
+// minReachableHosts returns the number of reachable hosts that the policy
+// needs out of the given number of hosts
+func (policy HostSubsetPolicy) minReachableHosts(hostCount int) int {
+	switch policy {
+	case RequireQuorum:
+		return hostCount/2 + 1
+	case RequireAnyHost:
+		return 1
+	}
+	return hostCount
+}
+
+// runPreflightProbe probes the NMA and the HTTPS service of the hosts, and
+// removes the unreachable hosts from the options when the command can proceed
+// without them. The removed hosts are reported as the SkippedHosts of the
+// operation report. It does nothing unless PreflightProbe is set.
+func (opt *DatabaseOptions) runPreflightProbe(vcc VClusterCommands) error {
+	if !opt.PreflightProbe || len(opt.Hosts) == 0 {
+		return nil
+	}
+	requirement := GetHostRequirement(opt.commandName)
+
+	vdb := makeVCoordinationDatabase()
+	nmaGetHealthyNodesOp := makeNMAGetHealthyNodesOp(opt.Hosts, &vdb)
+	instructions := []clusterOp{&nmaGetHealthyNodesOp}
+	var httpsRunningHosts []string
+	if requirement.NeedsHTTPS {
+		httpsProbeOp, err := makeHTTPSProbeOp(opt.Hosts, opt.usePassword, opt.UserName, opt.Password, &httpsRunningHosts)
+		if err != nil {
+			return err
+		}
+		instructions = append(instructions, &httpsProbeOp)
+	}
+	clusterOpEngine := opt.makeClusterOpEngine(instructions)
+	err := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		return fmt.Errorf("fail to probe the hosts: %w", err)
+	}
+
+	reachableHosts := vdb.HostList
+	if requirement.NeedsHTTPS {
+		reachableHosts = util.SliceCommon(reachableHosts, httpsRunningHosts)
+	}
+	unreachableHosts := util.SliceDiff(opt.Hosts, reachableHosts)
+	if len(unreachableHosts) == 0 {
+		return nil
+	}
+	slices.Sort(unreachableHosts)
+	if len(reachableHosts) < requirement.Policy.minReachableHosts(len(opt.Hosts)) {
+		return categorizeError(ErrNMAUnreachable, fmt.Errorf("%s cannot proceed because the hosts %v are unreachable",
+			opt.commandName, unreachableHosts))
+	}
+
+	vcc.Log.PrintWarning("Skipping the unreachable hosts %v", unreachableHosts)
+	// keep the order of the input hosts
+	opt.Hosts = util.SliceDiff(opt.Hosts, unreachableHosts)
+	opt.report.SkippedHosts = append(opt.report.SkippedHosts, unreachableHosts...)
+	return nil
+}
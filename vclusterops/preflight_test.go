@@ -0,0 +1,104 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slices"
+)
+
+// makePreflightTestOptions returns options whose NMA is down on the nmaDownHosts,
+// and whose HTTPS service is down on the httpsDownHosts
+func makePreflightTestOptions(t *testing.T, commandName string, nmaDownHosts, httpsDownHosts []string) DatabaseOptions {
+	bundle := makeTestTLSBundle(t)
+	provider, err := NewPEMCertProvider(bundle.keyPEM, bundle.certPEM, bundle.caPEM)
+	assert.NoError(t, err)
+
+	opt := DatabaseOptionsFactory()
+	opt.Hosts = []string{"192.168.1.101", "192.168.1.102", "192.168.1.103"}
+	opt.CertProvider = provider
+	opt.commandName = commandName
+	opt.PreflightProbe = true
+	opt.WrapTransport = func(host string, _ http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			isNMA := strings.HasSuffix(req.URL.Path, "/health")
+			if (isNMA && slices.Contains(nmaDownHosts, host)) || (!isNMA && slices.Contains(httpsDownHosts, host)) {
+				return nil, errors.New("connection refused")
+			}
+			statusCode := http.StatusOK
+			if !isNMA {
+				// a host that rejects the credentials is running
+				statusCode = http.StatusUnauthorized
+			}
+			return &http.Response{StatusCode: statusCode, Header: http.Header{},
+				Body: io.NopCloser(strings.NewReader(`{"healthy": "true"}`))}, nil
+		})
+	}
+	return opt
+}
+
+func TestPreflightProbe(t *testing.T) {
+	vcc := VClusterCommands{}
+
+	// start_db proceeds with a quorum of the hosts
+	opt := makePreflightTestOptions(t, commandStartDB, []string{"192.168.1.102"}, nil)
+	assert.NoError(t, opt.runPreflightProbe(vcc))
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.103"}, opt.Hosts)
+	report := opt.GetOperationReport()
+	assert.Equal(t, []string{"192.168.1.102"}, report.SkippedHosts)
+
+	// but not without it
+	opt = makePreflightTestOptions(t, commandStartDB, []string{"192.168.1.102", "192.168.1.103"}, nil)
+	err := opt.runPreflightProbe(vcc)
+	assert.ErrorIs(t, err, ErrNMAUnreachable)
+	assert.ErrorContains(t, err, "start_db cannot proceed because the hosts [192.168.1.102 192.168.1.103] are unreachable")
+	assert.Len(t, opt.Hosts, 3)
+
+	// the commands that run against an UP database need the HTTPS service
+	opt = makePreflightTestOptions(t, commandListConfigurationParams, []string{"192.168.1.101"}, []string{"192.168.1.102"})
+	assert.NoError(t, opt.runPreflightProbe(vcc))
+	assert.Equal(t, []string{"192.168.1.103"}, opt.Hosts)
+	report = opt.GetOperationReport()
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.102"}, report.SkippedHosts)
+
+	// the other commands need all of their hosts
+	opt = makePreflightTestOptions(t, commandStopDB, nil, []string{"192.168.1.102"})
+	assert.NoError(t, opt.runPreflightProbe(vcc))
+	assert.Len(t, opt.Hosts, 3)
+	opt = makePreflightTestOptions(t, commandStopDB, []string{"192.168.1.102"}, nil)
+	assert.ErrorIs(t, opt.runPreflightProbe(vcc), ErrNMAUnreachable)
+
+	// the hosts are not probed unless asked
+	opt = makePreflightTestOptions(t, commandStartDB, []string{"192.168.1.102"}, nil)
+	opt.PreflightProbe = false
+	assert.NoError(t, opt.runPreflightProbe(vcc))
+	assert.Len(t, opt.Hosts, 3)
+}
+
+func TestHostSubsetPolicy(t *testing.T) {
+	assert.Equal(t, 3, RequireAllHosts.minReachableHosts(3))
+	assert.Equal(t, 2, RequireQuorum.minReachableHosts(3))
+	assert.Equal(t, 3, RequireQuorum.minReachableHosts(4))
+	assert.Equal(t, 1, RequireAnyHost.minReachableHosts(3))
+	assert.Equal(t, HostRequirement{Policy: RequireQuorum}, GetHostRequirement(commandStartDB))
+	assert.Equal(t, HostRequirement{}, GetHostRequirement(commandStopDB))
+}
@@ -0,0 +1,102 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+)
+
+// PrivilegeLevel is the database privilege of a user. A command declares the
+// level that it requires, and the commands of VClusterCommands can be run by
+// a user other than the database superuser when they do not require more
+// than the user has.
+type PrivilegeLevel int
+
+const (
+	// PrivilegeSuperuser is the privilege of the database superuser, e.g.,
+	// dbadmin. It is the default, and it is required by the commands that
+	// change the cluster.
+	PrivilegeSuperuser PrivilegeLevel = iota
+	// PrivilegeDatabaseUser is the privilege of any user that can log in to
+	// the database. It is enough for the commands that only read the state
+	// of the cluster, where the HTTPS service allows it.
+	PrivilegeDatabaseUser
+)
+
+func (level PrivilegeLevel) String() string {
+	switch level {
+	case PrivilegeSuperuser:
+		return "superuser"
+	case PrivilegeDatabaseUser:
+		return "database user"
+	}
+	return "unknown"
+}
+
+// covers returns true if a user with the level can run the commands that
+// require the given level
+func (level PrivilegeLevel) covers(required PrivilegeLevel) bool {
+	return level == PrivilegeSuperuser || level == required
+}
+
+// commandPrivileges are the commands that do not require the superuser.
+// Any other command requires PrivilegeSuperuser.
+var commandPrivileges = map[string]PrivilegeLevel{
+	commandFetchNodeState:          PrivilegeDatabaseUser,
+	commandFetchNodesDetails:       PrivilegeDatabaseUser,
+	commandListConfigurationParams: PrivilegeDatabaseUser,
+	commandReplicationStatus:       PrivilegeDatabaseUser,
+}
+
+// GetRequiredPrivilege returns the privilege level that a command requires,
+// given the name that the command validates its options with, e.g.,
+// "list_configuration_parameters"
+func GetRequiredPrivilege(commandName string) PrivilegeLevel {
+	if level, ok := commandPrivileges[commandName]; ok {
+		return level
+	}
+	return PrivilegeSuperuser
+}
+
+// validatePrivilege fails the command before it sends any request when the
+// declared privilege of the user is not enough to run it
+func (opt *DatabaseOptions) validatePrivilege(commandName string) error {
+	required := GetRequiredPrivilege(commandName)
+	if opt.UserPrivilege.covers(required) {
+		return nil
+	}
+	return makeInsufficientPrivilegeError(commandName, opt.UserName, required, "", nil)
+}
+
+// checkPrivilegeError turns the error of a run into an InsufficientPrivilegeError
+// when a host rejected the request of the user because it lacks privileges
+func (opEngine *VClusterOpEngine) checkPrivilegeError(err error) error {
+	if err == nil || opEngine.options == nil {
+		return err
+	}
+	var failures *hostFailuresError
+	if !errors.As(err, &failures) {
+		return err
+	}
+	for _, hostErr := range failures.hostErrors {
+		if hostErr.StatusCode == ForbiddenCode {
+			commandName := opEngine.options.commandName
+			return makeInsufficientPrivilegeError(commandName, opEngine.options.UserName,
+				GetRequiredPrivilege(commandName), hostErr.Host, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestGetRequiredPrivilege(t *testing.T) {
+	assert.Equal(t, PrivilegeDatabaseUser, GetRequiredPrivilege(commandListConfigurationParams))
+	assert.Equal(t, PrivilegeDatabaseUser, GetRequiredPrivilege(commandFetchNodeState))
+	assert.Equal(t, PrivilegeSuperuser, GetRequiredPrivilege(commandStopDB))
+	assert.Equal(t, PrivilegeSuperuser, GetRequiredPrivilege("unknown_command"))
+
+	assert.True(t, PrivilegeSuperuser.covers(PrivilegeDatabaseUser))
+	assert.True(t, PrivilegeDatabaseUser.covers(PrivilegeDatabaseUser))
+	assert.False(t, PrivilegeDatabaseUser.covers(PrivilegeSuperuser))
+}
+
+func TestValidatePrivilege(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	opt.DBName = "test_db"
+	opt.RawHosts = []string{"192.168.1.101"}
+	opt.UserName = "reader"
+	opt.UserPrivilege = PrivilegeDatabaseUser
+
+	// a database user can read the configuration parameters
+	assert.NoError(t, opt.validateBaseOptions(commandListConfigurationParams, vlog.Printer{}))
+
+	// but cannot stop the database
+	err := opt.validateBaseOptions(commandStopDB, vlog.Printer{})
+	assert.ErrorIs(t, err, ErrInsufficientPrivilege)
+	var privilegeErr *InsufficientPrivilegeError
+	assert.True(t, errors.As(err, &privilegeErr))
+	assert.Equal(t, commandStopDB, privilegeErr.Command)
+	assert.Equal(t, PrivilegeSuperuser, privilegeErr.Required)
+	assert.Empty(t, privilegeErr.Host)
+	assert.EqualError(t, err, "user reader does not have the superuser privilege required by stop_db")
+	hints, found := GetErrorHints(err)
+	assert.True(t, found)
+	assert.Equal(t, "UserName", hints.OptionName)
+
+	// the superuser can run any command
+	opt.UserPrivilege = PrivilegeSuperuser
+	assert.NoError(t, opt.validateBaseOptions(commandStopDB, vlog.Printer{}))
+}
+
+func TestCheckPrivilegeError(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	opt.UserName = "reader"
+	opt.commandName = commandListConfigurationParams
+	opEngine := opt.makeClusterOpEngine(nil)
+
+	assert.NoError(t, opEngine.checkPrivilegeError(nil))
+
+	// a host rejects the user
+	runErr := withHostErrors(errors.New("execute failed"), []*HostError{
+		{OpName: "HTTPSGetConfigurationParametersOp", Host: "192.168.1.101", StatusCode: UnauthorizedCode},
+		{OpName: "HTTPSGetConfigurationParametersOp", Host: "192.168.1.102", StatusCode: ForbiddenCode},
+	})
+	err := opEngine.checkPrivilegeError(runErr)
+	assert.ErrorIs(t, err, ErrInsufficientPrivilege)
+	var privilegeErr *InsufficientPrivilegeError
+	assert.True(t, errors.As(err, &privilegeErr))
+	assert.Equal(t, "192.168.1.102", privilegeErr.Host)
+	assert.Equal(t, "reader", privilegeErr.UserName)
+	assert.Equal(t, PrivilegeDatabaseUser, privilegeErr.Required)
+	// the host errors are still available
+	var hostErr *HostError
+	assert.True(t, errors.As(err, &hostErr))
+
+	// other failures are returned as is
+	runErr = withHostErrors(errors.New("execute failed"), []*HostError{
+		{OpName: "HTTPSGetConfigurationParametersOp", Host: "192.168.1.101", StatusCode: InternalErrorCode},
+	})
+	assert.Equal(t, runErr, opEngine.checkPrivilegeError(runErr))
+}
@@ -20,6 +20,8 @@ import (
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type VPromoteSandboxToMainOptions struct {
@@ -27,6 +29,21 @@ type VPromoteSandboxToMainOptions struct {
 	DatabaseOptions
 	// Name of the sandbox to promote to main
 	SandboxName string
+	// The subclusters and nodes of the old main cluster are discarded once the
+	// sandbox is promoted, so the caller must confirm the promotion explicitly.
+	// The preflight report lists what would be discarded.
+	Confirm bool
+}
+
+// PromoteSandboxToMainReport describes the outcome of promoting a sandbox to
+// the main cluster, before the promotion is run
+type PromoteSandboxToMainReport struct {
+	SandboxName string
+	// subclusters of the sandbox, which will form the new main cluster
+	SandboxSubclusters []string
+	// subclusters and nodes of the old main cluster, which will be discarded
+	DiscardedSubclusters []string
+	DiscardedNodes       []string
 }
 
 func VPromoteSandboxToMainFactory() VPromoteSandboxToMainOptions {
@@ -50,10 +67,18 @@ func (opt *VPromoteSandboxToMainOptions) validateParseOptions(logger vlog.Printe
 	}
 
 	// need to provide a password or certs in source database
-	if opt.Password == nil && (opt.Cert == "" || opt.Key == "") {
+	if opt.Password == nil && !opt.hasCertsInOptions() {
 		return fmt.Errorf("must provide a password or a key-certificate pair")
 	}
 
+	if opt.SandboxName == "" {
+		return fmt.Errorf("must specify a sandbox name")
+	}
+	err = util.ValidateSandboxName(opt.SandboxName)
+	if err != nil {
+		return err
+	}
+
 	return opt.validateBaseOptions(commandPromoteSandboxToMain, logger)
 }
 
@@ -62,7 +87,7 @@ func (opt *VPromoteSandboxToMainOptions) analyzeOptions() (err error) {
 	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
 	if len(opt.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		hostAddresses, err := util.ResolveRawHostsToAddresses(opt.RawHosts, opt.IPv6)
+		hostAddresses, err := opt.resolveRawHosts(opt.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -85,9 +110,27 @@ func (opt *VPromoteSandboxToMainOptions) validateAnalyzeOptions(logger vlog.Prin
 	return opt.validateUserName(logger)
 }
 
+// VPromoteSandboxToMainPreflight returns what promoting the sandbox to the
+// main cluster would discard, without changing the database
+func (vcc VClusterCommands) VPromoteSandboxToMainPreflight(options *VPromoteSandboxToMainOptions) (PromoteSandboxToMainReport, error) {
+	// validate and analyze options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return PromoteSandboxToMainReport{}, err
+	}
+
+	vdb := makeVCoordinationDatabase()
+	err = vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, options.SandboxName)
+	if err != nil {
+		return PromoteSandboxToMainReport{}, err
+	}
+	return buildPromoteSandboxToMainReport(&vdb, options.SandboxName)
+}
+
 // VPromoteSandboxToMain can convert local sandbox to main cluster. The conversion is supported only for
 // special sandboxes: without meta-isolation and communal (prefix) isolation. Those can be created
-// with the: "sls=false;imeta=false" options
+// with the: "sls=false;imeta=false" options. The old main cluster is discarded, so options.Confirm
+// must be set.
 func (vcc VClusterCommands) VPromoteSandboxToMain(options *VPromoteSandboxToMainOptions) error {
 	/*
 	 *   - Produce Instructions
@@ -108,6 +151,17 @@ func (vcc VClusterCommands) VPromoteSandboxToMain(options *VPromoteSandboxToMain
 		return err
 	}
 
+	report, err := buildPromoteSandboxToMainReport(&vdb, options.SandboxName)
+	if err != nil {
+		return err
+	}
+	if !options.Confirm {
+		return fmt.Errorf("promoting sandbox %s would discard subclusters %v and nodes %v of the main cluster, "+
+			"confirm the promotion to proceed", options.SandboxName, report.DiscardedSubclusters, report.DiscardedNodes)
+	}
+	vcc.Log.PrintWarning("Discarding subclusters %v and nodes %v of the main cluster",
+		report.DiscardedSubclusters, report.DiscardedNodes)
+
 	// produce sandbox to main cluster instructions
 	instructions, err := vcc.promoteSandboxToMainInstructions(options, &vdb)
 	if err != nil {
@@ -115,11 +169,10 @@ func (vcc VClusterCommands) VPromoteSandboxToMain(options *VPromoteSandboxToMain
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to promote a sandbox to main cluster: %w", runError)
 	}
@@ -127,8 +180,35 @@ func (vcc VClusterCommands) VPromoteSandboxToMain(options *VPromoteSandboxToMain
 	return nil
 }
 
+// buildPromoteSandboxToMainReport lists the subclusters of the sandbox, and
+// the subclusters and nodes of the main cluster that would be discarded
+func buildPromoteSandboxToMainReport(vdb *VCoordinationDatabase, sandboxName string) (PromoteSandboxToMainReport, error) {
+	report := PromoteSandboxToMainReport{SandboxName: sandboxName}
+	sandboxSubclusters := make(map[string]struct{})
+	discardedSubclusters := make(map[string]struct{})
+	for _, vnode := range vdb.HostNodeMap {
+		switch vnode.Sandbox {
+		case sandboxName:
+			sandboxSubclusters[vnode.Subcluster] = struct{}{}
+		case util.MainClusterSandbox:
+			discardedSubclusters[vnode.Subcluster] = struct{}{}
+			report.DiscardedNodes = append(report.DiscardedNodes, vnode.Name)
+		}
+	}
+	if len(sandboxSubclusters) == 0 {
+		return report, fmt.Errorf("sandbox %s does not exist or has no nodes", sandboxName)
+	}
+
+	report.SandboxSubclusters = maps.Keys(sandboxSubclusters)
+	report.DiscardedSubclusters = maps.Keys(discardedSubclusters)
+	slices.Sort(report.SandboxSubclusters)
+	slices.Sort(report.DiscardedSubclusters)
+	slices.Sort(report.DiscardedNodes)
+	return report, nil
+}
+
 // The generated instructions will later perform the following operations necessary
-// for a successful alter subcluster type operation:
+// for a successful promote sandbox to main operation:
 // - promote sandbox to main using one of the up nodes in the sandbox subcluster
 func (vcc VClusterCommands) promoteSandboxToMainInstructions(options *VPromoteSandboxToMainOptions,
 	vdb *VCoordinationDatabase) ([]clusterOp, error) {
@@ -146,6 +226,9 @@ func (vcc VClusterCommands) promoteSandboxToMainInstructions(options *VPromoteSa
 			break
 		}
 	}
+	if upHost == "" {
+		return nil, fmt.Errorf("cannot find any up host in sandbox %s", options.SandboxName)
+	}
 	sandboxHost := []string{upHost}
 	httpsConvertSandboxToMainOp, err := makeHTTPSConvertSandboxToMainOp(sandboxHost,
 		options.UserName, options.Password, options.usePassword, options.SandboxName)
@@ -33,6 +33,7 @@ func TestPromoteSandboxToMainOptions_validateParseOptions(t *testing.T) {
 	opt.DBName = testDBName
 	opt.UserName = testUserName
 	opt.Password = &testPassword
+	opt.SandboxName = "sand1"
 
 	err := opt.validateParseOptions(logger)
 	assert.NoError(t, err)
@@ -41,6 +42,12 @@ func TestPromoteSandboxToMainOptions_validateParseOptions(t *testing.T) {
 	err = opt.validateParseOptions(logger)
 	assert.NoError(t, err)
 
+	// negative: no sandbox name
+	opt.SandboxName = ""
+	err = opt.validateParseOptions(logger)
+	assert.ErrorContains(t, err, "must specify a sandbox name")
+	opt.SandboxName = "sand1"
+
 	// negative: no database name
 	opt.UserName = testUserName
 	opt.DBName = ""
@@ -52,3 +59,26 @@ func TestPromoteSandboxToMainOptions_validateParseOptions(t *testing.T) {
 	err = opt.validateParseOptions(logger)
 	assert.ErrorContains(t, err, "promote a sandbox to main is only supported in Eon mode")
 }
+
+func TestBuildPromoteSandboxToMainReport(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	addNode := func(host, name, sc, sandbox string) {
+		vdb.HostNodeMap[host] = &VCoordinationNode{Address: host, Name: name, Subcluster: sc, Sandbox: sandbox}
+	}
+	addNode("192.168.1.101", "v_test_db_node0001", "default_subcluster", "")
+	addNode("192.168.1.102", "v_test_db_node0002", "sc1", "")
+	addNode("192.168.1.103", "v_test_db_node0003", "sc2", "sand1")
+	addNode("192.168.1.104", "v_test_db_node0004", "sc3", "sand1")
+	addNode("192.168.1.105", "v_test_db_node0005", "sc4", "sand2")
+
+	report, err := buildPromoteSandboxToMainReport(&vdb, "sand1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sc2", "sc3"}, report.SandboxSubclusters)
+	assert.Equal(t, []string{"default_subcluster", "sc1"}, report.DiscardedSubclusters)
+	assert.Equal(t, []string{"v_test_db_node0001", "v_test_db_node0002"}, report.DiscardedNodes)
+
+	// negative: unknown sandbox
+	_, err = buildPromoteSandboxToMainReport(&vdb, "sand3")
+	assert.ErrorContains(t, err, "sandbox sand3 does not exist")
+}
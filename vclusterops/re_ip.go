@@ -23,6 +23,8 @@ import (
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type VReIPOptions struct {
@@ -30,6 +32,14 @@ type VReIPOptions struct {
 
 	// re-ip list
 	ReIPList []ReIPInfo
+	// A map from node name to the new address of the node. This is an
+	// alternative to ReIPList, and the entries are added to it.
+	NodeAddresses map[string]string
+	// Re-ip the nodes of a running database through its UP nodes, and
+	// reload spread, instead of editing the catalog of a down database. The
+	// nodes to re-ip must be down, e.g., because their addresses changed,
+	// and they can be started with VStartNodes once the re-ip is done.
+	OnlineReIP bool
 
 	/* hidden option */
 
@@ -38,6 +48,13 @@ type VReIPOptions struct {
 	// perform an additional HTTPS check (checkRunningDB operation) to verify that the database is running.
 	// This is useful when Re-IP should only be applied to down db.
 	CheckDBRunning bool
+
+	/* result info */
+
+	// network profiles of the target addresses, keyed by address. They are
+	// set even if the re-ip fails, so that callers can see why an address
+	// was rejected.
+	NetworkProfiles map[string]NetworkProfile
 }
 
 func VReIPFactory() VReIPOptions {
@@ -58,6 +75,11 @@ func (options *VReIPOptions) validateRequiredOptions(logger vlog.Printer) error
 }
 
 func (options *VReIPOptions) validateExtraOptions() error {
+	// the catalog of a running database is not read from the disk
+	if options.OnlineReIP {
+		return nil
+	}
+
 	err := util.ValidateRequiredAbsPath(options.CatalogPrefix, "catalog path")
 	if err != nil {
 		return err
@@ -87,12 +109,23 @@ func (options *VReIPOptions) validateParseOptions(logger vlog.Printer) error {
 
 func (options *VReIPOptions) analyzeOptions() error {
 	if len(options.RawHosts) > 0 {
-		hostAddresses, err := util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		hostAddresses, err := options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
 		options.Hosts = hostAddresses
 	}
+
+	// sort the node names so that the re-ip list is always the same
+	nodeNames := maps.Keys(options.NodeAddresses)
+	slices.Sort(nodeNames)
+	for _, nodeName := range nodeNames {
+		options.ReIPList = append(options.ReIPList, ReIPInfo{
+			NodeName:      nodeName,
+			TargetAddress: options.NodeAddresses[nodeName],
+		})
+	}
+	options.NodeAddresses = nil
 	return nil
 }
 
@@ -155,6 +188,10 @@ func (vcc VClusterCommands) VReIP(options *VReIPOptions) error {
 		return err
 	}
 
+	if options.OnlineReIP {
+		return vcc.reIPRunningDB(options)
+	}
+
 	// VER-93369 may improve this if the CLI knows which nodes are primary
 	// from the config file
 	var pVDB *VCoordinationDatabase
@@ -184,11 +221,11 @@ func (vcc VClusterCommands) VReIP(options *VReIPOptions) error {
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	options.NetworkProfiles, _ = clusterOpEngine.execContext.NetworkProfiles()
 	if runError != nil {
 		return fmt.Errorf("fail to re-ip: %w", runError)
 	}
@@ -229,12 +266,9 @@ func (vcc VClusterCommands) produceReIPInstructions(options *VReIPOptions, vdb *
 		instructions = append(instructions, &checkDBRunningOp)
 	}
 
-	// get network profiles of the new addresses
-	var newAddresses []string
-	for _, info := range options.ReIPList {
-		newAddresses = append(newAddresses, info.TargetAddress)
-	}
-	nmaNetworkProfileOp := makeNMANetworkProfileOp(newAddresses)
+	// get network profiles of the new addresses, and make sure that
+	// the addresses live on the interfaces of the hosts
+	nmaNetworkProfileOp := makeNMANetworkProfileOpForReIP(options.ReIPList)
 
 	instructions = append(instructions, &nmaNetworkProfileOp)
 
@@ -275,6 +309,137 @@ func (vcc VClusterCommands) produceReIPInstructions(options *VReIPOptions, vdb *
 	return instructions, nil
 }
 
+// reIPRunningDB re-ips the down nodes of a running database through its UP
+// nodes, and checks that the catalog has the new addresses
+func (vcc VClusterCommands) reIPRunningDB(options *VReIPOptions) error {
+	vdb := makeVCoordinationDatabase()
+	err := vcc.getVDBFromRunningDB(&vdb, &options.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = options.completeReIPListFromVDB(&vdb)
+	if err != nil {
+		return err
+	}
+
+	instructions, err := vcc.produceOnlineReIPInstructions(options, &vdb)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	options.NetworkProfiles, _ = clusterOpEngine.execContext.NetworkProfiles()
+	if runError != nil {
+		return fmt.Errorf("fail to re-ip: %w", runError)
+	}
+
+	return verifyReIPInCatalog(&vdb, options.ReIPList)
+}
+
+// completeReIPListFromVDB fills in the node names and the current addresses
+// of the re-ip list, and checks that the nodes to re-ip are not UP
+func (options *VReIPOptions) completeReIPListFromVDB(vdb *VCoordinationDatabase) error {
+	nodeNameToHost := make(map[string]string)
+	for host, vnode := range vdb.HostNodeMap {
+		nodeNameToHost[vnode.Name] = host
+	}
+
+	for i := range options.ReIPList {
+		info := &options.ReIPList[i]
+		if info.NodeName == "" {
+			vnode, ok := vdb.HostNodeMap[info.NodeAddress]
+			if !ok {
+				return fmt.Errorf("the address %s is not in the catalog", info.NodeAddress)
+			}
+			info.NodeName = vnode.Name
+		}
+		host, ok := nodeNameToHost[info.NodeName]
+		if !ok {
+			return fmt.Errorf("the node %s is not in the catalog", info.NodeName)
+		}
+		info.NodeAddress = host
+		if vdb.HostNodeMap[host].State == util.NodeUpState {
+			return fmt.Errorf("the node %s is UP, only down nodes can be re-ip'ed in a running database", info.NodeName)
+		}
+	}
+	return nil
+}
+
+// produceOnlineReIPInstructions will build a list of instructions to execute
+// for the re-ip of a running database.
+//
+// The generated instructions will later perform the following operations:
+//   - Check NMA connectivity on the new addresses
+//   - Get UP nodes through HTTPS call
+//   - Get network profiles of the new addresses
+//   - Call https re-ip endpoint
+//   - Reload spread
+//   - Call https /v1/nodes to get the nodes' info with the new addresses
+func (vcc VClusterCommands) produceOnlineReIPInstructions(options *VReIPOptions,
+	vdb *VCoordinationDatabase) ([]clusterOp, error) {
+	var instructions []clusterOp
+
+	err := options.setUsePasswordAndValidateUsernameIfNeeded(vcc.Log)
+	if err != nil {
+		return instructions, err
+	}
+
+	var nodeNames, targetAddresses []string
+	for _, info := range options.ReIPList {
+		nodeNames = append(nodeNames, info.NodeName)
+		targetAddresses = append(targetAddresses, info.TargetAddress)
+	}
+
+	nmaHealthOp := makeNMAHealthOp(targetAddresses)
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password, ReIPCmd)
+	if err != nil {
+		return instructions, err
+	}
+	nmaNetworkProfileOp := makeNMANetworkProfileOp(targetAddresses)
+	httpsReIPOp, err := makeHTTPSReIPOp(nodeNames, targetAddresses,
+		options.usePassword, options.UserName, options.Password)
+	if err != nil {
+		return instructions, err
+	}
+	// the UP nodes found by httpsGetUpNodesOp reload spread
+	httpsReloadSpreadOp, err := makeHTTPSReloadSpreadOp(options.usePassword, options.UserName, options.Password)
+	if err != nil {
+		return instructions, err
+	}
+	httpsGetNodesInfoOp, err := makeHTTPSGetNodesInfoOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password, vdb, false, util.MainClusterSandbox)
+	if err != nil {
+		return instructions, err
+	}
+
+	instructions = append(instructions,
+		&nmaHealthOp,
+		&httpsGetUpNodesOp,
+		&nmaNetworkProfileOp,
+		&httpsReIPOp,
+		&httpsReloadSpreadOp,
+		&httpsGetNodesInfoOp,
+	)
+	return instructions, nil
+}
+
+// verifyReIPInCatalog returns an error if a node in the re-ip list does not
+// have its new address in the catalog
+func verifyReIPInCatalog(vdb *VCoordinationDatabase, reIPList []ReIPInfo) error {
+	var allErrs error
+	for _, info := range reIPList {
+		vnode, ok := vdb.HostNodeMap[info.TargetAddress]
+		if !ok || vnode.Name != info.NodeName {
+			allErrs = errors.Join(allErrs, categorizeError(ErrCatalogMismatch,
+				fmt.Errorf("the catalog does not have the new address %s of node %s", info.TargetAddress, info.NodeName)))
+		}
+	}
+	return allErrs
+}
+
 type reIPRow struct {
 	CurrentAddress      string `json:"from_address"`
 	NewAddress          string `json:"to_address"`
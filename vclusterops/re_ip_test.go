@@ -16,11 +16,13 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
@@ -73,7 +75,7 @@ func TestTrimReIPList(t *testing.T) {
 	// build a stub exec context
 	log := vlog.Printer{}
 	var op nmaReIPOp
-	execContext := makeOpEngineExecContext(log)
+	execContext := makeOpEngineExecContext(context.Background(), log)
 
 	// build a stub NmaVDatabase
 	nmaVDB := nmaVDatabase{}
@@ -108,3 +110,66 @@ func TestTrimReIPList(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, len(op.reIPList), 3)
 }
+
+func TestValidateReIPList(t *testing.T) {
+	profiles := map[string]NetworkProfile{
+		"192.168.1.103": {Name: "eth0", Address: "192.168.1.103", Subnet: "192.168.0.0/16",
+			Netmask: "255.255.0.0", Broadcast: "192.168.255.255"},
+		"10.0.0.5": {Name: "eth0", Address: "10.0.0.9", Subnet: "10.0.0.0/24",
+			Netmask: "255.255.255.0", Broadcast: "10.0.0.255"},
+	}
+
+	op := makeNMANetworkProfileOpForReIP([]ReIPInfo{
+		{TargetAddress: "192.168.1.103", TargetControlAddress: "192.168.1.103", TargetControlBroadcast: "192.168.255.255"},
+	})
+	assert.NoError(t, op.validateReIPList(profiles))
+
+	// the control broadcast is not in the subnet of the interface
+	op.reIPList[0].TargetControlBroadcast = "10.0.0.255"
+	assert.ErrorContains(t, op.validateReIPList(profiles), "address 10.0.0.255 is not in the subnet 192.168.0.0/16")
+
+	// the address does not live on the host
+	op = makeNMANetworkProfileOpForReIP([]ReIPInfo{{TargetAddress: "10.0.0.5"}})
+	assert.ErrorContains(t, op.validateReIPList(profiles), "address 10.0.0.5 does not live on any interface of the host")
+}
+
+func TestOnlineReIP(t *testing.T) {
+	opt := VReIPFactory()
+	opt.DBName = "test_db"
+	opt.RawHosts = []string{"192.168.1.101"}
+	opt.OnlineReIP = true
+	opt.NodeAddresses = map[string]string{"v_test_db_node0003": "192.168.1.113", "v_test_db_node0002": "192.168.1.112"}
+	// the catalog path is not required to re-ip a running database
+	err := opt.validateAnalyzeOptions(vlog.Printer{})
+	assert.NoError(t, err)
+	assert.Equal(t, []ReIPInfo{
+		{NodeName: "v_test_db_node0002", TargetAddress: "192.168.1.112"},
+		{NodeName: "v_test_db_node0003", TargetAddress: "192.168.1.113"},
+	}, opt.ReIPList)
+
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = vHostNodeMap{
+		"192.168.1.101": {Name: "v_test_db_node0001", State: util.NodeUpState},
+		"192.168.1.102": {Name: "v_test_db_node0002", State: util.NodeDownState},
+		"192.168.1.103": {Name: "v_test_db_node0003", State: util.NodeDownState},
+	}
+	assert.NoError(t, opt.completeReIPListFromVDB(&vdb))
+	assert.Equal(t, "192.168.1.102", opt.ReIPList[0].NodeAddress)
+
+	// an UP node cannot be re-ip'ed online
+	opt.ReIPList = append(opt.ReIPList, ReIPInfo{NodeAddress: "192.168.1.101", TargetAddress: "192.168.1.111"})
+	assert.ErrorContains(t, opt.completeReIPListFromVDB(&vdb), "v_test_db_node0001 is UP")
+	opt.ReIPList = opt.ReIPList[:2]
+
+	instructions, err := VClusterCommands{}.produceOnlineReIPInstructions(&opt, &vdb)
+	assert.NoError(t, err)
+	assert.Len(t, instructions, 6)
+
+	// the catalog must have the new addresses after the re-ip
+	vdb.HostNodeMap["192.168.1.112"] = vdb.HostNodeMap["192.168.1.102"]
+	err = verifyReIPInCatalog(&vdb, opt.ReIPList)
+	assert.ErrorIs(t, err, ErrCatalogMismatch)
+	assert.ErrorContains(t, err, "192.168.1.113")
+	vdb.HostNodeMap["192.168.1.113"] = vdb.HostNodeMap["192.168.1.103"]
+	assert.NoError(t, verifyReIPInCatalog(&vdb, opt.ReIPList))
+}
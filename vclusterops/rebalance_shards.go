@@ -0,0 +1,280 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type VRebalanceShardsOptions struct {
+	/* part 1: basic db info */
+	DatabaseOptions
+
+	/* part 2: rebalance options */
+	// the subcluster to rebalance the shards of
+	SCName string
+	// when set, the rebalance job is started and its handle is returned
+	// without waiting for the job to complete
+	Async bool
+	// timeout for waiting for the rebalance job to complete, a negative value means no timeout
+	StatePollingTimeout int
+}
+
+// RebalanceJob is the handle and progress of a shard rebalance job
+type RebalanceJob struct {
+	JobID           string  `json:"job_id"`
+	SCName          string  `json:"subcluster_name"`
+	State           string  `json:"state"`
+	ProgressPercent float64 `json:"progress_percent"`
+	// estimated seconds to completion, or -1 if there is no progress yet to estimate from
+	ETASeconds int64  `json:"eta_seconds"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// IsDone returns true if the job has completed or failed
+func (job *RebalanceJob) IsDone() bool {
+	return job.State == rebalanceJobCompleted || job.State == rebalanceJobFailed
+}
+
+func (job *RebalanceJob) update(resp *rebalanceJobResponse) {
+	job.SCName = resp.SubclusterName
+	job.State = resp.State
+	job.ProgressPercent = resp.ProgressPercent
+	job.Detail = resp.Detail
+	job.ETASeconds = estimateRebalanceETA(resp.State, resp.ProgressPercent, resp.ElapsedSeconds)
+}
+
+// estimateRebalanceETA extrapolates the remaining time from the elapsed time,
+// assuming the rebalance progresses at a constant rate
+func estimateRebalanceETA(state string, progressPercent, elapsedSeconds float64) int64 {
+	const fullPercent = 100
+	if state == rebalanceJobCompleted || progressPercent >= fullPercent {
+		return 0
+	}
+	if progressPercent <= 0 || elapsedSeconds <= 0 {
+		return -1
+	}
+	return int64(elapsedSeconds * (fullPercent - progressPercent) / progressPercent)
+}
+
+func VRebalanceShardsOptionsFactory() VRebalanceShardsOptions {
+	options := VRebalanceShardsOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	options.StatePollingTimeout = util.DefaultStatePollingTimeout
+
+	return options
+}
+
+func (options *VRebalanceShardsOptions) validateParseOptions(logger vlog.Printer) error {
+	if !options.IsEon {
+		return fmt.Errorf("shard rebalance is only supported in Eon mode")
+	}
+
+	err := options.validateBaseOptions(commandRebalanceShards, logger)
+	if err != nil {
+		return err
+	}
+
+	if options.SCName == "" {
+		return fmt.Errorf("must specify a subcluster name")
+	}
+	return util.ValidateName(options.SCName, "subcluster", false)
+}
+
+func (options *VRebalanceShardsOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VRebalanceShardsOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	return options.setUsePasswordAndValidateUsernameIfNeeded(logger)
+}
+
+// VRebalanceShards rebalances the shards of a subcluster. Unless Async is set, it
+// waits for the rebalance job to complete and logs its progress. In async mode, the
+// returned job can be tracked with VGetRebalanceJobStatus.
+func (vcc VClusterCommands) VRebalanceShards(options *VRebalanceShardsOptions) (RebalanceJob, error) {
+	job := RebalanceJob{SCName: options.SCName, ETASeconds: -1}
+
+	// validate and analyze all options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return job, err
+	}
+
+	vdb := makeVCoordinationDatabase()
+	err = vcc.getVDBFromRunningDB(&vdb, &options.DatabaseOptions)
+	if err != nil {
+		return job, err
+	}
+
+	initiator, err := getRebalanceInitiatorHost(&vdb, options.SCName)
+	if err != nil {
+		return job, err
+	}
+
+	var jobID string
+	httpsRebalanceOp, err := makeHTTPSRebalanceSubclusterShardsOp(initiator, options.usePassword,
+		options.UserName, options.Password, options.SCName)
+	if err != nil {
+		return job, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	httpsRebalanceOp.runAsync(&jobID)
+
+	httpsPollRebalanceJobOp, err := makeHTTPSPollRebalanceJobOp(initiator, options.usePassword,
+		options.UserName, options.Password, options.StatePollingTimeout, &jobID, &job)
+	if err != nil {
+		return job, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	if options.Async {
+		// read the job once to return a handle with its initial state
+		httpsPollRebalanceJobOp.readOnce()
+	}
+
+	instructions := []clusterOp{&httpsRebalanceOp, &httpsPollRebalanceJobOp}
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		return job, fmt.Errorf("fail to rebalance shards of subcluster %s: %w", options.SCName, err)
+	}
+	return job, nil
+}
+
+// getRebalanceInitiatorHost returns an UP host in the same cluster (main cluster or
+// sandbox) as the subcluster to rebalance, preferring a host of the subcluster itself
+func getRebalanceInitiatorHost(vdb *VCoordinationDatabase, scName string) ([]string, error) {
+	var scSandbox string
+	found := false
+	for _, vnode := range vdb.HostNodeMap {
+		if vnode.Subcluster == scName {
+			scSandbox = vnode.Sandbox
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("subcluster %s does not exist in the database", scName)
+	}
+
+	var initiator string
+	for _, h := range vdb.HostList {
+		vnode := vdb.HostNodeMap[h]
+		if vnode.Sandbox != scSandbox || vnode.State != util.NodeUpState {
+			continue
+		}
+		if vnode.Subcluster == scName {
+			return []string{h}, nil
+		}
+		if initiator == "" {
+			initiator = h
+		}
+	}
+	if initiator == "" {
+		return nil, categorizeError(ErrNodeDown,
+			fmt.Errorf("cannot find any up hosts to rebalance the shards of subcluster %s", scName))
+	}
+	return []string{initiator}, nil
+}
+
+// VRebalanceJobStatusOptions are the options to get the progress of a rebalance job
+type VRebalanceJobStatusOptions struct {
+	DatabaseOptions
+	// ID of the job returned by VRebalanceShards
+	JobID string
+}
+
+func VRebalanceJobStatusOptionsFactory() VRebalanceJobStatusOptions {
+	options := VRebalanceJobStatusOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VRebalanceJobStatusOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandRebalanceShards, logger)
+	if err != nil {
+		return err
+	}
+	if options.JobID == "" {
+		return fmt.Errorf("must specify the ID of the rebalance job")
+	}
+	return nil
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VRebalanceJobStatusOptions) analyzeOptions() (err error) {
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VRebalanceJobStatusOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	return options.setUsePasswordAndValidateUsernameIfNeeded(logger)
+}
+
+// VGetRebalanceJobStatus returns the progress of a rebalance job started by
+// VRebalanceShards in async mode. It does not wait for the job to complete.
+func (vcc VClusterCommands) VGetRebalanceJobStatus(options *VRebalanceJobStatusOptions) (RebalanceJob, error) {
+	job := RebalanceJob{JobID: options.JobID, ETASeconds: -1}
+
+	// validate and analyze options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return job, err
+	}
+
+	httpsPollRebalanceJobOp, err := makeHTTPSPollRebalanceJobOp(options.Hosts, options.usePassword,
+		options.UserName, options.Password, 0 /*timeout*/, &options.JobID, &job)
+	if err != nil {
+		return job, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	httpsPollRebalanceJobOp.readOnce()
+
+	clusterOpEngine := options.makeClusterOpEngine([]clusterOp{&httpsPollRebalanceJobOp})
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		return job, fmt.Errorf("fail to get rebalance job status: %w", err)
+	}
+	return job, nil
+}
@@ -0,0 +1,141 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+func TestEstimateRebalanceETA(t *testing.T) {
+	// 25% done in 60 seconds, so 180 seconds remaining
+	assert.Equal(t, int64(180), estimateRebalanceETA(rebalanceJobRunning, 25, 60))
+	assert.Equal(t, int64(0), estimateRebalanceETA(rebalanceJobCompleted, 90, 60))
+	assert.Equal(t, int64(0), estimateRebalanceETA(rebalanceJobRunning, 100, 60))
+	// no progress to estimate from
+	assert.Equal(t, int64(-1), estimateRebalanceETA(rebalanceJobRunning, 0, 60))
+	assert.Equal(t, int64(-1), estimateRebalanceETA(rebalanceJobRunning, 50, 0))
+}
+
+func TestGetRebalanceInitiatorHost(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	addNode := func(host, sc, state, sandbox string) {
+		vdb.HostList = append(vdb.HostList, host)
+		vdb.HostNodeMap[host] = &VCoordinationNode{Address: host, Subcluster: sc, State: state, Sandbox: sandbox}
+	}
+	addNode("192.168.1.101", "sc1", util.NodeUpState, "")
+	addNode("192.168.1.102", "sc2", util.NodeDownState, "")
+	addNode("192.168.1.103", "sc2", util.NodeUpState, "")
+	addNode("192.168.1.104", "sc3", util.NodeDownState, "sand1")
+	addNode("192.168.1.105", "sc4", util.NodeUpState, "sand1")
+
+	// a host of the subcluster is preferred
+	hosts, err := getRebalanceInitiatorHost(&vdb, "sc2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.103"}, hosts)
+
+	// an UP host of the same sandbox is used otherwise
+	hosts, err = getRebalanceInitiatorHost(&vdb, "sc3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.105"}, hosts)
+
+	// negative: unknown subcluster
+	_, err = getRebalanceInitiatorHost(&vdb, "sc5")
+	assert.ErrorContains(t, err, "subcluster sc5 does not exist")
+
+	// negative: no UP host in the sandbox
+	vdb.HostNodeMap["192.168.1.105"].State = util.NodeDownState
+	_, err = getRebalanceInitiatorHost(&vdb, "sc3")
+	assert.ErrorContains(t, err, "cannot find any up hosts")
+}
+
+func TestPollRebalanceJobOp(t *testing.T) {
+	const host = "192.168.1.101"
+	jobID := "45035996273705058"
+	job := RebalanceJob{}
+	op, err := makeHTTPSPollRebalanceJobOp([]string{host}, false, "", nil, 10, &jobID, &job)
+	assert.NoError(t, err)
+	op.job.JobID = jobID
+	op.setupBasicInfo()
+
+	setResult := func(content string) {
+		op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+			host: {status: SUCCESS, statusCode: SuccessCode, host: host, content: content},
+		}
+	}
+
+	setResult(`{"job_id": "45035996273705058", "subcluster_name": "sc1", "state": "RUNNING",
+		"progress_percent": 40, "elapsed_seconds": 20}`)
+	stop, err := op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.False(t, stop)
+	assert.Equal(t, RebalanceJob{JobID: jobID, SCName: "sc1", State: rebalanceJobRunning,
+		ProgressPercent: 40, ETASeconds: 30}, job)
+	assert.False(t, job.IsDone())
+
+	setResult(`{"job_id": "45035996273705058", "subcluster_name": "sc1", "state": "COMPLETED",
+		"progress_percent": 100, "elapsed_seconds": 50}`)
+	stop, err = op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.True(t, stop)
+	assert.True(t, job.IsDone())
+	assert.Equal(t, int64(0), job.ETASeconds)
+
+	// negative: failed job
+	setResult(`{"job_id": "45035996273705058", "subcluster_name": "sc1", "state": "FAILED",
+		"progress_percent": 60, "elapsed_seconds": 50, "detail": "node down"}`)
+	stop, err = op.shouldStopPolling()
+	assert.True(t, stop)
+	assert.ErrorContains(t, err, "rebalance of subcluster sc1 failed: node down")
+
+	// negative: another job
+	setResult(`{"job_id": "1234", "state": "RUNNING"}`)
+	_, err = op.shouldStopPolling()
+	assert.ErrorContains(t, err, "returned rebalance job 1234")
+
+	// in read-once mode, a running job stops the polling
+	op.readOnce()
+	setResult(`{"job_id": "45035996273705058", "subcluster_name": "sc1", "state": "RUNNING",
+		"progress_percent": 10, "elapsed_seconds": 5}`)
+	stop, err = op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.True(t, stop)
+}
+
+func TestRebalanceShardsAsyncResponse(t *testing.T) {
+	const host = "192.168.1.101"
+	op, err := makeHTTPSRebalanceSubclusterShardsOp([]string{host}, false, "", nil, "sc1")
+	assert.NoError(t, err)
+	var jobID string
+	op.runAsync(&jobID)
+	op.setupBasicInfo()
+
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: SUCCESS, statusCode: SuccessCode, host: host, content: `{"job_id": "45035996273705058"}`},
+	}
+	assert.NoError(t, op.processResult(nil))
+	assert.Equal(t, "45035996273705058", jobID)
+
+	// negative: no job ID
+	jobID = ""
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: SUCCESS, statusCode: SuccessCode, host: host, content: `{"detail": "REBALANCED SHARDS"}`},
+	}
+	assert.ErrorContains(t, op.processResult(nil), "has no rebalance job ID")
+}
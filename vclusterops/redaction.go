@@ -0,0 +1,127 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+/* Sensitive fields in request body
+ */
+type sensitiveFields struct {
+	DBPassword         string            `json:"db_password"`
+	AWSAccessKeyID     string            `json:"aws_access_key_id"`
+	AWSSecretAccessKey string            `json:"aws_secret_access_key"`
+	Parameters         map[string]string `json:"parameters"`
+}
+
+const maskedValue = "******"
+
+// sensitiveConfigParameters are the configuration parameters whose value is a credential
+var sensitiveConfigParameters = []string{"awsauth", "awssessiontoken", "gcsauth", "azurestoragecredentials"}
+
+// isSensitiveConfigParameter returns true if the value of the configuration
+// parameter is a credential, which must not be logged
+func isSensitiveConfigParameter(key string) bool {
+	key = strings.ToLower(key)
+	for _, param := range sensitiveConfigParameters {
+		if key == param {
+			return true
+		}
+	}
+	return false
+}
+
+// isMaskedValue returns true if the value is made of asterisks only, which is
+// how a credential is returned instead of its value
+func isMaskedValue(value string) bool {
+	return value != "" && strings.Trim(value, "*") == ""
+}
+
+// isSensitiveQueryParam returns true if the query parameter carries a secret
+func isSensitiveQueryParam(key string) bool {
+	lowerKey := strings.ToLower(key)
+	return isSensitiveConfigParameter(key) || strings.Contains(lowerKey, "password") ||
+		strings.Contains(lowerKey, "secret") || strings.Contains(lowerKey, "token")
+}
+
+func (maskedData *sensitiveFields) maskSensitiveInfo() {
+	maskedData.DBPassword = maskedValue
+	maskedData.AWSAccessKeyID = maskedValue
+	maskedData.AWSSecretAccessKey = maskedValue
+	for key := range maskedData.Parameters {
+		// Mask the value if the keys are credentials
+		if isSensitiveConfigParameter(key) {
+			maskedData.Parameters[key] = maskedValue
+		}
+	}
+}
+
+var (
+	// a JSON string field whose name is a secret, e.g., "password": "..."
+	sensitiveJSONFieldRegexp = regexp.MustCompile(`(?i)("(?:password|db_password|aws_access_key_id|aws_secret_access_key|` +
+		`private_key|spread_key|key|` + strings.Join(sensitiveConfigParameters, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// a key=value pair of a credential, e.g., in the configuration parameters of a command
+	sensitivePairRegexp = regexp.MustCompile(`(?i)\b(` + strings.Join(sensitiveConfigParameters, "|") + `)(\s*=\s*)[^,\s"]+`)
+	// a PEM-encoded private key, whose line breaks may be escaped in a JSON string
+	privateKeyRegexp = regexp.MustCompile(`-----BEGIN ([A-Z ]*)PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)
+)
+
+// redactSecrets masks the passwords, the credentials, and the private keys in
+// a request or response payload, or in an error message, before it is logged
+// or returned to the caller. All ops should log their payloads through it.
+func redactSecrets(text string) string {
+	text = sensitiveJSONFieldRegexp.ReplaceAllString(text, `$1"`+maskedValue+`"`)
+	text = sensitivePairRegexp.ReplaceAllString(text, `$1$2`+maskedValue)
+	return privateKeyRegexp.ReplaceAllString(text, `-----BEGIN ${1}PRIVATE KEY-----`+maskedValue+`-----END ${1}PRIVATE KEY-----`)
+}
+
+// redactForLog encodes a payload as JSON with its secrets masked
+func redactForLog(payload any) string {
+	if text, ok := payload.(string); ok {
+		return redactSecrets(text)
+	}
+	dataBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Sprintf("<fail to encode the payload: %v>", err)
+	}
+	return redactSecrets(string(dataBytes))
+}
+
+// redactedError masks the secrets in the message of the wrapped error, which
+// can still be inspected with errors.Is and errors.As
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return redactSecrets(e.err.Error())
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactError wraps err so that its message does not leak any secret
+func redactError(err error) error {
+	if _, ok := err.(*redactedError); ok || err == nil {
+		return err
+	}
+	return &redactedError{err: err}
+}
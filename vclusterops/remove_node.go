@@ -31,6 +31,10 @@ type VRemoveNodeOptions struct {
 	Initiator     string   // A primary up host that will be used to execute remove_node operations.
 	ForceDelete   bool     // whether force delete directories
 	IsSubcluster  bool     // is removing all nodes for a subcluster
+	// Remove the nodes even if the database would lose the quorum of its
+	// primary nodes, its shard coverage (Eon mode only), or its K-safety
+	// (Enterprise mode only) without them
+	Force bool
 }
 
 func VRemoveNodeOptionsFactory() VRemoveNodeOptions {
@@ -79,7 +83,7 @@ func (options *VRemoveNodeOptions) validateParseOptions(logger vlog.Printer) err
 }
 
 func (options *VRemoveNodeOptions) analyzeOptions() (err error) {
-	options.HostsToRemove, err = util.ResolveRawHostsToAddresses(options.HostsToRemove, options.IPv6)
+	options.HostsToRemove, err = options.resolveRawHosts(options.HostsToRemove)
 	if err != nil {
 		return err
 	}
@@ -87,7 +91,7 @@ func (options *VRemoveNodeOptions) analyzeOptions() (err error) {
 	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -140,6 +144,14 @@ func (vcc VClusterCommands) VRemoveNode(options *VRemoveNodeOptions) (VCoordinat
 	var hostsNotInCatalog []string
 	options.HostsToRemove, hostsNotInCatalog = vdb.containNodes(options.HostsToRemove)
 
+	err = checkRemoveNodeSafety(&vdb, options.HostsToRemove)
+	if err != nil {
+		if !options.Force {
+			return vdb, fmt.Errorf("%w, use the force option to remove the nodes anyway", err)
+		}
+		vcc.Log.PrintWarning("Removing the nodes although %v", err)
+	}
+
 	vdb, err = vcc.removeNodesInCatalog(options, &vdb)
 	if err != nil || len(hostsNotInCatalog) == 0 {
 		return vdb, err
@@ -170,9 +182,8 @@ func (vcc VClusterCommands) removeNodesInCatalog(options *VRemoveNodeOptions, vd
 
 	remainingHosts := util.SliceDiff(vdb.HostList, options.HostsToRemove)
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	if runError := clusterOpEngine.run(vcc.Log); runError != nil {
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	if runError := clusterOpEngine.run(vcc.Context(), vcc.Log); runError != nil {
 		// If the machines of the to-be-removed nodes crashed or get killed,
 		// the run error may be ignored.
 		// Here we check whether the to-be-removed nodes are still in the catalog.
@@ -201,9 +212,8 @@ func (vcc VClusterCommands) handleRemoveNodeForHostsNotInCatalog(vdb *VCoordinat
 	nmaGetNodesInfoOp := makeNMAGetNodesInfoOp(missingHosts, options.DBName, options.CatalogPrefix,
 		false /* report all errors */, vdb)
 	instructions := []clusterOp{&nmaGetNodesInfoOp}
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	opEng := makeClusterOpEngine(instructions, &certs)
-	err := opEng.run(vcc.Log)
+	opEng := options.makeClusterOpEngine(instructions)
+	err := opEng.run(vcc.Context(), vcc.Log)
 	if err != nil {
 		return *vdb, fmt.Errorf("failed to get node info for missing hosts: %w", err)
 	}
@@ -223,8 +233,8 @@ func (vcc VClusterCommands) handleRemoveNodeForHostsNotInCatalog(vdb *VCoordinat
 		return *vdb, err
 	}
 	instructions = []clusterOp{&nmaDeleteDirectoriesOp}
-	opEng = makeClusterOpEngine(instructions, &certs)
-	err = opEng.run(vcc.Log)
+	opEng = options.makeClusterOpEngine(instructions)
+	err = opEng.run(vcc.Context(), vcc.Log)
 	if err != nil {
 		return *vdb, fmt.Errorf("failed to delete directories for missing hosts: %w", err)
 	}
@@ -238,7 +248,7 @@ func (vcc VClusterCommands) handleRemoveNodeForHostsNotInCatalog(vdb *VCoordinat
 func checkRemoveNodeRequirements(vdb *VCoordinationDatabase, options *VRemoveNodeOptions) error {
 	if !vdb.IsEon {
 		if vdb.hasAtLeastOneDownNode() {
-			return errors.New("all nodes must be up or standby")
+			return categorizeError(ErrNodeDown, errors.New("all nodes must be up or standby"))
 		}
 	}
 	// cannot remove sandboxed nodes
@@ -255,6 +265,45 @@ func checkRemoveNodeRequirements(vdb *VCoordinationDatabase, options *VRemoveNod
 	return nil
 }
 
+// checkRemoveNodeSafety returns an error if the main cluster would lose the
+// quorum of its primary nodes, its shard coverage, or its K-safety once the
+// given hosts are removed
+func checkRemoveNodeSafety(vdb *VCoordinationDatabase, hostsToRemove []string) error {
+	var remainingNodeCount, remainingPrimaryCount, remainingUpPrimaryCount int
+	for _, host := range util.SliceDiff(vdb.HostList, hostsToRemove) {
+		vnode, ok := vdb.HostNodeMap[host]
+		if !ok || vnode.Sandbox != util.MainClusterSandbox {
+			continue
+		}
+		remainingNodeCount++
+		if vnode.IsPrimary {
+			remainingPrimaryCount++
+			if vnode.State == util.NodeUpState {
+				remainingUpPrimaryCount++
+			}
+		}
+	}
+
+	if remainingPrimaryCount == 0 {
+		return errors.New("no primary node would remain in the database")
+	}
+	// an UP primary node subscribes to all the shards after the rebalance
+	if vdb.IsEon && remainingUpPrimaryCount == 0 {
+		return errors.New("no UP primary node would remain to cover the shards")
+	}
+	if remainingUpPrimaryCount*2 <= remainingPrimaryCount {
+		return categorizeError(ErrQuorumLost,
+			fmt.Errorf("only %d of the %d remaining primary nodes would be UP, which is not a quorum",
+				remainingUpPrimaryCount, remainingPrimaryCount))
+	}
+	// the design is marked K-safe 0 when fewer nodes than the threshold remain
+	if !vdb.IsEon && remainingNodeCount < ksafetyThreshold && len(vdb.HostList) >= ksafetyThreshold {
+		return fmt.Errorf("only %d node(s) would remain, which would lower the K-safety of the database to 0",
+			remainingNodeCount)
+	}
+	return nil
+}
+
 // completeVDBSetting sets some VCoordinationDatabase fields we cannot get yet
 // from the https endpoints. We set those fields from options.
 func (options *VRemoveNodeOptions) completeVDBSetting(vdb *VCoordinationDatabase) error {
@@ -0,0 +1,57 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+func TestCheckRemoveNodeSafety(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.IsEon = true
+	vdb.HostList = []string{"192.168.1.101", "192.168.1.102", "192.168.1.103", "192.168.1.104"}
+	vdb.HostNodeMap = vHostNodeMap{
+		"192.168.1.101": {Name: "v_db_node0001", IsPrimary: true, State: util.NodeUpState},
+		"192.168.1.102": {Name: "v_db_node0002", IsPrimary: true, State: util.NodeUpState},
+		"192.168.1.103": {Name: "v_db_node0003", IsPrimary: true, State: util.NodeDownState},
+		"192.168.1.104": {Name: "v_db_node0004", State: util.NodeUpState},
+	}
+
+	// removing a secondary node or a down primary node is safe
+	assert.NoError(t, checkRemoveNodeSafety(&vdb, []string{"192.168.1.104"}))
+	assert.NoError(t, checkRemoveNodeSafety(&vdb, []string{"192.168.1.103"}))
+
+	// one UP primary node out of two is not a quorum
+	err := checkRemoveNodeSafety(&vdb, []string{"192.168.1.101"})
+	assert.ErrorIs(t, err, ErrQuorumLost)
+
+	err = checkRemoveNodeSafety(&vdb, []string{"192.168.1.101", "192.168.1.102"})
+	assert.ErrorContains(t, err, "no UP primary node would remain to cover the shards")
+
+	err = checkRemoveNodeSafety(&vdb, []string{"192.168.1.101", "192.168.1.102", "192.168.1.103"})
+	assert.ErrorContains(t, err, "no primary node would remain")
+
+	// an Enterprise database loses its K-safety with fewer than 3 nodes
+	vdb.IsEon = false
+	vdb.HostNodeMap["192.168.1.103"].State = util.NodeUpState
+	vdb.HostNodeMap["192.168.1.104"].IsPrimary = true
+	assert.NoError(t, checkRemoveNodeSafety(&vdb, []string{"192.168.1.104"}))
+	err = checkRemoveNodeSafety(&vdb, []string{"192.168.1.103", "192.168.1.104"})
+	assert.ErrorContains(t, err, "lower the K-safety of the database to 0")
+}
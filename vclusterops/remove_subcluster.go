@@ -37,6 +37,10 @@ type VRemoveScOptions struct {
 	// A primary up host in another subcluster that belongs to same cluster as the target subcluster.
 	// This option will be used to do re-ip in the cluster.
 	PrimaryUpHost string
+	// Time in seconds to wait for the user sessions on the subcluster to end
+	// before its nodes are removed. New connections to the subcluster are
+	// paused first. Zero, the default, removes the nodes without draining.
+	DrainSeconds int
 }
 
 func VRemoveScOptionsFactory() VRemoveScOptions {
@@ -65,6 +69,10 @@ func (options *VRemoveScOptions) validateRequiredOptions(logger vlog.Printer) er
 	if err != nil {
 		return err
 	}
+
+	if options.DrainSeconds < 0 {
+		return fmt.Errorf("drain seconds cannot be negative: %d", options.DrainSeconds)
+	}
 	return nil
 }
 
@@ -114,7 +122,7 @@ func (options *VRemoveScOptions) analyzeOptions() (err error) {
 	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -137,8 +145,10 @@ func (options *VRemoveScOptions) validateAnalyzeOptions(logger vlog.Printer) err
 // VRemoveSubcluster removes a subcluster. It returns updated database catalog information and any error encountered.
 // VRemoveSubcluster has three major phases:
 //  1. Pre-check: check the subcluster name and get nodes for the subcluster.
-//  2. Removes nodes: Optional. If there are any nodes still associated with the subcluster, runs VRemoveNode.
-//  3. Drop the subcluster: Remove the subcluster name from the database catalog.
+//  2. Drain: Optional. If DrainSeconds is set, pauses new connections to the subcluster and waits
+//     for its user sessions to end.
+//  3. Removes nodes: Optional. If there are any nodes still associated with the subcluster, runs VRemoveNode.
+//  4. Drop the subcluster: Remove the subcluster name from the database catalog.
 func (vcc VClusterCommands) VRemoveSubcluster(removeScOpt *VRemoveScOptions) (VCoordinationDatabase, error) {
 	vdb := makeVCoordinationDatabase()
 
@@ -182,6 +192,14 @@ func (vcc VClusterCommands) VRemoveSubcluster(removeScOpt *VRemoveScOptions) (VC
 		needRemoveNodes = true
 	}
 
+	if needRemoveNodes && removeScOpt.DrainSeconds > 0 {
+		vcc.Log.PrintInfo("Draining the connections of subcluster %s", removeScOpt.SCName)
+		err = vcc.drainSubcluster(&vdb, removeScOpt)
+		if err != nil {
+			return vdb, err
+		}
+	}
+
 	if needRemoveNodes {
 		// Remove nodes from the target subcluster
 		removeNodeOpt := VRemoveNodeOptionsFactory()
@@ -213,7 +231,7 @@ type removeDefaultSubclusterError struct {
 }
 
 func (e *removeDefaultSubclusterError) Error() string {
-	return fmt.Sprintf("cannot remove the default subcluster '%s'", e.Name)
+	return localize(MsgRemoveDefaultSubcluster, e.Name)
 }
 
 // removeScPreCheck will build a list of instructions to perform
@@ -261,9 +279,8 @@ func (vcc VClusterCommands) removeScPreCheck(vdb *VCoordinationDatabase, options
 		&httpsFindSubclusterOp,
 	)
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	err = clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if err != nil {
 		// VER-88585 will improve this rfc error flow
 		if strings.Contains(err.Error(), "does not exist in the database") {
@@ -306,6 +323,62 @@ func (options *VRemoveScOptions) completeVDBSetting(vdb *VCoordinationDatabase)
 	return nil
 }
 
+// drainSubcluster pauses the new connections to the subcluster and waits up
+// to DrainSeconds for the user sessions on its nodes to end
+func (vcc VClusterCommands) drainSubcluster(vdb *VCoordinationDatabase, options *VRemoveScOptions) error {
+	var nodeNames []string
+	for _, vnode := range vdb.HostNodeMap {
+		if vnode.Subcluster == options.SCName {
+			nodeNames = append(nodeNames, vnode.Name)
+		}
+	}
+
+	instructions, err := options.produceDrainSubclusterInstructions(nodeNames)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions to drain subcluster %s, %w", options.SCName, err)
+	}
+
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		return fmt.Errorf("fail to drain subcluster %s, %w", options.SCName, err)
+	}
+	return nil
+}
+
+// produceDrainSubclusterInstructions will build a list of instructions to
+// drain the user sessions on the given nodes of a subcluster
+//
+// The generated instructions will later perform the following operations:
+//   - Get UP nodes through HTTPS call
+//   - Pause new connections to the subcluster
+//   - Wait for the user sessions on the subcluster nodes to end
+func (options *VRemoveScOptions) produceDrainSubclusterInstructions(nodeNames []string) ([]clusterOp, error) {
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password, ManageConnectionDrainingCmd)
+	if err != nil {
+		return nil, err
+	}
+	password, err := options.getRequestDataPassword()
+	if err != nil {
+		return nil, err
+	}
+	nmaManageConnectionsOp, err := makeNMAManageConnectionsOp(options.Hosts,
+		options.UserName, options.DBName, util.MainClusterSandbox, options.SCName, "",
+		ActionPause, password, options.usePassword)
+	if err != nil {
+		return nil, err
+	}
+	httpsPollActiveSessionsOp, err := makeHTTPSPollActiveSessionsOp(options.Hosts, options.usePassword,
+		options.UserName, options.Password, util.MainClusterSandbox, options.DrainSeconds)
+	if err != nil {
+		return nil, err
+	}
+	httpsPollActiveSessionsOp.nodeNames = nodeNames
+
+	return []clusterOp{&httpsGetUpNodesOp, &nmaManageConnectionsOp, &httpsPollActiveSessionsOp}, nil
+}
+
 func (vcc VClusterCommands) dropSubcluster(vdb *VCoordinationDatabase, options *VRemoveScOptions) error {
 	dropScErrMsg := fmt.Sprintf("fail to drop subcluster %s", options.SCName)
 
@@ -328,9 +401,8 @@ func (vcc VClusterCommands) dropSubcluster(vdb *VCoordinationDatabase, options *
 	var instructions []clusterOp
 	instructions = append(instructions, &httpsDropScOp)
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	err = clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if err != nil {
 		vcc.Log.Error(err, "fail to drop subcluster, details: %v", dropScErrMsg)
 		return err
@@ -57,3 +57,37 @@ func TestRemoveSubcluster(t *testing.T) {
 	err = options.validateParseOptions(vlog.Printer{})
 	assert.NoError(t, err)
 }
+
+func TestDrainSubclusterInstructions(t *testing.T) {
+	options := VRemoveScOptionsFactory()
+	options.Hosts = []string{"192.168.1.101", "192.168.1.102"}
+	options.DBName = dbName
+	options.UserName = "dbadmin"
+	options.Password = new(string)
+	options.SCName = "sc1"
+
+	// negative drain seconds are rejected
+	options.DrainSeconds = -1
+	err := options.validateRequiredOptions(vlog.Printer{})
+	assert.ErrorContains(t, err, "drain seconds cannot be negative")
+
+	options.DrainSeconds = 60
+	nodeNames := []string{"v_test_db_node0003", "v_test_db_node0004"}
+	instructions, err := options.produceDrainSubclusterInstructions(nodeNames)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"HTTPSGetUpNodesOp", "NMAManageConnectionsOp", "HTTPSPollActiveSessionsOp"},
+		getOpNames(instructions))
+
+	pollOp := instructions[2].(*httpsPollActiveSessionsOp)
+	assert.Equal(t, nodeNames, pollOp.nodeNames)
+	assert.Equal(t, 60, pollOp.getPollingTimeout())
+
+	// only the user sessions on the subcluster nodes are counted
+	sessions := sessionList{SessionList: []sessionInfo{
+		{NodeName: "v_test_db_node0001", IsInternal: false},
+		{NodeName: "v_test_db_node0003", IsInternal: false},
+		{NodeName: "v_test_db_node0004", IsInternal: true},
+	}}
+	assert.Equal(t, 1, sessions.countUserSessions(nodeNames))
+	assert.Equal(t, 2, sessions.countUserSessions(nil))
+}
@@ -56,7 +56,7 @@ func (options *VRenameSubclusterOptions) validateParseOptions(logger vlog.Printe
 	}
 
 	// need to provide a password or key and certs
-	if options.Password == nil && (options.Cert == "" || options.Key == "") {
+	if options.Password == nil && !options.hasCertsInOptions() {
 		// validate key and cert files in local file system
 		_, err = getCertFilePaths()
 		if err != nil {
@@ -91,7 +91,7 @@ func (options *VRenameSubclusterOptions) analyzeOptions() (err error) {
 	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -122,7 +122,12 @@ func (vcc VClusterCommands) VRenameSubcluster(options *VRenameSubclusterOptions)
 
 	// retrieve information from the database to accurately determine the state of each node in both the main cluster and sandbox
 	vdb := makeVCoordinationDatabase()
-	err = vcc.getVDBFromRunningDB(&vdb, &options.DatabaseOptions)
+	err = vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, options.Sandbox)
+	if err != nil {
+		return err
+	}
+
+	err = options.checkRenameSubcluster(&vdb)
 	if err != nil {
 		return err
 	}
@@ -134,11 +139,10 @@ func (vcc VClusterCommands) VRenameSubcluster(options *VRenameSubclusterOptions)
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to rename subcluster: %w", runError)
 	}
@@ -146,8 +150,26 @@ func (vcc VClusterCommands) VRenameSubcluster(options *VRenameSubclusterOptions)
 	return nil
 }
 
+// checkRenameSubcluster returns an error if the subcluster is not in the
+// given sandbox, or if the new name is already taken by another subcluster
+func (options *VRenameSubclusterOptions) checkRenameSubcluster(vdb *VCoordinationDatabase) error {
+	for _, vnode := range vdb.HostNodeMap {
+		if vnode.Subcluster == options.NewSCName {
+			return fmt.Errorf("subcluster %s already exists", options.NewSCName)
+		}
+		if vnode.Subcluster == options.SCName && vnode.Sandbox != options.Sandbox {
+			if vnode.Sandbox == util.MainClusterSandbox {
+				return fmt.Errorf("subcluster %s is in the main cluster, not in sandbox %s",
+					options.SCName, options.Sandbox)
+			}
+			return fmt.Errorf("subcluster %s is in sandbox %s", options.SCName, vnode.Sandbox)
+		}
+	}
+	return nil
+}
+
 // The generated instructions will later perform the following operations necessary
-// for a successful promote/demote subcluster operation:
+// for a successful rename subcluster operation:
 // - Rename subclusters using one of the up nodes
 func (vcc VClusterCommands) produceRenameSubclusterInstructions(options *VRenameSubclusterOptions,
 	vdb *VCoordinationDatabase) ([]clusterOp, error) {
@@ -73,3 +73,29 @@ func TestVRenameSubclusterOptions_validateParseOptions(t *testing.T) {
 	err = opt.validateParseOptions(logger)
 	assert.ErrorContains(t, err, "rename subcluster is only supported in Eon mode")
 }
+
+func TestCheckRenameSubcluster(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	vdb.HostNodeMap["192.168.1.101"] = &VCoordinationNode{Address: "192.168.1.101", Subcluster: "sc1"}
+	vdb.HostNodeMap["192.168.1.102"] = &VCoordinationNode{Address: "192.168.1.102", Subcluster: "sc2", Sandbox: "sand1"}
+
+	opt := VRenameSubclusterFactory()
+	opt.SCName = "sc1"
+	opt.NewSCName = "sc3"
+	assert.NoError(t, opt.checkRenameSubcluster(&vdb))
+
+	// negative: the new name is taken
+	opt.NewSCName = "sc2"
+	assert.ErrorContains(t, opt.checkRenameSubcluster(&vdb), "subcluster sc2 already exists")
+
+	// negative: the subcluster is not in the given sandbox
+	opt.NewSCName = "sc3"
+	opt.Sandbox = "sand1"
+	assert.ErrorContains(t, opt.checkRenameSubcluster(&vdb), "is in the main cluster, not in sandbox sand1")
+
+	opt.SCName = "sc2"
+	assert.NoError(t, opt.checkRenameSubcluster(&vdb))
+	opt.Sandbox = ""
+	assert.ErrorContains(t, opt.checkRenameSubcluster(&vdb), "subcluster sc2 is in sandbox sand1")
+}
@@ -33,7 +33,42 @@ type VReplicationDatabaseOptions struct {
 	TargetUserName  string
 	TargetPassword  *string
 	SourceTLSConfig string
-	SandboxName     string
+	// Source sandbox to replicate from. To replicate to a sandbox of the target
+	// database, set TargetHosts to hosts of that sandbox.
+	SandboxName string
+
+	/* part 3: objects to replicate, the whole database by default */
+	// A schema or a table to replicate, e.g. "schema1" or "schema1.table1"
+	TableOrSchemaName string
+	// Pattern of the objects to replicate, e.g. "schema1.*". It cannot be used
+	// with TableOrSchemaName.
+	IncludePattern string
+	// Pattern of the objects to leave out of IncludePattern
+	ExcludePattern string
+
+	// If true, return as soon as the replication is started, and use the
+	// returned transaction ID to check its status
+	Async bool
+}
+
+// ReplicationStatus describes a replication to a target database
+type ReplicationStatus struct {
+	TargetDB string
+	// ID of the transaction that runs an asynchronous replication
+	TransactionID int64
+	Async         bool
+	// whether the replication has completed
+	Completed bool
+
+	// progress of an asynchronous replication, see VGetReplicationStatus
+	Phase            string
+	BytesTransferred int64
+	TotalBytes       int64
+	TablesDone       int
+	TablesTotal      int
+	// whether the replication has failed, and why
+	Failed        bool
+	FailureDetail string
 }
 
 func VReplicationDatabaseFactory() VReplicationDatabaseOptions {
@@ -43,6 +78,11 @@ func VReplicationDatabaseFactory() VReplicationDatabaseOptions {
 	return options
 }
 
+// SetTargetPassword sets the password of the user on the target database
+func (options *VReplicationDatabaseOptions) SetTargetPassword(password string) {
+	options.TargetPassword = &password
+}
+
 func (options *VReplicationDatabaseOptions) validateRequiredOptions(logger vlog.Printer) error {
 	err := options.validateBaseOptions(commandReplicationStart, logger)
 	if err != nil {
@@ -73,7 +113,7 @@ func (options *VReplicationDatabaseOptions) validateExtraOptions() error {
 	}
 
 	// need to provide a password or key and certs
-	if options.Password == nil && (options.Cert == "" || options.Key == "") {
+	if options.Password == nil && !options.hasCertsInOptions() {
 		// validate key and cert files in local file system
 		_, err = getCertFilePaths()
 		if err != nil {
@@ -97,6 +137,17 @@ func (options *VReplicationDatabaseOptions) validateExtraOptions() error {
 		}
 	}
 
+	return options.validateObjectOptions()
+}
+
+// validateObjectOptions checks the options that select the objects to replicate
+func (options *VReplicationDatabaseOptions) validateObjectOptions() error {
+	if options.TableOrSchemaName != "" && options.IncludePattern != "" {
+		return fmt.Errorf("cannot specify both a table or schema name and an include pattern")
+	}
+	if options.ExcludePattern != "" && options.IncludePattern == "" {
+		return fmt.Errorf("an exclude pattern requires an include pattern")
+	}
 	return nil
 }
 
@@ -125,7 +176,7 @@ func (options *VReplicationDatabaseOptions) validateParseOptions(logger vlog.Pri
 func (options *VReplicationDatabaseOptions) analyzeOptions() (err error) {
 	if len(options.TargetHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		options.TargetHosts, err = util.ResolveRawHostsToAddresses(options.TargetHosts, options.IPv6)
+		options.TargetHosts, err = options.resolveRawHosts(options.TargetHosts)
 		if err != nil {
 			return err
 		}
@@ -133,7 +184,7 @@ func (options *VReplicationDatabaseOptions) analyzeOptions() (err error) {
 	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		hostAddresses, err := util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		hostAddresses, err := options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -149,8 +200,10 @@ func (options *VReplicationDatabaseOptions) validateAnalyzeOptions(logger vlog.P
 	return options.analyzeOptions()
 }
 
-// VReplicateDatabase can copy all table data and metadata from this cluster to another
-func (vcc VClusterCommands) VReplicateDatabase(options *VReplicationDatabaseOptions) error {
+// VReplicateDatabase can copy all table data and metadata from this cluster to another,
+// or only the selected schemas and tables. An asynchronous replication returns once
+// it is started, with the ID of its transaction in the returned status.
+func (vcc VClusterCommands) VReplicateDatabase(options *VReplicationDatabaseOptions) (ReplicationStatus, error) {
 	/*
 	 *   - Produce Instructions
 	 *   - Create a VClusterOpEngine
@@ -158,30 +211,30 @@ func (vcc VClusterCommands) VReplicateDatabase(options *VReplicationDatabaseOpti
 	 */
 
 	// validate and analyze options
+	status := ReplicationStatus{TargetDB: options.TargetDB, Async: options.Async}
 	err := options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return err
+		return status, err
 	}
 
 	// retrieve information from the database to accurately determine the state of each node in both the main cluster and andbox
 	vdb := makeVCoordinationDatabase()
 	err = vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, AnySandbox)
 	if err != nil {
-		return err
+		return status, err
 	}
 
 	// produce database replication instructions
-	instructions, err := vcc.produceDBReplicationInstructions(options, &vdb)
+	instructions, err := vcc.produceDBReplicationInstructions(options, &vdb, &status)
 	if err != nil {
-		return fmt.Errorf("fail to produce instructions, %w", err)
+		return status, fmt.Errorf("fail to produce instructions, %w", err)
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		if strings.Contains(runError.Error(), "EnableConnectCredentialForwarding is false") {
 			runError = fmt.Errorf("target database authentication failed, need to do one of the following things: " +
@@ -189,9 +242,9 @@ func (vcc VClusterCommands) VReplicateDatabase(options *VReplicationDatabaseOpti
 				"2. set EnableConnectCredentialForwarding to True in source database using vsql " +
 				"3. configure a Trust Authentication in target database using vsql")
 		}
-		return fmt.Errorf("fail to replicate database: %w", runError)
+		return status, fmt.Errorf("fail to replicate database: %w", runError)
 	}
-	return nil
+	return status, nil
 }
 
 // The generated instructions will later perform the following operations necessary
@@ -199,8 +252,11 @@ func (vcc VClusterCommands) VReplicateDatabase(options *VReplicationDatabaseOpti
 //   - Check NMA connectivity
 //   - Check Vertica versions
 //   - Replicate database
+//
+// The replication op fills the given status once the replication is started
+// or completed.
 func (vcc VClusterCommands) produceDBReplicationInstructions(options *VReplicationDatabaseOptions,
-	vdb *VCoordinationDatabase) ([]clusterOp, error) {
+	vdb *VCoordinationDatabase, status *ReplicationStatus) ([]clusterOp, error) {
 	var instructions []clusterOp
 
 	// need username for https operations in source database
@@ -241,6 +297,11 @@ func (vcc VClusterCommands) produceDBReplicationInstructions(options *VReplicati
 	if err != nil {
 		return instructions, err
 	}
+	httpsStartReplicationOp.tableOrSchemaName = options.TableOrSchemaName
+	httpsStartReplicationOp.includePattern = options.IncludePattern
+	httpsStartReplicationOp.excludePattern = options.ExcludePattern
+	httpsStartReplicationOp.async = options.Async
+	httpsStartReplicationOp.status = status
 
 	instructions = append(instructions,
 		&nmaHealthOp,
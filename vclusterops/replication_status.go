@@ -0,0 +1,98 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// VReplicationStatusDatabaseOptions are the options to get the status of an
+// asynchronous replication. DatabaseOptions describe the target database of
+// the replication.
+type VReplicationStatusDatabaseOptions struct {
+	DatabaseOptions
+	// ID of the transaction returned by VReplicateDatabase
+	TransactionID int64
+}
+
+func VReplicationStatusDatabaseFactory() VReplicationStatusDatabaseOptions {
+	options := VReplicationStatusDatabaseOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VReplicationStatusDatabaseOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandReplicationStatus, logger)
+	if err != nil {
+		return err
+	}
+	if options.TransactionID <= 0 {
+		return fmt.Errorf("must specify the transaction ID of the replication")
+	}
+	return nil
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VReplicationStatusDatabaseOptions) analyzeOptions() (err error) {
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VReplicationStatusDatabaseOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	return options.setUsePasswordAndValidateUsernameIfNeeded(logger)
+}
+
+// VGetReplicationStatus returns the progress of an asynchronous replication,
+// as reported by the target database. It does not wait for the replication
+// to complete, so the callers can call it repeatedly to track the progress
+// and apply their own timeouts.
+func (vcc VClusterCommands) VGetReplicationStatus(options *VReplicationStatusDatabaseOptions) (ReplicationStatus, error) {
+	status := ReplicationStatus{TargetDB: options.DBName, TransactionID: options.TransactionID, Async: true}
+
+	// validate and analyze options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return status, err
+	}
+
+	httpsGetReplicationStatusOp, err := makeHTTPSGetReplicationStatusOp(options.Hosts, options.usePassword,
+		options.UserName, options.Password, options.TransactionID, &status)
+	if err != nil {
+		return status, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	clusterOpEngine := options.makeClusterOpEngine([]clusterOp{&httpsGetReplicationStatusOp})
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		return status, fmt.Errorf("fail to get replication status: %w", err)
+	}
+	return status, nil
+}
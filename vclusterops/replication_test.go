@@ -0,0 +1,103 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicationObjectOptions(t *testing.T) {
+	options := VReplicationDatabaseFactory()
+	assert.NoError(t, options.validateObjectOptions())
+
+	options.TableOrSchemaName = "schema1.table1"
+	assert.NoError(t, options.validateObjectOptions())
+
+	// negative: both an object name and an include pattern
+	options.IncludePattern = "schema1.*"
+	assert.ErrorContains(t, options.validateObjectOptions(), "cannot specify both")
+
+	options.TableOrSchemaName = ""
+	options.ExcludePattern = "schema1.tmp_*"
+	assert.NoError(t, options.validateObjectOptions())
+
+	// negative: an exclude pattern without an include pattern
+	options.IncludePattern = ""
+	assert.ErrorContains(t, options.validateObjectOptions(), "requires an include pattern")
+}
+
+func TestStartAsyncReplication(t *testing.T) {
+	const host = "192.168.1.101"
+	status := ReplicationStatus{TargetDB: "target_db", Async: true}
+	op, err := makeHTTPSStartReplicationOp(dbName, []string{host}, false, "", nil,
+		false, "target_db", "", "192.168.1.201", nil, "", "", nil)
+	assert.NoError(t, err)
+	op.async = true
+	op.includePattern = "schema1.*"
+	op.status = &status
+	op.setupBasicInfo()
+
+	assert.NoError(t, op.setupRequestBody([]string{host}))
+	assert.Contains(t, op.hostRequestBodyMap[host], `"include_pattern":"schema1.*"`)
+	assert.NotContains(t, op.hostRequestBodyMap[host], "table_or_schema_name")
+	assert.NoError(t, op.setupClusterHTTPRequest([]string{host}))
+	assert.Contains(t, op.clusterHTTPRequest.RequestCollection[host].Endpoint, "replicate/async")
+
+	assert.NoError(t, op.processAsyncResult(host, `{"transaction_id": 45035996273704962}`))
+	assert.Equal(t, int64(45035996273704962), status.TransactionID)
+
+	err = op.processAsyncResult(host, `{}`)
+	assert.ErrorContains(t, err, "does not contain a transaction ID")
+}
+
+func TestGetReplicationStatus(t *testing.T) {
+	const host = "192.168.1.201"
+	status := ReplicationStatus{TransactionID: 45035996273704962, Async: true}
+	op, err := makeHTTPSGetReplicationStatusOp([]string{host}, false, "", nil, status.TransactionID, &status)
+	assert.NoError(t, err)
+	op.setupBasicInfo()
+	assert.NoError(t, op.setupClusterHTTPRequest([]string{host}))
+	assert.Equal(t, "45035996273704962", op.clusterHTTPRequest.RequestCollection[host].QueryParams["transaction_id"])
+
+	// a replication in progress
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: SUCCESS, statusCode: SuccessCode, host: host, content: `{"transaction_id": 45035996273704962,
+			"phase": "data_transfer", "status": "running", "bytes_transferred": 1024, "total_bytes": 4096,
+			"tables_done": 3, "tables_total": 10}`},
+	}
+	assert.NoError(t, op.processResult(nil))
+	assert.Equal(t, "data_transfer", status.Phase)
+	assert.Equal(t, int64(1024), status.BytesTransferred)
+	assert.Equal(t, int64(4096), status.TotalBytes)
+	assert.Equal(t, 3, status.TablesDone)
+	assert.Equal(t, 10, status.TablesTotal)
+	assert.False(t, status.Completed)
+	assert.False(t, status.Failed)
+
+	// a failed replication
+	op.clusterHTTPRequest.ResultCollection[host] = hostHTTPResult{status: SUCCESS, statusCode: SuccessCode, host: host,
+		content: `{"transaction_id": 45035996273704962, "phase": "commit", "status": "failed", "error": "out of space"}`}
+	assert.NoError(t, op.processResult(nil))
+	assert.True(t, status.Failed)
+	assert.Equal(t, "out of space", status.FailureDetail)
+
+	// negative: the status of another transaction
+	op.clusterHTTPRequest.ResultCollection[host] = hostHTTPResult{status: SUCCESS, statusCode: SuccessCode, host: host,
+		content: `{"transaction_id": 1, "status": "completed"}`}
+	assert.ErrorContains(t, op.processResult(nil), "rather than 45035996273704962")
+}
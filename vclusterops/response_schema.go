@@ -0,0 +1,177 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// schemaTagName is the struct tag used by versioned response structs to mark
+// fields that must be present in a response. For example:
+//
+//	Name string `json:"name" validate:"required"`
+const (
+	schemaTagName     = "validate"
+	schemaTagRequired = "required"
+)
+
+// versionedResponse is implemented by the response structs of NMA and HTTPS
+// endpoints. Each struct declares the endpoint versions it knows how to
+// decode, so a response can only be parsed into a struct that matches the
+// version of the endpoint the request was sent to.
+type versionedResponse interface {
+	supportedVersions() []string
+}
+
+// ResponseValidationError is returned when the response of an NMA or HTTPS
+// endpoint does not match the schema expected for the endpoint version.
+type ResponseValidationError struct {
+	OpName   string
+	Host     string
+	Endpoint string
+	// Field is the JSON path of the offending field, it is empty when the
+	// whole response is rejected (e.g., unsupported endpoint version)
+	Field  string
+	Reason string
+}
+
+func (e *ResponseValidationError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("[%s] invalid response from endpoint %s on host %s: %s",
+			e.OpName, e.Endpoint, e.Host, e.Reason)
+	}
+	return fmt.Sprintf("[%s] invalid response from endpoint %s on host %s: field %q %s",
+		e.OpName, e.Endpoint, e.Host, e.Field, e.Reason)
+}
+
+// getEndpointVersion returns the version prefix of an endpoint, e.g., "v1/"
+// for "v1/network-profiles"
+func getEndpointVersion(endpoint string) string {
+	version, _, found := strings.Cut(endpoint, "/")
+	if !found {
+		return ""
+	}
+	return version + "/"
+}
+
+// validateVersionedResponse checks that the response struct supports the
+// version of the endpoint, if the endpoint is known, then checks every field
+// tagged as required
+func validateVersionedResponse(opName, host, endpoint string, responseObj versionedResponse) error {
+	validationErr := &ResponseValidationError{
+		OpName:   opName,
+		Host:     host,
+		Endpoint: endpoint,
+	}
+
+	version := getEndpointVersion(endpoint)
+	if endpoint != "" && !slices.Contains(responseObj.supportedVersions(), version) {
+		validationErr.Reason = fmt.Sprintf("endpoint version %q is not supported, supported versions: %v",
+			version, responseObj.supportedVersions())
+		return validationErr
+	}
+
+	field, reason := findInvalidField(reflect.ValueOf(responseObj), "")
+	if reason != "" {
+		validationErr.Field = field
+		validationErr.Reason = reason
+		return validationErr
+	}
+	return nil
+}
+
+// findInvalidField walks through the response object and returns the path of
+// the first required field that is missing, along with the reason
+func findInvalidField(v reflect.Value, fieldPath string) (field, reason string) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return fieldPath, "is missing"
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			structField := v.Type().Field(i)
+			if !structField.IsExported() {
+				continue
+			}
+			path := joinFieldPath(fieldPath, getJSONFieldName(&structField))
+			if structField.Tag.Get(schemaTagName) == schemaTagRequired && v.Field(i).IsZero() {
+				return path, "is required but missing or empty"
+			}
+			if field, reason = findInvalidField(v.Field(i), path); reason != "" {
+				return field, reason
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if field, reason = findInvalidField(v.Index(i), fmt.Sprintf("%s[%d]", fieldPath, i)); reason != "" {
+				return field, reason
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			path := joinFieldPath(fieldPath, fmt.Sprintf("%v", iter.Key().Interface()))
+			if field, reason = findInvalidField(iter.Value(), path); reason != "" {
+				return field, reason
+			}
+		}
+	default:
+		// scalar fields are checked by their parent struct
+	}
+	return "", ""
+}
+
+// getJSONFieldName returns the name of the field in the JSON response
+func getJSONFieldName(structField *reflect.StructField) string {
+	name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return structField.Name
+	}
+	return name
+}
+
+func joinFieldPath(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+	return parent + "." + child
+}
+
+// parseAndValidateResponse parses the response content from a host into a
+// versioned response struct, and validates it against the version of the
+// endpoint the request was sent to
+func (op *opBase) parseAndValidateResponse(host, responseContent string, responseObj versionedResponse) error {
+	err := op.parseAndCheckResponse(host, responseContent, responseObj)
+	if err != nil {
+		return err
+	}
+
+	endpoint := op.clusterHTTPRequest.RequestCollection[host].Endpoint
+	err = validateVersionedResponse(op.name, host, endpoint, responseObj)
+	if err != nil {
+		op.logger.Error(err, "fail to validate response on host, detail", "host", host)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type networkProfileList []NetworkProfile
+
+func (networkProfileList) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
+func TestValidateVersionedResponse(t *testing.T) {
+	const host = "192.168.1.101"
+	const endpoint = NMAVersion1 + "network-profiles"
+
+	profile := NetworkProfile{
+		Name:      "eth0",
+		Address:   host,
+		Subnet:    "192.168.0.0/16",
+		Netmask:   "255.255.0.0",
+		Broadcast: "192.168.255.255",
+	}
+	err := validateVersionedResponse("NMANetworkProfileOp", host, endpoint, &profile)
+	assert.NoError(t, err)
+
+	// a missing field should be reported with its JSON name, the endpoint and the host
+	profile.Broadcast = ""
+	err = validateVersionedResponse("NMANetworkProfileOp", host, endpoint, &profile)
+	var validationErr *ResponseValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "broadcast", validationErr.Field)
+	assert.Equal(t, endpoint, validationErr.Endpoint)
+	assert.Equal(t, host, validationErr.Host)
+
+	// fields of slice elements are reported with their index
+	profiles := networkProfileList{profile}
+	profiles[0].Broadcast = "192.168.255.255"
+	profiles = append(profiles, NetworkProfile{Name: "eth1", Address: host})
+	err = validateVersionedResponse("NMANetworkProfileOp", host, endpoint, &profiles)
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "[1].subnet", validationErr.Field)
+
+	// an endpoint version that the response struct does not know about is rejected
+	err = validateVersionedResponse("NMANetworkProfileOp", host, "v2/network-profiles", &NetworkProfile{})
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Empty(t, validationErr.Field)
+	assert.ErrorContains(t, err, `endpoint version "v2/" is not supported`)
+
+	// the version is not checked when the endpoint of the request is unknown
+	assert.NoError(t, validateVersionedResponse("NMANetworkProfileOp", host, "", &profiles[0]))
+}
+
+func TestSkipInvalidRestorePoints(t *testing.T) {
+	const host = "192.168.1.101"
+	op := makeNMAShowRestorePointsOp(vlog.Printer{}, []string{host}, "test_db", "/communal", nil)
+	op.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{host: {Endpoint: NMAVersion1 + "restore-points"}}
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{host: {status: SUCCESS, statusCode: SuccessCode, host: host,
+		content: `[{"archive": "db", "id": "4ee4119b-802c-4bb4-94b0-061c8748b602", "index": 1}, {"archive": "db", "index": 2}]`}}
+	execContext := makeOpEngineExecContext(context.Background(), vlog.Printer{})
+
+	// the restore point without an ID is skipped with a warning
+	assert.NoError(t, op.processResult(&execContext))
+	restorePoints, listed := execContext.RestorePoints()
+	assert.True(t, listed)
+	assert.Equal(t, []RestorePoint{{Archive: "db", ID: "4ee4119b-802c-4bb4-94b0-061c8748b602", Index: 1}}, restorePoints)
+	assert.Len(t, op.warnings, 1)
+}
@@ -18,6 +18,9 @@ package vclusterops
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
@@ -107,7 +110,7 @@ func (options *VShowRestorePointsOptions) validateRequiredOptions(logger vlog.Pr
 		return err
 	}
 
-	err = util.ValidateCommunalStorageLocation(options.CommunalStorageLocation)
+	err = util.ValidateCommunalStorage(options.CommunalStorageLocation, options.ConfigurationParameters)
 	if err != nil {
 		return err
 	}
@@ -120,9 +123,37 @@ func (options *VShowRestorePointsOptions) validateExtraOptions() error {
 		return err
 	}
 
+	if options.FilterOptions.ArchiveName != "" {
+		err = validateArchiveName(options.FilterOptions.ArchiveName)
+		if err != nil {
+			return err
+		}
+	}
+	if options.FilterOptions.ArchiveIndex != "" {
+		index, err := strconv.Atoi(options.FilterOptions.ArchiveIndex)
+		if err != nil || index <= 0 {
+			return fmt.Errorf("restore point index %q must be a positive integer", options.FilterOptions.ArchiveIndex)
+		}
+	}
 	return nil
 }
 
+// GetTimestamp parses the timestamp when the restore point was created, which is in UTC
+func (restorePoint *RestorePoint) GetTimestamp() (time.Time, error) {
+	return time.Parse(util.DefaultDateTimeFormat, restorePoint.Timestamp)
+}
+
+// sortRestorePoints sorts the restore points by archive name, and then from
+// the most recent to the oldest in each archive
+func sortRestorePoints(restorePoints []RestorePoint) {
+	sort.SliceStable(restorePoints, func(i, j int) bool {
+		if restorePoints[i].Archive != restorePoints[j].Archive {
+			return restorePoints[i].Archive < restorePoints[j].Archive
+		}
+		return restorePoints[i].Index < restorePoints[j].Index
+	})
+}
+
 func (options *VShowRestorePointsOptions) validateParseOptions(logger vlog.Printer) error {
 	// batch 1: validate required parameters
 	err := options.validateRequiredOptions(logger)
@@ -143,7 +174,7 @@ func (options *VShowRestorePointsOptions) analyzeOptions() (err error) {
 	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		hostAddresses, err := util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		hostAddresses, err := options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -159,7 +190,10 @@ func (options *VShowRestorePointsOptions) validateAnalyzeOptions(logger vlog.Pri
 	return options.analyzeOptions()
 }
 
-// VShowRestorePoints can query the restore points from an archive
+// VShowRestorePoints queries the restore points in the archives of the
+// database from communal storage, without the database running. The options
+// can filter them by archive name, ID, index and creation time. The restore
+// points are sorted by archive name, and from the most recent in each archive.
 func (vcc VClusterCommands) VShowRestorePoints(options *VShowRestorePointsOptions) (restorePoints []RestorePoint, err error) {
 	/*
 	 *   - Produce Instructions
@@ -180,15 +214,15 @@ func (vcc VClusterCommands) VShowRestorePoints(options *VShowRestorePointsOption
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		return restorePoints, fmt.Errorf("fail to show restore points: %w", runError)
 	}
-	restorePoints = clusterOpEngine.execContext.restorePoints
+	restorePoints, _ = clusterOpEngine.execContext.RestorePoints()
+	sortRestorePoints(restorePoints)
 	return restorePoints, nil
 }
 
@@ -201,7 +235,10 @@ func (vcc VClusterCommands) produceShowRestorePointsInstructions(options *VShowR
 	var instructions []clusterOp
 
 	hosts := options.Hosts
-	initiator := getInitiator(hosts)
+	initiator, err := options.selectInitiator(vcc.Context(), hosts)
+	if err != nil {
+		return instructions, err
+	}
 	bootstrapHost := []string{initiator}
 
 	nmaHealthOp := makeNMAHealthOp(hosts)
@@ -211,6 +248,7 @@ func (vcc VClusterCommands) produceShowRestorePointsInstructions(options *VShowR
 
 	nmaShowRestorePointOp := makeNMAShowRestorePointsOpWithFilterOptions(vcc.Log, bootstrapHost, options.DBName,
 		options.CommunalStorageLocation, options.ConfigurationParameters, &options.FilterOptions)
+	nmaShowRestorePointOp.fallbackInitiators = hosts
 
 	instructions = append(instructions,
 		&nmaHealthOp,
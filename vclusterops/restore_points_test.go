@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
 func TestShowRestorePointFilterOptions_ValidateAndStandardizeTimestampsIfAny(t *testing.T) {
@@ -84,3 +85,94 @@ func TestShowRestorePointFilterOptions_ValidateAndStandardizeTimestampsIfAny(t *
 	err = filterOptions.ValidateAndStandardizeTimestampsIfAny()
 	assert.EqualError(t, err, "start timestamp must be before end timestamp")
 }
+
+func TestRestorePointLifecycleOptions(t *testing.T) {
+	password := "password"
+	setBaseOptions := func(opt *DatabaseOptions) {
+		opt.DBName = "test_db"
+		opt.RawHosts = []string{"192.168.1.101"}
+		opt.Password = &password
+	}
+
+	createOptions := VCreateArchiveOptionsFactory()
+	setBaseOptions(&createOptions.DatabaseOptions)
+	err := createOptions.validateParseOptions(vlog.Printer{})
+	assert.ErrorContains(t, err, "must specify an archive name")
+	createOptions.ArchiveName = "daily/archive"
+	err = createOptions.validateParseOptions(vlog.Printer{})
+	assert.ErrorContains(t, err, "invalid character in archive name")
+	createOptions.ArchiveName = "daily"
+	createOptions.NumRestorePoints = -1
+	err = createOptions.validateParseOptions(vlog.Printer{})
+	assert.ErrorContains(t, err, "must not be negative")
+	createOptions.NumRestorePoints = 7
+	assert.NoError(t, createOptions.validateParseOptions(vlog.Printer{}))
+
+	saveOptions := VSaveRestorePointOptionsFactory()
+	setBaseOptions(&saveOptions.DatabaseOptions)
+	saveOptions.ArchiveName = "daily"
+	assert.NoError(t, saveOptions.validateParseOptions(vlog.Printer{}))
+
+	removeOptions := VRemoveRestorePointOptionsFactory()
+	setBaseOptions(&removeOptions.DatabaseOptions)
+	removeOptions.ArchiveName = "daily"
+	err = removeOptions.validateParseOptions(vlog.Printer{})
+	assert.ErrorContains(t, err, "must specify the ID of the restore point")
+	removeOptions.RestorePointID = "8f3d0ad1"
+	assert.NoError(t, removeOptions.validateParseOptions(vlog.Printer{}))
+}
+
+func TestRestorePointLifecycleRequests(t *testing.T) {
+	createOp, err := makeHTTPSCreateArchiveOp(false, "", nil, "daily", 7)
+	assert.NoError(t, err)
+	createOp.setupBasicInfo()
+	assert.NoError(t, createOp.setupClusterHTTPRequest([]string{"host1"}))
+	request := createOp.clusterHTTPRequest.RequestCollection["host1"]
+	assert.Equal(t, PostMethod, request.Method)
+	assert.Contains(t, request.Endpoint, "archives/daily")
+	assert.Equal(t, "7", request.QueryParams["num-restore-points"])
+
+	saveOp, err := makeHTTPSSaveRestorePointOp(false, "", nil, "daily")
+	assert.NoError(t, err)
+	saveOp.setupBasicInfo()
+	assert.NoError(t, saveOp.setupClusterHTTPRequest([]string{"host1"}))
+	request = saveOp.clusterHTTPRequest.RequestCollection["host1"]
+	assert.Equal(t, PostMethod, request.Method)
+	assert.Contains(t, request.Endpoint, "archives/daily/restore-points")
+
+	removeOp, err := makeHTTPSRemoveRestorePointOp(false, "", nil, "daily", "8f3d0ad1")
+	assert.NoError(t, err)
+	removeOp.setupBasicInfo()
+	assert.NoError(t, removeOp.setupClusterHTTPRequest([]string{"host1"}))
+	request = removeOp.clusterHTTPRequest.RequestCollection["host1"]
+	assert.Equal(t, DeleteMethod, request.Method)
+	assert.Contains(t, request.Endpoint, "archives/daily/restore-points/8f3d0ad1")
+}
+
+func TestShowRestorePointsFilterAndSort(t *testing.T) {
+	options := VShowRestorePointsFactory()
+	options.FilterOptions.ArchiveIndex = "0"
+	assert.ErrorContains(t, options.validateExtraOptions(), "must be a positive integer")
+	options.FilterOptions.ArchiveIndex = "2"
+	options.FilterOptions.ArchiveName = "db;"
+	assert.ErrorContains(t, options.validateExtraOptions(), "invalid character in archive name")
+	options.FilterOptions.ArchiveName = "db"
+	assert.NoError(t, options.validateExtraOptions())
+
+	restorePoints := []RestorePoint{
+		{Archive: "db2", ID: "c", Index: 1},
+		{Archive: "db1", ID: "b", Index: 2},
+		{Archive: "db1", ID: "a", Index: 1, Timestamp: "2023-05-02 14:10:31.038289"},
+	}
+	sortRestorePoints(restorePoints)
+	assert.Equal(t, "a", restorePoints[0].ID)
+	assert.Equal(t, "b", restorePoints[1].ID)
+	assert.Equal(t, "c", restorePoints[2].ID)
+
+	timestamp, err := restorePoints[0].GetTimestamp()
+	assert.NoError(t, err)
+	assert.Equal(t, 2023, timestamp.Year())
+	assert.Equal(t, 38289000, timestamp.Nanosecond())
+	_, err = restorePoints[1].GetTimestamp()
+	assert.Error(t, err)
+}
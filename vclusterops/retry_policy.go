@@ -0,0 +1,103 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"syscall"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// RetryPolicy controls how the ops send a request to a host again after a
+// transient failure, such as a refused connection while the node is starting,
+// instead of failing the whole command on a single blip. The zero value
+// disables retries.
+type RetryPolicy struct {
+	// maximum number of attempts of a request, including the first one.
+	// A value less than 2 disables retries.
+	MaxAttempts int
+	// wait before the first retry, doubled for each further retry
+	InitialBackoff time.Duration
+	// upper limit of the wait between two attempts, 0 for no limit
+	MaxBackoff time.Duration
+	// HTTP status codes of the responses to retry, e.g., 503
+	RetryableStatusCodes []int
+	// errors of the requests to retry, matched with errors.Is,
+	// e.g., syscall.ECONNREFUSED
+	RetryableErrors []error
+}
+
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = time.Second
+	defaultRetryMaxBackoff     = 10 * time.Second
+)
+
+// DefaultRetryPolicy returns a policy that retries the requests refused or
+// reset by the hosts, or rejected as unavailable, up to 3 times
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:          defaultRetryMaxAttempts,
+		InitialBackoff:       defaultRetryInitialBackoff,
+		MaxBackoff:           defaultRetryMaxBackoff,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		RetryableErrors:      []error{syscall.ECONNREFUSED, syscall.ECONNRESET, io.EOF},
+	}
+}
+
+func (policy *RetryPolicy) isEnabled() bool {
+	return policy != nil && policy.MaxAttempts > 1
+}
+
+// isRetryable returns true if the request of the result can be sent again
+func (policy *RetryPolicy) isRetryable(result *hostHTTPResult) bool {
+	if result.isPassing() {
+		return false
+	}
+	// a canceled or timed out operation should stop as soon as possible
+	if errors.Is(result.err, context.Canceled) || errors.Is(result.err, context.DeadlineExceeded) {
+		return false
+	}
+	if result.statusCode != 0 && slices.Contains(policy.RetryableStatusCodes, result.statusCode) {
+		return true
+	}
+	for _, retryableErr := range policy.RetryableErrors {
+		if errors.Is(result.err, retryableErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// getBackoff returns the wait before the given retry, which starts from 1
+func (policy *RetryPolicy) getBackoff(retry int) time.Duration {
+	backoff := policy.InitialBackoff
+	for i := 1; i < retry; i++ {
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff >= policy.MaxBackoff {
+			break
+		}
+	}
+	if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+		return policy.MaxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,144 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// mockFlakyAdapter refuses the connection for the first failures requests
+type mockFlakyAdapter struct {
+	host        string
+	failures    int
+	sent        int
+	lastRequest hostHTTPRequest
+}
+
+func (m *mockFlakyAdapter) sendRequest(_ context.Context, request *hostHTTPRequest, resultChannel chan<- hostHTTPResult) {
+	m.sent++
+	m.lastRequest = *request
+	if m.sent <= m.failures {
+		resultChannel <- hostHTTPResult{host: m.host, status: EXCEPTION,
+			err: fmt.Errorf("dial tcp %s:5554: %w", m.host, syscall.ECONNREFUSED)}
+		return
+	}
+	resultChannel <- hostHTTPResult{host: m.host, status: SUCCESS, statusCode: SuccessCode}
+}
+
+func (m *mockFlakyAdapter) generateResult(_ *http.Response) hostHTTPResult {
+	return hostHTTPResult{}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 3 * time.Millisecond
+
+	// the backoff is doubled up to the limit
+	assert.Equal(t, time.Millisecond, policy.getBackoff(1))
+	assert.Equal(t, 2*time.Millisecond, policy.getBackoff(2))
+	assert.Equal(t, 3*time.Millisecond, policy.getBackoff(3))
+
+	refused := hostHTTPResult{status: EXCEPTION, err: fmt.Errorf("dial: %w", syscall.ECONNREFUSED)}
+	assert.True(t, policy.isRetryable(&refused))
+	unavailable := hostHTTPResult{status: FAILURE, statusCode: http.StatusServiceUnavailable, err: fmt.Errorf("unavailable")}
+	assert.True(t, policy.isRetryable(&unavailable))
+	internalError := hostHTTPResult{status: FAILURE, statusCode: InternalErrorCode, err: fmt.Errorf("internal error")}
+	assert.False(t, policy.isRetryable(&internalError))
+	canceled := hostHTTPResult{status: EXCEPTION, err: fmt.Errorf("%w: %w", context.Canceled, syscall.ECONNREFUSED)}
+	assert.False(t, policy.isRetryable(&canceled))
+
+	// the host that refuses the connection once succeeds on the retry, and the
+	// host that keeps refusing it fails after the max attempts
+	flaky := mockFlakyAdapter{host: "host1", failures: 1}
+	down := mockFlakyAdapter{host: "host2", failures: 10}
+	healthy := mockFlakyAdapter{host: "host3"}
+	pool := makeAdapterPool(vlog.Printer{})
+	pool.connections = map[string]adapter{"host1": &flaky, "host2": &down, "host3": &healthy}
+	httpRequest := clusterHTTPRequest{RequestCollection: map[string]hostHTTPRequest{
+		"host1": {}, "host2": {}, "host3": {},
+	}}
+	err := pool.sendRequest(context.Background(), &httpRequest, nil, &policy, 0)
+	assert.NoError(t, err)
+	flakyResult, downResult := httpRequest.ResultCollection["host1"], httpRequest.ResultCollection["host2"]
+	assert.True(t, flakyResult.isPassing())
+	assert.Equal(t, 1, flakyResult.retryCount)
+	assert.False(t, downResult.isPassing())
+	assert.Equal(t, 3, down.sent)
+	assert.Equal(t, 1, healthy.sent)
+
+	// no retry by default
+	flaky = mockFlakyAdapter{host: "host1", failures: 1}
+	httpRequest = clusterHTTPRequest{RequestCollection: map[string]hostHTTPRequest{"host1": {}}}
+	err = pool.sendRequest(context.Background(), &httpRequest, nil, &RetryPolicy{}, 0)
+	assert.NoError(t, err)
+	flakyResult = httpRequest.ResultCollection["host1"]
+	assert.False(t, flakyResult.isPassing())
+	assert.Equal(t, 1, flaky.sent)
+}
+
+// mockSlowAdapter records the most requests that were in flight at a time
+type mockSlowAdapter struct {
+	host     string
+	inFlight *atomic.Int32
+	maxSeen  *atomic.Int32
+}
+
+func (m *mockSlowAdapter) sendRequest(_ context.Context, _ *hostHTTPRequest, resultChannel chan<- hostHTTPResult) {
+	n := m.inFlight.Add(1)
+	for {
+		seen := m.maxSeen.Load()
+		if n <= seen || m.maxSeen.CompareAndSwap(seen, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	m.inFlight.Add(-1)
+	resultChannel <- hostHTTPResult{host: m.host, status: SUCCESS, statusCode: SuccessCode}
+}
+
+func (m *mockSlowAdapter) generateResult(_ *http.Response) hostHTTPResult {
+	return hostHTTPResult{}
+}
+
+func TestMaxConcurrentHosts(t *testing.T) {
+	const hostCount = 20
+	const maxConcurrentHosts = 4
+	var inFlight, maxSeen atomic.Int32
+	pool := makeAdapterPool(vlog.Printer{})
+	pool.connections = make(map[string]adapter)
+	httpRequest := clusterHTTPRequest{RequestCollection: make(map[string]hostHTTPRequest)}
+	for i := 0; i < hostCount; i++ {
+		host := fmt.Sprintf("host%d", i)
+		pool.connections[host] = &mockSlowAdapter{host: host, inFlight: &inFlight, maxSeen: &maxSeen}
+		httpRequest.RequestCollection[host] = hostHTTPRequest{}
+	}
+
+	err := pool.sendRequest(context.Background(), &httpRequest, nil, nil, maxConcurrentHosts)
+	assert.NoError(t, err)
+	// every host got the request, but not more than the limit at a time
+	assert.Len(t, httpRequest.ResultCollection, hostCount)
+	assert.LessOrEqual(t, maxSeen.Load(), int32(maxConcurrentHosts))
+}
@@ -19,8 +19,10 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
 type VReviveDatabaseOptions struct {
@@ -33,12 +35,33 @@ type VReviveDatabaseOptions struct {
 	LoadCatalogTimeout uint
 	// whether force remove existing directories before revive the database
 	ForceRemoval bool
+	// move the directories removed by ForceRemoval to a trash location
+	Trash TrashOptions
 	// describe the database on communal storage, and exit
 	DisplayOnly bool
 	// whether ignore the cluster lease
 	IgnoreClusterLease bool
 	// the restore policy
 	RestorePoint RestorePointPolicy
+	// name of a sandbox to revive from its own catalog on the communal
+	// storage, instead of the main cluster. The restore policy then applies
+	// to the restore points of the sandbox.
+	Sandbox string
+	// ID of the restore point found by the Timestamp selector of RestorePoint
+	restorePointIDByTimestamp string
+	// maps node names to new hosts, overriding the default that assigns the
+	// hosts to the nodes sorted by name. This keeps the data paths of each node
+	// on the intended machine when the hosts have different storage. The hosts
+	// must be the same as the hosts of the options, and every node must be
+	// mapped exactly once unless PartialRevive is set.
+	NodeHostMap map[string]string
+	// revive only the nodes in NodeHostMap onto fewer hosts than the nodes of
+	// the database. All of the primary nodes must be mapped. The other nodes
+	// are left DOWN in the catalog with their old addresses, to be replaced
+	// later, e.g., through add_node.
+	PartialRevive bool
+	// NodeHostMap with the hosts resolved to IP addresses, keyed by the addresses
+	newHostToNodeName map[string]string
 }
 
 type RestorePointPolicy struct {
@@ -46,8 +69,28 @@ type RestorePointPolicy struct {
 	Archive string
 	// The (1-based) index of the restore point in the restore archive to restore from
 	Index int
-	// The identifier of the restore point in the restore archive to restore from
+	// The identifier of the restore point in the restore archive to restore from.
+	// If Index is set as well, the restore point must have both.
 	ID string
+	// Restore from the most recent restore point in the restore archive that was
+	// created no later than this UTC timestamp, in date time or date only format.
+	// A date only timestamp means the end of that day.
+	Timestamp string
+}
+
+// parseTimestamp returns the time of the Timestamp selector of the policy
+func (policy *RestorePointPolicy) parseTimestamp() (time.Time, error) {
+	parsedTime, err := time.Parse(util.DefaultDateTimeFormat, policy.Timestamp)
+	if err == nil {
+		return parsedTime, nil
+	}
+	_, dateOnlyErr := time.Parse(util.DefaultDateOnlyFormat, policy.Timestamp)
+	if dateOnlyErr != nil {
+		return parsedTime, fmt.Errorf("restore point timestamp %q is invalid; cannot parse as a datetime: %w; "+
+			"cannot parse as a date as well: %w", policy.Timestamp, err, dateOnlyErr)
+	}
+	dateOnly := policy.Timestamp
+	return *util.FillInDefaultTimeForEndTimestamp(&dateOnly), nil
 }
 
 func (options *VReviveDatabaseOptions) isRestoreEnabled() bool {
@@ -62,20 +105,37 @@ func (options *VReviveDatabaseOptions) hasValidRestorePointIndex() bool {
 	return options.RestorePoint.Index > 0
 }
 
+func (options *VReviveDatabaseOptions) hasValidRestorePointTimestamp() bool {
+	return options.RestorePoint.Timestamp != ""
+}
+
+func (options *VReviveDatabaseOptions) hasRestorePointSelector() bool {
+	return options.hasValidRestorePointID() || options.hasValidRestorePointIndex() || options.hasValidRestorePointTimestamp()
+}
+
 func (options *VReviveDatabaseOptions) findSpecifiedRestorePoint(allRestorePoints []RestorePoint) (string, error) {
+	if options.hasValidRestorePointTimestamp() {
+		return options.findLatestRestorePointBefore(allRestorePoints)
+	}
 	foundRestorePoints := make([]RestorePoint, 0)
 	for _, restorePoint := range allRestorePoints {
 		if restorePoint.Archive != options.RestorePoint.Archive {
 			continue
 		}
-		if restorePoint.ID == options.RestorePoint.ID || restorePoint.Index == options.RestorePoint.Index {
-			foundRestorePoints = append(foundRestorePoints, restorePoint)
+		// the ID and the index must both match when both are specified
+		if options.hasValidRestorePointID() && restorePoint.ID != options.RestorePoint.ID {
+			continue
 		}
+		if options.hasValidRestorePointIndex() && restorePoint.Index != options.RestorePoint.Index {
+			continue
+		}
+		foundRestorePoints = append(foundRestorePoints, restorePoint)
 	}
 	if len(foundRestorePoints) == 0 {
 		err := &ReviveDBRestorePointNotFoundError{Archive: options.RestorePoint.Archive}
 		if options.hasValidRestorePointID() {
 			err.InvalidID = options.RestorePoint.ID
+			err.InvalidIndex = options.RestorePoint.Index
 		} else {
 			err.InvalidIndex = options.RestorePoint.Index
 		}
@@ -87,25 +147,136 @@ func (options *VReviveDatabaseOptions) findSpecifiedRestorePoint(allRestorePoint
 	return "", fmt.Errorf("found %d restore points instead of 1: %+v", len(foundRestorePoints), foundRestorePoints)
 }
 
+// findLatestRestorePointBefore returns the ID of the most recent restore point
+// of the archive that was created no later than the Timestamp selector
+func (options *VReviveDatabaseOptions) findLatestRestorePointBefore(allRestorePoints []RestorePoint) (string, error) {
+	maxTime, err := options.RestorePoint.parseTimestamp()
+	if err != nil {
+		return "", err
+	}
+	var latestID string
+	var latestTime time.Time
+	for i := range allRestorePoints {
+		restorePoint := &allRestorePoints[i]
+		if restorePoint.Archive != options.RestorePoint.Archive {
+			continue
+		}
+		createTime, err := restorePoint.GetTimestamp()
+		if err != nil {
+			return "", fmt.Errorf("fail to parse the timestamp of restore point %s: %w", restorePoint.ID, err)
+		}
+		if createTime.After(maxTime) {
+			continue
+		}
+		if latestID == "" || createTime.After(latestTime) {
+			latestID = restorePoint.ID
+			latestTime = createTime
+		}
+	}
+	if latestID == "" {
+		return "", &ReviveDBRestorePointNotFoundError{Archive: options.RestorePoint.Archive,
+			InvalidTimestamp: options.RestorePoint.Timestamp}
+	}
+	return latestID, nil
+}
+
 // ReviveDBRestorePointNotFoundError is the error that is returned when the retore point specified by the user
-// either via index or id is not found among all restore points in the specified archive. Either InvalidID or
-// InvalidIndex will be set depending on whether the user specified the retore point by index or id.
+// either via index, id or timestamp is not found among all restore points in the specified archive. One of
+// InvalidID, InvalidIndex or InvalidTimestamp will be set depending on how the user specified the retore point.
 type ReviveDBRestorePointNotFoundError struct {
-	Archive      string
-	InvalidID    string
-	InvalidIndex int
+	Archive          string
+	InvalidID        string
+	InvalidIndex     int
+	InvalidTimestamp string
 }
 
 func (e *ReviveDBRestorePointNotFoundError) Error() string {
-	var indicator, value string
 	if e.InvalidID != "" {
-		indicator = "ID"
-		value = e.InvalidID
-	} else {
-		indicator = "index"
-		value = fmt.Sprintf("%d", e.InvalidIndex)
+		return localize(MsgRestorePointIDNotFound, e.InvalidID, e.Archive)
+	}
+	if e.InvalidTimestamp != "" {
+		return localize(MsgRestorePointTimestampNotFound, e.InvalidTimestamp, e.Archive)
 	}
-	return fmt.Sprintf("restore point with %s %s not found in archive %q", indicator, value, e.Archive)
+	return localize(MsgRestorePointIndexNotFound, e.InvalidIndex, e.Archive)
+}
+
+// VReviveDatabaseResult holds the information that VReviveDatabase collected
+// from communal storage
+type VReviveDatabaseResult struct {
+	// the raw database information retrieved from communal storage,
+	// only set when DisplayOnly is specified. Description has the same
+	// information in typed fields.
+	DBInfo string
+	// the database information retrieved from communal storage
+	Description *DBDescription
+	// the communal storage location that the database was revived from
+	CommunalStorageLocation string
+	// the sandbox that was revived, empty for the main cluster
+	Sandbox string
+	// the restore points found in the restore archive, only set when the archive
+	// is specified. They are all of the restore points of the archive when
+	// DisplayOnly is specified, so they can be listed to pick one from, and the
+	// specified restore point otherwise.
+	RestorePoints []RestorePoint
+	// ID of the restore point that the database was restored to,
+	// only set when a restore point is specified
+	AppliedRestorePointID string
+	// names of the nodes that were not revived, only set for a partial revive
+	DeferredNodes []string
+}
+
+// DBDescription is the database information in the description file
+// on the communal storage
+type DBDescription struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// number of segment shards, excluding the replica shard
+	ShardCount             int                 `json:"shard_count"`
+	ClusterLeaseExpiration string              `json:"cluster_lease_expiration"`
+	Nodes                  []DBDescriptionNode `json:"nodes"`
+	// paths of the storage locations
+	StorageLocations []string                  `json:"storage_locations"`
+	Subclusters      []DBDescriptionSubcluster `json:"subclusters"`
+	// names of the sandboxes that the subclusters belong to
+	Sandboxes []string `json:"sandboxes"`
+	// the largest catalog and depot of the nodes in bytes, zero if the
+	// description file does not have the sizes
+	CatalogSizeBytes uint64 `json:"catalog_size_bytes"`
+	DepotSizeBytes   uint64 `json:"depot_size_bytes"`
+}
+
+// DBDescriptionNode is a node in the description file
+type DBDescriptionNode struct {
+	Name        string `json:"name"`
+	Address     string `json:"address"`
+	CatalogPath string `json:"catalog_path"`
+	IsPrimary   bool   `json:"is_primary"`
+	Subcluster  string `json:"subcluster"`
+	// empty for a node of the main cluster
+	Sandbox string `json:"sandbox"`
+}
+
+// DBDescriptionSubcluster is a subcluster in the description file
+type DBDescriptionSubcluster struct {
+	Name      string `json:"name"`
+	IsPrimary bool   `json:"is_primary"`
+	// empty for a subcluster of the main cluster
+	Sandbox string `json:"sandbox"`
+}
+
+// DiffHosts compares the hosts of the nodes in the description with the intended
+// hosts. It returns the intended hosts that are not in the description, and the
+// hosts in the description that are not intended, both sorted.
+func (desc *DBDescription) DiffHosts(hosts []string) (missingHosts, extraHosts []string) {
+	var descHosts []string
+	for i := range desc.Nodes {
+		descHosts = append(descHosts, desc.Nodes[i].Address)
+	}
+	missingHosts = util.SliceDiff(hosts, descHosts)
+	extraHosts = util.SliceDiff(descHosts, hosts)
+	sort.Strings(missingHosts)
+	sort.Strings(extraHosts)
+	return missingHosts, extraHosts
 }
 
 func VReviveDBOptionsFactory() VReviveDatabaseOptions {
@@ -140,21 +311,54 @@ func (options *VReviveDatabaseOptions) validateRequiredOptions() error {
 		return fmt.Errorf("must specify a host or host list")
 	}
 
+	// sandbox
+	if options.Sandbox != util.MainClusterSandbox {
+		err = util.ValidateSandboxName(options.Sandbox)
+		if err != nil {
+			return err
+		}
+	}
+
 	// communal storage
-	return util.ValidateCommunalStorageLocation(options.CommunalStorageLocation)
+	return util.ValidateCommunalStorage(options.CommunalStorageLocation, options.ConfigurationParameters)
+}
+
+func (options *VReviveDatabaseOptions) hasNodeHostMap() bool {
+	return len(options.NodeHostMap) > 0
 }
 
 func (options *VReviveDatabaseOptions) validateExtraOptions() error {
-	if options.isRestoreEnabled() &&
-		options.hasValidRestorePointID() == options.hasValidRestorePointIndex() {
-		return fmt.Errorf("for a restore, must specify exactly one of (1-based) restore point index or id, " +
-			"not both or none")
+	if err := options.Trash.validate(); err != nil {
+		return err
+	}
+	if options.PartialRevive && !options.hasNodeHostMap() {
+		return fmt.Errorf("a partial revive requires a node-to-host mapping of the nodes to revive")
+	}
+	for nodeName, host := range options.NodeHostMap {
+		if nodeName == "" || host == "" {
+			return fmt.Errorf("the node name and the host in a node-to-host mapping must not be empty")
+		}
+	}
+	if !options.isRestoreEnabled() {
+		return nil
+	}
+	if options.hasValidRestorePointTimestamp() {
+		if options.hasValidRestorePointID() || options.hasValidRestorePointIndex() {
+			return fmt.Errorf("for a restore, must not specify the restore point timestamp together with its index or id")
+		}
+		_, err := options.RestorePoint.parseTimestamp()
+		return err
+	}
+	// describing the database lists the restore points of the archive without picking one
+	if !options.hasRestorePointSelector() && !options.DisplayOnly {
+		return fmt.Errorf("for a restore, must specify the (1-based) restore point index, id or timestamp")
 	}
 
 	return nil
 }
 
 func (options *VReviveDatabaseOptions) validateParseOptions() error {
+	options.resetReport()
 	// batch 1: validate required parameters
 	err := options.validateRequiredOptions()
 	if err != nil {
@@ -178,12 +382,62 @@ func (options *VReviveDatabaseOptions) analyzeOptions() (err error) {
 
 	// resolve RawHosts to be IP addresses
 	if len(options.RawHosts) > 0 {
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
 	}
 
+	if options.hasNodeHostMap() {
+		return options.analyzeNodeHostMap()
+	}
+	return nil
+}
+
+// reresolveHosts resolves the hosts again, so that the nodes are lined up
+// with the current addresses of the hosts whose DNS records changed since
+// the command started
+func (options *VReviveDatabaseOptions) reresolveHosts(logger vlog.Printer) error {
+	oldHosts := options.Hosts
+	hosts, err := options.resolveRawHosts(options.RawHosts)
+	if err != nil {
+		return err
+	}
+	for i, host := range hosts {
+		if host != oldHosts[i] {
+			logger.PrintInfo("Host %s is resolved to %s instead of %s", options.RawHosts[i], host, oldHosts[i])
+		}
+	}
+	options.Hosts = hosts
+	if options.hasNodeHostMap() {
+		return options.analyzeNodeHostMap()
+	}
+	return nil
+}
+
+// analyzeNodeHostMap resolves the hosts of NodeHostMap, and checks that they
+// are the same as the hosts of the options
+func (options *VReviveDatabaseOptions) analyzeNodeHostMap() error {
+	options.newHostToNodeName = make(map[string]string)
+	for nodeName, rawHost := range options.NodeHostMap {
+		host, err := options.resolveHost(rawHost)
+		if err != nil {
+			return err
+		}
+		if otherNodeName, found := options.newHostToNodeName[host]; found {
+			return fmt.Errorf("host %s is mapped to both node %s and node %s", rawHost, otherNodeName, nodeName)
+		}
+		options.newHostToNodeName[host] = nodeName
+	}
+	if len(options.newHostToNodeName) != len(options.Hosts) {
+		return fmt.Errorf("the %d hosts in the node-to-host mapping do not match the %d hosts to revive onto",
+			len(options.newHostToNodeName), len(options.Hosts))
+	}
+	for _, host := range options.Hosts {
+		if _, found := options.newHostToNodeName[host]; !found {
+			return fmt.Errorf("host %s is not mapped to any node", host)
+		}
+	}
 	return nil
 }
 
@@ -194,9 +448,18 @@ func (options *VReviveDatabaseOptions) validateAnalyzeOptions() error {
 	return options.analyzeOptions()
 }
 
+// getCheckpointKey returns the key of the options that the progress of a revive
+// depends on, so that a checkpoint is not resumed after one of them changes
+func (options *VReviveDatabaseOptions) getCheckpointKey() (string, error) {
+	return makeCheckpointKey(options.CommunalStorageLocation, options.Sandbox, options.RestorePoint,
+		options.NodeHostMap, options.PartialRevive)
+}
+
 // VReviveDatabase revives a database that was terminated but whose communal storage data still exists.
-// It returns the database information retrieved from communal storage and any error encountered.
-func (vcc VClusterCommands) VReviveDatabase(options *VReviveDatabaseOptions) (dbInfo string, vdbPtr *VCoordinationDatabase, err error) {
+// It returns the information retrieved from communal storage, including the restore points of the
+// archive when restoring, and any error encountered.
+func (vcc VClusterCommands) VReviveDatabase(options *VReviveDatabaseOptions) (result VReviveDatabaseResult,
+	vdbPtr *VCoordinationDatabase, err error) {
 	/*
 	 *   - Validate options
 	 *   - Run VClusterOpEngine to get terminated database info
@@ -206,61 +469,64 @@ func (vcc VClusterCommands) VReviveDatabase(options *VReviveDatabaseOptions) (db
 	// validate and analyze options
 	err = options.validateAnalyzeOptions()
 	if err != nil {
-		return dbInfo, nil, err
+		return result, nil, err
 	}
 
-	vdb := makeVCoordinationDatabase()
-
-	// part 1: produce instructions for getting terminated database info, and save the info to vdb
-	preReviveDBInstructions, err := vcc.producePreReviveDBInstructions(options, &vdb)
+	// a previous run that failed in reviving the database leaves the database info in its checkpoint
+	checkpointKey, err := options.getCheckpointKey()
 	if err != nil {
-		return dbInfo, nil, fmt.Errorf("fail to produce pre-revive database instructions %w", err)
+		return result, nil, err
 	}
-
-	// generate clusterOpEngine certs
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	// feed the pre-revive db instructions to the VClusterOpEngine
-	clusterOpEngine := makeClusterOpEngine(preReviveDBInstructions, &certs)
-	err = clusterOpEngine.run(vcc.GetLog())
+	checkpoint, err := options.loadCheckpoint(commandReviveDB, checkpointKey)
 	if err != nil {
-		return dbInfo, nil, fmt.Errorf("fail to collect the information of database in revive_db %w", err)
+		return result, nil, err
 	}
 
-	if options.isRestoreEnabled() {
-		validatedRestorePointID, findErr := options.findSpecifiedRestorePoint(clusterOpEngine.execContext.restorePoints)
-		if findErr != nil {
-			return dbInfo, &vdb, fmt.Errorf("fail to find a restore point as specified %w", findErr)
-		}
-
-		restoreDBSpecificInstructions, produceErr := vcc.produceRestoreDBSpecificInstructions(options, &vdb, validatedRestorePointID)
-		if produceErr != nil {
-			return dbInfo, &vdb, fmt.Errorf("fail to produce restore-specific instructions %w", produceErr)
+	vdb := makeVCoordinationDatabase()
+	// a restore point selected by timestamp is resolved by part 1, so it cannot be skipped
+	if checkpoint != nil && checkpoint.VDB != nil && checkpoint.ReviveResult != nil &&
+		!options.DisplayOnly && !options.hasValidRestorePointTimestamp() {
+		vcc.PrintInfo("Resuming revive_db from a checkpoint, the database info is not collected again")
+		vdb = *checkpoint.VDB
+		result = *checkpoint.ReviveResult
+	} else {
+		checkpoint = nil
+		// part 1: get terminated database info, and save the info to vdb
+		var clusterOpEngine VClusterOpEngine
+		clusterOpEngine, err = vcc.runPreReviveDBInstructions(options, &vdb, &result)
+		if err != nil {
+			return result, &vdb, err
 		}
 
-		// feed the restore db specific instructions to the VClusterOpEngine
-		clusterOpEngine = makeClusterOpEngine(restoreDBSpecificInstructions, &certs)
-		runErr := clusterOpEngine.run(vcc.GetLog())
-		if runErr != nil {
-			return dbInfo, &vdb, fmt.Errorf("fail to collect the restore-specific information of database in revive_db %w", runErr)
+		if options.DisplayOnly {
+			result.DBInfo = clusterOpEngine.execContext.dbInfo
+			return result, &vdb, nil
 		}
 	}
 
-	if options.DisplayOnly {
-		dbInfo = clusterOpEngine.execContext.dbInfo
-		return dbInfo, &vdb, nil
+	// the catalog is updated with the addresses of the hosts, which must be the current ones
+	if options.ReresolveHostnames {
+		err = options.reresolveHosts(vcc.Log)
+		if err != nil {
+			return result, &vdb, err
+		}
 	}
 
 	// part 2: produce instructions for reviving database using terminated database info
-	reviveDBInstructions, err := vcc.produceReviveDBInstructions(options, &vdb)
+	reviveDBInstructions, err := vcc.produceReviveDBInstructions(options, &vdb, result.Description)
 	if err != nil {
-		return dbInfo, &vdb, fmt.Errorf("fail to produce revive database instructions %w", err)
+		return result, &vdb, fmt.Errorf("fail to produce revive database instructions %w", err)
 	}
 
-	// feed revive db instructions to the VClusterOpEngine
-	clusterOpEngine = makeClusterOpEngine(reviveDBInstructions, &certs)
-	err = clusterOpEngine.run(vcc.GetLog())
+	// feed revive db instructions to the VClusterOpEngine, which saves a
+	// checkpoint to resume from if an instruction fails
+	clusterOpEngine := options.makeResumableClusterOpEngine(commandReviveDB, checkpointKey, reviveDBInstructions, checkpoint, &vdb)
+	if clusterOpEngine.checkpoint != nil {
+		clusterOpEngine.checkpoint.ReviveResult = &result
+	}
+	err = clusterOpEngine.run(vcc.Context(), vcc.GetLog())
 	if err != nil {
-		return dbInfo, &vdb, fmt.Errorf("fail to revive database %w", err)
+		return result, &vdb, fmt.Errorf("fail to revive database %w", err)
 	}
 
 	// fill vdb with VReviveDatabaseOptions information
@@ -268,8 +534,66 @@ func (vcc VClusterCommands) VReviveDatabase(options *VReviveDatabaseOptions) (db
 	vdb.IsEon = true
 	vdb.CommunalStorageLocation = options.CommunalStorageLocation
 	vdb.Ipv6 = options.IPv6
+	// the revived nodes stay in the sandbox that they were revived from
+	for _, vnode := range vdb.HostNodeMap {
+		vnode.Sandbox = options.Sandbox
+	}
+	if options.PartialRevive {
+		result.DeferredNodes = options.getDeferredNodes(&vdb)
+	}
+	result.CommunalStorageLocation = options.CommunalStorageLocation
+	result.Sandbox = options.Sandbox
+
+	return result, &vdb, nil
+}
+
+// runPreReviveDBInstructions collects the information of the terminated database
+// into vdb, and the restore points into the result if a restore is enabled. It
+// returns the engine of the last run.
+func (vcc VClusterCommands) runPreReviveDBInstructions(options *VReviveDatabaseOptions,
+	vdb *VCoordinationDatabase, result *VReviveDatabaseResult) (clusterOpEngine VClusterOpEngine, err error) {
+	// produce instructions for getting terminated database info, and save the info to vdb
+	preReviveDBInstructions, err := vcc.producePreReviveDBInstructions(options, vdb)
+	if err != nil {
+		return clusterOpEngine, fmt.Errorf("fail to produce pre-revive database instructions %w", err)
+	}
+
+	// feed the pre-revive db instructions to the VClusterOpEngine
+	clusterOpEngine = options.makeClusterOpEngine(preReviveDBInstructions)
+	err = clusterOpEngine.run(vcc.Context(), vcc.GetLog())
+	result.Description = clusterOpEngine.execContext.dbDescription
+	if err != nil {
+		return clusterOpEngine, fmt.Errorf("fail to collect the information of database in revive_db %w", err)
+	}
+
+	if !options.isRestoreEnabled() {
+		return clusterOpEngine, nil
+	}
+	result.RestorePoints, _ = clusterOpEngine.execContext.RestorePoints()
+	if !options.hasRestorePointSelector() {
+		return clusterOpEngine, nil
+	}
+	validatedRestorePointID, err := options.findSpecifiedRestorePoint(result.RestorePoints)
+	if err != nil {
+		return clusterOpEngine, fmt.Errorf("fail to find a restore point as specified %w", err)
+	}
+	if options.hasValidRestorePointTimestamp() {
+		options.restorePointIDByTimestamp = validatedRestorePointID
+	}
+	result.AppliedRestorePointID = validatedRestorePointID
 
-	return dbInfo, &vdb, nil
+	restoreDBSpecificInstructions, err := vcc.produceRestoreDBSpecificInstructions(options, vdb, validatedRestorePointID)
+	if err != nil {
+		return clusterOpEngine, fmt.Errorf("fail to produce restore-specific instructions %w", err)
+	}
+
+	// feed the restore db specific instructions to the VClusterOpEngine
+	clusterOpEngine = options.makeClusterOpEngine(restoreDBSpecificInstructions)
+	err = clusterOpEngine.run(vcc.Context(), vcc.GetLog())
+	if err != nil {
+		return clusterOpEngine, fmt.Errorf("fail to collect the restore-specific information of database in revive_db %w", err)
+	}
+	return clusterOpEngine, nil
 }
 
 // revive db instructions are split into two parts:
@@ -301,7 +625,11 @@ func (vcc VClusterCommands) producePreReviveDBInstructions(options *VReviveDatab
 	)
 
 	// use current description file path as source file path
-	currConfigFileSrcPath := options.getCurrConfigFilePath()
+	currConfigFileSrcPath := options.getReviveConfigFilePath()
+	initiator, err := options.selectInitiator(vcc.Context(), options.Hosts)
+	if err != nil {
+		return instructions, err
+	}
 
 	if !options.isRestoreEnabled() {
 		// perform revive, either display-only or not
@@ -311,6 +639,8 @@ func (vcc VClusterCommands) producePreReviveDBInstructions(options *VReviveDatab
 		if err != nil {
 			return instructions, err
 		}
+		nmaDownloadFileOpForRevive.partialRevive = options.PartialRevive
+		nmaDownloadFileOpForRevive.setInitiator(initiator)
 		instructions = append(instructions,
 			&nmaDownloadFileOpForRevive,
 		)
@@ -324,24 +654,29 @@ func (vcc VClusterCommands) producePreReviveDBInstructions(options *VReviveDatab
 			if err != nil {
 				return instructions, err
 			}
+			nmaDownloadFileOpForRestoreLeaseCheck.partialRevive = options.PartialRevive
+			nmaDownloadFileOpForRestoreLeaseCheck.setInitiator(initiator)
 			instructions = append(instructions,
 				&nmaDownloadFileOpForRestoreLeaseCheck,
 			)
 		}
-		// no matter display-only or not, list all restore points for later use
-		hosts := options.Hosts
-		initiator := getInitiator(hosts)
+		// no matter display-only or not, list the restore points for later use
 		bootstrapHost := []string{initiator}
 		filterOptions := ShowRestorePointFilterOptions{}
 		filterOptions.ArchiveName = options.RestorePoint.Archive
-		if options.hasValidRestorePointID() {
+		// every restore point of the archive is listed when describing the database, or
+		// when a timestamp selects one, otherwise the NMA lists only the specified one.
+		// findSpecifiedRestorePoint() then picks the restore point from the list.
+		if !options.DisplayOnly && !options.hasValidRestorePointTimestamp() {
 			filterOptions.ArchiveID = options.RestorePoint.ID
-		} else {
-			indexStr := strconv.Itoa(options.RestorePoint.Index)
-			filterOptions.ArchiveIndex = indexStr
+			if options.hasValidRestorePointIndex() {
+				filterOptions.ArchiveIndex = strconv.Itoa(options.RestorePoint.Index)
+			}
 		}
 		nmaShowRestorePointsOp := makeNMAShowRestorePointsOpWithFilterOptions(vcc.GetLog(), bootstrapHost, options.DBName,
 			options.CommunalStorageLocation, options.ConfigurationParameters, &filterOptions)
+		nmaShowRestorePointsOp.sandbox = options.Sandbox
+		nmaShowRestorePointsOp.fallbackInitiators = options.Hosts
 		instructions = append(instructions,
 			&nmaShowRestorePointsOp,
 		)
@@ -366,6 +701,7 @@ func (vcc VClusterCommands) produceRestoreDBSpecificInstructions(options *VReviv
 	if err != nil {
 		return instructions, err
 	}
+	nmaDownLoadFileOp.partialRevive = options.PartialRevive
 
 	instructions = append(instructions,
 		&nmaDownLoadFileOp,
@@ -376,10 +712,12 @@ func (vcc VClusterCommands) produceRestoreDBSpecificInstructions(options *VReviv
 
 // produceReviveDBInstructions will build the second half of revive_db instructions
 // The generated instructions will later perform the following operations
+//   - Check the disk space and the paths of database directories for all the hosts
 //   - Prepare database directories for all the hosts
 //   - Get network profiles for all the hosts
 //   - Load remote catalog from communal storage on all the hosts
-func (vcc VClusterCommands) produceReviveDBInstructions(options *VReviveDatabaseOptions, vdb *VCoordinationDatabase) ([]clusterOp, error) {
+func (vcc VClusterCommands) produceReviveDBInstructions(options *VReviveDatabaseOptions, vdb *VCoordinationDatabase,
+	desc *DBDescription) ([]clusterOp, error) {
 	var instructions []clusterOp
 
 	newVDB, oldHosts, err := options.generateReviveVDB(vdb)
@@ -407,6 +745,12 @@ func (vcc VClusterCommands) produceReviveDBInstructions(options *VReviveDatabase
 		vnode.StorageLocations = newLocations
 		hostNodeMap[host] = vnode
 	}
+	// check the directories before they are prepared, which removes existing
+	// directories with ForceRemoval
+	nmaCheckReviveDirectoriesOp, err := makeNMACheckReviveDirectoriesOp(hostNodeMap, desc)
+	if err != nil {
+		return instructions, err
+	}
 	// prepare all directories
 	nmaPrepareDirectoriesOp, err := makeNMAPrepareDirectoriesOp(hostNodeMap, options.ForceRemoval, true /*for db revive*/)
 	if err != nil {
@@ -415,9 +759,22 @@ func (vcc VClusterCommands) produceReviveDBInstructions(options *VReviveDatabase
 
 	nmaNetworkProfileOp := makeNMANetworkProfileOp(options.Hosts)
 
+	// the catalog is loaded from the restore point found by its timestamp,
+	// as the NMA only selects a restore point by index or ID
+	restorePoint := options.RestorePoint
+	if options.hasValidRestorePointTimestamp() {
+		restorePoint.ID = options.restorePointIDByTimestamp
+		restorePoint.Timestamp = ""
+	}
 	nmaLoadRemoteCatalogOp := makeNMALoadRemoteCatalogOp(oldHosts, options.ConfigurationParameters,
-		&newVDB, options.LoadCatalogTimeout, &options.RestorePoint)
+		&newVDB, options.LoadCatalogTimeout, &restorePoint)
+	nmaLoadRemoteCatalogOp.sandbox = options.Sandbox
 
+	instructions = append(instructions, &nmaCheckReviveDirectoriesOp)
+	instructions, err = options.Trash.addTrashInstructions(instructions, options.DBName, &nmaPrepareDirectoriesOp)
+	if err != nil {
+		return instructions, err
+	}
 	instructions = append(instructions,
 		&nmaPrepareDirectoriesOp,
 		&nmaNetworkProfileOp,
@@ -462,6 +819,10 @@ func (options *VReviveDatabaseOptions) generateReviveVDB(vdb *VCoordinationDatab
 	})
 
 	newVDB.HostNodeMap = makeVHostNodeMap()
+	if options.hasNodeHostMap() {
+		oldHosts, err = options.lineUpMappedNodes(vNodes, &newVDB)
+		return newVDB, oldHosts, err
+	}
 	if len(newVDB.HostList) != len(vNodes) {
 		return newVDB, oldHosts, fmt.Errorf("the number of new hosts does not match the number of nodes in original database")
 	}
@@ -474,3 +835,48 @@ func (options *VReviveDatabaseOptions) generateReviveVDB(vdb *VCoordinationDatab
 
 	return newVDB, oldHosts, nil
 }
+
+// lineUpMappedNodes lines up the nodes mapped by NodeHostMap with the new
+// hosts, and returns their old hosts in the order of the new hosts
+func (options *VReviveDatabaseOptions) lineUpMappedNodes(vNodes []*VCoordinationNode,
+	newVDB *VCoordinationDatabase) (oldHosts []string, err error) {
+	nodesByName := make(map[string]*VCoordinationNode)
+	for _, vnode := range vNodes {
+		nodesByName[vnode.Name] = vnode
+		if _, found := options.NodeHostMap[vnode.Name]; found {
+			continue
+		}
+		if !options.PartialRevive {
+			return oldHosts, fmt.Errorf("node %s is not mapped to a host, every node must be mapped "+
+				"unless the revive is partial", vnode.Name)
+		}
+		// without all of the primary nodes, the revived database cannot have quorum
+		if vnode.IsPrimary {
+			return oldHosts, fmt.Errorf("primary node %s must be mapped to a host in a partial revive", vnode.Name)
+		}
+	}
+	for _, newHost := range newVDB.HostList {
+		nodeName := options.newHostToNodeName[newHost]
+		vnode, found := nodesByName[nodeName]
+		if !found {
+			return oldHosts, fmt.Errorf("node %s is not in the original database", nodeName)
+		}
+		oldHosts = append(oldHosts, vnode.Address)
+		vnode.Address = newHost
+		newVDB.HostNodeMap[newHost] = vnode
+	}
+	return oldHosts, nil
+}
+
+// getDeferredNodes returns the sorted names of the nodes that a partial
+// revive does not revive
+func (options *VReviveDatabaseOptions) getDeferredNodes(vdb *VCoordinationDatabase) []string {
+	var deferredNodes []string
+	for _, vnode := range vdb.HostNodeMap {
+		if _, found := options.NodeHostMap[vnode.Name]; !found {
+			deferredNodes = append(deferredNodes, vnode.Name)
+		}
+	}
+	sort.Strings(deferredNodes)
+	return deferredNodes
+}
@@ -1,24 +1,13 @@
 package vclusterops
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
 func TestFindSpecifiedRestorePoint(t *testing.T) {
-	archiveVal := "archive1"
-	idVal := "id1"
-	indexVal := 0
-	options := VReviveDatabaseOptions{
-		RestorePoint: RestorePointPolicy{
-			Archive: archiveVal,
-			ID:      idVal,
-			Index:   indexVal,
-		},
-	}
-
 	allRestorePoints := []RestorePoint{
 		{Archive: "archive1", ID: "id1", Index: 1},
 		{Archive: "archive2", ID: "id2", Index: 1},
@@ -27,30 +16,188 @@ func TestFindSpecifiedRestorePoint(t *testing.T) {
 		{Archive: "archive1", ID: "id3", Index: 3},
 	}
 
-	// Test case: Found a single matching restore point
-	expectedID := idVal
+	tests := []struct {
+		name        string
+		policy      RestorePointPolicy
+		expectedID  string
+		expectedErr string
+	}{
+		{name: "by ID", policy: RestorePointPolicy{Archive: "archive1", ID: "id1"}, expectedID: "id1"},
+		{name: "by index", policy: RestorePointPolicy{Archive: "archive1", Index: 2}, expectedID: "id3"},
+		{name: "by ID and index", policy: RestorePointPolicy{Archive: "archive1", ID: "id3", Index: 3}, expectedID: "id3"},
+		{name: "ID and index of different restore points", policy: RestorePointPolicy{Archive: "archive1", ID: "id1", Index: 2},
+			expectedErr: (&ReviveDBRestorePointNotFoundError{Archive: "archive1", InvalidID: "id1"}).Error()},
+		{name: "ID of several restore points", policy: RestorePointPolicy{Archive: "archive1", ID: "id3"},
+			expectedErr: "found 2 restore points instead of 1: " +
+				"[{Archive:archive1 ID:id3 Index:2 Timestamp: VerticaVersion:} {Archive:archive1 ID:id3 Index:3 Timestamp: VerticaVersion:}]"},
+		{name: "ID in another archive", policy: RestorePointPolicy{Archive: "archive3", ID: "id3"},
+			expectedErr: (&ReviveDBRestorePointNotFoundError{Archive: "archive3", InvalidID: "id3"}).Error()},
+		{name: "index out of range", policy: RestorePointPolicy{Archive: "archive2", Index: 3},
+			expectedErr: (&ReviveDBRestorePointNotFoundError{Archive: "archive2", InvalidIndex: 3}).Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := VReviveDatabaseOptions{RestorePoint: tt.policy}
+			actualID, err := options.findSpecifiedRestorePoint(allRestorePoints)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedID, actualID)
+		})
+	}
+}
+
+func TestValidateRestorePointSelector(t *testing.T) {
+	options := VReviveDatabaseOptions{RestorePoint: RestorePointPolicy{Archive: "archive1"}}
+	assert.ErrorContains(t, options.validateExtraOptions(), "must specify the (1-based) restore point index, id or timestamp")
+	// describing the database lists the restore points without selecting one
+	options.DisplayOnly = true
+	assert.NoError(t, options.validateExtraOptions())
+
+	options.RestorePoint.ID = "id1"
+	options.RestorePoint.Index = 1
+	assert.NoError(t, options.validateExtraOptions())
+	options.RestorePoint.Timestamp = "2024-03-04"
+	assert.ErrorContains(t, options.validateExtraOptions(), "must not specify the restore point timestamp together")
+}
+
+func TestFindRestorePointByTimestamp(t *testing.T) {
+	options := VReviveDatabaseOptions{
+		RestorePoint: RestorePointPolicy{
+			Archive:   "archive1",
+			Timestamp: "2024-03-04 08:00:00",
+		},
+	}
+
+	allRestorePoints := []RestorePoint{
+		{Archive: "archive1", ID: "id1", Index: 3, Timestamp: "2024-03-03 08:00:00.000001"},
+		{Archive: "archive1", ID: "id2", Index: 2, Timestamp: "2024-03-04 07:59:59.999999"},
+		{Archive: "archive1", ID: "id3", Index: 1, Timestamp: "2024-03-04 12:00:00.5"},
+		{Archive: "archive2", ID: "id4", Index: 1, Timestamp: "2024-03-04 07:59:59.999999"},
+	}
+
+	// the most recent restore point no later than the timestamp
 	actualID, err := options.findSpecifiedRestorePoint(allRestorePoints)
 	assert.NoError(t, err)
-	assert.Equal(t, expectedID, actualID)
+	assert.Equal(t, "id2", actualID)
 
-	options.RestorePoint.Index = 2
-	options.RestorePoint.ID = ""
-	expectedID = "id3"
+	// a date only timestamp means the end of the day
+	options.RestorePoint.Timestamp = "2024-03-04"
 	actualID, err = options.findSpecifiedRestorePoint(allRestorePoints)
 	assert.NoError(t, err)
-	assert.Equal(t, expectedID, actualID)
+	assert.Equal(t, "id3", actualID)
 
-	// Test case: Found multiple matching restore points
-	options.RestorePoint.Index = 0
-	options.RestorePoint.ID = expectedID
+	// no restore point is old enough
+	options.RestorePoint.Timestamp = "2024-03-01"
 	_, err = options.findSpecifiedRestorePoint(allRestorePoints)
-	expectedErr := fmt.Errorf("found 2 restore points instead of 1: " +
-		"[{Archive:archive1 ID:id3 Index:2 Timestamp: VerticaVersion:} {Archive:archive1 ID:id3 Index:3 Timestamp: VerticaVersion:}]")
+	expectedErr := &ReviveDBRestorePointNotFoundError{Archive: "archive1", InvalidTimestamp: "2024-03-01"}
 	assert.EqualError(t, err, expectedErr.Error())
 
-	// Test case: No matching restore points found
-	options.RestorePoint.Archive = "archive3"
-	_, err = options.findSpecifiedRestorePoint(allRestorePoints)
-	expectedErr = &ReviveDBRestorePointNotFoundError{Archive: "archive3", InvalidID: "id3"}
-	assert.EqualError(t, err, expectedErr.Error())
+	// the timestamp cannot be combined with the other selectors
+	options.RestorePoint.Index = 1
+	assert.ErrorContains(t, options.validateExtraOptions(), "must not specify the restore point timestamp together")
+	options.RestorePoint.Index = 0
+	options.RestorePoint.Timestamp = "yesterday"
+	assert.ErrorContains(t, options.validateExtraOptions(), `restore point timestamp "yesterday" is invalid`)
+	options.RestorePoint.Timestamp = "2024-03-04 08:00:00"
+	assert.NoError(t, options.validateExtraOptions())
+}
+
+func TestPartialReviveVDB(t *testing.T) {
+	makeVDB := func() *VCoordinationDatabase {
+		vdb := makeVCoordinationDatabase()
+		vdb.HostNodeMap = makeVHostNodeMap()
+		vdb.HostNodeMap["192.168.1.101"] = &VCoordinationNode{Name: "v_test_db_node0001", Address: "192.168.1.101", IsPrimary: true}
+		vdb.HostNodeMap["192.168.1.102"] = &VCoordinationNode{Name: "v_test_db_node0002", Address: "192.168.1.102", IsPrimary: true}
+		vdb.HostNodeMap["192.168.1.103"] = &VCoordinationNode{Name: "v_test_db_node0003", Address: "192.168.1.103"}
+		return &vdb
+	}
+	options := VReviveDBOptionsFactory()
+	options.DBName = "test_db"
+	options.Hosts = []string{"10.1.10.2", "10.1.10.1"}
+	options.NodeHostMap = map[string]string{"v_test_db_node0001": "10.1.10.1", "v_test_db_node0002": "10.1.10.2"}
+	err := options.analyzeNodeHostMap()
+	assert.NoError(t, err)
+
+	// every node must be mapped unless the revive is partial
+	_, _, err = options.generateReviveVDB(makeVDB())
+	assert.ErrorContains(t, err, "node v_test_db_node0003 is not mapped to a host")
+
+	// the mapped nodes are lined up with the new hosts, and the others are deferred
+	options.PartialRevive = true
+	vdb := makeVDB()
+	newVDB, oldHosts, err := options.generateReviveVDB(vdb)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.102", "192.168.1.101"}, oldHosts)
+	assert.Len(t, newVDB.HostNodeMap, 2)
+	assert.Equal(t, "v_test_db_node0001", newVDB.HostNodeMap["10.1.10.1"].Name)
+	assert.Equal(t, []string{"v_test_db_node0003"}, options.getDeferredNodes(vdb))
+
+	// all of the primary nodes must be revived
+	options.NodeHostMap = map[string]string{"v_test_db_node0001": "10.1.10.1", "v_test_db_node0003": "10.1.10.2"}
+	err = options.analyzeNodeHostMap()
+	assert.NoError(t, err)
+	_, _, err = options.generateReviveVDB(makeVDB())
+	assert.ErrorContains(t, err, "primary node v_test_db_node0002 must be mapped")
+
+	// the mapped nodes must be in the database
+	options.NodeHostMap = map[string]string{"v_test_db_node0001": "10.1.10.1", "v_test_db_node0002": "10.1.10.2",
+		"v_test_db_node0009": "10.1.10.3"}
+	err = options.analyzeNodeHostMap()
+	assert.ErrorContains(t, err, "do not match the 2 hosts")
+	options.Hosts = append(options.Hosts, "10.1.10.3")
+	err = options.analyzeNodeHostMap()
+	assert.NoError(t, err)
+	_, _, err = options.generateReviveVDB(makeVDB())
+	assert.ErrorContains(t, err, "node v_test_db_node0009 is not in the original database")
+}
+
+func TestExplicitNodeHostMap(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	vdb.HostNodeMap["192.168.1.101"] = &VCoordinationNode{Name: "v_test_db_node0001", Address: "192.168.1.101"}
+	vdb.HostNodeMap["192.168.1.102"] = &VCoordinationNode{Name: "v_test_db_node0002", Address: "192.168.1.102"}
+	options := VReviveDBOptionsFactory()
+	options.DBName = "test_db"
+	options.Hosts = []string{"10.1.10.1", "10.1.10.2"}
+
+	// the mapping overrides the default that assigns the hosts by the sorted node names
+	options.NodeHostMap = map[string]string{"v_test_db_node0001": "10.1.10.2", "v_test_db_node0002": "10.1.10.1"}
+	err := options.analyzeNodeHostMap()
+	assert.NoError(t, err)
+	newVDB, oldHosts, err := options.generateReviveVDB(&vdb)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.102", "192.168.1.101"}, oldHosts)
+	assert.Equal(t, "v_test_db_node0002", newVDB.HostNodeMap["10.1.10.1"].Name)
+	assert.Equal(t, "v_test_db_node0001", newVDB.HostNodeMap["10.1.10.2"].Name)
+	assert.Empty(t, options.getDeferredNodes(&vdb))
+
+	// a host cannot be mapped to two nodes
+	options.NodeHostMap = map[string]string{"v_test_db_node0001": "10.1.10.1", "v_test_db_node0002": "10.1.10.1"}
+	err = options.analyzeNodeHostMap()
+	assert.ErrorContains(t, err, "host 10.1.10.1 is mapped to both node")
+
+	// a partial revive needs the mapping
+	options.NodeHostMap = nil
+	options.PartialRevive = true
+	err = options.validateExtraOptions()
+	assert.ErrorContains(t, err, "a partial revive requires a node-to-host mapping")
+}
+
+func TestReviveReresolveHosts(t *testing.T) {
+	options := VReviveDBOptionsFactory()
+	options.ReresolveHostnames = true
+	options.RawHosts = []string{"192.168.1.101", "localhost"}
+	// the address of localhost changed since the command started
+	options.Hosts = []string{"192.168.1.101", "10.1.10.2"}
+	options.NodeHostMap = map[string]string{"v_test_db_node0001": "192.168.1.101", "v_test_db_node0002": "localhost"}
+
+	err := options.reresolveHosts(vlog.Printer{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.101", "127.0.0.1"}, options.Hosts)
+	assert.Equal(t, map[string]string{"192.168.1.101": "v_test_db_node0001", "127.0.0.1": "v_test_db_node0002"},
+		options.newHostToNodeName)
+	assert.Equal(t, map[string]string{"127.0.0.1": "localhost"}, options.hostnames)
 }
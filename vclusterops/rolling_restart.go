@@ -0,0 +1,355 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// VRollingRestartOptions represents the available options for VRollingRestart.
+type VRollingRestartOptions struct {
+	DatabaseOptions
+	// Number of nodes that are restarted at a time
+	BatchSize int
+	// Names of the nodes to restart. If empty, all UP nodes of the main
+	// cluster are restarted.
+	NodeNames []string
+	// Timeout for polling the restarted nodes to come up and, in Eon mode,
+	// for their shard subscriptions to become ACTIVE
+	StatePollingTimeout int
+}
+
+// RollingRestartResult holds the outcome of VRollingRestart
+type RollingRestartResult struct {
+	// nodes restarted successfully, in the restart order
+	RestartedNodes []string
+	// nodes of the batch that failed to restart
+	FailedNodes []string
+	// nodes left untouched because the rolling restart was aborted
+	SkippedNodes []string
+}
+
+func VRollingRestartOptionsFactory() VRollingRestartOptions {
+	options := VRollingRestartOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+
+	return options
+}
+
+func (options *VRollingRestartOptions) setDefaultValues() {
+	options.DatabaseOptions.setDefaultValues()
+	options.BatchSize = 1
+	options.StatePollingTimeout = util.DefaultStatePollingTimeout
+}
+
+func (options *VRollingRestartOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandRollingRestart, logger)
+	if err != nil {
+		return err
+	}
+	if options.BatchSize < 1 {
+		return fmt.Errorf("batch size must be at least 1, got %d", options.BatchSize)
+	}
+	if options.StatePollingTimeout < 0 {
+		return fmt.Errorf("state polling timeout must not be negative, got %d", options.StatePollingTimeout)
+	}
+	return nil
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VRollingRestartOptions) analyzeOptions() (err error) {
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VRollingRestartOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	return options.setUsePasswordAndValidateUsernameIfNeeded(logger)
+}
+
+// VRollingRestart restarts the nodes of the main cluster, BatchSize nodes at
+// a time, so that the database stays available. The secondary nodes are
+// restarted before the primary nodes. After each batch, it waits for the
+// nodes to rejoin the cluster and, in Eon mode, for their shard subscriptions
+// to become ACTIVE, so that the cluster has recovered its K-safety before the
+// next batch is stopped. The rolling restart is aborted at the first batch
+// that fails, and the returned result tells which nodes were restarted.
+func (vcc VClusterCommands) VRollingRestart(options *VRollingRestartOptions) (RollingRestartResult, error) {
+	var result RollingRestartResult
+
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return result, err
+	}
+
+	vdb := makeVCoordinationDatabase()
+	err = vcc.getVDBFromRunningDB(&vdb, &options.DatabaseOptions)
+	if err != nil {
+		return result, err
+	}
+
+	var shardSubscribers map[string][]string
+	if vdb.IsEon {
+		shardSubscribers, err = vcc.getShardSubscribers(options, &vdb)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	batches, err := planRollingRestartBatches(&vdb, shardSubscribers, options.NodeNames, options.BatchSize)
+	if err != nil {
+		return result, err
+	}
+
+	for i, batch := range batches {
+		batchNodeNames := getNodeNamesOfHosts(&vdb, batch)
+		vcc.Log.PrintInfo("Restarting nodes %v (batch %d of %d)", batchNodeNames, i+1, len(batches))
+		err = vcc.restartNodeBatch(options, &vdb, batch)
+		if err != nil {
+			result.FailedNodes = batchNodeNames
+			for _, skippedBatch := range batches[i+1:] {
+				result.SkippedNodes = append(result.SkippedNodes, getNodeNamesOfHosts(&vdb, skippedBatch)...)
+			}
+			return result, fmt.Errorf("rolling restart aborted, fail to restart nodes %v: %w", batchNodeNames, err)
+		}
+		result.RestartedNodes = append(result.RestartedNodes, batchNodeNames...)
+	}
+
+	return result, nil
+}
+
+// getShardSubscribers returns the names of the nodes with an ACTIVE
+// subscription to each shard, keyed by shard name
+func (vcc VClusterCommands) getShardSubscribers(options *VRollingRestartOptions,
+	vdb *VCoordinationDatabase) (map[string][]string, error) {
+	initiator, err := getInitiatorHost(vdb.PrimaryUpNodes, []string{})
+	if err != nil {
+		return nil, err
+	}
+	var shardSubscribers map[string][]string
+	httpsGetSubscriptionsOp, err := makeHTTPSGetSubscriptionsOp([]string{initiator}, options.usePassword,
+		options.UserName, options.Password, &shardSubscribers)
+	if err != nil {
+		return nil, err
+	}
+	clusterOpEngine := options.makeClusterOpEngine([]clusterOp{&httpsGetSubscriptionsOp})
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get the shard subscriptions: %w", err)
+	}
+	return shardSubscribers, nil
+}
+
+// planRollingRestartBatches returns the hosts to restart, split in batches of
+// batchSize hosts. The secondary nodes come before the primary nodes, and each
+// batch is checked to keep the quorum of the main cluster and, in Eon mode, an
+// UP subscriber of every shard in shardSubscribers while it is down.
+func planRollingRestartBatches(vdb *VCoordinationDatabase, shardSubscribers map[string][]string,
+	nodeNames []string, batchSize int) ([][]string, error) {
+	var hosts []string
+	for _, host := range vdb.HostList {
+		vnode := vdb.HostNodeMap[host]
+		if vnode.Sandbox != util.MainClusterSandbox {
+			continue
+		}
+		if len(nodeNames) > 0 && !slices.Contains(nodeNames, vnode.Name) {
+			continue
+		}
+		if vnode.State != util.NodeUpState {
+			if len(nodeNames) > 0 {
+				return nil, fmt.Errorf("node %s is %s, only UP nodes can be restarted", vnode.Name, vnode.State)
+			}
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+
+	if len(nodeNames) > 0 {
+		foundNodeNames := getNodeNamesOfHosts(vdb, hosts)
+		missingNodeNames := util.SliceDiff(nodeNames, foundNodeNames)
+		if len(missingNodeNames) > 0 {
+			return nil, fmt.Errorf("nodes %v do not exist in the main cluster", missingNodeNames)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("cannot find any UP node to restart in the main cluster")
+	}
+
+	// restart the secondary nodes first, then the primary nodes
+	slices.SortStableFunc(hosts, func(a, b string) int {
+		return boolToInt(vdb.HostNodeMap[a].IsPrimary) - boolToInt(vdb.HostNodeMap[b].IsPrimary)
+	})
+
+	var batches [][]string
+	for start := 0; start < len(hosts); start += batchSize {
+		end := util.Min(start+batchSize, len(hosts))
+		batch := hosts[start:end]
+		err := checkRestartBatchSafety(vdb, shardSubscribers, batch)
+		if err != nil {
+			return nil, fmt.Errorf("cannot restart nodes %v at the same time, try a smaller batch size: %w",
+				getNodeNamesOfHosts(vdb, batch), err)
+		}
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+// checkRestartBatchSafety returns an error if stopping the given hosts at the
+// same time would lose the quorum of the primary nodes of the main cluster,
+// its shard coverage in Eon mode, or data availability in Enterprise mode
+func checkRestartBatchSafety(vdb *VCoordinationDatabase, shardSubscribers map[string][]string, batch []string) error {
+	var primaryCount, upPrimaryCount, downPrimaryCount int
+	for host, vnode := range vdb.HostNodeMap {
+		if vnode.Sandbox != util.MainClusterSandbox || !vnode.IsPrimary {
+			continue
+		}
+		// the stopped nodes still count in the quorum
+		primaryCount++
+		switch {
+		case slices.Contains(batch, host):
+		case vnode.State == util.NodeUpState:
+			upPrimaryCount++
+		default:
+			downPrimaryCount++
+		}
+	}
+
+	if vdb.IsEon && upPrimaryCount == 0 {
+		return errors.New("no UP primary node would remain to cover the shards")
+	}
+	if shard := findUncoveredShard(vdb, shardSubscribers, batch); shard != "" {
+		return fmt.Errorf("no UP node would remain subscribed to shard %s", shard)
+	}
+	if upPrimaryCount*2 <= primaryCount {
+		return categorizeError(ErrQuorumLost,
+			fmt.Errorf("only %d of the %d primary nodes would be UP, which is not a quorum",
+				upPrimaryCount, primaryCount))
+	}
+	// a K-safe Enterprise database tolerates a single down node, including
+	// the nodes that are already down
+	if !vdb.IsEon && downPrimaryCount+len(batch) > 1 && len(vdb.HostList) >= ksafetyThreshold {
+		return fmt.Errorf("only one node of an Enterprise database can be down at a time, "+
+			"already down: %d", downPrimaryCount)
+	}
+	return nil
+}
+
+// findUncoveredShard returns the first shard, by name, that would be left
+// without an UP subscriber of the main cluster if the given hosts were stopped
+func findUncoveredShard(vdb *VCoordinationDatabase, shardSubscribers map[string][]string, batch []string) string {
+	upNodeNames := make(map[string]bool)
+	for host, vnode := range vdb.HostNodeMap {
+		if vnode.Sandbox == util.MainClusterSandbox && vnode.State == util.NodeUpState && !slices.Contains(batch, host) {
+			upNodeNames[vnode.Name] = true
+		}
+	}
+	shards := maps.Keys(shardSubscribers)
+	slices.Sort(shards)
+	for _, shard := range shards {
+		if !slices.ContainsFunc(shardSubscribers[shard], func(nodeName string) bool { return upNodeNames[nodeName] }) {
+			return shard
+		}
+	}
+	return ""
+}
+
+// restartNodeBatch stops the nodes on the given hosts, starts them again, and
+// waits for them to rejoin the cluster
+func (vcc VClusterCommands) restartNodeBatch(options *VRollingRestartOptions, vdb *VCoordinationDatabase,
+	hosts []string) error {
+	stopNodeOptions := VStopNodeOptionsFactory()
+	stopNodeOptions.DatabaseOptions = options.DatabaseOptions
+	stopNodeOptions.StopHosts = hosts
+	err := vcc.VStopNode(&stopNodeOptions)
+	if err != nil {
+		return err
+	}
+
+	startNodesOptions := VStartNodesOptionsFactory()
+	startNodesOptions.DatabaseOptions = options.DatabaseOptions
+	startNodesOptions.StatePollingTimeout = options.StatePollingTimeout
+	for _, host := range hosts {
+		startNodesOptions.Nodes[vdb.HostNodeMap[host].Name] = host
+	}
+	startResults, err := vcc.VStartNodes(&startNodesOptions)
+	if err != nil {
+		return err
+	}
+	for _, startResult := range startResults {
+		if startResult.State != util.NodeUpState {
+			return categorizeError(ErrNodeDown, fmt.Errorf("node %s is %s after the restart, check %s",
+				startResult.NodeName, startResult.State, startResult.DBLogPath))
+		}
+	}
+
+	if !vdb.IsEon {
+		return nil
+	}
+	return vcc.pollRestartedNodeSubscriptions(options, getNodeNamesOfHosts(vdb, hosts), hosts)
+}
+
+// pollRestartedNodeSubscriptions waits for the shard subscriptions of the
+// restarted nodes to become ACTIVE
+func (vcc VClusterCommands) pollRestartedNodeSubscriptions(options *VRollingRestartOptions,
+	nodeNames, hosts []string) error {
+	httpsPollSubscriptionStateOp, err := makeHTTPSPollSubscriptionStateOp(hosts,
+		options.usePassword, options.UserName, options.Password, &nodeNames)
+	if err != nil {
+		return err
+	}
+	if options.StatePollingTimeout > 0 {
+		httpsPollSubscriptionStateOp.timeout = options.StatePollingTimeout
+	}
+
+	clusterOpEngine := options.makeClusterOpEngine([]clusterOp{&httpsPollSubscriptionStateOp})
+	return clusterOpEngine.run(vcc.Context(), vcc.Log)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// getNodeNamesOfHosts returns the names of the nodes on the given hosts
+func getNodeNamesOfHosts(vdb *VCoordinationDatabase, hosts []string) []string {
+	nodeNames := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if vnode, ok := vdb.HostNodeMap[host]; ok {
+			nodeNames = append(nodeNames, vnode.Name)
+		}
+	}
+	return nodeNames
+}
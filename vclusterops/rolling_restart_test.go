@@ -0,0 +1,106 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+func TestPlanRollingRestartBatches(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.IsEon = true
+	vdb.HostNodeMap = makeVHostNodeMap()
+	addNode := func(host, name string, isPrimary bool, state, sandbox string) {
+		vdb.HostList = append(vdb.HostList, host)
+		vdb.HostNodeMap[host] = &VCoordinationNode{Address: host, Name: name, IsPrimary: isPrimary,
+			State: state, Sandbox: sandbox}
+	}
+	addNode("192.168.1.101", "v_test_db_node0001", true, util.NodeUpState, "")
+	addNode("192.168.1.102", "v_test_db_node0002", true, util.NodeUpState, "")
+	addNode("192.168.1.103", "v_test_db_node0003", true, util.NodeUpState, "")
+	addNode("192.168.1.104", "v_test_db_node0004", false, util.NodeUpState, "")
+	addNode("192.168.1.105", "v_test_db_node0005", false, util.NodeDownState, "")
+	addNode("192.168.1.106", "v_test_db_node0006", false, util.NodeUpState, "sand1")
+
+	// secondary nodes first, down and sandboxed nodes are left out
+	batches, err := planRollingRestartBatches(&vdb, nil, nil, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"192.168.1.104"}, {"192.168.1.101"}, {"192.168.1.102"}, {"192.168.1.103"}}, batches)
+
+	// negative: two of the three primary nodes down at once lose the quorum
+	_, err = planRollingRestartBatches(&vdb, nil, nil, 2)
+	assert.ErrorIs(t, err, ErrQuorumLost)
+	assert.ErrorContains(t, err, "try a smaller batch size")
+
+	// selected nodes
+	batches, err = planRollingRestartBatches(&vdb, nil, []string{"v_test_db_node0003", "v_test_db_node0004"}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"192.168.1.104", "192.168.1.103"}}, batches)
+
+	// negative: a selected node is down or unknown
+	_, err = planRollingRestartBatches(&vdb, nil, []string{"v_test_db_node0005"}, 1)
+	assert.ErrorContains(t, err, "only UP nodes can be restarted")
+	_, err = planRollingRestartBatches(&vdb, nil, []string{"v_test_db_node0007"}, 1)
+	assert.ErrorContains(t, err, "do not exist in the main cluster")
+
+	// negative: the batch stops every UP subscriber of a shard
+	shardSubscribers := map[string][]string{
+		"replica":     {"v_test_db_node0001", "v_test_db_node0002", "v_test_db_node0003", "v_test_db_node0004"},
+		"segment0001": {"v_test_db_node0001", "v_test_db_node0004"},
+		"segment0002": {"v_test_db_node0002", "v_test_db_node0003"},
+	}
+	_, err = planRollingRestartBatches(&vdb, shardSubscribers, []string{"v_test_db_node0001", "v_test_db_node0004"}, 2)
+	assert.ErrorContains(t, err, "no UP node would remain subscribed to shard segment0001")
+	batches, err = planRollingRestartBatches(&vdb, shardSubscribers, []string{"v_test_db_node0001", "v_test_db_node0003"}, 1)
+	assert.NoError(t, err)
+	assert.Len(t, batches, 2)
+
+	// negative: several nodes of an Enterprise database at once
+	vdb.IsEon = false
+	_, err = planRollingRestartBatches(&vdb, nil, []string{"v_test_db_node0001", "v_test_db_node0004"}, 2)
+	assert.ErrorContains(t, err, "only one node of an Enterprise database can be down at a time")
+}
+
+func TestRestartBatchSafetyWithDownNodes(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	for i, state := range []string{util.NodeUpState, util.NodeDownState, util.NodeUpState, util.NodeUpState, util.NodeUpState} {
+		host := fmt.Sprintf("192.168.1.10%d", i+1)
+		vdb.HostList = append(vdb.HostList, host)
+		vdb.HostNodeMap[host] = &VCoordinationNode{Address: host, Name: fmt.Sprintf("v_test_db_node000%d", i+1),
+			IsPrimary: true, State: state}
+	}
+
+	// the node that is already down counts against the K-safety of an Enterprise database
+	err := checkRestartBatchSafety(&vdb, nil, []string{"192.168.1.101"})
+	assert.ErrorContains(t, err, "only one node of an Enterprise database can be down at a time, already down: 1")
+
+	// and against the quorum
+	vdb.HostNodeMap["192.168.1.103"].State = util.NodeDownState
+	vdb.IsEon = true
+	err = checkRestartBatchSafety(&vdb, nil, []string{"192.168.1.101"})
+	assert.ErrorIs(t, err, ErrQuorumLost)
+
+	// once the down nodes are back, a single node can be restarted
+	vdb.IsEon = false
+	vdb.HostNodeMap["192.168.1.102"].State = util.NodeUpState
+	vdb.HostNodeMap["192.168.1.103"].State = util.NodeUpState
+	assert.NoError(t, checkRestartBatchSafety(&vdb, nil, []string{"192.168.1.101"}))
+}
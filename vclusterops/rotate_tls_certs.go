@@ -0,0 +1,361 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type VRotateTLSCertsOptions struct {
+	/* part 1: basic db info */
+	DatabaseOptions
+
+	/* part 2: certificate rotation options */
+	// names of the new certificate and of its CA certificate in the database
+	CertificateName   string
+	CACertificateName string
+	// the new bundle, PEM-encoded
+	PrivateKeyPEM    string
+	CertificatePEM   string
+	CACertificatePEM string
+
+	// DER encoding of the certificate, parsed from CertificatePEM
+	certDER []byte
+	// the CA certificates parsed from CACertificatePEM
+	caPool *x509.CertPool
+}
+
+func VRotateTLSCertsOptionsFactory() VRotateTLSCertsOptions {
+	options := VRotateTLSCertsOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VRotateTLSCertsOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandRotateTLSCerts, logger)
+	if err != nil {
+		return err
+	}
+
+	// need to provide a password or key and certs
+	if options.Password == nil && !options.hasCertsInOptions() {
+		// validate key and cert files in local file system
+		_, err = getCertFilePaths()
+		if err != nil {
+			// in case that the key or cert files do not exist
+			return fmt.Errorf("must provide a password, key and certificates explicitly," +
+				" or key and certificate files in the default paths")
+		}
+	}
+
+	return options.validateExtraOptions()
+}
+
+func (options *VRotateTLSCertsOptions) validateExtraOptions() error {
+	if options.CertificateName == "" || options.CACertificateName == "" {
+		return fmt.Errorf("must specify the names of the certificate and of the CA certificate")
+	}
+	if options.CertificateName == options.CACertificateName {
+		return fmt.Errorf("the certificate and the CA certificate must have different names")
+	}
+	// the key must match the certificate, otherwise the services cannot use it
+	if _, err := tls.X509KeyPair([]byte(options.CertificatePEM), []byte(options.PrivateKeyPEM)); err != nil {
+		return fmt.Errorf("invalid certificate or private key, details: %w", err)
+	}
+	block, _ := pem.Decode([]byte(options.CertificatePEM))
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("invalid certificate, details: %w", err)
+	}
+	options.certDER = block.Bytes
+
+	options.caPool = x509.NewCertPool()
+	if !options.caPool.AppendCertsFromPEM([]byte(options.CACertificatePEM)) {
+		return fmt.Errorf("invalid CA certificate, no PEM-encoded certificate is found")
+	}
+	// reject a bundle that the services would serve but the clients would not trust
+	_, err = cert.Verify(x509.VerifyOptions{Roots: options.caPool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+	if err != nil {
+		return fmt.Errorf("the certificate is not signed by the CA certificate, details: %w", err)
+	}
+	return nil
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VRotateTLSCertsOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VRotateTLSCertsOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	return options.setUsePassword(logger)
+}
+
+// getHTTPSTLSConfig returns the HTTPS TLS configuration that uses the new bundle
+func (options *VRotateTLSCertsOptions) getHTTPSTLSConfig() TLSConfig {
+	return TLSConfig{
+		Name:           HTTPSTLSConfig,
+		Certificate:    options.CertificateName,
+		CACertificates: []string{options.CACertificateName},
+	}
+}
+
+// VRotateTLSCerts replaces the key, the certificate and the CA certificate
+// that the node management agents and the HTTPS service use. It runs in
+// three phases:
+//   - the new bundle is staged on the agent of every host, and the current
+//     HTTPS TLS configuration is saved
+//   - the bundle is imported into the database and used by the HTTPS TLS
+//     configuration, the agents switch to it, and every service is checked
+//     to serve the new certificate chain
+//   - the agents remove the previous bundle
+//
+// If the second phase fails, the agents restore the previous bundle and the
+// HTTPS TLS configuration is reverted, so that no host is left with a mix of
+// old and new certificates. It returns any error encountered.
+func (vcc VClusterCommands) VRotateTLSCerts(options *VRotateTLSCertsOptions) error {
+	// validate and analyze options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	// phase 1: stage the new bundle, nothing is changed if it fails
+	previousConfig := TLSConfig{}
+	instructions, err := vcc.produceStageTLSCertsInstructions(options, &previousConfig)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		return fmt.Errorf("fail to stage the new TLS certificates: %w", err)
+	}
+
+	// phase 2: switch to the new bundle, and roll back on failure
+	instructions, err = vcc.produceSwitchTLSCertsInstructions(options)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	clusterOpEngine = options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		err = fmt.Errorf("fail to rotate the TLS certificates: %w", err)
+		vcc.Log.PrintWarning("Rolling back to the previous TLS certificates, as the rotation failed")
+		rollbackErr := vcc.rollbackTLSCerts(options, &previousConfig)
+		if rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("fail to roll back to the previous TLS certificates: %w", rollbackErr))
+		}
+		return err
+	}
+
+	// phase 3: the previous bundle is no longer needed
+	cleanupOp, err := makeNMATLSCertsOp(options.Hosts, tlsCertsCleanup, nil)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	clusterOpEngine = options.makeClusterOpEngine([]clusterOp{&cleanupOp})
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		// the new bundle is in use, so a leftover backup is not a failure
+		vcc.Log.PrintWarning("Failed to remove the previous TLS certificates from the hosts: %s", err)
+	}
+	return nil
+}
+
+// The generated instructions will later perform the following operations:
+//   - Get up nodes through HTTPS call
+//   - Save the current HTTPS TLS configuration
+//   - Stage the new bundle on the agent of every host
+func (vcc VClusterCommands) produceStageTLSCertsInstructions(options *VRotateTLSCertsOptions,
+	previousConfig *TLSConfig) ([]clusterOp, error) {
+	var instructions []clusterOp
+
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password, RotateTLSCertsCmd)
+	if err != nil {
+		return instructions, err
+	}
+
+	httpsGetTLSConfigOp, err := makeHTTPSCheckTLSConfigOp(options.usePassword, options.UserName,
+		options.Password, &TLSConfig{Name: HTTPSTLSConfig})
+	if err != nil {
+		return instructions, err
+	}
+	httpsGetTLSConfigOp.reportedConfig = previousConfig
+
+	nmaStageOp, err := makeNMATLSCertsOp(options.Hosts, tlsCertsStage, &tlsCertsRequestData{
+		PrivateKey:    options.PrivateKeyPEM,
+		Certificate:   options.CertificatePEM,
+		CACertificate: options.CACertificatePEM,
+	})
+	if err != nil {
+		return instructions, err
+	}
+
+	instructions = append(instructions,
+		&httpsGetUpNodesOp,
+		&httpsGetTLSConfigOp,
+		&nmaStageOp,
+	)
+	return instructions, nil
+}
+
+// The generated instructions will later perform the following operations:
+//   - Get up nodes through HTTPS call
+//   - Import the CA certificate and the certificate into the catalog
+//   - Set the HTTPS TLS configuration to use them
+//   - Verify that all of the up nodes use the new TLS configuration
+//   - Switch the agent of every host to the new bundle
+//   - Verify that the HTTPS service of the up hosts, and the agent of every
+//     host, serve the new certificate chain
+func (vcc VClusterCommands) produceSwitchTLSCertsInstructions(options *VRotateTLSCertsOptions) ([]clusterOp, error) {
+	var instructions []clusterOp
+
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password, RotateTLSCertsCmd)
+	if err != nil {
+		return instructions, err
+	}
+
+	httpsImportCACertificateOp, err := makeHTTPSImportCertificateOp(options.usePassword, options.UserName,
+		options.Password, options.CACertificateName, options.CACertificatePEM, "")
+	if err != nil {
+		return instructions, err
+	}
+
+	httpsImportCertificateOp, err := makeHTTPSImportCertificateOp(options.usePassword, options.UserName,
+		options.Password, options.CertificateName, options.CertificatePEM, options.PrivateKeyPEM)
+	if err != nil {
+		return instructions, err
+	}
+
+	tlsConfig := options.getHTTPSTLSConfig()
+	httpsSetTLSConfigOp, err := makeHTTPSSetTLSConfigOp(options.usePassword, options.UserName,
+		options.Password, &tlsConfig)
+	if err != nil {
+		return instructions, err
+	}
+
+	httpsCheckTLSConfigOp, err := makeHTTPSCheckTLSConfigOp(options.usePassword, options.UserName,
+		options.Password, &tlsConfig)
+	if err != nil {
+		return instructions, err
+	}
+
+	nmaActivateOp, err := makeNMATLSCertsOp(options.Hosts, tlsCertsActivate, nil)
+	if err != nil {
+		return instructions, err
+	}
+
+	httpsServedCertCheckOp := makeTLSServedCertCheckOp(nil, "HTTPS service", httpsPort, options.certDER, options.caPool)
+	nmaServedCertCheckOp := makeTLSServedCertCheckOp(options.Hosts, "node management agent", nmaPort,
+		options.certDER, options.caPool)
+
+	instructions = append(instructions,
+		&httpsGetUpNodesOp,
+		&httpsImportCACertificateOp,
+		&httpsImportCertificateOp,
+		&httpsSetTLSConfigOp,
+		&httpsCheckTLSConfigOp,
+		&nmaActivateOp,
+		&httpsServedCertCheckOp,
+		&nmaServedCertCheckOp,
+	)
+	return instructions, nil
+}
+
+// rollbackTLSCerts restores the previous bundle on the agents, and the
+// previous HTTPS TLS configuration
+func (vcc VClusterCommands) rollbackTLSCerts(options *VRotateTLSCertsOptions, previousConfig *TLSConfig) error {
+	instructions, err := vcc.produceRollbackTLSCertsInstructions(options, previousConfig)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	return clusterOpEngine.run(vcc.Context(), vcc.Log)
+}
+
+// The generated instructions will later perform the following operations:
+//   - Restore the previous bundle on the agent of every host
+//   - Get up nodes through HTTPS call
+//   - Set the HTTPS TLS configuration back to the saved settings
+//   - Verify that all of the up nodes use the saved settings
+func (vcc VClusterCommands) produceRollbackTLSCertsInstructions(options *VRotateTLSCertsOptions,
+	previousConfig *TLSConfig) ([]clusterOp, error) {
+	var instructions []clusterOp
+
+	nmaRollbackOp, err := makeNMATLSCertsOp(options.Hosts, tlsCertsRollback, nil)
+	if err != nil {
+		return instructions, err
+	}
+	instructions = append(instructions, &nmaRollbackOp)
+
+	// the TLS mode was not changed, so only the certificates are restored
+	restoredConfig := TLSConfig{
+		Name:           HTTPSTLSConfig,
+		Certificate:    previousConfig.Certificate,
+		CACertificates: previousConfig.CACertificates,
+	}
+	if restoredConfig.Certificate == "" && len(restoredConfig.CACertificates) == 0 {
+		return instructions, nil
+	}
+
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password, RotateTLSCertsCmd)
+	if err != nil {
+		return instructions, err
+	}
+
+	httpsSetTLSConfigOp, err := makeHTTPSSetTLSConfigOp(options.usePassword, options.UserName,
+		options.Password, &restoredConfig)
+	if err != nil {
+		return instructions, err
+	}
+
+	httpsCheckTLSConfigOp, err := makeHTTPSCheckTLSConfigOp(options.usePassword, options.UserName,
+		options.Password, &restoredConfig)
+	if err != nil {
+		return instructions, err
+	}
+
+	instructions = append(instructions,
+		&httpsGetUpNodesOp,
+		&httpsSetTLSConfigOp,
+		&httpsCheckTLSConfigOp,
+	)
+	return instructions, nil
+}
@@ -0,0 +1,180 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTLSBundle struct {
+	keyPEM, certPEM, caPEM string
+	cert                   tls.Certificate
+}
+
+// makeTestTLSBundle returns a CA certificate and a certificate signed by it
+func makeTestTLSBundle(t *testing.T) testTLSBundle {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTemplate := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "vertica-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "vertica"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &caTemplate, &key.PublicKey, caKey)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	return testTLSBundle{
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		caPEM:   string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})),
+		cert:    tls.Certificate{Certificate: [][]byte{der, caDER}, PrivateKey: key},
+	}
+}
+
+func makeTestRotateTLSCertsOptions(bundle *testTLSBundle) VRotateTLSCertsOptions {
+	options := VRotateTLSCertsOptionsFactory()
+	options.CertificateName = "https_cert_2024"
+	options.CACertificateName = "https_ca_2024"
+	options.PrivateKeyPEM = bundle.keyPEM
+	options.CertificatePEM = bundle.certPEM
+	options.CACertificatePEM = bundle.caPEM
+	return options
+}
+
+func TestRotateTLSCertsValidation(t *testing.T) {
+	bundle := makeTestTLSBundle(t)
+	other := makeTestTLSBundle(t)
+
+	options := makeTestRotateTLSCertsOptions(&bundle)
+	assert.NoError(t, options.validateExtraOptions())
+	assert.NotEmpty(t, options.certDER)
+
+	options = makeTestRotateTLSCertsOptions(&bundle)
+	options.CACertificateName = ""
+	assert.ErrorContains(t, options.validateExtraOptions(), "must specify the names")
+
+	// the key does not match the certificate
+	options = makeTestRotateTLSCertsOptions(&bundle)
+	options.PrivateKeyPEM = other.keyPEM
+	assert.ErrorContains(t, options.validateExtraOptions(), "invalid certificate or private key")
+
+	// the certificate is signed by another CA
+	options = makeTestRotateTLSCertsOptions(&bundle)
+	options.CACertificatePEM = other.caPEM
+	assert.ErrorContains(t, options.validateExtraOptions(), "not signed by the CA certificate")
+}
+
+func TestRotateTLSCertsInstructions(t *testing.T) {
+	bundle := makeTestTLSBundle(t)
+	options := makeTestRotateTLSCertsOptions(&bundle)
+	options.Hosts = []string{"host1", "host2"}
+	assert.NoError(t, options.validateExtraOptions())
+	vcc := VClusterCommands{}
+
+	previousConfig := TLSConfig{}
+	instructions, err := vcc.produceStageTLSCertsInstructions(&options, &previousConfig)
+	assert.NoError(t, err)
+	assert.Len(t, instructions, 3)
+	assert.Same(t, &previousConfig, instructions[1].(*httpsCheckTLSConfigOp).reportedConfig)
+	stageOp := instructions[2].(*nmaTLSCertsOp)
+	assert.Equal(t, tlsCertsStage, stageOp.action)
+	assert.Contains(t, stageOp.hostRequestBody, "private_key")
+
+	instructions, err = vcc.produceSwitchTLSCertsInstructions(&options)
+	assert.NoError(t, err)
+	assert.Len(t, instructions, 8)
+	assert.Equal(t, tlsCertsActivate, instructions[5].(*nmaTLSCertsOp).action)
+	// the agents are checked on every host, the HTTPS service on the up hosts
+	assert.Equal(t, options.Hosts, instructions[7].(*tlsServedCertCheckOp).hosts)
+	assert.Empty(t, instructions[6].(*tlsServedCertCheckOp).hosts)
+
+	// the HTTPS TLS configuration is only restored if it was saved
+	instructions, err = vcc.produceRollbackTLSCertsInstructions(&options, &previousConfig)
+	assert.NoError(t, err)
+	assert.Len(t, instructions, 1)
+	previousConfig = TLSConfig{Name: HTTPSTLSConfig, Certificate: "https_cert_2023", TLSMode: "ENABLE"}
+	instructions, err = vcc.produceRollbackTLSCertsInstructions(&options, &previousConfig)
+	assert.NoError(t, err)
+	assert.Len(t, instructions, 4)
+	assert.Equal(t, "https_cert_2023", instructions[2].(*httpsSetTLSConfigOp).tlsConfig.Certificate)
+	assert.Empty(t, instructions[2].(*httpsSetTLSConfigOp).tlsConfig.TLSMode)
+}
+
+func TestTLSServedCertCheckOp(t *testing.T) {
+	bundle := makeTestTLSBundle(t)
+	other := makeTestTLSBundle(t)
+	options := makeTestRotateTLSCertsOptions(&bundle)
+	assert.NoError(t, options.validateExtraOptions())
+
+	serve := func(cert tls.Certificate) int {
+		listener, err := tls.Listen("tcp", "127.0.0.1:0",
+			&tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+		assert.NoError(t, err)
+		t.Cleanup(func() { listener.Close() })
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			_ = conn.(*tls.Conn).Handshake()
+		}()
+		return listener.Addr().(*net.TCPAddr).Port
+	}
+
+	// the service serves the new chain
+	op := makeTLSServedCertCheckOp([]string{"127.0.0.1"}, "HTTPS service", serve(bundle.cert),
+		options.certDER, options.caPool)
+	execContext := makeOpEngineExecContext(context.Background(), op.logger)
+	assert.NoError(t, op.prepare(&execContext))
+	assert.NoError(t, op.execute(&execContext))
+
+	// the service still serves another certificate
+	op = makeTLSServedCertCheckOp([]string{"127.0.0.1"}, "HTTPS service", serve(other.cert),
+		options.certDER, options.caPool)
+	err := op.execute(&execContext)
+	assert.ErrorContains(t, err, "does not serve the new certificate chain")
+}
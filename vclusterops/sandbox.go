@@ -20,6 +20,7 @@ import (
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/slices"
 )
 
 type VSandboxOptions struct {
@@ -94,7 +95,7 @@ func (options *VSandboxOptions) analyzeOptions() (err error) {
 	// we analyze hostnames when it is set in user input, otherwise we use hosts in yaml config
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -102,7 +103,7 @@ func (options *VSandboxOptions) analyzeOptions() (err error) {
 
 	// resolve SCRawHosts to be IP addresses
 	if len(options.SCRawHosts) > 0 {
-		options.SCHosts, err = util.ResolveRawHostsToAddresses(options.SCRawHosts, options.IPv6)
+		options.SCHosts, err = options.resolveRawHosts(options.SCRawHosts)
 		if err != nil {
 			return err
 		}
@@ -136,7 +137,7 @@ func (vcc *VClusterCommands) produceSandboxSubclusterInstructions(options *VSand
 
 	// when password is specified, we will use username/password to call https endpoints
 	usePassword := false
-	if options.Password != nil {
+	if options.hasPassword() {
 		usePassword = true
 		err := options.validateUserName(vcc.Log)
 		if err != nil {
@@ -212,6 +213,11 @@ func (options *VSandboxOptions) runCommand(vcc VClusterCommands) error {
 		}
 	}
 
+	err := vcc.sandboxPreCheck(options)
+	if err != nil {
+		return err
+	}
+
 	// make instructions
 	instructions, err := vcc.produceSandboxSubclusterInstructions(options)
 	if err != nil {
@@ -219,17 +225,77 @@ func (options *VSandboxOptions) runCommand(vcc VClusterCommands) error {
 	}
 
 	// add certs and instructions to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// run the engine
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to sandbox subcluster %s, %w", options.SCName, runError)
 	}
 	return nil
 }
 
+// sandboxPreCheck retrieves the database information from the main cluster,
+// including the accurate state of the sandboxed nodes, and checks that the
+// subcluster can be sandboxed
+func (vcc *VClusterCommands) sandboxPreCheck(options *VSandboxOptions) error {
+	vdb := makeVCoordinationDatabase()
+	err := vcc.getVDBFromMainRunningDBContainsSandbox(&vdb, &options.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	if !vdb.IsEon {
+		return fmt.Errorf(`cannot sandbox subclusters for an enterprise database '%s'`,
+			options.DBName)
+	}
+	return options.checkSandboxSubcluster(&vdb)
+}
+
+// checkSandboxSubcluster returns an error if the subcluster does not exist,
+// is not a secondary subcluster of the main cluster, or has nodes that are
+// not UP. The catalog isolation options are only allowed when the sandbox
+// does not exist yet, as the subclusters joining an existing sandbox share
+// its catalog and storage locations.
+func (options *VSandboxOptions) checkSandboxSubcluster(vdb *VCoordinationDatabase) error {
+	scFound := false
+	sandboxExists := false
+	var downNodes []string
+	for _, vnode := range vdb.HostNodeMap {
+		if vnode.Sandbox == options.SandboxName {
+			sandboxExists = true
+		}
+		if vnode.Subcluster != options.SCName {
+			continue
+		}
+		scFound = true
+		if vnode.Sandbox != util.MainClusterSandbox {
+			return fmt.Errorf("subcluster %s is already in sandbox %s", options.SCName, vnode.Sandbox)
+		}
+		if vnode.IsPrimary {
+			return fmt.Errorf("subcluster %s is a primary subcluster, only secondary subclusters can be sandboxed",
+				options.SCName)
+		}
+		if vnode.State != util.NodeUpState {
+			downNodes = append(downNodes, vnode.Name)
+		}
+	}
+
+	if !scFound {
+		return fmt.Errorf("subcluster %s does not exist or has no nodes", options.SCName)
+	}
+	if len(downNodes) > 0 {
+		slices.Sort(downNodes)
+		return categorizeError(ErrNodeDown,
+			fmt.Errorf("all nodes of subcluster %s must be UP to sandbox it, nodes not UP: %v",
+				options.SCName, downNodes))
+	}
+	if sandboxExists && (options.Imeta || options.Sls) {
+		return fmt.Errorf("sandbox %s already exists, metadata isolation and sandbox storage locations "+
+			"can only be requested when creating a new sandbox", options.SandboxName)
+	}
+	return nil
+}
+
 // runSandboxCmd is a help function to run sandbox/unsandbox command.
 // It can avoid code duplication between VSandbox and VUnsandbox.
 func runSandboxCmd(vcc VClusterCommands, i sandboxInterface) error {
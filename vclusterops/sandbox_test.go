@@ -0,0 +1,70 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+func TestCheckSandboxSubcluster(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	addNode := func(host, name, sc, sandbox string, isPrimary bool, state string) {
+		vdb.HostNodeMap[host] = &VCoordinationNode{Address: host, Name: name, Subcluster: sc,
+			Sandbox: sandbox, IsPrimary: isPrimary, State: state}
+	}
+	addNode("192.168.1.101", "v_test_db_node0001", "default_subcluster", "", true, util.NodeUpState)
+	addNode("192.168.1.102", "v_test_db_node0002", "sc1", "", false, util.NodeUpState)
+	addNode("192.168.1.103", "v_test_db_node0003", "sc2", "", false, util.NodeDownState)
+	addNode("192.168.1.104", "v_test_db_node0004", "sc3", "sand1", false, util.NodeUpState)
+
+	options := VSandboxOptionsFactory()
+	options.SCName = "sc1"
+	options.SandboxName = "sand2"
+	options.Imeta = true
+	assert.NoError(t, options.checkSandboxSubcluster(&vdb))
+
+	// negative: the catalog isolation options only apply to a new sandbox
+	options.SandboxName = "sand1"
+	err := options.checkSandboxSubcluster(&vdb)
+	assert.ErrorContains(t, err, "sandbox sand1 already exists")
+	options.Imeta = false
+	assert.NoError(t, options.checkSandboxSubcluster(&vdb))
+
+	// negative: primary subcluster
+	options.SCName = "default_subcluster"
+	err = options.checkSandboxSubcluster(&vdb)
+	assert.ErrorContains(t, err, "only secondary subclusters can be sandboxed")
+
+	// negative: a node of the subcluster is down
+	options.SCName = "sc2"
+	err = options.checkSandboxSubcluster(&vdb)
+	assert.ErrorIs(t, err, ErrNodeDown)
+	assert.ErrorContains(t, err, "v_test_db_node0003")
+
+	// negative: already sandboxed
+	options.SCName = "sc3"
+	err = options.checkSandboxSubcluster(&vdb)
+	assert.ErrorContains(t, err, "subcluster sc3 is already in sandbox sand1")
+
+	// negative: unknown subcluster
+	options.SCName = "sc4"
+	err = options.checkSandboxSubcluster(&vdb)
+	assert.ErrorContains(t, err, "does not exist")
+}
@@ -0,0 +1,141 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type VSaveRestorePointOptions struct {
+	/* part 1: basic db info */
+	DatabaseOptions
+
+	/* part 2: archive info */
+	// name of the archive to save the restore point to
+	ArchiveName string
+}
+
+func VSaveRestorePointOptionsFactory() VSaveRestorePointOptions {
+	options := VSaveRestorePointOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VSaveRestorePointOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandSaveRestorePoint, logger)
+	if err != nil {
+		return err
+	}
+
+	// need to provide a password or key and certs
+	if options.Password == nil && !options.hasCertsInOptions() {
+		// validate key and cert files in local file system
+		_, err = getCertFilePaths()
+		if err != nil {
+			// in case that the key or cert files do not exist
+			return fmt.Errorf("must provide a password, key and certificates explicitly," +
+				" or key and certificate files in the default paths")
+		}
+	}
+
+	return validateArchiveName(options.ArchiveName)
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VSaveRestorePointOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VSaveRestorePointOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	return options.setUsePassword(logger)
+}
+
+// VSaveRestorePoint saves a restore point of the database to an existing
+// archive. VShowRestorePoints lists the saved restore points, and revive_db can
+// restore the database to one of them. It returns any error encountered.
+func (vcc VClusterCommands) VSaveRestorePoint(options *VSaveRestorePointOptions) error {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	// validate and analyze options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	// produce save restore point instructions
+	instructions, err := vcc.produceSaveRestorePointInstructions(options)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	// create a VClusterOpEngine, and add certs to the engine
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+
+	// give the instructions to the VClusterOpEngine to run
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if runError != nil {
+		return fmt.Errorf("fail to save restore point to archive %s: %w", options.ArchiveName, runError)
+	}
+
+	return nil
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful save restore point operation:
+//   - Get up nodes through HTTPS call
+//   - Save the restore point through one of the up nodes
+func (vcc VClusterCommands) produceSaveRestorePointInstructions(options *VSaveRestorePointOptions) ([]clusterOp, error) {
+	var instructions []clusterOp
+
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password, SaveRestorePointCmd)
+	if err != nil {
+		return instructions, err
+	}
+
+	httpsSaveRestorePointOp, err := makeHTTPSSaveRestorePointOp(options.usePassword, options.UserName,
+		options.Password, options.ArchiveName)
+	if err != nil {
+		return instructions, err
+	}
+
+	instructions = append(instructions,
+		&httpsGetUpNodesOp,
+		&httpsSaveRestorePointOp,
+	)
+	return instructions, nil
+}
@@ -16,10 +16,12 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -35,9 +37,9 @@ const scrutinizeRemoteOutputPath = ScrutinizeOutputBasePath + "/remote"
 const scrutinizeLogFileName = "vcluster.log"
 
 // exported options for default use by CLI, others fixed and could be made options later
-const ScrutinizeLogMaxAgeHoursDefault = 24              // copy archived logs produced in most recent 24 hours
-const scrutinizeLogLimitBytes = 10 * 1024 * 1024 * 1024 // 10GB in bytes is the limit for individual log size
-const scrutinizeFileLimitBytes = 100 * 1024 * 1024      // 100 MB in bytes is the limit for individual misc file size
+const ScrutinizeLogMaxAgeHoursDefault = 24                     // copy archived logs produced in most recent 24 hours
+const ScrutinizeLogLimitBytesDefault = 10 * 1024 * 1024 * 1024 // 10GB in bytes is the limit for individual log size
+const ScrutinizeFileLimitBytesDefault = 100 * 1024 * 1024      // 100 MB in bytes is the limit for individual misc file size
 
 // batches are fixed, top level folders for each node's data
 const scrutinizeBatchNormal = "normal"
@@ -57,7 +59,14 @@ type VScrutinizeOptions struct {
 	SkipCollectLibs             bool
 	LogAgeOldestTime            string
 	LogAgeNewestTime            string
-	LogAgeHours                 int // max log age from input
+	LogAgeHours                 int   // max log age from input
+	LogSizeLimitBytes           int64 // limit of an individual log size
+	FileSizeLimitBytes          int64 // limit of an individual misc file or catalog snapshot size
+	IncludeCatalogSnapshot      bool
+	// when set, each host uploads its tarballs to this communal storage location,
+	// rather than vcluster retrieving them into a local tarball. The credentials
+	// of the communal storage are in ConfigurationParameters.
+	UploadLocation string
 
 	timeFormats    []util.TimeFormat // generated by factory
 	logAgeMaxHours int               // calculated from exported log age options
@@ -77,6 +86,8 @@ func (options *VScrutinizeOptions) setDefaultValues() {
 	options.DatabaseOptions.setDefaultValues()
 
 	options.ID = generateScrutinizeID()
+	options.LogSizeLimitBytes = ScrutinizeLogLimitBytesDefault
+	options.FileSizeLimitBytes = ScrutinizeFileLimitBytesDefault
 
 	// if these are changed, the help format string must also be changed
 	noTZFormat := util.TimeFormat{Layout: "2006-01-02 15", UseLocalTZ: true}
@@ -149,6 +160,16 @@ func (options *VScrutinizeOptions) validateRequiredOptions(logger vlog.Printer)
 		return err
 	}
 
+	if options.LogSizeLimitBytes <= 0 || options.FileSizeLimitBytes <= 0 {
+		return fmt.Errorf("the log and file size limits must be positive")
+	}
+	if options.UploadLocation != "" {
+		err = util.ValidateCommunalStorageLocation(options.UploadLocation)
+		if err != nil {
+			return fmt.Errorf("invalid upload location: %w", err)
+		}
+	}
+
 	// RawHosts is already required by the cmd parser, so no need to check here
 	// check if catalog prefix in user input is correct
 	return options.validateCatalogPath()
@@ -168,7 +189,7 @@ func (options *VScrutinizeOptions) analyzeOptions(logger vlog.Printer) (err erro
 	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -203,7 +224,7 @@ func (vcc VClusterCommands) VScrutinize(options *VScrutinizeOptions) error {
 	// 1. slice of nodes with NMA running
 	// 2. host -> node info map
 	vdb := makeVCoordinationDatabase()
-	err = options.getVDBForScrutinize(vcc.Log, &vdb)
+	err = options.getVDBForScrutinize(vcc.Context(), vcc.Log, &vdb)
 	if err != nil {
 		vcc.Log.Error(err, "failed to retrieve cluster info for scrutinize")
 		return err
@@ -217,12 +238,17 @@ func (vcc VClusterCommands) VScrutinize(options *VScrutinizeOptions) error {
 		vcc.Log.Error(err, "failed to produce instructions for scrutinize")
 		return err
 	}
-	err = options.runClusterOpEngine(vcc.Log, instructions)
+	err = options.runClusterOpEngine(vcc.Context(), vcc.Log, instructions)
 	if err != nil {
 		vcc.Log.Error(err, "failed to run scrutinize operations")
 		return err
 	}
 
+	if options.UploadLocation != "" {
+		vcc.Log.PrintInfo("Scrutinize results uploaded to %s/%s", strings.TrimSuffix(options.UploadLocation, "/"), options.ID)
+		return nil
+	}
+
 	// add vcluster log to output
 	options.stageVclusterLog(options.ID, vcc.Log)
 
@@ -278,11 +304,11 @@ func tarAndRemoveDirectory(tarballName, id string, log vlog.Printer) (err error)
 
 // getVDBForScrutinize populates an empty coordinator database with the minimum
 // required information for further scrutinize operations.
-func (options *VScrutinizeOptions) getVDBForScrutinize(logger vlog.Printer,
+func (options *VScrutinizeOptions) getVDBForScrutinize(ctx context.Context, logger vlog.Printer,
 	vdb *VCoordinationDatabase) error {
 	// get nodes where NMA is running and only use those for NMA ops
 	getHealthyNodesOp := makeNMAGetHealthyNodesOp(options.Hosts, vdb)
-	err := options.runClusterOpEngine(logger, []clusterOp{&getHealthyNodesOp})
+	err := options.runClusterOpEngine(ctx, logger, []clusterOp{&getHealthyNodesOp})
 	if err != nil {
 		return err
 	}
@@ -290,7 +316,7 @@ func (options *VScrutinizeOptions) getVDBForScrutinize(logger vlog.Printer,
 	// get map of host to node name and fully qualified catalog path
 	getNodesInfoOp := makeNMAGetNodesInfoOp(vdb.HostList, options.DBName,
 		options.CatalogPrefix, true /* ignore internal errors */, vdb)
-	err = options.runClusterOpEngine(logger, []clusterOp{&getNodesInfoOp})
+	err = options.runClusterOpEngine(ctx, logger, []clusterOp{&getNodesInfoOp})
 	if err != nil {
 		return err
 	}
@@ -319,10 +345,13 @@ func (options *VScrutinizeOptions) getVDBForScrutinize(logger vlog.Printer,
 //   - Stage vertica logs on all nodes
 //   - Stage files on all nodes
 //   - Stage DC tables on all nodes
+//   - (If applicable) Stage a catalog snapshot on all nodes
 //   - Tar and retrieve vertica logs and DC tables from all nodes (batch normal)
 //   - Tar and retrieve error report from all nodes (batch context)
 //   - (If applicable) Poll for system table staging completion on task node
 //   - (If applicable) Tar and retrieve system tables from task node (batch system_tables)
+//
+// With an upload location, the tarballs are uploaded by each node rather than retrieved.
 func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOptions,
 	vdb *VCoordinationDatabase) (instructions []clusterOp, err error) {
 	// extract needed info from vdb
@@ -348,7 +377,7 @@ func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOp
 
 	// stage Vertica logs
 	stageVerticaLogsOp, err := makeNMAStageVerticaLogsOp(options.ID, options.Hosts,
-		hostNodeNameMap, hostCatPathMap, scrutinizeLogLimitBytes, options.logAgeMaxHours, options.logAgeMinHours)
+		hostNodeNameMap, hostCatPathMap, options.LogSizeLimitBytes, options.logAgeMaxHours, options.logAgeMinHours)
 	if err != nil {
 		// map invariant assertion failure -- should not occur
 		return nil, err
@@ -357,16 +386,26 @@ func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOp
 
 	// stage DC Tables
 	stageDCTablesOp, err := makeNMAStageDCTablesOp(options.ID, options.Hosts,
-		hostNodeNameMap, hostCatPathMap)
+		hostNodeNameMap, hostCatPathMap, options.logAgeMaxHours, options.logAgeMinHours)
 	if err != nil {
 		// map invariant assertion failure -- should not occur
 		return nil, err
 	}
 	instructions = append(instructions, &stageDCTablesOp)
 
+	// stage a snapshot of the catalog
+	if options.IncludeCatalogSnapshot {
+		stageCatalogOp, e := makeNMAStageCatalogOp(options.ID, options.Hosts,
+			hostNodeNameMap, hostCatPathMap, options.FileSizeLimitBytes)
+		if e != nil {
+			return nil, e
+		}
+		instructions = append(instructions, &stageCatalogOp)
+	}
+
 	// stage 'normal' batch files -- see NMA for what files are collected
 	stageVerticaNormalFilesOp, err := makeNMAStageFilesOp(options.ID, scrutinizeBatchNormal,
-		options.Hosts, hostNodeNameMap, hostCatPathMap, scrutinizeFileLimitBytes)
+		options.Hosts, hostNodeNameMap, hostCatPathMap, options.FileSizeLimitBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -374,7 +413,7 @@ func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOp
 
 	// stage 'context' batch files -- see NMA for what files are collected
 	stageVerticaContextFilesOp, err := makeNMAStageFilesOp(options.ID, scrutinizeBatchContext,
-		options.Hosts, hostNodeNameMap, hostCatPathMap, scrutinizeFileLimitBytes)
+		options.Hosts, hostNodeNameMap, hostCatPathMap, options.FileSizeLimitBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -388,30 +427,43 @@ func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOp
 	}
 	instructions = append(instructions, &stageCommandsOp)
 
-	// get 'normal' batch tarball (inc. Vertica logs and 'normal' batch files)
-	getNormalTarballOp, err := makeNMAGetScrutinizeTarOp(options.ID, scrutinizeBatchNormal,
-		options.Hosts, hostNodeNameMap)
+	tarballInstructions, err := getScrutinizeTarballInstructions(options, hostNodeNameMap)
 	if err != nil {
 		return nil, err
 	}
-	instructions = append(instructions, &getNormalTarballOp)
+	instructions = append(instructions, tarballInstructions...)
 
-	// get 'context' batch tarball (inc. 'context' batch files)
-	getContextTarballOp, err := makeNMAGetScrutinizeTarOp(options.ID, scrutinizeBatchContext,
-		options.Hosts, hostNodeNameMap)
-	if err != nil {
-		return nil, err
-	}
-	instructions = append(instructions, &getContextTarballOp)
+	return instructions, nil
+}
 
+// getScrutinizeTarballInstructions retrieves or uploads the tarball of each batch
+func getScrutinizeTarballInstructions(options *VScrutinizeOptions, hostNodeNameMap map[string]string,
+) (instructions []clusterOp, err error) {
 	// get 'system_tables' batch tarball last, as staging systables can take a long time
-	getSystemTablesTarballOp, err := makeNMAGetScrutinizeTarOp(options.ID, scrutinizeBatchSystemTables,
-		options.Hosts, hostNodeNameMap)
-	if err != nil {
-		return nil, err
+	batches := []string{scrutinizeBatchNormal, scrutinizeBatchContext, scrutinizeBatchSystemTables}
+	for _, batch := range batches {
+		if options.UploadLocation != "" {
+			uploadTarballOp, e := makeNMAUploadScrutinizeTarOp(options.ID, batch, options.Hosts, hostNodeNameMap,
+				options.UploadLocation, options.ConfigurationParameters)
+			if e != nil {
+				return nil, e
+			}
+			if batch == scrutinizeBatchSystemTables {
+				uploadTarballOp.useSingleHost()
+			}
+			instructions = append(instructions, &uploadTarballOp)
+			continue
+		}
+
+		getTarballOp, e := makeNMAGetScrutinizeTarOp(options.ID, batch, options.Hosts, hostNodeNameMap)
+		if e != nil {
+			return nil, e
+		}
+		if batch == scrutinizeBatchSystemTables {
+			getTarballOp.useSingleHost()
+		}
+		instructions = append(instructions, &getTarballOp)
 	}
-	getSystemTablesTarballOp.useSingleHost()
-	instructions = append(instructions, &getSystemTablesTarballOp)
 
 	return instructions, nil
 }
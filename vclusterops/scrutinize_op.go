@@ -52,6 +52,13 @@ func (op *scrutinizeOpBase) setupClusterHTTPRequest(hosts []string) error {
 	return nil
 }
 
+// stagedItemList is the v1 response of the NMA endpoints that stage items for scrutinize
+type stagedItemList[T any] []T
+
+func (stagedItemList[T]) supportedVersions() []string {
+	return []string{NMAVersion1}
+}
+
 // processeStagedItemsResult is a parameterized function which contains common logic
 // for processing the results of staging various types of items, e.g. vertica.log,
 // system tables, etc.
@@ -67,7 +74,9 @@ func processStagedItemsResult[T any](op *scrutinizeOpBase, itemList []T) error {
 				continue
 			}
 			// the response is an array of item info structs
-			err := op.parseAndCheckResponse(host, result.content, &itemList)
+			stagedItems := stagedItemList[T](itemList)
+			err := op.parseAndValidateResponse(host, result.content, &stagedItems)
+			itemList = stagedItems
 			if err != nil {
 				err = fmt.Errorf("[%s] fail to parse result on host %s, details: %w", op.name, host, err)
 				allErrs = errors.Join(allErrs, err)
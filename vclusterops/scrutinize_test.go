@@ -148,3 +148,47 @@ func TestSetLogAgeRange(t *testing.T) {
 	assert.ErrorContains(t, err, "invalid time range: max log age cannot be less than min log age")
 	assert.Contains(t, logBuf.String(), "invalid log age range")
 }
+
+func TestScrutinizeTarballInstructions(t *testing.T) {
+	sOptions := VScrutinizeOptionsFactory()
+	sOptions.Hosts = []string{"192.168.1.101", "192.168.1.102"}
+	hostNodeNameMap := map[string]string{
+		"192.168.1.101": "v_test_db_node0001",
+		"192.168.1.102": "v_test_db_node0002",
+	}
+
+	// upload the tarballs to communal storage
+	sOptions.UploadLocation = "s3://bucket/scrutinize/"
+	instructions, err := getScrutinizeTarballInstructions(&sOptions, hostNodeNameMap)
+	assert.NoError(t, err)
+	assert.Len(t, instructions, 3)
+	uploadOp, ok := instructions[0].(*nmaUploadScrutinizeTarOp)
+	assert.True(t, ok)
+	assert.False(t, uploadOp.useInitiator)
+	err = uploadOp.setupRequestBody(sOptions.Hosts)
+	assert.NoError(t, err)
+	assert.Contains(t, uploadOp.hostRequestBodyMap["192.168.1.102"],
+		`"destination_file_path":"s3://bucket/scrutinize/`+sOptions.ID+`/v_test_db_node0002-normal.tgz"`)
+	// system tables are uploaded from a single host
+	uploadOp, ok = instructions[2].(*nmaUploadScrutinizeTarOp)
+	assert.True(t, ok)
+	assert.True(t, uploadOp.useInitiator)
+}
+
+func TestScrutinizeSizeLimitsAndUploadLocation(t *testing.T) {
+	sOptions := VScrutinizeOptionsFactory()
+	sOptions.DBName = "test_db"
+	sOptions.RawHosts = []string{"192.168.1.101"}
+	sOptions.CatalogPrefix = "/data"
+	assert.Equal(t, int64(ScrutinizeLogLimitBytesDefault), sOptions.LogSizeLimitBytes)
+	assert.NoError(t, sOptions.validateRequiredOptions(vlog.Printer{}))
+
+	// negative: invalid size limit
+	sOptions.FileSizeLimitBytes = 0
+	assert.ErrorContains(t, sOptions.validateRequiredOptions(vlog.Printer{}), "size limits must be positive")
+	sOptions.FileSizeLimitBytes = ScrutinizeFileLimitBytesDefault
+
+	// negative: invalid upload location
+	sOptions.UploadLocation = "relative/path"
+	assert.ErrorContains(t, sOptions.validateRequiredOptions(vlog.Printer{}), "invalid upload location")
+}
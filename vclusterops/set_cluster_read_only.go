@@ -0,0 +1,129 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// VSetClusterReadOnlyOptions are the options to put a database in read-only
+// mode, e.g., for maintenance or storage migrations, or to take it out of it
+type VSetClusterReadOnlyOptions struct {
+	/* part 1: basic db info */
+	DatabaseOptions
+
+	/* part 2: read-only options */
+	ReadOnly bool
+	// timeout for polling the UP nodes until they all report the new mode
+	StatePollingTimeout int
+}
+
+func VSetClusterReadOnlyOptionsFactory() VSetClusterReadOnlyOptions {
+	options := VSetClusterReadOnlyOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	options.StatePollingTimeout = util.DefaultStatePollingTimeout
+
+	return options
+}
+
+func (options *VSetClusterReadOnlyOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandSetReadOnly, logger)
+	if err != nil {
+		return err
+	}
+	if options.StatePollingTimeout < 0 {
+		return fmt.Errorf("the state polling timeout must not be negative")
+	}
+	return nil
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VSetClusterReadOnlyOptions) analyzeOptions() (err error) {
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VSetClusterReadOnlyOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	return options.setUsePasswordAndValidateUsernameIfNeeded(logger)
+}
+
+// VSetClusterReadOnly puts the main cluster of a running database in
+// read-only mode, or takes it out of it, and waits for every UP node to
+// report the new mode. The sandboxes are not changed.
+func (vcc VClusterCommands) VSetClusterReadOnly(options *VSetClusterReadOnlyOptions) error {
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	instructions, err := vcc.produceSetClusterReadOnlyInstructions(options)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		return fmt.Errorf("fail to make database %s %s: %w", options.DBName, readOnlyModeName(options.ReadOnly), err)
+	}
+	return nil
+}
+
+// produceSetClusterReadOnlyInstructions will build a list of instructions to execute for
+// the set read-only operation.
+//
+// The generated instructions will later perform the following operations:
+//   - Get the UP nodes of the main cluster
+//   - Set the read-only mode through the first UP node
+//   - Poll the UP nodes until they all report the new mode
+func (vcc VClusterCommands) produceSetClusterReadOnlyInstructions(options *VSetClusterReadOnlyOptions) ([]clusterOp, error) {
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password, SetReadOnlyCmd)
+	if err != nil {
+		return nil, err
+	}
+	httpsGetUpNodesOp.restrictToSandbox(util.MainClusterSandbox)
+
+	httpsSetReadOnlyOp, err := makeHTTPSSetReadOnlyOp(options.usePassword, options.UserName, options.Password,
+		options.ReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	httpsPollReadOnlyOp, err := makeHTTPSPollReadOnlyOp(options.usePassword, options.UserName, options.Password,
+		options.ReadOnly, options.StatePollingTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return []clusterOp{&httpsGetUpNodesOp, &httpsSetReadOnlyOp, &httpsPollReadOnlyOp}, nil
+}
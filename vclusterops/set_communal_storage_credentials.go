@@ -0,0 +1,226 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	awsAuthParameter         = "AWSAuth"
+	awsSessionTokenParameter = "AWSSessionToken"
+	clearConfigParameterVal  = "null"
+)
+
+// communalCredentialParameters are the configuration parameters, in lowercase,
+// that VSetCommunalStorageCredentials can set
+var communalCredentialParameters = []string{
+	"awsauth", "awssessiontoken", "awsregion", "awsendpoint", "awsenablehttps",
+	"awscafile", "awscapath", "awsstsendpoint",
+	"gcsauth", "gcsendpoint", "gcsregion", "gcsenablehttps",
+	"azurestoragecredentials", "azurestorageendpointconfig",
+}
+
+type VSetCommunalStorageCredentialsOptions struct {
+	/* part 1: basic db info */
+	DatabaseOptions
+
+	/* part 2: credentials options */
+	// the new values of the credential parameters, e.g., AWSAuth, AWSSessionToken,
+	// GCSAuth, or AzureStorageCredentials
+	Credentials map[string]string
+	// clear the AWS keys so that the nodes access the communal storage with
+	// the IAM role of their instances
+	UseIAMRole bool
+}
+
+func VSetCommunalStorageCredentialsOptionsFactory() VSetCommunalStorageCredentialsOptions {
+	options := VSetCommunalStorageCredentialsOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	options.Credentials = make(map[string]string)
+
+	return options
+}
+
+func (options *VSetCommunalStorageCredentialsOptions) validateParseOptions(logger vlog.Printer) error {
+	if !options.IsEon {
+		return fmt.Errorf("communal storage credentials can only be set in Eon mode")
+	}
+
+	err := options.validateBaseOptions(commandSetCommunalCredentials, logger)
+	if err != nil {
+		return err
+	}
+
+	err = options.validateExtraOptions()
+	if err != nil {
+		return err
+	}
+
+	// the new credentials must meet the requirements of the communal storage provider
+	return util.ValidateCommunalStorage(options.CommunalStorageLocation, options.getTestReadParameters())
+}
+
+func (options *VSetCommunalStorageCredentialsOptions) validateExtraOptions() error {
+	if len(options.Credentials) == 0 && !options.UseIAMRole {
+		return fmt.Errorf("must specify the new credentials, or use the IAM role")
+	}
+	for key := range options.Credentials {
+		if !slices.Contains(communalCredentialParameters, strings.ToLower(key)) {
+			return fmt.Errorf("%s is not a communal storage credential parameter", key)
+		}
+		if options.UseIAMRole && isAWSKeyParameter(key) {
+			return fmt.Errorf("cannot set %s when using the IAM role", key)
+		}
+	}
+	return nil
+}
+
+func isAWSKeyParameter(key string) bool {
+	return strings.EqualFold(key, awsAuthParameter) || strings.EqualFold(key, awsSessionTokenParameter)
+}
+
+func (options *VSetCommunalStorageCredentialsOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VSetCommunalStorageCredentialsOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// getCredentialParameters returns the configuration parameters to set in the database
+func (options *VSetCommunalStorageCredentialsOptions) getCredentialParameters() map[string]string {
+	parameters := maps.Clone(options.Credentials)
+	if options.UseIAMRole {
+		parameters[awsAuthParameter] = clearConfigParameterVal
+		parameters[awsSessionTokenParameter] = clearConfigParameterVal
+	}
+	return parameters
+}
+
+// getTestReadParameters returns the configuration parameters used to access the
+// communal storage with the new credentials: the current parameters, with the
+// credential parameters replaced by their new values
+func (options *VSetCommunalStorageCredentialsOptions) getTestReadParameters() map[string]string {
+	credentials := options.getCredentialParameters()
+	parameters := make(map[string]string)
+	for key, value := range options.ConfigurationParameters {
+		replaced := false
+		for credentialKey := range credentials {
+			replaced = replaced || strings.EqualFold(key, credentialKey)
+		}
+		if !replaced {
+			parameters[key] = value
+		}
+	}
+	for key, value := range credentials {
+		// a cleared parameter is not passed, so that the default, like the IAM role, is used
+		if value != clearConfigParameterVal {
+			parameters[key] = value
+		}
+	}
+	return parameters
+}
+
+// VSetCommunalStorageCredentials rotates the credentials that the database uses to
+// access the communal storage. It first reads the description file from the communal
+// storage with the new credentials, and only sets them in the database, which applies
+// them to all nodes, if the read succeeds. The credentials are set all or none, so
+// that the database is not left with half-rotated credentials: when a parameter fails
+// to be set, the ones already set are restored. It returns the status of each parameter.
+func (vcc VClusterCommands) VSetCommunalStorageCredentials(
+	options *VSetCommunalStorageCredentialsOptions) ([]ConfigurationParameterStatus, error) {
+	// validate and analyze all options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	err = vcc.testCommunalStorageAccess(options)
+	if err != nil {
+		return nil, err
+	}
+
+	setConfigOptions := VSetConfigurationParameterOptionsFactory()
+	setConfigOptions.DatabaseOptions = options.DatabaseOptions
+	setConfigOptions.ConfigParameters = options.getCredentialParameters()
+	statuses, err := vcc.VSetConfigurationParametersWithStatus(&setConfigOptions)
+	if err != nil {
+		if notRestored := getNotRestoredParameters(statuses); len(notRestored) > 0 {
+			return statuses, fmt.Errorf("fail to set communal storage credentials, and %v are left set to their new values, "+
+				"set them again to their old values or to new values that match the others: %w", notRestored, err)
+		}
+		return statuses, fmt.Errorf("fail to set communal storage credentials, the credentials are not changed: %w", err)
+	}
+	vcc.Log.PrintInfo("Communal storage credentials of database %s are set", options.DBName)
+	return statuses, nil
+}
+
+// getNotRestoredParameters returns the parameters that were set, but could not
+// be restored after another parameter failed to be set
+func getNotRestoredParameters(statuses []ConfigurationParameterStatus) []string {
+	var notRestored []string
+	for _, status := range statuses {
+		if status.NotRestored {
+			notRestored = append(notRestored, status.ConfigParameter)
+		}
+	}
+	return notRestored
+}
+
+// testCommunalStorageAccess reads the description file from the communal storage
+// with the new credentials
+func (vcc VClusterCommands) testCommunalStorageAccess(options *VSetCommunalStorageCredentialsOptions) error {
+	vdb := makeVCoordinationDatabase()
+	nmaHealthOp := makeNMAHealthOp(options.Hosts)
+	nmaDownLoadFileOp, err := makeNMADownloadFileOp(options.Hosts, options.getCurrConfigFilePath(),
+		currConfigFileDestPath, catalogPath, options.getTestReadParameters(), &vdb)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	nmaDownLoadFileOp.description = "Read the communal storage with the new credentials"
+	initiator, err := options.selectInitiator(vcc.Context(), options.Hosts)
+	if err != nil {
+		return err
+	}
+	nmaDownLoadFileOp.setInitiator(initiator)
+
+	clusterOpEngine := options.makeClusterOpEngine([]clusterOp{&nmaHealthOp, &nmaDownLoadFileOp})
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		return fmt.Errorf("fail to access communal storage %s with the new credentials, the credentials are not changed: %w",
+			options.CommunalStorageLocation, err)
+	}
+	return nil
+}
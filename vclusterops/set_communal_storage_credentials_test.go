@@ -0,0 +1,123 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestValidateCommunalStorageCredentialsOptions(t *testing.T) {
+	options := VSetCommunalStorageCredentialsOptionsFactory()
+	assert.ErrorContains(t, options.validateExtraOptions(), "must specify the new credentials")
+
+	options.Credentials = map[string]string{"AWSAuth": "key:secret", "AWSRegion": "us-east-1"}
+	assert.NoError(t, options.validateExtraOptions())
+
+	// negative: not a credential parameter
+	options.Credentials["MaxClientSessions"] = "100"
+	assert.ErrorContains(t, options.validateExtraOptions(), "MaxClientSessions is not a communal storage credential parameter")
+	delete(options.Credentials, "MaxClientSessions")
+
+	// negative: AWS keys together with the IAM role
+	options.UseIAMRole = true
+	assert.ErrorContains(t, options.validateExtraOptions(), "cannot set AWSAuth when using the IAM role")
+
+	options.Credentials = map[string]string{}
+	assert.NoError(t, options.validateExtraOptions())
+}
+
+func TestGetCommunalStorageCredentialParameters(t *testing.T) {
+	options := VSetCommunalStorageCredentialsOptionsFactory()
+	options.ConfigurationParameters = map[string]string{
+		"awsauth":     "old:secret",
+		"AWSEndpoint": "minio:9000",
+	}
+	options.Credentials = map[string]string{"AWSAuth": "new:secret"}
+
+	assert.Equal(t, map[string]string{"AWSAuth": "new:secret"}, options.getCredentialParameters())
+	// the old credential is replaced regardless of the case of the parameter
+	assert.Equal(t, map[string]string{"AWSAuth": "new:secret", "AWSEndpoint": "minio:9000"},
+		options.getTestReadParameters())
+
+	// the IAM role clears the AWS keys, which are not passed to the test read
+	options.Credentials = map[string]string{"AWSRegion": "us-west-2"}
+	options.UseIAMRole = true
+	assert.Equal(t, map[string]string{"AWSRegion": "us-west-2", "AWSAuth": "null", "AWSSessionToken": "null"},
+		options.getCredentialParameters())
+	assert.Equal(t, map[string]string{"AWSRegion": "us-west-2", "AWSEndpoint": "minio:9000"},
+		options.getTestReadParameters())
+	// the options are not changed
+	assert.Equal(t, map[string]string{"AWSRegion": "us-west-2"}, options.Credentials)
+}
+
+func TestCommunalStorageCredentialsAllOrNone(t *testing.T) {
+	bundle := makeTestTLSBundle(t)
+	provider, err := NewPEMCertProvider(bundle.keyPEM, bundle.certPEM, bundle.caPEM)
+	assert.NoError(t, err)
+
+	// AWSSessionToken fails to be set after AWSAuth is set
+	var sentValues []string
+	wrap := func(string, http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requestData := setConfigurationParameterData{}
+			body, _ := io.ReadAll(req.Body)
+			_ = json.Unmarshal(body, &requestData)
+			sentValues = append(sentValues, requestData.ConfigParameter+"="+requestData.Value)
+			statusCode := http.StatusOK
+			if requestData.ConfigParameter == awsSessionTokenParameter {
+				statusCode = http.StatusInternalServerError
+			}
+			return &http.Response{StatusCode: statusCode, Header: http.Header{},
+				Body: io.NopCloser(strings.NewReader(`"ok"`))}, nil
+		})
+	}
+	options := VSetCommunalStorageCredentialsOptionsFactory()
+	options.Credentials = map[string]string{awsAuthParameter: "new_id:new_secret", awsSessionTokenParameter: "new_token"}
+	runSetCredentialsOp := func(oldAWSAuth string) ([]ConfigurationParameterStatus, error) {
+		sentValues = nil
+		password := "password"
+		op, err := makeNMASetConfigurationParameterOp([]string{"host1"}, "dbadmin", "test_db", "",
+			options.getCredentialParameters(), "", &password, true)
+		assert.NoError(t, err)
+		execContext := makeOpEngineExecContext(context.Background(), vlog.Printer{})
+		execContext.dispatcher.transports = makeTransportCache(&TransportPolicy{}, nil, wrap)
+		execContext.upHostsToSandboxes = map[string]string{"host1": ""}
+		execContext.configParameterValues = map[string]string{awsAuthParameter: oldAWSAuth, awsSessionTokenParameter: ""}
+		clusterOpEngine := makeClusterOpEngine([]clusterOp{&op}, &httpsCerts{provider: provider})
+		err = clusterOpEngine.runWithExecContext(vlog.Printer{}, &execContext)
+		return execContext.configParameterStatuses, err
+	}
+
+	// AWSAuth is restored to its old value
+	statuses, err := runSetCredentialsOp("old_id:old_secret")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"AWSAuth=new_id:new_secret", "AWSSessionToken=new_token", "AWSAuth=old_id:old_secret"}, sentValues)
+	assert.Empty(t, getNotRestoredParameters(statuses))
+
+	// a masked old value is not written back
+	statuses, err = runSetCredentialsOp("****")
+	assert.ErrorContains(t, err, "fail to restore configuration parameter AWSAuth: the old value is masked by the database")
+	assert.Equal(t, []string{"AWSAuth=new_id:new_secret", "AWSSessionToken=new_token"}, sentValues)
+	assert.Equal(t, []string{awsAuthParameter}, getNotRestoredParameters(statuses))
+}
@@ -18,6 +18,7 @@ package vclusterops
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
@@ -32,7 +33,37 @@ type VSetConfigurationParameterOptions struct {
 	ConfigParameter string
 	// set value literally to "null" to clear the value of a config parameter
 	Value string
-	Level string
+	// parameters to set to their values in one engine run, e.g., a tuning
+	// profile, instead of ConfigParameter and Value. They are set all or
+	// none. Level and Sandbox apply to all of them.
+	ConfigParameters map[string]string
+	Level            string
+	// whether the parameter names and the types of the values are checked
+	// against the parameters of the database before any parameter is set
+	ValidateParameterNames bool
+	// if set, an audit record of the change, with the old and the new values,
+	// is appended to this file. VShowConfigurationAudit lists the records.
+	AuditFilePath string
+	// if set, only the UP nodes of Sandbox, or of the main cluster when
+	// Sandbox is empty, can be picked to set the parameters
+	RestrictToSandbox bool
+}
+
+const (
+	configParameterSetSuccess = "Success"
+	configParameterSetFailure = "Failure"
+)
+
+// ConfigurationParameterStatus is the outcome of setting a configuration parameter
+type ConfigurationParameterStatus struct {
+	ConfigParameter string `json:"config_parameter"`
+	// Success or Failure
+	SetStatus string `json:"set_status"`
+	// why the parameter failed to be set
+	Error string `json:"error,omitempty"`
+	// whether the parameter is left set to its new value, because it could
+	// not be restored to its old value after a later parameter failed
+	NotRestored bool `json:"not_restored,omitempty"`
 }
 
 func VSetConfigurationParameterOptionsFactory() VSetConfigurationParameterOptions {
@@ -50,7 +81,7 @@ func (opt *VSetConfigurationParameterOptions) validateParseOptions(logger vlog.P
 	}
 
 	// need to provide a password or key and certs
-	if opt.Password == nil && (opt.Cert == "" || opt.Key == "") {
+	if opt.Password == nil && !opt.hasCertsInOptions() {
 		// validate key and cert files in local file system
 		_, err := getCertFilePaths()
 		if err != nil {
@@ -64,13 +95,24 @@ func (opt *VSetConfigurationParameterOptions) validateParseOptions(logger vlog.P
 }
 
 func (opt *VSetConfigurationParameterOptions) validateExtraOptions(logger vlog.Printer) error {
-	if opt.ConfigParameter == "" {
+	if len(opt.ConfigParameters) > 0 {
+		if opt.ConfigParameter != "" {
+			return fmt.Errorf("cannot set configuration parameter %s together with a list of configuration parameters",
+				opt.ConfigParameter)
+		}
+		if _, found := opt.ConfigParameters[""]; found {
+			return fmt.Errorf("configuration parameter must not be empty")
+		}
+	} else if opt.ConfigParameter == "" {
 		errStr := "configuration parameter must not be empty"
 		logger.PrintError(errStr)
 		return errors.New(errStr)
 	}
 	// opt.Value could be empty (which is not equivalent to "null")
 	// opt.Level could be empty (which means database level)
+	if opt.AuditFilePath != "" {
+		return util.ValidateAbsPath(opt.AuditFilePath, "audit file path")
+	}
 	return nil
 }
 
@@ -78,13 +120,13 @@ func (opt *VSetConfigurationParameterOptions) analyzeOptions() (err error) {
 	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
 	if len(opt.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		opt.Hosts, err = util.ResolveRawHostsToAddresses(opt.RawHosts, opt.IPv6)
+		opt.Hosts, err = opt.resolveRawHosts(opt.RawHosts)
 		if err != nil {
 			return err
 		}
 		opt.normalizePaths()
 	}
-	return nil
+	return opt.TargetSelector.resolveExcludedHosts(&opt.DatabaseOptions)
 }
 
 func (opt *VSetConfigurationParameterOptions) validateAnalyzeOptions(log vlog.Printer) error {
@@ -101,39 +143,135 @@ func (opt *VSetConfigurationParameterOptions) validateAnalyzeOptions(log vlog.Pr
 	return opt.validateUserName(log)
 }
 
-// VSetConfigurationParameters sets or clears the value of a database configuration parameter.
-// It returns any error encountered.
+// getConfigParameters returns the parameters to set, keyed by their names
+func (opt *VSetConfigurationParameterOptions) getConfigParameters() map[string]string {
+	if len(opt.ConfigParameters) > 0 {
+		return opt.ConfigParameters
+	}
+	return map[string]string{opt.ConfigParameter: opt.Value}
+}
+
+// VSetConfigurationParameters sets or clears the value of a database configuration parameter,
+// or of each parameter in ConfigParameters. It returns any error encountered.
 func (vcc VClusterCommands) VSetConfigurationParameters(options *VSetConfigurationParameterOptions) error {
+	_, err := vcc.VSetConfigurationParametersWithStatus(options)
+	return err
+}
+
+// VSetConfigurationParametersWithStatus sets the configuration parameters like
+// VSetConfigurationParameters, in one engine run. The parameters are set all
+// or none: when a parameter fails to be set, the remaining ones are not set,
+// and the ones already set are restored to their old values. The returned
+// status of each parameter tells what happened to it. No status is returned
+// if the run fails before any parameter is set.
+func (vcc VClusterCommands) VSetConfigurationParametersWithStatus(
+	options *VSetConfigurationParameterOptions) (statuses []ConfigurationParameterStatus, err error) {
 	// validate and analyze all options
-	err := options.validateAnalyzeOptions(vcc.Log)
+	err = options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return err
+		return statuses, err
 	}
 
 	// produce set configuration parameters instructions
 	instructions, err := vcc.produceSetConfigurationParameterInstructions(options)
 	if err != nil {
-		return fmt.Errorf("fail to produce instructions, %w", err)
+		return statuses, fmt.Errorf("fail to produce instructions, %w", err)
 	}
 
 	// Create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	statuses = clusterOpEngine.execContext.configParameterStatuses
+	if options.AuditFilePath != "" {
+		vcc.auditConfigurationParameters(options, statuses, clusterOpEngine.execContext.configParameterValues)
+	}
+	options.setOutcomes(statuses, runError)
 	if runError != nil {
-		return fmt.Errorf("fail to set configuration parameter: %w", runError)
+		return statuses, options.report.partialSuccessError(fmt.Errorf("fail to set configuration parameter: %w", runError))
 	}
 
-	return nil
+	return statuses, nil
+}
+
+// setOutcomes sets the outcome of setting each parameter in the operation
+// report. All of the parameters fail when the run fails before setting them.
+func (opt *VSetConfigurationParameterOptions) setOutcomes(statuses []ConfigurationParameterStatus, runError error) {
+	var outcomes []TargetOutcome
+	if len(statuses) == 0 && runError != nil {
+		for configParameter := range opt.getConfigParameters() {
+			outcomes = append(outcomes, TargetOutcome{Target: configParameter, Err: runError})
+		}
+	}
+	for _, status := range statuses {
+		outcome := TargetOutcome{Target: status.ConfigParameter, Succeeded: status.SetStatus == configParameterSetSuccess}
+		if !outcome.Succeeded {
+			outcome.Err = errors.New(status.Error)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	opt.report.setOutcomes(outcomes)
+}
+
+// VRerunFailedConfigurationParameters sets the parameters that the last
+// VSetConfigurationParametersWithStatus with the options failed to set, and
+// does not set the others again. The ConfigParameters of the options are
+// narrowed to the failed parameters.
+func (vcc VClusterCommands) VRerunFailedConfigurationParameters(
+	options *VSetConfigurationParameterOptions) ([]ConfigurationParameterStatus, error) {
+	if options.report.Status == "" {
+		return nil, fmt.Errorf("cannot rerun set configuration parameters before the parameters are set once")
+	}
+	failedParameters := options.report.FailedTargets()
+	if len(failedParameters) == 0 {
+		vcc.Log.PrintInfo("No failed configuration parameters to set again")
+		return nil, nil
+	}
+	// a single parameter is set again as is
+	if len(options.ConfigParameters) > 0 {
+		configParameters := options.ConfigParameters
+		options.ConfigParameters = make(map[string]string)
+		for _, configParameter := range failedParameters {
+			options.ConfigParameters[configParameter] = configParameters[configParameter]
+		}
+	}
+	return vcc.VSetConfigurationParametersWithStatus(options)
+}
+
+// auditConfigurationParameters appends an audit record of each parameter that was set
+func (vcc VClusterCommands) auditConfigurationParameters(options *VSetConfigurationParameterOptions,
+	statuses []ConfigurationParameterStatus, oldValues map[string]string) {
+	configParameters := options.getConfigParameters()
+	for _, status := range statuses {
+		if status.SetStatus != configParameterSetSuccess {
+			continue
+		}
+		record := ConfigurationAuditRecord{
+			DBName:          options.DBName,
+			Sandbox:         options.Sandbox,
+			ConfigParameter: status.ConfigParameter,
+			OldValue:        oldValues[status.ConfigParameter],
+			NewValue:        configParameters[status.ConfigParameter],
+			Level:           options.Level,
+			User:            options.UserName,
+			Timestamp:       time.Now().UTC(),
+		}
+		err := appendConfigurationAuditRecord(options.AuditFilePath, &record)
+		if err != nil {
+			// the parameter is already set, so do not fail the command
+			vcc.Log.PrintWarning("fail to write the audit record of the configuration change, detail: %s", err)
+		}
+	}
 }
 
 // The generated instructions will later perform the following operations necessary
 // for a successful set configuration parameter action.
 //   - Check NMA connectivity
 //   - Check UP nodes and sandboxes info
-//   - Send set configuration parameter request
+//   - List the valid configuration parameters, if the names are validated
+//   - Get the current values of the configuration parameters, if the change is audited
+//   - Send a set configuration parameter request for each parameter
 func (vcc VClusterCommands) produceSetConfigurationParameterInstructions(
 	options *VSetConfigurationParameterOptions) ([]clusterOp, error) {
 	var instructions []clusterOp
@@ -145,12 +283,17 @@ func (vcc VClusterCommands) produceSetConfigurationParameterInstructions(
 	if err != nil {
 		return instructions, err
 	}
+	if options.RestrictToSandbox {
+		httpsGetUpNodesOp.restrictToSandbox(options.Sandbox)
+	}
+	httpsGetUpNodesOp.selectTargets(&options.TargetSelector)
 
 	nmaHealthOp := makeNMAHealthOp(options.Hosts)
 
+	configParameters := options.getConfigParameters()
 	nmaSetConfigOp, err := makeNMASetConfigurationParameterOp(options.Hosts,
 		options.UserName, options.DBName, options.Sandbox,
-		options.ConfigParameter, options.Value, options.Level,
+		configParameters, options.Level,
 		options.Password, options.usePassword)
 	if err != nil {
 		return instructions, err
@@ -159,8 +302,35 @@ func (vcc VClusterCommands) produceSetConfigurationParameterInstructions(
 	instructions = append(instructions,
 		&nmaHealthOp,
 		&httpsGetUpNodesOp,
-		&nmaSetConfigOp,
 	)
 
+	if options.ValidateParameterNames {
+		nmaListConfigOp, err := makeNMAListAllConfigurationParametersOp(options.Hosts,
+			options.UserName, options.DBName, options.Sandbox,
+			options.Password, options.usePassword)
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, &nmaListConfigOp)
+		nmaSetConfigOp.validateNames = true
+	}
+
+	// the old values are kept for the audit, and to restore the parameters
+	// that were set when a later parameter fails
+	if options.AuditFilePath != "" || len(nmaSetConfigOp.configParameters) > 1 {
+		for _, configParameter := range nmaSetConfigOp.configParameters {
+			nmaGetConfigOp, err := makeNMAGetConfigurationParameterOp(options.Hosts,
+				options.UserName, options.DBName, options.Sandbox,
+				configParameter, options.Level,
+				options.Password, options.usePassword)
+			if err != nil {
+				return instructions, err
+			}
+			instructions = append(instructions, &nmaGetConfigOp)
+		}
+	}
+
+	instructions = append(instructions, &nmaSetConfigOp)
+
 	return instructions, nil
 }
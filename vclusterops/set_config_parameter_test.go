@@ -79,4 +79,55 @@ func TestVSetConfigurationParameterOptions_validateParseOptions(t *testing.T) {
 	opt.ConfigParameter = ""
 	err = opt.validateParseOptions(logger)
 	assert.Error(t, err)
+	// positive: a list of configuration parameters
+	opt.ConfigParameters = map[string]string{testConfigParameter: testValue, "config-test-parameter-2": "null"}
+	err = opt.validateParseOptions(logger)
+	assert.NoError(t, err)
+	assert.Len(t, opt.getConfigParameters(), 2)
+
+	// negative: a list of configuration parameters together with a single one
+	opt.ConfigParameter = testConfigParameter
+	err = opt.validateParseOptions(logger)
+	assert.ErrorContains(t, err, "together with a list of configuration parameters")
+
+	// negative: an empty parameter name in the list
+	opt.ConfigParameter = ""
+	opt.ConfigParameters = map[string]string{"": testValue}
+	err = opt.validateParseOptions(logger)
+	assert.ErrorContains(t, err, "configuration parameter must not be empty")
+}
+
+func TestSetConfigurationParametersInstructions(t *testing.T) {
+	vcc := VClusterCommands{}
+	password := "config-test-password"
+	opt := VSetConfigurationParameterOptionsFactory()
+	opt.DBName = "test_db"
+	opt.UserName = "dbadmin"
+	opt.Password = &password
+	opt.usePassword = true
+	opt.Hosts = []string{"192.168.1.101"}
+
+	// a single parameter does not need its old value
+	opt.ConfigParameter = "MaxClientSessions"
+	opt.Value = "100"
+	instructions, err := vcc.produceSetConfigurationParameterInstructions(&opt)
+	assert.NoError(t, err)
+	assert.Len(t, instructions, 3)
+	// the UP nodes are found in all sandboxes unless the option restricts them
+	assert.False(t, instructions[1].(*httpsGetUpNodesOp).sandboxScoped)
+	opt.RestrictToSandbox = true
+	instructions, err = vcc.produceSetConfigurationParameterInstructions(&opt)
+	assert.NoError(t, err)
+	getUpNodesOp := instructions[1].(*httpsGetUpNodesOp)
+	assert.True(t, getUpNodesOp.sandboxScoped)
+	assert.True(t, getUpNodesOp.mainCluster)
+	opt.RestrictToSandbox = false
+
+	// the old values are read to restore the parameters set before a failed one
+	opt.ConfigParameter = ""
+	opt.ConfigParameters = map[string]string{"MaxClientSessions": "100", "EnableSSL": "1"}
+	instructions, err = vcc.produceSetConfigurationParameterInstructions(&opt)
+	assert.NoError(t, err)
+	assert.Len(t, instructions, 5)
+	assert.Equal(t, "NMAGetConfigurationParameterOp", instructions[2].getName())
 }
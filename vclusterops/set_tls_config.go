@@ -0,0 +1,198 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// TLSConfigName is the name of a TLS configuration in the database
+type TLSConfigName string
+
+const (
+	// ServerTLSConfig secures the client-server connections
+	ServerTLSConfig TLSConfigName = "server"
+	// HTTPSTLSConfig secures the HTTPS service
+	HTTPSTLSConfig TLSConfigName = "https"
+)
+
+// valid TLS modes of a TLS configuration
+var tlsModes = []string{"DISABLE", "ENABLE", "TRY_VERIFY", "VERIFY_CA", "VERIFY_FULL"}
+
+// TLSConfig holds the settings of a TLS configuration. The empty settings
+// are left unchanged.
+type TLSConfig struct {
+	// name of the TLS configuration to change
+	Name TLSConfigName
+	// name of the certificate, already imported into the database, that the
+	// TLS configuration uses
+	Certificate string
+	// names of the CA certificates, already imported into the database, that
+	// the TLS configuration trusts
+	CACertificates []string
+	// TLS mode, one of DISABLE, ENABLE, TRY_VERIFY, VERIFY_CA and VERIFY_FULL
+	TLSMode string
+}
+
+func (c *TLSConfig) validate() error {
+	if c.Name != ServerTLSConfig && c.Name != HTTPSTLSConfig {
+		return fmt.Errorf("invalid TLS configuration %q, must be one of %s, %s", c.Name, ServerTLSConfig, HTTPSTLSConfig)
+	}
+	if c.Certificate == "" && len(c.CACertificates) == 0 && c.TLSMode == "" {
+		return fmt.Errorf("must specify a certificate, CA certificates or a TLS mode for TLS configuration %s", c.Name)
+	}
+	if c.TLSMode != "" {
+		c.TLSMode = strings.ToUpper(c.TLSMode)
+		if !util.StringInArray(c.TLSMode, tlsModes) {
+			return fmt.Errorf("invalid TLS mode %q, must be one of %s", c.TLSMode, strings.Join(tlsModes, ", "))
+		}
+	}
+	// the HTTPS service cannot be turned off, as vcluster relies on it
+	if c.Name == HTTPSTLSConfig && c.TLSMode == "DISABLE" {
+		return fmt.Errorf("cannot disable TLS for the HTTPS service")
+	}
+	return nil
+}
+
+type VSetTLSConfigOptions struct {
+	/* part 1: basic db info */
+	DatabaseOptions
+
+	/* part 2: TLS configuration */
+	TLSConfig TLSConfig
+}
+
+func VSetTLSConfigOptionsFactory() VSetTLSConfigOptions {
+	options := VSetTLSConfigOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VSetTLSConfigOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandSetTLSConfig, logger)
+	if err != nil {
+		return err
+	}
+
+	// need to provide a password or key and certs
+	if options.Password == nil && !options.hasCertsInOptions() {
+		// validate key and cert files in local file system
+		_, err = getCertFilePaths()
+		if err != nil {
+			// in case that the key or cert files do not exist
+			return fmt.Errorf("must provide a password, key and certificates explicitly," +
+				" or key and certificate files in the default paths")
+		}
+	}
+
+	return options.TLSConfig.validate()
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VSetTLSConfigOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VSetTLSConfigOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	return options.setUsePassword(logger)
+}
+
+// VSetTLSConfig changes the certificate, the CA certificates or the TLS mode of
+// a TLS configuration, and then verifies that all of the up nodes use the
+// new settings. It returns any error encountered.
+func (vcc VClusterCommands) VSetTLSConfig(options *VSetTLSConfigOptions) error {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	// validate and analyze options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	// produce set TLS config instructions
+	instructions, err := vcc.produceSetTLSConfigInstructions(options)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	// create a VClusterOpEngine, and add certs to the engine
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+
+	// give the instructions to the VClusterOpEngine to run
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if runError != nil {
+		return fmt.Errorf("fail to set TLS configuration %s: %w", options.TLSConfig.Name, runError)
+	}
+
+	return nil
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful set TLS config operation:
+//   - Get up nodes through HTTPS call
+//   - Set the TLS configuration through one of the up nodes
+//   - Verify that all of the up nodes use the new TLS configuration
+func (vcc VClusterCommands) produceSetTLSConfigInstructions(options *VSetTLSConfigOptions) ([]clusterOp, error) {
+	var instructions []clusterOp
+
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password, SetTLSConfigCmd)
+	if err != nil {
+		return instructions, err
+	}
+
+	httpsSetTLSConfigOp, err := makeHTTPSSetTLSConfigOp(options.usePassword, options.UserName,
+		options.Password, &options.TLSConfig)
+	if err != nil {
+		return instructions, err
+	}
+
+	httpsCheckTLSConfigOp, err := makeHTTPSCheckTLSConfigOp(options.usePassword, options.UserName,
+		options.Password, &options.TLSConfig)
+	if err != nil {
+		return instructions, err
+	}
+
+	instructions = append(instructions,
+		&httpsGetUpNodesOp,
+		&httpsSetTLSConfigOp,
+		&httpsCheckTLSConfigOp,
+	)
+	return instructions, nil
+}
@@ -0,0 +1,59 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSConfigValidate(t *testing.T) {
+	config := TLSConfig{Name: ServerTLSConfig, TLSMode: "verify_ca"}
+	assert.NoError(t, config.validate())
+	// the TLS mode is normalized
+	assert.Equal(t, "VERIFY_CA", config.TLSMode)
+
+	// nothing to change
+	config = TLSConfig{Name: ServerTLSConfig}
+	assert.Error(t, config.validate())
+
+	config = TLSConfig{Name: "internode", Certificate: "cert"}
+	assert.Error(t, config.validate())
+
+	config = TLSConfig{Name: ServerTLSConfig, TLSMode: "STRICT"}
+	assert.Error(t, config.validate())
+
+	config = TLSConfig{Name: HTTPSTLSConfig, TLSMode: "DISABLE"}
+	assert.Error(t, config.validate())
+}
+
+func TestCheckTLSConfigCompare(t *testing.T) {
+	config := TLSConfig{Name: ServerTLSConfig, Certificate: "server_cert",
+		CACertificates: []string{"ca1", "ca2"}, TLSMode: "VERIFY_CA"}
+	op, err := makeHTTPSCheckTLSConfigOp(false, "", nil, &config)
+	assert.NoError(t, err)
+
+	response := tlsConfigResponse{Name: "server", Certificate: "server_cert",
+		CACertificates: []string{"ca2", "ca1"}, TLSMode: "verify_ca"}
+	assert.NoError(t, op.compare("192.168.1.101", &response))
+
+	response.Certificate = "old_cert"
+	response.CACertificates = []string{"ca1"}
+	err = op.compare("192.168.1.101", &response)
+	assert.ErrorContains(t, err, `certificate "old_cert"`)
+	assert.ErrorContains(t, err, "CA certificates [ca1]")
+}
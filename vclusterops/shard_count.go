@@ -0,0 +1,82 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+)
+
+// a node:shard ratio above this leaves the extra nodes of a subcluster
+// without a shard of their own to serve
+const maxNodesPerShard = 2
+
+// ShardCountAdvice is the result of CheckShardCount
+type ShardCountAdvice struct {
+	ShardCount int `json:"shard_count"`
+	// the smallest shard count that splits evenly across both the initial
+	// and the expected node counts
+	RecommendedShardCount int      `json:"recommended_shard_count"`
+	Warnings              []string `json:"warnings,omitempty"`
+}
+
+// CheckShardCount validates the shard count of an Eon database with nodeCount
+// nodes in a subcluster, which is expected to grow to expectedNodeCount nodes.
+// The warnings describe the shard counts that prevent the subcluster from
+// scaling elastically: the shards must split evenly across the nodes, and
+// there must not be more than two nodes per shard. With a zero shardCount or
+// expectedNodeCount, it only recommends a shard count or checks the initial
+// node count respectively.
+func CheckShardCount(shardCount, nodeCount, expectedNodeCount int) (ShardCountAdvice, error) {
+	advice := ShardCountAdvice{ShardCount: shardCount}
+	if nodeCount <= 0 {
+		return advice, fmt.Errorf("the node count must be greater than 0")
+	}
+	if shardCount < 0 || expectedNodeCount < 0 {
+		return advice, fmt.Errorf("the shard count and the expected node count must not be negative")
+	}
+	if expectedNodeCount == 0 {
+		expectedNodeCount = nodeCount
+	}
+
+	advice.RecommendedShardCount = nodeCount / gcd(nodeCount, expectedNodeCount) * expectedNodeCount
+	if shardCount == 0 {
+		return advice, nil
+	}
+
+	nodeCounts := []int{nodeCount}
+	if expectedNodeCount != nodeCount {
+		nodeCounts = append(nodeCounts, expectedNodeCount)
+	}
+	for _, count := range nodeCounts {
+		if count > maxNodesPerShard*shardCount {
+			advice.Warnings = append(advice.Warnings, fmt.Sprintf(
+				"%d nodes with %d shards is more than %d nodes per shard, the extra nodes will not improve query performance",
+				count, shardCount, maxNodesPerShard))
+		} else if shardCount%count != 0 && count%shardCount != 0 {
+			advice.Warnings = append(advice.Warnings, fmt.Sprintf(
+				"%d shards do not split evenly across %d nodes, some nodes will serve more shards than the others",
+				shardCount, count))
+		}
+	}
+	return advice, nil
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,62 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckShardCount(t *testing.T) {
+	// only recommend a shard count
+	advice, err := CheckShardCount(0, 3, 12)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, advice.RecommendedShardCount)
+	assert.Empty(t, advice.Warnings)
+
+	advice, err = CheckShardCount(0, 4, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, advice.RecommendedShardCount)
+
+	// the expected node count defaults to the node count
+	advice, err = CheckShardCount(6, 3, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, advice.RecommendedShardCount)
+	assert.Empty(t, advice.Warnings)
+
+	// the shards split evenly when the subcluster grows
+	advice, err = CheckShardCount(12, 3, 12)
+	assert.NoError(t, err)
+	assert.Empty(t, advice.Warnings)
+
+	// uneven split once the subcluster grows
+	advice, err = CheckShardCount(6, 3, 4)
+	assert.NoError(t, err)
+	assert.Len(t, advice.Warnings, 1)
+	assert.Contains(t, advice.Warnings[0], "6 shards do not split evenly across 4 nodes")
+
+	// too many nodes per shard once the subcluster grows
+	advice, err = CheckShardCount(3, 3, 9)
+	assert.NoError(t, err)
+	assert.Len(t, advice.Warnings, 1)
+	assert.Contains(t, advice.Warnings[0], "9 nodes with 3 shards is more than 2 nodes per shard")
+
+	_, err = CheckShardCount(6, 0, 0)
+	assert.ErrorContains(t, err, "node count must be greater than 0")
+	_, err = CheckShardCount(6, 3, -1)
+	assert.ErrorContains(t, err, "must not be negative")
+}
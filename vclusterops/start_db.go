@@ -20,6 +20,8 @@ import (
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 // VStartDatabaseOptions represents the available options when you start a database
@@ -44,11 +46,35 @@ type VStartDatabaseOptions struct {
 	// you may not want to have both the NMA and Vertica server in the same container.
 	// This feature requires version 24.2.0+.
 	StartUpConf string
+	// Extra vertica arguments that are merged into the start command of a
+	// node, keyed by node name, e.g., {"v_db_node0001": {"-D",
+	// "/data/db/v_db_node0001_catalog", "--debug"}}. Each argument is its own
+	// element, so a value can contain spaces. The value of a flag that is
+	// already in the start command is replaced. This is meant for recovery,
+	// when the stored start command of a node is wrong.
+	StartCommandOverrides map[string][]string
 	// whether the provided hosts are in a sandbox
 	HostsInSandbox bool
 
 	// whether the first time to start the database after revive
 	FirstStartAfterRevive bool
+	// start the primary nodes first, and wait for them to be UP and to sync
+	// the catalog before starting the secondary nodes. This speeds up the
+	// recovery of a large Eon database, as the secondary nodes do not compete
+	// with the primary nodes to form the quorum.
+	StagedStart bool
+	// Re-ip the nodes whose addresses changed before starting them. The nodes
+	// are matched to the hosts by the catalog found on each host, so this
+	// helps when the hosts get new addresses, e.g., from DHCP or when the
+	// pods are rescheduled in Kubernetes.
+	AutoReIP bool
+
+	// the hosts of the primary and the secondary nodes found in the catalog,
+	// only set for StagedStart
+	primaryHosts   []string
+	secondaryHosts []string
+	// the nodes whose addresses changed, only set for AutoReIP
+	reIPList []ReIPInfo
 }
 
 func VStartDatabaseOptionsFactory() VStartDatabaseOptions {
@@ -71,6 +97,10 @@ func (options *VStartDatabaseOptions) validateRequiredOptions(logger vlog.Printe
 	if err != nil {
 		return err
 	}
+	err = validateStartCommandOverrides(options.StartCommandOverrides)
+	if err != nil {
+		return err
+	}
 	return options.validateCatalogPath()
 }
 
@@ -99,7 +129,7 @@ func (options *VStartDatabaseOptions) analyzeOptions() (err error) {
 	// we analyze hostnames when it is set in user input, otherwise we use hosts in yaml config
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -126,14 +156,20 @@ func (vcc VClusterCommands) VStartDatabase(options *VStartDatabaseOptions) (vdbP
 	if err != nil {
 		return nil, err
 	}
+	// the database can start with a quorum of the hosts
+	err = options.runPreflightProbe(vcc)
+	if err != nil {
+		return nil, err
+	}
 
 	// VER-93369 may improve this if the CLI knows which nodes are primary
 	// from the config file
 	var vdb VCoordinationDatabase
 	// retrieve database information from cluster_config.json for Eon databases,
 	// skip this step for starting a sandbox because cluster_config.json does not
-	// contain accurate info of nodes in a sandbox
-	if !options.HostsInSandbox && options.IsEon {
+	// contain accurate info of nodes in a sandbox, and for AutoReIP because the
+	// addresses in cluster_config.json are stale if they changed
+	if !options.HostsInSandbox && options.IsEon && !options.AutoReIP {
 		const warningMsg = " for an Eon database, start_db after revive_db could fail " +
 			"because we cannot retrieve the correct database information"
 		if options.CommunalStorageLocation != "" {
@@ -159,6 +195,18 @@ func (vcc VClusterCommands) VStartDatabase(options *VStartDatabaseOptions) (vdbP
 		return nil, err
 	}
 
+	if len(options.reIPList) > 0 {
+		err = vcc.reIPBeforeStartDB(options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.isStagedStart() {
+		vcc.PrintInfo("Starting the database in stages: %d primary node(s) first, then %d secondary node(s)",
+			len(options.primaryHosts), len(options.secondaryHosts))
+	}
+
 	// produce start_db instructions
 	instructions, err := vcc.produceStartDBInstructions(options, &vdb)
 	if err != nil {
@@ -166,11 +214,10 @@ func (vcc VClusterCommands) VStartDatabase(options *VStartDatabaseOptions) (vdbP
 	}
 
 	// create a VClusterOpEngine for start_db instructions, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		return nil, fmt.Errorf("fail to start database: %w", runError)
 	}
@@ -193,13 +240,19 @@ func (vcc VClusterCommands) runStartDBPrecheck(options *VStartDatabaseOptions, v
 	}
 
 	// create a VClusterOpEngine for pre-check, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(preInstructions, &certs)
-	runError := clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := options.makeClusterOpEngine(preInstructions)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to start database pre-checks: %w", runError)
 	}
 
+	// the nodes whose addresses changed are found on the hosts with the
+	// new addresses, which they will have in the catalog after the re-ip
+	if options.AutoReIP {
+		options.reIPList = findChangedNodeAddresses(&clusterOpEngine.execContext.nmaVDatabase, vdb)
+		clusterOpEngine.execContext.nmaVDatabase.moveNodeAddresses(options.reIPList)
+	}
+
 	// If requested, remove any provided hosts that are not in the catalog. Use
 	// the vdb that we just fetched by the catalog editor. It will be the from
 	// the latest catalog.
@@ -207,9 +260,92 @@ func (vcc VClusterCommands) runStartDBPrecheck(options *VStartDatabaseOptions, v
 		options.Hosts = vcc.removeHostsNotInCatalog(&clusterOpEngine.execContext.nmaVDatabase, options.Hosts)
 	}
 
+	if options.StagedStart {
+		options.splitHostsByRole(&clusterOpEngine.execContext.nmaVDatabase)
+		if !options.isStagedStart() {
+			vcc.Log.PrintWarning("Starting all nodes at once, as the hosts do not have both primary and secondary nodes")
+		}
+	}
+
 	return nil
 }
 
+// findChangedNodeAddresses returns the re-ip list of the nodes whose address
+// in the catalog is not the address of the host where the node is found
+func findChangedNodeAddresses(catalogVDB *nmaVDatabase, hostVDB *VCoordinationDatabase) []ReIPInfo {
+	nodeNameToAddress := make(map[string]string)
+	for i := range catalogVDB.Nodes {
+		nodeNameToAddress[catalogVDB.Nodes[i].Name] = catalogVDB.Nodes[i].Address
+	}
+
+	var reIPList []ReIPInfo
+	hosts := maps.Keys(hostVDB.HostNodeMap)
+	slices.Sort(hosts)
+	for _, host := range hosts {
+		nodeName := hostVDB.HostNodeMap[host].Name
+		address, ok := nodeNameToAddress[nodeName]
+		if !ok || address == host {
+			continue
+		}
+		reIPList = append(reIPList, ReIPInfo{NodeName: nodeName, NodeAddress: address, TargetAddress: host})
+	}
+	return reIPList
+}
+
+// moveNodeAddresses moves the nodes in the re-ip list to their new addresses
+// in the host to node map
+func (vdb *nmaVDatabase) moveNodeAddresses(reIPList []ReIPInfo) {
+	for _, info := range reIPList {
+		vnode, ok := vdb.HostNodeMap[info.NodeAddress]
+		if !ok {
+			continue
+		}
+		delete(vdb.HostNodeMap, info.NodeAddress)
+		vnode.Address = info.TargetAddress
+		vdb.HostNodeMap[info.TargetAddress] = vnode
+	}
+}
+
+// reIPBeforeStartDB re-ips the nodes whose addresses changed
+func (vcc VClusterCommands) reIPBeforeStartDB(options *VStartDatabaseOptions) error {
+	for _, info := range options.reIPList {
+		vcc.PrintInfo("The address of node %s changed from %s to %s, re-ip it before starting the database",
+			info.NodeName, info.NodeAddress, info.TargetAddress)
+	}
+
+	reIPOptions := VReIPFactory()
+	reIPOptions.DatabaseOptions = options.DatabaseOptions
+	// read the nodes info from the hosts, as cluster_config.json has the
+	// old addresses
+	reIPOptions.CommunalStorageLocation = ""
+	reIPOptions.ReIPList = options.reIPList
+	err := vcc.VReIP(&reIPOptions)
+	if err != nil {
+		return fmt.Errorf("fail to re-ip the nodes whose addresses changed: %w", err)
+	}
+	return nil
+}
+
+// splitHostsByRole splits the hosts into the hosts of the primary nodes and
+// the hosts of the secondary nodes in the catalog. The hosts not found in the
+// catalog are started with the secondary nodes.
+func (options *VStartDatabaseOptions) splitHostsByRole(vdb *nmaVDatabase) {
+	options.primaryHosts, options.secondaryHosts = nil, nil
+	for _, host := range options.Hosts {
+		if vnode, ok := vdb.HostNodeMap[host]; ok && vnode.IsPrimary {
+			options.primaryHosts = append(options.primaryHosts, host)
+		} else {
+			options.secondaryHosts = append(options.secondaryHosts, host)
+		}
+	}
+}
+
+// isStagedStart returns true if the nodes can be started in stages, which
+// requires both primary and secondary nodes
+func (options *VStartDatabaseOptions) isStagedStart() bool {
+	return options.StagedStart && len(options.primaryHosts) > 0 && len(options.secondaryHosts) > 0
+}
+
 func (vcc VClusterCommands) removeHostsNotInCatalog(vdb *nmaVDatabase, hosts []string) []string {
 	var trimmedHostList []string
 	var extraHosts []string
@@ -268,8 +404,10 @@ func (vcc VClusterCommands) produceStartDBPreCheck(options *VStartDatabaseOption
 		instructions = append(instructions, &nmaGetNodesInfoOp)
 	}
 
-	// find latest catalog to use for removal of nodes not in the catalog
-	if trimHostList {
+	// find latest catalog to use for removal of nodes not in the catalog,
+	// for telling the primary nodes apart in a staged start, or for
+	// finding the nodes whose addresses changed
+	if trimHostList || options.StagedStart || options.AutoReIP {
 		nmaReadCatalogEditorOp, err := makeNMAReadCatalogEditorOpForStartDB(vdb, options.FirstStartAfterRevive)
 		if err != nil {
 			return instructions, err
@@ -322,7 +460,17 @@ func (vcc VClusterCommands) produceStartDBInstructions(options *VStartDatabaseOp
 		options.Hosts,
 		nil /*db configurations retrieved from a running db*/)
 
+	if options.isStagedStart() {
+		stagedStartOps, err := options.produceStagedStartOps()
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, stagedStartOps...)
+		return instructions, nil
+	}
+
 	nmaStartNewNodesOp := makeNMAStartNodeOp(options.Hosts, options.StartUpConf)
+	nmaStartNewNodesOp.startCommandOverrides = options.StartCommandOverrides
 	httpsPollNodeStateOp, err := makeHTTPSPollNodeStateOpWithTimeoutAndCommand(options.Hosts,
 		options.usePassword, options.UserName, options.Password, options.StatePollingTimeout, StartDBCmd)
 	if err != nil {
@@ -345,6 +493,43 @@ func (vcc VClusterCommands) produceStartDBInstructions(options *VStartDatabaseOp
 	return instructions, nil
 }
 
+// produceStagedStartOps produces the ops that start the nodes in two stages:
+//   - Start the primary nodes, poll them until they are UP, and sync the catalog (Eon mode only)
+//   - Start the secondary nodes all together, and poll them until they are UP
+func (options *VStartDatabaseOptions) produceStagedStartOps() ([]clusterOp, error) {
+	const stageCount = 2
+	var instructions []clusterOp
+	for i, stageHosts := range [][]string{options.primaryHosts, options.secondaryHosts} {
+		stage := i + 1
+		role := "primary"
+		if stage == stageCount {
+			role = "secondary"
+		}
+		nmaStartNodeOp := makeNMAStartNodeOp(stageHosts, options.StartUpConf)
+		nmaStartNodeOp.startCommandOverrides = options.StartCommandOverrides
+		nmaStartNodeOp.description = fmt.Sprintf("Stage %d/%d: start %d %s node(s)", stage, stageCount, len(stageHosts), role)
+		httpsPollNodeStateOp, err := makeHTTPSPollNodeStateOpWithTimeoutAndCommand(stageHosts,
+			options.usePassword, options.UserName, options.Password, options.StatePollingTimeout, StartDBCmd)
+		if err != nil {
+			return instructions, err
+		}
+		httpsPollNodeStateOp.description = fmt.Sprintf("Stage %d/%d: wait for the %s node(s) to be UP", stage, stageCount, role)
+		instructions = append(instructions, &nmaStartNodeOp, &httpsPollNodeStateOp)
+
+		// the secondary nodes start from the catalog synced by the primary nodes
+		if options.IsEon && stage == 1 {
+			httpsSyncCatalogOp, err := makeHTTPSSyncCatalogOp(stageHosts, options.usePassword, options.UserName,
+				options.Password, StartDBSyncCat)
+			if err != nil {
+				return instructions, err
+			}
+			httpsSyncCatalogOp.description = fmt.Sprintf("Stage %d/%d: synchronize catalog with communal storage", stage, stageCount)
+			instructions = append(instructions, &httpsSyncCatalogOp)
+		}
+	}
+	return instructions, nil
+}
+
 func (vcc VClusterCommands) setOrRotateEncryptionKey(keyType string) clusterOp {
 	vcc.Log.Info("adding instruction to set or rotate the key for spread encryption")
 	op := makeNMASpreadSecurityOp(vcc.Log, keyType)
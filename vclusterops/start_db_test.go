@@ -0,0 +1,93 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStagedStart(t *testing.T) {
+	options := VStartDatabaseOptionsFactory()
+	options.Hosts = []string{"192.168.1.101", "192.168.1.102", "192.168.1.103", "192.168.1.104"}
+	options.IsEon = true
+	options.StagedStart = true
+	vdb := nmaVDatabase{HostNodeMap: map[string]*nmaVNode{
+		"192.168.1.101": {IsPrimary: true},
+		"192.168.1.102": {IsPrimary: false},
+		"192.168.1.103": {IsPrimary: true},
+	}}
+
+	// the host not in the catalog is started with the secondary nodes
+	options.splitHostsByRole(&vdb)
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.103"}, options.primaryHosts)
+	assert.Equal(t, []string{"192.168.1.102", "192.168.1.104"}, options.secondaryHosts)
+	assert.True(t, options.isStagedStart())
+
+	instructions, err := options.produceStagedStartOps()
+	assert.NoError(t, err)
+	var opNames, opDescriptions []string
+	for _, op := range instructions {
+		opNames = append(opNames, op.getName())
+		opDescriptions = append(opDescriptions, op.getOpInfo().Description)
+	}
+	// the catalog is synced before the secondary nodes start
+	assert.Equal(t, []string{"NMAStartNodeOp", "HTTPSPollNodeStateOp", "HTTPSSyncCatalogOp",
+		"NMAStartNodeOp", "HTTPSPollNodeStateOp"}, opNames)
+	assert.Equal(t, "Stage 1/2: start 2 primary node(s)", opDescriptions[0])
+	assert.Equal(t, "Stage 2/2: wait for the secondary node(s) to be UP", opDescriptions[4])
+
+	// all nodes start at once without secondary nodes
+	options.Hosts = []string{"192.168.1.101", "192.168.1.103"}
+	options.splitHostsByRole(&vdb)
+	assert.False(t, options.isStagedStart())
+}
+
+func TestFindChangedNodeAddresses(t *testing.T) {
+	catalogVDB := nmaVDatabase{Nodes: []nmaVNode{
+		{Name: "v_db_node0001", Address: "192.168.1.101", IsPrimary: true},
+		{Name: "v_db_node0002", Address: "192.168.1.102", IsPrimary: true},
+		{Name: "v_db_node0003", Address: "192.168.1.103"},
+	}}
+	catalogVDB.HostNodeMap = make(map[string]*nmaVNode)
+	for i := range catalogVDB.Nodes {
+		catalogVDB.HostNodeMap[catalogVDB.Nodes[i].Address] = &catalogVDB.Nodes[i]
+	}
+
+	// the nodes 2 and 3 are found on hosts with new addresses
+	hostVDB := makeVCoordinationDatabase()
+	hostVDB.HostNodeMap = vHostNodeMap{
+		"192.168.1.101": {Name: "v_db_node0001"},
+		"192.168.1.113": {Name: "v_db_node0003"},
+		"192.168.1.112": {Name: "v_db_node0002"},
+	}
+	reIPList := findChangedNodeAddresses(&catalogVDB, &hostVDB)
+	assert.Equal(t, []ReIPInfo{
+		{NodeName: "v_db_node0002", NodeAddress: "192.168.1.102", TargetAddress: "192.168.1.112"},
+		{NodeName: "v_db_node0003", NodeAddress: "192.168.1.103", TargetAddress: "192.168.1.113"},
+	}, reIPList)
+
+	// the nodes are kept with their new addresses, e.g., for trimming the hosts
+	catalogVDB.moveNodeAddresses(reIPList)
+	assert.NotContains(t, catalogVDB.HostNodeMap, "192.168.1.102")
+	assert.Equal(t, "v_db_node0002", catalogVDB.HostNodeMap["192.168.1.112"].Name)
+	assert.True(t, catalogVDB.HostNodeMap["192.168.1.112"].IsPrimary)
+
+	// no node has changed its address
+	hostVDB.HostNodeMap = vHostNodeMap{"192.168.1.101": {Name: "v_db_node0001"}}
+	assert.Empty(t, findChangedNodeAddresses(&catalogVDB, &hostVDB))
+}
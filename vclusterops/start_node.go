@@ -18,9 +18,11 @@ package vclusterops
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/slices"
 )
 
 // VStartNodesOptions represents the available options when you start one or more nodes
@@ -37,6 +39,9 @@ type VStartNodesOptions struct {
 	// you may not want to have both the NMA and Vertica server in the same container.
 	// This feature requires version 24.2.0+.
 	StartUpConf string
+	// Extra vertica arguments that are merged into the start command of a
+	// node, keyed by node name. See VStartDatabaseOptions.StartCommandOverrides.
+	StartCommandOverrides map[string][]string
 
 	vdb *VCoordinationDatabase
 }
@@ -57,6 +62,21 @@ type VStartNodesInfo struct {
 	hasDownNodeNoNeedToReIP bool
 }
 
+// NodeStartResult holds the outcome of starting a node with VStartNodes
+type NodeStartResult struct {
+	Host     string
+	NodeName string
+	// the path of dbLog reported by the NMA when starting the node, which
+	// is where to look first if the node fails to come up
+	DBLogPath string
+	// the state of the node after the start, UP if the start succeeded
+	State string
+	// the global catalog version of the node after the start
+	CatalogVersion int64
+	// whether the catalog of the node caught up with an UP node of the cluster
+	CatalogSynced bool
+}
+
 func VStartNodesOptionsFactory() VStartNodesOptions {
 	options := VStartNodesOptions{}
 
@@ -77,7 +97,7 @@ func (options *VStartNodesOptions) validateRequiredOptions(logger vlog.Printer)
 	if err != nil {
 		return err
 	}
-	return nil
+	return validateStartCommandOverrides(options.StartCommandOverrides)
 }
 
 func (options *VStartNodesOptions) validateParseOptions(logger vlog.Printer) error {
@@ -94,12 +114,12 @@ func (options *VStartNodesOptions) analyzeOptions() (err error) {
 	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
 	}
-	return nil
+	return options.TargetSelector.resolveExcludedHosts(&options.DatabaseOptions)
 }
 
 // ParseNodesList resolves hostname in a nodeName-hostname map and build a new map.
@@ -107,7 +127,7 @@ func (options *VStartNodesOptions) analyzeOptions() (err error) {
 // map[string]string{vnodeName1: 192.168.1.101, vnodeName2: 192.168.1.102}
 func (options *VStartNodesOptions) ParseNodesList(rawNodeMap map[string]string) error {
 	for k, v := range rawNodeMap {
-		ip, err := util.ResolveToOneIP(v, options.IPv6)
+		ip, err := options.resolveHost(v)
 		if err != nil {
 			return err
 		}
@@ -150,11 +170,12 @@ func (vcc VClusterCommands) startNodePreCheck(vdb *VCoordinationDatabase, option
 }
 
 // VStartNodes starts the given nodes for a cluster that has not yet lost
-// cluster quorum. Returns any error encountered. If necessary, it updates the
-// node's IP in the Vertica catalog. If cluster quorum is already lost, use
-// VStartDatabase. It will skip any nodes given that no longer exist in the
-// catalog.
-func (vcc VClusterCommands) VStartNodes(options *VStartNodesOptions) error {
+// cluster quorum. If necessary, it updates the node's IP in the Vertica
+// catalog. If cluster quorum is already lost, use VStartDatabase. It will skip
+// any nodes given that no longer exist in the catalog. It waits for the nodes
+// to be UP and for their catalog to be synced, and returns the outcome of
+// each started node, sorted by host, along with any error encountered.
+func (vcc VClusterCommands) VStartNodes(options *VStartNodesOptions) ([]NodeStartResult, error) {
 	/*
 	 *   - Produce Instructions
 	 *   - Create a VClusterOpEngine
@@ -164,7 +185,7 @@ func (vcc VClusterCommands) VStartNodes(options *VStartNodesOptions) error {
 	// validate and analyze options
 	err := options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// retrieve database information to execute the command so we do not always rely on some user input
@@ -173,7 +194,7 @@ func (vcc VClusterCommands) VStartNodes(options *VStartNodesOptions) error {
 	if options.vdb == nil {
 		err = vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, AnySandbox)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 		vdb = *options.vdb
@@ -185,19 +206,28 @@ func (vcc VClusterCommands) VStartNodes(options *VStartNodesOptions) error {
 		hostNodeNameMap[vnode.Name] = vnode.Address
 	}
 
+	// only start the nodes that satisfy the target selector, the nodes of the
+	// caller are restored once the command returns
+	nodes := options.Nodes
+	defer func() { options.Nodes = nodes }()
+	options.Nodes = options.filterNodesByTargetSelector(&vdb, hostNodeNameMap)
+	if len(options.Nodes) == 0 && len(nodes) > 0 {
+		return nil, fmt.Errorf("none of the nodes to start is selected by the target selector")
+	}
+
 	// precheck to make sure the nodes to start are either all sandboxed nodes in one sandbox or all main cluster nodes
 	err = vcc.startNodePreCheck(&vdb, options, hostNodeNameMap, restartNodeInfo)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// sandboxes may have different catalog from the main cluster, update the vdb build from the sandbox of the nodes to restart
 	err = vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, restartNodeInfo.Sandbox)
 	if err != nil {
 		if restartNodeInfo.Sandbox != util.MainClusterSandbox {
-			return errors.Join(err, fmt.Errorf("hint: make sure there is at least one UP node in the sandbox %s", restartNodeInfo.Sandbox))
+			return nil, errors.Join(err, fmt.Errorf("hint: make sure there is at least one UP node in the sandbox %s", restartNodeInfo.Sandbox))
 		}
-		return errors.Join(err, fmt.Errorf("hint: make sure there is at least one UP node in the database"))
+		return nil, errors.Join(err, fmt.Errorf("hint: make sure there is at least one UP node in the database"))
 	}
 
 	// find out hosts
@@ -208,7 +238,7 @@ func (vcc VClusterCommands) VStartNodes(options *VStartNodesOptions) error {
 	// check primary node count is more than nodes to re-ip, specially for sandboxes
 	err = options.checkQuorum(&vdb, restartNodeInfo)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// for the hosts that don't need to re-ip,
@@ -218,7 +248,7 @@ func (vcc VClusterCommands) VStartNodes(options *VStartNodesOptions) error {
 		const msg = "The provided nodes are either not in catalog or already up. There is nothing to start."
 		fmt.Println(msg)
 		vcc.Log.Info(msg)
-		return nil
+		return nil, nil
 	}
 
 	// we can proceed to restart both nodes with and without IP changes
@@ -231,25 +261,102 @@ func (vcc VClusterCommands) VStartNodes(options *VStartNodesOptions) error {
 		const msg = "None of the nodes provided are in the catalog. There is nothing to start."
 		fmt.Println(msg)
 		vcc.Log.Info(msg)
-		return nil
+		return nil, nil
 	}
 
 	// produce restart_node instructions
 	instructions, err := vcc.produceStartNodesInstructions(restartNodeInfo, options, &vdb)
 	if err != nil {
-		return fmt.Errorf("fail to produce instructions, %w", err)
+		return nil, fmt.Errorf("fail to produce instructions, %w", err)
 	}
 
 	// create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	err = clusterOpEngine.run(vcc.Log)
+	firstOp := len(options.report.Ops)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	results := makeNodeStartResults(clusterOpEngine.execContext, restartNodeInfo.HostsToStart, &vdb, err == nil)
+	options.setOutcomes(results, firstOp, err)
 	if err != nil {
-		return fmt.Errorf("fail to restart node, %w", err)
+		return results, options.report.partialSuccessError(fmt.Errorf("fail to restart node, %w", err))
 	}
-	return nil
+	return results, nil
+}
+
+// setOutcomes sets the outcome of starting each host in the operation report
+func (options *VStartNodesOptions) setOutcomes(results []NodeStartResult, firstOp int, runErr error) {
+	var outcomes []TargetOutcome
+	for i := range results {
+		outcome := TargetOutcome{Target: results[i].Host, Succeeded: results[i].State == util.NodeUpState}
+		if !outcome.Succeeded {
+			outcome.Err = options.report.hostErrorsOf(results[i].Host, firstOp)
+			if outcome.Err == nil {
+				outcome.Err = runErr
+			}
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	options.report.setOutcomes(outcomes)
+}
+
+// VRerunFailedStartNodes starts the nodes that the last VStartNodes with the
+// options failed to start, and does not start the others again. The Nodes of
+// the options are narrowed to the failed nodes.
+func (vcc VClusterCommands) VRerunFailedStartNodes(options *VStartNodesOptions) ([]NodeStartResult, error) {
+	if options.report.Status == "" {
+		return nil, fmt.Errorf("cannot rerun start nodes before the nodes are started once")
+	}
+	failedHosts := options.report.FailedTargets()
+	for nodeName, host := range options.Nodes {
+		if !slices.Contains(failedHosts, host) {
+			delete(options.Nodes, nodeName)
+		}
+	}
+	if len(options.Nodes) == 0 {
+		vcc.Log.PrintInfo("No failed nodes to start again")
+		return nil, nil
+	}
+	return vcc.VStartNodes(options)
+}
+
+// makeNodeStartResults collects the outcome of starting the given hosts from
+// the ops that ran
+func makeNodeStartResults(execContext *opEngineExecContext, hosts []string,
+	vdb *VCoordinationDatabase, succeeded bool) []NodeStartResult {
+	var results []NodeStartResult
+	for _, host := range hosts {
+		result := NodeStartResult{Host: host}
+		if execContext != nil {
+			result = *execContext.getNodeStartResult(host)
+		}
+		if vnode, ok := vdb.HostNodeMap[host]; ok {
+			result.NodeName = vnode.Name
+			result.State = vnode.State
+		}
+		if succeeded {
+			result.State = util.NodeUpState
+		}
+		results = append(results, result)
+	}
+	slices.SortFunc(results, func(a, b NodeStartResult) int { return strings.Compare(a.Host, b.Host) })
+	return results
+}
+
+// filterNodesByTargetSelector returns a copy of options.Nodes without the nodes
+// that are not selected by options.TargetSelector. The nodes that are not in the
+// catalog are kept, as they will be skipped later.
+func (options *VStartNodesOptions) filterNodesByTargetSelector(vdb *VCoordinationDatabase,
+	hostNodeNameMap map[string]string) map[string]string {
+	selectedNodes := make(map[string]string)
+	for nodename, newIP := range options.Nodes {
+		oldIP, ok := hostNodeNameMap[nodename]
+		if ok && !options.TargetSelector.isNodeSelected(vdb.HostNodeMap[oldIP]) {
+			continue
+		}
+		selectedNodes[nodename] = newIP
+	}
+	return selectedNodes
 }
 
 // primary up node details can vary in case of sandboxes. This check is to ensure quorum is maintained
@@ -289,6 +396,7 @@ func (options *VStartNodesOptions) checkQuorum(vdb *VCoordinationDatabase, resta
 //   - restart nodes
 //   - Poll node start up
 //   - sync catalog
+//   - Poll the catalog version of the nodes until it matches an UP node
 func (vcc VClusterCommands) produceStartNodesInstructions(startNodeInfo *VStartNodesInfo, options *VStartNodesOptions,
 	vdb *VCoordinationDatabase) ([]clusterOp, error) {
 	var instructions []clusterOp
@@ -358,8 +466,10 @@ func (vcc VClusterCommands) produceStartNodesInstructions(startNodeInfo *VStartN
 	if err != nil {
 		return instructions, err
 	}
+	httpsRestartUpCommandOp.savedStartupCommands = options.StartupCommands
 
 	nmaRestartNewNodesOp := makeNMAStartNodeOpWithVDB(startNodeInfo.HostsToStart, options.StartUpConf, vdb)
+	nmaRestartNewNodesOp.startCommandOverrides = options.StartCommandOverrides
 	httpsPollNodeStateOp, err := makeHTTPSPollNodeStateOpWithTimeoutAndCommand(startNodeInfo.HostsToStart,
 		options.usePassword, options.UserName, options.Password, options.StatePollingTimeout, StartNodeCmd)
 	if err != nil {
@@ -381,6 +491,10 @@ func (vcc VClusterCommands) produceStartNodesInstructions(startNodeInfo *VStartN
 		instructions = append(instructions, &httpsSyncCatalogOp)
 	}
 
+	nmaPollCatalogVersionOp := makeNMAPollCatalogVersionOp(startNodeInfo.HostsToStart, vdb,
+		startNodeInfo.Sandbox, options.StatePollingTimeout)
+	instructions = append(instructions, &nmaPollCatalogVersionOp)
+
 	return instructions, nil
 }
 
@@ -88,7 +88,7 @@ func (options *VStartScOptions) analyzeOptions() (err error) {
 	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -112,6 +112,7 @@ func (options *VStartScOptions) validateAnalyzeOptions(logger vlog.Printer) erro
 // VStartSubcluster has two major phases:
 //  1. Pre-check: check the subcluster name and get nodes for the subcluster.
 //  2. Start nodes: Optional. If there are any down nodes in the subcluster, runs VStartNodes.
+//     Otherwise the subcluster is already running, and no error is returned.
 func (vcc VClusterCommands) VStartSubcluster(options *VStartScOptions) error {
 	err := options.validateAnalyzeOptions(vcc.Log)
 	if err != nil {
@@ -125,6 +126,11 @@ func (vcc VClusterCommands) VStartSubcluster(options *VStartScOptions) error {
 		return err
 	}
 
+	scNodeCount, _, err := countSubclusterNodes(&vdb, options.SCName)
+	if err != nil {
+		return err
+	}
+
 	// node name to host address map
 	nodesToStart := make(map[string]string)
 
@@ -135,9 +141,11 @@ func (vcc VClusterCommands) VStartSubcluster(options *VStartScOptions) error {
 		}
 	}
 
+	// nothing to do when the subcluster has no down nodes, so that scaling out
+	// can safely be retried
 	if len(nodesToStart) == 0 {
-		return fmt.Errorf("cannot find down node to start in subcluster %s",
-			options.SCName)
+		vcc.Log.PrintInfo("All %d nodes of subcluster %s are already running", scNodeCount, options.SCName)
+		return nil
 	}
 
 	var startNodesOptions VStartNodesOptions
@@ -148,5 +156,24 @@ func (vcc VClusterCommands) VStartSubcluster(options *VStartScOptions) error {
 
 	vlog.DisplayColorInfo("Starting nodes %v in subcluster %s", maps.Keys(nodesToStart), options.SCName)
 
-	return vcc.VStartNodes(&startNodesOptions)
+	_, err = vcc.VStartNodes(&startNodesOptions)
+	return err
+}
+
+// countSubclusterNodes returns the number of nodes of the subcluster, and how
+// many of them are UP. It returns an error if the subcluster has no nodes.
+func countSubclusterNodes(vdb *VCoordinationDatabase, scName string) (nodeCount, upNodeCount int, err error) {
+	for _, vnode := range vdb.HostNodeMap {
+		if vnode.Subcluster != scName {
+			continue
+		}
+		nodeCount++
+		if vnode.State == util.NodeUpState {
+			upNodeCount++
+		}
+	}
+	if nodeCount == 0 {
+		return 0, 0, fmt.Errorf("cannot find subcluster %s in database", scName)
+	}
+	return nodeCount, upNodeCount, nil
 }
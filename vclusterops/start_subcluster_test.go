@@ -0,0 +1,46 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+func TestCountSubclusterNodes(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	vdb.HostNodeMap["192.168.1.101"] = &VCoordinationNode{Subcluster: "sc1", State: util.NodeUpState}
+	vdb.HostNodeMap["192.168.1.102"] = &VCoordinationNode{Subcluster: "sc1", State: util.NodeDownState}
+	vdb.HostNodeMap["192.168.1.103"] = &VCoordinationNode{Subcluster: "sc2", State: util.NodeDownState}
+
+	nodeCount, upNodeCount, err := countSubclusterNodes(&vdb, "sc1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, nodeCount)
+	assert.Equal(t, 1, upNodeCount)
+
+	// a stopped subcluster
+	nodeCount, upNodeCount, err = countSubclusterNodes(&vdb, "sc2")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, nodeCount)
+	assert.Equal(t, 0, upNodeCount)
+
+	// negative: unknown subcluster
+	_, _, err = countSubclusterNodes(&vdb, "sc3")
+	assert.ErrorContains(t, err, "cannot find subcluster sc3")
+}
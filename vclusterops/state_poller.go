@@ -16,6 +16,8 @@
 package vclusterops
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -29,6 +31,10 @@ const (
 	PollingInterval          = 3 * OneSecond
 )
 
+// errPollingTimeout is the category of the error returned when the polling
+// reaches its timeout, for the ops that can carry on after it
+var errPollingTimeout = errors.New("reached polling timeout")
+
 type statePoller interface {
 	getPollingTimeout() int
 	shouldStopPolling() (bool, error)
@@ -53,7 +59,9 @@ func pollState(poller statePoller, execContext *opEngineExecContext) error {
 		}
 
 		if count > 0 {
-			time.Sleep(PollingInterval * time.Second)
+			if err := sleepWithContext(execContext.ctx, PollingInterval*time.Second); err != nil {
+				return err
+			}
 		}
 
 		shouldStopPoll, err := poller.shouldStopPolling()
@@ -72,5 +80,18 @@ func pollState(poller statePoller, execContext *opEngineExecContext) error {
 		count++
 	}
 
-	return fmt.Errorf("reached polling timeout of %d seconds", timeout)
+	return categorizeError(errPollingTimeout, fmt.Errorf("reached polling timeout of %d seconds", timeout))
+}
+
+// sleepWithContext waits for the given duration, and returns early with an
+// error if the context is canceled in the meantime
+func sleepWithContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("polling is canceled: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
 }
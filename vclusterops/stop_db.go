@@ -30,6 +30,12 @@ type VStopDatabaseOptions struct {
 	DrainSeconds *int   // time in seconds to wait for database users' disconnection
 	SandboxName  string // Stop db on given sandbox
 	MainCluster  bool   // Stop db on main cluster only
+	// pause new connections and wait up to DrainSeconds for the user sessions
+	// to end before stopping the nodes, which closes the sessions left. It
+	// applies to the main cluster, or to the sandbox given by SandboxName.
+	// It requires an Eon database and a positive DrainSeconds. The connections
+	// are resumed if the database fails to stop.
+	GracefulDrain bool
 	/* part 3: hidden info */
 	CheckUserConn bool // whether check user connection
 	ForceKill     bool // whether force kill connections
@@ -47,6 +53,20 @@ func (options *VStopDatabaseOptions) setDefaultValues() {
 	options.DatabaseOptions.setDefaultValues()
 }
 
+// SetDrainSeconds sets the time in seconds to wait for the users to disconnect
+func (options *VStopDatabaseOptions) SetDrainSeconds(drainSeconds int) {
+	options.DrainSeconds = &drainSeconds
+}
+
+// getDrainSeconds treats an unset DrainSeconds as no drain time, so that
+// options built without the factory do not panic
+func (options *VStopDatabaseOptions) getDrainSeconds() int {
+	if options.DrainSeconds == nil {
+		return 0
+	}
+	return *options.DrainSeconds
+}
+
 func (options *VStopDatabaseOptions) validateRequiredOptions(log vlog.Printer) error {
 	err := options.validateBaseOptions(commandStopDB, log)
 	if err != nil {
@@ -73,6 +93,15 @@ func (options *VStopDatabaseOptions) validateEonOptions(log vlog.Printer) error
 		options.DrainSeconds = new(int)
 		*options.DrainSeconds = util.DefaultDrainSeconds
 	}
+
+	if options.GracefulDrain {
+		if !options.IsEon {
+			return fmt.Errorf("a graceful drain is only available in Eon mode")
+		}
+		if options.getDrainSeconds() <= 0 {
+			return fmt.Errorf("a graceful drain requires a positive drain time")
+		}
+	}
 	return nil
 }
 
@@ -109,7 +138,7 @@ func (options *VStopDatabaseOptions) validateParseOptions(log vlog.Printer) erro
 func (options *VStopDatabaseOptions) analyzeOptions() (err error) {
 	// resolve RawHosts to be IP addresses
 	if len(options.RawHosts) > 0 {
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -157,11 +186,10 @@ func (vcc VClusterCommands) VStopDatabase(options *VStopDatabaseOptions) error {
 	}
 
 	// Create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to stop database: %w", runError)
 	}
@@ -175,6 +203,7 @@ func (vcc VClusterCommands) VStopDatabase(options *VStopDatabaseOptions) error {
 // The generated instructions will later perform the following operations necessary
 // for a successful stop_db:
 //   - Get up nodes through https call
+//   - Pause new connections and wait for user sessions to end (graceful drain only)
 //   - Sync catalog through the first up node
 //   - Stop db through the first up node
 //   - Check there is not any database running
@@ -183,7 +212,7 @@ func (vcc *VClusterCommands) produceStopDBInstructions(options *VStopDatabaseOpt
 
 	// when password is specified, we will use username/password to call https endpoints
 	usePassword := false
-	if options.Password != nil {
+	if options.hasPassword() {
 		usePassword = true
 		err := options.validateUserName(vcc.Log)
 		if err != nil {
@@ -198,6 +227,20 @@ func (vcc *VClusterCommands) produceStopDBInstructions(options *VStopDatabaseOpt
 	}
 	instructions = append(instructions, &httpsGetUpNodesOp)
 
+	if options.isGracefulDrain() {
+		// the connections are managed through a local database connection,
+		// which always requires a user name
+		err = options.validateUserName(vcc.Log)
+		if err != nil {
+			return instructions, err
+		}
+		drainOps, err := options.produceGracefulDrainOps(usePassword)
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, drainOps...)
+	}
+
 	if options.IsEon {
 		httpsSyncCatalogOp, e := makeHTTPSSyncCatalogOpWithoutHosts(usePassword, options.UserName, options.Password, StopDBSyncCat)
 		if e != nil {
@@ -228,6 +271,34 @@ func (vcc *VClusterCommands) produceStopDBInstructions(options *VStopDatabaseOpt
 	return instructions, nil
 }
 
+// isGracefulDrain returns true if the sessions are drained before stopping the
+// nodes. validateEonOptions checks that the database is in Eon mode, and that
+// the drain time is positive.
+func (options *VStopDatabaseOptions) isGracefulDrain() bool {
+	return options.GracefulDrain
+}
+
+// produceGracefulDrainOps produces the ops that pause new connections to the
+// nodes, and wait for the user sessions to end
+func (options *VStopDatabaseOptions) produceGracefulDrainOps(usePassword bool) ([]clusterOp, error) {
+	password, err := options.getRequestDataPassword()
+	if err != nil {
+		return nil, err
+	}
+	nmaManageConnectionsOp, err := makeNMAManageConnectionsOp(options.Hosts,
+		options.UserName, options.DBName, options.SandboxName, "" /* all subclusters */, "",
+		ActionPause, password, usePassword)
+	if err != nil {
+		return nil, err
+	}
+	httpsPollActiveSessionsOp, err := makeHTTPSPollActiveSessionsOp(options.Hosts, usePassword,
+		options.UserName, options.Password, options.SandboxName, options.getDrainSeconds())
+	if err != nil {
+		return nil, err
+	}
+	return []clusterOp{&nmaManageConnectionsOp, &httpsPollActiveSessionsOp}, nil
+}
+
 // checkStopDBRequirements validates any stop_db requirements. It will
 // return an error if a requirement isn't met.
 func (options *VStopDatabaseOptions) checkStopDBRequirements(vdb *VCoordinationDatabase) error {
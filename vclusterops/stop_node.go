@@ -21,6 +21,8 @@ import (
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 // VStopNodeOptions represents the available options for VStopNode.
@@ -28,6 +30,10 @@ type VStopNodeOptions struct {
 	DatabaseOptions
 	// Hosts to stop
 	StopHosts []string
+	// Whether to remove the stopped nodes from the spread configuration
+	// once they are down. This needs at least one UP node to remain in
+	// the same sandbox or main cluster.
+	EvictFromSpread bool
 }
 
 func VStopNodeOptionsFactory() VStopNodeOptions {
@@ -61,7 +67,7 @@ func (options *VStopNodeOptions) validateParseOptions(logger vlog.Printer) error
 
 // analyzeOptions will modify some options based on what is chosen
 func (options *VStopNodeOptions) analyzeOptions() (err error) {
-	options.StopHosts, err = util.ResolveRawHostsToAddresses(options.StopHosts, options.IPv6)
+	options.StopHosts, err = options.resolveRawHosts(options.StopHosts)
 	if err != nil {
 		return err
 	}
@@ -69,14 +75,14 @@ func (options *VStopNodeOptions) analyzeOptions() (err error) {
 	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
 	// resolve RawHosts to be IP addresses
 	if len(options.RawHosts) > 0 {
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
 		options.normalizePaths()
 	}
 
-	return nil
+	return options.TargetSelector.resolveExcludedHosts(&options.DatabaseOptions)
 }
 
 func (options *VStopNodeOptions) validateAnalyzeOptions(logger vlog.Printer) error {
@@ -105,6 +111,15 @@ func (vcc VClusterCommands) VStopNode(options *VStopNodeOptions) error {
 
 	options.completeVDBSetting(&vdb)
 
+	// only stop the hosts that satisfy the target selector, the hosts of the
+	// caller are restored once the command returns
+	stopHosts := options.StopHosts
+	defer func() { options.StopHosts = stopHosts }()
+	options.StopHosts = options.TargetSelector.filterHosts(&vdb, options.StopHosts)
+	if len(options.StopHosts) == 0 {
+		return fmt.Errorf("none of the hosts to stop is selected by the target selector")
+	}
+
 	// stop_node is aborted if requirements are not met.
 	// Here we check whether the nodes to be stopped already exist
 	err = checkStopNodeRequirements(&vdb, options.StopHosts)
@@ -117,11 +132,11 @@ func (vcc VClusterCommands) VStopNode(options *VStopNodeOptions) error {
 		return fmt.Errorf("fail to produce stop node instructions, %w", err)
 	}
 
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
-	if runError := clusterOpEngine.run(vcc.Log); runError != nil {
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	if runError := clusterOpEngine.run(vcc.Context(), vcc.Log); runError != nil {
 		return fmt.Errorf("fail to complete stop node operation, %w", runError)
 	}
+	options.saveStartupCommands(clusterOpEngine.execContext)
 	return nil
 }
 
@@ -151,8 +166,10 @@ func (options *VStopNodeOptions) completeVDBSetting(vdb *VCoordinationDatabase)
 //
 // The generated instructions will later perform the following operations necessary
 // for a successful stop_node:
+//   - Save the startup commands of the nodes
 //   - Stop nodes
 //   - Poll node state down
+//   - Remove the stopped nodes from spread (optional)
 func (vcc VClusterCommands) produceStopNodeInstructions(vdb *VCoordinationDatabase,
 	options *VStopNodeOptions) ([]clusterOp, error) {
 	var instructions []clusterOp
@@ -166,6 +183,17 @@ func (vcc VClusterCommands) produceStopNodeInstructions(vdb *VCoordinationDataba
 		stopHostNodeNameMap[vnode.Name] = h
 	}
 
+	// save the startup commands of the nodes so that they can be started later
+	// even if no UP node remains to serve the startup commands
+	sandbox, err := getStopNodesSandbox(stopHostNodeMap)
+	if err != nil {
+		return instructions, err
+	}
+	httpsStartUpCommandOp, err := makeHTTPSStartUpCommandOpBeforeStopNodes(usePassword, username, password, vdb, sandbox)
+	if err != nil {
+		return instructions, err
+	}
+
 	httpsStopNodeOp, err := makeHTTPSStopInputNodesOp(stopHostNodeNameMap, usePassword, username, password, nil)
 	if err != nil {
 		return instructions, err
@@ -179,8 +207,64 @@ func (vcc VClusterCommands) produceStopNodeInstructions(vdb *VCoordinationDataba
 	}
 
 	instructions = append(instructions,
+		&httpsStartUpCommandOp,
 		&httpsStopNodeOp,
 		&httpsPollNodesDown,
 	)
+
+	if options.EvictFromSpread {
+		initiatorHost, err := getSpreadEvictionInitiator(vdb, options.StopHosts, sandbox)
+		if err != nil {
+			return instructions, err
+		}
+		httpsSpreadRemoveNodeOp, err := makeHTTPSSpreadRemoveNodeOp(options.StopHosts, []string{initiatorHost},
+			usePassword, username, password, stopHostNodeMap)
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, &httpsSpreadRemoveNodeOp)
+	}
 	return instructions, nil
 }
+
+// getStopNodesSandbox returns the sandbox of the nodes to stop, which must all
+// be in the same sandbox, or all in the main cluster, as the startup commands
+// and the spread eviction are served by the cluster of the nodes
+func getStopNodesSandbox(stopHostNodeMap vHostNodeMap) (string, error) {
+	hosts := maps.Keys(stopHostNodeMap)
+	slices.Sort(hosts)
+	var sandbox string
+	for i, host := range hosts {
+		vnode := stopHostNodeMap[host]
+		if i > 0 && vnode.Sandbox != sandbox {
+			return "", fmt.Errorf("cannot stop nodes of different sandboxes in one command, hosts %s and %s are in %s and %s",
+				hosts[0], host, clusterDisplayName(sandbox), clusterDisplayName(vnode.Sandbox))
+		}
+		sandbox = vnode.Sandbox
+	}
+	return sandbox, nil
+}
+
+// getSpreadEvictionInitiator returns an UP host, in the given sandbox, that is
+// not being stopped. Primary nodes are preferred.
+func getSpreadEvictionInitiator(vdb *VCoordinationDatabase, hostsToStop []string, sandbox string) (string, error) {
+	var secondaryHost string
+	for _, h := range vdb.HostList {
+		vnode, ok := vdb.HostNodeMap[h]
+		if !ok || vnode.State != util.NodeUpState || vnode.Sandbox != sandbox ||
+			slices.Contains(hostsToStop, h) {
+			continue
+		}
+		if vnode.IsPrimary {
+			return h, nil
+		}
+		if secondaryHost == "" {
+			secondaryHost = h
+		}
+	}
+	if secondaryHost == "" {
+		return "", fmt.Errorf("cannot evict the nodes from spread: no UP node would remain after stopping %s",
+			strings.Join(hostsToStop, ","))
+	}
+	return secondaryHost, nil
+}
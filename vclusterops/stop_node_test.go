@@ -0,0 +1,68 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+func TestGetSpreadEvictionInitiator(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostList = []string{"192.168.1.101", "192.168.1.102", "192.168.1.103", "192.168.1.104"}
+	vdb.HostNodeMap = vHostNodeMap{
+		"192.168.1.101": {Name: "v_db_node0001", State: util.NodeUpState, IsPrimary: true},
+		"192.168.1.102": {Name: "v_db_node0002", State: util.NodeUpState},
+		"192.168.1.103": {Name: "v_db_node0003", State: util.NodeUpState, IsPrimary: true},
+		"192.168.1.104": {Name: "v_db_node0004", State: util.NodeUpState, Sandbox: "sand"},
+	}
+
+	// a primary node that is not being stopped is preferred
+	host, err := getSpreadEvictionInitiator(&vdb, []string{"192.168.1.101"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.103", host)
+
+	// fall back to a secondary node
+	host, err = getSpreadEvictionInitiator(&vdb, []string{"192.168.1.101", "192.168.1.103"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.102", host)
+
+	// down nodes and nodes from other sandboxes are never picked
+	vdb.HostNodeMap["192.168.1.102"].State = util.NodeDownState
+	_, err = getSpreadEvictionInitiator(&vdb, []string{"192.168.1.101", "192.168.1.103"}, "")
+	assert.ErrorContains(t, err, "no UP node would remain")
+
+	host, err = getSpreadEvictionInitiator(&vdb, []string{}, "sand")
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.104", host)
+}
+
+func TestGetStopNodesSandbox(t *testing.T) {
+	stopHostNodeMap := vHostNodeMap{
+		"192.168.1.101": {Address: "192.168.1.101", Sandbox: "sand1"},
+		"192.168.1.102": {Address: "192.168.1.102", Sandbox: "sand1"},
+	}
+	sandbox, err := getStopNodesSandbox(stopHostNodeMap)
+	assert.NoError(t, err)
+	assert.Equal(t, "sand1", sandbox)
+
+	// negative: the nodes are in a sandbox and in the main cluster
+	stopHostNodeMap["192.168.1.103"] = &VCoordinationNode{Address: "192.168.1.103"}
+	_, err = getStopNodesSandbox(stopHostNodeMap)
+	assert.ErrorContains(t, err, "hosts 192.168.1.101 and 192.168.1.103 are in sandbox sand1 and the main cluster")
+}
@@ -101,7 +101,7 @@ func (options *VStopSubclusterOptions) validateParseOptions(log vlog.Printer) er
 func (options *VStopSubclusterOptions) analyzeOptions() (err error) {
 	// resolve RawHosts to be IP addresses
 	if len(options.RawHosts) > 0 {
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -132,20 +132,36 @@ func (vcc VClusterCommands) VStopSubcluster(options *VStopSubclusterOptions) err
 		return err
 	}
 
+	// nothing to do when no node of the subcluster is UP, so that scaling in
+	// can safely be retried
+	vdb := makeVCoordinationDatabase()
+	err = vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, AnySandbox)
+	if err != nil {
+		return err
+	}
+	_, upNodeCount, err := countSubclusterNodes(&vdb, options.SCName)
+	if err != nil {
+		return err
+	}
+	if upNodeCount == 0 {
+		vcc.Log.PrintInfo("No node of subcluster %s is UP, the subcluster is already stopped", options.SCName)
+		return nil
+	}
+
 	instructions, err := vcc.produceStopSCInstructions(options)
 	if err != nil {
 		return fmt.Errorf("fail to production instructions: %w", err)
 	}
 
 	// Create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("failed to stop subcluster %s: %w", options.SCName, runError)
 	}
+	options.saveStartupCommands(clusterOpEngine.execContext)
 
 	return nil
 }
@@ -156,6 +172,7 @@ func (vcc VClusterCommands) VStopSubcluster(options *VStopSubclusterOptions) err
 // The generated instructions will later perform the following operations necessary
 // for a successful stop_subcluster:
 //   - Get up nodes in the target subcluster through https call
+//   - Save the startup commands of the nodes in the target subcluster
 //   - Sync catalog through the first up node in the target subcluster
 //   - Stop subcluster through the first up node in the target subcluster
 //   - Check if there are any running nodes in the target subcluster
@@ -164,7 +181,7 @@ func (vcc *VClusterCommands) produceStopSCInstructions(options *VStopSubclusterO
 
 	// when password is specified, we will use username/password to call https endpoints
 	usePassword := false
-	if options.Password != nil {
+	if options.hasPassword() {
 		usePassword = true
 		err := options.validateUserName(vcc.Log)
 		if err != nil {
@@ -178,6 +195,11 @@ func (vcc *VClusterCommands) produceStopSCInstructions(options *VStopSubclusterO
 		return instructions, err
 	}
 
+	httpsStartUpCommandOp, err := makeHTTPSStartUpCommandOpBeforeStopSC(usePassword, options.UserName, options.Password)
+	if err != nil {
+		return instructions, err
+	}
+
 	httpsSyncCatalogOp, err := makeHTTPSSyncCatalogOpWithoutHosts(usePassword, options.UserName, options.Password, StopSCSyncCat)
 	if err != nil {
 		return instructions, err
@@ -196,6 +218,7 @@ func (vcc *VClusterCommands) produceStopSCInstructions(options *VStopSubclusterO
 
 	instructions = append(instructions,
 		&httpsGetUpNodesOp,
+		&httpsStartUpCommandOp,
 		&httpsSyncCatalogOp,
 		&httpsStopSCOp,
 		&httpsCheckDBRunningOp,
@@ -0,0 +1,169 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+const (
+	defaultTailLogLines               = 100
+	defaultTailLogPollIntervalSeconds = 1
+)
+
+// VTailLogOptions are the options of VTailLog. RawHosts must contain
+// exactly one entry, the host to read the log from.
+type VTailLogOptions struct {
+	DatabaseOptions
+	// full path of the log on the host, e.g., the vertica.log in the catalog
+	// path, or the dbLog returned in NodeStartResult.DBLogPath. It is not the
+	// log of vcluster, which is DatabaseOptions.LogPath.
+	ServerLogPath string
+	// number of lines to read from the end of the log
+	Lines int
+	// keep reading the lines appended to the log, until the context of
+	// VClusterCommands is canceled
+	Follow bool
+	// seconds between two reads in follow mode
+	PollIntervalSeconds int
+}
+
+func VTailLogOptionsFactory() VTailLogOptions {
+	options := VTailLogOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VTailLogOptions) setDefaultValues() {
+	options.DatabaseOptions.setDefaultValues()
+	options.Lines = defaultTailLogLines
+	options.PollIntervalSeconds = defaultTailLogPollIntervalSeconds
+}
+
+func (options *VTailLogOptions) validateParseOptions(logger vlog.Printer) error {
+	logger = logger.WithName(commandTailLog)
+	options.resetReport()
+	if len(options.RawHosts) != 1 {
+		return fmt.Errorf("must specify exactly one host to read the log from")
+	}
+	logger.Info("tail log options", "server log path", options.ServerLogPath, "lines", options.Lines,
+		"follow", options.Follow)
+	err := util.ValidateRequiredAbsPath(options.ServerLogPath, "server log path")
+	if err != nil {
+		return err
+	}
+	if options.Lines < 0 {
+		return fmt.Errorf("the number of lines must not be negative, got %d", options.Lines)
+	}
+	if options.Follow && options.PollIntervalSeconds <= 0 {
+		return fmt.Errorf("the poll interval must be positive, got %d", options.PollIntervalSeconds)
+	}
+	return nil
+}
+
+func (options *VTailLogOptions) analyzeOptions() (err error) {
+	// resolve RawHosts to be IP addresses
+	options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+	return err
+}
+
+func (options *VTailLogOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// VTailLog sends the last lines of a log on a host to the lines channel, through
+// the NMA. In follow mode, it keeps sending the lines appended to the log until
+// the context of VClusterCommands is canceled, so the caller can watch a node
+// start in real time. VTailLog closes the lines channel when it returns.
+func (vcc VClusterCommands) VTailLog(options *VTailLogOptions, lines chan<- string) error {
+	defer close(lines)
+
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+	host := options.Hosts[0]
+
+	// a negative offset reads the last lines
+	offset := int64(-1)
+	var pending string
+	for {
+		// the report only holds the latest read
+		options.resetReport()
+		var result logReadResult
+		nmaReadLogOp := makeNMAReadLogOp(host, options.ServerLogPath, offset, options.Lines, &result)
+		clusterOpEngine := options.makeClusterOpEngine([]clusterOp{&nmaReadLogOp})
+		err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+		if err != nil {
+			return fmt.Errorf("fail to read log %s on host %s: %w", options.ServerLogPath, host, err)
+		}
+
+		if offset >= 0 && result.NextOffset < offset {
+			// the log was rotated or truncated, the content is from the start of the new log
+			vcc.Log.Info("log was rotated", "path", options.ServerLogPath, "host", host)
+			pending = ""
+		}
+		offset = result.NextOffset
+
+		var newLines []string
+		newLines, pending = splitLogLines(pending, result.Content)
+		for _, line := range newLines {
+			select {
+			case lines <- line:
+			case <-vcc.Context().Done():
+				return nil
+			}
+		}
+
+		if !options.Follow {
+			// the last line may not end with a newline yet
+			if pending != "" {
+				select {
+				case lines <- pending:
+				case <-vcc.Context().Done():
+				}
+			}
+			return nil
+		}
+
+		select {
+		case <-time.After(time.Duration(options.PollIntervalSeconds) * time.Second):
+		case <-vcc.Context().Done():
+			return nil
+		}
+	}
+}
+
+// splitLogLines splits the content read from a log into complete lines. The
+// incomplete last line is returned as the pending content for the next read.
+func splitLogLines(pending, content string) (lines []string, rest string) {
+	content = pending + content
+	lastNewline := strings.LastIndex(content, "\n")
+	if lastNewline < 0 {
+		return nil, content
+	}
+	lines = strings.Split(content[:lastNewline], "\n")
+	return lines, content[lastNewline+1:]
+}
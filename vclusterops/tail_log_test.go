@@ -0,0 +1,86 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestSplitLogLines(t *testing.T) {
+	lines, rest := splitLogLines("", "line 1\nline 2\nline")
+	assert.Equal(t, []string{"line 1", "line 2"}, lines)
+	assert.Equal(t, "line", rest)
+
+	// the pending content completes the first line
+	lines, rest = splitLogLines(rest, " 3\n")
+	assert.Equal(t, []string{"line 3"}, lines)
+	assert.Empty(t, rest)
+
+	// no complete line yet
+	lines, rest = splitLogLines("", "partial")
+	assert.Empty(t, lines)
+	assert.Equal(t, "partial", rest)
+}
+
+func TestTailLogOptions(t *testing.T) {
+	options := VTailLogOptionsFactory()
+	options.RawHosts = []string{"192.168.1.101"}
+	options.ServerLogPath = "/data/test_db/v_test_db_node0001_catalog/vertica.log"
+	assert.NoError(t, options.validateParseOptions(vlog.Printer{}))
+
+	// negative: more than one host
+	options.RawHosts = []string{"192.168.1.101", "192.168.1.102"}
+	assert.ErrorContains(t, options.validateParseOptions(vlog.Printer{}), "exactly one host")
+	options.RawHosts = []string{"192.168.1.101"}
+
+	// negative: relative log path
+	options.ServerLogPath = "vertica.log"
+	assert.Error(t, options.validateParseOptions(vlog.Printer{}))
+	options.ServerLogPath = "/data/test_db/dbLog"
+
+	// negative: no poll interval in follow mode
+	options.Follow = true
+	options.PollIntervalSeconds = 0
+	assert.ErrorContains(t, options.validateParseOptions(vlog.Printer{}), "poll interval")
+}
+
+func TestReadLogOpRequest(t *testing.T) {
+	const host = "192.168.1.101"
+	var result logReadResult
+
+	// tail the last lines
+	op := makeNMAReadLogOp(host, "/data/test_db/dbLog", -1, 50, &result)
+	op.setupBasicInfo()
+	assert.NoError(t, op.setupClusterHTTPRequest(op.hosts))
+	assert.Equal(t, map[string]string{"path": "/data/test_db/dbLog", "tail_lines": "50"},
+		op.clusterHTTPRequest.RequestCollection[host].QueryParams)
+
+	// read from an offset, and parse the result
+	op = makeNMAReadLogOp(host, "/data/test_db/dbLog", 1024, 50, &result)
+	op.setupBasicInfo()
+	assert.NoError(t, op.setupClusterHTTPRequest(op.hosts))
+	assert.Equal(t, "1024", op.clusterHTTPRequest.RequestCollection[host].QueryParams["offset"])
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: SUCCESS, statusCode: SuccessCode, host: host,
+			content: `{"content": "line 1\n", "next_offset": 1031}`},
+	}
+	assert.NoError(t, op.processResult(nil))
+	assert.Equal(t, "line 1\n", result.Content)
+	assert.Equal(t, int64(1031), result.NextOffset)
+}
@@ -0,0 +1,84 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// TargetSelector narrows down the nodes that a command acts on. The
+// conditions are combined, so a node must satisfy all of them to be
+// selected. The zero value selects every node.
+type TargetSelector struct {
+	// select the primary nodes only
+	PrimaryOnly bool
+	// select the secondary nodes only
+	SecondaryOnly bool
+	// select the nodes of this subcluster only
+	Subcluster string
+	// never select these hosts, expected to be IP addresses or hostnames
+	ExcludedHosts []string
+}
+
+func (s *TargetSelector) validate() error {
+	if s.PrimaryOnly && s.SecondaryOnly {
+		return errors.New("cannot select both primary-only and secondary-only target nodes")
+	}
+	return nil
+}
+
+// resolveExcludedHosts resolves the excluded hosts to IP addresses
+func (s *TargetSelector) resolveExcludedHosts(opt *DatabaseOptions) (err error) {
+	if len(s.ExcludedHosts) == 0 {
+		return nil
+	}
+	s.ExcludedHosts, err = opt.resolveRawHosts(s.ExcludedHosts)
+	return err
+}
+
+// isSelected returns true if a node with the given properties is selected
+func (s *TargetSelector) isSelected(address, subcluster string, isPrimary bool) bool {
+	if s.PrimaryOnly && !isPrimary {
+		return false
+	}
+	if s.SecondaryOnly && isPrimary {
+		return false
+	}
+	if s.Subcluster != "" && subcluster != s.Subcluster {
+		return false
+	}
+	return !util.StringInArray(address, s.ExcludedHosts)
+}
+
+func (s *TargetSelector) isNodeSelected(vnode *VCoordinationNode) bool {
+	return s.isSelected(vnode.Address, vnode.Subcluster, vnode.IsPrimary)
+}
+
+// filterHosts returns the hosts of the database that are selected. The hosts
+// that are not in the database are kept as they are, so that the command can
+// report them in its own way.
+func (s *TargetSelector) filterHosts(vdb *VCoordinationDatabase, hosts []string) []string {
+	var selected []string
+	for _, host := range hosts {
+		vnode, ok := vdb.HostNodeMap[host]
+		if !ok || s.isNodeSelected(vnode) {
+			selected = append(selected, host)
+		}
+	}
+	return selected
+}
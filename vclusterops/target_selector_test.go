@@ -0,0 +1,71 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetSelector(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	vdb.HostNodeMap["192.168.1.101"] = &VCoordinationNode{Address: "192.168.1.101", Subcluster: "sc1", IsPrimary: true}
+	vdb.HostNodeMap["192.168.1.102"] = &VCoordinationNode{Address: "192.168.1.102", Subcluster: "sc1", IsPrimary: true}
+	vdb.HostNodeMap["192.168.1.103"] = &VCoordinationNode{Address: "192.168.1.103", Subcluster: "sc2"}
+	hosts := []string{"192.168.1.101", "192.168.1.102", "192.168.1.103", "192.168.1.104"}
+
+	// the zero value selects every host
+	selector := TargetSelector{}
+	assert.NoError(t, selector.validate())
+	assert.Equal(t, hosts, selector.filterHosts(&vdb, hosts))
+
+	// hosts not in the database are always kept
+	selector.PrimaryOnly = true
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.102", "192.168.1.104"}, selector.filterHosts(&vdb, hosts))
+
+	selector.ExcludedHosts = []string{"192.168.1.102"}
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.104"}, selector.filterHosts(&vdb, hosts))
+
+	selector = TargetSelector{SecondaryOnly: true}
+	assert.Equal(t, []string{"192.168.1.103", "192.168.1.104"}, selector.filterHosts(&vdb, hosts))
+
+	selector = TargetSelector{Subcluster: "sc1"}
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.102", "192.168.1.104"}, selector.filterHosts(&vdb, hosts))
+
+	// conflicting roles
+	selector = TargetSelector{PrimaryOnly: true, SecondaryOnly: true}
+	assert.Error(t, selector.validate())
+}
+
+func TestFilterNodesByTargetSelector(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	vdb.HostNodeMap["192.168.1.101"] = &VCoordinationNode{Name: "v_test_db_node0001", Address: "192.168.1.101", IsPrimary: true}
+	vdb.HostNodeMap["192.168.1.102"] = &VCoordinationNode{Name: "v_test_db_node0002", Address: "192.168.1.102"}
+	hostNodeNameMap := map[string]string{"v_test_db_node0001": "192.168.1.101", "v_test_db_node0002": "192.168.1.102"}
+	options := VStartNodesOptionsFactory()
+	options.Nodes = map[string]string{"v_test_db_node0001": "192.168.1.101", "v_test_db_node0002": "192.168.1.102",
+		"v_test_db_node0003": "192.168.1.103"}
+	options.TargetSelector = TargetSelector{PrimaryOnly: true}
+
+	// the nodes not in the catalog are kept, and the nodes of the caller are not changed
+	selectedNodes := options.filterNodesByTargetSelector(&vdb, hostNodeNameMap)
+	assert.Equal(t, map[string]string{"v_test_db_node0001": "192.168.1.101", "v_test_db_node0003": "192.168.1.103"},
+		selectedNodes)
+	assert.Len(t, options.Nodes, 3)
+}
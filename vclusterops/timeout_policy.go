@@ -0,0 +1,66 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// TimeoutPolicy limits how long the requests, the ops, and the whole run of a
+// command can take. The zero value keeps the defaults of the ops, and puts no
+// limit on the ops and the run.
+type TimeoutPolicy struct {
+	// timeout of each HTTP request whose op does not set its own timeout.
+	// 0 keeps the default of 300 seconds.
+	RequestTimeout time.Duration
+	// timeout of each op, including its retries and polling, 0 for no limit
+	OpTimeout time.Duration
+	// timeouts of specific ops keyed by the op name, e.g., "NMALoadRemoteCatalogOp",
+	// which override OpTimeout
+	OpTimeouts map[string]time.Duration
+	// timeout of all ops run by a command, 0 for no limit
+	EngineTimeout time.Duration
+}
+
+// getRequestTimeoutSeconds returns the request timeout rounded up to seconds,
+// which is the unit of the timeout of the requests
+func (policy *TimeoutPolicy) getRequestTimeoutSeconds() int {
+	if policy == nil || policy.RequestTimeout <= 0 {
+		return 0
+	}
+	return int(math.Ceil(policy.RequestTimeout.Seconds()))
+}
+
+func (policy *TimeoutPolicy) getOpTimeout(opName string) time.Duration {
+	if policy == nil {
+		return 0
+	}
+	if opTimeout, ok := policy.OpTimeouts[opName]; ok {
+		return opTimeout
+	}
+	return policy.OpTimeout
+}
+
+// withTimeout returns a context that expires after the timeout, or ctx itself
+// if the timeout is not positive
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,80 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// mockBlockingOp waits until its context is done
+type mockBlockingOp struct {
+	mockOp
+}
+
+func (m *mockBlockingOp) execute(execContext *opEngineExecContext) error {
+	<-execContext.ctx.Done()
+	return execContext.ctx.Err()
+}
+
+func TestTimeoutPolicy(t *testing.T) {
+	policy := TimeoutPolicy{
+		RequestTimeout: 1500 * time.Millisecond,
+		OpTimeout:      time.Minute,
+		OpTimeouts:     map[string]time.Duration{"slow-op": time.Hour},
+	}
+	// the request timeout is rounded up to seconds
+	assert.Equal(t, 2, policy.getRequestTimeoutSeconds())
+	assert.Equal(t, time.Hour, policy.getOpTimeout("slow-op"))
+	assert.Equal(t, time.Minute, policy.getOpTimeout("other-op"))
+	var nilPolicy *TimeoutPolicy
+	assert.Zero(t, nilPolicy.getRequestTimeoutSeconds())
+	assert.Zero(t, nilPolicy.getOpTimeout("other-op"))
+
+	// the dispatcher sets the timeout of the requests without their own
+	adpt := mockFlakyAdapter{host: "host1"}
+	dispatcher := makeHTTPRequestDispatcher(context.Background(), vlog.Printer{})
+	dispatcher.pool = makeAdapterPool(vlog.Printer{})
+	dispatcher.pool.connections = map[string]adapter{"host1": &adpt}
+	dispatcher.requestTimeout = policy.getRequestTimeoutSeconds()
+	httpRequest := clusterHTTPRequest{RequestCollection: map[string]hostHTTPRequest{"host1": {}}}
+	assert.NoError(t, dispatcher.sendRequest(&httpRequest, nil))
+	assert.Equal(t, 2, adpt.lastRequest.Timeout)
+	httpRequest = clusterHTTPRequest{RequestCollection: map[string]hostHTTPRequest{"host1": {Timeout: 30}}}
+	assert.NoError(t, dispatcher.sendRequest(&httpRequest, nil))
+	assert.Equal(t, 30, adpt.lastRequest.Timeout)
+
+	// an op that runs longer than its timeout is aborted
+	op := mockBlockingOp{mockOp: makeMockOp(false)}
+	options := DatabaseOptionsFactory()
+	options.TimeoutPolicy.OpTimeout = 10 * time.Millisecond
+	opEngn := options.makeClusterOpEngine([]clusterOp{&op})
+	err := opEngn.run(context.Background(), vlog.Printer{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	// the context of the run is restored after the op
+	assert.NoError(t, opEngn.execContext.ctx.Err())
+
+	// so are the remaining ops when the run reaches its timeout
+	options = DatabaseOptionsFactory()
+	options.TimeoutPolicy.EngineTimeout = 10 * time.Millisecond
+	opEngn = options.makeClusterOpEngine([]clusterOp{&op})
+	err = opEngn.run(context.Background(), vlog.Printer{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,145 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// tlsServedCertCheckOp opens a TLS connection to a service on each host, and
+// checks that the service presents the expected certificate with a chain
+// that the expected CA certificates verify. Like clientTLSCheckOp, it does
+// not go through the dispatcher.
+type tlsServedCertCheckOp struct {
+	opBase
+	service string
+	port    int
+	certDER []byte
+	caPool  *x509.CertPool
+	// the chain that each host presented, leaf first
+	hostChains map[string][]*x509.Certificate
+}
+
+// makeTLSServedCertCheckOp creates an op that checks the hosts, or the up
+// hosts if hosts is empty
+func makeTLSServedCertCheckOp(hosts []string, service string, port int,
+	certDER []byte, caPool *x509.CertPool) tlsServedCertCheckOp {
+	op := tlsServedCertCheckOp{}
+	op.name = "TLSServedCertCheckOp"
+	op.description = fmt.Sprintf("Verify certificate served by %s", service)
+	op.hosts = hosts
+	op.service = service
+	op.port = port
+	op.certDER = certDER
+	op.caPool = caPool
+	return op
+}
+
+func (op *tlsServedCertCheckOp) prepare(execContext *opEngineExecContext) error {
+	if len(op.hosts) > 0 {
+		return nil
+	}
+	upHosts, err := execContext.requireUpHosts(op.name)
+	if err != nil {
+		return err
+	}
+	op.hosts = upHosts
+	return nil
+}
+
+// loadCertsIfNeeded does nothing, as the op makes its own connections
+func (op *tlsServedCertCheckOp) loadCertsIfNeeded(_ *httpsCerts, _ bool) error {
+	return nil
+}
+
+func (op *tlsServedCertCheckOp) execute(execContext *opEngineExecContext) error {
+	op.hostChains = make(map[string][]*x509.Certificate)
+	var allErrs error
+	for _, host := range op.hosts {
+		chain, err := op.getServedChain(execContext.ctx, host)
+		if err != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] fail to negotiate TLS with the %s on host %s, details: %w",
+				op.name, op.service, host, err))
+			continue
+		}
+		op.hostChains[host] = chain
+	}
+	if allErrs != nil {
+		return allErrs
+	}
+	return op.processResult(execContext)
+}
+
+func (op *tlsServedCertCheckOp) getServedChain(ctx context.Context, host string) ([]*x509.Certificate, error) {
+	dialer := tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: clientTLSCheckTimeout},
+		// the chain is verified against the expected CA certificates below,
+		// without the host name, as the services are reached by IP address
+		Config: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(op.port)))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	chain := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(chain) == 0 {
+		return nil, errors.New("the service did not present a certificate")
+	}
+	return chain, nil
+}
+
+// verifyChain returns an error if the chain does not start with the expected
+// certificate, or is not verified by the expected CA certificates
+func (op *tlsServedCertCheckOp) verifyChain(chain []*x509.Certificate) error {
+	if !bytes.Equal(chain[0].Raw, op.certDER) {
+		return fmt.Errorf("presented certificate %q instead of the new certificate", chain[0].Subject)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         op.caPool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
+
+func (op *tlsServedCertCheckOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+	for host, chain := range op.hostChains {
+		if err := op.verifyChain(chain); err != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] the %s on host %s does not serve the new certificate chain: %w",
+				op.name, op.service, host, err))
+			continue
+		}
+		op.logger.Info("host served the new certificate chain", "host", host, "service", op.service)
+	}
+	return allErrs
+}
+
+func (op *tlsServedCertCheckOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// TLSVerifyMode is how the commands verify the certificates that the HTTPS
+// service and the node management agents present
+type TLSVerifyMode string
+
+const (
+	// TLSInsecureSkipVerify accepts any certificate, which is the default,
+	// as the certificates generated by the installer are self-signed
+	TLSInsecureSkipVerify TLSVerifyMode = "insecure-skip-verify"
+	// TLSVerifyCA requires a certificate chain trusted by the CA certificates
+	TLSVerifyCA TLSVerifyMode = "verify-ca"
+	// TLSVerifyFull also requires the certificate to be issued to the host
+	TLSVerifyFull TLSVerifyMode = "verify-full"
+)
+
+// TLSVerificationPolicy configures the verification of the server
+// certificates for a command. The CA certificates are the ones of the
+// CertProvider or CaCert of the options, or the system ones when the
+// command authenticates with a password.
+type TLSVerificationPolicy struct {
+	// empty for TLSInsecureSkipVerify
+	Mode TLSVerifyMode
+	// the name that the certificates are issued to, instead of the address
+	// of each host. Only valid with TLSVerifyFull.
+	ServerName string
+	// if not empty, the certificate must have at least one of these DNS
+	// names, IP addresses or URIs, e.g., a SPIFFE ID, in its SANs
+	AllowedSANs []string
+}
+
+// cacheKey identifies the policy in the transport cache
+func (p *TLSVerificationPolicy) cacheKey() string {
+	if p == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s|%s|%s", p.Mode, p.ServerName, strings.Join(p.AllowedSANs, ","))
+}
+
+func (p *TLSVerificationPolicy) validate() error {
+	switch p.Mode {
+	case "", TLSInsecureSkipVerify:
+		if p.ServerName != "" || len(p.AllowedSANs) > 0 {
+			return fmt.Errorf("the server name and the allowed SANs require TLS verify mode %s or %s", TLSVerifyCA, TLSVerifyFull)
+		}
+	case TLSVerifyCA:
+		if p.ServerName != "" {
+			return fmt.Errorf("the server name requires TLS verify mode %s", TLSVerifyFull)
+		}
+	case TLSVerifyFull:
+	default:
+		return fmt.Errorf("invalid TLS verify mode %q, must be one of %s, %s, %s",
+			p.Mode, TLSInsecureSkipVerify, TLSVerifyCA, TLSVerifyFull)
+	}
+	return nil
+}
+
+func (p *TLSVerificationPolicy) skipsVerify() bool {
+	return p == nil || p.Mode == "" || p.Mode == TLSInsecureSkipVerify
+}
+
+// apply makes tlsConfig verify the certificates of host. The standard
+// verification is replaced, since it cannot check the SANs, and the
+// certificates are not verified at all by default.
+func (p *TLSVerificationPolicy) apply(tlsConfig *tls.Config, host string) {
+	//nolint:gosec
+	tlsConfig.InsecureSkipVerify = true
+	if p.skipsVerify() {
+		return
+	}
+	policy := *p
+	rootCAs := tlsConfig.RootCAs
+	tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+		return policy.verify(state.PeerCertificates, rootCAs, host)
+	}
+}
+
+// verify checks the chain that host presented, leaf first. A nil rootCAs
+// is the CA certificates of the system.
+func (p *TLSVerificationPolicy) verify(chain []*x509.Certificate, rootCAs *x509.CertPool, host string) error {
+	if len(chain) == 0 {
+		return fmt.Errorf("host %s did not present a certificate", host)
+	}
+	opts := x509.VerifyOptions{
+		Roots:         rootCAs,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, cert := range chain[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if p.Mode == TLSVerifyFull {
+		opts.DNSName = host
+		if p.ServerName != "" {
+			opts.DNSName = p.ServerName
+		}
+	}
+	leaf := chain[0]
+	if _, err := leaf.Verify(opts); err != nil {
+		return fmt.Errorf("fail to verify the certificate of host %s, details: %w", host, err)
+	}
+	if len(p.AllowedSANs) > 0 && !hasAllowedSAN(leaf, p.AllowedSANs) {
+		return errors.New("the certificate of host " + host + " has none of the allowed SANs " + strings.Join(p.AllowedSANs, ", "))
+	}
+	return nil
+}
+
+func hasAllowedSAN(cert *x509.Certificate, allowedSANs []string) bool {
+	for _, san := range allowedSANs {
+		for _, name := range cert.DNSNames {
+			if strings.EqualFold(name, san) {
+				return true
+			}
+		}
+		for _, ip := range cert.IPAddresses {
+			if ip.Equal(net.ParseIP(san)) {
+				return true
+			}
+		}
+		for _, uri := range cert.URIs {
+			if uri.String() == san {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// makeTestCertChain returns a CA pool and a leaf, signed by the CA, that has the given SANs
+func makeTestCertChain(t *testing.T, dnsNames []string, ips []net.IP, uris []*url.URL) (*x509.CertPool, []*x509.Certificate) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTemplate := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "vertica-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	assert.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "vertica"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     dnsNames,
+		IPAddresses:  ips,
+		URIs:         uris,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, caCert, &key.PublicKey, caKey)
+	assert.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	return pool, []*x509.Certificate{leaf}
+}
+
+func TestTLSVerificationPolicyValidation(t *testing.T) {
+	policy := TLSVerificationPolicy{}
+	assert.NoError(t, policy.validate())
+	policy.Mode = TLSVerifyCA
+	assert.NoError(t, policy.validate())
+	policy.Mode = TLSVerifyFull
+	policy.ServerName = "vertica.example.com"
+	assert.NoError(t, policy.validate())
+
+	policy = TLSVerificationPolicy{Mode: "verify-all"}
+	assert.ErrorContains(t, policy.validate(), "invalid TLS verify mode")
+	policy = TLSVerificationPolicy{Mode: TLSVerifyCA, ServerName: "vertica.example.com"}
+	assert.ErrorContains(t, policy.validate(), "requires TLS verify mode")
+	policy = TLSVerificationPolicy{AllowedSANs: []string{"vertica.example.com"}}
+	assert.ErrorContains(t, policy.validate(), "require TLS verify mode")
+}
+
+func TestTLSVerificationPolicyVerify(t *testing.T) {
+	const host = "192.168.1.101"
+	spiffeID, err := url.Parse("spiffe://example.com/vertica")
+	assert.NoError(t, err)
+	pool, chain := makeTestCertChain(t, []string{"vertica.example.com"}, []net.IP{net.ParseIP(host)}, []*url.URL{spiffeID})
+	otherPool, _ := makeTestCertChain(t, nil, nil, nil)
+
+	// verify-ca only checks the chain
+	policy := TLSVerificationPolicy{Mode: TLSVerifyCA}
+	assert.NoError(t, policy.verify(chain, pool, "192.168.1.102"))
+	assert.ErrorContains(t, policy.verify(chain, otherPool, host), "fail to verify the certificate of host")
+	assert.ErrorContains(t, policy.verify(nil, pool, host), "did not present a certificate")
+
+	// verify-full also checks the host, or the server name
+	policy = TLSVerificationPolicy{Mode: TLSVerifyFull}
+	assert.NoError(t, policy.verify(chain, pool, host))
+	assert.Error(t, policy.verify(chain, pool, "192.168.1.102"))
+	policy.ServerName = "vertica.example.com"
+	assert.NoError(t, policy.verify(chain, pool, "192.168.1.102"))
+	policy.ServerName = "other.example.com"
+	assert.Error(t, policy.verify(chain, pool, host))
+
+	// the allowed SANs match a DNS name, an IP address, or a URI
+	policy = TLSVerificationPolicy{Mode: TLSVerifyCA}
+	for _, san := range []string{"VERTICA.example.com", host, spiffeID.String()} {
+		policy.AllowedSANs = []string{"other.example.com", san}
+		assert.NoError(t, policy.verify(chain, pool, host))
+	}
+	policy.AllowedSANs = []string{"other.example.com", "spiffe://example.com/other"}
+	assert.ErrorContains(t, policy.verify(chain, pool, host), "has none of the allowed SANs")
+}
+
+func TestTLSVerificationPolicyApply(t *testing.T) {
+	// no policy keeps the certificates unverified
+	var policy *TLSVerificationPolicy
+	tlsConfig := &tls.Config{}
+	policy.apply(tlsConfig, "192.168.1.101")
+	assert.True(t, tlsConfig.InsecureSkipVerify)
+	assert.Nil(t, tlsConfig.VerifyConnection)
+
+	pool, chain := makeTestCertChain(t, nil, []net.IP{net.ParseIP("192.168.1.101")}, nil)
+	policy = &TLSVerificationPolicy{Mode: TLSVerifyFull}
+	tlsConfig = &tls.Config{RootCAs: pool}
+	policy.apply(tlsConfig, "192.168.1.101")
+	assert.NotNil(t, tlsConfig.VerifyConnection)
+	assert.NoError(t, tlsConfig.VerifyConnection(tls.ConnectionState{PeerCertificates: chain}))
+
+	tlsConfig = &tls.Config{RootCAs: pool}
+	policy.apply(tlsConfig, "192.168.1.102")
+	assert.Error(t, tlsConfig.VerifyConnection(tls.ConnectionState{PeerCertificates: chain}))
+}
+
+func TestDispatcherSetsTLSVerification(t *testing.T) {
+	policy := &TLSVerificationPolicy{Mode: TLSVerifyCA}
+	dispatcher := makeHTTPRequestDispatcher(context.Background(), vlog.Printer{})
+	dispatcher.tlsVerification = policy
+	own := &TLSVerificationPolicy{Mode: TLSVerifyFull}
+	httpRequest := clusterHTTPRequest{RequestCollection: map[string]hostHTTPRequest{
+		"192.168.1.101": {},
+		"192.168.1.102": {TLSVerification: own},
+	}}
+	dispatcher.setDefaults(&httpRequest)
+	assert.Equal(t, policy, httpRequest.RequestCollection["192.168.1.101"].TLSVerification)
+	assert.Equal(t, own, httpRequest.RequestCollection["192.168.1.102"].TLSVerification)
+}
@@ -0,0 +1,210 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"golang.org/x/exp/maps"
+)
+
+// TopologyChange is a proposed change of the nodes of a database
+type TopologyChange struct {
+	// hosts of the nodes to remove
+	RemoveHosts []string
+	// a subcluster to add, nil if no subcluster is added
+	AddSubcluster *SimulatedSubcluster
+}
+
+// SimulatedSubcluster describes a subcluster that does not exist yet
+type SimulatedSubcluster struct {
+	Name      string
+	IsPrimary bool
+	NodeCount int
+}
+
+// SubclusterImpact is the predicted layout of a subcluster after a change
+type SubclusterImpact struct {
+	Name      string
+	IsPrimary bool
+	NodeCount int
+	// number of shards that each node subscribes to, for an Eon database
+	ShardsPerNode int
+	// number of nodes that subscribe to each shard, for an Eon database
+	SubscribersPerShard int
+	// number of control nodes, which relay the spread messages of the
+	// other nodes of the subcluster
+	ControlNodeCount int
+}
+
+// TopologyImpact is the predicted state of a database after a topology change
+type TopologyImpact struct {
+	NodeCount        int
+	PrimaryNodeCount int
+	// number of primary nodes that are UP after the change
+	UpPrimaryNodeCount int
+	// the K-safety of the database design, which is the K-safety in the
+	// catalog unless too few primary nodes are left to support it
+	KSafety int
+	// whether more than half of the primary nodes are UP after the change
+	HasQuorum bool
+	// subclusters sorted by name
+	Subclusters []SubclusterImpact
+	// names of the removed nodes that are control nodes. The nodes that
+	// depend on them are assigned to other control nodes.
+	RemovedControlNodes []string
+	// problems that the change is predicted to cause
+	Warnings []string
+}
+
+// SimulateTopologyChange predicts the K-safety, the shard coverage and the
+// distribution of the control nodes of the database after the change. It
+// only works on the database information, and does not contact the cluster.
+// The prediction follows the defaults of the database, e.g., the shards are
+// evenly distributed among the nodes of a subcluster, so the actual layout
+// may differ if they were changed.
+func (vdb *VCoordinationDatabase) SimulateTopologyChange(change *TopologyChange) (impact TopologyImpact, err error) {
+	_, missingHosts := vdb.containNodes(change.RemoveHosts)
+	if len(missingHosts) > 0 {
+		return impact, fmt.Errorf("hosts %v are not in database %s", missingHosts, vdb.Name)
+	}
+
+	subclusters := make(map[string]*SubclusterImpact)
+	allControlNodes := true
+	var scsLosingControlNodes []string
+	for _, vnode := range vdb.HostNodeMap {
+		allControlNodes = allControlNodes && vnode.IsControlNode
+		if util.StringInArray(vnode.Address, change.RemoveHosts) {
+			if vnode.IsControlNode {
+				impact.RemovedControlNodes = append(impact.RemovedControlNodes, vnode.Name)
+				scsLosingControlNodes = append(scsLosingControlNodes, vnode.Subcluster)
+			}
+			continue
+		}
+		sc, ok := subclusters[vnode.Subcluster]
+		if !ok {
+			sc = &SubclusterImpact{Name: vnode.Subcluster, IsPrimary: vnode.IsPrimary}
+			subclusters[vnode.Subcluster] = sc
+		}
+		sc.NodeCount++
+		if vnode.IsControlNode {
+			sc.ControlNodeCount++
+		}
+		impact.NodeCount++
+		if vnode.IsPrimary {
+			impact.PrimaryNodeCount++
+			if vnode.State == util.NodeUpState {
+				impact.UpPrimaryNodeCount++
+			}
+		}
+	}
+	sort.Strings(impact.RemovedControlNodes)
+	removedSCs := util.SliceDiff(vdb.getSCNames(), maps.Keys(subclusters))
+	sort.Strings(removedSCs)
+
+	if change.AddSubcluster != nil {
+		err = vdb.simulateAddSubcluster(change.AddSubcluster, subclusters, allControlNodes, &impact)
+		if err != nil {
+			return impact, err
+		}
+	}
+
+	impact.KSafety = getSupportedKSafety(vdb.KSafety, impact.PrimaryNodeCount)
+	impact.HasQuorum = impact.UpPrimaryNodeCount*2 > impact.PrimaryNodeCount
+	for _, sc := range subclusters {
+		vdb.simulateShardLayout(sc, impact.KSafety)
+		impact.Subclusters = append(impact.Subclusters, *sc)
+	}
+	sort.Slice(impact.Subclusters, func(i, j int) bool {
+		return impact.Subclusters[i].Name < impact.Subclusters[j].Name
+	})
+	impact.Warnings = getTopologyWarnings(&impact, vdb.KSafety, removedSCs, scsLosingControlNodes)
+	return impact, nil
+}
+
+func (vdb *VCoordinationDatabase) simulateAddSubcluster(newSC *SimulatedSubcluster,
+	subclusters map[string]*SubclusterImpact, allControlNodes bool, impact *TopologyImpact) error {
+	if newSC.NodeCount <= 0 {
+		return fmt.Errorf("the new subcluster %s must have at least one node", newSC.Name)
+	}
+	if _, ok := subclusters[newSC.Name]; ok || util.StringInArray(newSC.Name, vdb.getSCNames()) {
+		return fmt.Errorf("subcluster %s already exists in database %s", newSC.Name, vdb.Name)
+	}
+	sc := &SubclusterImpact{Name: newSC.Name, IsPrimary: newSC.IsPrimary, NodeCount: newSC.NodeCount}
+	// when all of the nodes are control nodes, the new nodes are too
+	if allControlNodes {
+		sc.ControlNodeCount = newSC.NodeCount
+	}
+	subclusters[newSC.Name] = sc
+	impact.NodeCount += newSC.NodeCount
+	if newSC.IsPrimary {
+		// add_subcluster starts the new nodes, so they count toward the quorum
+		impact.PrimaryNodeCount += newSC.NodeCount
+		impact.UpPrimaryNodeCount += newSC.NodeCount
+	}
+	return nil
+}
+
+// simulateShardLayout spreads the shards evenly among the nodes of the
+// subcluster, with 1+K subscribers for each shard
+func (vdb *VCoordinationDatabase) simulateShardLayout(sc *SubclusterImpact, ksafety int) {
+	if !vdb.IsEon || vdb.NumShards == 0 || sc.NodeCount == 0 {
+		return
+	}
+	sc.SubscribersPerShard = ksafety + 1
+	if sc.SubscribersPerShard > sc.NodeCount {
+		sc.SubscribersPerShard = sc.NodeCount
+	}
+	subscriptions := vdb.NumShards * sc.SubscribersPerShard
+	sc.ShardsPerNode = (subscriptions + sc.NodeCount - 1) / sc.NodeCount
+}
+
+// getSupportedKSafety caps the K-safety in the catalog by the primary node
+// count, as a K-safety of K needs at least 2K+1 primary nodes
+func getSupportedKSafety(catalogKSafety, primaryNodeCount int) int {
+	maxKSafety := ksafeValueZero
+	if primaryNodeCount > 0 {
+		maxKSafety = (primaryNodeCount - 1) / 2
+	}
+	return util.Min(catalogKSafety, maxKSafety)
+}
+
+func getTopologyWarnings(impact *TopologyImpact, catalogKSafety int,
+	removedSCs, scsLosingControlNodes []string) []string {
+	var warnings []string
+	if impact.PrimaryNodeCount == 0 {
+		warnings = append(warnings, "no primary node is left in the database")
+	} else if !impact.HasQuorum {
+		warnings = append(warnings, fmt.Sprintf("only %d of %d primary nodes are UP, the database will lose quorum",
+			impact.UpPrimaryNodeCount, impact.PrimaryNodeCount))
+	}
+	if impact.KSafety < catalogKSafety {
+		warnings = append(warnings, fmt.Sprintf("only %d primary nodes are left, K-safety drops from %d to %d",
+			impact.PrimaryNodeCount, catalogKSafety, impact.KSafety))
+	}
+	for _, scName := range removedSCs {
+		warnings = append(warnings, fmt.Sprintf("all of the nodes of subcluster %s are removed", scName))
+	}
+	for i := range impact.Subclusters {
+		sc := &impact.Subclusters[i]
+		if sc.ControlNodeCount == 0 && util.StringInArray(sc.Name, scsLosingControlNodes) {
+			warnings = append(warnings, fmt.Sprintf("subcluster %s has no control node left", sc.Name))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,141 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+func makeVDBForTopologySimulation() VCoordinationDatabase {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	vdb.Name = "test_db"
+	vdb.IsEon = true
+	vdb.NumShards = 6
+	vdb.KSafety = ksafeValueOne
+	addNode := func(name, address, sc string, isPrimary, isControl bool) {
+		vnode := makeVCoordinationNode()
+		vnode.Name = name
+		vnode.Address = address
+		vnode.Subcluster = sc
+		vnode.IsPrimary = isPrimary
+		vnode.IsControlNode = isControl
+		vnode.State = util.NodeUpState
+		vdb.HostNodeMap[address] = &vnode
+	}
+	addNode("v_test_db_node0001", "192.168.1.101", "default_subcluster", true, true)
+	addNode("v_test_db_node0002", "192.168.1.102", "default_subcluster", true, true)
+	addNode("v_test_db_node0003", "192.168.1.103", "default_subcluster", true, true)
+	addNode("v_test_db_node0004", "192.168.1.104", "sc1", false, true)
+	addNode("v_test_db_node0005", "192.168.1.105", "sc1", false, false)
+	return vdb
+}
+
+func TestSimulateTopologyChange(t *testing.T) {
+	vdb := makeVDBForTopologySimulation()
+
+	// no change
+	impact, err := vdb.SimulateTopologyChange(&TopologyChange{})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, impact.NodeCount)
+	assert.Equal(t, 3, impact.PrimaryNodeCount)
+	assert.Equal(t, ksafeValueOne, impact.KSafety)
+	assert.True(t, impact.HasQuorum)
+	assert.Empty(t, impact.Warnings)
+	assert.Len(t, impact.Subclusters, 2)
+	assert.Equal(t, "default_subcluster", impact.Subclusters[0].Name)
+	assert.Equal(t, 2, impact.Subclusters[0].SubscribersPerShard)
+	assert.Equal(t, 4, impact.Subclusters[0].ShardsPerNode)
+	assert.Equal(t, 6, impact.Subclusters[1].ShardsPerNode)
+
+	// removing a primary node drops the K-safety
+	impact, err = vdb.SimulateTopologyChange(&TopologyChange{RemoveHosts: []string{"192.168.1.103"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, impact.PrimaryNodeCount)
+	assert.Equal(t, ksafeValueZero, impact.KSafety)
+	assert.True(t, impact.HasQuorum)
+	assert.Equal(t, []string{"v_test_db_node0003"}, impact.RemovedControlNodes)
+	assert.Equal(t, 1, impact.Subclusters[0].SubscribersPerShard)
+	assert.Equal(t, 3, impact.Subclusters[0].ShardsPerNode)
+	assert.Len(t, impact.Warnings, 1)
+	assert.Contains(t, impact.Warnings[0], "K-safety drops from 1 to 0")
+
+	// quorum is lost when the remaining primary nodes are mostly down
+	vdb.HostNodeMap["192.168.1.102"].State = util.NodeDownState
+	impact, err = vdb.SimulateTopologyChange(&TopologyChange{RemoveHosts: []string{"192.168.1.103"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, impact.UpPrimaryNodeCount)
+	assert.False(t, impact.HasQuorum)
+	assert.Contains(t, impact.Warnings[0], "lose quorum")
+	vdb.HostNodeMap["192.168.1.102"].State = util.NodeUpState
+
+	// a subcluster that loses its only control node
+	impact, err = vdb.SimulateTopologyChange(&TopologyChange{RemoveHosts: []string{"192.168.1.104"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"subcluster sc1 has no control node left"}, impact.Warnings)
+
+	// a subcluster that loses all of its nodes
+	impact, err = vdb.SimulateTopologyChange(&TopologyChange{RemoveHosts: []string{"192.168.1.104", "192.168.1.105"}})
+	assert.NoError(t, err)
+	assert.Len(t, impact.Subclusters, 1)
+	assert.Equal(t, []string{"all of the nodes of subcluster sc1 are removed"}, impact.Warnings)
+
+	// adding a subcluster
+	impact, err = vdb.SimulateTopologyChange(&TopologyChange{
+		AddSubcluster: &SimulatedSubcluster{Name: "sc2", NodeCount: 4},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 9, impact.NodeCount)
+	assert.Len(t, impact.Subclusters, 3)
+	assert.Equal(t, "sc2", impact.Subclusters[2].Name)
+	assert.Equal(t, 3, impact.Subclusters[2].ShardsPerNode)
+	assert.Equal(t, 0, impact.Subclusters[2].ControlNodeCount)
+
+	// the added primary nodes are UP, and the K-safety stays the one in the catalog
+	impact, err = vdb.SimulateTopologyChange(&TopologyChange{
+		AddSubcluster: &SimulatedSubcluster{Name: "sc2", IsPrimary: true, NodeCount: 3},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, impact.PrimaryNodeCount)
+	assert.Equal(t, 6, impact.UpPrimaryNodeCount)
+	assert.True(t, impact.HasQuorum)
+	assert.Equal(t, ksafeValueOne, impact.KSafety)
+	assert.Empty(t, impact.Warnings)
+
+	// a design marked K-safe 0 is not raised by the primary node count
+	vdb.KSafety = ksafeValueZero
+	impact, err = vdb.SimulateTopologyChange(&TopologyChange{})
+	assert.NoError(t, err)
+	assert.Equal(t, ksafeValueZero, impact.KSafety)
+	assert.Equal(t, 1, impact.Subclusters[0].SubscribersPerShard)
+	assert.Empty(t, impact.Warnings)
+	vdb.KSafety = ksafeValueOne
+
+	// invalid changes
+	_, err = vdb.SimulateTopologyChange(&TopologyChange{RemoveHosts: []string{"192.168.1.200"}})
+	assert.ErrorContains(t, err, "are not in database test_db")
+	_, err = vdb.SimulateTopologyChange(&TopologyChange{
+		AddSubcluster: &SimulatedSubcluster{Name: "sc1", NodeCount: 1},
+	})
+	assert.ErrorContains(t, err, "already exists")
+	_, err = vdb.SimulateTopologyChange(&TopologyChange{
+		AddSubcluster: &SimulatedSubcluster{Name: "sc2"},
+	})
+	assert.ErrorContains(t, err, "at least one node")
+}
@@ -0,0 +1,159 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// trashTimestampLayout is the layout of the timestamp in the names of the trash directories
+const trashTimestampLayout = "20060102T150405Z"
+
+// TrashOptions make a force removal move the existing directories to a trash
+// location, instead of deleting them, so that they can be recovered
+type TrashOptions struct {
+	// the directory on each host that the existing directories are moved to,
+	// empty to delete them
+	TrashLocation string
+	// the hours to keep the directories in the trash location. Older directories
+	// are purged at the next force removal. Zero keeps them until VPurgeTrash is called.
+	TrashRetentionHours int
+	// delete the directories when the NMA of a host cannot move them to the trash
+	// location, instead of failing
+	AllowDeleteFallback bool
+}
+
+func (trash *TrashOptions) validate() error {
+	if trash.TrashLocation == "" {
+		return nil
+	}
+	if trash.TrashRetentionHours < 0 {
+		return fmt.Errorf("trash retention hours must not be negative")
+	}
+	return util.ValidateAbsPath(trash.TrashLocation, "trash location")
+}
+
+// getTrashPath returns the timestamped directory in the trash location that the
+// directories of a force removal are moved to
+func (trash *TrashOptions) getTrashPath(dbName string, now time.Time) string {
+	return filepath.Join(trash.TrashLocation, fmt.Sprintf("%s_%s", dbName, now.UTC().Format(trashTimestampLayout)))
+}
+
+// addTrashInstructions makes the force cleanup of the prepare directories op move the
+// existing directories to the trash, and adds the check that the NMA of each host
+// supports the trash, and the purge of the expired directories in the trash, before
+// it, if the trash location is set. The check is skipped when the directories are
+// allowed to be deleted.
+func (trash *TrashOptions) addTrashInstructions(instructions []clusterOp, dbName string,
+	nmaPrepareDirectoriesOp *nmaPrepareDirectoriesOp) ([]clusterOp, error) {
+	if trash.TrashLocation == "" || !nmaPrepareDirectoriesOp.forceCleanup {
+		return instructions, nil
+	}
+	err := nmaPrepareDirectoriesOp.moveToTrash(trash.getTrashPath(dbName, time.Now()), trash.AllowDeleteFallback)
+	if err != nil {
+		return instructions, err
+	}
+	if !trash.AllowDeleteFallback {
+		nmaCheckTrashSupportOp := makeNMACheckTrashSupportOp(nmaPrepareDirectoriesOp.hosts)
+		instructions = append(instructions, &nmaCheckTrashSupportOp)
+	}
+	if trash.TrashRetentionHours > 0 {
+		nmaPurgeTrashOp, err := makeNMAPurgeTrashOp(nmaPrepareDirectoriesOp.hosts, trash.TrashLocation,
+			trash.TrashRetentionHours, make(map[string][]string))
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, &nmaPurgeTrashOp)
+	}
+	return instructions, nil
+}
+
+type VPurgeTrashOptions struct {
+	/* part 1: basic db info */
+	DatabaseOptions
+
+	/* part 2: purge trash options */
+	// the trash location that a force removal moved the directories to
+	TrashLocation string
+	// only purge the directories that were moved to the trash more than these
+	// hours ago, zero to purge all of them
+	OlderThanHours int
+}
+
+func VPurgeTrashOptionsFactory() VPurgeTrashOptions {
+	options := VPurgeTrashOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+
+	return options
+}
+
+func (options *VPurgeTrashOptions) validateParseOptions(logger vlog.Printer) error {
+	options.resetReport()
+	// the database does not need to exist, so only the hosts are required
+	if len(options.RawHosts) == 0 {
+		return fmt.Errorf("must specify a host or host list")
+	}
+	if options.OlderThanHours < 0 {
+		return fmt.Errorf("the hours to keep the directories in the trash must not be negative")
+	}
+	logger.Info("purge trash options", "trash location", options.TrashLocation, "older than hours", options.OlderThanHours)
+	return util.ValidateRequiredAbsPath(options.TrashLocation, "trash location")
+}
+
+func (options *VPurgeTrashOptions) analyzeOptions() (err error) {
+	// resolve RawHosts to be IP addresses
+	options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+	return err
+}
+
+func (options *VPurgeTrashOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// VPurgeTrash deletes the directories that force removals moved to the trash
+// location on each host. It returns the purged directories of each host.
+func (vcc VClusterCommands) VPurgeTrash(options *VPurgeTrashOptions) (map[string][]string, error) {
+	hostPurgedPaths := make(map[string][]string)
+
+	// validate and analyze options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return hostPurgedPaths, err
+	}
+
+	nmaHealthOp := makeNMAHealthOp(options.Hosts)
+	nmaPurgeTrashOp, err := makeNMAPurgeTrashOp(options.Hosts, options.TrashLocation,
+		options.OlderThanHours, hostPurgedPaths)
+	if err != nil {
+		return hostPurgedPaths, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	clusterOpEngine := options.makeClusterOpEngine([]clusterOp{&nmaHealthOp, &nmaPurgeTrashOp})
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		return hostPurgedPaths, fmt.Errorf("fail to purge the trash: %w", err)
+	}
+	return hostPurgedPaths, nil
+}
@@ -0,0 +1,155 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTrashOptions(t *testing.T) {
+	trash := TrashOptions{}
+	assert.NoError(t, trash.validate())
+
+	trash.TrashLocation = "/data/trash"
+	trash.TrashRetentionHours = 24
+	assert.NoError(t, trash.validate())
+
+	// negative: relative trash location, or negative retention
+	trash.TrashLocation = "data/trash"
+	assert.Error(t, trash.validate())
+	trash.TrashLocation = "/data/trash"
+	trash.TrashRetentionHours = -1
+	assert.ErrorContains(t, trash.validate(), "must not be negative")
+}
+
+func TestAddTrashInstructions(t *testing.T) {
+	const host = "192.168.1.101"
+	hostNodeMap := makeVHostNodeMap()
+	hostNodeMap[host] = &VCoordinationNode{Address: host, CatalogPath: "/data/test_db/v_test_db_node0001_catalog"}
+	trash := TrashOptions{TrashLocation: "/data/trash", TrashRetentionHours: 24}
+
+	// no trash without a force cleanup
+	op, err := makeNMAPrepareDirectoriesOp(hostNodeMap, false, true)
+	assert.NoError(t, err)
+	instructions, err := trash.addTrashInstructions(nil, "test_db", &op)
+	assert.NoError(t, err)
+	assert.Empty(t, instructions)
+	assert.Empty(t, op.trashPath)
+
+	// the support of the trash is checked and the expired directories are purged
+	// before the directories are moved to the trash
+	op, err = makeNMAPrepareDirectoriesOp(hostNodeMap, true, true)
+	assert.NoError(t, err)
+	instructions, err = trash.addTrashInstructions(nil, "test_db", &op)
+	assert.NoError(t, err)
+	assert.Len(t, instructions, 2)
+	assert.Equal(t, "NMACheckTrashSupportOp", instructions[0].getName())
+	assert.Equal(t, "NMAPurgeTrashOp", instructions[1].getName())
+	assert.Regexp(t, `^/data/trash/test_db_\d{8}T\d{6}Z$`, op.trashPath)
+	requestData := prepareDirectoriesRequestData{}
+	assert.NoError(t, json.Unmarshal([]byte(op.hostRequestBodyMap[host]), &requestData))
+	assert.Equal(t, op.trashPath, requestData.TrashPath)
+
+	// the directories are kept in the trash without a retention
+	trash.TrashRetentionHours = 0
+	instructions, err = trash.addTrashInstructions(nil, "test_db", &op)
+	assert.NoError(t, err)
+	assert.Len(t, instructions, 1)
+
+	// the support is not checked when the directories may be deleted
+	trash.AllowDeleteFallback = true
+	instructions, err = trash.addTrashInstructions(nil, "test_db", &op)
+	assert.NoError(t, err)
+	assert.Empty(t, instructions)
+	assert.True(t, op.allowDeleteFallback)
+	trash.AllowDeleteFallback = false
+
+	// negative: the trash is inside a directory to clean up
+	trash.TrashLocation = "/data/test_db/v_test_db_node0001_catalog/trash"
+	_, err = trash.addTrashInstructions(nil, "test_db", &op)
+	assert.ErrorContains(t, err, "must not be nested inside each other")
+
+	now := time.Date(2024, 3, 4, 8, 5, 0, 0, time.UTC)
+	assert.Equal(t, "/data/test_db/v_test_db_node0001_catalog/trash/test_db_20240304T080500Z",
+		trash.getTrashPath("test_db", now))
+}
+
+func TestPurgeTrashOp(t *testing.T) {
+	const host = "192.168.1.101"
+	hostPurgedPaths := make(map[string][]string)
+	op, err := makeNMAPurgeTrashOp([]string{host}, "/data/trash", 0, hostPurgedPaths)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"trash_location": "/data/trash", "older_than_hours": 0}`, op.requestBody)
+	op.setupBasicInfo()
+
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: SUCCESS, statusCode: SuccessCode, host: host,
+			content: `{"purged_paths": ["/data/trash/test_db_20240304T080500Z"]}`},
+	}
+	assert.NoError(t, op.processResult(nil))
+	assert.Equal(t, map[string][]string{host: {"/data/trash/test_db_20240304T080500Z"}}, hostPurgedPaths)
+}
+
+func TestMoveToTrashConfirmation(t *testing.T) {
+	const host = "192.168.1.101"
+	hostNodeMap := makeVHostNodeMap()
+	hostNodeMap[host] = &VCoordinationNode{Address: host, CatalogPath: "/data/test_db/v_test_db_node0001_catalog"}
+	op, err := makeNMAPrepareDirectoriesOp(hostNodeMap, true, true)
+	assert.NoError(t, err)
+	assert.NoError(t, op.moveToTrash("/data/trash/test_db_20240304T080500Z", false))
+	op.setupBasicInfo()
+
+	// the NMA confirms the move
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: SUCCESS, statusCode: SuccessCode, host: host,
+			content: `{"/data/test_db/v_test_db_node0001_catalog": "created", "trash_path": "/data/trash/test_db_20240304T080500Z"}`},
+	}
+	assert.NoError(t, op.processResult(nil))
+
+	// an NMA that does not know the trash does not return the trash path
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: SUCCESS, statusCode: SuccessCode, host: host,
+			content: `{"/data/test_db/v_test_db_node0001_catalog": "created"}`},
+	}
+	assert.ErrorContains(t, op.processResult(nil), "did not confirm that the existing directories were moved")
+
+	// unless the directories are allowed to be deleted
+	op.allowDeleteFallback = true
+	assert.NoError(t, op.processResult(nil))
+}
+
+func TestCheckTrashSupportOp(t *testing.T) {
+	const host = "192.168.1.101"
+	op := makeNMACheckTrashSupportOp([]string{host})
+	op.setupBasicInfo()
+
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: SUCCESS, statusCode: SuccessCode, host: host, content: `{}`},
+	}
+	assert.NoError(t, op.processResult(nil))
+
+	// an old NMA does not have the trash endpoint
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: FAILURE, statusCode: http.StatusNotFound, host: host, err: errors.New("404 Not Found")},
+	}
+	assert.ErrorContains(t, op.processResult(nil), "does not support moving directories to a trash")
+}
@@ -83,7 +83,7 @@ func (options *VUnsandboxOptions) analyzeOptions() (err error) {
 	// we analyze hostnames when it is set in user input, otherwise we use hosts in yaml config
 	if len(options.RawHosts) > 0 {
 		// resolve RawHosts to be IP addresses
-		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
 		if err != nil {
 			return err
 		}
@@ -91,7 +91,7 @@ func (options *VUnsandboxOptions) analyzeOptions() (err error) {
 
 	// resolve SCRawHosts to be IP addresses
 	if len(options.SCRawHosts) > 0 {
-		options.SCHosts, err = util.ResolveRawHostsToAddresses(options.SCRawHosts, options.IPv6)
+		options.SCHosts, err = options.resolveRawHosts(options.SCRawHosts)
 		if err != nil {
 			return err
 		}
@@ -114,7 +114,7 @@ type SubclusterNotSandboxedError struct {
 }
 
 func (e *SubclusterNotSandboxedError) Error() string {
-	return fmt.Sprintf(`cannot unsandbox a regular subcluster [%s]`, e.SCName)
+	return localize(MsgSubclusterNotSandboxed, e.SCName)
 }
 
 // unsandboxPreCheck will build a list of instructions to perform
@@ -197,7 +197,7 @@ func (vcc *VClusterCommands) produceUnsandboxSCInstructions(options *VUnsandboxO
 
 	// when password is specified, we will use username/password to call https endpoints
 	usePassword := false
-	if options.Password != nil {
+	if options.hasPassword() {
 		usePassword = true
 		err := options.validateUserName(vcc.Log)
 		if err != nil {
@@ -320,11 +320,10 @@ func (options *VUnsandboxOptions) runCommand(vcc VClusterCommands) error {
 	}
 
 	// add certs and instructions to the engine
-	certs := httpsCerts{key: options.Key, cert: options.Cert, caCert: options.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
 
 	// run the engine
-	runError := clusterOpEngine.run(vcc.Log)
+	runError := clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if runError != nil {
 		return fmt.Errorf("fail to unsandbox subcluster %s, %w", options.SCName, runError)
 	}
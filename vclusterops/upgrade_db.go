@@ -0,0 +1,187 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// the name of the upgrade step that checks the Vertica binaries on the hosts
+const upgradeCheckVersionsStep = "check_vertica_versions"
+
+// VUpgradeDatabaseOptions are the options of an offline upgrade, which
+// restarts the database with the Vertica binaries that were installed on all
+// of the hosts while it was running
+type VUpgradeDatabaseOptions struct {
+	/* part 1: basic db info */
+	DatabaseOptions
+
+	/* part 2: upgrade options */
+	// the Vertica version that the hosts must have installed, e.g., v24.2.0.
+	// If empty, the hosts only need to have the same version.
+	TargetVersion string
+	// the archive to save a restore point to before the database is stopped,
+	// none if empty. Eon mode only.
+	RestorePointArchive string
+	// timeout for polling the states of the nodes when the database starts
+	StatePollingTimeout int
+	// whether to skip reinstalling the packages once the database is UP
+	SkipPackageReinstall bool
+}
+
+func VUpgradeDatabaseOptionsFactory() VUpgradeDatabaseOptions {
+	options := VUpgradeDatabaseOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	options.StatePollingTimeout = util.DefaultStatePollingTimeout
+
+	return options
+}
+
+func (options *VUpgradeDatabaseOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(commandUpgradeDB, logger)
+	if err != nil {
+		return err
+	}
+	if options.RestorePointArchive != "" {
+		if !options.IsEon {
+			return fmt.Errorf("restore points are only supported in Eon mode")
+		}
+		return validateArchiveName(options.RestorePointArchive)
+	}
+	return nil
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VUpgradeDatabaseOptions) analyzeOptions() (err error) {
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = options.resolveRawHosts(options.RawHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VUpgradeDatabaseOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// VUpgradeDatabase upgrades a running database to the Vertica binaries that
+// are installed on its hosts. It runs the following steps as a plan, and
+// stops at the first one that fails:
+//   - Check that all hosts have the same Vertica version, and the target version if given
+//   - Save a restore point (optional), while the database is still UP
+//   - Stop the database
+//   - Start the database with the new binaries
+//   - Reinstall the packages (optional)
+//
+// It returns the report of each step. Only offline upgrades are supported.
+func (vcc VClusterCommands) VUpgradeDatabase(options *VUpgradeDatabaseOptions) (PlanReport, error) {
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return PlanReport{}, err
+	}
+
+	plan := options.makeUpgradePlan()
+	return vcc.VRunPlan(&plan)
+}
+
+// makeUpgradePlan makes the plan of the upgrade steps. Each step runs with a
+// copy of the options, so it takes their authentication and policies.
+func (options *VUpgradeDatabaseOptions) makeUpgradePlan() Plan {
+	plan := MakePlan(&options.DatabaseOptions)
+	plan.AddStep(upgradeCheckVersionsStep, &options.DatabaseOptions, func(vcc VClusterCommands) error {
+		return vcc.checkUpgradeVersions(options)
+	})
+
+	if options.RestorePointArchive != "" {
+		restorePointOptions := VSaveRestorePointOptionsFactory()
+		restorePointOptions.DatabaseOptions = options.DatabaseOptions
+		restorePointOptions.ArchiveName = options.RestorePointArchive
+		plan.AddSaveRestorePoint(&restorePointOptions)
+	}
+
+	stopDBOptions := VStopDatabaseOptionsFactory()
+	stopDBOptions.DatabaseOptions = options.DatabaseOptions
+	plan.AddStopDatabase(&stopDBOptions)
+
+	startDBOptions := VStartDatabaseOptionsFactory()
+	startDBOptions.DatabaseOptions = options.DatabaseOptions
+	startDBOptions.StatePollingTimeout = options.StatePollingTimeout
+	plan.AddStartDatabase(&startDBOptions)
+
+	if !options.SkipPackageReinstall {
+		installPackagesOptions := VInstallPackagesOptionsFactory()
+		installPackagesOptions.DatabaseOptions = options.DatabaseOptions
+		installPackagesOptions.ForceReinstall = true
+		plan.AddInstallPackages(&installPackagesOptions)
+	}
+	return plan
+}
+
+// checkUpgradeVersions reads the version of the Vertica binaries on every
+// host through the NMA, and checks that they match each other and the
+// target version
+func (vcc VClusterCommands) checkUpgradeVersions(options *VUpgradeDatabaseOptions) error {
+	nmaHealthOp := makeNMAHealthOp(options.Hosts)
+	nmaVerticaVersionOp := makeNMACheckVerticaVersionOp(options.Hosts, true /*sameVersion*/, options.IsEon)
+	instructions := []clusterOp{&nmaHealthOp, &nmaVerticaVersionOp}
+
+	clusterOpEngine := options.makeClusterOpEngine(instructions)
+	err := clusterOpEngine.run(vcc.Context(), vcc.Log)
+	if err != nil {
+		return fmt.Errorf("fail to check the Vertica versions of the hosts: %w", err)
+	}
+
+	hostVersions := make(map[string]string)
+	for _, scHostVersions := range nmaVerticaVersionOp.SCToHostVersionMap {
+		maps.Copy(hostVersions, scHostVersions)
+	}
+	return checkTargetVersion(hostVersions, options.TargetVersion)
+}
+
+// checkTargetVersion returns an error that lists the hosts whose version,
+// like "Vertica Analytic Database v24.2.0", is not the target version
+func checkTargetVersion(hostVersions map[string]string, targetVersion string) error {
+	if targetVersion == "" {
+		return nil
+	}
+	target := strings.TrimPrefix(targetVersion, "v")
+
+	var mismatchedHosts []string
+	for host, version := range hostVersions {
+		versionInfo := strings.Split(version, " ")
+		if strings.TrimPrefix(versionInfo[len(versionInfo)-1], "v") != target {
+			mismatchedHosts = append(mismatchedHosts, fmt.Sprintf("%s (%s)", host, version))
+		}
+	}
+	if len(mismatchedHosts) > 0 {
+		slices.Sort(mismatchedHosts)
+		return fmt.Errorf("the hosts %v do not have the target version %s installed", mismatchedHosts, targetVersion)
+	}
+	return nil
+}
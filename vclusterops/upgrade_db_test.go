@@ -0,0 +1,91 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestCheckTargetVersion(t *testing.T) {
+	hostVersions := map[string]string{
+		"192.168.1.101": "Vertica Analytic Database v24.2.0",
+		"192.168.1.102": "Vertica Analytic Database v24.2.0",
+	}
+
+	// without a target version, any version is accepted
+	assert.NoError(t, checkTargetVersion(hostVersions, ""))
+
+	// the "v" prefix is optional
+	assert.NoError(t, checkTargetVersion(hostVersions, "v24.2.0"))
+	assert.NoError(t, checkTargetVersion(hostVersions, "24.2.0"))
+
+	hostVersions["192.168.1.102"] = "Vertica Analytic Database v24.1.0"
+	err := checkTargetVersion(hostVersions, "v24.2.0")
+	assert.ErrorContains(t, err, "the hosts [192.168.1.102 (Vertica Analytic Database v24.1.0)] "+
+		"do not have the target version v24.2.0 installed")
+}
+
+func TestUpgradeDatabaseOptions(t *testing.T) {
+	logger := vlog.Printer{}
+	options := VUpgradeDatabaseOptionsFactory()
+	options.DBName = "test_db"
+	options.RawHosts = []string{"192.168.1.101"}
+	options.RestorePointArchive = "before_upgrade"
+
+	// restore points need an Eon database
+	err := options.validateParseOptions(logger)
+	assert.ErrorContains(t, err, "only supported in Eon mode")
+
+	options.IsEon = true
+	assert.NoError(t, options.validateParseOptions(logger))
+
+	options.RestorePointArchive = "before/upgrade"
+	assert.Error(t, options.validateParseOptions(logger))
+}
+
+func TestUpgradeReinstallsPackages(t *testing.T) {
+	var authHeaders []string
+	options := VUpgradeDatabaseOptionsFactory()
+	options.DBName = "test_db"
+	options.Hosts = []string{"192.168.1.101"}
+	options.UserName = "dbadmin"
+	options.CredentialProvider = CredentialProviderFunc(func(_ string) (string, error) {
+		return "provided-password", nil
+	})
+	options.WrapTransport = makeInstallPackagesTransport(&authHeaders)
+
+	plan := options.makeUpgradePlan()
+	installStep := plan.steps[len(plan.steps)-1]
+	assert.Equal(t, commandInstallPackages, installStep.name)
+
+	// the last step runs with the authentication of the upgrade, and reports its hosts
+	plan.steps = []planStep{installStep}
+	report, err := VClusterCommands{}.VRunPlan(&plan)
+	assert.NoError(t, err)
+	req := http.Request{Header: http.Header{}}
+	req.SetBasicAuth("dbadmin", "provided-password")
+	assert.Contains(t, authHeaders, req.Header.Get("Authorization"))
+	assert.Equal(t, []string{"192.168.1.101"}, report.Steps[0].Report.SucceededHosts())
+
+	// the packages are not reinstalled if skipped
+	options.SkipPackageReinstall = true
+	plan = options.makeUpgradePlan()
+	assert.NotEqual(t, commandInstallPackages, plan.steps[len(plan.steps)-1].name)
+}
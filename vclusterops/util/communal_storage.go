@@ -0,0 +1,199 @@
+/*
+ (c) Copyright [2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// CommunalStorageValidator checks the requirements of a communal storage provider,
+// given the location and the configuration parameters used to access it
+type CommunalStorageValidator func(location *url.URL, configParams map[string]string) error
+
+// communalStorageValidators are keyed by the URL schemes of the providers
+var communalStorageValidators = map[string]CommunalStorageValidator{
+	"s3":       validateS3CommunalStorage,
+	"gs":       validateGCSCommunalStorage,
+	"azb":      validateAzureCommunalStorage,
+	"webhdfs":  validateHDFSCommunalStorage,
+	"swebhdfs": validateHDFSCommunalStorage,
+}
+
+// RegisterCommunalStorageValidator adds or replaces the validator of the communal
+// storage locations with the given URL scheme
+func RegisterCommunalStorageValidator(scheme string, validator CommunalStorageValidator) {
+	communalStorageValidators[strings.ToLower(scheme)] = validator
+}
+
+// ValidateCommunalStorage validates a communal storage location, like
+// ValidateCommunalStorageLocation, and the configuration parameters that the
+// provider of the location requires. It is meant to catch a misconfiguration
+// before any request is sent to the communal storage.
+func ValidateCommunalStorage(location string, configParams map[string]string) error {
+	err := ValidateCommunalStorageLocation(location)
+	if err != nil {
+		return err
+	}
+	// a local path has no requirement
+	if IsAbsPath(location) {
+		return nil
+	}
+
+	locationURL, err := url.Parse(location)
+	if err != nil {
+		return fmt.Errorf("communal storage location %s is not a valid URL: %w", location, err)
+	}
+	scheme := strings.ToLower(locationURL.Scheme)
+	validator, ok := communalStorageValidators[scheme]
+	if !ok {
+		schemes := maps.Keys(communalStorageValidators)
+		slices.Sort(schemes)
+		return fmt.Errorf("communal storage scheme %s:// is not supported, use an absolute local path or one of the schemes %v",
+			scheme, schemes)
+	}
+	return validator(locationURL, configParams)
+}
+
+// getConfigParam returns the value of a configuration parameter, whose name is case insensitive
+func getConfigParam(configParams map[string]string, name string) (string, bool) {
+	for key, value := range configParams {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// validateKeyPair checks that the parameter, if set, is in the format of <access key ID>:<secret key>
+func validateKeyPair(configParams map[string]string, name string) error {
+	value, ok := getConfigParam(configParams, name)
+	if !ok {
+		return nil
+	}
+	id, secret, found := strings.Cut(value, ":")
+	if !found || id == "" || secret == "" {
+		return fmt.Errorf("%s must be in the format of <access key ID>:<secret key>", name)
+	}
+	return nil
+}
+
+// validateBooleanParam checks that the parameter, if set, is 0 or 1
+func validateBooleanParam(configParams map[string]string, name string) error {
+	value, ok := getConfigParam(configParams, name)
+	if ok && value != "0" && value != "1" {
+		return fmt.Errorf("%s must be 0 or 1, not %q", name, value)
+	}
+	return nil
+}
+
+// validateEndpointParam checks that the parameter, if set, is a <host>[:<port>] without a scheme
+func validateEndpointParam(configParams map[string]string, name, httpsParam string) error {
+	value, ok := getConfigParam(configParams, name)
+	if !ok {
+		return nil
+	}
+	if strings.Contains(value, "://") {
+		return fmt.Errorf("%s must be a <host>[:<port>] without the scheme, use %s to choose between http and https",
+			name, httpsParam)
+	}
+	if value == "" || strings.Contains(value, "/") {
+		return fmt.Errorf("%s must be a <host>[:<port>], not %q", name, value)
+	}
+	return nil
+}
+
+// validateS3CommunalStorage checks the parameters of AWS S3 or an S3 compatible storage.
+// No key is required, since the nodes can access S3 with their IAM roles.
+func validateS3CommunalStorage(_ *url.URL, configParams map[string]string) error {
+	if err := validateKeyPair(configParams, "AWSAuth"); err != nil {
+		return err
+	}
+	if _, ok := getConfigParam(configParams, "AWSSessionToken"); ok {
+		if _, hasAuth := getConfigParam(configParams, "AWSAuth"); !hasAuth {
+			return fmt.Errorf("AWSSessionToken requires the temporary keys in AWSAuth")
+		}
+	}
+	if region, ok := getConfigParam(configParams, "AWSRegion"); ok && region == "" {
+		return fmt.Errorf("AWSRegion must not be empty, remove it to use the default region us-east-1")
+	}
+	if err := validateEndpointParam(configParams, "AWSEndpoint", "AWSEnableHttps"); err != nil {
+		return err
+	}
+	return validateBooleanParam(configParams, "AWSEnableHttps")
+}
+
+// validateGCSCommunalStorage checks the parameters of Google Cloud Storage, which
+// is accessed with HMAC keys
+func validateGCSCommunalStorage(_ *url.URL, configParams map[string]string) error {
+	if _, ok := getConfigParam(configParams, "GCSAuth"); !ok {
+		return fmt.Errorf("must set GCSAuth to the HMAC keys of the Google Cloud Storage in the configuration parameters")
+	}
+	if err := validateKeyPair(configParams, "GCSAuth"); err != nil {
+		return err
+	}
+	if err := validateEndpointParam(configParams, "GCSEndpoint", "GCSEnableHttps"); err != nil {
+		return err
+	}
+	return validateBooleanParam(configParams, "GCSEnableHttps")
+}
+
+// validateAzureCommunalStorage checks the parameters of Azure Blob Storage. A location is in the
+// format of azb://<account>/<container>/<path>. No credential is required, since the nodes can
+// access the storage with their managed identities.
+func validateAzureCommunalStorage(location *url.URL, configParams map[string]string) error {
+	account := location.Host
+	if strings.Trim(location.Path, "/") == "" {
+		return fmt.Errorf("azure communal storage location must be in the format of azb://<account>/<container>/<path>")
+	}
+
+	type azureAccountConfig struct {
+		AccountName string `json:"accountName"`
+	}
+	for _, name := range []string{"AzureStorageCredentials", "AzureStorageEndpointConfig"} {
+		value, ok := getConfigParam(configParams, name)
+		if !ok {
+			continue
+		}
+		var configs []azureAccountConfig
+		if err := json.Unmarshal([]byte(value), &configs); err != nil {
+			return fmt.Errorf("%s must be a JSON array of the configurations of the storage accounts: %w", name, err)
+		}
+		found := false
+		for _, config := range configs {
+			found = found || config.AccountName == account
+		}
+		if !found {
+			return fmt.Errorf("%s has no configuration for the storage account %s of the communal storage", name, account)
+		}
+	}
+	return nil
+}
+
+// validateHDFSCommunalStorage checks an HDFS location, which must name the name node
+// or the name service of the HDFS cluster
+func validateHDFSCommunalStorage(location *url.URL, _ map[string]string) error {
+	if location.Hostname() == "" {
+		return fmt.Errorf("HDFS communal storage location must be in the format of %s://<name node or name service>/<path>",
+			location.Scheme)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+ (c) Copyright [2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCommunalStorage(t *testing.T) {
+	// local paths and invalid locations are checked as before
+	assert.NoError(t, ValidateCommunalStorage("/communal/db", nil))
+	assert.Error(t, ValidateCommunalStorage("s3:/bucket/db", nil))
+
+	// negative: unknown scheme
+	err := ValidateCommunalStorage("ftp://host/db", nil)
+	assert.ErrorContains(t, err, "communal storage scheme ftp:// is not supported")
+
+	// s3 can be accessed with an IAM role, without any parameter
+	assert.NoError(t, ValidateCommunalStorage("s3://bucket/db", nil))
+	assert.NoError(t, ValidateCommunalStorage("S3://bucket/db", map[string]string{
+		"awsauth": "id:secret", "AWSRegion": "us-west-2", "AWSEndpoint": "minio.local:9000", "AWSEnableHttps": "0",
+	}))
+	err = ValidateCommunalStorage("s3://bucket/db", map[string]string{"AWSAuth": "id"})
+	assert.ErrorContains(t, err, "AWSAuth must be in the format of <access key ID>:<secret key>")
+	err = ValidateCommunalStorage("s3://bucket/db", map[string]string{"AWSSessionToken": "token"})
+	assert.ErrorContains(t, err, "AWSSessionToken requires the temporary keys in AWSAuth")
+	err = ValidateCommunalStorage("s3://bucket/db", map[string]string{"AWSEndpoint": "http://minio.local:9000"})
+	assert.ErrorContains(t, err, "use AWSEnableHttps")
+	err = ValidateCommunalStorage("s3://bucket/db", map[string]string{"AWSEnableHttps": "true"})
+	assert.ErrorContains(t, err, "AWSEnableHttps must be 0 or 1")
+
+	// gs requires the HMAC keys
+	err = ValidateCommunalStorage("gs://bucket/db", nil)
+	assert.ErrorContains(t, err, "must set GCSAuth")
+	assert.NoError(t, ValidateCommunalStorage("gs://bucket/db", map[string]string{"GCSAuth": "id:secret"}))
+
+	// azb requires the container, and the configurations of the storage account
+	assert.NoError(t, ValidateCommunalStorage("azb://account/container/db", nil))
+	err = ValidateCommunalStorage("azb://account", nil)
+	assert.ErrorContains(t, err, "azb://<account>/<container>/<path>")
+	credentials := `[{"accountName": "account", "accountKey": "key"}]`
+	assert.NoError(t, ValidateCommunalStorage("azb://account/container/db",
+		map[string]string{"AzureStorageCredentials": credentials}))
+	err = ValidateCommunalStorage("azb://other/container/db", map[string]string{"AzureStorageCredentials": credentials})
+	assert.ErrorContains(t, err, "AzureStorageCredentials has no configuration for the storage account other")
+	err = ValidateCommunalStorage("azb://account/container/db", map[string]string{"AzureStorageEndpointConfig": "{"})
+	assert.ErrorContains(t, err, "AzureStorageEndpointConfig must be a JSON array")
+
+	assert.NoError(t, ValidateCommunalStorage("webhdfs://namenode:50070/db", nil))
+}
+
+func TestRegisterCommunalStorageValidator(t *testing.T) {
+	defer delete(communalStorageValidators, "oci")
+
+	assert.Error(t, ValidateCommunalStorage("oci://bucket/db", nil))
+	RegisterCommunalStorageValidator("OCI", func(location *url.URL, _ map[string]string) error {
+		return fmt.Errorf("bucket %s is not reachable", location.Host)
+	})
+	assert.ErrorContains(t, ValidateCommunalStorage("oci://bucket/db", nil), "bucket bucket is not reachable")
+}
@@ -240,6 +240,22 @@ func IsIPv6(ip string) bool {
 	return strings.Contains(ip, ":") && net.ParseIP(ip).To16() != nil
 }
 
+// IsIPv6Literal returns true if host is an IPv6 address that a connection can
+// be made to, which may be a scoped address, e.g., a link-local address with a
+// zone ID after `%`. The addresses stored in the catalog must pass IsIPv6.
+func IsIPv6Literal(host string) bool {
+	ip, _, _ := strings.Cut(host, "%")
+	return IsIPv6(ip)
+}
+
+// TrimHostBrackets removes the brackets around an IPv6 literal, e.g., [fd00::1]
+func TrimHostBrackets(host string) string {
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		return host[1 : len(host)-1]
+	}
+	return host
+}
+
 func AddressCheck(address string, ipv6 bool) error {
 	checkPassed := false
 	if ipv6 {
@@ -260,38 +276,46 @@ func AddressCheck(address string, ipv6 bool) error {
 }
 
 func ResolveToIPAddrs(hostname string, ipv6 bool) ([]string, error) {
+	v4Addrs, v6Addrs, err := ResolveToAllIPAddrs(hostname)
+	if err != nil {
+		return nil, err
+	}
+	if ipv6 {
+		return v6Addrs, nil
+	}
+	return v4Addrs, nil
+}
+
+// ResolveToAllIPAddrs resolves hostname to its IPv4 and its IPv6 addresses
+func ResolveToAllIPAddrs(hostname string) (v4Addrs, v6Addrs []string, err error) {
 	// resolve hostname using local resolver
 	hostIPs, err := net.LookupHost(hostname)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if len(hostIPs) < 1 {
-		return nil, fmt.Errorf("cannot resolve %s to a valid IP address", hostname)
+		return nil, nil, fmt.Errorf("cannot resolve %s to a valid IP address", hostname)
 	}
-	var v4Addrs []string
-	var v6Addrs []string
 	for _, addr := range hostIPs {
 		if IsIPv4(addr) {
 			v4Addrs = append(v4Addrs, addr)
-		} else if IsIPv6(addr) {
+		} else if IsIPv6Literal(addr) {
 			v6Addrs = append(v6Addrs, addr)
 		} else {
-			return nil, fmt.Errorf("%s is resolved to invalid address %s", hostname, addr)
+			return nil, nil, fmt.Errorf("%s is resolved to invalid address %s", hostname, addr)
 		}
 	}
-	if ipv6 {
-		return v6Addrs, nil
-	}
-	return v4Addrs, nil
+	return v4Addrs, v6Addrs, nil
 }
 
 func ResolveToOneIP(hostname string, ipv6 bool) (string, error) {
+	hostname = TrimHostBrackets(hostname)
 	// already an IPv4 or IPv6 address
 	if !ipv6 && IsIPv4(hostname) {
 		return hostname, nil
 	}
 	// IPv6
-	if ipv6 && IsIPv6(hostname) {
+	if ipv6 && IsIPv6Literal(hostname) {
 		return hostname, nil
 	}
 
@@ -317,6 +341,30 @@ func ResolveToOneIP(hostname string, ipv6 bool) (string, error) {
 	return addrs[0], nil
 }
 
+// ResolveToOneIPDualStack resolves hostname to an IP address of either family,
+// for the clusters whose hosts do not all have the same address family. An IP
+// address is kept as is, and a hostname that resolves to both families is
+// resolved to an address of the preferred family.
+func ResolveToOneIPDualStack(hostname string, preferIPv6 bool) (string, error) {
+	hostname = TrimHostBrackets(hostname)
+	if IsIPv4(hostname) || IsIPv6Literal(hostname) {
+		return hostname, nil
+	}
+
+	v4Addrs, v6Addrs, err := ResolveToAllIPAddrs(hostname)
+	if err != nil {
+		return "", err
+	}
+	addrs := v4Addrs
+	if (preferIPv6 && len(v6Addrs) > 0) || len(v4Addrs) == 0 {
+		addrs = v6Addrs
+	}
+	if len(addrs) > 1 {
+		return "", fmt.Errorf("%s is resolved to more than one IP addresss: %v", hostname, addrs)
+	}
+	return addrs[0], nil
+}
+
 // resolve RawHosts to be IP addresses
 func ResolveRawHostsToAddresses(rawHosts []string, ipv6 bool) ([]string, error) {
 	var hostAddresses []string
@@ -336,6 +384,25 @@ func ResolveRawHostsToAddresses(rawHosts []string, ipv6 bool) ([]string, error)
 	return hostAddresses, nil
 }
 
+// ResolveRawHostsToAddressesDualStack resolves the hosts to IP addresses of
+// either family, see ResolveToOneIPDualStack
+func ResolveRawHostsToAddressesDualStack(rawHosts []string, preferIPv6 bool) ([]string, error) {
+	var hostAddresses []string
+
+	for _, host := range rawHosts {
+		if host == "" {
+			return hostAddresses, fmt.Errorf("invalid empty host found in the provided host list")
+		}
+		addr, err := ResolveToOneIPDualStack(host, preferIPv6)
+		if err != nil {
+			return hostAddresses, err
+		}
+		hostAddresses = append(hostAddresses, addr)
+	}
+
+	return hostAddresses, nil
+}
+
 // replace all '//' to be '/', trim the path string
 func GetCleanPath(path string) string {
 	if path == "" {
@@ -596,6 +663,14 @@ func Max[T constraints.Ordered](a, b T) T {
 	return b
 }
 
+// Min is the counterpart of Max
+func Min[T constraints.Ordered](a, b T) T {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 // GetPathPrefix returns a path prefix for a (catalog/data/depot) path of a node
 func GetPathPrefix(path string) string {
 	if path == "" {
@@ -661,3 +736,44 @@ func FillInDefaultTimeForEndTimestamp(dateonly *string) *time.Time {
 func IsTimeEqualOrAfter(start, end time.Time) bool {
 	return end.Equal(start) || end.After(start)
 }
+
+// FindClosestString returns the candidate with the smallest case-insensitive
+// edit distance to the given string, to suggest a fix for a typo. It returns
+// false if no candidate is close enough to be a likely typo.
+func FindClosestString(s string, candidates []string) (string, bool) {
+	const minMaxDistance = 2
+	const lengthPerDistance = 3
+	maxDistance := Max(minMaxDistance, len(s)/lengthPerDistance)
+	closest := ""
+	closestDistance := maxDistance + 1
+	for _, candidate := range candidates {
+		distance := editDistance(strings.ToLower(s), strings.ToLower(candidate))
+		if distance < closestDistance {
+			closest = candidate
+			closestDistance = distance
+		}
+	}
+	return closest, closestDistance <= maxDistance
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			substitution := previous[j-1]
+			if ra[i-1] != rb[j-1] {
+				substitution++
+			}
+			current[j] = Min(Min(previous[j]+1, current[j-1]+1), substitution)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
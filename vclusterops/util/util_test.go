@@ -115,6 +115,54 @@ func TestResolveToOneIP(t *testing.T) {
 
 	_, err = ResolveToOneIP("2001:db8::8:800:200c:417a", false)
 	assert.ErrorContains(t, err, "cannot resolve 2001:db8::8:800:200c:417a as IPv4 address")
+
+	// bracketed and scoped IPv6 literals
+	res, err = ResolveToOneIP("[2001:db8::8:800:200c:417a]", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:db8::8:800:200c:417a", res)
+	res, err = ResolveToOneIP("fe80::1%eth0", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "fe80::1%eth0", res)
+}
+
+func TestResolveToOneIPDualStack(t *testing.T) {
+	// the addresses of both families are kept
+	hosts, err := ResolveRawHostsToAddressesDualStack([]string{"192.168.1.101", "[fd00::102]", "fe80::103%eth1"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.101", "fd00::102", "fe80::103%eth1"}, hosts)
+
+	_, err = ResolveRawHostsToAddressesDualStack([]string{"192.168.1.101", ""}, true)
+	assert.ErrorContains(t, err, "invalid empty host")
+
+	// localhost resolves to 127.0.0.1, and to ::1 on some systems
+	v4Addrs, v6Addrs, err := ResolveToAllIPAddrs("localhost")
+	assert.NoError(t, err)
+	assert.Contains(t, v4Addrs, "127.0.0.1")
+	res, err := ResolveToOneIPDualStack("localhost", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", res)
+	if len(v6Addrs) == 1 {
+		res, err = ResolveToOneIPDualStack("localhost", true)
+		assert.NoError(t, err)
+		assert.Equal(t, v6Addrs[0], res)
+	}
+
+	_, err = ResolveToOneIPDualStack("randomIP", false)
+	assert.Error(t, err)
+}
+
+func TestIPv6Literals(t *testing.T) {
+	// a scoped address can be connected to, but is not a valid address of a node
+	assert.False(t, IsIPv6("fe80::1%eth0"))
+	assert.True(t, IsIPv6Literal("fe80::1%eth0"))
+	assert.Error(t, AddressCheck("fe80::1%eth0", true))
+	assert.False(t, IsIPv6Literal("192.168.1.101%eth0"))
+	assert.True(t, IsIPv6("fd00::1"))
+	assert.False(t, IsIPv6("192.168.1.101"))
+	assert.False(t, IsIPv6("[fd00::1]"))
+	assert.Equal(t, "fd00::1", TrimHostBrackets("[fd00::1]"))
+	assert.Equal(t, "fd00::1", TrimHostBrackets("fd00::1"))
+	assert.Equal(t, "vertica-node1", TrimHostBrackets("vertica-node1"))
 }
 
 func TestGetCleanPath(t *testing.T) {
@@ -383,3 +431,21 @@ func TestIsEmptyOrValidTimeStr(t *testing.T) {
 	_, err = IsEmptyOrValidTimeStr(layout, testTimeString)
 	assert.ErrorContains(t, err, "cannot parse")
 }
+
+func TestFindClosestString(t *testing.T) {
+	candidates := []string{"MaxClientSessions", "EnableSSL", "MaxDepotSizePercent"}
+
+	// a typo is matched case-insensitively
+	closest, found := FindClosestString("maxclientsesions", candidates)
+	assert.True(t, found)
+	assert.Equal(t, "MaxClientSessions", closest)
+	closest, found = FindClosestString("EnableSLS", candidates)
+	assert.True(t, found)
+	assert.Equal(t, "EnableSSL", closest)
+
+	// nothing close enough
+	_, found = FindClosestString("DisableEverything", candidates)
+	assert.False(t, found)
+	_, found = FindClosestString("EnableSSL", nil)
+	assert.False(t, found)
+}
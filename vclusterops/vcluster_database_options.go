@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -36,6 +37,28 @@ type DatabaseOptions struct {
 	Hosts []string
 	// whether using IPv6 for host addresses
 	IPv6 bool
+	// whether the hosts can have addresses of both families, e.g., in a
+	// cluster that is migrated to IPv6. IPv6 is then the preferred family
+	// of the hostnames that resolve to addresses of both families.
+	DualStack bool
+	// whether the hostnames in RawHosts are resolved again each time that
+	// a connection is made to their hosts, so that the ops reach a host whose
+	// DNS record changes during the command, e.g., a restarted pod in
+	// Kubernetes. The hosts are still identified by the addresses that they
+	// are resolved to when the command starts.
+	ReresolveHostnames bool
+	// the hostnames that the hosts are resolved from, keyed by address
+	hostnames map[string]string
+	// the addresses of the nodes of a multi-homed cluster on each network.
+	// The hosts are the addresses of the nodes on the spread network.
+	AddressBook *AddressBook
+	// selects the host that the ops needing a single bootstrap host run on,
+	// the first host if not set
+	InitiatorStrategy InitiatorStrategy
+	// whether the hosts are probed before the instructions are built, so that
+	// the commands that can proceed with a subset of their hosts skip the
+	// unreachable ones instead of failing, see GetHostRequirement
+	PreflightProbe bool
 	// path of catalog directory
 	CatalogPrefix string
 	// path of data directory
@@ -58,14 +81,31 @@ type DatabaseOptions struct {
 
 	// user name
 	UserName string
+	// the privilege of the user, PrivilegeSuperuser by default. The commands
+	// that require more than the user has fail with an InsufficientPrivilegeError
+	// before they send any request.
+	UserPrivilege PrivilegeLevel
 	// password
 	Password *string
+	// supplies the password when the requests are sent, instead of Password
+	CredentialProvider CredentialProvider
+	// the token that the HTTPS requests authenticate the user with, instead
+	// of a password, on the servers that support token authentication
+	BearerToken string
+	// called for a new token when the HTTPS service rejects BearerToken, e.g.,
+	// because it has expired, and for the first token if BearerToken is empty
+	RefreshBearerToken TokenRefreshFunc
+	// the bearer token shared by the runs of the command
+	bearerToken *bearerTokenSource
 	// TLS Key
 	Key string
 	// TLS Certificate
 	Cert string
 	// TLS CA Certificate
 	CaCert string
+	// provides the TLS credentials for each new connection, and takes
+	// precedence over Key, Cert and CaCert, see CertProvider
+	CertProvider CertProvider
 
 	/* part 4: other info */
 
@@ -73,6 +113,51 @@ type DatabaseOptions struct {
 	LogPath string
 	// whether use password
 	usePassword bool
+	// name of the command that validated the options, recorded by the audit
+	commandName string
+	// startup commands of the nodes, keyed by node name. The stop commands
+	// save the commands of the nodes here before stopping them, so that the
+	// caller can persist them and pass them to a later start when no UP node
+	// is able to serve them.
+	StartupCommands map[string][]string
+	// filters the nodes that the command acts on by their role, see TargetSelector
+	// for the commands that honor it
+	TargetSelector TargetSelector
+	// directory where a command saves its progress when it fails, so that
+	// running it again with the same options resumes from the failed step.
+	// Empty disables checkpointing. Only revive_db supports it for now.
+	CheckpointDir string
+	// keeps the changes of the completed steps, such as the prepared
+	// directories, when a later step fails, instead of rolling them back.
+	// This is useful to debug a failure. A command that saves a checkpoint
+	// never rolls back its changes.
+	DisableRollback bool
+	// how the requests to the hosts are retried after a transient failure,
+	// see DefaultRetryPolicy. The zero value disables retries.
+	RetryPolicy RetryPolicy
+	// limits how long the requests, the ops, and the whole command can take
+	TimeoutPolicy TimeoutPolicy
+	// maximum number of hosts that an op sends requests to at a time, which
+	// keeps a large cluster from overwhelming the local sockets and the node
+	// management agents. 0 means no limit.
+	MaxConcurrentHosts int
+	// how the certificates of the HTTPS service and the node management
+	// agents are verified, they are not verified by default
+	TLSVerification TLSVerificationPolicy
+	// configures the connections to the hosts that the ops of the command share
+	TransportPolicy TransportPolicy
+	// how the hosts are reached, e.g., through a proxy or an SSH jump host
+	ProxyPolicy ProxyPolicy
+	// optional, wraps the transports that send the requests of the command
+	WrapTransport TransportWrapper
+	// the connections shared by the steps of a plan, nil for the engines to
+	// open their own connections
+	sharedTransports *transportCache
+
+	/* part 5: result info */
+
+	// per-host request details of the ops run with these options
+	report OperationReport
 }
 
 const (
@@ -87,6 +172,7 @@ const (
 const (
 	commandCreateDB                  = "create_db"
 	commandDropDB                    = "drop_db"
+	commandReviveDB                  = "revive_db"
 	commandStopDB                    = "stop_db"
 	commandStartDB                   = "start_db"
 	commandAddNode                   = "add_node"
@@ -104,14 +190,47 @@ const (
 	commandConfigRecover             = "manage_config_recover"
 	commandManageConnectionDraining  = "manage_connection_draining"
 	commandSetConfigurationParameter = "set_configuration_parameter"
+	commandListConfigurationParams   = "list_configuration_parameters"
 	commandReplicationStart          = "replication_start"
+	commandReplicationStatus         = "replication_status"
 	commandPromoteSandboxToMain      = "promote_sandbox_to_main"
 	commandFetchNodesDetails         = "fetch_nodes_details"
+	commandFetchNodeState            = "fetch_node_state"
 	commandAlterSubclusterType       = "alter_subcluster_type"
 	commandRenameSc                  = "rename_subcluster"
+	commandSetTLSConfig              = "set_tls_config"
+	commandDeployServerCertificate   = "deploy_server_certificate"
+	commandCreateArchive             = "create_archive"
+	commandSaveRestorePoint          = "save_restore_point"
+	commandRemoveRestorePoint        = "remove_restore_point"
 	commandReIP                      = "re_ip"
+	commandGetClusterLease           = "get_cluster_lease"
+	commandRollingRestart            = "rolling_restart"
+	commandHealthCheck               = "health_check"
+	commandTailLog                   = "tail_log"
+	commandManageDepot               = "manage_depot"
+	commandRebalanceShards           = "rebalance_shards"
+	commandSetCommunalCredentials    = "set_communal_storage_credentials"
+	commandUpgradeDB                 = "upgrade_db"
+	commandSetReadOnly               = "set_read_only"
+	commandNodeWatchdog              = "node_watchdog"
+	commandClusterEvents             = "cluster_events"
+	commandRotateTLSCerts            = "rotate_tls_certs"
 )
 
+// SetPassword sets the password, so that callers do not need a pointer to a string
+func (opt *DatabaseOptions) SetPassword(password string) {
+	opt.Password = &password
+}
+
+// GetPassword returns the password, and whether it is set
+func (opt *DatabaseOptions) GetPassword() (string, bool) {
+	if opt.Password == nil {
+		return "", false
+	}
+	return *opt.Password, true
+}
+
 func DatabaseOptionsFactory() DatabaseOptions {
 	opt := DatabaseOptions{}
 	// set default values to the params
@@ -127,6 +246,8 @@ func (opt *DatabaseOptions) setDefaultValues() {
 func (opt *DatabaseOptions) validateBaseOptions(commandName string, log vlog.Printer) error {
 	// get vcluster commands
 	log.WithName(commandName)
+	opt.commandName = commandName
+	opt.resetReport()
 	// database name
 	if opt.DBName == "" {
 		return fmt.Errorf("must specify a database name")
@@ -142,6 +263,44 @@ func (opt *DatabaseOptions) validateBaseOptions(commandName string, log vlog.Pri
 		return err
 	}
 
+	err = opt.TargetSelector.validate()
+	if err != nil {
+		return err
+	}
+
+	if opt.MaxConcurrentHosts < 0 {
+		return fmt.Errorf("max concurrent hosts must not be negative, got %d", opt.MaxConcurrentHosts)
+	}
+
+	if opt.Password != nil && opt.CredentialProvider != nil {
+		return fmt.Errorf("only one of the password and the credential provider can be set")
+	}
+	if opt.hasBearerToken() && (opt.Password != nil || opt.CredentialProvider != nil) {
+		return fmt.Errorf("the bearer token cannot be set with a password or a credential provider")
+	}
+
+	err = opt.validatePrivilege(commandName)
+	if err != nil {
+		return err
+	}
+
+	err = opt.TLSVerification.validate()
+	if err != nil {
+		return err
+	}
+	err = opt.TransportPolicy.validate()
+	if err != nil {
+		return err
+	}
+	err = opt.ProxyPolicy.validate()
+	if err != nil {
+		return err
+	}
+	err = opt.AddressBook.validate()
+	if err != nil {
+		return err
+	}
+
 	// paths
 	err = opt.validatePaths(commandName)
 	if err != nil {
@@ -157,6 +316,14 @@ func (opt *DatabaseOptions) validateBaseOptions(commandName string, log vlog.Pri
 		}
 	}
 
+	// checkpoint directory
+	if opt.CheckpointDir != "" {
+		err = util.ValidateAbsPath(opt.CheckpointDir, "checkpoint directory")
+		if err != nil {
+			return err
+		}
+	}
+
 	// log directory
 	if log.LogToFileOnly {
 		err = util.ValidateAbsPath(opt.LogPath, "log directory")
@@ -263,7 +430,7 @@ func (opt *DatabaseOptions) setUsePasswordAndValidateUsernameIfNeeded(log vlog.P
 	// when password is specified,
 	// we will use username/password to call https endpoints
 	opt.usePassword = false
-	if opt.Password != nil {
+	if opt.hasPassword() {
 		opt.usePassword = true
 		err := opt.validateUserName(log)
 		if err != nil {
@@ -274,14 +441,40 @@ func (opt *DatabaseOptions) setUsePasswordAndValidateUsernameIfNeeded(log vlog.P
 	return nil
 }
 
+// hasCertsInOptions returns true if the options provide the TLS credentials,
+// rather than the commands reading them from the default paths
+func (opt *DatabaseOptions) hasCertsInOptions() bool {
+	return opt.CertProvider != nil || (opt.Cert != "" && opt.Key != "")
+}
+
 func (opt *DatabaseOptions) setUsePassword(_ vlog.Printer) error {
 	opt.usePassword = false
-	if opt.Password != nil {
+	if opt.hasPassword() {
 		opt.usePassword = true
 	}
 	return nil
 }
 
+// hasPassword returns true if the options provide a password, directly or
+// through a CredentialProvider, or a bearer token that replaces it
+func (opt *DatabaseOptions) hasPassword() bool {
+	return opt.Password != nil || opt.CredentialProvider != nil || opt.hasBearerToken()
+}
+
+// getRequestDataPassword returns the password of the ops that send it in
+// their request data, which is built before the engine runs. The password of
+// a CredentialProvider is only kept by the ops, not by the options.
+func (opt *DatabaseOptions) getRequestDataPassword() (*string, error) {
+	if opt.Password != nil || opt.CredentialProvider == nil {
+		return opt.Password, nil
+	}
+	password, err := opt.CredentialProvider.GetPassword(opt.UserName)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get the password of user %s: %w", opt.UserName, err)
+	}
+	return &password, nil
+}
+
 // normalizePaths replaces all '//' to be '/', and trim
 // catalog, data and depot prefixes.
 func (opt *DatabaseOptions) normalizePaths() {
@@ -317,9 +510,8 @@ func (opt *DatabaseOptions) getVDBWhenDBIsDown(vcc VClusterCommands) (vdb VCoord
 		&nmaGetNodesInfoOp,
 	)
 
-	certs := httpsCerts{key: opt.Key, cert: opt.Cert, caCert: opt.CaCert}
-	clusterOpEngine := makeClusterOpEngine(instructions1, &certs)
-	err = clusterOpEngine.run(vcc.Log)
+	clusterOpEngine := opt.makeClusterOpEngine(instructions1)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if err != nil {
 		vcc.Log.PrintError("fail to retrieve node names from NMA /nodes: %v", err)
 		return vdb, err
@@ -334,10 +526,15 @@ func (opt *DatabaseOptions) getVDBWhenDBIsDown(vcc VClusterCommands) (vdb VCoord
 	if err != nil {
 		return vdb, err
 	}
+	initiator, err := opt.selectInitiator(vcc.Context(), opt.Hosts)
+	if err != nil {
+		return vdb, err
+	}
+	nmaDownLoadFileOp.setInitiator(initiator)
 	instructions2 = append(instructions2, &nmaDownLoadFileOp)
 
-	clusterOpEngine = makeClusterOpEngine(instructions2, &certs)
-	err = clusterOpEngine.run(vcc.Log)
+	clusterOpEngine = opt.makeClusterOpEngine(instructions2)
+	err = clusterOpEngine.run(vcc.Context(), vcc.Log)
 	if err != nil {
 		vcc.Log.PrintError("fail to retrieve node details from %s: %v", descriptionFileName, err)
 		return vdb, err
@@ -381,6 +578,32 @@ func (opt *DatabaseOptions) getCurrConfigFilePath() string {
 	return descriptionFilePath
 }
 
+// getReviveConfigFilePath makes the current description file path of the database, or of the
+// sandbox to revive if it is set in the options
+func (options *VReviveDatabaseOptions) getReviveConfigFilePath() string {
+	if options.Sandbox == util.MainClusterSandbox {
+		return options.getCurrConfigFilePath()
+	}
+	// description file will be in the location: {communal_storage_location}/metadata/{db_name}/sandbox/{sandbox}/cluster_config.json
+	descriptionFilePath := filepath.Join(options.getMetadataFolder(), descriptionFileName)
+	descriptionFilePath = strings.Replace(descriptionFilePath, ":/", "://", 1)
+
+	return descriptionFilePath
+}
+
+// getMetadataFolder makes the folder of the metadata to revive from, which is the metadata folder
+// of the sandbox if it is set in the options. The path is not in url format.
+func (options *VReviveDatabaseOptions) getMetadataFolder() string {
+	const (
+		sandboxFolder = "sandbox"
+	)
+	metadataFolder := filepath.Join(options.CommunalStorageLocation, descriptionFileMetadataFolder, options.DBName)
+	if options.Sandbox != util.MainClusterSandbox {
+		metadataFolder = filepath.Join(metadataFolder, sandboxFolder, options.Sandbox)
+	}
+	return metadataFolder
+}
+
 // getRestorePointConfigFilePath can make the restore point description file path using db name, archive name, restore point id,
 // and communal storage location in the options
 func (options *VReviveDatabaseOptions) getRestorePointConfigFilePath(validatedRestorePointID string) string {
@@ -390,8 +613,9 @@ func (options *VReviveDatabaseOptions) getRestorePointConfigFilePath(validatedRe
 	// description file will be in the location:
 	// {communal_storage_location}/metadata/{db_name}/archives/{archive_name}/{restore_point_id}/cluster_config.json
 	// an example: s3://tfminio/test_loc/metadata/test_db/archives/test_archive_name/2251e5cc-3e16-4fb1-8cd0-e4b8651f5779/cluster_config.json
-	descriptionFilePath := filepath.Join(options.CommunalStorageLocation, descriptionFileMetadataFolder,
-		options.DBName, archivesFolder, options.RestorePoint.Archive, validatedRestorePointID, descriptionFileName)
+	// for a sandbox, the archives are in {communal_storage_location}/metadata/{db_name}/sandbox/{sandbox}
+	descriptionFilePath := filepath.Join(options.getMetadataFolder(),
+		archivesFolder, options.RestorePoint.Archive, validatedRestorePointID, descriptionFileName)
 	// filepath.Join() will change "://" of the remote communal storage path to ":/"
 	// as a result, we need to change the separator back to url format
 	descriptionFilePath = strings.Replace(descriptionFilePath, ":/", "://", 1)
@@ -410,11 +634,106 @@ func (opt *DatabaseOptions) isSpreadEncryptionEnabled() (enabled bool, encryptio
 	return false, ""
 }
 
-func (opt *DatabaseOptions) runClusterOpEngine(log vlog.Printer, instructions []clusterOp) error {
-	// Create a VClusterOpEngine, and add certs to the engine
-	certs := httpsCerts{key: opt.Key, cert: opt.Cert, caCert: opt.CaCert}
+// GetOperationReport returns the per-host request details, such as HTTP status
+// codes, latencies and retry counts, and the warnings of the ops of the last
+// command run with these options
+func (opt *DatabaseOptions) GetOperationReport() OperationReport {
+	return opt.report
+}
+
+// resetReport starts a new report, so that the report of options reused
+// across commands, or across the polls of a long-running command, only
+// holds the latest run
+func (opt *DatabaseOptions) resetReport() {
+	opt.report = OperationReport{}
+}
+
+// resolveRawHosts resolves the hosts to IP addresses of the family of the options
+func (opt *DatabaseOptions) resolveRawHosts(rawHosts []string) (hosts []string, err error) {
+	if opt.DualStack {
+		hosts, err = util.ResolveRawHostsToAddressesDualStack(rawHosts, opt.IPv6)
+	} else {
+		hosts, err = util.ResolveRawHostsToAddresses(rawHosts, opt.IPv6)
+	}
+	if err != nil {
+		return hosts, err
+	}
+	for i, host := range hosts {
+		opt.recordHostname(rawHosts[i], host)
+	}
+	return hosts, nil
+}
+
+// resolveHost resolves a host to an IP address of the family of the options
+func (opt *DatabaseOptions) resolveHost(rawHost string) (host string, err error) {
+	if opt.DualStack {
+		host, err = util.ResolveToOneIPDualStack(rawHost, opt.IPv6)
+	} else {
+		host, err = util.ResolveToOneIP(rawHost, opt.IPv6)
+	}
+	if err != nil {
+		return host, err
+	}
+	opt.recordHostname(rawHost, host)
+	return host, nil
+}
+
+// recordHostname keeps the hostname that host is resolved from, if it is not an address
+func (opt *DatabaseOptions) recordHostname(rawHost, host string) {
+	rawHost = util.TrimHostBrackets(rawHost)
+	if rawHost == host || util.IsIPv4(rawHost) || util.IsIPv6Literal(rawHost) {
+		return
+	}
+	if opt.hostnames == nil {
+		opt.hostnames = make(map[string]string)
+	}
+	opt.hostnames[host] = rawHost
+}
+
+// makeClusterOpEngine creates a VClusterOpEngine that uses the certs in the
+// options, and records the report of the ops into the options
+func (opt *DatabaseOptions) makeClusterOpEngine(instructions []clusterOp) VClusterOpEngine {
+	certs := httpsCerts{key: opt.Key, cert: opt.Cert, caCert: opt.CaCert, provider: opt.CertProvider}
 	clusterOpEngine := makeClusterOpEngine(instructions, &certs)
+	clusterOpEngine.report = &opt.report
+	clusterOpEngine.retryPolicy = &opt.RetryPolicy
+	clusterOpEngine.timeoutPolicy = &opt.TimeoutPolicy
+	clusterOpEngine.maxConcurrentHosts = opt.MaxConcurrentHosts
+	clusterOpEngine.tlsVerification = &opt.TLSVerification
+	clusterOpEngine.transportPolicy = &opt.TransportPolicy
+	clusterOpEngine.proxyPolicy = &opt.ProxyPolicy
+	clusterOpEngine.wrapTransport = opt.WrapTransport
+	if opt.ReresolveHostnames {
+		clusterOpEngine.hostnames = opt.hostnames
+	}
+	clusterOpEngine.addressBook = opt.AddressBook
+	clusterOpEngine.credentialProvider = opt.CredentialProvider
+	clusterOpEngine.bearerToken = opt.getBearerTokenSource()
+	clusterOpEngine.rollbackDisabled = opt.DisableRollback
+	clusterOpEngine.sharedTransports = opt.sharedTransports
+	clusterOpEngine.options = opt
+	return clusterOpEngine
+}
+
+// saveStartupCommands keeps the startup commands retrieved by the ops of
+// the engine into the options
+func (opt *DatabaseOptions) saveStartupCommands(execContext *opEngineExecContext) {
+	startupCommands, ok := execContext.StartupCommands()
+	if !ok {
+		return
+	}
+	if opt.StartupCommands == nil {
+		opt.StartupCommands = make(map[string][]string)
+	}
+	for nodeName, startupCommand := range startupCommands {
+		opt.StartupCommands[nodeName] = startupCommand
+	}
+}
+
+func (opt *DatabaseOptions) runClusterOpEngine(ctx context.Context, log vlog.Printer, instructions []clusterOp) error {
+	// Create a VClusterOpEngine, and add certs to the engine
+	clusterOpEngine := opt.makeClusterOpEngine(instructions)
 
 	// Give the instructions to the VClusterOpEngine to run
-	return clusterOpEngine.run(log)
+	return clusterOpEngine.run(ctx, log)
 }
@@ -65,3 +65,77 @@ func TestGetDescriptionFilePath(t *testing.T) {
 	path = opt.getCurrConfigFilePath()
 	assert.Equal(t, targetGCPPath, path)
 }
+
+func TestGetSandboxConfigFilePath(t *testing.T) {
+	opt := VReviveDBOptionsFactory()
+	opt.DBName = "test_eon_db"
+	opt.CommunalStorageLocation = "s3://vertica-fleeting/k8s/revive_eon_5"
+
+	// without a sandbox, the description file of the main cluster is used
+	path := opt.getReviveConfigFilePath()
+	assert.Equal(t, "s3://vertica-fleeting/k8s/revive_eon_5/metadata/test_eon_db/cluster_config.json", path)
+
+	// the description files of a sandbox are in its own metadata folder
+	opt.Sandbox = "sand"
+	path = opt.getReviveConfigFilePath()
+	assert.Equal(t, "s3://vertica-fleeting/k8s/revive_eon_5/metadata/test_eon_db/sandbox/sand/cluster_config.json", path)
+	opt.RestorePoint.Archive = "archive1"
+	path = opt.getRestorePointConfigFilePath("2251e5cc-3e16-4fb1-8cd0-e4b8651f5779")
+	assert.Equal(t, "s3://vertica-fleeting/k8s/revive_eon_5/metadata/test_eon_db/sandbox/sand/archives/archive1/"+
+		"2251e5cc-3e16-4fb1-8cd0-e4b8651f5779/cluster_config.json", path)
+}
+
+func TestValueSetters(t *testing.T) {
+	opt := DatabaseOptions{}
+	_, isSet := opt.GetPassword()
+	assert.False(t, isSet)
+	opt.SetPassword("")
+	password, isSet := opt.GetPassword()
+	assert.True(t, isSet)
+	assert.Empty(t, password)
+
+	// options built without the factory do not panic on the unset fields
+	addNodeOptions := VAddNodeOptions{}
+	assert.False(t, addNodeOptions.isSkipRebalanceShards())
+	addNodeOptions.SetSkipRebalanceShards(true)
+	assert.True(t, addNodeOptions.isSkipRebalanceShards())
+
+	stopDBOptions := VStopDatabaseOptions{}
+	assert.Equal(t, 0, stopDBOptions.getDrainSeconds())
+	stopDBOptions.SetDrainSeconds(30)
+	assert.Equal(t, 30, *stopDBOptions.DrainSeconds)
+	assert.Equal(t, 30, stopDBOptions.getDrainSeconds())
+
+	// the password of add_subcluster is also used to add its nodes
+	addSCOptions := VAddSubclusterOptions{}
+	addSCOptions.SetPassword("secret")
+	password, isSet = addSCOptions.DatabaseOptions.GetPassword()
+	assert.True(t, isSet)
+	assert.Equal(t, "secret", password)
+	password, isSet = addSCOptions.VAddNodeOptions.GetPassword()
+	assert.True(t, isSet)
+	assert.Equal(t, "secret", password)
+}
+
+func TestReresolveHostnames(t *testing.T) {
+	opt := DatabaseOptions{}
+	hosts, err := opt.resolveRawHosts([]string{"192.168.1.101", "localhost"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.101", "127.0.0.1"}, hosts)
+	assert.Equal(t, map[string]string{"127.0.0.1": "localhost"}, opt.hostnames)
+
+	// the engine only sends the requests to the hostnames if they are re-resolved
+	engine := opt.makeClusterOpEngine(nil)
+	assert.Nil(t, engine.hostnames)
+	opt.ReresolveHostnames = true
+	engine = opt.makeClusterOpEngine(nil)
+	assert.Equal(t, opt.hostnames, engine.hostnames)
+
+	cache := makeTransportCache(&opt.TransportPolicy, &opt.ProxyPolicy, nil)
+	cache.hostnames = engine.hostnames
+	assert.Equal(t, "https://localhost:5554", cache.baseURL("127.0.0.1", nmaPort, false))
+	assert.Equal(t, "https://192.168.1.101:5554", cache.baseURL("192.168.1.101", nmaPort, false))
+	// an address rewrite takes precedence
+	opt.ProxyPolicy.AddressRewrites = map[string]string{"127.0.0.1": "node1.example.com"}
+	assert.Equal(t, "https://node1.example.com:5554", cache.baseURL("127.0.0.1", nmaPort, false))
+}
@@ -0,0 +1,169 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vlog
+
+import (
+	"sync"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogControls configures the verbosity and the sampling of the info logs per
+// logger name. The ops of vclusterops log with their op name as the logger
+// name, e.g., "NMAHealthOp" or "HTTPSPollNodeStateOp".
+type LogControls struct {
+	// Verbosity maps a logger name to the highest V-level of the info logs
+	// that are written. A negative value silences the info logs of the
+	// logger, which include the messages of PrintInfo and PrintWarning.
+	// Errors are always written.
+	Verbosity map[string]int
+	// DefaultVerbosity applies to the loggers that are not in Verbosity
+	DefaultVerbosity int
+	// SampleEvery maps a logger name to N, so that only the first and then
+	// every N-th occurrence of the same info message is written. This keeps
+	// the logs of ops that poll the hosts readable.
+	SampleEvery map[string]int
+}
+
+// maxVerbosity returns the highest V-level that any logger may write
+func (c *LogControls) maxVerbosity() int {
+	maxLevel := c.DefaultVerbosity
+	for _, level := range c.Verbosity {
+		if level > maxLevel {
+			maxLevel = level
+		}
+	}
+	return maxLevel
+}
+
+// SetLogControls applies the log controls to the printer and to all of the
+// printers that are later derived from it with WithName.
+func (p *Printer) SetLogControls(controls LogControls) {
+	if p.Log.GetSink() == nil {
+		return
+	}
+	// the logger built by SetupOrDie drops the V-levels above 0, so
+	// lower its level to let the verbose loggers through
+	if p.level != nil {
+		p.level.SetLevel(zapcore.Level(-controls.maxVerbosity()))
+	}
+	newSink := &controlledSink{
+		sink:     p.Log.GetSink(),
+		controls: &controls,
+		counts:   &sampleCounts{counts: make(map[string]int)},
+	}
+	// replace the controls that were set before instead of stacking them
+	if cs, ok := newSink.sink.(*controlledSink); ok {
+		newSink.sink = cs.sink
+		newSink.names = cs.names
+	}
+	p.Log = logr.New(newSink)
+}
+
+// maxSampledMessages caps the distinct messages that sampleCounts tracks, as
+// the messages that embed changing values would otherwise grow it without end
+const maxSampledMessages = 1024
+
+// sampleCounts tracks the occurrences of the sampled messages. It is shared
+// by all of the sinks derived from the same controlled sink.
+type sampleCounts struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// next records an occurrence of the message and returns how many times it
+// occurred before. The counts are reset once too many messages are tracked,
+// after which the next occurrence of each message is written again.
+func (s *sampleCounts) next(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count, found := s.counts[key]
+	if !found && len(s.counts) >= maxSampledMessages {
+		s.counts = make(map[string]int)
+	}
+	s.counts[key]++
+	return count
+}
+
+// controlledSink wraps a logr sink to apply the LogControls of the logger name
+type controlledSink struct {
+	sink     logr.LogSink
+	controls *LogControls
+	counts   *sampleCounts
+	// names of the logger, outermost first
+	names []string
+}
+
+// lookup returns the value configured for the innermost name of the logger
+func (s *controlledSink) lookup(settings map[string]int) (value int, found bool) {
+	for i := len(s.names) - 1; i >= 0; i-- {
+		if value, found = settings[s.names[i]]; found {
+			return value, found
+		}
+	}
+	return 0, false
+}
+
+func (s *controlledSink) Init(info logr.RuntimeInfo) {
+	s.sink.Init(info)
+}
+
+func (s *controlledSink) Enabled(level int) bool {
+	verbosity, found := s.lookup(s.controls.Verbosity)
+	if !found {
+		verbosity = s.controls.DefaultVerbosity
+	}
+	return level <= verbosity && s.sink.Enabled(level)
+}
+
+func (s *controlledSink) Info(level int, msg string, keysAndValues ...any) {
+	if every, found := s.lookup(s.controls.SampleEvery); found && every > 1 {
+		key := s.names[len(s.names)-1] + "/" + msg
+		if s.counts.next(key)%every != 0 {
+			return
+		}
+	}
+	s.sink.Info(level, msg, keysAndValues...)
+}
+
+func (s *controlledSink) Error(err error, msg string, keysAndValues ...any) {
+	s.sink.Error(err, msg, keysAndValues...)
+}
+
+func (s *controlledSink) WithValues(keysAndValues ...any) logr.LogSink {
+	newSink := *s
+	newSink.sink = s.sink.WithValues(keysAndValues...)
+	return &newSink
+}
+
+func (s *controlledSink) WithName(name string) logr.LogSink {
+	newSink := *s
+	newSink.sink = s.sink.WithName(name)
+	newSink.names = append(append([]string{}, s.names...), name)
+	return &newSink
+}
+
+// WithCallDepth keeps the caller information of the wrapped sink accurate
+func (s *controlledSink) WithCallDepth(depth int) logr.LogSink {
+	callDepthSink, ok := s.sink.(logr.CallDepthLogSink)
+	if !ok {
+		return s
+	}
+	newSink := *s
+	newSink.sink = callDepthSink.WithCallDepth(depth)
+	return &newSink
+}
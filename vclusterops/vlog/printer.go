@@ -24,6 +24,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 const (
@@ -33,6 +34,19 @@ const (
 	DebugLog   = "[DEBUG] "
 )
 
+// LogFormat is the encoding of the log entries written by the logger that
+// Setup builds
+type LogFormat string
+
+const (
+	// ConsoleLogFormat writes human-readable lines
+	ConsoleLogFormat LogFormat = "console"
+	// JSONLogFormat writes a JSON object per entry, with the logger name,
+	// e.g., the op name, and the key-value pairs of the entry as fields,
+	// so that the logs can be parsed by log collectors
+	JSONLogFormat LogFormat = "json"
+)
+
 // Printer is a wrapper for the logger API that handles dual logging to the log
 // and stdout. It reimplements all of the APIs from logr but adds two additional
 // members: one is for printing messages to stdout, and the other one is for identifying
@@ -42,6 +56,8 @@ type Printer struct {
 	LogToFileOnly bool
 	// ForCli can indicate if vclusterops is called from vcluster cli or other clients
 	ForCli bool
+	// level of the logger built by SetupOrDie, adjusted by SetLogControls
+	level *zap.AtomicLevel
 }
 
 // WithName will construct a new printer with the logger set with an additional
@@ -51,6 +67,19 @@ func (p *Printer) WithName(logName string) Printer {
 		Log:           p.Log.WithName(logName),
 		LogToFileOnly: p.LogToFileOnly,
 		ForCli:        p.ForCli,
+		level:         p.level,
+	}
+}
+
+// WithValues will construct a new printer whose log entries carry the given
+// key-value pairs, e.g., the ID of a request. The new printer inherits state
+// from the current Printer.
+func (p *Printer) WithValues(keysAndValues ...any) Printer {
+	return Printer{
+		Log:           p.Log.WithValues(keysAndValues...),
+		LogToFileOnly: p.LogToFileOnly,
+		ForCli:        p.ForCli,
+		level:         p.level,
 	}
 }
 
@@ -147,6 +176,7 @@ func logMaskedArgParseHelper(inputArgv []string) (maskedPairs []string) {
 	// with value format k=v,k=v,k=v...
 	targetMaskedArg := map[string]bool{
 		"--config-param": true,
+		"--credentials":  true,
 	}
 	// some params have simple value format v
 	targetMaskedSimpleArg := map[string]bool{
@@ -185,12 +215,35 @@ func logMaskedArgParseHelper(inputArgv []string) (maskedPairs []string) {
 // setupOrDie will setup the logging for vcluster CLI. On exit, p.Log will
 // be set.
 func (p *Printer) SetupOrDie(logFile string) {
+	err := p.Setup(logFile, ConsoleLogFormat)
+	if err != nil {
+		fmt.Printf("Failed to setup the logger: %s", err.Error())
+		os.Exit(1)
+	}
+}
+
+// Setup builds the logger that writes to logFile, or to stderr if logFile is
+// empty, in the given format. On success, p.Log will be set.
+func (p *Printer) Setup(logFile string, format LogFormat) error {
 	// The vcluster library uses logr as the logging API. We use Uber's zap
 	// package to implement the logging API.
-	EncoderConfigWithoutCaller := zap.NewDevelopmentEncoderConfig()
-	EncoderConfigWithoutCaller.EncodeCaller = nil // Set EncodeCaller to nil to exclude caller information
+	var encoderConfig zapcore.EncoderConfig
+	switch format {
+	case ConsoleLogFormat:
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+	case JSONLogFormat:
+		encoderConfig = zap.NewProductionEncoderConfig()
+		encoderConfig.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+		encoderConfig.EncodeDuration = zapcore.StringDurationEncoder
+		// the JSON encoder requires a caller encoder unless the key is omitted
+		encoderConfig.CallerKey = zapcore.OmitKey
+	default:
+		return fmt.Errorf("invalid log format %q, must be %q or %q", format, ConsoleLogFormat, JSONLogFormat)
+	}
+	encoderConfig.EncodeCaller = nil // Set EncodeCaller to nil to exclude caller information
+	level := zap.NewAtomicLevelAt(zap.InfoLevel)
 	cfg := zap.Config{
-		Level:       zap.NewAtomicLevelAt(zap.InfoLevel),
+		Level:       level,
 		Development: false,
 		// Sampling is enabled at 100:100, meaning that after the first 100 log
 		// entries with the same level and message in the same second, it will
@@ -199,8 +252,8 @@ func (p *Printer) SetupOrDie(logFile string) {
 			Initial:    100,
 			Thereafter: 100,
 		},
-		Encoding:         "console",
-		EncoderConfig:    EncoderConfigWithoutCaller,
+		Encoding:         string(format),
+		EncoderConfig:    encoderConfig,
 		OutputPaths:      []string{"stderr"},
 		ErrorOutputPaths: []string{"stderr"},
 	}
@@ -211,11 +264,12 @@ func (p *Printer) SetupOrDie(logFile string) {
 	}
 	zapLg, err := cfg.Build()
 	if err != nil {
-		fmt.Printf("Failed to setup the logger: %s", err.Error())
-		os.Exit(1)
+		return err
 	}
 	p.Log = zapr.NewLogger(zapLg)
-	p.Log.Info("Successfully started logger", "logFile", logFile)
+	p.level = &level
+	p.Log.Info("Successfully started logger", "logFile", logFile, "logFormat", format)
+	return nil
 }
 
 func isVerboseOutputEnabled() bool {
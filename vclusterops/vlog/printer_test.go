@@ -16,11 +16,19 @@
 package vlog
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/tonglil/buflogr"
 )
 
 // CaptureStdout returns the stdout of the function f as a string
@@ -51,4 +59,77 @@ func TestPasswordRedaction(t *testing.T) {
 	unmaskedArgs := logMaskedArgParseHelper(argv)
 	assert.Len(t, unmaskedArgs, 2)
 	assert.Equal(t, pw, unmaskedArgs[1])
+
+	// test credential redaction
+	credArgv := []string{"--credentials", "AWSAuth=key:secret"}
+	maskedArgs = logMaskedArgParseHelper(credArgv)
+	assert.Equal(t, []string{"--credentials", "AWSAuth=******"}, maskedArgs)
+}
+
+func TestLogControls(t *testing.T) {
+	var logBuf bytes.Buffer
+	p := Printer{Log: buflogr.NewWithBuffer(&logBuf)}
+	p.SetLogControls(LogControls{
+		Verbosity:   map[string]int{"QuietOp": -1, "VerboseOp": 1},
+		SampleEvery: map[string]int{"PollOp": 3},
+	})
+
+	quiet := p.WithName("QuietOp")
+	quiet.Info("quiet info")
+	quiet.Error(errors.New("boom"), "quiet error")
+	verbose := p.WithName("VerboseOp")
+	verbose.V(1).Info("verbose detail")
+	p.V(1).Info("default detail")
+	poll := p.WithName("PollOp")
+	for i := 0; i < 5; i++ {
+		poll.Info("polling hosts")
+	}
+
+	logs := logBuf.String()
+	assert.NotContains(t, logs, "quiet info")
+	assert.Contains(t, logs, "quiet error")
+	assert.Contains(t, logs, "verbose detail")
+	assert.NotContains(t, logs, "default detail")
+	// the 1st and the 4th occurrences are written
+	assert.Equal(t, 2, strings.Count(logs, "polling hosts"))
+
+	// the counts are reset instead of growing without end
+	counts := &sampleCounts{counts: make(map[string]int)}
+	for i := 0; i < maxSampledMessages; i++ {
+		counts.next(fmt.Sprintf("PollOp/message %d", i))
+	}
+	assert.Equal(t, 1, counts.next("PollOp/message 0"))
+	assert.Equal(t, 0, counts.next("PollOp/another message"))
+	assert.Len(t, counts.counts, 1)
+}
+
+func TestJSONLogFormat(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "vcluster.log")
+	p := Printer{}
+	err := p.Setup(logFile, JSONLogFormat)
+	assert.NoError(t, err)
+
+	opLogger := p.WithName("NMAHealthOp")
+	opLogger = opLogger.WithValues("runID", "abc123")
+	opLogger.Info("request completed", "host", "192.168.1.101", "duration", 2*time.Second)
+	opLogger.PrintWarning("host %s is slow", "192.168.1.101")
+
+	content, err := os.ReadFile(logFile)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	// the first entry is written by Setup
+	assert.Len(t, lines, 3)
+	entry := make(map[string]any)
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, "request completed", entry["msg"])
+	assert.Equal(t, "NMAHealthOp", entry["logger"])
+	assert.Equal(t, "abc123", entry["runID"])
+	assert.Equal(t, "192.168.1.101", entry["host"])
+	assert.Equal(t, "2s", entry["duration"])
+	entry = make(map[string]any)
+	assert.NoError(t, json.Unmarshal([]byte(lines[2]), &entry))
+	assert.Equal(t, "host 192.168.1.101 is slow", entry["msg"])
+
+	err = p.Setup(logFile, LogFormat("xml"))
+	assert.ErrorContains(t, err, `invalid log format "xml"`)
 }